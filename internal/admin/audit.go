@@ -0,0 +1,29 @@
+package admin
+
+import (
+	"github.com/centrifugal/centrifugo/v6/internal/apiproto"
+	"github.com/centrifugal/centrifugo/v6/internal/audit"
+)
+
+const auditSource = "admin"
+
+// SetAuditor sets audit Logger to record admin logins, draining and denied admin API
+// commands. Allowed admin API commands are recorded by API executor.
+func (s *Handler) SetAuditor(l *audit.Logger) {
+	s.auditor = l
+}
+
+func (s *Handler) audit(method string, identity string, respErr *apiproto.Error) {
+	if s.auditor == nil {
+		return
+	}
+	r := audit.Record{
+		Source:   auditSource,
+		Identity: identity,
+		Method:   method,
+	}
+	if respErr != nil {
+		r.Error = &audit.Error{Code: respErr.Code, Message: respErr.Message}
+	}
+	s.auditor.Log(r)
+}
@@ -1,12 +1,21 @@
 package admin
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/centrifugal/centrifugo/v6/internal/api"
+	"github.com/centrifugal/centrifugo/v6/internal/apiproto"
+	"github.com/centrifugal/centrifugo/v6/internal/audit"
+	"github.com/centrifugal/centrifugo/v6/internal/clientcontext"
 	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/drain"
 	"github.com/centrifugal/centrifugo/v6/internal/middleware"
 	"github.com/centrifugal/centrifugo/v6/internal/reverseproxy"
 	"github.com/centrifugal/centrifugo/v6/internal/tools"
@@ -21,9 +30,12 @@ type Config = configtypes.Admin
 
 // Handler handles admin web UI endpoints.
 type Handler struct {
-	mux    *http.ServeMux
-	node   *centrifuge.Node
-	config configtypes.Admin
+	mux     *http.ServeMux
+	node    *centrifuge.Node
+	config  configtypes.Admin
+	drainer *drain.Drainer
+	oidc    *oidcProvider
+	auditor *audit.Logger
 }
 
 // NewHandler creates new Handler.
@@ -36,7 +48,13 @@ func NewHandler(n *centrifuge.Node, apiExecutor *api.Executor, c Config) *Handle
 	prefix := strings.TrimRight(h.config.HandlerPrefix, "/")
 	mux.Handle(prefix+"/admin/init", http.HandlerFunc(h.initHandler))
 	mux.Handle(prefix+"/admin/auth", middleware.Post(http.HandlerFunc(h.authHandler)))
-	mux.Handle(prefix+"/admin/api", middleware.Post(h.adminSecureTokenAuth(api.NewHandler(n, apiExecutor, api.Config{}).OldRoute())))
+	mux.Handle(prefix+"/admin/api", middleware.Post(h.adminSecureTokenAuth(h.apiRoleAuth(api.NewHandler(n, apiExecutor, api.Config{}).OldRoute()))))
+	mux.Handle(prefix+"/admin/drain", middleware.Post(h.adminSecureTokenAuth(h.requireRole(RoleAdmin, http.HandlerFunc(h.drainHandler)))))
+	if c.OIDC.Enabled {
+		h.oidc = newOIDCProvider(c.OIDC, c.Secret)
+		mux.Handle(prefix+"/admin/oidc/login", middleware.Method(http.MethodGet, http.HandlerFunc(h.oidc.loginHandler)))
+		mux.Handle(prefix+"/admin/oidc/callback", middleware.Method(http.MethodGet, http.HandlerFunc(h.oidcCallbackHandler)))
+	}
 
 	webPrefix := prefix + "/"
 	if c.WebProxyAddress != "" {
@@ -55,6 +73,11 @@ func NewHandler(n *centrifuge.Node, apiExecutor *api.Executor, c Config) *Handle
 	return h
 }
 
+// SetDrainer sets Drainer to start node draining from admin endpoint.
+func (s *Handler) SetDrainer(d *drain.Drainer) {
+	s.drainer = d
+}
+
 func (s *Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 	s.mux.ServeHTTP(rw, r)
 }
@@ -65,7 +88,7 @@ func (s *Handler) adminSecureTokenAuth(h http.Handler) http.Handler {
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if insecure {
-			h.ServeHTTP(w, r)
+			h.ServeHTTP(w, r.WithContext(setSessionToContext(r.Context(), insecureSession)))
 			return
 		}
 
@@ -94,27 +117,103 @@ func (s *Handler) adminSecureTokenAuth(h http.Handler) http.Handler {
 			token = r.URL.Query().Get("token")
 		}
 
-		if token == "" || !checkSecureAdminToken(secret, token) {
+		if token == "" {
 			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
+		session, ok := checkSecureAdminToken(secret, token)
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r.WithContext(setSessionToContext(r.Context(), session)))
+	})
+}
+
+// requireRole allows request only if admin session role includes required role.
+// Must be used after adminSecureTokenAuth.
+func (s *Handler) requireRole(role Role, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session := getSessionFromContext(r.Context())
+		if !session.Role.Includes(role) {
+			log.Warn().Str("identity", session.Identity).Str("role", string(session.Role)).
+				Str("path", r.URL.Path).Msg("admin action denied")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
 		h.ServeHTTP(w, r)
 	})
 }
 
+// apiRoleAuth checks that admin session role allows all commands in API request
+// and logs admin actions with admin identity. Must be used after adminSecureTokenAuth.
+func (s *Handler) apiRoleAuth(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session := getSessionFromContext(r.Context())
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			log.Error().Err(err).Msg("error reading admin API request body")
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		var methods []api.CommandMethodType
+		decoder := api.GetCommandDecoder(data)
+		defer api.PutCommandDecoder(decoder)
+		for {
+			command, decodeErr := decoder.Decode()
+			if decodeErr != nil && decodeErr != io.EOF {
+				http.Error(w, "Bad Request", http.StatusBadRequest)
+				return
+			}
+			if command != nil {
+				methods = append(methods, command.Method)
+			}
+			if decodeErr == io.EOF {
+				break
+			}
+		}
+		for _, method := range methods {
+			if !commandAllowed(session.Role, method) {
+				log.Warn().Str("identity", session.Identity).Str("role", string(session.Role)).
+					Str("method", methodName(method)).Msg("admin API command denied")
+				s.audit(methodName(method), session.Identity, apiproto.ErrorPermissionDenied)
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+		}
+		for _, method := range methods {
+			log.Info().Str("identity", session.Identity).Str("role", string(session.Role)).
+				Str("method", methodName(method)).Msg("admin API command")
+		}
+		r.Body = io.NopCloser(bytes.NewReader(data))
+		ctx := clientcontext.SetAPICallerToContext(r.Context(), clientcontext.APICaller{
+			Source:   auditSource,
+			Identity: session.Identity,
+		})
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func methodName(method api.CommandMethodType) string {
+	return strings.ToLower(api.Command_MethodType_name[int32(method)])
+}
+
 // initHandler allows to get admin web interface settings.
 func (s *Handler) initHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	resp := map[string]any{
 		"insecure": s.config.Insecure,
 		"edition":  "oss",
+		"oidc":     s.config.OIDC.Enabled,
 	}
 
 	secret := s.config.Secret
 	authenticated := false
+	var session adminSession
 
 	if s.config.Insecure {
 		authenticated = true
+		session = insecureSession
 	} else if secret != "" {
 		var token string
 		authorization := r.Header.Get("Authorization")
@@ -127,11 +226,15 @@ func (s *Handler) initHandler(w http.ResponseWriter, r *http.Request) {
 		if token == "" {
 			token = r.URL.Query().Get("token")
 		}
-		if token != "" && checkSecureAdminToken(secret, token) {
-			authenticated = true
+		if token != "" {
+			session, authenticated = checkSecureAdminToken(secret, token)
 		}
 	}
 	resp["authenticated"] = authenticated
+	if authenticated {
+		resp["identity"] = session.Identity
+		resp["role"] = session.Role
+	}
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
@@ -151,44 +254,138 @@ func (s *Handler) authHandler(w http.ResponseWriter, r *http.Request) {
 
 	if tools.SecureCompareString(formPassword, password) {
 		w.Header().Set("Content-Type", "application/json")
-		token, err := generateSecureAdminToken(secret)
+		token, err := generateSecureAdminToken(secret, s.config.SessionTTL.ToDuration())
 		if err != nil {
 			log.Error().Msgf("error generating admin token: %v", err)
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 			return
 		}
+		log.Info().Str("identity", passwordSession.Identity).Str("role", string(passwordSession.Role)).Msg("admin logged in")
+		s.audit("admin_login", passwordSession.Identity, nil)
 		resp := map[string]string{
 			"token": token,
 		}
 		_ = json.NewEncoder(w).Encode(resp)
 		return
 	}
+	log.Warn().Msg("admin login with invalid password")
+	s.audit("admin_login", passwordSession.Identity, apiproto.ErrorPermissionDenied)
 	http.Error(w, "Bad Request", http.StatusBadRequest)
 }
 
+// oidcCallbackHandler completes OIDC login and redirects to admin web UI with admin
+// session token in URL fragment.
+func (s *Handler) oidcCallbackHandler(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{Name: oidcCookieName, Path: s.oidc.cookiePath(), MaxAge: -1})
+	identity, err := s.oidc.callback(r)
+	if err != nil {
+		if errors.Is(err, errNoRole) {
+			log.Warn().Str("identity", identity.Identity).Msg("admin OIDC login denied: no role")
+			s.audit("admin_login", identity.Identity, apiproto.ErrorPermissionDenied)
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		log.Warn().Err(err).Msg("admin OIDC login failed")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	session := adminSession{
+		Identity: identity.Identity,
+		Role:     identity.Role,
+		ExpireAt: time.Now().Add(s.config.SessionTTL.ToDuration()).Unix(),
+	}
+	token, err := generateAdminSessionToken(s.config.Secret, session)
+	if err != nil {
+		log.Error().Err(err).Msg("error generating admin token")
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	log.Info().Str("identity", session.Identity).Str("role", string(session.Role)).Msg("admin logged in over OIDC")
+	s.audit("admin_login", session.Identity, nil)
+	http.Redirect(w, r, strings.TrimRight(s.config.HandlerPrefix, "/")+"/#token="+token, http.StatusFound)
+}
+
+// drainHandler starts node draining. Draining can't be stopped, node must be
+// restarted to accept connections again.
+func (s *Handler) drainHandler(w http.ResponseWriter, r *http.Request) {
+	if s.drainer == nil {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+	started := s.drainer.Start(context.Background())
+	if started {
+		identity := getSessionFromContext(r.Context()).Identity
+		log.Info().Str("identity", identity).Msg("node draining requested over admin endpoint")
+		s.audit("admin_drain", identity, nil)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]bool{
+		"draining": true,
+		"started":  started,
+	})
+}
+
 const (
-	// AdminTokenKey is a key for admin authorization token.
+	// secureAdminTokenKey is a name of admin authorization token.
 	secureAdminTokenKey = "token"
-	// AdminTokenValue is a value for secure admin authorization token.
-	secureAdminTokenValue = "authorized"
 )
 
-// generateSecureAdminToken generates admin authentication token.
-func generateSecureAdminToken(secret string) (string, error) {
+// adminSession is encoded into admin authorization token.
+type adminSession struct {
+	Identity string
+	Role     Role
+	// ExpireAt is a Unix time of session expiration, zero means session never expires.
+	ExpireAt int64
+}
+
+var (
+	// passwordSession is a session of admin logged in with admin password.
+	passwordSession = adminSession{Identity: "admin", Role: RoleAdmin}
+	// insecureSession is used for all requests when admin insecure mode is on.
+	insecureSession = adminSession{Identity: "insecure", Role: RoleAdmin}
+)
+
+type sessionContextKey struct{}
+
+func setSessionToContext(ctx context.Context, session adminSession) context.Context {
+	return context.WithValue(ctx, sessionContextKey{}, session)
+}
+
+func getSessionFromContext(ctx context.Context) adminSession {
+	session, _ := ctx.Value(sessionContextKey{}).(adminSession)
+	return session
+}
+
+// generateSecureAdminToken generates admin authentication token for password login.
+// Zero ttl means session never expires.
+func generateSecureAdminToken(secret string, ttl time.Duration) (string, error) {
+	session := passwordSession
+	if ttl > 0 {
+		session.ExpireAt = time.Now().Add(ttl).Unix()
+	}
+	return generateAdminSessionToken(secret, session)
+}
+
+// generateAdminSessionToken generates admin authentication token carrying session.
+func generateAdminSessionToken(secret string, session adminSession) (string, error) {
 	s := securecookie.New([]byte(secret), nil)
-	return s.Encode(secureAdminTokenKey, secureAdminTokenValue)
+	return s.Encode(secureAdminTokenKey, session)
 }
 
-// checkSecureAdminToken checks admin connection token which Centrifugo returns after admin login.
-func checkSecureAdminToken(secret string, token string) bool {
+// checkSecureAdminToken checks admin connection token which Centrifugo returns after admin login
+// and returns admin session encoded into it.
+func checkSecureAdminToken(secret string, token string) (adminSession, bool) {
 	s := securecookie.New([]byte(secret), nil)
-	var val string
-	err := s.Decode(secureAdminTokenKey, token, &val)
+	var session adminSession
+	err := s.Decode(secureAdminTokenKey, token, &session)
 	if err != nil {
-		return false
+		return adminSession{}, false
+	}
+	if session.Role.level() == 0 {
+		return adminSession{}, false
 	}
-	if val != secureAdminTokenValue {
-		return false
+	if session.ExpireAt > 0 && time.Now().Unix() >= session.ExpireAt {
+		return adminSession{}, false
 	}
-	return true
+	return session, true
 }
@@ -7,6 +7,9 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
 
 	"github.com/centrifugal/centrifuge"
 	"github.com/stretchr/testify/require"
@@ -75,7 +78,7 @@ func TestAuthHandler_NoPasswordOrSecret(t *testing.T) {
 
 // TestAuthHandler_ValidPassword tests authHandler token generation with valid password.
 func TestAuthHandler_ValidPassword(t *testing.T) {
-	config := Config{Password: "test-password", Secret: "test-secret"}
+	config := Config{Password: "test-password", Secret: "test-secret", SessionTTL: configtypes.Duration(time.Hour)}
 	handler := &Handler{config: config}
 	form := url.Values{}
 	form.Add("password", "test-password")
@@ -90,6 +93,11 @@ func TestAuthHandler_ValidPassword(t *testing.T) {
 	err := json.NewDecoder(resp.Body).Decode(&response)
 	require.NoError(t, err)
 	require.NotEmpty(t, response["token"])
+
+	session, ok := checkSecureAdminToken("test-secret", response["token"])
+	require.True(t, ok)
+	require.Equal(t, RoleAdmin, session.Role)
+	require.InDelta(t, time.Now().Add(time.Hour).Unix(), session.ExpireAt, 5)
 }
 
 // TestAuthHandler_InvalidPassword tests authHandler rejection with invalid password.
@@ -153,7 +161,7 @@ func TestAdminSecureTokenAuth_ValidToken(t *testing.T) {
 	})
 
 	// Generate a valid token
-	token, err := generateSecureAdminToken("test-secret")
+	token, err := generateSecureAdminToken("test-secret", time.Hour)
 	require.NoError(t, err)
 
 	authHandler := handler.adminSecureTokenAuth(finalHandler)
@@ -182,3 +190,73 @@ func TestAdminSecureTokenAuth_InvalidToken(t *testing.T) {
 	authHandler.ServeHTTP(resp, req)
 	require.Equal(t, http.StatusUnauthorized, resp.Code)
 }
+
+// TestDrainHandler_NoDrainer tests drain endpoint when draining is not configured.
+func TestDrainHandler_NoDrainer(t *testing.T) {
+	handler := &Handler{config: Config{Insecure: true}}
+	req := httptest.NewRequest("POST", "/admin/drain", nil)
+	resp := httptest.NewRecorder()
+
+	handler.drainHandler(resp, req)
+	require.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+// TestAPIRoleAuth tests admin API commands are restricted by session role.
+func TestAPIRoleAuth(t *testing.T) {
+	handler := &Handler{config: Config{Secret: "test-secret"}}
+	finalHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	apiHandler := handler.adminSecureTokenAuth(handler.apiRoleAuth(finalHandler))
+
+	viewerToken, err := generateAdminSessionToken("test-secret", adminSession{Identity: "viewer@example.com", Role: RoleViewer})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/admin/api?token="+viewerToken, strings.NewReader(`{"method": "info", "params": {}}`))
+	resp := httptest.NewRecorder()
+	apiHandler.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	body := `{"method": "info", "params": {}}` + "\n" + `{"method": "publish", "params": {"channel": "test", "data": {}}}`
+	req = httptest.NewRequest("POST", "/admin/api?token="+viewerToken, strings.NewReader(body))
+	resp = httptest.NewRecorder()
+	apiHandler.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusForbidden, resp.Code)
+
+	operatorToken, err := generateAdminSessionToken("test-secret", adminSession{Identity: "operator@example.com", Role: RoleOperator})
+	require.NoError(t, err)
+	req = httptest.NewRequest("POST", "/admin/api?token="+operatorToken, strings.NewReader(body))
+	resp = httptest.NewRecorder()
+	apiHandler.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+}
+
+// TestAdminSecureTokenAuth_ExpiredSession tests adminSecureTokenAuth rejection of expired session.
+func TestAdminSecureTokenAuth_ExpiredSession(t *testing.T) {
+	handler := &Handler{config: Config{Secret: "test-secret"}}
+	finalHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Fail(t, "final handler should not be invoked")
+	})
+	token, err := generateAdminSessionToken("test-secret", adminSession{
+		Identity: "admin@example.com", Role: RoleAdmin, ExpireAt: time.Now().Add(-time.Minute).Unix(),
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("GET", "/admin/api?token="+token, nil)
+	resp := httptest.NewRecorder()
+	handler.adminSecureTokenAuth(finalHandler).ServeHTTP(resp, req)
+	require.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+// TestDrainHandler_RequiresAdminRole tests drain endpoint is not available for operators.
+func TestDrainHandler_RequiresAdminRole(t *testing.T) {
+	handler := &Handler{config: Config{Secret: "test-secret"}}
+	drainHandler := handler.adminSecureTokenAuth(handler.requireRole(RoleAdmin, http.HandlerFunc(handler.drainHandler)))
+	token, err := generateAdminSessionToken("test-secret", adminSession{Identity: "operator@example.com", Role: RoleOperator})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/admin/drain?token="+token, nil)
+	resp := httptest.NewRecorder()
+	drainHandler.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusForbidden, resp.Code)
+}
@@ -0,0 +1,312 @@
+package admin
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/jwks"
+	"github.com/centrifugal/centrifugo/v6/internal/jwtverify"
+
+	"github.com/cristalhq/jwt/v5"
+	"github.com/gorilla/securecookie"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	oidcCookieName   = "centrifugo_admin_oidc"
+	oidcCookieMaxAge = 600
+	oidcHTTPTimeout  = 10 * time.Second
+)
+
+var defaultOIDCScopes = []string{"openid", "profile", "email"}
+
+// oidcMetadata is a part of OpenID provider metadata used for login.
+type oidcMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// oidcLoginState is kept in a signed and encrypted cookie between login redirect and callback.
+type oidcLoginState struct {
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+// oidcIdentity is an admin identity extracted from verified ID token.
+type oidcIdentity struct {
+	Identity string
+	Role     Role
+}
+
+// oidcProvider implements OpenID Connect authorization code flow with PKCE.
+type oidcProvider struct {
+	config configtypes.AdminOIDC
+	client *http.Client
+	cookie *securecookie.SecureCookie
+
+	mu       sync.Mutex
+	metadata *oidcMetadata
+	keys     *jwks.Manager
+}
+
+func newOIDCProvider(c configtypes.AdminOIDC, secret string) *oidcProvider {
+	hashKey := sha256.Sum256([]byte("oidc-hash:" + secret))
+	blockKey := sha256.Sum256([]byte("oidc-block:" + secret))
+	cookie := securecookie.New(hashKey[:], blockKey[:])
+	cookie.MaxAge(oidcCookieMaxAge)
+	return &oidcProvider{
+		config: c,
+		client: &http.Client{Timeout: oidcHTTPTimeout},
+		cookie: cookie,
+	}
+}
+
+// discover loads provider metadata once. Failed attempts are not cached so that login
+// starts working as soon as provider becomes available.
+func (p *oidcProvider) discover(ctx context.Context) (*oidcMetadata, *jwks.Manager, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.metadata != nil {
+		return p.metadata, p.keys, nil
+	}
+	discoveryURL := strings.TrimRight(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error requesting provider metadata: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected provider metadata response status: %d", resp.StatusCode)
+	}
+	var metadata oidcMetadata
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1024*1024)).Decode(&metadata); err != nil {
+		return nil, nil, fmt.Errorf("error decoding provider metadata: %w", err)
+	}
+	if metadata.Issuer != p.config.Issuer {
+		return nil, nil, fmt.Errorf("provider metadata issuer %q does not match configured issuer", metadata.Issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, nil, errors.New("provider metadata misses required endpoints")
+	}
+	keys, err := jwks.NewManager(metadata.JWKSURI, jwks.WithName("admin_oidc"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating JWKS manager: %w", err)
+	}
+	p.metadata = &metadata
+	p.keys = keys
+	return p.metadata, p.keys, nil
+}
+
+func (p *oidcProvider) scopes() string {
+	scopes := p.config.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultOIDCScopes
+	}
+	if !slices.Contains(scopes, "openid") {
+		scopes = append([]string{"openid"}, scopes...)
+	}
+	return strings.Join(scopes, " ")
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (p *oidcProvider) cookiePath() string {
+	u, err := url.Parse(p.config.RedirectURL)
+	if err != nil || u.Path == "" {
+		return "/"
+	}
+	return u.Path[:strings.LastIndex(u.Path, "/")+1]
+}
+
+// loginHandler redirects admin to OpenID provider authorization endpoint.
+func (p *oidcProvider) loginHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, _, err := p.discover(r.Context())
+	if err != nil {
+		log.Error().Err(err).Str("issuer", p.config.Issuer).Msg("error discovering OpenID provider")
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	var state oidcLoginState
+	for _, v := range []*string{&state.State, &state.Nonce, &state.CodeVerifier} {
+		if *v, err = randomString(); err != nil {
+			log.Error().Err(err).Msg("error generating OIDC login state")
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	}
+	encoded, err := p.cookie.Encode(oidcCookieName, state)
+	if err != nil {
+		log.Error().Err(err).Msg("error encoding OIDC login state")
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcCookieName,
+		Value:    encoded,
+		Path:     p.cookiePath(),
+		MaxAge:   oidcCookieMaxAge,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(p.config.RedirectURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.config.ClientID)
+	params.Set("redirect_uri", p.config.RedirectURL)
+	params.Set("scope", p.scopes())
+	params.Set("state", state.State)
+	params.Set("nonce", state.Nonce)
+	params.Set("code_challenge", codeChallenge(state.CodeVerifier))
+	params.Set("code_challenge_method", "S256")
+	sep := "?"
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	http.Redirect(w, r, metadata.AuthorizationEndpoint+sep+params.Encode(), http.StatusFound)
+}
+
+// callback completes login: it checks state, exchanges authorization code for ID token
+// and extracts admin identity and role from it.
+func (p *oidcProvider) callback(r *http.Request) (oidcIdentity, error) {
+	cookie, err := r.Cookie(oidcCookieName)
+	if err != nil {
+		return oidcIdentity{}, errors.New("login state cookie not found")
+	}
+	var state oidcLoginState
+	if err := p.cookie.Decode(oidcCookieName, cookie.Value, &state); err != nil {
+		return oidcIdentity{}, fmt.Errorf("invalid login state cookie: %w", err)
+	}
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		return oidcIdentity{}, fmt.Errorf("provider returned error: %s", providerErr)
+	}
+	if query.Get("state") == "" || query.Get("state") != state.State {
+		return oidcIdentity{}, errors.New("state mismatch")
+	}
+	code := query.Get("code")
+	if code == "" {
+		return oidcIdentity{}, errors.New("no authorization code")
+	}
+	metadata, keys, err := p.discover(r.Context())
+	if err != nil {
+		return oidcIdentity{}, err
+	}
+	rawIDToken, err := p.exchangeCode(r.Context(), metadata, code, state.CodeVerifier)
+	if err != nil {
+		return oidcIdentity{}, err
+	}
+	return p.verifyIDToken(r.Context(), metadata, keys, rawIDToken, state.Nonce)
+}
+
+func (p *oidcProvider) exchangeCode(ctx context.Context, metadata *oidcMetadata, code string, codeVerifier string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("code_verifier", codeVerifier)
+	if p.config.ClientSecret != "" {
+		form.Set("client_secret", p.config.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error requesting token endpoint: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected token endpoint response status: %d", resp.StatusCode)
+	}
+	var tokenResp struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1024*1024)).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("error decoding token endpoint response: %w", err)
+	}
+	if tokenResp.IDToken == "" {
+		return "", errors.New("no id_token in token endpoint response")
+	}
+	return tokenResp.IDToken, nil
+}
+
+func (p *oidcProvider) verifyIDToken(ctx context.Context, metadata *oidcMetadata, keys *jwks.Manager, rawIDToken string, nonce string) (oidcIdentity, error) {
+	token, err := jwt.ParseNoVerify([]byte(rawIDToken))
+	if err != nil {
+		return oidcIdentity{}, fmt.Errorf("malformed ID token: %w", err)
+	}
+	key, err := keys.FetchKey(ctx, token.Header().KeyID, nil)
+	if err != nil {
+		return oidcIdentity{}, fmt.Errorf("error fetching ID token key: %w", err)
+	}
+	if err := jwtverify.VerifyByJWK(token, key); err != nil {
+		return oidcIdentity{}, fmt.Errorf("invalid ID token signature: %w", err)
+	}
+	var registered jwt.RegisteredClaims
+	if err := json.Unmarshal(token.Claims(), &registered); err != nil {
+		return oidcIdentity{}, fmt.Errorf("malformed ID token claims: %w", err)
+	}
+	now := time.Now()
+	if registered.ExpiresAt == nil || !registered.IsValidExpiresAt(now) || !registered.IsValidNotBefore(now) {
+		return oidcIdentity{}, errors.New("ID token expired or not valid yet")
+	}
+	if !registered.IsIssuer(metadata.Issuer) {
+		return oidcIdentity{}, errors.New("ID token issuer mismatch")
+	}
+	if !registered.IsForAudience(p.config.ClientID) {
+		return oidcIdentity{}, errors.New("ID token audience mismatch")
+	}
+	var claims map[string]any
+	if err := json.Unmarshal(token.Claims(), &claims); err != nil {
+		return oidcIdentity{}, fmt.Errorf("malformed ID token claims: %w", err)
+	}
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return oidcIdentity{}, errors.New("ID token nonce mismatch")
+	}
+	identity, _ := claims[p.config.IdentityClaim].(string)
+	if identity == "" {
+		identity = registered.Subject
+	}
+	role, ok := roleFromClaims(p.config, claims)
+	if !ok {
+		return oidcIdentity{Identity: identity}, errNoRole
+	}
+	return oidcIdentity{Identity: identity, Role: role}, nil
+}
+
+var errNoRole = errors.New("no admin role granted by ID token claims")
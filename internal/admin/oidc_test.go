@@ -0,0 +1,220 @@
+package admin
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/cristalhq/jwt/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	_ = metrics.Init(metrics.Config{
+		Registerer: prometheus.NewRegistry(),
+	})
+	os.Exit(m.Run())
+}
+
+type testOIDCProvider struct {
+	server     *httptest.Server
+	privateKey *rsa.PrivateKey
+	// claims returns ID token claims for nonce passed in authorization request.
+	claims func(nonce string) map[string]any
+	// challenge is a PKCE code challenge from the last authorization request.
+	challenge string
+	nonce     string
+}
+
+func newTestOIDCProvider(t *testing.T) *testOIDCProvider {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	p := &testOIDCProvider{privateKey: privateKey}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.server.URL,
+			"authorization_endpoint": p.server.URL + "/authorize",
+			"token_endpoint":         p.server.URL + "/token",
+			"jwks_uri":               p.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{
+				{
+					"alg": "RS256",
+					"kty": "RSA",
+					"use": "sig",
+					"kid": "test",
+					"n":   base64.RawURLEncoding.EncodeToString(privateKey.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(privateKey.E)).Bytes()),
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "test-code" || codeChallenge(r.FormValue("code_verifier")) != p.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		signer, err := jwt.NewSignerRS(jwt.RS256, privateKey)
+		require.NoError(t, err)
+		token, err := jwt.NewBuilder(signer, jwt.WithKeyID("test")).Build(p.claims(p.nonce))
+		require.NoError(t, err)
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": token.String()})
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	p.claims = func(nonce string) map[string]any {
+		return map[string]any{
+			"iss":   p.server.URL,
+			"aud":   "centrifugo",
+			"sub":   "user-1",
+			"email": "alice@example.com",
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"nonce": nonce,
+			"realm_access": map[string]any{
+				"roles": []string{"support"},
+			},
+		}
+	}
+	return p
+}
+
+func newOIDCTestHandler(p *testOIDCProvider) *Handler {
+	return NewHandler(&centrifuge.Node{}, nil, Config{
+		Secret:     "test-secret",
+		SessionTTL: configtypes.Duration(time.Hour),
+		OIDC: configtypes.AdminOIDC{
+			Enabled:       true,
+			Issuer:        p.server.URL,
+			ClientID:      "centrifugo",
+			RedirectURL:   "https://centrifugo.example.com/admin/oidc/callback",
+			IdentityClaim: "email",
+			RolesClaim:    "realm_access.roles",
+			ViewerRoles:   []string{"staff"},
+			OperatorRoles: []string{"support"},
+			AdminRoles:    []string{"ops"},
+		},
+	})
+}
+
+// oidcLogin runs login redirect and returns callback request as provider would
+// redirect browser to it.
+func oidcLogin(t *testing.T, handler *Handler, p *testOIDCProvider) *http.Request {
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/admin/oidc/login", nil))
+	require.Equal(t, http.StatusFound, resp.Code)
+	location, err := url.Parse(resp.Header().Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, p.server.URL+"/authorize", location.Scheme+"://"+location.Host+location.Path)
+	query := location.Query()
+	require.Equal(t, "code", query.Get("response_type"))
+	require.Equal(t, "centrifugo", query.Get("client_id"))
+	require.Equal(t, "S256", query.Get("code_challenge_method"))
+	require.Equal(t, "openid profile email", query.Get("scope"))
+	p.challenge = query.Get("code_challenge")
+	p.nonce = query.Get("nonce")
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/oidc/callback?code=test-code&state="+url.QueryEscape(query.Get("state")), nil)
+	for _, c := range resp.Result().Cookies() {
+		req.AddCookie(c)
+	}
+	return req
+}
+
+func TestOIDCLogin(t *testing.T) {
+	p := newTestOIDCProvider(t)
+	handler := newOIDCTestHandler(p)
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, oidcLogin(t, handler, p))
+	require.Equal(t, http.StatusFound, resp.Code)
+	location := resp.Header().Get("Location")
+	require.True(t, strings.HasPrefix(location, "/#token="))
+
+	session, ok := checkSecureAdminToken("test-secret", strings.TrimPrefix(location, "/#token="))
+	require.True(t, ok)
+	require.Equal(t, "alice@example.com", session.Identity)
+	require.Equal(t, RoleOperator, session.Role)
+	require.Greater(t, session.ExpireAt, time.Now().Unix())
+}
+
+func TestOIDCLogin_StateMismatch(t *testing.T) {
+	p := newTestOIDCProvider(t)
+	handler := newOIDCTestHandler(p)
+
+	req := oidcLogin(t, handler, p)
+	query := req.URL.Query()
+	query.Set("state", "forged")
+	req.URL.RawQuery = query.Encode()
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+func TestOIDCLogin_NonceMismatch(t *testing.T) {
+	p := newTestOIDCProvider(t)
+	handler := newOIDCTestHandler(p)
+	claims := p.claims
+	p.claims = func(string) map[string]any { return claims("forged") }
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, oidcLogin(t, handler, p))
+	require.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+func TestOIDCLogin_WrongAudience(t *testing.T) {
+	p := newTestOIDCProvider(t)
+	handler := newOIDCTestHandler(p)
+	claims := p.claims
+	p.claims = func(nonce string) map[string]any {
+		c := claims(nonce)
+		c["aud"] = "another-client"
+		return c
+	}
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, oidcLogin(t, handler, p))
+	require.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+func TestOIDCLogin_NoRole(t *testing.T) {
+	p := newTestOIDCProvider(t)
+	handler := newOIDCTestHandler(p)
+	claims := p.claims
+	p.claims = func(nonce string) map[string]any {
+		c := claims(nonce)
+		c["realm_access"] = map[string]any{"roles": []string{"guest"}}
+		return c
+	}
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, oidcLogin(t, handler, p))
+	require.Equal(t, http.StatusForbidden, resp.Code)
+}
+
+func TestOIDCLogin_NoStateCookie(t *testing.T) {
+	p := newTestOIDCProvider(t)
+	handler := newOIDCTestHandler(p)
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/admin/oidc/callback?code=test-code&state=x", nil))
+	require.Equal(t, http.StatusUnauthorized, resp.Code)
+}
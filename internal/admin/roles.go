@@ -0,0 +1,99 @@
+package admin
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/centrifugal/centrifugo/v6/internal/api"
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+)
+
+// Role of admin. Roles are ordered, each role includes permissions of previous ones.
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"
+	RoleOperator Role = "operator"
+	RoleAdmin    Role = "admin"
+)
+
+func (r Role) level() int {
+	switch r {
+	case RoleViewer:
+		return 1
+	case RoleOperator:
+		return 2
+	case RoleAdmin:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Includes checks whether role has permissions of other role.
+func (r Role) Includes(other Role) bool {
+	return r.level() > 0 && r.level() >= other.level()
+}
+
+// commandRoles defines minimal role required to call API command from admin web UI
+// for viewer and operator roles. RoleAdmin is allowed to call all commands, other
+// roles can't call commands not listed here.
+var commandRoles = map[api.CommandMethodType]Role{
+	api.Command_INFO:           RoleViewer,
+	api.Command_CHANNELS:       RoleViewer,
+	api.Command_PRESENCE:       RoleViewer,
+	api.Command_PRESENCE_STATS: RoleViewer,
+	api.Command_HISTORY:        RoleViewer,
+	api.Command_PUBLISH:        RoleOperator,
+	api.Command_BROADCAST:      RoleOperator,
+	api.Command_SUBSCRIBE:      RoleOperator,
+	api.Command_UNSUBSCRIBE:    RoleOperator,
+	api.Command_DISCONNECT:     RoleOperator,
+	api.Command_REFRESH:        RoleOperator,
+}
+
+// commandAllowed checks whether role is allowed to call API command.
+func commandAllowed(role Role, method api.CommandMethodType) bool {
+	if role == RoleAdmin {
+		return true
+	}
+	required, ok := commandRoles[method]
+	return ok && role.Includes(required)
+}
+
+// roleFromClaims returns the highest role granted by values of roles claim. Claim may be
+// nested (path segments separated by dots) and contain a string or an array of strings.
+func roleFromClaims(c configtypes.AdminOIDC, claims map[string]any) (Role, bool) {
+	var value any = claims
+	for _, part := range strings.Split(c.RolesClaim, ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		value = m[part]
+	}
+	var values []string
+	switch v := value.(type) {
+	case string:
+		values = []string{v}
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+	granted := map[Role][]string{
+		RoleAdmin:    c.AdminRoles,
+		RoleOperator: c.OperatorRoles,
+		RoleViewer:   c.ViewerRoles,
+	}
+	for _, role := range []Role{RoleAdmin, RoleOperator, RoleViewer} {
+		for _, v := range values {
+			if slices.Contains(granted[role], v) {
+				return role, true
+			}
+		}
+	}
+	return "", false
+}
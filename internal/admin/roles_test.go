@@ -0,0 +1,65 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/centrifugal/centrifugo/v6/internal/api"
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleIncludes(t *testing.T) {
+	require.True(t, RoleAdmin.Includes(RoleViewer))
+	require.True(t, RoleOperator.Includes(RoleOperator))
+	require.False(t, RoleViewer.Includes(RoleOperator))
+	require.False(t, Role("").Includes(RoleViewer))
+	require.False(t, Role("unknown").Includes(Role("unknown")))
+}
+
+func TestCommandAllowed(t *testing.T) {
+	require.True(t, commandAllowed(RoleViewer, api.Command_INFO))
+	require.False(t, commandAllowed(RoleViewer, api.Command_PUBLISH))
+	require.True(t, commandAllowed(RoleOperator, api.Command_PUBLISH))
+	require.False(t, commandAllowed(RoleOperator, api.Command_HISTORY_REMOVE))
+	require.True(t, commandAllowed(RoleAdmin, api.Command_RPC))
+	// Commands not listed are allowed for admin role only.
+	require.True(t, commandAllowed(RoleAdmin, api.Command_DEVICE_REGISTER))
+	require.False(t, commandAllowed(RoleOperator, api.Command_DEVICE_REGISTER))
+	require.False(t, commandAllowed(Role(""), api.Command_INFO))
+}
+
+func TestRoleFromClaims(t *testing.T) {
+	c := configtypes.AdminOIDC{
+		RolesClaim:    "roles",
+		ViewerRoles:   []string{"staff"},
+		OperatorRoles: []string{"support"},
+		AdminRoles:    []string{"ops"},
+	}
+	testCases := []struct {
+		name   string
+		claims map[string]any
+		role   Role
+		ok     bool
+	}{
+		{"string", map[string]any{"roles": "staff"}, RoleViewer, true},
+		{"highest", map[string]any{"roles": []any{"staff", "ops", "support"}}, RoleAdmin, true},
+		{"unknown", map[string]any{"roles": []any{"guest"}}, "", false},
+		{"missing", map[string]any{}, "", false},
+		{"wrong_type", map[string]any{"roles": 1}, "", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			role, ok := roleFromClaims(c, tc.claims)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.role, role)
+		})
+	}
+
+	c.RolesClaim = "realm_access.roles"
+	role, ok := roleFromClaims(c, map[string]any{"realm_access": map[string]any{"roles": []any{"support"}}})
+	require.True(t, ok)
+	require.Equal(t, RoleOperator, role)
+	_, ok = roleFromClaims(c, map[string]any{"realm_access": "support"})
+	require.False(t, ok)
+}
@@ -8,10 +8,18 @@ import (
 	"time"
 
 	. "github.com/centrifugal/centrifugo/v6/internal/apiproto"
+	"github.com/centrifugal/centrifugo/v6/internal/audit"
+	"github.com/centrifugal/centrifugo/v6/internal/clientcontext"
 	"github.com/centrifugal/centrifugo/v6/internal/config"
 	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/historyquery"
+	"github.com/centrifugal/centrifugo/v6/internal/mapfilter"
+	"github.com/centrifugal/centrifugo/v6/internal/mapsnapshot"
 	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+	"github.com/centrifugal/centrifugo/v6/internal/schedule"
 	"github.com/centrifugal/centrifugo/v6/internal/subsource"
+	"github.com/centrifugal/centrifugo/v6/internal/tenant"
+	"github.com/centrifugal/centrifugo/v6/internal/throttle"
 
 	"github.com/centrifugal/centrifuge"
 	"github.com/rs/zerolog/log"
@@ -29,6 +37,14 @@ type Executor struct {
 	config       ExecutorConfig
 	rpcExtension map[string]RPCHandler
 	surveyCaller SurveyCaller
+	throttler    *throttle.Throttler
+	tenants      *tenant.Registry
+	auditor      *audit.Logger
+	scheduler    *schedule.Scheduler
+	history      *historyquery.Reader
+
+	mapStateFilterReader MapStateFilterReader
+	mapSnapshotImporter  MapSnapshotImporter
 }
 
 // SurveyCaller can do surveys.
@@ -49,6 +65,7 @@ func NewExecutor(n *centrifuge.Node, cfgContainer *config.Container, surveyCalle
 		config:       config,
 		surveyCaller: surveyCaller,
 		rpcExtension: make(map[string]RPCHandler),
+		history:      historyquery.NewReader(n, nil),
 	}
 	return e
 }
@@ -58,88 +75,203 @@ func (h *Executor) SetRPCExtension(method string, handler RPCHandler) {
 	h.rpcExtension[method] = handler
 }
 
+// SetThrottler sets Throttler used to coalesce publications in namespaces with
+// throttle enabled. Without Throttler publications are never coalesced.
+func (h *Executor) SetThrottler(t *throttle.Throttler) {
+	h.throttler = t
+}
+
+// SetTenants sets tenant Registry used to enforce tenant publish rate limits.
+func (h *Executor) SetTenants(r *tenant.Registry) {
+	h.tenants = r
+}
+
+// SetHistoryRangeReader sets broker which can filter history by time range
+// natively. Without it time range history queries filter channel history stream.
+func (h *Executor) SetHistoryRangeReader(r historyquery.RangeReader) {
+	h.history = historyquery.NewReader(h.node, r)
+}
+
+// isTenantRequest reports whether request was authorized with tenant API key.
+func isTenantRequest(ctx context.Context) bool {
+	_, ok := clientcontext.GetTenantFromContext(ctx)
+	return ok
+}
+
+// tenantAllowed checks that request authorized with tenant API key only touches
+// channels of that tenant. Requests authorized with global API key are not limited.
+func (h *Executor) tenantAllowed(ctx context.Context, channels ...string) bool {
+	tenantName, ok := clientcontext.GetTenantFromContext(ctx)
+	if !ok {
+		return true
+	}
+	for _, ch := range channels {
+		if h.cfgContainer.ChannelTenant(ch) != tenantName {
+			return false
+		}
+	}
+	return true
+}
+
+// tenantPublishAllowed checks publish rate limit of channel tenant.
+func (h *Executor) tenantPublishAllowed(ch string) bool {
+	if h.tenants == nil {
+		return true
+	}
+	tenantName := h.cfgContainer.ChannelTenant(ch)
+	return tenantName == "" || h.tenants.PublishAllowed(tenantName)
+}
+
+// publishThrottled calls publish right away if throttling is not enabled for the channel or
+// the current throttle window for the channel is not active. Otherwise publish is deferred
+// to the end of the window (replacing a previously deferred one) and false is returned.
+func (h *Executor) publishThrottled(
+	ch string, nsName string, chOpts configtypes.ChannelOptions, tags map[string]string,
+	publish func() (centrifuge.PublishResult, error),
+) (centrifuge.PublishResult, bool, error) {
+	if h.throttler == nil || !chOpts.Throttle.Enabled {
+		result, err := publish()
+		return result, true, err
+	}
+	key := throttle.Key(ch, chOpts.Throttle.TagKey, tags)
+	runNow, replaced := h.throttler.Submit(key, chOpts.Throttle.Interval.ToDuration(), func() {
+		if _, err := publish(); err != nil {
+			log.Error().Err(err).Str("channel", ch).Msg("error publishing throttled data to channel")
+		}
+	})
+	if replaced {
+		metrics.IncThrottleCoalesced(nsName)
+	}
+	if !runNow {
+		return centrifuge.PublishResult{}, false, nil
+	}
+	result, err := publish()
+	return result, true, err
+}
+
 func (h *Executor) processCmd(ctx context.Context, cmd *Command, i int, replies []*Reply) {
 	var method string
+	var req any
 	if cmd.Publish != nil {
 		method = "publish"
+		req = cmd.Publish
 		res := h.Publish(ctx, cmd.Publish)
 		replies[i].Publish, replies[i].Error = res.Result, res.Error
 	} else if cmd.Broadcast != nil {
 		method = "broadcast"
+		req = cmd.Broadcast
 		res := h.Broadcast(ctx, cmd.Broadcast)
 		replies[i].Broadcast, replies[i].Error = res.Result, res.Error
 	} else if cmd.Subscribe != nil {
 		method = "subscribe"
+		req = cmd.Subscribe
 		res := h.Subscribe(ctx, cmd.Subscribe)
 		replies[i].Subscribe, replies[i].Error = res.Result, res.Error
 	} else if cmd.Unsubscribe != nil {
 		method = "unsubscribe"
+		req = cmd.Unsubscribe
 		res := h.Unsubscribe(ctx, cmd.Unsubscribe)
 		replies[i].Unsubscribe, replies[i].Error = res.Result, res.Error
 	} else if cmd.Disconnect != nil {
 		method = "disconnect"
+		req = cmd.Disconnect
 		res := h.Disconnect(ctx, cmd.Disconnect)
 		replies[i].Disconnect, replies[i].Error = res.Result, res.Error
 	} else if cmd.History != nil {
 		method = "history"
+		req = cmd.History
 		res := h.History(ctx, cmd.History)
 		replies[i].History, replies[i].Error = res.Result, res.Error
 	} else if cmd.HistoryRemove != nil {
 		method = "history_remove"
+		req = cmd.HistoryRemove
 		res := h.HistoryRemove(ctx, cmd.HistoryRemove)
 		replies[i].HistoryRemove, replies[i].Error = res.Result, res.Error
 	} else if cmd.Presence != nil {
 		method = "presence"
+		req = cmd.Presence
 		res := h.Presence(ctx, cmd.Presence)
 		replies[i].Presence, replies[i].Error = res.Result, res.Error
 	} else if cmd.PresenceStats != nil {
 		method = "presence_stats"
+		req = cmd.PresenceStats
 		res := h.PresenceStats(ctx, cmd.PresenceStats)
 		replies[i].PresenceStats, replies[i].Error = res.Result, res.Error
 	} else if cmd.Info != nil {
 		method = "info"
+		req = cmd.Info
 		res := h.Info(ctx, cmd.Info)
 		replies[i].Info, replies[i].Error = res.Result, res.Error
 	} else if cmd.Rpc != nil {
 		method = "rpc"
+		req = cmd.Rpc
 		res := h.RPC(ctx, cmd.Rpc)
 		replies[i].Rpc, replies[i].Error = res.Result, res.Error
 	} else if cmd.Refresh != nil {
 		method = "refresh"
+		req = cmd.Refresh
 		res := h.Refresh(ctx, cmd.Refresh)
 		replies[i].Refresh, replies[i].Error = res.Result, res.Error
 	} else if cmd.Channels != nil {
 		method = "channels"
+		req = cmd.Channels
 		res := h.Channels(ctx, cmd.Channels)
 		replies[i].Channels, replies[i].Error = res.Result, res.Error
 	} else if cmd.MapPublish != nil {
 		method = "map_publish"
+		req = cmd.MapPublish
 		res := h.MapPublish(ctx, cmd.MapPublish)
 		replies[i].MapPublish, replies[i].Error = res.Result, res.Error
 	} else if cmd.MapRemove != nil {
 		method = "map_remove"
+		req = cmd.MapRemove
 		res := h.MapRemove(ctx, cmd.MapRemove)
 		replies[i].MapRemove, replies[i].Error = res.Result, res.Error
 	} else if cmd.MapReadState != nil {
 		method = "map_read_state"
+		req = cmd.MapReadState
 		res := h.MapReadState(ctx, cmd.MapReadState)
 		replies[i].MapReadState, replies[i].Error = res.Result, res.Error
 	} else if cmd.MapReadStream != nil {
 		method = "map_read_stream"
+		req = cmd.MapReadStream
 		res := h.MapReadStream(ctx, cmd.MapReadStream)
 		replies[i].MapReadStream, replies[i].Error = res.Result, res.Error
 	} else if cmd.MapStats != nil {
 		method = "map_stats"
+		req = cmd.MapStats
 		res := h.MapStats(ctx, cmd.MapStats)
 		replies[i].MapStats, replies[i].Error = res.Result, res.Error
 	} else if cmd.MapClear != nil {
 		method = "map_clear"
+		req = cmd.MapClear
 		res := h.MapClear(ctx, cmd.MapClear)
 		replies[i].MapClear, replies[i].Error = res.Result, res.Error
 	} else if cmd.SharedPollPublish != nil {
 		method = "shared_poll_publish"
+		req = cmd.SharedPollPublish
 		res := h.SharedPollPublish(ctx, cmd.SharedPollPublish)
 		replies[i].SharedPollPublish, replies[i].Error = res.Result, res.Error
+	} else if cmd.MapExport != nil {
+		method = "map_export"
+		req = cmd.MapExport
+		res := h.MapExport(ctx, cmd.MapExport)
+		replies[i].MapExport, replies[i].Error = res.Result, res.Error
+	} else if cmd.MapImport != nil {
+		method = "map_import"
+		req = cmd.MapImport
+		res := h.MapImport(ctx, cmd.MapImport)
+		replies[i].MapImport, replies[i].Error = res.Result, res.Error
+	} else if cmd.ScheduledList != nil {
+		method = "scheduled_list"
+		req = cmd.ScheduledList
+		res := h.ScheduledList(ctx, cmd.ScheduledList)
+		replies[i].ScheduledList, replies[i].Error = res.Result, res.Error
+	} else if cmd.ScheduledCancel != nil {
+		method = "scheduled_cancel"
+		req = cmd.ScheduledCancel
+		res := h.ScheduledCancel(ctx, cmd.ScheduledCancel)
+		replies[i].ScheduledCancel, replies[i].Error = res.Result, res.Error
 	} else {
 		method = "unknown"
 		replies[i].Error = ErrorNotFound
@@ -147,6 +279,9 @@ func (h *Executor) processCmd(ctx context.Context, cmd *Command, i int, replies
 	if replies[i].Error != nil {
 		metrics.IncAPIError(h.config.Protocol, method, replies[i].Error.Code)
 	}
+	if req != nil {
+		h.audit(ctx, method, req, replies[i].Error)
+	}
 }
 
 // batchRequestMaxConcurrency is applied for the parallel batch request.
@@ -193,6 +328,11 @@ func (h *Executor) Publish(ctx context.Context, cmd *PublishRequest) *PublishRes
 
 	resp := &PublishResponse{}
 
+	if !h.tenantAllowed(ctx, cmd.Channel) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+
 	if ch == "" {
 		log.Error().Err(errors.New("channel required for publish")).Msg("bad publish request")
 		resp.Error = ErrorBadRequest
@@ -211,7 +351,7 @@ func (h *Executor) Publish(ctx context.Context, cmd *PublishRequest) *PublishRes
 		data = cmd.Data
 	}
 
-	_, _, chOpts, found, err := h.cfgContainer.ChannelOptions(ch)
+	nsName, _, chOpts, found, err := h.cfgContainer.ChannelOptions(ch)
 	if err != nil {
 		resp.Error = ErrorInternal
 		return resp
@@ -237,6 +377,31 @@ func (h *Executor) Publish(ctx context.Context, cmd *PublishRequest) *PublishRes
 		resp.Error = ErrorBadRequest
 		return resp
 	}
+	if err := config.ValidatePublicationDataSchema(data, chOpts); err != nil {
+		metrics.IncPublicationSchemaViolation(nsName, h.config.Protocol)
+		log.Info().Err(err).Str("channel", ch).Msg("publication data schema violation")
+		resp.Error = ErrorSchemaViolation
+		return resp
+	}
+
+	if publishAt, scheduled, respErr := publishTime(cmd.PublishAt, cmd.Delay); scheduled {
+		if respErr != nil {
+			resp.Error = respErr
+			return resp
+		}
+		id, respErr := h.schedule(ctx, "publish", []string{ch}, cmd, publishAt)
+		if respErr != nil {
+			resp.Error = respErr
+			return resp
+		}
+		resp.Result = &PublishResult{ScheduleId: id}
+		return resp
+	}
+
+	if !h.tenantPublishAllowed(ch) {
+		resp.Error = ErrorTooManyRequests
+		return resp
+	}
 
 	historySize := chOpts.HistorySize
 	historyTTL := chOpts.HistoryTTL
@@ -251,19 +416,29 @@ func (h *Executor) Publish(ctx context.Context, cmd *PublishRequest) *PublishRes
 		delta = true
 	}
 
-	result, err := h.node.Publish(
-		cmd.Channel, data,
-		centrifuge.WithHistory(historySize, historyTTL.ToDuration(), historyMetaTTL.ToDuration()),
-		centrifuge.WithTags(cmd.GetTags()),
-		centrifuge.WithIdempotencyKey(cmd.GetIdempotencyKey()),
-		centrifuge.WithDelta(delta),
-		centrifuge.WithVersion(cmd.Version, cmd.VersionEpoch),
-	)
+	result, published, err := h.publishThrottled(ch, nsName, chOpts, cmd.GetTags(), func() (centrifuge.PublishResult, error) {
+		return h.node.Publish(
+			cmd.Channel, data,
+			centrifuge.WithHistory(historySize, historyTTL.ToDuration(), historyMetaTTL.ToDuration()),
+			centrifuge.WithTags(cmd.GetTags()),
+			centrifuge.WithIdempotencyKey(cmd.GetIdempotencyKey()),
+			centrifuge.WithDelta(delta),
+			centrifuge.WithVersion(cmd.Version, cmd.VersionEpoch),
+		)
+	})
 	if err != nil {
 		log.Error().Err(err).Str("channel", cmd.Channel).Msg("error publishing data to channel")
 		resp.Error = ErrorInternal
 		return resp
 	}
+	if tenantName := h.cfgContainer.ChannelTenant(ch); tenantName != "" {
+		metrics.IncTenantPublication(tenantName, "api")
+	}
+	if !published {
+		// Publication deferred till the end of the throttle window, no stream position yet.
+		resp.Result = &PublishResult{Deferred: true}
+		return resp
+	}
 	resp.Result = &PublishResult{
 		Offset: result.StreamPosition.Offset,
 		Epoch:  result.StreamPosition.Epoch,
@@ -279,6 +454,11 @@ func (h *Executor) Broadcast(ctx context.Context, cmd *BroadcastRequest) *Broadc
 
 	resp := &BroadcastResponse{}
 
+	if !h.tenantAllowed(ctx, cmd.Channels...) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+
 	channels := cmd.Channels
 
 	if h.config.UseOpenTelemetry {
@@ -304,6 +484,23 @@ func (h *Executor) Broadcast(ctx context.Context, cmd *BroadcastRequest) *Broadc
 		data = cmd.Data
 	}
 
+	if publishAt, scheduled, respErr := publishTime(cmd.PublishAt, cmd.Delay); scheduled {
+		if respErr == nil {
+			respErr = h.validateBroadcastChannels(channels, data)
+		}
+		if respErr != nil {
+			resp.Error = respErr
+			return resp
+		}
+		id, respErr := h.schedule(ctx, "broadcast", channels, cmd, publishAt)
+		if respErr != nil {
+			resp.Error = respErr
+			return resp
+		}
+		resp.Result = &BroadcastResult{ScheduleId: id}
+		return resp
+	}
+
 	sem := make(chan struct{}, broadcastRequestMaxConcurrency)
 
 	responses := make([]*PublishResponse, len(channels))
@@ -322,7 +519,7 @@ func (h *Executor) Broadcast(ctx context.Context, cmd *BroadcastRequest) *Broadc
 				return
 			}
 
-			_, _, chOpts, found, err := h.cfgContainer.ChannelOptions(ch)
+			nsName, _, chOpts, found, err := h.cfgContainer.ChannelOptions(ch)
 			if err != nil {
 				respError := ErrorInternal
 				metrics.IncAPIError(h.config.Protocol, "broadcast_publish", respError.Code)
@@ -346,6 +543,20 @@ func (h *Executor) Broadcast(ctx context.Context, cmd *BroadcastRequest) *Broadc
 				responses[i] = &PublishResponse{Error: respError}
 				return
 			}
+			if err := config.ValidatePublicationDataSchema(data, chOpts); err != nil {
+				respError := ErrorSchemaViolation
+				metrics.IncAPIError(h.config.Protocol, "broadcast_publish", respError.Code)
+				metrics.IncPublicationSchemaViolation(nsName, h.config.Protocol)
+				log.Info().Err(err).Str("channel", ch).Msg("publication data schema violation")
+				responses[i] = &PublishResponse{Error: respError}
+				return
+			}
+			if !h.tenantPublishAllowed(ch) {
+				respError := ErrorTooManyRequests
+				metrics.IncAPIError(h.config.Protocol, "broadcast_publish", respError.Code)
+				responses[i] = &PublishResponse{Error: respError}
+				return
+			}
 
 			historySize := chOpts.HistorySize
 			historyTTL := chOpts.HistoryTTL
@@ -360,16 +571,23 @@ func (h *Executor) Broadcast(ctx context.Context, cmd *BroadcastRequest) *Broadc
 				delta = true
 			}
 
-			result, err := h.node.Publish(
-				ch, data,
-				centrifuge.WithHistory(historySize, historyTTL.ToDuration(), historyMetaTTL.ToDuration()),
-				centrifuge.WithTags(cmd.GetTags()),
-				centrifuge.WithIdempotencyKey(cmd.GetIdempotencyKey()),
-				centrifuge.WithDelta(delta),
-				centrifuge.WithVersion(cmd.Version, cmd.VersionEpoch),
-			)
+			result, published, err := h.publishThrottled(ch, nsName, chOpts, cmd.GetTags(), func() (centrifuge.PublishResult, error) {
+				return h.node.Publish(
+					ch, data,
+					centrifuge.WithHistory(historySize, historyTTL.ToDuration(), historyMetaTTL.ToDuration()),
+					centrifuge.WithTags(cmd.GetTags()),
+					centrifuge.WithIdempotencyKey(cmd.GetIdempotencyKey()),
+					centrifuge.WithDelta(delta),
+					centrifuge.WithVersion(cmd.Version, cmd.VersionEpoch),
+				)
+			})
 			resp := &PublishResponse{}
-			if err == nil {
+			if tenantName := h.cfgContainer.ChannelTenant(ch); err == nil && tenantName != "" {
+				metrics.IncTenantPublication(tenantName, "api")
+			}
+			if err == nil && !published {
+				resp.Result = &PublishResult{Deferred: true}
+			} else if err == nil {
 				resp.Result = &PublishResult{
 					Offset: result.StreamPosition.Offset,
 					Epoch:  result.StreamPosition.Epoch,
@@ -390,11 +608,16 @@ func (h *Executor) Broadcast(ctx context.Context, cmd *BroadcastRequest) *Broadc
 
 // Subscribe subscribes user to a channel and sends subscribe
 // control message to other nodes, so they could also subscribe user.
-func (h *Executor) Subscribe(_ context.Context, cmd *SubscribeRequest) *SubscribeResponse {
+func (h *Executor) Subscribe(ctx context.Context, cmd *SubscribeRequest) *SubscribeResponse {
 	defer metrics.ObserveAPICommand(time.Now(), h.config.Protocol, "subscribe")
 
 	resp := &SubscribeResponse{}
 
+	if !h.tenantAllowed(ctx, cmd.Channel) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+
 	user := cmd.User
 	channel := cmd.Channel
 
@@ -469,11 +692,16 @@ func (h *Executor) Subscribe(_ context.Context, cmd *SubscribeRequest) *Subscrib
 
 // Unsubscribe unsubscribes user from channel and sends unsubscribe
 // control message to other nodes, so they could also unsubscribe user.
-func (h *Executor) Unsubscribe(_ context.Context, cmd *UnsubscribeRequest) *UnsubscribeResponse {
+func (h *Executor) Unsubscribe(ctx context.Context, cmd *UnsubscribeRequest) *UnsubscribeResponse {
 	defer metrics.ObserveAPICommand(time.Now(), h.config.Protocol, "unsubscribe")
 
 	resp := &UnsubscribeResponse{}
 
+	if !h.tenantAllowed(ctx, cmd.Channel) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+
 	user := cmd.User
 	channel := cmd.Channel
 
@@ -501,11 +729,16 @@ func (h *Executor) Unsubscribe(_ context.Context, cmd *UnsubscribeRequest) *Unsu
 
 // Disconnect disconnects user by its ID and sends disconnect
 // control message to other nodes, so they could also disconnect user.
-func (h *Executor) Disconnect(_ context.Context, cmd *DisconnectRequest) *DisconnectResponse {
+func (h *Executor) Disconnect(ctx context.Context, cmd *DisconnectRequest) *DisconnectResponse {
 	defer metrics.ObserveAPICommand(time.Now(), h.config.Protocol, "disconnect")
 
 	resp := &DisconnectResponse{}
 
+	if isTenantRequest(ctx) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+
 	user := cmd.User
 
 	disconnect := centrifuge.DisconnectForceNoReconnect
@@ -532,10 +765,16 @@ func (h *Executor) Disconnect(_ context.Context, cmd *DisconnectRequest) *Discon
 }
 
 // Refresh user connection by its ID.
-func (h *Executor) Refresh(_ context.Context, cmd *RefreshRequest) *RefreshResponse {
+func (h *Executor) Refresh(ctx context.Context, cmd *RefreshRequest) *RefreshResponse {
 	defer metrics.ObserveAPICommand(time.Now(), h.config.Protocol, "refresh")
 
 	resp := &RefreshResponse{}
+
+	if isTenantRequest(ctx) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+
 	user := cmd.User
 
 	err := h.node.Refresh(
@@ -556,11 +795,16 @@ func (h *Executor) Refresh(_ context.Context, cmd *RefreshRequest) *RefreshRespo
 }
 
 // Presence returns response with presence information for channel.
-func (h *Executor) Presence(_ context.Context, cmd *PresenceRequest) *PresenceResponse {
+func (h *Executor) Presence(ctx context.Context, cmd *PresenceRequest) *PresenceResponse {
 	defer metrics.ObserveAPICommand(time.Now(), h.config.Protocol, "presence")
 
 	resp := &PresenceResponse{}
 
+	if !h.tenantAllowed(ctx, cmd.Channel) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+
 	ch := cmd.Channel
 
 	if ch == "" {
@@ -608,11 +852,16 @@ func (h *Executor) Presence(_ context.Context, cmd *PresenceRequest) *PresenceRe
 }
 
 // PresenceStats returns response with presence stats information for channel.
-func (h *Executor) PresenceStats(_ context.Context, cmd *PresenceStatsRequest) *PresenceStatsResponse {
+func (h *Executor) PresenceStats(ctx context.Context, cmd *PresenceStatsRequest) *PresenceStatsResponse {
 	defer metrics.ObserveAPICommand(time.Now(), h.config.Protocol, "presence_stats")
 
 	resp := &PresenceStatsResponse{}
 
+	if !h.tenantAllowed(ctx, cmd.Channel) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+
 	ch := cmd.Channel
 
 	if ch == "" {
@@ -652,11 +901,16 @@ func (h *Executor) PresenceStats(_ context.Context, cmd *PresenceStatsRequest) *
 }
 
 // History returns response with history information for channel.
-func (h *Executor) History(_ context.Context, cmd *HistoryRequest) *HistoryResponse {
+func (h *Executor) History(ctx context.Context, cmd *HistoryRequest) *HistoryResponse {
 	defer metrics.ObserveAPICommand(time.Now(), h.config.Protocol, "history")
 
 	resp := &HistoryResponse{}
 
+	if !h.tenantAllowed(ctx, cmd.Channel) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+
 	ch := cmd.Channel
 
 	if ch == "" {
@@ -680,23 +934,37 @@ func (h *Executor) History(_ context.Context, cmd *HistoryRequest) *HistoryRespo
 		return resp
 	}
 
+	if cmd.FromTime < 0 || cmd.ToTime < 0 || (cmd.Cursor != "" && cmd.Since != nil) {
+		resp.Error = ErrorBadRequest
+		return resp
+	}
+
 	var sp *centrifuge.StreamPosition
 	if cmd.Since != nil {
 		sp = &centrifuge.StreamPosition{
 			Epoch:  cmd.Since.Epoch,
 			Offset: cmd.Since.Offset,
 		}
+	} else if cmd.Cursor != "" {
+		sp, err = historyquery.ParseCursor(cmd.Cursor)
+		if err != nil {
+			resp.Error = ErrorBadRequest
+			return resp
+		}
 	}
 
 	historyMetaTTL := chOpts.HistoryMetaTTL
 
-	history, err := h.node.History(
-		ch,
-		centrifuge.WithHistoryMetaTTL(historyMetaTTL.ToDuration()),
-		centrifuge.WithLimit(int(cmd.Limit)),
-		centrifuge.WithSince(sp),
-		centrifuge.WithReverse(cmd.Reverse),
-	)
+	history, err := h.history.History(ch, historyquery.Query{
+		Since:   sp,
+		Limit:   int(cmd.Limit),
+		Reverse: cmd.Reverse,
+		Range: historyquery.TimeRange{
+			From: cmd.FromTime,
+			To:   cmd.ToTime,
+		},
+		MetaTTL: historyMetaTTL.ToDuration(),
+	})
 	if err != nil {
 		log.Error().Err(err).Str("channel", ch).Msg("error getting history for channel")
 		if errors.Is(err, centrifuge.ErrorUnrecoverablePosition) {
@@ -714,6 +982,7 @@ func (h *Executor) History(_ context.Context, cmd *HistoryRequest) *HistoryRespo
 			Data:   Raw(pub.Data),
 			Offset: pub.Offset,
 			Tags:   pub.Tags,
+			Time:   pub.Time,
 		}
 		if pub.Info != nil {
 			apiPub.Info = &ClientInfo{
@@ -728,18 +997,24 @@ func (h *Executor) History(_ context.Context, cmd *HistoryRequest) *HistoryRespo
 
 	resp.Result = &HistoryResult{
 		Publications: apiPubs,
-		Offset:       history.Offset,
-		Epoch:        history.Epoch,
+		Offset:       history.StreamPosition.Offset,
+		Epoch:        history.StreamPosition.Epoch,
+		NextCursor:   history.NextCursor,
 	}
 	return resp
 }
 
 // HistoryRemove removes all history information for channel.
-func (h *Executor) HistoryRemove(_ context.Context, cmd *HistoryRemoveRequest) *HistoryRemoveResponse {
+func (h *Executor) HistoryRemove(ctx context.Context, cmd *HistoryRemoveRequest) *HistoryRemoveResponse {
 	defer metrics.ObserveAPICommand(time.Now(), h.config.Protocol, "history_remove")
 
 	resp := &HistoryRemoveResponse{}
 
+	if !h.tenantAllowed(ctx, cmd.Channel) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+
 	ch := cmd.Channel
 
 	if ch == "" {
@@ -774,11 +1049,16 @@ func (h *Executor) HistoryRemove(_ context.Context, cmd *HistoryRemoveRequest) *
 }
 
 // Info returns information about running nodes.
-func (h *Executor) Info(_ context.Context, _ *InfoRequest) *InfoResponse {
+func (h *Executor) Info(ctx context.Context, _ *InfoRequest) *InfoResponse {
 	defer metrics.ObserveAPICommand(time.Now(), h.config.Protocol, "info")
 
 	resp := &InfoResponse{}
 
+	if isTenantRequest(ctx) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+
 	info, err := h.node.Info()
 	if err != nil {
 		log.Error().Err(err).Msg("error calling info")
@@ -820,6 +1100,11 @@ func (h *Executor) RPC(ctx context.Context, cmd *RPCRequest) *RPCResponse {
 
 	resp := &RPCResponse{}
 
+	if isTenantRequest(ctx) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+
 	if cmd.Method == "" {
 		log.Error().Err(errors.New("rpc method required")).Msg("bad rpc request")
 		resp.Error = ErrorBadRequest
@@ -862,6 +1147,14 @@ func (h *Executor) Channels(ctx context.Context, cmd *ChannelsRequest) *Channels
 		return resp
 	}
 
+	if tenantName, ok := clientcontext.GetTenantFromContext(ctx); ok {
+		for ch := range channels {
+			if h.cfgContainer.ChannelTenant(ch) != tenantName {
+				delete(channels, ch)
+			}
+		}
+	}
+
 	resp.Result = &ChannelsResult{
 		Channels: channels,
 	}
@@ -880,12 +1173,18 @@ func (h *Executor) MapPublish(ctx context.Context, cmd *MapPublishRequest) *MapP
 	}
 
 	resp := &MapPublishResponse{}
+
+	if !h.tenantAllowed(ctx, cmd.Channel) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+
 	if ch == "" || cmd.Key == "" {
 		resp.Error = ErrorBadRequest
 		return resp
 	}
 
-	_, _, chOpts, found, err := h.cfgContainer.ChannelOptions(ch)
+	nsName, _, chOpts, found, err := h.cfgContainer.ChannelOptions(ch)
 	if err != nil {
 		resp.Error = ErrorInternal
 		return resp
@@ -911,6 +1210,14 @@ func (h *Executor) MapPublish(ctx context.Context, cmd *MapPublishRequest) *MapP
 	} else {
 		data = cmd.Data
 	}
+	if len(data) > 0 {
+		if err := config.ValidatePublicationDataSchema(data, chOpts); err != nil {
+			metrics.IncPublicationSchemaViolation(nsName, h.config.Protocol)
+			log.Info().Err(err).Str("channel", ch).Msg("map publication data schema violation")
+			resp.Error = ErrorSchemaViolation
+			return resp
+		}
+	}
 
 	delta := cmd.Delta
 	if chOpts.DeltaPublish {
@@ -925,6 +1232,9 @@ func (h *Executor) MapPublish(ctx context.Context, cmd *MapPublishRequest) *MapP
 	}
 	opts.Version = cmd.Version
 	opts.VersionEpoch = cmd.VersionEpoch
+	if chOpts.Map.IndexTags && len(data) > 0 {
+		opts.Tags = mapfilter.Tags(chOpts.Map.Indexes.FilterIndexes(), data, opts.Tags)
+	}
 	if cmd.KeyMode != "" {
 		switch cmd.KeyMode {
 		case "if_new", "if_exists":
@@ -962,6 +1272,12 @@ func (h *Executor) MapRemove(ctx context.Context, cmd *MapRemoveRequest) *MapRem
 	}
 
 	resp := &MapRemoveResponse{}
+
+	if !h.tenantAllowed(ctx, cmd.Channel) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+
 	if ch == "" || cmd.Key == "" {
 		resp.Error = ErrorBadRequest
 		return resp
@@ -1012,6 +1328,12 @@ func (h *Executor) MapReadState(ctx context.Context, cmd *MapReadStateRequest) *
 	}
 
 	resp := &MapReadStateResponse{}
+
+	if !h.tenantAllowed(ctx, cmd.Channel) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+
 	if ch == "" {
 		resp.Error = ErrorBadRequest
 		return resp
@@ -1042,24 +1364,43 @@ func (h *Executor) MapReadState(ctx context.Context, cmd *MapReadStateRequest) *
 		opts.Revision = &centrifuge.StreamPosition{Offset: cmd.RevisionOffset, Epoch: cmd.RevisionEpoch}
 	}
 
-	result, err := h.node.MapStateRead(ctx, ch, opts)
-	if err != nil {
-		log.Error().Err(err).Str("channel", ch).Msg("error in map read state")
-		resp.Error = ErrorInternal
-		return resp
+	var result centrifuge.MapStateResult
+	if cmd.Filter != "" || cmd.Sort != "" {
+		if cmd.Key != "" {
+			log.Info().Str("channel", ch).Msg("map_read_state key lookup can not be combined with filter or sort")
+			resp.Error = ErrorBadRequest
+			return resp
+		}
+		q, err := mapfilter.Parse(cmd.Filter, cmd.Sort, chOpts.Map.Indexes.FilterIndexes())
+		if err != nil {
+			log.Info().Err(err).Str("channel", ch).Msg("bad map_read_state filter")
+			resp.Error = ErrorBadRequest
+			return resp
+		}
+		result, err = h.mapReadFilteredState(ctx, ch, q, opts, chOpts.Map.FilterScanLimit)
+		if err != nil {
+			if errors.Is(err, mapfilter.ErrInvalidExpression) || errors.Is(err, errMapFilterScanLimit) {
+				log.Info().Err(err).Str("channel", ch).Msg("can't read filtered map state")
+				resp.Error = ErrorBadRequest
+				return resp
+			}
+			log.Error().Err(err).Str("channel", ch).Msg("error in map read filtered state")
+			resp.Error = ErrorInternal
+			return resp
+		}
+	} else {
+		var err error
+		result, err = h.node.MapStateRead(ctx, ch, opts)
+		if err != nil {
+			log.Error().Err(err).Str("channel", ch).Msg("error in map read state")
+			resp.Error = ErrorInternal
+			return resp
+		}
 	}
 
 	entries := make([]*MapEntry, 0, len(result.Publications))
 	for _, pub := range result.Publications {
-		entries = append(entries, &MapEntry{
-			Key:     pub.Key,
-			Data:    pub.Data,
-			Tags:    pub.Tags,
-			Offset:  pub.Offset,
-			Score:   pub.Score,
-			Removed: pub.Removed,
-			Time:    pub.Time,
-		})
+		entries = append(entries, mapEntryFromPublication(pub))
 	}
 
 	resp.Result = &MapReadStateResult{
@@ -1082,6 +1423,12 @@ func (h *Executor) MapReadStream(ctx context.Context, cmd *MapReadStreamRequest)
 	}
 
 	resp := &MapReadStreamResponse{}
+
+	if !h.tenantAllowed(ctx, cmd.Channel) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+
 	if ch == "" {
 		resp.Error = ErrorBadRequest
 		return resp
@@ -1121,15 +1468,7 @@ func (h *Executor) MapReadStream(ctx context.Context, cmd *MapReadStreamRequest)
 
 	entries := make([]*MapEntry, 0, len(result.Publications))
 	for _, pub := range result.Publications {
-		entries = append(entries, &MapEntry{
-			Key:     pub.Key,
-			Data:    pub.Data,
-			Tags:    pub.Tags,
-			Offset:  pub.Offset,
-			Score:   pub.Score,
-			Removed: pub.Removed,
-			Time:    pub.Time,
-		})
+		entries = append(entries, mapEntryFromPublication(pub))
 	}
 
 	resp.Result = &MapReadStreamResult{
@@ -1151,6 +1490,12 @@ func (h *Executor) MapStats(ctx context.Context, cmd *MapStatsRequest) *MapStats
 	}
 
 	resp := &MapStatsResponse{}
+
+	if !h.tenantAllowed(ctx, cmd.Channel) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+
 	if ch == "" {
 		resp.Error = ErrorBadRequest
 		return resp
@@ -1194,6 +1539,12 @@ func (h *Executor) MapClear(ctx context.Context, cmd *MapClearRequest) *MapClear
 	}
 
 	resp := &MapClearResponse{}
+
+	if !h.tenantAllowed(ctx, cmd.Channel) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+
 	if ch == "" {
 		resp.Error = ErrorBadRequest
 		return resp
@@ -1224,6 +1575,233 @@ func (h *Executor) MapClear(ctx context.Context, cmd *MapClearRequest) *MapClear
 	return resp
 }
 
+// MapExport exports the current state of a map channel together with its stream position.
+func (h *Executor) MapExport(ctx context.Context, cmd *MapExportRequest) *MapExportResponse {
+	defer metrics.ObserveAPICommand(time.Now(), h.config.Protocol, "map_export")
+
+	ch := cmd.Channel
+	if h.config.UseOpenTelemetry {
+		span := trace.SpanFromContext(ctx)
+		span.SetAttributes(attribute.String("centrifugo.channel", ch))
+	}
+
+	resp := &MapExportResponse{}
+
+	if !h.tenantAllowed(ctx, cmd.Channel) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+
+	if ch == "" {
+		resp.Error = ErrorBadRequest
+		return resp
+	}
+
+	_, _, chOpts, found, err := h.cfgContainer.ChannelOptions(ch)
+	if err != nil {
+		resp.Error = ErrorInternal
+		return resp
+	}
+	if !found {
+		resp.Error = ErrorUnknownChannel
+		return resp
+	}
+	if chOpts.SubscriptionType != "map" && chOpts.SubscriptionType != "map_clients" && chOpts.SubscriptionType != "map_users" {
+		log.Warn().Str("channel", ch).Str("subscription_type", chOpts.SubscriptionType).Msg("map_export called on non-map namespace")
+		resp.Error = ErrorBadRequest
+		return resp
+	}
+
+	snapshot, err := h.readMapSnapshot(ctx, ch)
+	if err != nil {
+		log.Error().Err(err).Str("channel", ch).Msg("error in map export")
+		resp.Error = ErrorInternal
+		return resp
+	}
+	resp.Result = &MapExportResult{Snapshot: snapshot}
+	return resp
+}
+
+// MapImport imports a map channel snapshot made by MapExport. Stream position of the
+// snapshot is kept when map broker supports native import.
+func (h *Executor) MapImport(ctx context.Context, cmd *MapImportRequest) *MapImportResponse {
+	defer metrics.ObserveAPICommand(time.Now(), h.config.Protocol, "map_import")
+
+	resp := &MapImportResponse{}
+	snapshot := cmd.Snapshot
+	if snapshot == nil || snapshot.Channel == "" {
+		resp.Error = ErrorBadRequest
+		return resp
+	}
+	ch := snapshot.Channel
+	if !h.tenantAllowed(ctx, ch) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+	if h.config.UseOpenTelemetry {
+		span := trace.SpanFromContext(ctx)
+		span.SetAttributes(attribute.String("centrifugo.channel", ch))
+	}
+
+	_, _, chOpts, found, err := h.cfgContainer.ChannelOptions(ch)
+	if err != nil {
+		resp.Error = ErrorInternal
+		return resp
+	}
+	if !found {
+		resp.Error = ErrorUnknownChannel
+		return resp
+	}
+	if chOpts.SubscriptionType != "map" && chOpts.SubscriptionType != "map_clients" && chOpts.SubscriptionType != "map_users" {
+		log.Warn().Str("channel", ch).Str("subscription_type", chOpts.SubscriptionType).Msg("map_import called on non-map namespace")
+		resp.Error = ErrorBadRequest
+		return resp
+	}
+	for _, entry := range snapshot.Entries {
+		if entry.Score != 0 {
+			// Map brokers keep no entry scores, import would silently lose them.
+			log.Info().Str("channel", ch).Str("key", entry.Key).Msg("map_import entry score can't be preserved")
+			resp.Error = ErrorBadRequest
+			return resp
+		}
+	}
+
+	if h.mapSnapshotImporter != nil && snapshot.Epoch != "" {
+		entries := make([]*centrifuge.Publication, 0, len(snapshot.Entries))
+		for _, entry := range snapshot.Entries {
+			if entry.Removed {
+				continue
+			}
+			entries = append(entries, publicationFromMapEntry(entry))
+		}
+		pos := centrifuge.StreamPosition{Offset: snapshot.Offset, Epoch: snapshot.Epoch}
+		err = h.mapSnapshotImporter.ImportSnapshot(ctx, ch, pos, entries, cmd.Replace)
+		if err != nil {
+			if errors.Is(err, mapsnapshot.ErrChannelNotEmpty) {
+				resp.Error = ErrorConflict
+				return resp
+			}
+			log.Error().Err(err).Str("channel", ch).Msg("error in map import")
+			resp.Error = ErrorInternal
+			return resp
+		}
+		resp.Result = &MapImportResult{Offset: pos.Offset, Epoch: pos.Epoch, PositionPreserved: true}
+		return resp
+	}
+
+	pos, err := h.republishMapSnapshot(ctx, snapshot, cmd.Replace)
+	if err != nil {
+		if errors.Is(err, mapsnapshot.ErrChannelNotEmpty) {
+			resp.Error = ErrorConflict
+			return resp
+		}
+		log.Error().Err(err).Str("channel", ch).Msg("error in map import")
+		resp.Error = ErrorInternal
+		return resp
+	}
+	resp.Result = &MapImportResult{Offset: pos.Offset, Epoch: pos.Epoch}
+	return resp
+}
+
+// ScheduledList returns pending scheduled publications. Publications of channels
+// not owned by the calling tenant are skipped, so a page may contain fewer
+// publications than requested limit.
+func (h *Executor) ScheduledList(ctx context.Context, cmd *ScheduledListRequest) *ScheduledListResponse {
+	defer metrics.ObserveAPICommand(time.Now(), h.config.Protocol, "scheduled_list")
+
+	resp := &ScheduledListResponse{}
+
+	if h.scheduler == nil {
+		resp.Error = ErrorNotAvailable
+		return resp
+	}
+	if cmd.Channel != "" && !h.tenantAllowed(ctx, cmd.Channel) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+	if cmd.Limit < 0 {
+		resp.Error = ErrorBadRequest
+		return resp
+	}
+
+	items, cursor, err := h.scheduler.List(ctx, schedule.ListOptions{
+		Channel: cmd.Channel,
+		Limit:   int(cmd.Limit),
+		Cursor:  cmd.Cursor,
+	})
+	if err != nil {
+		if errors.Is(err, schedule.ErrInvalidCursor) {
+			resp.Error = ErrorBadRequest
+			return resp
+		}
+		log.Error().Err(err).Msg("error listing scheduled publications")
+		resp.Error = ErrorInternal
+		return resp
+	}
+
+	publications := make([]*ScheduledPublication, 0, len(items))
+	for _, item := range items {
+		if !h.tenantAllowed(ctx, item.Channels...) {
+			continue
+		}
+		pub, err := scheduledPublication(item)
+		if err != nil {
+			log.Error().Err(err).Str("id", item.ID).Msg("malformed scheduled publication")
+			continue
+		}
+		publications = append(publications, pub)
+	}
+	resp.Result = &ScheduledListResult{Publications: publications, NextCursor: cursor}
+	return resp
+}
+
+// ScheduledCancel cancels pending scheduled publication. Publications which are being
+// fired already can't be cancelled, not found error is returned for them.
+func (h *Executor) ScheduledCancel(ctx context.Context, cmd *ScheduledCancelRequest) *ScheduledCancelResponse {
+	defer metrics.ObserveAPICommand(time.Now(), h.config.Protocol, "scheduled_cancel")
+
+	resp := &ScheduledCancelResponse{}
+
+	if h.scheduler == nil {
+		resp.Error = ErrorNotAvailable
+		return resp
+	}
+	if cmd.Id == "" {
+		resp.Error = ErrorBadRequest
+		return resp
+	}
+
+	if _, ok := clientcontext.GetTenantFromContext(ctx); ok {
+		item, found, err := h.scheduler.Get(ctx, cmd.Id)
+		if err != nil {
+			log.Error().Err(err).Str("id", cmd.Id).Msg("error getting scheduled publication")
+			resp.Error = ErrorInternal
+			return resp
+		}
+		if !found {
+			resp.Error = ErrorNotFound
+			return resp
+		}
+		if !h.tenantAllowed(ctx, item.Channels...) {
+			resp.Error = ErrorPermissionDenied
+			return resp
+		}
+	}
+
+	cancelled, err := h.scheduler.Cancel(ctx, cmd.Id)
+	if err != nil {
+		log.Error().Err(err).Str("id", cmd.Id).Msg("error cancelling scheduled publication")
+		resp.Error = ErrorInternal
+		return resp
+	}
+	if !cancelled {
+		resp.Error = ErrorNotFound
+		return resp
+	}
+	resp.Result = &ScheduledCancelResult{}
+	return resp
+}
+
 func (h *Executor) SharedPollPublish(ctx context.Context, cmd *SharedPollPublishRequest) *SharedPollPublishResponse {
 	defer metrics.ObserveAPICommand(time.Now(), h.config.Protocol, "shared_poll_publish")
 
@@ -1234,6 +1812,12 @@ func (h *Executor) SharedPollPublish(ctx context.Context, cmd *SharedPollPublish
 	}
 
 	resp := &SharedPollPublishResponse{}
+
+	if !h.tenantAllowed(ctx, cmd.Channel) {
+		resp.Error = ErrorPermissionDenied
+		return resp
+	}
+
 	if ch == "" || cmd.Key == "" || cmd.Version == 0 {
 		resp.Error = ErrorBadRequest
 		return resp
@@ -10,6 +10,7 @@ import (
 	"github.com/centrifugal/centrifugo/v6/internal/config"
 	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
 	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+	"github.com/centrifugal/centrifugo/v6/internal/throttle"
 
 	"github.com/centrifugal/centrifuge"
 	"github.com/prometheus/client_golang/prometheus"
@@ -68,6 +69,29 @@ func TestPublishAPI(t *testing.T) {
 	require.Equal(t, ErrorUnknownChannel, resp.Error)
 }
 
+func TestPublishAPIThrottleDeferred(t *testing.T) {
+	node := nodeWithMemoryEngine()
+	cfg := config.DefaultConfig()
+	cfg.Channel.WithoutNamespace.Throttle.Enabled = true
+	cfg.Channel.WithoutNamespace.Throttle.Interval = configtypes.Duration(time.Hour)
+	cfgContainer, err := config.NewContainer(cfg)
+	require.NoError(t, err)
+
+	api := NewExecutor(node, cfgContainer, &testSurveyCaller{}, ExecutorConfig{Protocol: "test", UseOpenTelemetry: false})
+	throttler := throttle.New()
+	defer throttler.Close()
+	api.SetThrottler(throttler)
+
+	resp := api.Publish(context.Background(), &PublishRequest{Channel: "test", Data: []byte(`{}`)})
+	require.Nil(t, resp.Error)
+	require.False(t, resp.Result.Deferred)
+
+	resp = api.Publish(context.Background(), &PublishRequest{Channel: "test", Data: []byte(`{}`)})
+	require.Nil(t, resp.Error)
+	require.True(t, resp.Result.Deferred)
+	require.Zero(t, resp.Result.Offset)
+}
+
 func TestBroadcastAPI(t *testing.T) {
 	node := nodeWithMemoryEngine()
 	cfg := config.DefaultConfig()
@@ -294,6 +318,56 @@ func TestHistoryAPI(t *testing.T) {
 	require.Nil(t, resp.Error)
 }
 
+func TestHistoryAPICursor(t *testing.T) {
+	node := nodeWithMemoryEngine()
+	cfg := config.DefaultConfig()
+	cfg.Channel.WithoutNamespace.HistorySize = 10
+	cfg.Channel.WithoutNamespace.HistoryTTL = configtypes.Duration(time.Minute)
+	cfgContainer, err := config.NewContainer(cfg)
+	require.NoError(t, err)
+
+	api := NewExecutor(node, cfgContainer, &testSurveyCaller{}, ExecutorConfig{Protocol: "test", UseOpenTelemetry: false})
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		pubResp := api.Publish(ctx, &PublishRequest{Channel: "test", Data: []byte(`{}`)})
+		require.Nil(t, pubResp.Error)
+	}
+
+	resp := api.History(ctx, &HistoryRequest{Channel: "test", Cursor: "invalid", Limit: 2})
+	require.Equal(t, ErrorBadRequest, resp.Error)
+	resp = api.History(ctx, &HistoryRequest{Channel: "test", Cursor: "1:x", Since: &StreamPosition{}, Limit: 2})
+	require.Equal(t, ErrorBadRequest, resp.Error)
+	resp = api.History(ctx, &HistoryRequest{Channel: "test", FromTime: -1, Limit: 2})
+	require.Equal(t, ErrorBadRequest, resp.Error)
+
+	// Reverse pagination over all publications with cursor.
+	var offsets []uint64
+	var cursor string
+	for {
+		resp = api.History(ctx, &HistoryRequest{Channel: "test", Limit: 2, Reverse: true, Cursor: cursor})
+		require.Nil(t, resp.Error)
+		for _, pub := range resp.Result.Publications {
+			offsets = append(offsets, pub.Offset)
+		}
+		cursor = resp.Result.NextCursor
+		if cursor == "" {
+			break
+		}
+	}
+	require.Equal(t, []uint64{5, 4, 3, 2, 1}, offsets)
+
+	resp = api.History(ctx, &HistoryRequest{Channel: "test", Limit: 5})
+	require.Nil(t, resp.Error)
+	require.Len(t, resp.Result.Publications, 5)
+	require.Empty(t, resp.Result.NextCursor)
+
+	// Empty time range.
+	resp = api.History(ctx, &HistoryRequest{Channel: "test", Limit: 5, FromTime: 2, ToTime: 1})
+	require.Nil(t, resp.Error)
+	require.Empty(t, resp.Result.Publications)
+	require.Equal(t, uint64(5), resp.Result.Offset)
+}
+
 func TestHistoryRemoveAPI(t *testing.T) {
 	node := nodeWithMemoryEngine()
 	cfg := config.DefaultConfig()
@@ -568,6 +642,15 @@ func TestMapAPIs_RejectNonMapNamespaces(t *testing.T) {
 
 	clResp := api.MapClear(context.Background(), &MapClearRequest{Channel: "ns:test"})
 	require.Equal(t, ErrorBadRequest, clResp.Error)
+
+	exResp := api.MapExport(context.Background(), &MapExportRequest{Channel: "ns:test"})
+	require.Equal(t, ErrorBadRequest, exResp.Error)
+
+	imResp := api.MapImport(context.Background(), &MapImportRequest{Snapshot: &MapSnapshot{Channel: "ns:test"}})
+	require.Equal(t, ErrorBadRequest, imResp.Error)
+
+	imResp = api.MapImport(context.Background(), &MapImportRequest{})
+	require.Equal(t, ErrorBadRequest, imResp.Error)
 }
 
 // TestMapAPIs_RejectUnknownChannel ensures the map API endpoints return
@@ -601,4 +684,38 @@ func TestMapAPIs_RejectUnknownChannel(t *testing.T) {
 
 	clResp := api.MapClear(context.Background(), &MapClearRequest{Channel: "missing:test"})
 	require.Equal(t, ErrorUnknownChannel, clResp.Error)
+
+	exResp := api.MapExport(context.Background(), &MapExportRequest{Channel: "missing:test"})
+	require.Equal(t, ErrorUnknownChannel, exResp.Error)
+
+	imResp := api.MapImport(context.Background(), &MapImportRequest{Snapshot: &MapSnapshot{Channel: "missing:test"}})
+	require.Equal(t, ErrorUnknownChannel, imResp.Error)
+}
+
+// TestMapReadStateAPI_RejectsBadFilter ensures filter and sort expressions are
+// validated against namespace map indexes.
+func TestMapReadStateAPI_RejectsBadFilter(t *testing.T) {
+	node := nodeWithMemoryEngine()
+	defer func() { _ = node.Shutdown(context.Background()) }()
+
+	cfg := configWithNamespace("ns", "map")
+	cfg.Channel.Namespaces[0].Map.Indexes = configtypes.MapIndexes{
+		{Name: "status", Field: "status"},
+	}
+	cfgContainer, err := config.NewContainer(cfg)
+	require.NoError(t, err)
+
+	api := NewExecutor(node, cfgContainer, &testSurveyCaller{}, ExecutorConfig{Protocol: "test", UseOpenTelemetry: false})
+
+	resp := api.MapReadState(context.Background(), &MapReadStateRequest{Channel: "ns:test", Filter: `unknown = 1`})
+	require.Equal(t, ErrorBadRequest, resp.Error)
+
+	resp = api.MapReadState(context.Background(), &MapReadStateRequest{Channel: "ns:test", Sort: "status sideways"})
+	require.Equal(t, ErrorBadRequest, resp.Error)
+
+	resp = api.MapReadState(context.Background(), &MapReadStateRequest{Channel: "ns:test", Key: "k", Filter: `status = open`})
+	require.Equal(t, ErrorBadRequest, resp.Error)
+
+	resp = api.MapReadState(context.Background(), &MapReadStateRequest{Channel: "ns:test", Sort: "status", Cursor: "!!!", Limit: 10})
+	require.Equal(t, ErrorBadRequest, resp.Error)
 }
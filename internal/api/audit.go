@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/centrifugal/centrifugo/v6/internal/apiproto"
+	"github.com/centrifugal/centrifugo/v6/internal/audit"
+	"github.com/centrifugal/centrifugo/v6/internal/clientcontext"
+)
+
+// SetAuditor sets audit Logger to record executed API commands. Without Logger
+// commands are not recorded.
+func (h *Executor) SetAuditor(l *audit.Logger) {
+	h.auditor = l
+}
+
+type channelRequest interface {
+	GetChannel() string
+}
+
+type channelsRequest interface {
+	GetChannels() []string
+}
+
+type snapshotRequest interface {
+	GetSnapshot() *MapSnapshot
+}
+
+type userRequest interface {
+	GetUser() string
+}
+
+// audit records API command together with caller identity taken from context. Caller
+// source defaults to API protocol for requests authorized without identity.
+func (h *Executor) audit(ctx context.Context, method string, req any, respErr *Error) {
+	if h.auditor == nil || !h.auditor.Enabled(method) {
+		return
+	}
+	caller, _ := clientcontext.GetAPICallerFromContext(ctx)
+	if caller.Source == "" {
+		caller.Source = h.config.Protocol
+	}
+	if caller.Identity == "" {
+		caller.Identity = "anonymous"
+	}
+	r := audit.Record{
+		Source:   caller.Source,
+		Identity: caller.Identity,
+		Method:   method,
+	}
+	if v, ok := req.(channelRequest); ok && v.GetChannel() != "" {
+		r.Channels = []string{v.GetChannel()}
+	}
+	if v, ok := req.(snapshotRequest); ok && v.GetSnapshot().GetChannel() != "" {
+		r.Channels = []string{v.GetSnapshot().GetChannel()}
+	}
+	if v, ok := req.(channelsRequest); ok {
+		r.Channels = append(r.Channels, v.GetChannels()...)
+	}
+	if v, ok := req.(userRequest); ok && v.GetUser() != "" {
+		r.Users = []string{v.GetUser()}
+	}
+	if h.auditor.RecordParams() {
+		if params, err := json.Marshal(req); err == nil {
+			r.Params = params
+		}
+	}
+	if respErr != nil {
+		r.Error = &audit.Error{Code: respErr.Code, Message: respErr.Message}
+	}
+	h.auditor.Log(r)
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/centrifugal/centrifugo/v6/internal/apiproto"
+	"github.com/centrifugal/centrifugo/v6/internal/audit"
+	"github.com/centrifugal/centrifugo/v6/internal/clientcontext"
+	"github.com/centrifugal/centrifugo/v6/internal/config"
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+
+	"github.com/stretchr/testify/require"
+)
+
+// runTestAuditor runs audit Logger writing to file and returns function which stops
+// Logger and returns written records.
+func runTestAuditor(t *testing.T, cfg configtypes.Audit) (*audit.Logger, func() []audit.Record) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	cfg.Enabled = true
+	cfg.BufferSize = 100
+	cfg.BatchSize = 100
+	cfg.FlushInterval = configtypes.Duration(time.Hour)
+	cfg.File = configtypes.AuditFileSink{Enabled: true, Path: path, MaxSizeMB: 1}
+	l, err := audit.New("node", cfg)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = l.Run(ctx)
+	}()
+	return l, func() []audit.Record {
+		cancel()
+		<-done
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		defer func() { _ = f.Close() }()
+		var records []audit.Record
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var r audit.Record
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+			records = append(records, r)
+		}
+		return records
+	}
+}
+
+func TestExecutorAudit(t *testing.T) {
+	node := nodeWithMemoryEngine()
+	cfgContainer, err := config.NewContainer(config.DefaultConfig())
+	require.NoError(t, err)
+	executor := NewExecutor(node, cfgContainer, &testSurveyCaller{}, ExecutorConfig{Protocol: "http"})
+	auditor, stop := runTestAuditor(t, configtypes.Audit{
+		RecordParams:   true,
+		ExcludeMethods: []string{"info"},
+	})
+	executor.SetAuditor(auditor)
+
+	ctx := clientcontext.SetAPICallerToContext(context.Background(), clientcontext.APICaller{Identity: "api_key"})
+	publishReq := &PublishRequest{Channel: "test", Data: []byte(`{"secret":true}`)}
+	executor.audit(ctx, "publish", publishReq, executor.Publish(ctx, publishReq).Error)
+	disconnectReq := &DisconnectRequest{User: "42"}
+	executor.audit(context.Background(), "disconnect", disconnectReq, ErrorPermissionDenied)
+	executor.audit(ctx, "info", &InfoRequest{}, nil)
+
+	records := stop()
+	require.Len(t, records, 2)
+
+	require.Equal(t, "publish", records[0].Method)
+	require.Equal(t, "http", records[0].Source)
+	require.Equal(t, "api_key", records[0].Identity)
+	require.Equal(t, []string{"test"}, records[0].Channels)
+	require.True(t, records[0].Success)
+	require.JSONEq(t, `{"channel":"test","data":"[redacted]"}`, string(records[0].Params))
+
+	require.Equal(t, "disconnect", records[1].Method)
+	require.Equal(t, "anonymous", records[1].Identity)
+	require.Equal(t, []string{"42"}, records[1].Users)
+	require.False(t, records[1].Success)
+	require.Equal(t, ErrorPermissionDenied.Code, records[1].Error.Code)
+}
+
+func TestBatchAudit(t *testing.T) {
+	node := nodeWithMemoryEngine()
+	cfgContainer, err := config.NewContainer(config.DefaultConfig())
+	require.NoError(t, err)
+	executor := NewExecutor(node, cfgContainer, &testSurveyCaller{}, ExecutorConfig{Protocol: "grpc"})
+	auditor, stop := runTestAuditor(t, configtypes.Audit{})
+	executor.SetAuditor(auditor)
+
+	resp := executor.Batch(context.Background(), &BatchRequest{
+		Commands: []*Command{
+			{Broadcast: &BroadcastRequest{Channels: []string{"a", "b"}, Data: []byte(`{}`)}},
+			{Unsubscribe: &UnsubscribeRequest{User: "42", Channel: "a"}},
+		},
+	})
+	require.Len(t, resp.Replies, 2)
+
+	records := stop()
+	require.Len(t, records, 2)
+	methods := map[string]audit.Record{}
+	for _, r := range records {
+		require.Equal(t, "grpc", r.Source)
+		require.Nil(t, r.Params)
+		methods[r.Method] = r
+	}
+	require.Equal(t, []string{"a", "b"}, methods["broadcast"].Channels)
+	require.Equal(t, []string{"a"}, methods["unsubscribe"].Channels)
+	require.Equal(t, []string{"42"}, methods["unsubscribe"].Users)
+}
+
+func TestMapImportAudit(t *testing.T) {
+	cfgContainer, err := config.NewContainer(config.DefaultConfig())
+	require.NoError(t, err)
+	executor := NewExecutor(nodeWithMemoryEngine(), cfgContainer, &testSurveyCaller{}, ExecutorConfig{Protocol: "http"})
+	auditor, stop := runTestAuditor(t, configtypes.Audit{})
+	executor.SetAuditor(auditor)
+
+	ctx := clientcontext.SetAPICallerToContext(context.Background(), clientcontext.APICaller{Identity: "api_key:billing"})
+	executor.audit(ctx, "map_import", &MapImportRequest{Snapshot: &MapSnapshot{Channel: "ns:test"}}, nil)
+
+	records := stop()
+	require.Len(t, records, 1)
+	require.Equal(t, "api_key:billing", records[0].Identity)
+	require.Equal(t, []string{"ns:test"}, records[0].Channels)
+}
@@ -51,6 +51,7 @@ func NewDispatcher(handler *ConsumingHandler) *Dispatcher {
 
 func (d *Dispatcher) Publish(ctx context.Context, req *apiproto.PublishRequest) error {
 	resp := d.handler.api.Publish(ctx, req)
+	d.handler.api.audit(ctx, "publish", req, resp.Error)
 	if d.handler.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -66,6 +67,7 @@ func (d *Dispatcher) Publish(ctx context.Context, req *apiproto.PublishRequest)
 
 func (d *Dispatcher) Broadcast(ctx context.Context, req *apiproto.BroadcastRequest) error {
 	resp := d.handler.api.Broadcast(ctx, req)
+	d.handler.api.audit(ctx, "broadcast", req, resp.Error)
 	if d.handler.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -18,6 +18,7 @@ func (h *ConsumingHandler) handlePublish(ctx context.Context, data []byte) (*Pub
 		return nil, ErrInvalidData
 	}
 	resp := h.api.Publish(ctx, req)
+	h.api.audit(ctx, "publish", req, resp.Error)
 	if h.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -35,6 +36,7 @@ func (h *ConsumingHandler) handleBroadcast(ctx context.Context, data []byte) (*B
 		return nil, ErrInvalidData
 	}
 	resp := h.api.Broadcast(ctx, req)
+	h.api.audit(ctx, "broadcast", req, resp.Error)
 	if h.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -52,6 +54,7 @@ func (h *ConsumingHandler) handleSubscribe(ctx context.Context, data []byte) (*S
 		return nil, ErrInvalidData
 	}
 	resp := h.api.Subscribe(ctx, req)
+	h.api.audit(ctx, "subscribe", req, resp.Error)
 	if h.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -69,6 +72,7 @@ func (h *ConsumingHandler) handleUnsubscribe(ctx context.Context, data []byte) (
 		return nil, ErrInvalidData
 	}
 	resp := h.api.Unsubscribe(ctx, req)
+	h.api.audit(ctx, "unsubscribe", req, resp.Error)
 	if h.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -86,6 +90,7 @@ func (h *ConsumingHandler) handleDisconnect(ctx context.Context, data []byte) (*
 		return nil, ErrInvalidData
 	}
 	resp := h.api.Disconnect(ctx, req)
+	h.api.audit(ctx, "disconnect", req, resp.Error)
 	if h.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -103,6 +108,7 @@ func (h *ConsumingHandler) handleHistoryRemove(ctx context.Context, data []byte)
 		return nil, ErrInvalidData
 	}
 	resp := h.api.HistoryRemove(ctx, req)
+	h.api.audit(ctx, "history_remove", req, resp.Error)
 	if h.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -120,6 +126,7 @@ func (h *ConsumingHandler) handleRefresh(ctx context.Context, data []byte) (*Ref
 		return nil, ErrInvalidData
 	}
 	resp := h.api.Refresh(ctx, req)
+	h.api.audit(ctx, "refresh", req, resp.Error)
 	if h.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -2,9 +2,12 @@ package api
 
 import (
 	"context"
-	"crypto/subtle"
+	"strings"
+	"sync/atomic"
 
 	. "github.com/centrifugal/centrifugo/v6/internal/apiproto"
+	"github.com/centrifugal/centrifugo/v6/internal/clientcontext"
+	"github.com/centrifugal/centrifugo/v6/internal/tools"
 
 	"github.com/centrifugal/centrifuge"
 	"google.golang.org/grpc"
@@ -13,24 +16,62 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-func authorize(ctx context.Context, key []byte) error {
-	if md, ok := metadata.FromIncomingContext(ctx); ok {
-		if len(md["authorization"]) > 0 && subtle.ConstantTimeCompare([]byte(md["authorization"][0]), key) == 1 {
-			return nil
+// GRPCKeyAuth allows to set simple authentication based on string key from configuration.
+// Client should provide per RPC credentials: set authorization key to metadata with value
+// `apikey <KEY>`. Besides main key named keys are accepted, name of matched key is put
+// to caller identity. Keys may be changed with Reload.
+type GRPCKeyAuth struct {
+	keys atomic.Pointer[grpcKeys]
+}
+
+type grpcKeys struct {
+	key       string
+	namedKeys map[string]string
+}
+
+// NewGRPCKeyAuth creates GRPCKeyAuth. Named keys are key names keyed by API key.
+func NewGRPCKeyAuth(key string, namedKeys map[string]string) *GRPCKeyAuth {
+	a := &GRPCKeyAuth{}
+	a.Reload(key, namedKeys)
+	return a
+}
+
+// Reload replaces keys used for authorization, e.g. after secrets rotation. Empty
+// keys are ignored since authentication can't be turned off on the fly.
+func (a *GRPCKeyAuth) Reload(key string, namedKeys map[string]string) {
+	if key == "" && len(namedKeys) == 0 {
+		return
+	}
+	a.keys.Store(&grpcKeys{key: key, namedKeys: namedKeys})
+}
+
+// authorize returns caller identity of matched key.
+func (a *GRPCKeyAuth) authorize(ctx context.Context) (string, error) {
+	keys := a.keys.Load()
+	if md, ok := metadata.FromIncomingContext(ctx); ok && keys != nil && len(md["authorization"]) > 0 {
+		parts := strings.Fields(md["authorization"][0])
+		if len(parts) == 2 && parts[0] == "apikey" {
+			if keys.key != "" && tools.SecureCompareString(keys.key, parts[1]) {
+				return "api_key", nil
+			}
+			for namedKey, name := range keys.namedKeys {
+				if tools.SecureCompareString(namedKey, parts[1]) {
+					return "api_key:" + name, nil
+				}
+			}
 		}
 	}
-	return status.Error(codes.Unauthenticated, "unauthenticated")
+	return "", status.Error(codes.Unauthenticated, "unauthenticated")
 }
 
-// GRPCKeyAuth allows to set simple authentication based on string key from configuration.
-// Client should provide per RPC credentials: set authorization key to metadata with value
-// `apikey <KEY>`.
-func GRPCKeyAuth(key string) grpc.ServerOption {
-	authKey := []byte("apikey " + key)
+// ServerOption returns GRPC server option with authorization interceptor.
+func (a *GRPCKeyAuth) ServerOption() grpc.ServerOption {
 	return grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
-		if err := authorize(ctx, authKey); err != nil {
+		identity, err := a.authorize(ctx)
+		if err != nil {
 			return nil, err
 		}
+		ctx = clientcontext.SetAPICallerToContext(ctx, clientcontext.APICaller{Identity: identity})
 		return handler(ctx, req)
 	})
 }
@@ -22,6 +22,7 @@ func (s *grpcAPIService) Batch(ctx context.Context, req *BatchRequest) (*BatchRe
 // Publish ...
 func (s *grpcAPIService) Publish(ctx context.Context, req *PublishRequest) (*PublishResponse, error) {
 	resp := s.api.Publish(ctx, req)
+	s.api.audit(ctx, "publish", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -41,6 +42,7 @@ func (s *grpcAPIService) Publish(ctx context.Context, req *PublishRequest) (*Pub
 // Broadcast ...
 func (s *grpcAPIService) Broadcast(ctx context.Context, req *BroadcastRequest) (*BroadcastResponse, error) {
 	resp := s.api.Broadcast(ctx, req)
+	s.api.audit(ctx, "broadcast", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -60,6 +62,7 @@ func (s *grpcAPIService) Broadcast(ctx context.Context, req *BroadcastRequest) (
 // Subscribe ...
 func (s *grpcAPIService) Subscribe(ctx context.Context, req *SubscribeRequest) (*SubscribeResponse, error) {
 	resp := s.api.Subscribe(ctx, req)
+	s.api.audit(ctx, "subscribe", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -79,6 +82,7 @@ func (s *grpcAPIService) Subscribe(ctx context.Context, req *SubscribeRequest) (
 // Unsubscribe ...
 func (s *grpcAPIService) Unsubscribe(ctx context.Context, req *UnsubscribeRequest) (*UnsubscribeResponse, error) {
 	resp := s.api.Unsubscribe(ctx, req)
+	s.api.audit(ctx, "unsubscribe", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -98,6 +102,7 @@ func (s *grpcAPIService) Unsubscribe(ctx context.Context, req *UnsubscribeReques
 // Disconnect ...
 func (s *grpcAPIService) Disconnect(ctx context.Context, req *DisconnectRequest) (*DisconnectResponse, error) {
 	resp := s.api.Disconnect(ctx, req)
+	s.api.audit(ctx, "disconnect", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -117,6 +122,7 @@ func (s *grpcAPIService) Disconnect(ctx context.Context, req *DisconnectRequest)
 // Presence ...
 func (s *grpcAPIService) Presence(ctx context.Context, req *PresenceRequest) (*PresenceResponse, error) {
 	resp := s.api.Presence(ctx, req)
+	s.api.audit(ctx, "presence", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -136,6 +142,7 @@ func (s *grpcAPIService) Presence(ctx context.Context, req *PresenceRequest) (*P
 // PresenceStats ...
 func (s *grpcAPIService) PresenceStats(ctx context.Context, req *PresenceStatsRequest) (*PresenceStatsResponse, error) {
 	resp := s.api.PresenceStats(ctx, req)
+	s.api.audit(ctx, "presence_stats", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -155,6 +162,7 @@ func (s *grpcAPIService) PresenceStats(ctx context.Context, req *PresenceStatsRe
 // History ...
 func (s *grpcAPIService) History(ctx context.Context, req *HistoryRequest) (*HistoryResponse, error) {
 	resp := s.api.History(ctx, req)
+	s.api.audit(ctx, "history", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -174,6 +182,7 @@ func (s *grpcAPIService) History(ctx context.Context, req *HistoryRequest) (*His
 // HistoryRemove ...
 func (s *grpcAPIService) HistoryRemove(ctx context.Context, req *HistoryRemoveRequest) (*HistoryRemoveResponse, error) {
 	resp := s.api.HistoryRemove(ctx, req)
+	s.api.audit(ctx, "history_remove", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -193,6 +202,7 @@ func (s *grpcAPIService) HistoryRemove(ctx context.Context, req *HistoryRemoveRe
 // Info ...
 func (s *grpcAPIService) Info(ctx context.Context, req *InfoRequest) (*InfoResponse, error) {
 	resp := s.api.Info(ctx, req)
+	s.api.audit(ctx, "info", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -212,6 +222,7 @@ func (s *grpcAPIService) Info(ctx context.Context, req *InfoRequest) (*InfoRespo
 // RPC ...
 func (s *grpcAPIService) RPC(ctx context.Context, req *RPCRequest) (*RPCResponse, error) {
 	resp := s.api.RPC(ctx, req)
+	s.api.audit(ctx, "rpc", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -231,6 +242,7 @@ func (s *grpcAPIService) RPC(ctx context.Context, req *RPCRequest) (*RPCResponse
 // Refresh ...
 func (s *grpcAPIService) Refresh(ctx context.Context, req *RefreshRequest) (*RefreshResponse, error) {
 	resp := s.api.Refresh(ctx, req)
+	s.api.audit(ctx, "refresh", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -250,6 +262,7 @@ func (s *grpcAPIService) Refresh(ctx context.Context, req *RefreshRequest) (*Ref
 // Channels ...
 func (s *grpcAPIService) Channels(ctx context.Context, req *ChannelsRequest) (*ChannelsResponse, error) {
 	resp := s.api.Channels(ctx, req)
+	s.api.audit(ctx, "channels", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -269,6 +282,7 @@ func (s *grpcAPIService) Channels(ctx context.Context, req *ChannelsRequest) (*C
 // MapPublish ...
 func (s *grpcAPIService) MapPublish(ctx context.Context, req *MapPublishRequest) (*MapPublishResponse, error) {
 	resp := s.api.MapPublish(ctx, req)
+	s.api.audit(ctx, "map_publish", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -288,6 +302,7 @@ func (s *grpcAPIService) MapPublish(ctx context.Context, req *MapPublishRequest)
 // MapRemove ...
 func (s *grpcAPIService) MapRemove(ctx context.Context, req *MapRemoveRequest) (*MapRemoveResponse, error) {
 	resp := s.api.MapRemove(ctx, req)
+	s.api.audit(ctx, "map_remove", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -307,6 +322,7 @@ func (s *grpcAPIService) MapRemove(ctx context.Context, req *MapRemoveRequest) (
 // MapReadState ...
 func (s *grpcAPIService) MapReadState(ctx context.Context, req *MapReadStateRequest) (*MapReadStateResponse, error) {
 	resp := s.api.MapReadState(ctx, req)
+	s.api.audit(ctx, "map_read_state", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -326,6 +342,7 @@ func (s *grpcAPIService) MapReadState(ctx context.Context, req *MapReadStateRequ
 // MapReadStream ...
 func (s *grpcAPIService) MapReadStream(ctx context.Context, req *MapReadStreamRequest) (*MapReadStreamResponse, error) {
 	resp := s.api.MapReadStream(ctx, req)
+	s.api.audit(ctx, "map_read_stream", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -345,6 +362,7 @@ func (s *grpcAPIService) MapReadStream(ctx context.Context, req *MapReadStreamRe
 // MapStats ...
 func (s *grpcAPIService) MapStats(ctx context.Context, req *MapStatsRequest) (*MapStatsResponse, error) {
 	resp := s.api.MapStats(ctx, req)
+	s.api.audit(ctx, "map_stats", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -364,6 +382,7 @@ func (s *grpcAPIService) MapStats(ctx context.Context, req *MapStatsRequest) (*M
 // MapClear ...
 func (s *grpcAPIService) MapClear(ctx context.Context, req *MapClearRequest) (*MapClearResponse, error) {
 	resp := s.api.MapClear(ctx, req)
+	s.api.audit(ctx, "map_clear", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -383,6 +402,7 @@ func (s *grpcAPIService) MapClear(ctx context.Context, req *MapClearRequest) (*M
 // SharedPollPublish ...
 func (s *grpcAPIService) SharedPollPublish(ctx context.Context, req *SharedPollPublishRequest) (*SharedPollPublishResponse, error) {
 	resp := s.api.SharedPollPublish(ctx, req)
+	s.api.audit(ctx, "shared_poll_publish", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -398,3 +418,83 @@ func (s *grpcAPIService) SharedPollPublish(ctx context.Context, req *SharedPollP
 	}
 	return resp, nil
 }
+
+// MapExport ...
+func (s *grpcAPIService) MapExport(ctx context.Context, req *MapExportRequest) (*MapExportResponse, error) {
+	resp := s.api.MapExport(ctx, req)
+	s.api.audit(ctx, "map_export", req, resp.Error)
+	if s.config.UseOpenTelemetry && resp.Error != nil {
+		span := trace.SpanFromContext(ctx)
+		span.SetStatus(codes.Error, resp.Error.Error())
+	}
+	if resp.Error != nil && s.useTransportErrorMode(ctx) {
+		metrics.IncAPIError(s.api.config.Protocol, "map_export", resp.Error.Code)
+		statusCode := MapErrorToGRPCCode(resp.Error)
+		transportError, _ := status.New(statusCode, resp.Error.Message).WithDetails(resp.Error)
+		return nil, transportError.Err()
+	}
+	if resp.Error != nil {
+		metrics.IncAPIError(s.api.config.Protocol, "map_export", resp.Error.Code)
+	}
+	return resp, nil
+}
+
+// MapImport ...
+func (s *grpcAPIService) MapImport(ctx context.Context, req *MapImportRequest) (*MapImportResponse, error) {
+	resp := s.api.MapImport(ctx, req)
+	s.api.audit(ctx, "map_import", req, resp.Error)
+	if s.config.UseOpenTelemetry && resp.Error != nil {
+		span := trace.SpanFromContext(ctx)
+		span.SetStatus(codes.Error, resp.Error.Error())
+	}
+	if resp.Error != nil && s.useTransportErrorMode(ctx) {
+		metrics.IncAPIError(s.api.config.Protocol, "map_import", resp.Error.Code)
+		statusCode := MapErrorToGRPCCode(resp.Error)
+		transportError, _ := status.New(statusCode, resp.Error.Message).WithDetails(resp.Error)
+		return nil, transportError.Err()
+	}
+	if resp.Error != nil {
+		metrics.IncAPIError(s.api.config.Protocol, "map_import", resp.Error.Code)
+	}
+	return resp, nil
+}
+
+// ScheduledList ...
+func (s *grpcAPIService) ScheduledList(ctx context.Context, req *ScheduledListRequest) (*ScheduledListResponse, error) {
+	resp := s.api.ScheduledList(ctx, req)
+	s.api.audit(ctx, "scheduled_list", req, resp.Error)
+	if s.config.UseOpenTelemetry && resp.Error != nil {
+		span := trace.SpanFromContext(ctx)
+		span.SetStatus(codes.Error, resp.Error.Error())
+	}
+	if resp.Error != nil && s.useTransportErrorMode(ctx) {
+		metrics.IncAPIError(s.api.config.Protocol, "scheduled_list", resp.Error.Code)
+		statusCode := MapErrorToGRPCCode(resp.Error)
+		transportError, _ := status.New(statusCode, resp.Error.Message).WithDetails(resp.Error)
+		return nil, transportError.Err()
+	}
+	if resp.Error != nil {
+		metrics.IncAPIError(s.api.config.Protocol, "scheduled_list", resp.Error.Code)
+	}
+	return resp, nil
+}
+
+// ScheduledCancel ...
+func (s *grpcAPIService) ScheduledCancel(ctx context.Context, req *ScheduledCancelRequest) (*ScheduledCancelResponse, error) {
+	resp := s.api.ScheduledCancel(ctx, req)
+	s.api.audit(ctx, "scheduled_cancel", req, resp.Error)
+	if s.config.UseOpenTelemetry && resp.Error != nil {
+		span := trace.SpanFromContext(ctx)
+		span.SetStatus(codes.Error, resp.Error.Error())
+	}
+	if resp.Error != nil && s.useTransportErrorMode(ctx) {
+		metrics.IncAPIError(s.api.config.Protocol, "scheduled_cancel", resp.Error.Code)
+		statusCode := MapErrorToGRPCCode(resp.Error)
+		transportError, _ := status.New(statusCode, resp.Error.Message).WithDetails(resp.Error)
+		return nil, transportError.Err()
+	}
+	if resp.Error != nil {
+		metrics.IncAPIError(s.api.config.Protocol, "scheduled_cancel", resp.Error.Code)
+	}
+	return resp, nil
+}
@@ -9,19 +9,20 @@ import (
 )
 
 func TestGRPC_Authorize_Unauthenticated(t *testing.T) {
-	err := authorize(context.Background(), []byte("apikey xxx"))
+	auth := NewGRPCKeyAuth("xxx", nil)
+	_, err := auth.authorize(context.Background())
 	require.Error(t, err)
 
 	md := metadata.New(map[string]string{})
 	ctx := metadata.NewIncomingContext(context.Background(), md)
-	err = authorize(ctx, []byte("apikey xxx"))
+	_, err = auth.authorize(ctx)
 	require.Error(t, err)
 
 	md = metadata.New(map[string]string{
 		"authorization": "apikey yyy",
 	})
 	ctx = metadata.NewIncomingContext(context.Background(), md)
-	err = authorize(ctx, []byte("apikey xxx"))
+	_, err = auth.authorize(ctx)
 	require.Error(t, err)
 }
 
@@ -30,6 +31,41 @@ func TestGRPC_Authorize_OK(t *testing.T) {
 		"authorization": "apikey xxx",
 	})
 	ctx := metadata.NewIncomingContext(context.Background(), md)
-	err := authorize(ctx, []byte("apikey xxx"))
+	identity, err := NewGRPCKeyAuth("xxx", nil).authorize(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "api_key", identity)
+}
+
+func TestGRPC_Authorize_NamedKey(t *testing.T) {
+	auth := NewGRPCKeyAuth("", map[string]string{"billing-key": "billing"})
+	md := metadata.New(map[string]string{
+		"authorization": "apikey billing-key",
+	})
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	identity, err := auth.authorize(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "api_key:billing", identity)
+
+	// Empty main key must not match.
+	md = metadata.New(map[string]string{
+		"authorization": "apikey ",
+	})
+	ctx = metadata.NewIncomingContext(context.Background(), md)
+	_, err = auth.authorize(ctx)
+	require.Error(t, err)
+}
+
+func TestGRPC_Authorize_Reload(t *testing.T) {
+	auth := NewGRPCKeyAuth("old", nil)
+	auth.Reload("new", nil)
+	md := metadata.New(map[string]string{
+		"authorization": "apikey old",
+	})
+	_, err := auth.authorize(metadata.NewIncomingContext(context.Background(), md))
+	require.Error(t, err)
+	md = metadata.New(map[string]string{
+		"authorization": "apikey new",
+	})
+	_, err = auth.authorize(metadata.NewIncomingContext(context.Background(), md))
 	require.NoError(t, err)
 }
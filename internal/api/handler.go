@@ -61,6 +61,10 @@ func (s *Handler) Routes() map[string]http.HandlerFunc {
 		"/map_stats":           s.handleMapStats,
 		"/map_clear":           s.handleMapClear,
 		"/shared_poll_publish": s.handleSharedPollPublish,
+		"/map_export":          s.handleMapExport,
+		"/map_import":          s.handleMapImport,
+		"/scheduled_list":      s.handleScheduledList,
+		"/scheduled_cancel":    s.handleScheduledCancel,
 	}
 }
 
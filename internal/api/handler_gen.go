@@ -56,6 +56,7 @@ func (s *Handler) handlePublish(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := s.api.Publish(r.Context(), req)
+	s.api.audit(r.Context(), "publish", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(r.Context())
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -98,6 +99,7 @@ func (s *Handler) handleBroadcast(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := s.api.Broadcast(r.Context(), req)
+	s.api.audit(r.Context(), "broadcast", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(r.Context())
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -140,6 +142,7 @@ func (s *Handler) handleSubscribe(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := s.api.Subscribe(r.Context(), req)
+	s.api.audit(r.Context(), "subscribe", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(r.Context())
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -182,6 +185,7 @@ func (s *Handler) handleUnsubscribe(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := s.api.Unsubscribe(r.Context(), req)
+	s.api.audit(r.Context(), "unsubscribe", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(r.Context())
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -224,6 +228,7 @@ func (s *Handler) handleDisconnect(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := s.api.Disconnect(r.Context(), req)
+	s.api.audit(r.Context(), "disconnect", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(r.Context())
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -266,6 +271,7 @@ func (s *Handler) handlePresence(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := s.api.Presence(r.Context(), req)
+	s.api.audit(r.Context(), "presence", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(r.Context())
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -308,6 +314,7 @@ func (s *Handler) handlePresenceStats(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := s.api.PresenceStats(r.Context(), req)
+	s.api.audit(r.Context(), "presence_stats", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(r.Context())
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -350,6 +357,7 @@ func (s *Handler) handleHistory(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := s.api.History(r.Context(), req)
+	s.api.audit(r.Context(), "history", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(r.Context())
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -392,6 +400,7 @@ func (s *Handler) handleHistoryRemove(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := s.api.HistoryRemove(r.Context(), req)
+	s.api.audit(r.Context(), "history_remove", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(r.Context())
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -434,6 +443,7 @@ func (s *Handler) handleInfo(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := s.api.Info(r.Context(), req)
+	s.api.audit(r.Context(), "info", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(r.Context())
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -476,6 +486,7 @@ func (s *Handler) handleRPC(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := s.api.RPC(r.Context(), req)
+	s.api.audit(r.Context(), "rpc", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(r.Context())
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -518,6 +529,7 @@ func (s *Handler) handleRefresh(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := s.api.Refresh(r.Context(), req)
+	s.api.audit(r.Context(), "refresh", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(r.Context())
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -560,6 +572,7 @@ func (s *Handler) handleChannels(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := s.api.Channels(r.Context(), req)
+	s.api.audit(r.Context(), "channels", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(r.Context())
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -602,6 +615,7 @@ func (s *Handler) handleMapPublish(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := s.api.MapPublish(r.Context(), req)
+	s.api.audit(r.Context(), "map_publish", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(r.Context())
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -644,6 +658,7 @@ func (s *Handler) handleMapRemove(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := s.api.MapRemove(r.Context(), req)
+	s.api.audit(r.Context(), "map_remove", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(r.Context())
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -686,6 +701,7 @@ func (s *Handler) handleMapReadState(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := s.api.MapReadState(r.Context(), req)
+	s.api.audit(r.Context(), "map_read_state", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(r.Context())
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -728,6 +744,7 @@ func (s *Handler) handleMapReadStream(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := s.api.MapReadStream(r.Context(), req)
+	s.api.audit(r.Context(), "map_read_stream", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(r.Context())
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -770,6 +787,7 @@ func (s *Handler) handleMapStats(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := s.api.MapStats(r.Context(), req)
+	s.api.audit(r.Context(), "map_stats", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(r.Context())
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -812,6 +830,7 @@ func (s *Handler) handleMapClear(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := s.api.MapClear(r.Context(), req)
+	s.api.audit(r.Context(), "map_clear", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(r.Context())
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -854,6 +873,7 @@ func (s *Handler) handleSharedPollPublish(w http.ResponseWriter, r *http.Request
 	}
 
 	resp := s.api.SharedPollPublish(r.Context(), req)
+	s.api.audit(r.Context(), "shared_poll_publish", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(r.Context())
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -879,3 +899,175 @@ func (s *Handler) handleSharedPollPublish(w http.ResponseWriter, r *http.Request
 
 	s.writeJson(w, data)
 }
+
+func (s *Handler) handleMapExport(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		metrics.IncAPIErrorStringCode(s.api.config.Protocol, "map_export", "read_body")
+		s.handleReadDataError(r, w, err)
+		return
+	}
+
+	req, err := requestDecoder.DecodeMapExport(data)
+	if err != nil {
+		metrics.IncAPIErrorStringCode(s.api.config.Protocol, "map_export", "unmarshal")
+		s.handleUnmarshalError(r, w, err)
+		return
+	}
+
+	resp := s.api.MapExport(r.Context(), req)
+	s.api.audit(r.Context(), "map_export", req, resp.Error)
+	if s.config.UseOpenTelemetry && resp.Error != nil {
+		span := trace.SpanFromContext(r.Context())
+		span.SetStatus(codes.Error, resp.Error.Error())
+	}
+
+	if resp.Error != nil && s.useTransportErrorMode(r) {
+		metrics.IncAPIError(s.api.config.Protocol, "map_export", resp.Error.Code)
+		statusCode := MapErrorToHTTPCode(resp.Error)
+		data, _ = EncodeError(resp.Error)
+		s.writeJsonCustomStatus(w, statusCode, data)
+		return
+	}
+
+	data, err = responseEncoder.EncodeMapExport(resp)
+	if err != nil {
+		metrics.IncAPIErrorStringCode(s.api.config.Protocol, "map_export", "marshal")
+		s.handleMarshalError(r, w, err)
+		return
+	}
+	if resp.Error != nil {
+		metrics.IncAPIError(s.api.config.Protocol, "map_export", resp.Error.Code)
+	}
+
+	s.writeJson(w, data)
+}
+
+func (s *Handler) handleMapImport(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		metrics.IncAPIErrorStringCode(s.api.config.Protocol, "map_import", "read_body")
+		s.handleReadDataError(r, w, err)
+		return
+	}
+
+	req, err := requestDecoder.DecodeMapImport(data)
+	if err != nil {
+		metrics.IncAPIErrorStringCode(s.api.config.Protocol, "map_import", "unmarshal")
+		s.handleUnmarshalError(r, w, err)
+		return
+	}
+
+	resp := s.api.MapImport(r.Context(), req)
+	s.api.audit(r.Context(), "map_import", req, resp.Error)
+	if s.config.UseOpenTelemetry && resp.Error != nil {
+		span := trace.SpanFromContext(r.Context())
+		span.SetStatus(codes.Error, resp.Error.Error())
+	}
+
+	if resp.Error != nil && s.useTransportErrorMode(r) {
+		metrics.IncAPIError(s.api.config.Protocol, "map_import", resp.Error.Code)
+		statusCode := MapErrorToHTTPCode(resp.Error)
+		data, _ = EncodeError(resp.Error)
+		s.writeJsonCustomStatus(w, statusCode, data)
+		return
+	}
+
+	data, err = responseEncoder.EncodeMapImport(resp)
+	if err != nil {
+		metrics.IncAPIErrorStringCode(s.api.config.Protocol, "map_import", "marshal")
+		s.handleMarshalError(r, w, err)
+		return
+	}
+	if resp.Error != nil {
+		metrics.IncAPIError(s.api.config.Protocol, "map_import", resp.Error.Code)
+	}
+
+	s.writeJson(w, data)
+}
+
+func (s *Handler) handleScheduledList(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		metrics.IncAPIErrorStringCode(s.api.config.Protocol, "scheduled_list", "read_body")
+		s.handleReadDataError(r, w, err)
+		return
+	}
+
+	req, err := requestDecoder.DecodeScheduledList(data)
+	if err != nil {
+		metrics.IncAPIErrorStringCode(s.api.config.Protocol, "scheduled_list", "unmarshal")
+		s.handleUnmarshalError(r, w, err)
+		return
+	}
+
+	resp := s.api.ScheduledList(r.Context(), req)
+	s.api.audit(r.Context(), "scheduled_list", req, resp.Error)
+	if s.config.UseOpenTelemetry && resp.Error != nil {
+		span := trace.SpanFromContext(r.Context())
+		span.SetStatus(codes.Error, resp.Error.Error())
+	}
+
+	if resp.Error != nil && s.useTransportErrorMode(r) {
+		metrics.IncAPIError(s.api.config.Protocol, "scheduled_list", resp.Error.Code)
+		statusCode := MapErrorToHTTPCode(resp.Error)
+		data, _ = EncodeError(resp.Error)
+		s.writeJsonCustomStatus(w, statusCode, data)
+		return
+	}
+
+	data, err = responseEncoder.EncodeScheduledList(resp)
+	if err != nil {
+		metrics.IncAPIErrorStringCode(s.api.config.Protocol, "scheduled_list", "marshal")
+		s.handleMarshalError(r, w, err)
+		return
+	}
+	if resp.Error != nil {
+		metrics.IncAPIError(s.api.config.Protocol, "scheduled_list", resp.Error.Code)
+	}
+
+	s.writeJson(w, data)
+}
+
+func (s *Handler) handleScheduledCancel(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		metrics.IncAPIErrorStringCode(s.api.config.Protocol, "scheduled_cancel", "read_body")
+		s.handleReadDataError(r, w, err)
+		return
+	}
+
+	req, err := requestDecoder.DecodeScheduledCancel(data)
+	if err != nil {
+		metrics.IncAPIErrorStringCode(s.api.config.Protocol, "scheduled_cancel", "unmarshal")
+		s.handleUnmarshalError(r, w, err)
+		return
+	}
+
+	resp := s.api.ScheduledCancel(r.Context(), req)
+	s.api.audit(r.Context(), "scheduled_cancel", req, resp.Error)
+	if s.config.UseOpenTelemetry && resp.Error != nil {
+		span := trace.SpanFromContext(r.Context())
+		span.SetStatus(codes.Error, resp.Error.Error())
+	}
+
+	if resp.Error != nil && s.useTransportErrorMode(r) {
+		metrics.IncAPIError(s.api.config.Protocol, "scheduled_cancel", resp.Error.Code)
+		statusCode := MapErrorToHTTPCode(resp.Error)
+		data, _ = EncodeError(resp.Error)
+		s.writeJsonCustomStatus(w, statusCode, data)
+		return
+	}
+
+	data, err = responseEncoder.EncodeScheduledCancel(resp)
+	if err != nil {
+		metrics.IncAPIErrorStringCode(s.api.config.Protocol, "scheduled_cancel", "marshal")
+		s.handleMarshalError(r, w, err)
+		return
+	}
+	if resp.Error != nil {
+		metrics.IncAPIError(s.api.config.Protocol, "scheduled_cancel", resp.Error.Code)
+	}
+
+	s.writeJson(w, data)
+}
@@ -394,6 +394,7 @@ func (s *Handler) handleAPICommand(ctx context.Context, cmd *LegacyCommand) (*Le
 			return rep, nil
 		}
 		resp := s.api.Publish(ctx, cmd)
+		s.api.audit(ctx, "publish", cmd, resp.Error)
 		if resp.Error != nil {
 			rep.Error = resp.Error
 		} else {
@@ -412,6 +413,7 @@ func (s *Handler) handleAPICommand(ctx context.Context, cmd *LegacyCommand) (*Le
 			return rep, nil
 		}
 		resp := s.api.Broadcast(ctx, cmd)
+		s.api.audit(ctx, "broadcast", cmd, resp.Error)
 		if resp.Error != nil {
 			rep.Error = resp.Error
 		} else {
@@ -430,6 +432,7 @@ func (s *Handler) handleAPICommand(ctx context.Context, cmd *LegacyCommand) (*Le
 			return rep, nil
 		}
 		resp := s.api.Subscribe(ctx, cmd)
+		s.api.audit(ctx, "subscribe", cmd, resp.Error)
 		if resp.Error != nil {
 			rep.Error = resp.Error
 		} else {
@@ -448,6 +451,7 @@ func (s *Handler) handleAPICommand(ctx context.Context, cmd *LegacyCommand) (*Le
 			return rep, nil
 		}
 		resp := s.api.Unsubscribe(ctx, cmd)
+		s.api.audit(ctx, "unsubscribe", cmd, resp.Error)
 		if resp.Error != nil {
 			rep.Error = resp.Error
 		} else {
@@ -466,6 +470,7 @@ func (s *Handler) handleAPICommand(ctx context.Context, cmd *LegacyCommand) (*Le
 			return rep, nil
 		}
 		resp := s.api.Disconnect(ctx, cmd)
+		s.api.audit(ctx, "disconnect", cmd, resp.Error)
 		if resp.Error != nil {
 			rep.Error = resp.Error
 		} else {
@@ -484,6 +489,7 @@ func (s *Handler) handleAPICommand(ctx context.Context, cmd *LegacyCommand) (*Le
 			return rep, nil
 		}
 		resp := s.api.Presence(ctx, cmd)
+		s.api.audit(ctx, "presence", cmd, resp.Error)
 		if resp.Error != nil {
 			rep.Error = resp.Error
 		} else {
@@ -502,6 +508,7 @@ func (s *Handler) handleAPICommand(ctx context.Context, cmd *LegacyCommand) (*Le
 			return rep, nil
 		}
 		resp := s.api.PresenceStats(ctx, cmd)
+		s.api.audit(ctx, "presence_stats", cmd, resp.Error)
 		if resp.Error != nil {
 			rep.Error = resp.Error
 		} else {
@@ -520,6 +527,7 @@ func (s *Handler) handleAPICommand(ctx context.Context, cmd *LegacyCommand) (*Le
 			return rep, nil
 		}
 		resp := s.api.History(ctx, cmd)
+		s.api.audit(ctx, "history", cmd, resp.Error)
 		if resp.Error != nil {
 			rep.Error = resp.Error
 		} else {
@@ -538,6 +546,7 @@ func (s *Handler) handleAPICommand(ctx context.Context, cmd *LegacyCommand) (*Le
 			return rep, nil
 		}
 		resp := s.api.HistoryRemove(ctx, cmd)
+		s.api.audit(ctx, "history_remove", cmd, resp.Error)
 		if resp.Error != nil {
 			rep.Error = resp.Error
 		} else {
@@ -549,7 +558,9 @@ func (s *Handler) handleAPICommand(ctx context.Context, cmd *LegacyCommand) (*Le
 			}
 		}
 	case Command_INFO:
-		resp := s.api.Info(ctx, &apiproto.InfoRequest{})
+		req := &apiproto.InfoRequest{}
+		resp := s.api.Info(ctx, req)
+		s.api.audit(ctx, "info", req, resp.Error)
 		if resp.Error != nil {
 			rep.Error = resp.Error
 		} else {
@@ -569,6 +580,7 @@ func (s *Handler) handleAPICommand(ctx context.Context, cmd *LegacyCommand) (*Le
 			return rep, nil
 		}
 		resp := s.api.RPC(ctx, cmd)
+		s.api.audit(ctx, "rpc", cmd, resp.Error)
 		if resp.Error != nil {
 			rep.Error = resp.Error
 		} else {
@@ -587,6 +599,7 @@ func (s *Handler) handleAPICommand(ctx context.Context, cmd *LegacyCommand) (*Le
 			return rep, nil
 		}
 		resp := s.api.Refresh(ctx, cmd)
+		s.api.audit(ctx, "refresh", cmd, resp.Error)
 		if resp.Error != nil {
 			rep.Error = resp.Error
 		} else {
@@ -605,6 +618,7 @@ func (s *Handler) handleAPICommand(ctx context.Context, cmd *LegacyCommand) (*Le
 			return rep, nil
 		}
 		resp := s.api.Channels(ctx, cmd)
+		s.api.audit(ctx, "channels", cmd, resp.Error)
 		if resp.Error != nil {
 			rep.Error = resp.Error
 		} else {
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/centrifugal/centrifugo/v6/internal/mapfilter"
+
+	"github.com/centrifugal/centrifuge"
+)
+
+// MapStateFilterReader can read map state filtered and sorted by secondary index values
+// natively (i.e. using database indexes). It may return mapfilter.ErrNotSupported for
+// channels it can not handle, in which case Executor falls back to in-memory filtering.
+type MapStateFilterReader interface {
+	ReadFilteredState(ctx context.Context, ch string, q mapfilter.Query, opts centrifuge.MapReadStateOptions) (centrifuge.MapStateResult, error)
+}
+
+// SetMapStateFilterReader sets MapStateFilterReader used for filtered map state reads.
+func (h *Executor) SetMapStateFilterReader(r MapStateFilterReader) {
+	h.mapStateFilterReader = r
+}
+
+const (
+	// mapFilterScanPageSize is a page size used to load map state for in-memory filtering.
+	mapFilterScanPageSize = 1000
+	// defaultMapFilterScanLimit limits the number of state entries scanned for in-memory
+	// filtering when namespace does not set map.filter_scan_limit. Filtering larger states
+	// requires a broker with native support.
+	defaultMapFilterScanLimit = 100000
+)
+
+var errMapFilterScanLimit = errors.New("map state too large for in-memory filtering")
+
+func (h *Executor) mapReadFilteredState(ctx context.Context, ch string, q mapfilter.Query, opts centrifuge.MapReadStateOptions, scanLimit int) (centrifuge.MapStateResult, error) {
+	if h.mapStateFilterReader != nil {
+		result, err := h.mapStateFilterReader.ReadFilteredState(ctx, ch, q, opts)
+		if !errors.Is(err, mapfilter.ErrNotSupported) {
+			return result, err
+		}
+	}
+	if scanLimit <= 0 {
+		scanLimit = defaultMapFilterScanLimit
+	}
+	return h.scanFilteredMapState(ctx, ch, q, opts, scanLimit)
+}
+
+// scanFilteredMapState loads the entire map state page by page and applies the query
+// in memory. All pages are read at the same revision, so the result is consistent.
+// Brokers without native filtering (memory, Redis, Postgres in binary data mode) pay
+// for a full state scan on every page request, so the number of scanned entries is
+// bounded by scanLimit. Only the requested page of matches is kept in memory.
+func (h *Executor) scanFilteredMapState(ctx context.Context, ch string, q mapfilter.Query, opts centrifuge.MapReadStateOptions, scanLimit int) (centrifuge.MapStateResult, error) {
+	var pos mapfilter.Position
+	if opts.Cursor != "" {
+		var err error
+		pos, err = q.ParseCursor(opts.Cursor)
+		if err != nil {
+			return centrifuge.MapStateResult{}, err
+		}
+	}
+
+	var matched []*centrifuge.Publication
+	var result centrifuge.MapStateResult
+	readOpts := centrifuge.MapReadStateOptions{
+		Limit:       mapFilterScanPageSize,
+		Revision:    opts.Revision,
+		AllowCached: opts.AllowCached,
+	}
+	scanned := 0
+	for {
+		page, err := h.node.MapStateRead(ctx, ch, readOpts)
+		if err != nil {
+			return centrifuge.MapStateResult{}, err
+		}
+		result.Position = page.Position
+		if readOpts.Revision == nil {
+			readOpts.Revision = &centrifuge.StreamPosition{Offset: page.Position.Offset, Epoch: page.Position.Epoch}
+		}
+		scanned += len(page.Publications)
+		if scanned > scanLimit {
+			return centrifuge.MapStateResult{}, fmt.Errorf("%w: more than %d entries", errMapFilterScanLimit, scanLimit)
+		}
+		for _, pub := range page.Publications {
+			if !q.Match(pub.Data) {
+				continue
+			}
+			if opts.Cursor != "" && !q.After(pub.Key, pub.Data, pos) {
+				continue
+			}
+			matched = append(matched, pub)
+		}
+		if opts.Limit > 0 && len(matched) > 2*opts.Limit {
+			// Keep memory bounded by the page size: only the first opts.Limit matches
+			// in sort order can be a part of the result.
+			matched = sortPublications(q, matched)[:opts.Limit+1]
+		}
+		if page.Cursor == "" {
+			break
+		}
+		readOpts.Cursor = page.Cursor
+	}
+
+	if opts.Limit == 0 {
+		return centrifuge.MapStateResult{Position: result.Position}, nil
+	}
+
+	matched = sortPublications(q, matched)
+	if opts.Limit > 0 && len(matched) > opts.Limit {
+		matched = matched[:opts.Limit]
+		last := matched[len(matched)-1]
+		result.Cursor = q.MakeCursor(last.Key, last.Data)
+	}
+	result.Publications = matched
+	return result, nil
+}
+
+func sortPublications(q mapfilter.Query, pubs []*centrifuge.Publication) []*centrifuge.Publication {
+	sort.SliceStable(pubs, func(i, j int) bool {
+		return q.Compare(pubs[i].Key, pubs[i].Data, pubs[j].Key, pubs[j].Data) < 0
+	})
+	return pubs
+}
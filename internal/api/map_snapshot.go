@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+
+	. "github.com/centrifugal/centrifugo/v6/internal/apiproto"
+	"github.com/centrifugal/centrifugo/v6/internal/mapsnapshot"
+
+	"github.com/centrifugal/centrifuge"
+)
+
+// MapSnapshotImporter can import map channel snapshot natively keeping stream position
+// of the snapshot. It must return mapsnapshot.ErrChannelNotEmpty if channel has data
+// and replace is false.
+type MapSnapshotImporter interface {
+	ImportSnapshot(ctx context.Context, ch string, pos centrifuge.StreamPosition, entries []*centrifuge.Publication, replace bool) error
+}
+
+// SetMapSnapshotImporter sets MapSnapshotImporter used by map_import. Without it
+// snapshot entries are re-published into the channel, so channel gets a new stream
+// position.
+func (h *Executor) SetMapSnapshotImporter(i MapSnapshotImporter) {
+	h.mapSnapshotImporter = i
+}
+
+// mapExportPageSize is a page size used to read map state for export.
+const mapExportPageSize = 1000
+
+// readMapSnapshot reads the entire map state page by page at the same revision.
+func (h *Executor) readMapSnapshot(ctx context.Context, ch string) (*MapSnapshot, error) {
+	snapshot := &MapSnapshot{Channel: ch}
+	opts := centrifuge.MapReadStateOptions{Limit: mapExportPageSize}
+	for {
+		page, err := h.node.MapStateRead(ctx, ch, opts)
+		if err != nil {
+			return nil, err
+		}
+		if opts.Revision == nil {
+			snapshot.Offset, snapshot.Epoch = page.Position.Offset, page.Position.Epoch
+			opts.Revision = &centrifuge.StreamPosition{Offset: page.Position.Offset, Epoch: page.Position.Epoch}
+		}
+		for _, pub := range page.Publications {
+			if pub.Removed {
+				continue
+			}
+			snapshot.Entries = append(snapshot.Entries, mapEntryFromPublication(pub))
+		}
+		if page.Cursor == "" {
+			return snapshot, nil
+		}
+		opts.Cursor = page.Cursor
+	}
+}
+
+// republishMapSnapshot imports snapshot entries with regular map publications. Used
+// when map broker can't import snapshots natively. Entries keep client info, but get
+// new offsets and publish time. Returns resulting stream position.
+func (h *Executor) republishMapSnapshot(ctx context.Context, snapshot *MapSnapshot, replace bool) (centrifuge.StreamPosition, error) {
+	ch := snapshot.Channel
+	if replace {
+		if err := h.node.MapClear(ctx, ch, centrifuge.MapClearOptions{}); err != nil {
+			return centrifuge.StreamPosition{}, err
+		}
+	} else {
+		stats, err := h.node.MapStats(ctx, ch)
+		if err != nil {
+			return centrifuge.StreamPosition{}, err
+		}
+		if stats.NumKeys > 0 {
+			return centrifuge.StreamPosition{}, mapsnapshot.ErrChannelNotEmpty
+		}
+	}
+	var pos centrifuge.StreamPosition
+	for _, entry := range snapshot.Entries {
+		if entry.Removed {
+			continue
+		}
+		opts := centrifuge.MapPublishOptions{
+			Data: entry.Data,
+			Tags: entry.Tags,
+		}
+		if entry.Info != nil {
+			opts.ClientInfo = publicationFromMapEntry(entry).Info
+		}
+		res, err := h.node.MapPublish(ctx, ch, entry.Key, opts)
+		if err != nil {
+			return centrifuge.StreamPosition{}, err
+		}
+		pos = res.Position
+	}
+	return pos, nil
+}
+
+// mapEntryFromPublication converts map publication to API map entry with all fields
+// required to restore it on import.
+func mapEntryFromPublication(pub *centrifuge.Publication) *MapEntry {
+	entry := &MapEntry{
+		Key:     pub.Key,
+		Data:    pub.Data,
+		Tags:    pub.Tags,
+		Offset:  pub.Offset,
+		Score:   pub.Score,
+		Removed: pub.Removed,
+		Time:    pub.Time,
+	}
+	if pub.Info != nil {
+		entry.Info = &ClientInfo{
+			User:     pub.Info.UserID,
+			Client:   pub.Info.ClientID,
+			ConnInfo: pub.Info.ConnInfo,
+			ChanInfo: pub.Info.ChanInfo,
+		}
+	}
+	return entry
+}
+
+// publicationFromMapEntry is the reverse of mapEntryFromPublication.
+func publicationFromMapEntry(entry *MapEntry) *centrifuge.Publication {
+	pub := &centrifuge.Publication{
+		Key:     entry.Key,
+		Data:    entry.Data,
+		Tags:    entry.Tags,
+		Offset:  entry.Offset,
+		Score:   entry.Score,
+		Removed: entry.Removed,
+		Time:    entry.Time,
+	}
+	if entry.Info != nil {
+		pub.Info = &centrifuge.ClientInfo{
+			UserID:   entry.Info.User,
+			ClientID: entry.Info.Client,
+			ConnInfo: entry.Info.ConnInfo,
+			ChanInfo: entry.Info.ChanInfo,
+		}
+	}
+	return pub
+}
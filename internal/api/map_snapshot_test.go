@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/centrifugal/centrifugo/v6/internal/apiproto"
+	"github.com/centrifugal/centrifugo/v6/internal/clientcontext"
+	"github.com/centrifugal/centrifugo/v6/internal/config"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/stretchr/testify/require"
+)
+
+func nodeWithMapChannels(t *testing.T) *centrifuge.Node {
+	n, err := centrifuge.New(centrifuge.Config{
+		Map: centrifuge.MapConfig{
+			GetMapChannelOptions: func(channel string) centrifuge.MapChannelOptions {
+				return centrifuge.MapChannelOptions{
+					Mode:   centrifuge.MapModeEphemeral,
+					KeyTTL: time.Minute,
+				}
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, n.Run())
+	t.Cleanup(func() { _ = n.Shutdown(context.Background()) })
+	return n
+}
+
+type testMapSnapshotImporter struct {
+	pos     centrifuge.StreamPosition
+	entries []*centrifuge.Publication
+}
+
+func (i *testMapSnapshotImporter) ImportSnapshot(_ context.Context, _ string, pos centrifuge.StreamPosition, entries []*centrifuge.Publication, _ bool) error {
+	i.pos = pos
+	i.entries = entries
+	return nil
+}
+
+func TestMapSnapshotRoundTrip(t *testing.T) {
+	for _, subscriptionType := range []string{"map_clients", "map_users"} {
+		t.Run(subscriptionType, func(t *testing.T) {
+			ctx := context.Background()
+			cfgContainer, err := config.NewContainer(configWithNamespace("ns", subscriptionType))
+			require.NoError(t, err)
+
+			source := nodeWithMapChannels(t)
+			info := &centrifuge.ClientInfo{
+				ClientID: "client1",
+				UserID:   "user1",
+				ConnInfo: []byte(`{"name":"Alice"}`),
+				ChanInfo: []byte(`{"role":"admin"}`),
+			}
+			_, err = source.MapPublish(ctx, "ns:test", "client1", centrifuge.MapPublishOptions{
+				Data:       []byte(`{"status":"online"}`),
+				Tags:       map[string]string{"device": "mobile"},
+				ClientInfo: info,
+			})
+			require.NoError(t, err)
+
+			sourceAPI := NewExecutor(source, cfgContainer, &testSurveyCaller{}, ExecutorConfig{Protocol: "test"})
+			exportResp := sourceAPI.MapExport(ctx, &MapExportRequest{Channel: "ns:test"})
+			require.Nil(t, exportResp.Error)
+			snapshot := exportResp.Result.Snapshot
+			require.Len(t, snapshot.Entries, 1)
+			exported := snapshot.Entries[0]
+			require.Equal(t, &ClientInfo{
+				User:     "user1",
+				Client:   "client1",
+				ConnInfo: info.ConnInfo,
+				ChanInfo: info.ChanInfo,
+			}, exported.Info)
+
+			// Native import gets all entry fields.
+			importer := &testMapSnapshotImporter{}
+			nativeAPI := NewExecutor(nodeWithMapChannels(t), cfgContainer, &testSurveyCaller{}, ExecutorConfig{Protocol: "test"})
+			nativeAPI.SetMapSnapshotImporter(importer)
+			importResp := nativeAPI.MapImport(ctx, &MapImportRequest{Snapshot: snapshot})
+			require.Nil(t, importResp.Error)
+			require.True(t, importResp.Result.PositionPreserved)
+			require.Len(t, importer.entries, 1)
+			imported := importer.entries[0]
+			require.Equal(t, exported.Key, imported.Key)
+			require.Equal(t, exported.Data, imported.Data)
+			require.Equal(t, exported.Tags, imported.Tags)
+			require.Equal(t, exported.Offset, imported.Offset)
+			require.Equal(t, exported.Time, imported.Time)
+			require.Equal(t, info, imported.Info)
+
+			// Re-published entries keep data, tags and client info.
+			target := nodeWithMapChannels(t)
+			targetAPI := NewExecutor(target, cfgContainer, &testSurveyCaller{}, ExecutorConfig{Protocol: "test"})
+			importResp = targetAPI.MapImport(ctx, &MapImportRequest{Snapshot: snapshot})
+			require.Nil(t, importResp.Error)
+			require.False(t, importResp.Result.PositionPreserved)
+			exportResp = targetAPI.MapExport(ctx, &MapExportRequest{Channel: "ns:test"})
+			require.Nil(t, exportResp.Error)
+			require.Len(t, exportResp.Result.Snapshot.Entries, 1)
+			reimported := exportResp.Result.Snapshot.Entries[0]
+			require.Equal(t, exported.Key, reimported.Key)
+			require.Equal(t, exported.Data, reimported.Data)
+			require.Equal(t, exported.Tags, reimported.Tags)
+			require.Equal(t, exported.Info, reimported.Info)
+		})
+	}
+}
+
+func TestMapImportRejectsScore(t *testing.T) {
+	cfgContainer, err := config.NewContainer(configWithNamespace("ns", "map"))
+	require.NoError(t, err)
+	api := NewExecutor(nodeWithMapChannels(t), cfgContainer, &testSurveyCaller{}, ExecutorConfig{Protocol: "test"})
+	resp := api.MapImport(context.Background(), &MapImportRequest{Snapshot: &MapSnapshot{
+		Channel: "ns:test",
+		Entries: []*MapEntry{{Key: "k", Data: []byte(`{}`), Score: 10}},
+	}})
+	require.Equal(t, ErrorBadRequest, resp.Error)
+}
+
+func TestMapImportTenantNotAllowed(t *testing.T) {
+	cfgContainer, err := config.NewContainer(configWithNamespace("ns", "map"))
+	require.NoError(t, err)
+	importer := &testMapSnapshotImporter{}
+	api := NewExecutor(nodeWithMapChannels(t), cfgContainer, &testSurveyCaller{}, ExecutorConfig{Protocol: "test"})
+	api.SetMapSnapshotImporter(importer)
+	ctx := clientcontext.SetTenantToContext(context.Background(), "acme")
+	resp := api.MapImport(ctx, &MapImportRequest{Snapshot: &MapSnapshot{
+		Channel: "ns:test",
+		Entries: []*MapEntry{{Key: "k", Data: []byte(`{}`)}},
+	}})
+	require.Equal(t, ErrorPermissionDenied, resp.Error)
+	require.Nil(t, importer.entries)
+}
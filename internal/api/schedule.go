@@ -0,0 +1,176 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/centrifugal/centrifugo/v6/internal/apiproto"
+	"github.com/centrifugal/centrifugo/v6/internal/clientcontext"
+	"github.com/centrifugal/centrifugo/v6/internal/config"
+	"github.com/centrifugal/centrifugo/v6/internal/schedule"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/proto"
+)
+
+// SetScheduler sets Scheduler used by publish and broadcast with publish_at or delay
+// set, and by scheduled_list and scheduled_cancel. Without Scheduler these requests
+// are rejected with not available error.
+func (h *Executor) SetScheduler(s *schedule.Scheduler) {
+	h.scheduler = s
+}
+
+// scheduledSource is an audit source of publications fired by scheduler.
+const scheduledSource = "scheduler"
+
+// scheduledIdempotencyKeyPrefix is used to build idempotency key of scheduled
+// publication sent without one, so that publication fired again after a node
+// failure is suppressed by broker.
+const scheduledIdempotencyKeyPrefix = "scheduled_"
+
+// publishTime returns time to publish at for request with publish_at or delay
+// set. Returns false if publication should be published right away.
+func publishTime(publishAt int64, delay int64) (time.Time, bool, *Error) {
+	if publishAt == 0 && delay == 0 {
+		return time.Time{}, false, nil
+	}
+	if publishAt < 0 || delay < 0 || (publishAt != 0 && delay != 0) {
+		return time.Time{}, true, ErrorBadRequest
+	}
+	if publishAt != 0 {
+		return time.UnixMilli(publishAt), true, nil
+	}
+	return time.Now().Add(time.Duration(delay) * time.Millisecond), true, nil
+}
+
+func (h *Executor) schedule(ctx context.Context, method string, channels []string, req proto.Message, publishAt time.Time) (string, *Error) {
+	if h.scheduler == nil {
+		return "", ErrorNotAvailable
+	}
+	payload, err := proto.Marshal(req)
+	if err != nil {
+		log.Error().Err(err).Str("method", method).Msg("error encoding scheduled publication")
+		return "", ErrorInternal
+	}
+	id, err := h.scheduler.Schedule(ctx, method, channels, payload, publishAt)
+	if err != nil {
+		if errors.Is(err, schedule.ErrTooFarInFuture) {
+			return "", ErrorBadRequest
+		}
+		log.Error().Err(err).Str("method", method).Msg("error scheduling publication")
+		return "", ErrorInternal
+	}
+	return id, nil
+}
+
+// validateBroadcastChannels validates all broadcast channels before broadcast is
+// scheduled, as scheduled broadcast can't report per channel errors.
+func (h *Executor) validateBroadcastChannels(channels []string, data []byte) *Error {
+	for _, ch := range channels {
+		if ch == "" {
+			return ErrorBadRequest
+		}
+		nsName, _, chOpts, found, err := h.cfgContainer.ChannelOptions(ch)
+		if err != nil {
+			return ErrorInternal
+		}
+		if !found {
+			return ErrorUnknownChannel
+		}
+		if err := config.ValidatePublicationData(data, chOpts.PublicationDataFormat); err != nil {
+			log.Error().Err(err).Str("channel", ch).Msg("bad broadcast request")
+			return ErrorBadRequest
+		}
+		if err := config.ValidatePublicationDataSchema(data, chOpts); err != nil {
+			log.Info().Err(err).Str("channel", ch).Str("namespace", nsName).Msg("publication data schema violation")
+			return ErrorSchemaViolation
+		}
+	}
+	return nil
+}
+
+// ExecuteScheduled fires scheduled publication. It returns an error only if firing
+// should be retried, other errors are logged and publication is considered fired.
+func (h *Executor) ExecuteScheduled(ctx context.Context, item schedule.Item) error {
+	ctx = clientcontext.SetAPICallerToContext(ctx, clientcontext.APICaller{
+		Source:   scheduledSource,
+		Identity: item.ID,
+	})
+	var respErr *Error
+	switch item.Method {
+	case "publish":
+		req := &PublishRequest{}
+		if err := proto.Unmarshal(item.Payload, req); err != nil {
+			log.Error().Err(err).Str("id", item.ID).Msg("malformed scheduled publication, skipping")
+			return nil
+		}
+		req.PublishAt, req.Delay = 0, 0
+		if req.IdempotencyKey == "" {
+			req.IdempotencyKey = scheduledIdempotencyKeyPrefix + item.ID
+		}
+		resp := h.Publish(ctx, req)
+		h.audit(ctx, "publish", req, resp.Error)
+		respErr = resp.Error
+	case "broadcast":
+		req := &BroadcastRequest{}
+		if err := proto.Unmarshal(item.Payload, req); err != nil {
+			log.Error().Err(err).Str("id", item.ID).Msg("malformed scheduled broadcast, skipping")
+			return nil
+		}
+		req.PublishAt, req.Delay = 0, 0
+		if req.IdempotencyKey == "" {
+			req.IdempotencyKey = scheduledIdempotencyKeyPrefix + item.ID
+		}
+		resp := h.Broadcast(ctx, req)
+		h.audit(ctx, "broadcast", req, resp.Error)
+		respErr = resp.Error
+		if respErr == nil && resp.Result != nil {
+			// Idempotency key makes broadcast retry safe for channels already published to.
+			for _, r := range resp.Result.Responses {
+				if r.Error != nil && (respErr == nil || r.Error.Code == ErrorInternal.Code) {
+					respErr = r.Error
+				}
+			}
+		}
+	default:
+		log.Error().Str("id", item.ID).Str("method", item.Method).Msg("unknown scheduled publication method, skipping")
+		return nil
+	}
+	if respErr != nil {
+		if respErr.Code == ErrorInternal.Code {
+			return fmt.Errorf("error firing scheduled %s: %s", item.Method, respErr.Message)
+		}
+		log.Warn().Str("id", item.ID).Str("method", item.Method).Uint32("code", respErr.Code).Str("error", respErr.Message).Msg("scheduled publication failed")
+	}
+	return nil
+}
+
+// scheduledPublication converts scheduled item to API representation.
+func scheduledPublication(item schedule.Item) (*ScheduledPublication, error) {
+	pub := &ScheduledPublication{
+		Id:        item.ID,
+		Method:    item.Method,
+		Channels:  item.Channels,
+		PublishAt: item.PublishAt,
+		CreatedAt: item.CreatedAt,
+	}
+	switch item.Method {
+	case "publish":
+		req := &PublishRequest{}
+		if err := proto.Unmarshal(item.Payload, req); err != nil {
+			return nil, err
+		}
+		pub.Data, pub.B64Data, pub.Tags, pub.IdempotencyKey = req.Data, req.B64Data, req.Tags, req.IdempotencyKey
+	case "broadcast":
+		req := &BroadcastRequest{}
+		if err := proto.Unmarshal(item.Payload, req); err != nil {
+			return nil, err
+		}
+		pub.Data, pub.B64Data, pub.Tags, pub.IdempotencyKey = req.Data, req.B64Data, req.Tags, req.IdempotencyKey
+	default:
+		return nil, fmt.Errorf("unknown method: %s", item.Method)
+	}
+	return pub, nil
+}
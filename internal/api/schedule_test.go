@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/centrifugal/centrifugo/v6/internal/apiproto"
+	"github.com/centrifugal/centrifugo/v6/internal/config"
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/schedule"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/stretchr/testify/require"
+)
+
+// testScheduleStore is a schedule.Store keeping publications in memory. It does not
+// support claiming, scheduled publications are fired in tests with ExecuteScheduled.
+type testScheduleStore struct {
+	mu    sync.Mutex
+	items map[string]schedule.Item
+}
+
+func (s *testScheduleStore) Add(_ context.Context, item schedule.Item) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[item.ID] = item
+	return nil
+}
+
+func (s *testScheduleStore) Get(_ context.Context, id string) (schedule.Item, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item, ok := s.items[id]
+	return item, ok, nil
+}
+
+func (s *testScheduleStore) List(_ context.Context, _ schedule.ListOptions) ([]schedule.Item, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items := make([]schedule.Item, 0, len(s.items))
+	for _, item := range s.items {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].PublishAt < items[j].PublishAt })
+	return items, "", nil
+}
+
+func (s *testScheduleStore) Cancel(_ context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.items[id]
+	delete(s.items, id)
+	return ok, nil
+}
+
+func (s *testScheduleStore) Claim(_ context.Context, _ time.Time, _ int, _ time.Duration) ([]schedule.Item, error) {
+	return nil, nil
+}
+
+func (s *testScheduleStore) Complete(_ context.Context, _ []string) error {
+	return nil
+}
+
+func newScheduleTestExecutor(t *testing.T) (*Executor, *testScheduleStore, *centrifuge.Node) {
+	node := nodeWithMemoryEngine()
+	t.Cleanup(func() { _ = node.Shutdown(context.Background()) })
+	cfg := config.DefaultConfig()
+	cfg.Channel.WithoutNamespace.HistorySize = 10
+	cfg.Channel.WithoutNamespace.HistoryTTL = configtypes.Duration(time.Minute)
+	cfgContainer, err := config.NewContainer(cfg)
+	require.NoError(t, err)
+	executor := NewExecutor(node, cfgContainer, &testSurveyCaller{}, ExecutorConfig{Protocol: "test"})
+	store := &testScheduleStore{items: map[string]schedule.Item{}}
+	executor.SetScheduler(schedule.New(store, configtypes.ScheduledPublications{
+		Enabled:  true,
+		MaxDelay: configtypes.Duration(time.Hour),
+	}, nil))
+	return executor, store, node
+}
+
+func historyLen(t *testing.T, node *centrifuge.Node, ch string) int {
+	res, err := node.History(ch, centrifuge.WithLimit(centrifuge.NoLimit))
+	require.NoError(t, err)
+	return len(res.Publications)
+}
+
+func TestPublishScheduled(t *testing.T) {
+	executor, store, node := newScheduleTestExecutor(t)
+	ctx := context.Background()
+
+	resp := executor.Publish(ctx, &PublishRequest{Channel: "test", Data: []byte(`{}`), PublishAt: 1, Delay: 1})
+	require.Equal(t, ErrorBadRequest, resp.Error)
+	resp = executor.Publish(ctx, &PublishRequest{Channel: "test", Data: []byte(`{}`), Delay: (2 * time.Hour).Milliseconds()})
+	require.Equal(t, ErrorBadRequest, resp.Error)
+
+	resp = executor.Publish(ctx, &PublishRequest{Channel: "test", Data: []byte(`{"a":1}`), Delay: 1000, Tags: map[string]string{"k": "v"}})
+	require.Nil(t, resp.Error)
+	require.NotEmpty(t, resp.Result.ScheduleId)
+	require.Zero(t, historyLen(t, node, "test"))
+
+	listResp := executor.ScheduledList(ctx, &ScheduledListRequest{})
+	require.Nil(t, listResp.Error)
+	require.Len(t, listResp.Result.Publications, 1)
+	pub := listResp.Result.Publications[0]
+	require.Equal(t, resp.Result.ScheduleId, pub.Id)
+	require.Equal(t, "publish", pub.Method)
+	require.Equal(t, []string{"test"}, pub.Channels)
+	require.Equal(t, `{"a":1}`, string(pub.Data))
+	require.Equal(t, map[string]string{"k": "v"}, pub.Tags)
+	require.Greater(t, pub.PublishAt, pub.CreatedAt)
+
+	item := store.items[pub.Id]
+	require.NoError(t, executor.ExecuteScheduled(ctx, item))
+	require.Equal(t, 1, historyLen(t, node, "test"))
+	// Firing again, for example after a node failure, is suppressed by idempotency key.
+	require.NoError(t, executor.ExecuteScheduled(ctx, item))
+	require.Equal(t, 1, historyLen(t, node, "test"))
+}
+
+func TestBroadcastScheduled(t *testing.T) {
+	executor, store, node := newScheduleTestExecutor(t)
+	ctx := context.Background()
+
+	resp := executor.Broadcast(ctx, &BroadcastRequest{Channels: []string{"a", "unknown:b"}, Data: []byte(`{}`), Delay: 1000})
+	require.Equal(t, ErrorUnknownChannel, resp.Error)
+	require.Empty(t, store.items)
+
+	resp = executor.Broadcast(ctx, &BroadcastRequest{Channels: []string{"a", "b"}, Data: []byte(`{}`), PublishAt: time.Now().Add(time.Minute).UnixMilli()})
+	require.Nil(t, resp.Error)
+	require.NotEmpty(t, resp.Result.ScheduleId)
+	require.Empty(t, resp.Result.Responses)
+
+	require.NoError(t, executor.ExecuteScheduled(ctx, store.items[resp.Result.ScheduleId]))
+	require.Equal(t, 1, historyLen(t, node, "a"))
+	require.Equal(t, 1, historyLen(t, node, "b"))
+}
+
+func TestScheduledCancel(t *testing.T) {
+	executor, _, _ := newScheduleTestExecutor(t)
+	ctx := context.Background()
+
+	resp := executor.Publish(ctx, &PublishRequest{Channel: "test", Data: []byte(`{}`), Delay: 1000})
+	require.Nil(t, resp.Error)
+
+	cancelResp := executor.ScheduledCancel(ctx, &ScheduledCancelRequest{})
+	require.Equal(t, ErrorBadRequest, cancelResp.Error)
+	cancelResp = executor.ScheduledCancel(ctx, &ScheduledCancelRequest{Id: resp.Result.ScheduleId})
+	require.Nil(t, cancelResp.Error)
+	cancelResp = executor.ScheduledCancel(ctx, &ScheduledCancelRequest{Id: resp.Result.ScheduleId})
+	require.Equal(t, ErrorNotFound, cancelResp.Error)
+
+	listResp := executor.ScheduledList(ctx, &ScheduledListRequest{})
+	require.Nil(t, listResp.Error)
+	require.Empty(t, listResp.Result.Publications)
+}
+
+func TestScheduledNotAvailable(t *testing.T) {
+	node := nodeWithMemoryEngine()
+	defer func() { _ = node.Shutdown(context.Background()) }()
+	cfgContainer, err := config.NewContainer(config.DefaultConfig())
+	require.NoError(t, err)
+	executor := NewExecutor(node, cfgContainer, &testSurveyCaller{}, ExecutorConfig{Protocol: "test"})
+	ctx := context.Background()
+
+	require.Equal(t, ErrorNotAvailable, executor.Publish(ctx, &PublishRequest{Channel: "test", Data: []byte(`{}`), Delay: 1000}).Error)
+	require.Equal(t, ErrorNotAvailable, executor.ScheduledList(ctx, &ScheduledListRequest{}).Error)
+	require.Equal(t, ErrorNotAvailable, executor.ScheduledCancel(ctx, &ScheduledCancelRequest{Id: "x"}).Error)
+}
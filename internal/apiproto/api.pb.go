@@ -62,6 +62,10 @@ type Command struct {
 	MapStats             *MapStatsRequest             `protobuf:"bytes,40,opt,name=map_stats,json=mapStats,proto3" json:"map_stats,omitempty"`
 	MapClear             *MapClearRequest             `protobuf:"bytes,41,opt,name=map_clear,json=mapClear,proto3" json:"map_clear,omitempty"`
 	SharedPollPublish    *SharedPollPublishRequest    `protobuf:"bytes,42,opt,name=shared_poll_publish,json=sharedPollPublish,proto3" json:"shared_poll_publish,omitempty"`
+	MapExport            *MapExportRequest            `protobuf:"bytes,43,opt,name=map_export,json=mapExport,proto3" json:"map_export,omitempty"`
+	MapImport            *MapImportRequest            `protobuf:"bytes,44,opt,name=map_import,json=mapImport,proto3" json:"map_import,omitempty"`
+	ScheduledList        *ScheduledListRequest        `protobuf:"bytes,45,opt,name=scheduled_list,json=scheduledList,proto3" json:"scheduled_list,omitempty"`
+	ScheduledCancel      *ScheduledCancelRequest      `protobuf:"bytes,46,opt,name=scheduled_cancel,json=scheduledCancel,proto3" json:"scheduled_cancel,omitempty"`
 	unknownFields        protoimpl.UnknownFields
 	sizeCache            protoimpl.SizeCache
 }
@@ -369,6 +373,34 @@ func (x *Command) GetSharedPollPublish() *SharedPollPublishRequest {
 	return nil
 }
 
+func (x *Command) GetMapExport() *MapExportRequest {
+	if x != nil {
+		return x.MapExport
+	}
+	return nil
+}
+
+func (x *Command) GetMapImport() *MapImportRequest {
+	if x != nil {
+		return x.MapImport
+	}
+	return nil
+}
+
+func (x *Command) GetScheduledList() *ScheduledListRequest {
+	if x != nil {
+		return x.ScheduledList
+	}
+	return nil
+}
+
+func (x *Command) GetScheduledCancel() *ScheduledCancelRequest {
+	if x != nil {
+		return x.ScheduledCancel
+	}
+	return nil
+}
+
 type Error struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Code          uint32                 `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
@@ -463,6 +495,10 @@ type Reply struct {
 	MapStats             *MapStatsResult             `protobuf:"bytes,40,opt,name=map_stats,json=mapStats,proto3" json:"map_stats,omitempty"`
 	MapClear             *MapClearResult             `protobuf:"bytes,41,opt,name=map_clear,json=mapClear,proto3" json:"map_clear,omitempty"`
 	SharedPollPublish    *SharedPollPublishResult    `protobuf:"bytes,42,opt,name=shared_poll_publish,json=sharedPollPublish,proto3" json:"shared_poll_publish,omitempty"`
+	MapExport            *MapExportResult            `protobuf:"bytes,43,opt,name=map_export,json=mapExport,proto3" json:"map_export,omitempty"`
+	MapImport            *MapImportResult            `protobuf:"bytes,44,opt,name=map_import,json=mapImport,proto3" json:"map_import,omitempty"`
+	ScheduledList        *ScheduledListResult        `protobuf:"bytes,45,opt,name=scheduled_list,json=scheduledList,proto3" json:"scheduled_list,omitempty"`
+	ScheduledCancel      *ScheduledCancelResult      `protobuf:"bytes,46,opt,name=scheduled_cancel,json=scheduledCancel,proto3" json:"scheduled_cancel,omitempty"`
 	unknownFields        protoimpl.UnknownFields
 	sizeCache            protoimpl.SizeCache
 }
@@ -777,6 +813,34 @@ func (x *Reply) GetSharedPollPublish() *SharedPollPublishResult {
 	return nil
 }
 
+func (x *Reply) GetMapExport() *MapExportResult {
+	if x != nil {
+		return x.MapExport
+	}
+	return nil
+}
+
+func (x *Reply) GetMapImport() *MapImportResult {
+	if x != nil {
+		return x.MapImport
+	}
+	return nil
+}
+
+func (x *Reply) GetScheduledList() *ScheduledListResult {
+	if x != nil {
+		return x.ScheduledList
+	}
+	return nil
+}
+
+func (x *Reply) GetScheduledCancel() *ScheduledCancelResult {
+	if x != nil {
+		return x.ScheduledCancel
+	}
+	return nil
+}
+
 type BatchRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Commands      []*Command             `protobuf:"bytes,1,rep,name=commands,proto3" json:"commands,omitempty"`
@@ -884,6 +948,8 @@ type PublishRequest struct {
 	Delta          bool                   `protobuf:"varint,7,opt,name=delta,proto3" json:"delta,omitempty"`
 	Version        uint64                 `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"`
 	VersionEpoch   string                 `protobuf:"bytes,9,opt,name=version_epoch,json=versionEpoch,proto3" json:"version_epoch,omitempty"`
+	PublishAt      int64                  `protobuf:"varint,10,opt,name=publish_at,json=publishAt,proto3" json:"publish_at,omitempty"` // Unix milliseconds, if set - publication will be scheduled to be published at this time.
+	Delay          int64                  `protobuf:"varint,11,opt,name=delay,proto3" json:"delay,omitempty"`                          // milliseconds, if set - publication will be scheduled to be published after this delay.
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -981,6 +1047,20 @@ func (x *PublishRequest) GetVersionEpoch() string {
 	return ""
 }
 
+func (x *PublishRequest) GetPublishAt() int64 {
+	if x != nil {
+		return x.PublishAt
+	}
+	return 0
+}
+
+func (x *PublishRequest) GetDelay() int64 {
+	if x != nil {
+		return x.Delay
+	}
+	return 0
+}
+
 type PublishResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Error         *Error                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
@@ -1037,6 +1117,8 @@ type PublishResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Offset        uint64                 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
 	Epoch         string                 `protobuf:"bytes,2,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	ScheduleId    string                 `protobuf:"bytes,3,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"` // set instead of offset and epoch when publication was scheduled.
+	Deferred      bool                   `protobuf:"varint,4,opt,name=deferred,proto3" json:"deferred,omitempty"`                      // set instead of offset and epoch when publication was deferred till the end of throttle window.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -1085,6 +1167,20 @@ func (x *PublishResult) GetEpoch() string {
 	return ""
 }
 
+func (x *PublishResult) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+func (x *PublishResult) GetDeferred() bool {
+	if x != nil {
+		return x.Deferred
+	}
+	return false
+}
+
 type BroadcastRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	Channels       []string               `protobuf:"bytes,1,rep,name=channels,proto3" json:"channels,omitempty"`
@@ -1096,6 +1192,8 @@ type BroadcastRequest struct {
 	Delta          bool                   `protobuf:"varint,7,opt,name=delta,proto3" json:"delta,omitempty"`
 	Version        uint64                 `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"`
 	VersionEpoch   string                 `protobuf:"bytes,9,opt,name=version_epoch,json=versionEpoch,proto3" json:"version_epoch,omitempty"`
+	PublishAt      int64                  `protobuf:"varint,10,opt,name=publish_at,json=publishAt,proto3" json:"publish_at,omitempty"` // Unix milliseconds, if set - broadcast will be scheduled to be published at this time.
+	Delay          int64                  `protobuf:"varint,11,opt,name=delay,proto3" json:"delay,omitempty"`                          // milliseconds, if set - broadcast will be scheduled to be published after this delay.
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -1193,6 +1291,20 @@ func (x *BroadcastRequest) GetVersionEpoch() string {
 	return ""
 }
 
+func (x *BroadcastRequest) GetPublishAt() int64 {
+	if x != nil {
+		return x.PublishAt
+	}
+	return 0
+}
+
+func (x *BroadcastRequest) GetDelay() int64 {
+	if x != nil {
+		return x.Delay
+	}
+	return 0
+}
+
 type BroadcastResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Error         *Error                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
@@ -1248,6 +1360,7 @@ func (x *BroadcastResponse) GetResult() *BroadcastResult {
 type BroadcastResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Responses     []*PublishResponse     `protobuf:"bytes,1,rep,name=responses,proto3" json:"responses,omitempty"`
+	ScheduleId    string                 `protobuf:"bytes,2,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"` // set instead of responses when broadcast was scheduled.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -1289,6 +1402,13 @@ func (x *BroadcastResult) GetResponses() []*PublishResponse {
 	return nil
 }
 
+func (x *BroadcastResult) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
 // FilterNode is a tree describing a label predicate.
 // Used as label_filter on Subscribe/Unsubscribe/Disconnect/Refresh/Connections requests.
 // PRO only — defined in OSS api.proto for protocol-surface consistency; OSS handlers parse but do not act on it.
@@ -2593,6 +2713,9 @@ type HistoryRequest struct {
 	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
 	Since         *StreamPosition        `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`
 	Reverse       bool                   `protobuf:"varint,4,opt,name=reverse,proto3" json:"reverse,omitempty"`
+	FromTime      int64                  `protobuf:"varint,5,opt,name=from_time,json=fromTime,proto3" json:"from_time,omitempty"` // Unix milliseconds, if set - only publications published at or after this time returned.
+	ToTime        int64                  `protobuf:"varint,6,opt,name=to_time,json=toTime,proto3" json:"to_time,omitempty"`       // Unix milliseconds, if set - only publications published before this time returned.
+	Cursor        string                 `protobuf:"bytes,7,opt,name=cursor,proto3" json:"cursor,omitempty"`                      // next_cursor from previous result to continue iteration, can't be used together with since.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -2655,6 +2778,27 @@ func (x *HistoryRequest) GetReverse() bool {
 	return false
 }
 
+func (x *HistoryRequest) GetFromTime() int64 {
+	if x != nil {
+		return x.FromTime
+	}
+	return 0
+}
+
+func (x *HistoryRequest) GetToTime() int64 {
+	if x != nil {
+		return x.ToTime
+	}
+	return 0
+}
+
+func (x *HistoryRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
 type HistoryResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Error         *Error                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
@@ -2714,6 +2858,7 @@ type Publication struct {
 	Info          *ClientInfo       `protobuf:"bytes,3,opt,name=info,proto3" json:"info,omitempty"`
 	Offset        uint64            `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
 	Tags          map[string]string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Time          int64             `protobuf:"varint,6,opt,name=time,proto3" json:"time,omitempty"` // Unix milliseconds, set if broker keeps publication time.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -2776,11 +2921,19 @@ func (x *Publication) GetTags() map[string]string {
 	return nil
 }
 
+func (x *Publication) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
 type HistoryResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Publications  []*Publication         `protobuf:"bytes,1,rep,name=publications,proto3" json:"publications"`
 	Epoch         string                 `protobuf:"bytes,2,opt,name=epoch,proto3" json:"epoch"`
 	Offset        uint64                 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset"`
+	NextCursor    string                 `protobuf:"bytes,4,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // set if there are more publications to return with the same query.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -2836,6 +2989,13 @@ func (x *HistoryResult) GetOffset() uint64 {
 	return 0
 }
 
+func (x *HistoryResult) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
 type HistoryRemoveRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Channel       string                 `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
@@ -9065,6 +9225,8 @@ type MapReadStateRequest struct {
 	Asc            bool                   `protobuf:"varint,5,opt,name=asc,proto3" json:"asc,omitempty"`
 	RevisionOffset uint64                 `protobuf:"varint,6,opt,name=revision_offset,json=revisionOffset,proto3" json:"revision_offset,omitempty"`
 	RevisionEpoch  string                 `protobuf:"bytes,7,opt,name=revision_epoch,json=revisionEpoch,proto3" json:"revision_epoch,omitempty"`
+	Filter         string                 `protobuf:"bytes,8,opt,name=filter,proto3" json:"filter,omitempty"`
+	Sort           string                 `protobuf:"bytes,9,opt,name=sort,proto3" json:"sort,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -9148,6 +9310,20 @@ func (x *MapReadStateRequest) GetRevisionEpoch() string {
 	return ""
 }
 
+func (x *MapReadStateRequest) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
+func (x *MapReadStateRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
 type MapReadStateResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Error         *Error                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
@@ -9277,6 +9453,7 @@ type MapEntry struct {
 	Score         int64                  `protobuf:"varint,5,opt,name=score,proto3" json:"score,omitempty"`
 	Removed       bool                   `protobuf:"varint,6,opt,name=removed,proto3" json:"removed,omitempty"`
 	Time          int64                  `protobuf:"varint,7,opt,name=time,proto3" json:"time,omitempty"`
+	Info          *ClientInfo            `protobuf:"bytes,8,opt,name=info,proto3" json:"info,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -9360,6 +9537,13 @@ func (x *MapEntry) GetTime() int64 {
 	return 0
 }
 
+func (x *MapEntry) GetInfo() *ClientInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
 type MapReadStreamRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Channel       string                 `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
@@ -9820,32 +10004,30 @@ func (*MapClearResult) Descriptor() ([]byte, []int) {
 	return file_api_proto_rawDescGZIP(), []int{159}
 }
 
-type SharedPollPublishRequest struct {
+type MapSnapshot struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Channel       string                 `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
-	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
-	Data          Raw                    `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
-	B64Data       string                 `protobuf:"bytes,4,opt,name=b64data,proto3" json:"b64data,omitempty"`
-	Version       uint64                 `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
-	Epoch         string                 `protobuf:"bytes,6,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Offset        uint64                 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Epoch         string                 `protobuf:"bytes,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Entries       []*MapEntry            `protobuf:"bytes,4,rep,name=entries,proto3" json:"entries,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SharedPollPublishRequest) Reset() {
-	*x = SharedPollPublishRequest{}
+func (x *MapSnapshot) Reset() {
+	*x = MapSnapshot{}
 	mi := &file_api_proto_msgTypes[160]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SharedPollPublishRequest) String() string {
+func (x *MapSnapshot) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SharedPollPublishRequest) ProtoMessage() {}
+func (*MapSnapshot) ProtoMessage() {}
 
-func (x *SharedPollPublishRequest) ProtoReflect() protoreflect.Message {
+func (x *MapSnapshot) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_msgTypes[160]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -9857,76 +10039,106 @@ func (x *SharedPollPublishRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SharedPollPublishRequest.ProtoReflect.Descriptor instead.
-func (*SharedPollPublishRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use MapSnapshot.ProtoReflect.Descriptor instead.
+func (*MapSnapshot) Descriptor() ([]byte, []int) {
 	return file_api_proto_rawDescGZIP(), []int{160}
 }
 
-func (x *SharedPollPublishRequest) GetChannel() string {
+func (x *MapSnapshot) GetChannel() string {
 	if x != nil {
 		return x.Channel
 	}
 	return ""
 }
 
-func (x *SharedPollPublishRequest) GetKey() string {
+func (x *MapSnapshot) GetOffset() uint64 {
 	if x != nil {
-		return x.Key
+		return x.Offset
 	}
-	return ""
+	return 0
 }
 
-func (x *SharedPollPublishRequest) GetData() []byte {
+func (x *MapSnapshot) GetEpoch() string {
 	if x != nil {
-		return x.Data
+		return x.Epoch
 	}
-	return nil
+	return ""
 }
 
-func (x *SharedPollPublishRequest) GetB64Data() string {
+func (x *MapSnapshot) GetEntries() []*MapEntry {
 	if x != nil {
-		return x.B64Data
+		return x.Entries
 	}
-	return ""
+	return nil
 }
 
-func (x *SharedPollPublishRequest) GetVersion() uint64 {
+type MapExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Channel       string                 `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MapExportRequest) Reset() {
+	*x = MapExportRequest{}
+	mi := &file_api_proto_msgTypes[161]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MapExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MapExportRequest) ProtoMessage() {}
+
+func (x *MapExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[161]
 	if x != nil {
-		return x.Version
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *SharedPollPublishRequest) GetEpoch() string {
+// Deprecated: Use MapExportRequest.ProtoReflect.Descriptor instead.
+func (*MapExportRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{161}
+}
+
+func (x *MapExportRequest) GetChannel() string {
 	if x != nil {
-		return x.Epoch
+		return x.Channel
 	}
 	return ""
 }
 
-type SharedPollPublishResponse struct {
-	state         protoimpl.MessageState   `protogen:"open.v1"`
-	Error         *Error                   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
-	Result        *SharedPollPublishResult `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+type MapExportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         *Error                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Result        *MapExportResult       `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SharedPollPublishResponse) Reset() {
-	*x = SharedPollPublishResponse{}
-	mi := &file_api_proto_msgTypes[161]
+func (x *MapExportResponse) Reset() {
+	*x = MapExportResponse{}
+	mi := &file_api_proto_msgTypes[162]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SharedPollPublishResponse) String() string {
+func (x *MapExportResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SharedPollPublishResponse) ProtoMessage() {}
+func (*MapExportResponse) ProtoMessage() {}
 
-func (x *SharedPollPublishResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[161]
+func (x *MapExportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[162]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9937,46 +10149,47 @@ func (x *SharedPollPublishResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SharedPollPublishResponse.ProtoReflect.Descriptor instead.
-func (*SharedPollPublishResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{161}
+// Deprecated: Use MapExportResponse.ProtoReflect.Descriptor instead.
+func (*MapExportResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{162}
 }
 
-func (x *SharedPollPublishResponse) GetError() *Error {
+func (x *MapExportResponse) GetError() *Error {
 	if x != nil {
 		return x.Error
 	}
 	return nil
 }
 
-func (x *SharedPollPublishResponse) GetResult() *SharedPollPublishResult {
+func (x *MapExportResponse) GetResult() *MapExportResult {
 	if x != nil {
 		return x.Result
 	}
 	return nil
 }
 
-type SharedPollPublishResult struct {
+type MapExportResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Snapshot      *MapSnapshot           `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SharedPollPublishResult) Reset() {
-	*x = SharedPollPublishResult{}
-	mi := &file_api_proto_msgTypes[162]
+func (x *MapExportResult) Reset() {
+	*x = MapExportResult{}
+	mi := &file_api_proto_msgTypes[163]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SharedPollPublishResult) String() string {
+func (x *MapExportResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SharedPollPublishResult) ProtoMessage() {}
+func (*MapExportResult) ProtoMessage() {}
 
-func (x *SharedPollPublishResult) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[162]
+func (x *MapExportResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[163]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9987,51 +10200,798 @@ func (x *SharedPollPublishResult) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SharedPollPublishResult.ProtoReflect.Descriptor instead.
-func (*SharedPollPublishResult) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{162}
+// Deprecated: Use MapExportResult.ProtoReflect.Descriptor instead.
+func (*MapExportResult) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{163}
 }
 
-var File_api_proto protoreflect.FileDescriptor
+func (x *MapExportResult) GetSnapshot() *MapSnapshot {
+	if x != nil {
+		return x.Snapshot
+	}
+	return nil
+}
 
-const file_api_proto_rawDesc = "" +
-	"\n" +
-	"\tapi.proto\x12\x1acentrifugal.centrifugo.api\"\xf7\x19\n" +
-	"\aCommand\x12D\n" +
-	"\apublish\x18\x04 \x01(\v2*.centrifugal.centrifugo.api.PublishRequestR\apublish\x12J\n" +
-	"\tbroadcast\x18\x05 \x01(\v2,.centrifugal.centrifugo.api.BroadcastRequestR\tbroadcast\x12J\n" +
-	"\tsubscribe\x18\x06 \x01(\v2,.centrifugal.centrifugo.api.SubscribeRequestR\tsubscribe\x12P\n" +
-	"\vunsubscribe\x18\a \x01(\v2..centrifugal.centrifugo.api.UnsubscribeRequestR\vunsubscribe\x12M\n" +
-	"\n" +
-	"disconnect\x18\b \x01(\v2-.centrifugal.centrifugo.api.DisconnectRequestR\n" +
-	"disconnect\x12G\n" +
-	"\bpresence\x18\t \x01(\v2+.centrifugal.centrifugo.api.PresenceRequestR\bpresence\x12W\n" +
-	"\x0epresence_stats\x18\n" +
-	" \x01(\v20.centrifugal.centrifugo.api.PresenceStatsRequestR\rpresenceStats\x12D\n" +
-	"\ahistory\x18\v \x01(\v2*.centrifugal.centrifugo.api.HistoryRequestR\ahistory\x12W\n" +
-	"\x0ehistory_remove\x18\f \x01(\v20.centrifugal.centrifugo.api.HistoryRemoveRequestR\rhistoryRemove\x12;\n" +
-	"\x04info\x18\r \x01(\v2'.centrifugal.centrifugo.api.InfoRequestR\x04info\x128\n" +
-	"\x03rpc\x18\x0e \x01(\v2&.centrifugal.centrifugo.api.RPCRequestR\x03rpc\x12D\n" +
-	"\arefresh\x18\x0f \x01(\v2*.centrifugal.centrifugo.api.RefreshRequestR\arefresh\x12G\n" +
-	"\bchannels\x18\x10 \x01(\v2+.centrifugal.centrifugo.api.ChannelsRequestR\bchannels\x12P\n" +
-	"\vconnections\x18\x11 \x01(\v2..centrifugal.centrifugo.api.ConnectionsRequestR\vconnections\x12a\n" +
-	"\x12update_user_status\x18\x12 \x01(\v23.centrifugal.centrifugo.api.UpdateUserStatusRequestR\x10updateUserStatus\x12X\n" +
-	"\x0fget_user_status\x18\x13 \x01(\v20.centrifugal.centrifugo.api.GetUserStatusRequestR\rgetUserStatus\x12a\n" +
-	"\x12delete_user_status\x18\x14 \x01(\v23.centrifugal.centrifugo.api.DeleteUserStatusRequestR\x10deleteUserStatus\x12K\n" +
-	"\n" +
-	"block_user\x18\x15 \x01(\v2,.centrifugal.centrifugo.api.BlockUserRequestR\tblockUser\x12Q\n" +
-	"\funblock_user\x18\x16 \x01(\v2..centrifugal.centrifugo.api.UnblockUserRequestR\vunblockUser\x12Q\n" +
-	"\frevoke_token\x18\x17 \x01(\v2..centrifugal.centrifugo.api.RevokeTokenRequestR\vrevokeToken\x12m\n" +
-	"\x16invalidate_user_tokens\x18\x18 \x01(\v27.centrifugal.centrifugo.api.InvalidateUserTokensRequestR\x14invalidateUserTokens\x12Z\n" +
-	"\x0fdevice_register\x18\x19 \x01(\v21.centrifugal.centrifugo.api.DeviceRegisterRequestR\x0edeviceRegister\x12T\n" +
-	"\rdevice_update\x18\x1a \x01(\v2/.centrifugal.centrifugo.api.DeviceUpdateRequestR\fdeviceUpdate\x12T\n" +
-	"\rdevice_remove\x18\x1b \x01(\v2/.centrifugal.centrifugo.api.DeviceRemoveRequestR\fdeviceRemove\x12N\n" +
-	"\vdevice_list\x18\x1c \x01(\v2-.centrifugal.centrifugo.api.DeviceListRequestR\n" +
-	"deviceList\x12^\n" +
-	"\x11device_topic_list\x18\x1d \x01(\v22.centrifugal.centrifugo.api.DeviceTopicListRequestR\x0fdeviceTopicList\x12d\n" +
-	"\x13device_topic_update\x18\x1e \x01(\v24.centrifugal.centrifugo.api.DeviceTopicUpdateRequestR\x11deviceTopicUpdate\x12X\n" +
-	"\x0fuser_topic_list\x18\x1f \x01(\v20.centrifugal.centrifugo.api.UserTopicListRequestR\ruserTopicList\x12^\n" +
-	"\x11user_topic_update\x18  \x01(\v22.centrifugal.centrifugo.api.UserTopicUpdateRequestR\x0fuserTopicUpdate\x12m\n" +
+type MapImportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Snapshot      *MapSnapshot           `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	Replace       bool                   `protobuf:"varint,2,opt,name=replace,proto3" json:"replace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MapImportRequest) Reset() {
+	*x = MapImportRequest{}
+	mi := &file_api_proto_msgTypes[164]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MapImportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MapImportRequest) ProtoMessage() {}
+
+func (x *MapImportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[164]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MapImportRequest.ProtoReflect.Descriptor instead.
+func (*MapImportRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{164}
+}
+
+func (x *MapImportRequest) GetSnapshot() *MapSnapshot {
+	if x != nil {
+		return x.Snapshot
+	}
+	return nil
+}
+
+func (x *MapImportRequest) GetReplace() bool {
+	if x != nil {
+		return x.Replace
+	}
+	return false
+}
+
+type MapImportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         *Error                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Result        *MapImportResult       `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MapImportResponse) Reset() {
+	*x = MapImportResponse{}
+	mi := &file_api_proto_msgTypes[165]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MapImportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MapImportResponse) ProtoMessage() {}
+
+func (x *MapImportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[165]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MapImportResponse.ProtoReflect.Descriptor instead.
+func (*MapImportResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{165}
+}
+
+func (x *MapImportResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *MapImportResponse) GetResult() *MapImportResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type MapImportResult struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Offset            uint64                 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	Epoch             string                 `protobuf:"bytes,2,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	PositionPreserved bool                   `protobuf:"varint,3,opt,name=position_preserved,json=positionPreserved,proto3" json:"position_preserved,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *MapImportResult) Reset() {
+	*x = MapImportResult{}
+	mi := &file_api_proto_msgTypes[166]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MapImportResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MapImportResult) ProtoMessage() {}
+
+func (x *MapImportResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[166]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MapImportResult.ProtoReflect.Descriptor instead.
+func (*MapImportResult) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{166}
+}
+
+func (x *MapImportResult) GetOffset() uint64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *MapImportResult) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
+
+func (x *MapImportResult) GetPositionPreserved() bool {
+	if x != nil {
+		return x.PositionPreserved
+	}
+	return false
+}
+
+type SharedPollPublishRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Channel       string                 `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Data          Raw                    `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	B64Data       string                 `protobuf:"bytes,4,opt,name=b64data,proto3" json:"b64data,omitempty"`
+	Version       uint64                 `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	Epoch         string                 `protobuf:"bytes,6,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SharedPollPublishRequest) Reset() {
+	*x = SharedPollPublishRequest{}
+	mi := &file_api_proto_msgTypes[167]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SharedPollPublishRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SharedPollPublishRequest) ProtoMessage() {}
+
+func (x *SharedPollPublishRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[167]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SharedPollPublishRequest.ProtoReflect.Descriptor instead.
+func (*SharedPollPublishRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{167}
+}
+
+func (x *SharedPollPublishRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *SharedPollPublishRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *SharedPollPublishRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *SharedPollPublishRequest) GetB64Data() string {
+	if x != nil {
+		return x.B64Data
+	}
+	return ""
+}
+
+func (x *SharedPollPublishRequest) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *SharedPollPublishRequest) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
+
+type SharedPollPublishResponse struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Error         *Error                   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Result        *SharedPollPublishResult `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SharedPollPublishResponse) Reset() {
+	*x = SharedPollPublishResponse{}
+	mi := &file_api_proto_msgTypes[168]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SharedPollPublishResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SharedPollPublishResponse) ProtoMessage() {}
+
+func (x *SharedPollPublishResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[168]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SharedPollPublishResponse.ProtoReflect.Descriptor instead.
+func (*SharedPollPublishResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{168}
+}
+
+func (x *SharedPollPublishResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *SharedPollPublishResponse) GetResult() *SharedPollPublishResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type SharedPollPublishResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SharedPollPublishResult) Reset() {
+	*x = SharedPollPublishResult{}
+	mi := &file_api_proto_msgTypes[169]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SharedPollPublishResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SharedPollPublishResult) ProtoMessage() {}
+
+func (x *SharedPollPublishResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[169]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SharedPollPublishResult.ProtoReflect.Descriptor instead.
+func (*SharedPollPublishResult) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{169}
+}
+
+type ScheduledPublication struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Method         string                 `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"` // publish | broadcast
+	Channels       []string               `protobuf:"bytes,3,rep,name=channels,proto3" json:"channels,omitempty"`
+	Data           Raw                    `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	Tags           map[string]string      `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	PublishAt      int64                  `protobuf:"varint,6,opt,name=publish_at,json=publishAt,proto3" json:"publish_at,omitempty"` // Unix milliseconds.
+	CreatedAt      int64                  `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Unix milliseconds.
+	IdempotencyKey string                 `protobuf:"bytes,8,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	B64Data        string                 `protobuf:"bytes,9,opt,name=b64data,proto3" json:"b64data,omitempty"` // set instead of data when publication was sent with b64data.
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ScheduledPublication) Reset() {
+	*x = ScheduledPublication{}
+	mi := &file_api_proto_msgTypes[170]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledPublication) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledPublication) ProtoMessage() {}
+
+func (x *ScheduledPublication) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[170]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledPublication.ProtoReflect.Descriptor instead.
+func (*ScheduledPublication) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{170}
+}
+
+func (x *ScheduledPublication) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ScheduledPublication) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *ScheduledPublication) GetChannels() []string {
+	if x != nil {
+		return x.Channels
+	}
+	return nil
+}
+
+func (x *ScheduledPublication) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ScheduledPublication) GetTags() map[string]string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ScheduledPublication) GetPublishAt() int64 {
+	if x != nil {
+		return x.PublishAt
+	}
+	return 0
+}
+
+func (x *ScheduledPublication) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *ScheduledPublication) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *ScheduledPublication) GetB64Data() string {
+	if x != nil {
+		return x.B64Data
+	}
+	return ""
+}
+
+type ScheduledListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Channel       string                 `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"` // optional, return only publications scheduled to this channel.
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor        string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"` // next_cursor from previous response to continue listing.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduledListRequest) Reset() {
+	*x = ScheduledListRequest{}
+	mi := &file_api_proto_msgTypes[171]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledListRequest) ProtoMessage() {}
+
+func (x *ScheduledListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[171]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledListRequest.ProtoReflect.Descriptor instead.
+func (*ScheduledListRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{171}
+}
+
+func (x *ScheduledListRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *ScheduledListRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ScheduledListRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type ScheduledListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         *Error                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Result        *ScheduledListResult   `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduledListResponse) Reset() {
+	*x = ScheduledListResponse{}
+	mi := &file_api_proto_msgTypes[172]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledListResponse) ProtoMessage() {}
+
+func (x *ScheduledListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[172]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledListResponse.ProtoReflect.Descriptor instead.
+func (*ScheduledListResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{172}
+}
+
+func (x *ScheduledListResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *ScheduledListResponse) GetResult() *ScheduledListResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type ScheduledListResult struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Publications  []*ScheduledPublication `protobuf:"bytes,1,rep,name=publications,proto3" json:"publications,omitempty"`
+	NextCursor    string                  `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduledListResult) Reset() {
+	*x = ScheduledListResult{}
+	mi := &file_api_proto_msgTypes[173]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledListResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledListResult) ProtoMessage() {}
+
+func (x *ScheduledListResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[173]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledListResult.ProtoReflect.Descriptor instead.
+func (*ScheduledListResult) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{173}
+}
+
+func (x *ScheduledListResult) GetPublications() []*ScheduledPublication {
+	if x != nil {
+		return x.Publications
+	}
+	return nil
+}
+
+func (x *ScheduledListResult) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type ScheduledCancelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduledCancelRequest) Reset() {
+	*x = ScheduledCancelRequest{}
+	mi := &file_api_proto_msgTypes[174]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledCancelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledCancelRequest) ProtoMessage() {}
+
+func (x *ScheduledCancelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[174]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledCancelRequest.ProtoReflect.Descriptor instead.
+func (*ScheduledCancelRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{174}
+}
+
+func (x *ScheduledCancelRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ScheduledCancelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         *Error                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Result        *ScheduledCancelResult `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduledCancelResponse) Reset() {
+	*x = ScheduledCancelResponse{}
+	mi := &file_api_proto_msgTypes[175]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledCancelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledCancelResponse) ProtoMessage() {}
+
+func (x *ScheduledCancelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[175]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledCancelResponse.ProtoReflect.Descriptor instead.
+func (*ScheduledCancelResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{175}
+}
+
+func (x *ScheduledCancelResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *ScheduledCancelResponse) GetResult() *ScheduledCancelResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type ScheduledCancelResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduledCancelResult) Reset() {
+	*x = ScheduledCancelResult{}
+	mi := &file_api_proto_msgTypes[176]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledCancelResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledCancelResult) ProtoMessage() {}
+
+func (x *ScheduledCancelResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[176]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledCancelResult.ProtoReflect.Descriptor instead.
+func (*ScheduledCancelResult) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{176}
+}
+
+var File_api_proto protoreflect.FileDescriptor
+
+const file_api_proto_rawDesc = "" +
+	"\n" +
+	"\tapi.proto\x12\x1acentrifugal.centrifugo.api\"\xc9\x1c\n" +
+	"\aCommand\x12D\n" +
+	"\apublish\x18\x04 \x01(\v2*.centrifugal.centrifugo.api.PublishRequestR\apublish\x12J\n" +
+	"\tbroadcast\x18\x05 \x01(\v2,.centrifugal.centrifugo.api.BroadcastRequestR\tbroadcast\x12J\n" +
+	"\tsubscribe\x18\x06 \x01(\v2,.centrifugal.centrifugo.api.SubscribeRequestR\tsubscribe\x12P\n" +
+	"\vunsubscribe\x18\a \x01(\v2..centrifugal.centrifugo.api.UnsubscribeRequestR\vunsubscribe\x12M\n" +
+	"\n" +
+	"disconnect\x18\b \x01(\v2-.centrifugal.centrifugo.api.DisconnectRequestR\n" +
+	"disconnect\x12G\n" +
+	"\bpresence\x18\t \x01(\v2+.centrifugal.centrifugo.api.PresenceRequestR\bpresence\x12W\n" +
+	"\x0epresence_stats\x18\n" +
+	" \x01(\v20.centrifugal.centrifugo.api.PresenceStatsRequestR\rpresenceStats\x12D\n" +
+	"\ahistory\x18\v \x01(\v2*.centrifugal.centrifugo.api.HistoryRequestR\ahistory\x12W\n" +
+	"\x0ehistory_remove\x18\f \x01(\v20.centrifugal.centrifugo.api.HistoryRemoveRequestR\rhistoryRemove\x12;\n" +
+	"\x04info\x18\r \x01(\v2'.centrifugal.centrifugo.api.InfoRequestR\x04info\x128\n" +
+	"\x03rpc\x18\x0e \x01(\v2&.centrifugal.centrifugo.api.RPCRequestR\x03rpc\x12D\n" +
+	"\arefresh\x18\x0f \x01(\v2*.centrifugal.centrifugo.api.RefreshRequestR\arefresh\x12G\n" +
+	"\bchannels\x18\x10 \x01(\v2+.centrifugal.centrifugo.api.ChannelsRequestR\bchannels\x12P\n" +
+	"\vconnections\x18\x11 \x01(\v2..centrifugal.centrifugo.api.ConnectionsRequestR\vconnections\x12a\n" +
+	"\x12update_user_status\x18\x12 \x01(\v23.centrifugal.centrifugo.api.UpdateUserStatusRequestR\x10updateUserStatus\x12X\n" +
+	"\x0fget_user_status\x18\x13 \x01(\v20.centrifugal.centrifugo.api.GetUserStatusRequestR\rgetUserStatus\x12a\n" +
+	"\x12delete_user_status\x18\x14 \x01(\v23.centrifugal.centrifugo.api.DeleteUserStatusRequestR\x10deleteUserStatus\x12K\n" +
+	"\n" +
+	"block_user\x18\x15 \x01(\v2,.centrifugal.centrifugo.api.BlockUserRequestR\tblockUser\x12Q\n" +
+	"\funblock_user\x18\x16 \x01(\v2..centrifugal.centrifugo.api.UnblockUserRequestR\vunblockUser\x12Q\n" +
+	"\frevoke_token\x18\x17 \x01(\v2..centrifugal.centrifugo.api.RevokeTokenRequestR\vrevokeToken\x12m\n" +
+	"\x16invalidate_user_tokens\x18\x18 \x01(\v27.centrifugal.centrifugo.api.InvalidateUserTokensRequestR\x14invalidateUserTokens\x12Z\n" +
+	"\x0fdevice_register\x18\x19 \x01(\v21.centrifugal.centrifugo.api.DeviceRegisterRequestR\x0edeviceRegister\x12T\n" +
+	"\rdevice_update\x18\x1a \x01(\v2/.centrifugal.centrifugo.api.DeviceUpdateRequestR\fdeviceUpdate\x12T\n" +
+	"\rdevice_remove\x18\x1b \x01(\v2/.centrifugal.centrifugo.api.DeviceRemoveRequestR\fdeviceRemove\x12N\n" +
+	"\vdevice_list\x18\x1c \x01(\v2-.centrifugal.centrifugo.api.DeviceListRequestR\n" +
+	"deviceList\x12^\n" +
+	"\x11device_topic_list\x18\x1d \x01(\v22.centrifugal.centrifugo.api.DeviceTopicListRequestR\x0fdeviceTopicList\x12d\n" +
+	"\x13device_topic_update\x18\x1e \x01(\v24.centrifugal.centrifugo.api.DeviceTopicUpdateRequestR\x11deviceTopicUpdate\x12X\n" +
+	"\x0fuser_topic_list\x18\x1f \x01(\v20.centrifugal.centrifugo.api.UserTopicListRequestR\ruserTopicList\x12^\n" +
+	"\x11user_topic_update\x18  \x01(\v22.centrifugal.centrifugo.api.UserTopicUpdateRequestR\x0fuserTopicUpdate\x12m\n" +
 	"\x16send_push_notification\x18! \x01(\v27.centrifugal.centrifugo.api.SendPushNotificationRequestR\x14sendPushNotification\x12a\n" +
 	"\x12update_push_status\x18\" \x01(\v23.centrifugal.centrifugo.api.UpdatePushStatusRequestR\x10updatePushStatus\x12N\n" +
 	"\vcancel_push\x18# \x01(\v2-.centrifugal.centrifugo.api.CancelPushRequestR\n" +
@@ -10044,10 +11004,16 @@ const file_api_proto_rawDesc = "" +
 	"\x0fmap_read_stream\x18' \x01(\v20.centrifugal.centrifugo.api.MapReadStreamRequestR\rmapReadStream\x12H\n" +
 	"\tmap_stats\x18( \x01(\v2+.centrifugal.centrifugo.api.MapStatsRequestR\bmapStats\x12H\n" +
 	"\tmap_clear\x18) \x01(\v2+.centrifugal.centrifugo.api.MapClearRequestR\bmapClear\x12d\n" +
-	"\x13shared_poll_publish\x18* \x01(\v24.centrifugal.centrifugo.api.SharedPollPublishRequestR\x11sharedPollPublishJ\x04\b\x01\x10\x02J\x04\b\x02\x10\x03J\x04\b\x03\x10\x04\"5\n" +
+	"\x13shared_poll_publish\x18* \x01(\v24.centrifugal.centrifugo.api.SharedPollPublishRequestR\x11sharedPollPublish\x12K\n" +
+	"\n" +
+	"map_export\x18+ \x01(\v2,.centrifugal.centrifugo.api.MapExportRequestR\tmapExport\x12K\n" +
+	"\n" +
+	"map_import\x18, \x01(\v2,.centrifugal.centrifugo.api.MapImportRequestR\tmapImport\x12W\n" +
+	"\x0escheduled_list\x18- \x01(\v20.centrifugal.centrifugo.api.ScheduledListRequestR\rscheduledList\x12]\n" +
+	"\x10scheduled_cancel\x18. \x01(\v22.centrifugal.centrifugo.api.ScheduledCancelRequestR\x0fscheduledCancelJ\x04\b\x01\x10\x02J\x04\b\x02\x10\x03J\x04\b\x03\x10\x04\"5\n" +
 	"\x05Error\x12\x12\n" +
 	"\x04code\x18\x01 \x01(\rR\x04code\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"\x81\x1a\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xcf\x1c\n" +
 	"\x05Reply\x127\n" +
 	"\x05error\x18\x02 \x01(\v2!.centrifugal.centrifugo.api.ErrorR\x05error\x12C\n" +
 	"\apublish\x18\x04 \x01(\v2).centrifugal.centrifugo.api.PublishResultR\apublish\x12I\n" +
@@ -10096,12 +11062,18 @@ const file_api_proto_rawDesc = "" +
 	"\x0fmap_read_stream\x18' \x01(\v2/.centrifugal.centrifugo.api.MapReadStreamResultR\rmapReadStream\x12G\n" +
 	"\tmap_stats\x18( \x01(\v2*.centrifugal.centrifugo.api.MapStatsResultR\bmapStats\x12G\n" +
 	"\tmap_clear\x18) \x01(\v2*.centrifugal.centrifugo.api.MapClearResultR\bmapClear\x12c\n" +
-	"\x13shared_poll_publish\x18* \x01(\v23.centrifugal.centrifugo.api.SharedPollPublishResultR\x11sharedPollPublishJ\x04\b\x01\x10\x02J\x04\b\x03\x10\x04\"k\n" +
+	"\x13shared_poll_publish\x18* \x01(\v23.centrifugal.centrifugo.api.SharedPollPublishResultR\x11sharedPollPublish\x12J\n" +
+	"\n" +
+	"map_export\x18+ \x01(\v2+.centrifugal.centrifugo.api.MapExportResultR\tmapExport\x12J\n" +
+	"\n" +
+	"map_import\x18, \x01(\v2+.centrifugal.centrifugo.api.MapImportResultR\tmapImport\x12V\n" +
+	"\x0escheduled_list\x18- \x01(\v2/.centrifugal.centrifugo.api.ScheduledListResultR\rscheduledList\x12\\\n" +
+	"\x10scheduled_cancel\x18. \x01(\v21.centrifugal.centrifugo.api.ScheduledCancelResultR\x0fscheduledCancelJ\x04\b\x01\x10\x02J\x04\b\x03\x10\x04\"k\n" +
 	"\fBatchRequest\x12?\n" +
 	"\bcommands\x18\x01 \x03(\v2#.centrifugal.centrifugo.api.CommandR\bcommands\x12\x1a\n" +
 	"\bparallel\x18\x02 \x01(\bR\bparallel\"L\n" +
 	"\rBatchResponse\x12;\n" +
-	"\areplies\x18\x01 \x03(\v2!.centrifugal.centrifugo.api.ReplyR\areplies\"\xfc\x02\n" +
+	"\areplies\x18\x01 \x03(\v2!.centrifugal.centrifugo.api.ReplyR\areplies\"\xb1\x03\n" +
 	"\x0ePublishRequest\x12\x18\n" +
 	"\achannel\x18\x01 \x01(\tR\achannel\x12\x12\n" +
 	"\x04data\x18\x02 \x01(\fR\x04data\x12\x18\n" +
@@ -10111,16 +11083,23 @@ const file_api_proto_rawDesc = "" +
 	"\x0fidempotency_key\x18\x06 \x01(\tR\x0eidempotencyKey\x12\x14\n" +
 	"\x05delta\x18\a \x01(\bR\x05delta\x12\x18\n" +
 	"\aversion\x18\b \x01(\x04R\aversion\x12#\n" +
-	"\rversion_epoch\x18\t \x01(\tR\fversionEpoch\x1a7\n" +
+	"\rversion_epoch\x18\t \x01(\tR\fversionEpoch\x12\x1d\n" +
+	"\n" +
+	"publish_at\x18\n" +
+	" \x01(\x03R\tpublishAt\x12\x14\n" +
+	"\x05delay\x18\v \x01(\x03R\x05delay\x1a7\n" +
 	"\tTagsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x8d\x01\n" +
 	"\x0fPublishResponse\x127\n" +
 	"\x05error\x18\x01 \x01(\v2!.centrifugal.centrifugo.api.ErrorR\x05error\x12A\n" +
-	"\x06result\x18\x02 \x01(\v2).centrifugal.centrifugo.api.PublishResultR\x06result\"=\n" +
+	"\x06result\x18\x02 \x01(\v2).centrifugal.centrifugo.api.PublishResultR\x06result\"z\n" +
 	"\rPublishResult\x12\x16\n" +
 	"\x06offset\x18\x01 \x01(\x04R\x06offset\x12\x14\n" +
-	"\x05epoch\x18\x02 \x01(\tR\x05epoch\"\x82\x03\n" +
+	"\x05epoch\x18\x02 \x01(\tR\x05epoch\x12\x1f\n" +
+	"\vschedule_id\x18\x03 \x01(\tR\n" +
+	"scheduleId\x12\x1a\n" +
+	"\bdeferred\x18\x04 \x01(\bR\bdeferred\"\xb7\x03\n" +
 	"\x10BroadcastRequest\x12\x1a\n" +
 	"\bchannels\x18\x01 \x03(\tR\bchannels\x12\x12\n" +
 	"\x04data\x18\x02 \x01(\fR\x04data\x12\x18\n" +
@@ -10130,15 +11109,21 @@ const file_api_proto_rawDesc = "" +
 	"\x0fidempotency_key\x18\x06 \x01(\tR\x0eidempotencyKey\x12\x14\n" +
 	"\x05delta\x18\a \x01(\bR\x05delta\x12\x18\n" +
 	"\aversion\x18\b \x01(\x04R\aversion\x12#\n" +
-	"\rversion_epoch\x18\t \x01(\tR\fversionEpoch\x1a7\n" +
+	"\rversion_epoch\x18\t \x01(\tR\fversionEpoch\x12\x1d\n" +
+	"\n" +
+	"publish_at\x18\n" +
+	" \x01(\x03R\tpublishAt\x12\x14\n" +
+	"\x05delay\x18\v \x01(\x03R\x05delay\x1a7\n" +
 	"\tTagsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x91\x01\n" +
 	"\x11BroadcastResponse\x127\n" +
 	"\x05error\x18\x01 \x01(\v2!.centrifugal.centrifugo.api.ErrorR\x05error\x12C\n" +
-	"\x06result\x18\x02 \x01(\v2+.centrifugal.centrifugo.api.BroadcastResultR\x06result\"\\\n" +
+	"\x06result\x18\x02 \x01(\v2+.centrifugal.centrifugo.api.BroadcastResultR\x06result\"}\n" +
 	"\x0fBroadcastResult\x12I\n" +
-	"\tresponses\x18\x01 \x03(\v2+.centrifugal.centrifugo.api.PublishResponseR\tresponses\"\xa4\x01\n" +
+	"\tresponses\x18\x01 \x03(\v2+.centrifugal.centrifugo.api.PublishResponseR\tresponses\x12\x1f\n" +
+	"\vschedule_id\x18\x02 \x01(\tR\n" +
+	"scheduleId\"\xa4\x01\n" +
 	"\n" +
 	"FilterNode\x12\x0e\n" +
 	"\x02op\x18\x01 \x01(\tR\x02op\x12\x10\n" +
@@ -10234,27 +11219,33 @@ const file_api_proto_rawDesc = "" +
 	"\tnum_users\x18\x02 \x01(\rR\bnumUsers\">\n" +
 	"\x0eStreamPosition\x12\x16\n" +
 	"\x06offset\x18\x01 \x01(\x04R\x06offset\x12\x14\n" +
-	"\x05epoch\x18\x02 \x01(\tR\x05epoch\"\x9c\x01\n" +
+	"\x05epoch\x18\x02 \x01(\tR\x05epoch\"\xea\x01\n" +
 	"\x0eHistoryRequest\x12\x18\n" +
 	"\achannel\x18\x01 \x01(\tR\achannel\x12\x14\n" +
 	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12@\n" +
 	"\x05since\x18\x03 \x01(\v2*.centrifugal.centrifugo.api.StreamPositionR\x05since\x12\x18\n" +
-	"\areverse\x18\x04 \x01(\bR\areverse\"\x8d\x01\n" +
+	"\areverse\x18\x04 \x01(\bR\areverse\x12\x1b\n" +
+	"\tfrom_time\x18\x05 \x01(\x03R\bfromTime\x12\x17\n" +
+	"\ato_time\x18\x06 \x01(\x03R\x06toTime\x12\x16\n" +
+	"\x06cursor\x18\a \x01(\tR\x06cursor\"\x8d\x01\n" +
 	"\x0fHistoryResponse\x127\n" +
 	"\x05error\x18\x01 \x01(\v2!.centrifugal.centrifugo.api.ErrorR\x05error\x12A\n" +
-	"\x06result\x18\x02 \x01(\v2).centrifugal.centrifugo.api.HistoryResultR\x06result\"\xf5\x01\n" +
+	"\x06result\x18\x02 \x01(\v2).centrifugal.centrifugo.api.HistoryResultR\x06result\"\x89\x02\n" +
 	"\vPublication\x12\x12\n" +
 	"\x04data\x18\x02 \x01(\fR\x04data\x12:\n" +
 	"\x04info\x18\x03 \x01(\v2&.centrifugal.centrifugo.api.ClientInfoR\x04info\x12\x16\n" +
 	"\x06offset\x18\x04 \x01(\x04R\x06offset\x12E\n" +
-	"\x04tags\x18\x05 \x03(\v21.centrifugal.centrifugo.api.Publication.TagsEntryR\x04tags\x1a7\n" +
+	"\x04tags\x18\x05 \x03(\v21.centrifugal.centrifugo.api.Publication.TagsEntryR\x04tags\x12\x12\n" +
+	"\x04time\x18\x06 \x01(\x03R\x04time\x1a7\n" +
 	"\tTagsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x8a\x01\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xab\x01\n" +
 	"\rHistoryResult\x12K\n" +
 	"\fpublications\x18\x01 \x03(\v2'.centrifugal.centrifugo.api.PublicationR\fpublications\x12\x14\n" +
 	"\x05epoch\x18\x02 \x01(\tR\x05epoch\x12\x16\n" +
-	"\x06offset\x18\x03 \x01(\x04R\x06offset\"0\n" +
+	"\x06offset\x18\x03 \x01(\x04R\x06offset\x12\x1f\n" +
+	"\vnext_cursor\x18\x04 \x01(\tR\n" +
+	"nextCursor\"0\n" +
 	"\x14HistoryRemoveRequest\x12\x18\n" +
 	"\achannel\x18\x01 \x01(\tR\achannel\"\x99\x01\n" +
 	"\x15HistoryRemoveResponse\x127\n" +
@@ -10728,7 +11719,7 @@ const file_api_proto_rawDesc = "" +
 	"\n" +
 	"suppressed\x18\x03 \x01(\bR\n" +
 	"suppressed\x12'\n" +
-	"\x0fsuppress_reason\x18\x04 \x01(\tR\x0esuppressReason\"\xd1\x01\n" +
+	"\x0fsuppress_reason\x18\x04 \x01(\tR\x0esuppressReason\"\xfd\x01\n" +
 	"\x13MapReadStateRequest\x12\x18\n" +
 	"\achannel\x18\x01 \x01(\tR\achannel\x12\x16\n" +
 	"\x06cursor\x18\x02 \x01(\tR\x06cursor\x12\x14\n" +
@@ -10736,7 +11727,9 @@ const file_api_proto_rawDesc = "" +
 	"\x03key\x18\x04 \x01(\tR\x03key\x12\x10\n" +
 	"\x03asc\x18\x05 \x01(\bR\x03asc\x12'\n" +
 	"\x0frevision_offset\x18\x06 \x01(\x04R\x0erevisionOffset\x12%\n" +
-	"\x0erevision_epoch\x18\a \x01(\tR\rrevisionEpoch\"\x97\x01\n" +
+	"\x0erevision_epoch\x18\a \x01(\tR\rrevisionEpoch\x12\x16\n" +
+	"\x06filter\x18\b \x01(\tR\x06filter\x12\x12\n" +
+	"\x04sort\x18\t \x01(\tR\x04sort\"\x97\x01\n" +
 	"\x14MapReadStateResponse\x127\n" +
 	"\x05error\x18\x01 \x01(\v2!.centrifugal.centrifugo.api.ErrorR\x05error\x12F\n" +
 	"\x06result\x18\x02 \x01(\v2..centrifugal.centrifugo.api.MapReadStateResultR\x06result\"\x9a\x01\n" +
@@ -10744,7 +11737,7 @@ const file_api_proto_rawDesc = "" +
 	"\aentries\x18\x01 \x03(\v2$.centrifugal.centrifugo.api.MapEntryR\aentries\x12\x16\n" +
 	"\x06offset\x18\x02 \x01(\x04R\x06offset\x12\x14\n" +
 	"\x05epoch\x18\x03 \x01(\tR\x05epoch\x12\x16\n" +
-	"\x06cursor\x18\x04 \x01(\tR\x06cursor\"\x89\x02\n" +
+	"\x06cursor\x18\x04 \x01(\tR\x06cursor\"\xc5\x02\n" +
 	"\bMapEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x12\n" +
 	"\x04data\x18\x02 \x01(\fR\x04data\x12B\n" +
@@ -10752,7 +11745,8 @@ const file_api_proto_rawDesc = "" +
 	"\x06offset\x18\x04 \x01(\x04R\x06offset\x12\x14\n" +
 	"\x05score\x18\x05 \x01(\x03R\x05score\x12\x18\n" +
 	"\aremoved\x18\x06 \x01(\bR\aremoved\x12\x12\n" +
-	"\x04time\x18\a \x01(\x03R\x04time\x1a7\n" +
+	"\x04time\x18\a \x01(\x03R\x04time\x12:\n" +
+	"\x04info\x18\b \x01(\v2&.centrifugal.centrifugo.api.ClientInfoR\x04info\x1a7\n" +
 	"\tTagsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa4\x01\n" +
@@ -10782,7 +11776,29 @@ const file_api_proto_rawDesc = "" +
 	"\x10MapClearResponse\x127\n" +
 	"\x05error\x18\x01 \x01(\v2!.centrifugal.centrifugo.api.ErrorR\x05error\x12B\n" +
 	"\x06result\x18\x02 \x01(\v2*.centrifugal.centrifugo.api.MapClearResultR\x06result\"\x10\n" +
-	"\x0eMapClearResult\"\xa4\x01\n" +
+	"\x0eMapClearResult\"\x95\x01\n" +
+	"\vMapSnapshot\x12\x18\n" +
+	"\achannel\x18\x01 \x01(\tR\achannel\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x04R\x06offset\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\tR\x05epoch\x12>\n" +
+	"\aentries\x18\x04 \x03(\v2$.centrifugal.centrifugo.api.MapEntryR\aentries\",\n" +
+	"\x10MapExportRequest\x12\x18\n" +
+	"\achannel\x18\x01 \x01(\tR\achannel\"\x91\x01\n" +
+	"\x11MapExportResponse\x127\n" +
+	"\x05error\x18\x01 \x01(\v2!.centrifugal.centrifugo.api.ErrorR\x05error\x12C\n" +
+	"\x06result\x18\x02 \x01(\v2+.centrifugal.centrifugo.api.MapExportResultR\x06result\"V\n" +
+	"\x0fMapExportResult\x12C\n" +
+	"\bsnapshot\x18\x01 \x01(\v2'.centrifugal.centrifugo.api.MapSnapshotR\bsnapshot\"q\n" +
+	"\x10MapImportRequest\x12C\n" +
+	"\bsnapshot\x18\x01 \x01(\v2'.centrifugal.centrifugo.api.MapSnapshotR\bsnapshot\x12\x18\n" +
+	"\areplace\x18\x02 \x01(\bR\areplace\"\x91\x01\n" +
+	"\x11MapImportResponse\x127\n" +
+	"\x05error\x18\x01 \x01(\v2!.centrifugal.centrifugo.api.ErrorR\x05error\x12C\n" +
+	"\x06result\x18\x02 \x01(\v2+.centrifugal.centrifugo.api.MapImportResultR\x06result\"n\n" +
+	"\x0fMapImportResult\x12\x16\n" +
+	"\x06offset\x18\x01 \x01(\x04R\x06offset\x12\x14\n" +
+	"\x05epoch\x18\x02 \x01(\tR\x05epoch\x12-\n" +
+	"\x12position_preserved\x18\x03 \x01(\bR\x11positionPreserved\"\xa4\x01\n" +
 	"\x18SharedPollPublishRequest\x12\x18\n" +
 	"\achannel\x18\x01 \x01(\tR\achannel\x12\x10\n" +
 	"\x03key\x18\x02 \x01(\tR\x03key\x12\x12\n" +
@@ -10793,7 +11809,39 @@ const file_api_proto_rawDesc = "" +
 	"\x19SharedPollPublishResponse\x127\n" +
 	"\x05error\x18\x01 \x01(\v2!.centrifugal.centrifugo.api.ErrorR\x05error\x12K\n" +
 	"\x06result\x18\x02 \x01(\v23.centrifugal.centrifugo.api.SharedPollPublishResultR\x06result\"\x19\n" +
-	"\x17SharedPollPublishResult2\x87$\n" +
+	"\x17SharedPollPublishResult\"\xf8\x02\n" +
+	"\x14ScheduledPublication\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x12\x1a\n" +
+	"\bchannels\x18\x03 \x03(\tR\bchannels\x12\x12\n" +
+	"\x04data\x18\x04 \x01(\fR\x04data\x12N\n" +
+	"\x04tags\x18\x05 \x03(\v2:.centrifugal.centrifugo.api.ScheduledPublication.TagsEntryR\x04tags\x12\x1d\n" +
+	"\n" +
+	"publish_at\x18\x06 \x01(\x03R\tpublishAt\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\x03R\tcreatedAt\x12'\n" +
+	"\x0fidempotency_key\x18\b \x01(\tR\x0eidempotencyKey\x12\x18\n" +
+	"\ab64data\x18\t \x01(\tR\ab64data\x1a7\n" +
+	"\tTagsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"^\n" +
+	"\x14ScheduledListRequest\x12\x18\n" +
+	"\achannel\x18\x01 \x01(\tR\achannel\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"\x99\x01\n" +
+	"\x15ScheduledListResponse\x127\n" +
+	"\x05error\x18\x01 \x01(\v2!.centrifugal.centrifugo.api.ErrorR\x05error\x12G\n" +
+	"\x06result\x18\x02 \x01(\v2/.centrifugal.centrifugo.api.ScheduledListResultR\x06result\"\x8c\x01\n" +
+	"\x13ScheduledListResult\x12T\n" +
+	"\fpublications\x18\x01 \x03(\v20.centrifugal.centrifugo.api.ScheduledPublicationR\fpublications\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"(\n" +
+	"\x16ScheduledCancelRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x9d\x01\n" +
+	"\x17ScheduledCancelResponse\x127\n" +
+	"\x05error\x18\x01 \x01(\v2!.centrifugal.centrifugo.api.ErrorR\x05error\x12I\n" +
+	"\x06result\x18\x02 \x01(\v21.centrifugal.centrifugo.api.ScheduledCancelResultR\x06result\"\x17\n" +
+	"\x15ScheduledCancelResult2\xd5'\n" +
 	"\rCentrifugoApi\x12^\n" +
 	"\x05Batch\x12(.centrifugal.centrifugo.api.BatchRequest\x1a).centrifugal.centrifugo.api.BatchResponse\"\x00\x12d\n" +
 	"\aPublish\x12*.centrifugal.centrifugo.api.PublishRequest\x1a+.centrifugal.centrifugo.api.PublishResponse\"\x00\x12j\n" +
@@ -10838,7 +11886,11 @@ const file_api_proto_rawDesc = "" +
 	"\rMapReadStream\x120.centrifugal.centrifugo.api.MapReadStreamRequest\x1a1.centrifugal.centrifugo.api.MapReadStreamResponse\"\x00\x12g\n" +
 	"\bMapStats\x12+.centrifugal.centrifugo.api.MapStatsRequest\x1a,.centrifugal.centrifugo.api.MapStatsResponse\"\x00\x12g\n" +
 	"\bMapClear\x12+.centrifugal.centrifugo.api.MapClearRequest\x1a,.centrifugal.centrifugo.api.MapClearResponse\"\x00\x12\x82\x01\n" +
-	"\x11SharedPollPublish\x124.centrifugal.centrifugo.api.SharedPollPublishRequest\x1a5.centrifugal.centrifugo.api.SharedPollPublishResponse\"\x00B\rZ\v./;apiprotob\x06proto3"
+	"\x11SharedPollPublish\x124.centrifugal.centrifugo.api.SharedPollPublishRequest\x1a5.centrifugal.centrifugo.api.SharedPollPublishResponse\"\x00\x12j\n" +
+	"\tMapExport\x12,.centrifugal.centrifugo.api.MapExportRequest\x1a-.centrifugal.centrifugo.api.MapExportResponse\"\x00\x12j\n" +
+	"\tMapImport\x12,.centrifugal.centrifugo.api.MapImportRequest\x1a-.centrifugal.centrifugo.api.MapImportResponse\"\x00\x12v\n" +
+	"\rScheduledList\x120.centrifugal.centrifugo.api.ScheduledListRequest\x1a1.centrifugal.centrifugo.api.ScheduledListResponse\"\x00\x12|\n" +
+	"\x0fScheduledCancel\x122.centrifugal.centrifugo.api.ScheduledCancelRequest\x1a3.centrifugal.centrifugo.api.ScheduledCancelResponse\"\x00B\rZ\v./;apiprotob\x06proto3"
 
 var (
 	file_api_proto_rawDescOnce sync.Once
@@ -10852,7 +11904,7 @@ func file_api_proto_rawDescGZIP() []byte {
 	return file_api_proto_rawDescData
 }
 
-var file_api_proto_msgTypes = make([]protoimpl.MessageInfo, 182)
+var file_api_proto_msgTypes = make([]protoimpl.MessageInfo, 197)
 var file_api_proto_goTypes = []any{
 	(*Command)(nil),                      // 0: centrifugal.centrifugo.api.Command
 	(*Error)(nil),                        // 1: centrifugal.centrifugo.api.Error
@@ -11014,28 +12066,43 @@ var file_api_proto_goTypes = []any{
 	(*MapClearRequest)(nil),              // 157: centrifugal.centrifugo.api.MapClearRequest
 	(*MapClearResponse)(nil),             // 158: centrifugal.centrifugo.api.MapClearResponse
 	(*MapClearResult)(nil),               // 159: centrifugal.centrifugo.api.MapClearResult
-	(*SharedPollPublishRequest)(nil),     // 160: centrifugal.centrifugo.api.SharedPollPublishRequest
-	(*SharedPollPublishResponse)(nil),    // 161: centrifugal.centrifugo.api.SharedPollPublishResponse
-	(*SharedPollPublishResult)(nil),      // 162: centrifugal.centrifugo.api.SharedPollPublishResult
-	nil,                                  // 163: centrifugal.centrifugo.api.PublishRequest.TagsEntry
-	nil,                                  // 164: centrifugal.centrifugo.api.BroadcastRequest.TagsEntry
-	nil,                                  // 165: centrifugal.centrifugo.api.PresenceResult.PresenceEntry
-	nil,                                  // 166: centrifugal.centrifugo.api.Publication.TagsEntry
-	nil,                                  // 167: centrifugal.centrifugo.api.Metrics.ItemsEntry
-	nil,                                  // 168: centrifugal.centrifugo.api.ChannelsResult.ChannelsEntry
-	nil,                                  // 169: centrifugal.centrifugo.api.ConnectionsResult.ConnectionsEntry
-	nil,                                  // 170: centrifugal.centrifugo.api.ConnectionInfo.LabelsEntry
-	nil,                                  // 171: centrifugal.centrifugo.api.ConnectionState.ChannelsEntry
-	nil,                                  // 172: centrifugal.centrifugo.api.ConnectionState.SubscriptionTokensEntry
-	nil,                                  // 173: centrifugal.centrifugo.api.DeviceRegisterRequest.MetaEntry
-	nil,                                  // 174: centrifugal.centrifugo.api.DeviceMetaUpdate.MetaEntry
-	nil,                                  // 175: centrifugal.centrifugo.api.Device.MetaEntry
-	nil,                                  // 176: centrifugal.centrifugo.api.ApnsPushNotification.HeadersEntry
-	nil,                                  // 177: centrifugal.centrifugo.api.WebPushPushNotification.HeadersEntry
-	nil,                                  // 178: centrifugal.centrifugo.api.SendPushNotificationRequest.LocalizationsEntry
-	nil,                                  // 179: centrifugal.centrifugo.api.PushLocalization.TranslationsEntry
-	nil,                                  // 180: centrifugal.centrifugo.api.MapPublishRequest.TagsEntry
-	nil,                                  // 181: centrifugal.centrifugo.api.MapEntry.TagsEntry
+	(*MapSnapshot)(nil),                  // 160: centrifugal.centrifugo.api.MapSnapshot
+	(*MapExportRequest)(nil),             // 161: centrifugal.centrifugo.api.MapExportRequest
+	(*MapExportResponse)(nil),            // 162: centrifugal.centrifugo.api.MapExportResponse
+	(*MapExportResult)(nil),              // 163: centrifugal.centrifugo.api.MapExportResult
+	(*MapImportRequest)(nil),             // 164: centrifugal.centrifugo.api.MapImportRequest
+	(*MapImportResponse)(nil),            // 165: centrifugal.centrifugo.api.MapImportResponse
+	(*MapImportResult)(nil),              // 166: centrifugal.centrifugo.api.MapImportResult
+	(*SharedPollPublishRequest)(nil),     // 167: centrifugal.centrifugo.api.SharedPollPublishRequest
+	(*SharedPollPublishResponse)(nil),    // 168: centrifugal.centrifugo.api.SharedPollPublishResponse
+	(*SharedPollPublishResult)(nil),      // 169: centrifugal.centrifugo.api.SharedPollPublishResult
+	(*ScheduledPublication)(nil),         // 170: centrifugal.centrifugo.api.ScheduledPublication
+	(*ScheduledListRequest)(nil),         // 171: centrifugal.centrifugo.api.ScheduledListRequest
+	(*ScheduledListResponse)(nil),        // 172: centrifugal.centrifugo.api.ScheduledListResponse
+	(*ScheduledListResult)(nil),          // 173: centrifugal.centrifugo.api.ScheduledListResult
+	(*ScheduledCancelRequest)(nil),       // 174: centrifugal.centrifugo.api.ScheduledCancelRequest
+	(*ScheduledCancelResponse)(nil),      // 175: centrifugal.centrifugo.api.ScheduledCancelResponse
+	(*ScheduledCancelResult)(nil),        // 176: centrifugal.centrifugo.api.ScheduledCancelResult
+	nil,                                  // 177: centrifugal.centrifugo.api.PublishRequest.TagsEntry
+	nil,                                  // 178: centrifugal.centrifugo.api.BroadcastRequest.TagsEntry
+	nil,                                  // 179: centrifugal.centrifugo.api.PresenceResult.PresenceEntry
+	nil,                                  // 180: centrifugal.centrifugo.api.Publication.TagsEntry
+	nil,                                  // 181: centrifugal.centrifugo.api.Metrics.ItemsEntry
+	nil,                                  // 182: centrifugal.centrifugo.api.ChannelsResult.ChannelsEntry
+	nil,                                  // 183: centrifugal.centrifugo.api.ConnectionsResult.ConnectionsEntry
+	nil,                                  // 184: centrifugal.centrifugo.api.ConnectionInfo.LabelsEntry
+	nil,                                  // 185: centrifugal.centrifugo.api.ConnectionState.ChannelsEntry
+	nil,                                  // 186: centrifugal.centrifugo.api.ConnectionState.SubscriptionTokensEntry
+	nil,                                  // 187: centrifugal.centrifugo.api.DeviceRegisterRequest.MetaEntry
+	nil,                                  // 188: centrifugal.centrifugo.api.DeviceMetaUpdate.MetaEntry
+	nil,                                  // 189: centrifugal.centrifugo.api.Device.MetaEntry
+	nil,                                  // 190: centrifugal.centrifugo.api.ApnsPushNotification.HeadersEntry
+	nil,                                  // 191: centrifugal.centrifugo.api.WebPushPushNotification.HeadersEntry
+	nil,                                  // 192: centrifugal.centrifugo.api.SendPushNotificationRequest.LocalizationsEntry
+	nil,                                  // 193: centrifugal.centrifugo.api.PushLocalization.TranslationsEntry
+	nil,                                  // 194: centrifugal.centrifugo.api.MapPublishRequest.TagsEntry
+	nil,                                  // 195: centrifugal.centrifugo.api.MapEntry.TagsEntry
+	nil,                                  // 196: centrifugal.centrifugo.api.ScheduledPublication.TagsEntry
 }
 var file_api_proto_depIdxs = []int32{
 	5,   // 0: centrifugal.centrifugo.api.Command.publish:type_name -> centrifugal.centrifugo.api.PublishRequest
@@ -11076,286 +12143,316 @@ var file_api_proto_depIdxs = []int32{
 	151, // 35: centrifugal.centrifugo.api.Command.map_read_stream:type_name -> centrifugal.centrifugo.api.MapReadStreamRequest
 	154, // 36: centrifugal.centrifugo.api.Command.map_stats:type_name -> centrifugal.centrifugo.api.MapStatsRequest
 	157, // 37: centrifugal.centrifugo.api.Command.map_clear:type_name -> centrifugal.centrifugo.api.MapClearRequest
-	160, // 38: centrifugal.centrifugo.api.Command.shared_poll_publish:type_name -> centrifugal.centrifugo.api.SharedPollPublishRequest
-	1,   // 39: centrifugal.centrifugo.api.Reply.error:type_name -> centrifugal.centrifugo.api.Error
-	7,   // 40: centrifugal.centrifugo.api.Reply.publish:type_name -> centrifugal.centrifugo.api.PublishResult
-	10,  // 41: centrifugal.centrifugo.api.Reply.broadcast:type_name -> centrifugal.centrifugo.api.BroadcastResult
-	17,  // 42: centrifugal.centrifugo.api.Reply.subscribe:type_name -> centrifugal.centrifugo.api.SubscribeResult
-	20,  // 43: centrifugal.centrifugo.api.Reply.unsubscribe:type_name -> centrifugal.centrifugo.api.UnsubscribeResult
-	24,  // 44: centrifugal.centrifugo.api.Reply.disconnect:type_name -> centrifugal.centrifugo.api.DisconnectResult
-	28,  // 45: centrifugal.centrifugo.api.Reply.presence:type_name -> centrifugal.centrifugo.api.PresenceResult
-	31,  // 46: centrifugal.centrifugo.api.Reply.presence_stats:type_name -> centrifugal.centrifugo.api.PresenceStatsResult
-	36,  // 47: centrifugal.centrifugo.api.Reply.history:type_name -> centrifugal.centrifugo.api.HistoryResult
-	39,  // 48: centrifugal.centrifugo.api.Reply.history_remove:type_name -> centrifugal.centrifugo.api.HistoryRemoveResult
-	42,  // 49: centrifugal.centrifugo.api.Reply.info:type_name -> centrifugal.centrifugo.api.InfoResult
-	45,  // 50: centrifugal.centrifugo.api.Reply.rpc:type_name -> centrifugal.centrifugo.api.RPCResult
-	48,  // 51: centrifugal.centrifugo.api.Reply.refresh:type_name -> centrifugal.centrifugo.api.RefreshResult
-	54,  // 52: centrifugal.centrifugo.api.Reply.channels:type_name -> centrifugal.centrifugo.api.ChannelsResult
-	58,  // 53: centrifugal.centrifugo.api.Reply.connections:type_name -> centrifugal.centrifugo.api.ConnectionsResult
-	66,  // 54: centrifugal.centrifugo.api.Reply.update_user_status:type_name -> centrifugal.centrifugo.api.UpdateUserStatusResult
-	69,  // 55: centrifugal.centrifugo.api.Reply.get_user_status:type_name -> centrifugal.centrifugo.api.GetUserStatusResult
-	73,  // 56: centrifugal.centrifugo.api.Reply.delete_user_status:type_name -> centrifugal.centrifugo.api.DeleteUserStatusResult
-	75,  // 57: centrifugal.centrifugo.api.Reply.block_user:type_name -> centrifugal.centrifugo.api.BlockUserResult
-	78,  // 58: centrifugal.centrifugo.api.Reply.unblock_user:type_name -> centrifugal.centrifugo.api.UnblockUserResult
-	81,  // 59: centrifugal.centrifugo.api.Reply.revoke_token:type_name -> centrifugal.centrifugo.api.RevokeTokenResult
-	84,  // 60: centrifugal.centrifugo.api.Reply.invalidate_user_tokens:type_name -> centrifugal.centrifugo.api.InvalidateUserTokensResult
-	110, // 61: centrifugal.centrifugo.api.Reply.device_register:type_name -> centrifugal.centrifugo.api.DeviceRegisterResult
-	111, // 62: centrifugal.centrifugo.api.Reply.device_update:type_name -> centrifugal.centrifugo.api.DeviceUpdateResult
-	112, // 63: centrifugal.centrifugo.api.Reply.device_remove:type_name -> centrifugal.centrifugo.api.DeviceRemoveResult
-	113, // 64: centrifugal.centrifugo.api.Reply.device_list:type_name -> centrifugal.centrifugo.api.DeviceListResult
-	115, // 65: centrifugal.centrifugo.api.Reply.device_topic_list:type_name -> centrifugal.centrifugo.api.DeviceTopicListResult
-	118, // 66: centrifugal.centrifugo.api.Reply.device_topic_update:type_name -> centrifugal.centrifugo.api.DeviceTopicUpdateResult
-	117, // 67: centrifugal.centrifugo.api.Reply.user_topic_list:type_name -> centrifugal.centrifugo.api.UserTopicListResult
-	119, // 68: centrifugal.centrifugo.api.Reply.user_topic_update:type_name -> centrifugal.centrifugo.api.UserTopicUpdateResult
-	134, // 69: centrifugal.centrifugo.api.Reply.send_push_notification:type_name -> centrifugal.centrifugo.api.SendPushNotificationResult
-	137, // 70: centrifugal.centrifugo.api.Reply.update_push_status:type_name -> centrifugal.centrifugo.api.UpdatePushStatusResult
-	140, // 71: centrifugal.centrifugo.api.Reply.cancel_push:type_name -> centrifugal.centrifugo.api.CancelPushResult
-	143, // 72: centrifugal.centrifugo.api.Reply.map_publish:type_name -> centrifugal.centrifugo.api.MapPublishResult
-	146, // 73: centrifugal.centrifugo.api.Reply.map_remove:type_name -> centrifugal.centrifugo.api.MapRemoveResult
-	149, // 74: centrifugal.centrifugo.api.Reply.map_read_state:type_name -> centrifugal.centrifugo.api.MapReadStateResult
-	153, // 75: centrifugal.centrifugo.api.Reply.map_read_stream:type_name -> centrifugal.centrifugo.api.MapReadStreamResult
-	156, // 76: centrifugal.centrifugo.api.Reply.map_stats:type_name -> centrifugal.centrifugo.api.MapStatsResult
-	159, // 77: centrifugal.centrifugo.api.Reply.map_clear:type_name -> centrifugal.centrifugo.api.MapClearResult
-	162, // 78: centrifugal.centrifugo.api.Reply.shared_poll_publish:type_name -> centrifugal.centrifugo.api.SharedPollPublishResult
-	0,   // 79: centrifugal.centrifugo.api.BatchRequest.commands:type_name -> centrifugal.centrifugo.api.Command
-	2,   // 80: centrifugal.centrifugo.api.BatchResponse.replies:type_name -> centrifugal.centrifugo.api.Reply
-	163, // 81: centrifugal.centrifugo.api.PublishRequest.tags:type_name -> centrifugal.centrifugo.api.PublishRequest.TagsEntry
-	1,   // 82: centrifugal.centrifugo.api.PublishResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	7,   // 83: centrifugal.centrifugo.api.PublishResponse.result:type_name -> centrifugal.centrifugo.api.PublishResult
-	164, // 84: centrifugal.centrifugo.api.BroadcastRequest.tags:type_name -> centrifugal.centrifugo.api.BroadcastRequest.TagsEntry
-	1,   // 85: centrifugal.centrifugo.api.BroadcastResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	10,  // 86: centrifugal.centrifugo.api.BroadcastResponse.result:type_name -> centrifugal.centrifugo.api.BroadcastResult
-	6,   // 87: centrifugal.centrifugo.api.BroadcastResult.responses:type_name -> centrifugal.centrifugo.api.PublishResponse
-	11,  // 88: centrifugal.centrifugo.api.FilterNode.nodes:type_name -> centrifugal.centrifugo.api.FilterNode
-	32,  // 89: centrifugal.centrifugo.api.SubscribeRequest.recover_since:type_name -> centrifugal.centrifugo.api.StreamPosition
-	16,  // 90: centrifugal.centrifugo.api.SubscribeRequest.override:type_name -> centrifugal.centrifugo.api.SubscribeOptionOverride
-	11,  // 91: centrifugal.centrifugo.api.SubscribeRequest.label_filter:type_name -> centrifugal.centrifugo.api.FilterNode
-	1,   // 92: centrifugal.centrifugo.api.SubscribeResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	17,  // 93: centrifugal.centrifugo.api.SubscribeResponse.result:type_name -> centrifugal.centrifugo.api.SubscribeResult
-	14,  // 94: centrifugal.centrifugo.api.SubscribeOptionOverride.presence:type_name -> centrifugal.centrifugo.api.BoolValue
-	14,  // 95: centrifugal.centrifugo.api.SubscribeOptionOverride.join_leave:type_name -> centrifugal.centrifugo.api.BoolValue
-	14,  // 96: centrifugal.centrifugo.api.SubscribeOptionOverride.force_recovery:type_name -> centrifugal.centrifugo.api.BoolValue
-	14,  // 97: centrifugal.centrifugo.api.SubscribeOptionOverride.force_positioning:type_name -> centrifugal.centrifugo.api.BoolValue
-	14,  // 98: centrifugal.centrifugo.api.SubscribeOptionOverride.force_push_join_leave:type_name -> centrifugal.centrifugo.api.BoolValue
-	11,  // 99: centrifugal.centrifugo.api.UnsubscribeRequest.label_filter:type_name -> centrifugal.centrifugo.api.FilterNode
-	1,   // 100: centrifugal.centrifugo.api.UnsubscribeResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	20,  // 101: centrifugal.centrifugo.api.UnsubscribeResponse.result:type_name -> centrifugal.centrifugo.api.UnsubscribeResult
-	21,  // 102: centrifugal.centrifugo.api.DisconnectRequest.disconnect:type_name -> centrifugal.centrifugo.api.Disconnect
-	11,  // 103: centrifugal.centrifugo.api.DisconnectRequest.label_filter:type_name -> centrifugal.centrifugo.api.FilterNode
-	1,   // 104: centrifugal.centrifugo.api.DisconnectResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	24,  // 105: centrifugal.centrifugo.api.DisconnectResponse.result:type_name -> centrifugal.centrifugo.api.DisconnectResult
-	1,   // 106: centrifugal.centrifugo.api.PresenceResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	28,  // 107: centrifugal.centrifugo.api.PresenceResponse.result:type_name -> centrifugal.centrifugo.api.PresenceResult
-	165, // 108: centrifugal.centrifugo.api.PresenceResult.presence:type_name -> centrifugal.centrifugo.api.PresenceResult.PresenceEntry
-	1,   // 109: centrifugal.centrifugo.api.PresenceStatsResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	31,  // 110: centrifugal.centrifugo.api.PresenceStatsResponse.result:type_name -> centrifugal.centrifugo.api.PresenceStatsResult
-	32,  // 111: centrifugal.centrifugo.api.HistoryRequest.since:type_name -> centrifugal.centrifugo.api.StreamPosition
-	1,   // 112: centrifugal.centrifugo.api.HistoryResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	36,  // 113: centrifugal.centrifugo.api.HistoryResponse.result:type_name -> centrifugal.centrifugo.api.HistoryResult
-	27,  // 114: centrifugal.centrifugo.api.Publication.info:type_name -> centrifugal.centrifugo.api.ClientInfo
-	166, // 115: centrifugal.centrifugo.api.Publication.tags:type_name -> centrifugal.centrifugo.api.Publication.TagsEntry
-	35,  // 116: centrifugal.centrifugo.api.HistoryResult.publications:type_name -> centrifugal.centrifugo.api.Publication
-	1,   // 117: centrifugal.centrifugo.api.HistoryRemoveResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	39,  // 118: centrifugal.centrifugo.api.HistoryRemoveResponse.result:type_name -> centrifugal.centrifugo.api.HistoryRemoveResult
-	1,   // 119: centrifugal.centrifugo.api.InfoResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	42,  // 120: centrifugal.centrifugo.api.InfoResponse.result:type_name -> centrifugal.centrifugo.api.InfoResult
-	49,  // 121: centrifugal.centrifugo.api.InfoResult.nodes:type_name -> centrifugal.centrifugo.api.NodeResult
-	1,   // 122: centrifugal.centrifugo.api.RPCResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	45,  // 123: centrifugal.centrifugo.api.RPCResponse.result:type_name -> centrifugal.centrifugo.api.RPCResult
-	11,  // 124: centrifugal.centrifugo.api.RefreshRequest.label_filter:type_name -> centrifugal.centrifugo.api.FilterNode
-	1,   // 125: centrifugal.centrifugo.api.RefreshResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	48,  // 126: centrifugal.centrifugo.api.RefreshResponse.result:type_name -> centrifugal.centrifugo.api.RefreshResult
-	50,  // 127: centrifugal.centrifugo.api.NodeResult.metrics:type_name -> centrifugal.centrifugo.api.Metrics
-	51,  // 128: centrifugal.centrifugo.api.NodeResult.process:type_name -> centrifugal.centrifugo.api.Process
-	167, // 129: centrifugal.centrifugo.api.Metrics.items:type_name -> centrifugal.centrifugo.api.Metrics.ItemsEntry
-	1,   // 130: centrifugal.centrifugo.api.ChannelsResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	54,  // 131: centrifugal.centrifugo.api.ChannelsResponse.result:type_name -> centrifugal.centrifugo.api.ChannelsResult
-	168, // 132: centrifugal.centrifugo.api.ChannelsResult.channels:type_name -> centrifugal.centrifugo.api.ChannelsResult.ChannelsEntry
-	11,  // 133: centrifugal.centrifugo.api.ConnectionsRequest.label_filter:type_name -> centrifugal.centrifugo.api.FilterNode
-	1,   // 134: centrifugal.centrifugo.api.ConnectionsResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	58,  // 135: centrifugal.centrifugo.api.ConnectionsResponse.result:type_name -> centrifugal.centrifugo.api.ConnectionsResult
-	169, // 136: centrifugal.centrifugo.api.ConnectionsResult.connections:type_name -> centrifugal.centrifugo.api.ConnectionsResult.ConnectionsEntry
-	60,  // 137: centrifugal.centrifugo.api.ConnectionInfo.state:type_name -> centrifugal.centrifugo.api.ConnectionState
-	170, // 138: centrifugal.centrifugo.api.ConnectionInfo.labels:type_name -> centrifugal.centrifugo.api.ConnectionInfo.LabelsEntry
-	171, // 139: centrifugal.centrifugo.api.ConnectionState.channels:type_name -> centrifugal.centrifugo.api.ConnectionState.ChannelsEntry
-	62,  // 140: centrifugal.centrifugo.api.ConnectionState.connection_token:type_name -> centrifugal.centrifugo.api.ConnectionTokenInfo
-	172, // 141: centrifugal.centrifugo.api.ConnectionState.subscription_tokens:type_name -> centrifugal.centrifugo.api.ConnectionState.SubscriptionTokensEntry
-	1,   // 142: centrifugal.centrifugo.api.UpdateUserStatusResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	66,  // 143: centrifugal.centrifugo.api.UpdateUserStatusResponse.result:type_name -> centrifugal.centrifugo.api.UpdateUserStatusResult
-	1,   // 144: centrifugal.centrifugo.api.GetUserStatusResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	69,  // 145: centrifugal.centrifugo.api.GetUserStatusResponse.result:type_name -> centrifugal.centrifugo.api.GetUserStatusResult
-	70,  // 146: centrifugal.centrifugo.api.GetUserStatusResult.statuses:type_name -> centrifugal.centrifugo.api.UserStatus
-	1,   // 147: centrifugal.centrifugo.api.DeleteUserStatusResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	73,  // 148: centrifugal.centrifugo.api.DeleteUserStatusResponse.result:type_name -> centrifugal.centrifugo.api.DeleteUserStatusResult
-	1,   // 149: centrifugal.centrifugo.api.BlockUserResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	75,  // 150: centrifugal.centrifugo.api.BlockUserResponse.result:type_name -> centrifugal.centrifugo.api.BlockUserResult
-	1,   // 151: centrifugal.centrifugo.api.UnblockUserResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	78,  // 152: centrifugal.centrifugo.api.UnblockUserResponse.result:type_name -> centrifugal.centrifugo.api.UnblockUserResult
-	1,   // 153: centrifugal.centrifugo.api.RevokeTokenResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	81,  // 154: centrifugal.centrifugo.api.RevokeTokenResponse.result:type_name -> centrifugal.centrifugo.api.RevokeTokenResult
-	1,   // 155: centrifugal.centrifugo.api.InvalidateUserTokensResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	84,  // 156: centrifugal.centrifugo.api.InvalidateUserTokensResponse.result:type_name -> centrifugal.centrifugo.api.InvalidateUserTokensResult
-	173, // 157: centrifugal.centrifugo.api.DeviceRegisterRequest.meta:type_name -> centrifugal.centrifugo.api.DeviceRegisterRequest.MetaEntry
-	89,  // 158: centrifugal.centrifugo.api.DeviceUpdateRequest.user_update:type_name -> centrifugal.centrifugo.api.DeviceUserUpdate
-	92,  // 159: centrifugal.centrifugo.api.DeviceUpdateRequest.meta_update:type_name -> centrifugal.centrifugo.api.DeviceMetaUpdate
-	93,  // 160: centrifugal.centrifugo.api.DeviceUpdateRequest.topics_update:type_name -> centrifugal.centrifugo.api.DeviceTopicsUpdate
-	90,  // 161: centrifugal.centrifugo.api.DeviceUpdateRequest.timezone_update:type_name -> centrifugal.centrifugo.api.DeviceTimezoneUpdate
-	91,  // 162: centrifugal.centrifugo.api.DeviceUpdateRequest.locale_update:type_name -> centrifugal.centrifugo.api.DeviceLocaleUpdate
-	174, // 163: centrifugal.centrifugo.api.DeviceMetaUpdate.meta:type_name -> centrifugal.centrifugo.api.DeviceMetaUpdate.MetaEntry
-	94,  // 164: centrifugal.centrifugo.api.DeviceListRequest.filter:type_name -> centrifugal.centrifugo.api.DeviceFilter
-	96,  // 165: centrifugal.centrifugo.api.DeviceTopicListRequest.filter:type_name -> centrifugal.centrifugo.api.DeviceTopicFilter
-	98,  // 166: centrifugal.centrifugo.api.UserTopicListRequest.filter:type_name -> centrifugal.centrifugo.api.UserTopicFilter
-	1,   // 167: centrifugal.centrifugo.api.DeviceRegisterResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	110, // 168: centrifugal.centrifugo.api.DeviceRegisterResponse.result:type_name -> centrifugal.centrifugo.api.DeviceRegisterResult
-	1,   // 169: centrifugal.centrifugo.api.DeviceUpdateResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	111, // 170: centrifugal.centrifugo.api.DeviceUpdateResponse.result:type_name -> centrifugal.centrifugo.api.DeviceUpdateResult
-	1,   // 171: centrifugal.centrifugo.api.DeviceRemoveResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	112, // 172: centrifugal.centrifugo.api.DeviceRemoveResponse.result:type_name -> centrifugal.centrifugo.api.DeviceRemoveResult
-	1,   // 173: centrifugal.centrifugo.api.DeviceListResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	113, // 174: centrifugal.centrifugo.api.DeviceListResponse.result:type_name -> centrifugal.centrifugo.api.DeviceListResult
-	1,   // 175: centrifugal.centrifugo.api.DeviceTopicListResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	115, // 176: centrifugal.centrifugo.api.DeviceTopicListResponse.result:type_name -> centrifugal.centrifugo.api.DeviceTopicListResult
-	1,   // 177: centrifugal.centrifugo.api.UserTopicListResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	117, // 178: centrifugal.centrifugo.api.UserTopicListResponse.result:type_name -> centrifugal.centrifugo.api.UserTopicListResult
-	1,   // 179: centrifugal.centrifugo.api.DeviceTopicUpdateResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	118, // 180: centrifugal.centrifugo.api.DeviceTopicUpdateResponse.result:type_name -> centrifugal.centrifugo.api.DeviceTopicUpdateResult
-	1,   // 181: centrifugal.centrifugo.api.UserTopicUpdateResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	119, // 182: centrifugal.centrifugo.api.UserTopicUpdateResponse.result:type_name -> centrifugal.centrifugo.api.UserTopicUpdateResult
-	114, // 183: centrifugal.centrifugo.api.DeviceListResult.items:type_name -> centrifugal.centrifugo.api.Device
-	175, // 184: centrifugal.centrifugo.api.Device.meta:type_name -> centrifugal.centrifugo.api.Device.MetaEntry
-	116, // 185: centrifugal.centrifugo.api.DeviceTopicListResult.items:type_name -> centrifugal.centrifugo.api.DeviceTopic
-	114, // 186: centrifugal.centrifugo.api.DeviceTopic.device:type_name -> centrifugal.centrifugo.api.Device
-	120, // 187: centrifugal.centrifugo.api.UserTopicListResult.items:type_name -> centrifugal.centrifugo.api.UserTopic
-	94,  // 188: centrifugal.centrifugo.api.PushRecipient.filter:type_name -> centrifugal.centrifugo.api.DeviceFilter
-	123, // 189: centrifugal.centrifugo.api.PushNotification.fcm:type_name -> centrifugal.centrifugo.api.FcmPushNotification
-	124, // 190: centrifugal.centrifugo.api.PushNotification.hms:type_name -> centrifugal.centrifugo.api.HmsPushNotification
-	125, // 191: centrifugal.centrifugo.api.PushNotification.apns:type_name -> centrifugal.centrifugo.api.ApnsPushNotification
-	126, // 192: centrifugal.centrifugo.api.PushNotification.webpush:type_name -> centrifugal.centrifugo.api.WebPushPushNotification
-	176, // 193: centrifugal.centrifugo.api.ApnsPushNotification.headers:type_name -> centrifugal.centrifugo.api.ApnsPushNotification.HeadersEntry
-	177, // 194: centrifugal.centrifugo.api.WebPushPushNotification.headers:type_name -> centrifugal.centrifugo.api.WebPushPushNotification.HeadersEntry
-	121, // 195: centrifugal.centrifugo.api.SendPushNotificationRequest.recipient:type_name -> centrifugal.centrifugo.api.PushRecipient
-	122, // 196: centrifugal.centrifugo.api.SendPushNotificationRequest.notification:type_name -> centrifugal.centrifugo.api.PushNotification
-	129, // 197: centrifugal.centrifugo.api.SendPushNotificationRequest.limit_strategy:type_name -> centrifugal.centrifugo.api.PushLimitStrategy
-	178, // 198: centrifugal.centrifugo.api.SendPushNotificationRequest.localizations:type_name -> centrifugal.centrifugo.api.SendPushNotificationRequest.LocalizationsEntry
-	179, // 199: centrifugal.centrifugo.api.PushLocalization.translations:type_name -> centrifugal.centrifugo.api.PushLocalization.TranslationsEntry
-	131, // 200: centrifugal.centrifugo.api.PushLimitStrategy.rate_limit:type_name -> centrifugal.centrifugo.api.PushRateLimitStrategy
-	130, // 201: centrifugal.centrifugo.api.PushLimitStrategy.time_limit:type_name -> centrifugal.centrifugo.api.PushTimeLimitStrategy
-	132, // 202: centrifugal.centrifugo.api.PushRateLimitStrategy.policies:type_name -> centrifugal.centrifugo.api.RateLimitPolicy
-	1,   // 203: centrifugal.centrifugo.api.SendPushNotificationResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	134, // 204: centrifugal.centrifugo.api.SendPushNotificationResponse.result:type_name -> centrifugal.centrifugo.api.SendPushNotificationResult
-	1,   // 205: centrifugal.centrifugo.api.UpdatePushStatusResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	137, // 206: centrifugal.centrifugo.api.UpdatePushStatusResponse.result:type_name -> centrifugal.centrifugo.api.UpdatePushStatusResult
-	1,   // 207: centrifugal.centrifugo.api.CancelPushResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	140, // 208: centrifugal.centrifugo.api.CancelPushResponse.result:type_name -> centrifugal.centrifugo.api.CancelPushResult
-	180, // 209: centrifugal.centrifugo.api.MapPublishRequest.tags:type_name -> centrifugal.centrifugo.api.MapPublishRequest.TagsEntry
-	1,   // 210: centrifugal.centrifugo.api.MapPublishResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	143, // 211: centrifugal.centrifugo.api.MapPublishResponse.result:type_name -> centrifugal.centrifugo.api.MapPublishResult
-	1,   // 212: centrifugal.centrifugo.api.MapRemoveResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	146, // 213: centrifugal.centrifugo.api.MapRemoveResponse.result:type_name -> centrifugal.centrifugo.api.MapRemoveResult
-	1,   // 214: centrifugal.centrifugo.api.MapReadStateResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	149, // 215: centrifugal.centrifugo.api.MapReadStateResponse.result:type_name -> centrifugal.centrifugo.api.MapReadStateResult
-	150, // 216: centrifugal.centrifugo.api.MapReadStateResult.entries:type_name -> centrifugal.centrifugo.api.MapEntry
-	181, // 217: centrifugal.centrifugo.api.MapEntry.tags:type_name -> centrifugal.centrifugo.api.MapEntry.TagsEntry
-	1,   // 218: centrifugal.centrifugo.api.MapReadStreamResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	153, // 219: centrifugal.centrifugo.api.MapReadStreamResponse.result:type_name -> centrifugal.centrifugo.api.MapReadStreamResult
-	150, // 220: centrifugal.centrifugo.api.MapReadStreamResult.entries:type_name -> centrifugal.centrifugo.api.MapEntry
-	1,   // 221: centrifugal.centrifugo.api.MapStatsResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	156, // 222: centrifugal.centrifugo.api.MapStatsResponse.result:type_name -> centrifugal.centrifugo.api.MapStatsResult
-	1,   // 223: centrifugal.centrifugo.api.MapClearResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	159, // 224: centrifugal.centrifugo.api.MapClearResponse.result:type_name -> centrifugal.centrifugo.api.MapClearResult
-	1,   // 225: centrifugal.centrifugo.api.SharedPollPublishResponse.error:type_name -> centrifugal.centrifugo.api.Error
-	162, // 226: centrifugal.centrifugo.api.SharedPollPublishResponse.result:type_name -> centrifugal.centrifugo.api.SharedPollPublishResult
-	27,  // 227: centrifugal.centrifugo.api.PresenceResult.PresenceEntry.value:type_name -> centrifugal.centrifugo.api.ClientInfo
-	55,  // 228: centrifugal.centrifugo.api.ChannelsResult.ChannelsEntry.value:type_name -> centrifugal.centrifugo.api.ChannelInfo
-	59,  // 229: centrifugal.centrifugo.api.ConnectionsResult.ConnectionsEntry.value:type_name -> centrifugal.centrifugo.api.ConnectionInfo
-	61,  // 230: centrifugal.centrifugo.api.ConnectionState.ChannelsEntry.value:type_name -> centrifugal.centrifugo.api.ChannelContext
-	63,  // 231: centrifugal.centrifugo.api.ConnectionState.SubscriptionTokensEntry.value:type_name -> centrifugal.centrifugo.api.SubscriptionTokenInfo
-	128, // 232: centrifugal.centrifugo.api.SendPushNotificationRequest.LocalizationsEntry.value:type_name -> centrifugal.centrifugo.api.PushLocalization
-	3,   // 233: centrifugal.centrifugo.api.CentrifugoApi.Batch:input_type -> centrifugal.centrifugo.api.BatchRequest
-	5,   // 234: centrifugal.centrifugo.api.CentrifugoApi.Publish:input_type -> centrifugal.centrifugo.api.PublishRequest
-	8,   // 235: centrifugal.centrifugo.api.CentrifugoApi.Broadcast:input_type -> centrifugal.centrifugo.api.BroadcastRequest
-	12,  // 236: centrifugal.centrifugo.api.CentrifugoApi.Subscribe:input_type -> centrifugal.centrifugo.api.SubscribeRequest
-	18,  // 237: centrifugal.centrifugo.api.CentrifugoApi.Unsubscribe:input_type -> centrifugal.centrifugo.api.UnsubscribeRequest
-	22,  // 238: centrifugal.centrifugo.api.CentrifugoApi.Disconnect:input_type -> centrifugal.centrifugo.api.DisconnectRequest
-	25,  // 239: centrifugal.centrifugo.api.CentrifugoApi.Presence:input_type -> centrifugal.centrifugo.api.PresenceRequest
-	29,  // 240: centrifugal.centrifugo.api.CentrifugoApi.PresenceStats:input_type -> centrifugal.centrifugo.api.PresenceStatsRequest
-	33,  // 241: centrifugal.centrifugo.api.CentrifugoApi.History:input_type -> centrifugal.centrifugo.api.HistoryRequest
-	37,  // 242: centrifugal.centrifugo.api.CentrifugoApi.HistoryRemove:input_type -> centrifugal.centrifugo.api.HistoryRemoveRequest
-	40,  // 243: centrifugal.centrifugo.api.CentrifugoApi.Info:input_type -> centrifugal.centrifugo.api.InfoRequest
-	43,  // 244: centrifugal.centrifugo.api.CentrifugoApi.RPC:input_type -> centrifugal.centrifugo.api.RPCRequest
-	46,  // 245: centrifugal.centrifugo.api.CentrifugoApi.Refresh:input_type -> centrifugal.centrifugo.api.RefreshRequest
-	52,  // 246: centrifugal.centrifugo.api.CentrifugoApi.Channels:input_type -> centrifugal.centrifugo.api.ChannelsRequest
-	56,  // 247: centrifugal.centrifugo.api.CentrifugoApi.Connections:input_type -> centrifugal.centrifugo.api.ConnectionsRequest
-	64,  // 248: centrifugal.centrifugo.api.CentrifugoApi.UpdateUserStatus:input_type -> centrifugal.centrifugo.api.UpdateUserStatusRequest
-	67,  // 249: centrifugal.centrifugo.api.CentrifugoApi.GetUserStatus:input_type -> centrifugal.centrifugo.api.GetUserStatusRequest
-	71,  // 250: centrifugal.centrifugo.api.CentrifugoApi.DeleteUserStatus:input_type -> centrifugal.centrifugo.api.DeleteUserStatusRequest
-	74,  // 251: centrifugal.centrifugo.api.CentrifugoApi.BlockUser:input_type -> centrifugal.centrifugo.api.BlockUserRequest
-	77,  // 252: centrifugal.centrifugo.api.CentrifugoApi.UnblockUser:input_type -> centrifugal.centrifugo.api.UnblockUserRequest
-	80,  // 253: centrifugal.centrifugo.api.CentrifugoApi.RevokeToken:input_type -> centrifugal.centrifugo.api.RevokeTokenRequest
-	83,  // 254: centrifugal.centrifugo.api.CentrifugoApi.InvalidateUserTokens:input_type -> centrifugal.centrifugo.api.InvalidateUserTokensRequest
-	86,  // 255: centrifugal.centrifugo.api.CentrifugoApi.DeviceRegister:input_type -> centrifugal.centrifugo.api.DeviceRegisterRequest
-	87,  // 256: centrifugal.centrifugo.api.CentrifugoApi.DeviceUpdate:input_type -> centrifugal.centrifugo.api.DeviceUpdateRequest
-	88,  // 257: centrifugal.centrifugo.api.CentrifugoApi.DeviceRemove:input_type -> centrifugal.centrifugo.api.DeviceRemoveRequest
-	95,  // 258: centrifugal.centrifugo.api.CentrifugoApi.DeviceList:input_type -> centrifugal.centrifugo.api.DeviceListRequest
-	97,  // 259: centrifugal.centrifugo.api.CentrifugoApi.DeviceTopicList:input_type -> centrifugal.centrifugo.api.DeviceTopicListRequest
-	100, // 260: centrifugal.centrifugo.api.CentrifugoApi.DeviceTopicUpdate:input_type -> centrifugal.centrifugo.api.DeviceTopicUpdateRequest
-	99,  // 261: centrifugal.centrifugo.api.CentrifugoApi.UserTopicList:input_type -> centrifugal.centrifugo.api.UserTopicListRequest
-	101, // 262: centrifugal.centrifugo.api.CentrifugoApi.UserTopicUpdate:input_type -> centrifugal.centrifugo.api.UserTopicUpdateRequest
-	127, // 263: centrifugal.centrifugo.api.CentrifugoApi.SendPushNotification:input_type -> centrifugal.centrifugo.api.SendPushNotificationRequest
-	135, // 264: centrifugal.centrifugo.api.CentrifugoApi.UpdatePushStatus:input_type -> centrifugal.centrifugo.api.UpdatePushStatusRequest
-	138, // 265: centrifugal.centrifugo.api.CentrifugoApi.CancelPush:input_type -> centrifugal.centrifugo.api.CancelPushRequest
-	141, // 266: centrifugal.centrifugo.api.CentrifugoApi.MapPublish:input_type -> centrifugal.centrifugo.api.MapPublishRequest
-	144, // 267: centrifugal.centrifugo.api.CentrifugoApi.MapRemove:input_type -> centrifugal.centrifugo.api.MapRemoveRequest
-	147, // 268: centrifugal.centrifugo.api.CentrifugoApi.MapReadState:input_type -> centrifugal.centrifugo.api.MapReadStateRequest
-	151, // 269: centrifugal.centrifugo.api.CentrifugoApi.MapReadStream:input_type -> centrifugal.centrifugo.api.MapReadStreamRequest
-	154, // 270: centrifugal.centrifugo.api.CentrifugoApi.MapStats:input_type -> centrifugal.centrifugo.api.MapStatsRequest
-	157, // 271: centrifugal.centrifugo.api.CentrifugoApi.MapClear:input_type -> centrifugal.centrifugo.api.MapClearRequest
-	160, // 272: centrifugal.centrifugo.api.CentrifugoApi.SharedPollPublish:input_type -> centrifugal.centrifugo.api.SharedPollPublishRequest
-	4,   // 273: centrifugal.centrifugo.api.CentrifugoApi.Batch:output_type -> centrifugal.centrifugo.api.BatchResponse
-	6,   // 274: centrifugal.centrifugo.api.CentrifugoApi.Publish:output_type -> centrifugal.centrifugo.api.PublishResponse
-	9,   // 275: centrifugal.centrifugo.api.CentrifugoApi.Broadcast:output_type -> centrifugal.centrifugo.api.BroadcastResponse
-	13,  // 276: centrifugal.centrifugo.api.CentrifugoApi.Subscribe:output_type -> centrifugal.centrifugo.api.SubscribeResponse
-	19,  // 277: centrifugal.centrifugo.api.CentrifugoApi.Unsubscribe:output_type -> centrifugal.centrifugo.api.UnsubscribeResponse
-	23,  // 278: centrifugal.centrifugo.api.CentrifugoApi.Disconnect:output_type -> centrifugal.centrifugo.api.DisconnectResponse
-	26,  // 279: centrifugal.centrifugo.api.CentrifugoApi.Presence:output_type -> centrifugal.centrifugo.api.PresenceResponse
-	30,  // 280: centrifugal.centrifugo.api.CentrifugoApi.PresenceStats:output_type -> centrifugal.centrifugo.api.PresenceStatsResponse
-	34,  // 281: centrifugal.centrifugo.api.CentrifugoApi.History:output_type -> centrifugal.centrifugo.api.HistoryResponse
-	38,  // 282: centrifugal.centrifugo.api.CentrifugoApi.HistoryRemove:output_type -> centrifugal.centrifugo.api.HistoryRemoveResponse
-	41,  // 283: centrifugal.centrifugo.api.CentrifugoApi.Info:output_type -> centrifugal.centrifugo.api.InfoResponse
-	44,  // 284: centrifugal.centrifugo.api.CentrifugoApi.RPC:output_type -> centrifugal.centrifugo.api.RPCResponse
-	47,  // 285: centrifugal.centrifugo.api.CentrifugoApi.Refresh:output_type -> centrifugal.centrifugo.api.RefreshResponse
-	53,  // 286: centrifugal.centrifugo.api.CentrifugoApi.Channels:output_type -> centrifugal.centrifugo.api.ChannelsResponse
-	57,  // 287: centrifugal.centrifugo.api.CentrifugoApi.Connections:output_type -> centrifugal.centrifugo.api.ConnectionsResponse
-	65,  // 288: centrifugal.centrifugo.api.CentrifugoApi.UpdateUserStatus:output_type -> centrifugal.centrifugo.api.UpdateUserStatusResponse
-	68,  // 289: centrifugal.centrifugo.api.CentrifugoApi.GetUserStatus:output_type -> centrifugal.centrifugo.api.GetUserStatusResponse
-	72,  // 290: centrifugal.centrifugo.api.CentrifugoApi.DeleteUserStatus:output_type -> centrifugal.centrifugo.api.DeleteUserStatusResponse
-	76,  // 291: centrifugal.centrifugo.api.CentrifugoApi.BlockUser:output_type -> centrifugal.centrifugo.api.BlockUserResponse
-	79,  // 292: centrifugal.centrifugo.api.CentrifugoApi.UnblockUser:output_type -> centrifugal.centrifugo.api.UnblockUserResponse
-	82,  // 293: centrifugal.centrifugo.api.CentrifugoApi.RevokeToken:output_type -> centrifugal.centrifugo.api.RevokeTokenResponse
-	85,  // 294: centrifugal.centrifugo.api.CentrifugoApi.InvalidateUserTokens:output_type -> centrifugal.centrifugo.api.InvalidateUserTokensResponse
-	102, // 295: centrifugal.centrifugo.api.CentrifugoApi.DeviceRegister:output_type -> centrifugal.centrifugo.api.DeviceRegisterResponse
-	103, // 296: centrifugal.centrifugo.api.CentrifugoApi.DeviceUpdate:output_type -> centrifugal.centrifugo.api.DeviceUpdateResponse
-	104, // 297: centrifugal.centrifugo.api.CentrifugoApi.DeviceRemove:output_type -> centrifugal.centrifugo.api.DeviceRemoveResponse
-	105, // 298: centrifugal.centrifugo.api.CentrifugoApi.DeviceList:output_type -> centrifugal.centrifugo.api.DeviceListResponse
-	106, // 299: centrifugal.centrifugo.api.CentrifugoApi.DeviceTopicList:output_type -> centrifugal.centrifugo.api.DeviceTopicListResponse
-	108, // 300: centrifugal.centrifugo.api.CentrifugoApi.DeviceTopicUpdate:output_type -> centrifugal.centrifugo.api.DeviceTopicUpdateResponse
-	107, // 301: centrifugal.centrifugo.api.CentrifugoApi.UserTopicList:output_type -> centrifugal.centrifugo.api.UserTopicListResponse
-	109, // 302: centrifugal.centrifugo.api.CentrifugoApi.UserTopicUpdate:output_type -> centrifugal.centrifugo.api.UserTopicUpdateResponse
-	133, // 303: centrifugal.centrifugo.api.CentrifugoApi.SendPushNotification:output_type -> centrifugal.centrifugo.api.SendPushNotificationResponse
-	136, // 304: centrifugal.centrifugo.api.CentrifugoApi.UpdatePushStatus:output_type -> centrifugal.centrifugo.api.UpdatePushStatusResponse
-	139, // 305: centrifugal.centrifugo.api.CentrifugoApi.CancelPush:output_type -> centrifugal.centrifugo.api.CancelPushResponse
-	142, // 306: centrifugal.centrifugo.api.CentrifugoApi.MapPublish:output_type -> centrifugal.centrifugo.api.MapPublishResponse
-	145, // 307: centrifugal.centrifugo.api.CentrifugoApi.MapRemove:output_type -> centrifugal.centrifugo.api.MapRemoveResponse
-	148, // 308: centrifugal.centrifugo.api.CentrifugoApi.MapReadState:output_type -> centrifugal.centrifugo.api.MapReadStateResponse
-	152, // 309: centrifugal.centrifugo.api.CentrifugoApi.MapReadStream:output_type -> centrifugal.centrifugo.api.MapReadStreamResponse
-	155, // 310: centrifugal.centrifugo.api.CentrifugoApi.MapStats:output_type -> centrifugal.centrifugo.api.MapStatsResponse
-	158, // 311: centrifugal.centrifugo.api.CentrifugoApi.MapClear:output_type -> centrifugal.centrifugo.api.MapClearResponse
-	161, // 312: centrifugal.centrifugo.api.CentrifugoApi.SharedPollPublish:output_type -> centrifugal.centrifugo.api.SharedPollPublishResponse
-	273, // [273:313] is the sub-list for method output_type
-	233, // [233:273] is the sub-list for method input_type
-	233, // [233:233] is the sub-list for extension type_name
-	233, // [233:233] is the sub-list for extension extendee
-	0,   // [0:233] is the sub-list for field type_name
+	167, // 38: centrifugal.centrifugo.api.Command.shared_poll_publish:type_name -> centrifugal.centrifugo.api.SharedPollPublishRequest
+	161, // 39: centrifugal.centrifugo.api.Command.map_export:type_name -> centrifugal.centrifugo.api.MapExportRequest
+	164, // 40: centrifugal.centrifugo.api.Command.map_import:type_name -> centrifugal.centrifugo.api.MapImportRequest
+	171, // 41: centrifugal.centrifugo.api.Command.scheduled_list:type_name -> centrifugal.centrifugo.api.ScheduledListRequest
+	174, // 42: centrifugal.centrifugo.api.Command.scheduled_cancel:type_name -> centrifugal.centrifugo.api.ScheduledCancelRequest
+	1,   // 43: centrifugal.centrifugo.api.Reply.error:type_name -> centrifugal.centrifugo.api.Error
+	7,   // 44: centrifugal.centrifugo.api.Reply.publish:type_name -> centrifugal.centrifugo.api.PublishResult
+	10,  // 45: centrifugal.centrifugo.api.Reply.broadcast:type_name -> centrifugal.centrifugo.api.BroadcastResult
+	17,  // 46: centrifugal.centrifugo.api.Reply.subscribe:type_name -> centrifugal.centrifugo.api.SubscribeResult
+	20,  // 47: centrifugal.centrifugo.api.Reply.unsubscribe:type_name -> centrifugal.centrifugo.api.UnsubscribeResult
+	24,  // 48: centrifugal.centrifugo.api.Reply.disconnect:type_name -> centrifugal.centrifugo.api.DisconnectResult
+	28,  // 49: centrifugal.centrifugo.api.Reply.presence:type_name -> centrifugal.centrifugo.api.PresenceResult
+	31,  // 50: centrifugal.centrifugo.api.Reply.presence_stats:type_name -> centrifugal.centrifugo.api.PresenceStatsResult
+	36,  // 51: centrifugal.centrifugo.api.Reply.history:type_name -> centrifugal.centrifugo.api.HistoryResult
+	39,  // 52: centrifugal.centrifugo.api.Reply.history_remove:type_name -> centrifugal.centrifugo.api.HistoryRemoveResult
+	42,  // 53: centrifugal.centrifugo.api.Reply.info:type_name -> centrifugal.centrifugo.api.InfoResult
+	45,  // 54: centrifugal.centrifugo.api.Reply.rpc:type_name -> centrifugal.centrifugo.api.RPCResult
+	48,  // 55: centrifugal.centrifugo.api.Reply.refresh:type_name -> centrifugal.centrifugo.api.RefreshResult
+	54,  // 56: centrifugal.centrifugo.api.Reply.channels:type_name -> centrifugal.centrifugo.api.ChannelsResult
+	58,  // 57: centrifugal.centrifugo.api.Reply.connections:type_name -> centrifugal.centrifugo.api.ConnectionsResult
+	66,  // 58: centrifugal.centrifugo.api.Reply.update_user_status:type_name -> centrifugal.centrifugo.api.UpdateUserStatusResult
+	69,  // 59: centrifugal.centrifugo.api.Reply.get_user_status:type_name -> centrifugal.centrifugo.api.GetUserStatusResult
+	73,  // 60: centrifugal.centrifugo.api.Reply.delete_user_status:type_name -> centrifugal.centrifugo.api.DeleteUserStatusResult
+	75,  // 61: centrifugal.centrifugo.api.Reply.block_user:type_name -> centrifugal.centrifugo.api.BlockUserResult
+	78,  // 62: centrifugal.centrifugo.api.Reply.unblock_user:type_name -> centrifugal.centrifugo.api.UnblockUserResult
+	81,  // 63: centrifugal.centrifugo.api.Reply.revoke_token:type_name -> centrifugal.centrifugo.api.RevokeTokenResult
+	84,  // 64: centrifugal.centrifugo.api.Reply.invalidate_user_tokens:type_name -> centrifugal.centrifugo.api.InvalidateUserTokensResult
+	110, // 65: centrifugal.centrifugo.api.Reply.device_register:type_name -> centrifugal.centrifugo.api.DeviceRegisterResult
+	111, // 66: centrifugal.centrifugo.api.Reply.device_update:type_name -> centrifugal.centrifugo.api.DeviceUpdateResult
+	112, // 67: centrifugal.centrifugo.api.Reply.device_remove:type_name -> centrifugal.centrifugo.api.DeviceRemoveResult
+	113, // 68: centrifugal.centrifugo.api.Reply.device_list:type_name -> centrifugal.centrifugo.api.DeviceListResult
+	115, // 69: centrifugal.centrifugo.api.Reply.device_topic_list:type_name -> centrifugal.centrifugo.api.DeviceTopicListResult
+	118, // 70: centrifugal.centrifugo.api.Reply.device_topic_update:type_name -> centrifugal.centrifugo.api.DeviceTopicUpdateResult
+	117, // 71: centrifugal.centrifugo.api.Reply.user_topic_list:type_name -> centrifugal.centrifugo.api.UserTopicListResult
+	119, // 72: centrifugal.centrifugo.api.Reply.user_topic_update:type_name -> centrifugal.centrifugo.api.UserTopicUpdateResult
+	134, // 73: centrifugal.centrifugo.api.Reply.send_push_notification:type_name -> centrifugal.centrifugo.api.SendPushNotificationResult
+	137, // 74: centrifugal.centrifugo.api.Reply.update_push_status:type_name -> centrifugal.centrifugo.api.UpdatePushStatusResult
+	140, // 75: centrifugal.centrifugo.api.Reply.cancel_push:type_name -> centrifugal.centrifugo.api.CancelPushResult
+	143, // 76: centrifugal.centrifugo.api.Reply.map_publish:type_name -> centrifugal.centrifugo.api.MapPublishResult
+	146, // 77: centrifugal.centrifugo.api.Reply.map_remove:type_name -> centrifugal.centrifugo.api.MapRemoveResult
+	149, // 78: centrifugal.centrifugo.api.Reply.map_read_state:type_name -> centrifugal.centrifugo.api.MapReadStateResult
+	153, // 79: centrifugal.centrifugo.api.Reply.map_read_stream:type_name -> centrifugal.centrifugo.api.MapReadStreamResult
+	156, // 80: centrifugal.centrifugo.api.Reply.map_stats:type_name -> centrifugal.centrifugo.api.MapStatsResult
+	159, // 81: centrifugal.centrifugo.api.Reply.map_clear:type_name -> centrifugal.centrifugo.api.MapClearResult
+	169, // 82: centrifugal.centrifugo.api.Reply.shared_poll_publish:type_name -> centrifugal.centrifugo.api.SharedPollPublishResult
+	163, // 83: centrifugal.centrifugo.api.Reply.map_export:type_name -> centrifugal.centrifugo.api.MapExportResult
+	166, // 84: centrifugal.centrifugo.api.Reply.map_import:type_name -> centrifugal.centrifugo.api.MapImportResult
+	173, // 85: centrifugal.centrifugo.api.Reply.scheduled_list:type_name -> centrifugal.centrifugo.api.ScheduledListResult
+	176, // 86: centrifugal.centrifugo.api.Reply.scheduled_cancel:type_name -> centrifugal.centrifugo.api.ScheduledCancelResult
+	0,   // 87: centrifugal.centrifugo.api.BatchRequest.commands:type_name -> centrifugal.centrifugo.api.Command
+	2,   // 88: centrifugal.centrifugo.api.BatchResponse.replies:type_name -> centrifugal.centrifugo.api.Reply
+	177, // 89: centrifugal.centrifugo.api.PublishRequest.tags:type_name -> centrifugal.centrifugo.api.PublishRequest.TagsEntry
+	1,   // 90: centrifugal.centrifugo.api.PublishResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	7,   // 91: centrifugal.centrifugo.api.PublishResponse.result:type_name -> centrifugal.centrifugo.api.PublishResult
+	178, // 92: centrifugal.centrifugo.api.BroadcastRequest.tags:type_name -> centrifugal.centrifugo.api.BroadcastRequest.TagsEntry
+	1,   // 93: centrifugal.centrifugo.api.BroadcastResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	10,  // 94: centrifugal.centrifugo.api.BroadcastResponse.result:type_name -> centrifugal.centrifugo.api.BroadcastResult
+	6,   // 95: centrifugal.centrifugo.api.BroadcastResult.responses:type_name -> centrifugal.centrifugo.api.PublishResponse
+	11,  // 96: centrifugal.centrifugo.api.FilterNode.nodes:type_name -> centrifugal.centrifugo.api.FilterNode
+	32,  // 97: centrifugal.centrifugo.api.SubscribeRequest.recover_since:type_name -> centrifugal.centrifugo.api.StreamPosition
+	16,  // 98: centrifugal.centrifugo.api.SubscribeRequest.override:type_name -> centrifugal.centrifugo.api.SubscribeOptionOverride
+	11,  // 99: centrifugal.centrifugo.api.SubscribeRequest.label_filter:type_name -> centrifugal.centrifugo.api.FilterNode
+	1,   // 100: centrifugal.centrifugo.api.SubscribeResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	17,  // 101: centrifugal.centrifugo.api.SubscribeResponse.result:type_name -> centrifugal.centrifugo.api.SubscribeResult
+	14,  // 102: centrifugal.centrifugo.api.SubscribeOptionOverride.presence:type_name -> centrifugal.centrifugo.api.BoolValue
+	14,  // 103: centrifugal.centrifugo.api.SubscribeOptionOverride.join_leave:type_name -> centrifugal.centrifugo.api.BoolValue
+	14,  // 104: centrifugal.centrifugo.api.SubscribeOptionOverride.force_recovery:type_name -> centrifugal.centrifugo.api.BoolValue
+	14,  // 105: centrifugal.centrifugo.api.SubscribeOptionOverride.force_positioning:type_name -> centrifugal.centrifugo.api.BoolValue
+	14,  // 106: centrifugal.centrifugo.api.SubscribeOptionOverride.force_push_join_leave:type_name -> centrifugal.centrifugo.api.BoolValue
+	11,  // 107: centrifugal.centrifugo.api.UnsubscribeRequest.label_filter:type_name -> centrifugal.centrifugo.api.FilterNode
+	1,   // 108: centrifugal.centrifugo.api.UnsubscribeResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	20,  // 109: centrifugal.centrifugo.api.UnsubscribeResponse.result:type_name -> centrifugal.centrifugo.api.UnsubscribeResult
+	21,  // 110: centrifugal.centrifugo.api.DisconnectRequest.disconnect:type_name -> centrifugal.centrifugo.api.Disconnect
+	11,  // 111: centrifugal.centrifugo.api.DisconnectRequest.label_filter:type_name -> centrifugal.centrifugo.api.FilterNode
+	1,   // 112: centrifugal.centrifugo.api.DisconnectResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	24,  // 113: centrifugal.centrifugo.api.DisconnectResponse.result:type_name -> centrifugal.centrifugo.api.DisconnectResult
+	1,   // 114: centrifugal.centrifugo.api.PresenceResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	28,  // 115: centrifugal.centrifugo.api.PresenceResponse.result:type_name -> centrifugal.centrifugo.api.PresenceResult
+	179, // 116: centrifugal.centrifugo.api.PresenceResult.presence:type_name -> centrifugal.centrifugo.api.PresenceResult.PresenceEntry
+	1,   // 117: centrifugal.centrifugo.api.PresenceStatsResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	31,  // 118: centrifugal.centrifugo.api.PresenceStatsResponse.result:type_name -> centrifugal.centrifugo.api.PresenceStatsResult
+	32,  // 119: centrifugal.centrifugo.api.HistoryRequest.since:type_name -> centrifugal.centrifugo.api.StreamPosition
+	1,   // 120: centrifugal.centrifugo.api.HistoryResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	36,  // 121: centrifugal.centrifugo.api.HistoryResponse.result:type_name -> centrifugal.centrifugo.api.HistoryResult
+	27,  // 122: centrifugal.centrifugo.api.Publication.info:type_name -> centrifugal.centrifugo.api.ClientInfo
+	180, // 123: centrifugal.centrifugo.api.Publication.tags:type_name -> centrifugal.centrifugo.api.Publication.TagsEntry
+	35,  // 124: centrifugal.centrifugo.api.HistoryResult.publications:type_name -> centrifugal.centrifugo.api.Publication
+	1,   // 125: centrifugal.centrifugo.api.HistoryRemoveResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	39,  // 126: centrifugal.centrifugo.api.HistoryRemoveResponse.result:type_name -> centrifugal.centrifugo.api.HistoryRemoveResult
+	1,   // 127: centrifugal.centrifugo.api.InfoResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	42,  // 128: centrifugal.centrifugo.api.InfoResponse.result:type_name -> centrifugal.centrifugo.api.InfoResult
+	49,  // 129: centrifugal.centrifugo.api.InfoResult.nodes:type_name -> centrifugal.centrifugo.api.NodeResult
+	1,   // 130: centrifugal.centrifugo.api.RPCResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	45,  // 131: centrifugal.centrifugo.api.RPCResponse.result:type_name -> centrifugal.centrifugo.api.RPCResult
+	11,  // 132: centrifugal.centrifugo.api.RefreshRequest.label_filter:type_name -> centrifugal.centrifugo.api.FilterNode
+	1,   // 133: centrifugal.centrifugo.api.RefreshResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	48,  // 134: centrifugal.centrifugo.api.RefreshResponse.result:type_name -> centrifugal.centrifugo.api.RefreshResult
+	50,  // 135: centrifugal.centrifugo.api.NodeResult.metrics:type_name -> centrifugal.centrifugo.api.Metrics
+	51,  // 136: centrifugal.centrifugo.api.NodeResult.process:type_name -> centrifugal.centrifugo.api.Process
+	181, // 137: centrifugal.centrifugo.api.Metrics.items:type_name -> centrifugal.centrifugo.api.Metrics.ItemsEntry
+	1,   // 138: centrifugal.centrifugo.api.ChannelsResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	54,  // 139: centrifugal.centrifugo.api.ChannelsResponse.result:type_name -> centrifugal.centrifugo.api.ChannelsResult
+	182, // 140: centrifugal.centrifugo.api.ChannelsResult.channels:type_name -> centrifugal.centrifugo.api.ChannelsResult.ChannelsEntry
+	11,  // 141: centrifugal.centrifugo.api.ConnectionsRequest.label_filter:type_name -> centrifugal.centrifugo.api.FilterNode
+	1,   // 142: centrifugal.centrifugo.api.ConnectionsResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	58,  // 143: centrifugal.centrifugo.api.ConnectionsResponse.result:type_name -> centrifugal.centrifugo.api.ConnectionsResult
+	183, // 144: centrifugal.centrifugo.api.ConnectionsResult.connections:type_name -> centrifugal.centrifugo.api.ConnectionsResult.ConnectionsEntry
+	60,  // 145: centrifugal.centrifugo.api.ConnectionInfo.state:type_name -> centrifugal.centrifugo.api.ConnectionState
+	184, // 146: centrifugal.centrifugo.api.ConnectionInfo.labels:type_name -> centrifugal.centrifugo.api.ConnectionInfo.LabelsEntry
+	185, // 147: centrifugal.centrifugo.api.ConnectionState.channels:type_name -> centrifugal.centrifugo.api.ConnectionState.ChannelsEntry
+	62,  // 148: centrifugal.centrifugo.api.ConnectionState.connection_token:type_name -> centrifugal.centrifugo.api.ConnectionTokenInfo
+	186, // 149: centrifugal.centrifugo.api.ConnectionState.subscription_tokens:type_name -> centrifugal.centrifugo.api.ConnectionState.SubscriptionTokensEntry
+	1,   // 150: centrifugal.centrifugo.api.UpdateUserStatusResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	66,  // 151: centrifugal.centrifugo.api.UpdateUserStatusResponse.result:type_name -> centrifugal.centrifugo.api.UpdateUserStatusResult
+	1,   // 152: centrifugal.centrifugo.api.GetUserStatusResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	69,  // 153: centrifugal.centrifugo.api.GetUserStatusResponse.result:type_name -> centrifugal.centrifugo.api.GetUserStatusResult
+	70,  // 154: centrifugal.centrifugo.api.GetUserStatusResult.statuses:type_name -> centrifugal.centrifugo.api.UserStatus
+	1,   // 155: centrifugal.centrifugo.api.DeleteUserStatusResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	73,  // 156: centrifugal.centrifugo.api.DeleteUserStatusResponse.result:type_name -> centrifugal.centrifugo.api.DeleteUserStatusResult
+	1,   // 157: centrifugal.centrifugo.api.BlockUserResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	75,  // 158: centrifugal.centrifugo.api.BlockUserResponse.result:type_name -> centrifugal.centrifugo.api.BlockUserResult
+	1,   // 159: centrifugal.centrifugo.api.UnblockUserResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	78,  // 160: centrifugal.centrifugo.api.UnblockUserResponse.result:type_name -> centrifugal.centrifugo.api.UnblockUserResult
+	1,   // 161: centrifugal.centrifugo.api.RevokeTokenResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	81,  // 162: centrifugal.centrifugo.api.RevokeTokenResponse.result:type_name -> centrifugal.centrifugo.api.RevokeTokenResult
+	1,   // 163: centrifugal.centrifugo.api.InvalidateUserTokensResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	84,  // 164: centrifugal.centrifugo.api.InvalidateUserTokensResponse.result:type_name -> centrifugal.centrifugo.api.InvalidateUserTokensResult
+	187, // 165: centrifugal.centrifugo.api.DeviceRegisterRequest.meta:type_name -> centrifugal.centrifugo.api.DeviceRegisterRequest.MetaEntry
+	89,  // 166: centrifugal.centrifugo.api.DeviceUpdateRequest.user_update:type_name -> centrifugal.centrifugo.api.DeviceUserUpdate
+	92,  // 167: centrifugal.centrifugo.api.DeviceUpdateRequest.meta_update:type_name -> centrifugal.centrifugo.api.DeviceMetaUpdate
+	93,  // 168: centrifugal.centrifugo.api.DeviceUpdateRequest.topics_update:type_name -> centrifugal.centrifugo.api.DeviceTopicsUpdate
+	90,  // 169: centrifugal.centrifugo.api.DeviceUpdateRequest.timezone_update:type_name -> centrifugal.centrifugo.api.DeviceTimezoneUpdate
+	91,  // 170: centrifugal.centrifugo.api.DeviceUpdateRequest.locale_update:type_name -> centrifugal.centrifugo.api.DeviceLocaleUpdate
+	188, // 171: centrifugal.centrifugo.api.DeviceMetaUpdate.meta:type_name -> centrifugal.centrifugo.api.DeviceMetaUpdate.MetaEntry
+	94,  // 172: centrifugal.centrifugo.api.DeviceListRequest.filter:type_name -> centrifugal.centrifugo.api.DeviceFilter
+	96,  // 173: centrifugal.centrifugo.api.DeviceTopicListRequest.filter:type_name -> centrifugal.centrifugo.api.DeviceTopicFilter
+	98,  // 174: centrifugal.centrifugo.api.UserTopicListRequest.filter:type_name -> centrifugal.centrifugo.api.UserTopicFilter
+	1,   // 175: centrifugal.centrifugo.api.DeviceRegisterResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	110, // 176: centrifugal.centrifugo.api.DeviceRegisterResponse.result:type_name -> centrifugal.centrifugo.api.DeviceRegisterResult
+	1,   // 177: centrifugal.centrifugo.api.DeviceUpdateResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	111, // 178: centrifugal.centrifugo.api.DeviceUpdateResponse.result:type_name -> centrifugal.centrifugo.api.DeviceUpdateResult
+	1,   // 179: centrifugal.centrifugo.api.DeviceRemoveResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	112, // 180: centrifugal.centrifugo.api.DeviceRemoveResponse.result:type_name -> centrifugal.centrifugo.api.DeviceRemoveResult
+	1,   // 181: centrifugal.centrifugo.api.DeviceListResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	113, // 182: centrifugal.centrifugo.api.DeviceListResponse.result:type_name -> centrifugal.centrifugo.api.DeviceListResult
+	1,   // 183: centrifugal.centrifugo.api.DeviceTopicListResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	115, // 184: centrifugal.centrifugo.api.DeviceTopicListResponse.result:type_name -> centrifugal.centrifugo.api.DeviceTopicListResult
+	1,   // 185: centrifugal.centrifugo.api.UserTopicListResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	117, // 186: centrifugal.centrifugo.api.UserTopicListResponse.result:type_name -> centrifugal.centrifugo.api.UserTopicListResult
+	1,   // 187: centrifugal.centrifugo.api.DeviceTopicUpdateResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	118, // 188: centrifugal.centrifugo.api.DeviceTopicUpdateResponse.result:type_name -> centrifugal.centrifugo.api.DeviceTopicUpdateResult
+	1,   // 189: centrifugal.centrifugo.api.UserTopicUpdateResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	119, // 190: centrifugal.centrifugo.api.UserTopicUpdateResponse.result:type_name -> centrifugal.centrifugo.api.UserTopicUpdateResult
+	114, // 191: centrifugal.centrifugo.api.DeviceListResult.items:type_name -> centrifugal.centrifugo.api.Device
+	189, // 192: centrifugal.centrifugo.api.Device.meta:type_name -> centrifugal.centrifugo.api.Device.MetaEntry
+	116, // 193: centrifugal.centrifugo.api.DeviceTopicListResult.items:type_name -> centrifugal.centrifugo.api.DeviceTopic
+	114, // 194: centrifugal.centrifugo.api.DeviceTopic.device:type_name -> centrifugal.centrifugo.api.Device
+	120, // 195: centrifugal.centrifugo.api.UserTopicListResult.items:type_name -> centrifugal.centrifugo.api.UserTopic
+	94,  // 196: centrifugal.centrifugo.api.PushRecipient.filter:type_name -> centrifugal.centrifugo.api.DeviceFilter
+	123, // 197: centrifugal.centrifugo.api.PushNotification.fcm:type_name -> centrifugal.centrifugo.api.FcmPushNotification
+	124, // 198: centrifugal.centrifugo.api.PushNotification.hms:type_name -> centrifugal.centrifugo.api.HmsPushNotification
+	125, // 199: centrifugal.centrifugo.api.PushNotification.apns:type_name -> centrifugal.centrifugo.api.ApnsPushNotification
+	126, // 200: centrifugal.centrifugo.api.PushNotification.webpush:type_name -> centrifugal.centrifugo.api.WebPushPushNotification
+	190, // 201: centrifugal.centrifugo.api.ApnsPushNotification.headers:type_name -> centrifugal.centrifugo.api.ApnsPushNotification.HeadersEntry
+	191, // 202: centrifugal.centrifugo.api.WebPushPushNotification.headers:type_name -> centrifugal.centrifugo.api.WebPushPushNotification.HeadersEntry
+	121, // 203: centrifugal.centrifugo.api.SendPushNotificationRequest.recipient:type_name -> centrifugal.centrifugo.api.PushRecipient
+	122, // 204: centrifugal.centrifugo.api.SendPushNotificationRequest.notification:type_name -> centrifugal.centrifugo.api.PushNotification
+	129, // 205: centrifugal.centrifugo.api.SendPushNotificationRequest.limit_strategy:type_name -> centrifugal.centrifugo.api.PushLimitStrategy
+	192, // 206: centrifugal.centrifugo.api.SendPushNotificationRequest.localizations:type_name -> centrifugal.centrifugo.api.SendPushNotificationRequest.LocalizationsEntry
+	193, // 207: centrifugal.centrifugo.api.PushLocalization.translations:type_name -> centrifugal.centrifugo.api.PushLocalization.TranslationsEntry
+	131, // 208: centrifugal.centrifugo.api.PushLimitStrategy.rate_limit:type_name -> centrifugal.centrifugo.api.PushRateLimitStrategy
+	130, // 209: centrifugal.centrifugo.api.PushLimitStrategy.time_limit:type_name -> centrifugal.centrifugo.api.PushTimeLimitStrategy
+	132, // 210: centrifugal.centrifugo.api.PushRateLimitStrategy.policies:type_name -> centrifugal.centrifugo.api.RateLimitPolicy
+	1,   // 211: centrifugal.centrifugo.api.SendPushNotificationResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	134, // 212: centrifugal.centrifugo.api.SendPushNotificationResponse.result:type_name -> centrifugal.centrifugo.api.SendPushNotificationResult
+	1,   // 213: centrifugal.centrifugo.api.UpdatePushStatusResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	137, // 214: centrifugal.centrifugo.api.UpdatePushStatusResponse.result:type_name -> centrifugal.centrifugo.api.UpdatePushStatusResult
+	1,   // 215: centrifugal.centrifugo.api.CancelPushResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	140, // 216: centrifugal.centrifugo.api.CancelPushResponse.result:type_name -> centrifugal.centrifugo.api.CancelPushResult
+	194, // 217: centrifugal.centrifugo.api.MapPublishRequest.tags:type_name -> centrifugal.centrifugo.api.MapPublishRequest.TagsEntry
+	1,   // 218: centrifugal.centrifugo.api.MapPublishResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	143, // 219: centrifugal.centrifugo.api.MapPublishResponse.result:type_name -> centrifugal.centrifugo.api.MapPublishResult
+	1,   // 220: centrifugal.centrifugo.api.MapRemoveResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	146, // 221: centrifugal.centrifugo.api.MapRemoveResponse.result:type_name -> centrifugal.centrifugo.api.MapRemoveResult
+	1,   // 222: centrifugal.centrifugo.api.MapReadStateResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	149, // 223: centrifugal.centrifugo.api.MapReadStateResponse.result:type_name -> centrifugal.centrifugo.api.MapReadStateResult
+	150, // 224: centrifugal.centrifugo.api.MapReadStateResult.entries:type_name -> centrifugal.centrifugo.api.MapEntry
+	195, // 225: centrifugal.centrifugo.api.MapEntry.tags:type_name -> centrifugal.centrifugo.api.MapEntry.TagsEntry
+	27,  // 226: centrifugal.centrifugo.api.MapEntry.info:type_name -> centrifugal.centrifugo.api.ClientInfo
+	1,   // 227: centrifugal.centrifugo.api.MapReadStreamResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	153, // 228: centrifugal.centrifugo.api.MapReadStreamResponse.result:type_name -> centrifugal.centrifugo.api.MapReadStreamResult
+	150, // 229: centrifugal.centrifugo.api.MapReadStreamResult.entries:type_name -> centrifugal.centrifugo.api.MapEntry
+	1,   // 230: centrifugal.centrifugo.api.MapStatsResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	156, // 231: centrifugal.centrifugo.api.MapStatsResponse.result:type_name -> centrifugal.centrifugo.api.MapStatsResult
+	1,   // 232: centrifugal.centrifugo.api.MapClearResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	159, // 233: centrifugal.centrifugo.api.MapClearResponse.result:type_name -> centrifugal.centrifugo.api.MapClearResult
+	150, // 234: centrifugal.centrifugo.api.MapSnapshot.entries:type_name -> centrifugal.centrifugo.api.MapEntry
+	1,   // 235: centrifugal.centrifugo.api.MapExportResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	163, // 236: centrifugal.centrifugo.api.MapExportResponse.result:type_name -> centrifugal.centrifugo.api.MapExportResult
+	160, // 237: centrifugal.centrifugo.api.MapExportResult.snapshot:type_name -> centrifugal.centrifugo.api.MapSnapshot
+	160, // 238: centrifugal.centrifugo.api.MapImportRequest.snapshot:type_name -> centrifugal.centrifugo.api.MapSnapshot
+	1,   // 239: centrifugal.centrifugo.api.MapImportResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	166, // 240: centrifugal.centrifugo.api.MapImportResponse.result:type_name -> centrifugal.centrifugo.api.MapImportResult
+	1,   // 241: centrifugal.centrifugo.api.SharedPollPublishResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	169, // 242: centrifugal.centrifugo.api.SharedPollPublishResponse.result:type_name -> centrifugal.centrifugo.api.SharedPollPublishResult
+	196, // 243: centrifugal.centrifugo.api.ScheduledPublication.tags:type_name -> centrifugal.centrifugo.api.ScheduledPublication.TagsEntry
+	1,   // 244: centrifugal.centrifugo.api.ScheduledListResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	173, // 245: centrifugal.centrifugo.api.ScheduledListResponse.result:type_name -> centrifugal.centrifugo.api.ScheduledListResult
+	170, // 246: centrifugal.centrifugo.api.ScheduledListResult.publications:type_name -> centrifugal.centrifugo.api.ScheduledPublication
+	1,   // 247: centrifugal.centrifugo.api.ScheduledCancelResponse.error:type_name -> centrifugal.centrifugo.api.Error
+	176, // 248: centrifugal.centrifugo.api.ScheduledCancelResponse.result:type_name -> centrifugal.centrifugo.api.ScheduledCancelResult
+	27,  // 249: centrifugal.centrifugo.api.PresenceResult.PresenceEntry.value:type_name -> centrifugal.centrifugo.api.ClientInfo
+	55,  // 250: centrifugal.centrifugo.api.ChannelsResult.ChannelsEntry.value:type_name -> centrifugal.centrifugo.api.ChannelInfo
+	59,  // 251: centrifugal.centrifugo.api.ConnectionsResult.ConnectionsEntry.value:type_name -> centrifugal.centrifugo.api.ConnectionInfo
+	61,  // 252: centrifugal.centrifugo.api.ConnectionState.ChannelsEntry.value:type_name -> centrifugal.centrifugo.api.ChannelContext
+	63,  // 253: centrifugal.centrifugo.api.ConnectionState.SubscriptionTokensEntry.value:type_name -> centrifugal.centrifugo.api.SubscriptionTokenInfo
+	128, // 254: centrifugal.centrifugo.api.SendPushNotificationRequest.LocalizationsEntry.value:type_name -> centrifugal.centrifugo.api.PushLocalization
+	3,   // 255: centrifugal.centrifugo.api.CentrifugoApi.Batch:input_type -> centrifugal.centrifugo.api.BatchRequest
+	5,   // 256: centrifugal.centrifugo.api.CentrifugoApi.Publish:input_type -> centrifugal.centrifugo.api.PublishRequest
+	8,   // 257: centrifugal.centrifugo.api.CentrifugoApi.Broadcast:input_type -> centrifugal.centrifugo.api.BroadcastRequest
+	12,  // 258: centrifugal.centrifugo.api.CentrifugoApi.Subscribe:input_type -> centrifugal.centrifugo.api.SubscribeRequest
+	18,  // 259: centrifugal.centrifugo.api.CentrifugoApi.Unsubscribe:input_type -> centrifugal.centrifugo.api.UnsubscribeRequest
+	22,  // 260: centrifugal.centrifugo.api.CentrifugoApi.Disconnect:input_type -> centrifugal.centrifugo.api.DisconnectRequest
+	25,  // 261: centrifugal.centrifugo.api.CentrifugoApi.Presence:input_type -> centrifugal.centrifugo.api.PresenceRequest
+	29,  // 262: centrifugal.centrifugo.api.CentrifugoApi.PresenceStats:input_type -> centrifugal.centrifugo.api.PresenceStatsRequest
+	33,  // 263: centrifugal.centrifugo.api.CentrifugoApi.History:input_type -> centrifugal.centrifugo.api.HistoryRequest
+	37,  // 264: centrifugal.centrifugo.api.CentrifugoApi.HistoryRemove:input_type -> centrifugal.centrifugo.api.HistoryRemoveRequest
+	40,  // 265: centrifugal.centrifugo.api.CentrifugoApi.Info:input_type -> centrifugal.centrifugo.api.InfoRequest
+	43,  // 266: centrifugal.centrifugo.api.CentrifugoApi.RPC:input_type -> centrifugal.centrifugo.api.RPCRequest
+	46,  // 267: centrifugal.centrifugo.api.CentrifugoApi.Refresh:input_type -> centrifugal.centrifugo.api.RefreshRequest
+	52,  // 268: centrifugal.centrifugo.api.CentrifugoApi.Channels:input_type -> centrifugal.centrifugo.api.ChannelsRequest
+	56,  // 269: centrifugal.centrifugo.api.CentrifugoApi.Connections:input_type -> centrifugal.centrifugo.api.ConnectionsRequest
+	64,  // 270: centrifugal.centrifugo.api.CentrifugoApi.UpdateUserStatus:input_type -> centrifugal.centrifugo.api.UpdateUserStatusRequest
+	67,  // 271: centrifugal.centrifugo.api.CentrifugoApi.GetUserStatus:input_type -> centrifugal.centrifugo.api.GetUserStatusRequest
+	71,  // 272: centrifugal.centrifugo.api.CentrifugoApi.DeleteUserStatus:input_type -> centrifugal.centrifugo.api.DeleteUserStatusRequest
+	74,  // 273: centrifugal.centrifugo.api.CentrifugoApi.BlockUser:input_type -> centrifugal.centrifugo.api.BlockUserRequest
+	77,  // 274: centrifugal.centrifugo.api.CentrifugoApi.UnblockUser:input_type -> centrifugal.centrifugo.api.UnblockUserRequest
+	80,  // 275: centrifugal.centrifugo.api.CentrifugoApi.RevokeToken:input_type -> centrifugal.centrifugo.api.RevokeTokenRequest
+	83,  // 276: centrifugal.centrifugo.api.CentrifugoApi.InvalidateUserTokens:input_type -> centrifugal.centrifugo.api.InvalidateUserTokensRequest
+	86,  // 277: centrifugal.centrifugo.api.CentrifugoApi.DeviceRegister:input_type -> centrifugal.centrifugo.api.DeviceRegisterRequest
+	87,  // 278: centrifugal.centrifugo.api.CentrifugoApi.DeviceUpdate:input_type -> centrifugal.centrifugo.api.DeviceUpdateRequest
+	88,  // 279: centrifugal.centrifugo.api.CentrifugoApi.DeviceRemove:input_type -> centrifugal.centrifugo.api.DeviceRemoveRequest
+	95,  // 280: centrifugal.centrifugo.api.CentrifugoApi.DeviceList:input_type -> centrifugal.centrifugo.api.DeviceListRequest
+	97,  // 281: centrifugal.centrifugo.api.CentrifugoApi.DeviceTopicList:input_type -> centrifugal.centrifugo.api.DeviceTopicListRequest
+	100, // 282: centrifugal.centrifugo.api.CentrifugoApi.DeviceTopicUpdate:input_type -> centrifugal.centrifugo.api.DeviceTopicUpdateRequest
+	99,  // 283: centrifugal.centrifugo.api.CentrifugoApi.UserTopicList:input_type -> centrifugal.centrifugo.api.UserTopicListRequest
+	101, // 284: centrifugal.centrifugo.api.CentrifugoApi.UserTopicUpdate:input_type -> centrifugal.centrifugo.api.UserTopicUpdateRequest
+	127, // 285: centrifugal.centrifugo.api.CentrifugoApi.SendPushNotification:input_type -> centrifugal.centrifugo.api.SendPushNotificationRequest
+	135, // 286: centrifugal.centrifugo.api.CentrifugoApi.UpdatePushStatus:input_type -> centrifugal.centrifugo.api.UpdatePushStatusRequest
+	138, // 287: centrifugal.centrifugo.api.CentrifugoApi.CancelPush:input_type -> centrifugal.centrifugo.api.CancelPushRequest
+	141, // 288: centrifugal.centrifugo.api.CentrifugoApi.MapPublish:input_type -> centrifugal.centrifugo.api.MapPublishRequest
+	144, // 289: centrifugal.centrifugo.api.CentrifugoApi.MapRemove:input_type -> centrifugal.centrifugo.api.MapRemoveRequest
+	147, // 290: centrifugal.centrifugo.api.CentrifugoApi.MapReadState:input_type -> centrifugal.centrifugo.api.MapReadStateRequest
+	151, // 291: centrifugal.centrifugo.api.CentrifugoApi.MapReadStream:input_type -> centrifugal.centrifugo.api.MapReadStreamRequest
+	154, // 292: centrifugal.centrifugo.api.CentrifugoApi.MapStats:input_type -> centrifugal.centrifugo.api.MapStatsRequest
+	157, // 293: centrifugal.centrifugo.api.CentrifugoApi.MapClear:input_type -> centrifugal.centrifugo.api.MapClearRequest
+	167, // 294: centrifugal.centrifugo.api.CentrifugoApi.SharedPollPublish:input_type -> centrifugal.centrifugo.api.SharedPollPublishRequest
+	161, // 295: centrifugal.centrifugo.api.CentrifugoApi.MapExport:input_type -> centrifugal.centrifugo.api.MapExportRequest
+	164, // 296: centrifugal.centrifugo.api.CentrifugoApi.MapImport:input_type -> centrifugal.centrifugo.api.MapImportRequest
+	171, // 297: centrifugal.centrifugo.api.CentrifugoApi.ScheduledList:input_type -> centrifugal.centrifugo.api.ScheduledListRequest
+	174, // 298: centrifugal.centrifugo.api.CentrifugoApi.ScheduledCancel:input_type -> centrifugal.centrifugo.api.ScheduledCancelRequest
+	4,   // 299: centrifugal.centrifugo.api.CentrifugoApi.Batch:output_type -> centrifugal.centrifugo.api.BatchResponse
+	6,   // 300: centrifugal.centrifugo.api.CentrifugoApi.Publish:output_type -> centrifugal.centrifugo.api.PublishResponse
+	9,   // 301: centrifugal.centrifugo.api.CentrifugoApi.Broadcast:output_type -> centrifugal.centrifugo.api.BroadcastResponse
+	13,  // 302: centrifugal.centrifugo.api.CentrifugoApi.Subscribe:output_type -> centrifugal.centrifugo.api.SubscribeResponse
+	19,  // 303: centrifugal.centrifugo.api.CentrifugoApi.Unsubscribe:output_type -> centrifugal.centrifugo.api.UnsubscribeResponse
+	23,  // 304: centrifugal.centrifugo.api.CentrifugoApi.Disconnect:output_type -> centrifugal.centrifugo.api.DisconnectResponse
+	26,  // 305: centrifugal.centrifugo.api.CentrifugoApi.Presence:output_type -> centrifugal.centrifugo.api.PresenceResponse
+	30,  // 306: centrifugal.centrifugo.api.CentrifugoApi.PresenceStats:output_type -> centrifugal.centrifugo.api.PresenceStatsResponse
+	34,  // 307: centrifugal.centrifugo.api.CentrifugoApi.History:output_type -> centrifugal.centrifugo.api.HistoryResponse
+	38,  // 308: centrifugal.centrifugo.api.CentrifugoApi.HistoryRemove:output_type -> centrifugal.centrifugo.api.HistoryRemoveResponse
+	41,  // 309: centrifugal.centrifugo.api.CentrifugoApi.Info:output_type -> centrifugal.centrifugo.api.InfoResponse
+	44,  // 310: centrifugal.centrifugo.api.CentrifugoApi.RPC:output_type -> centrifugal.centrifugo.api.RPCResponse
+	47,  // 311: centrifugal.centrifugo.api.CentrifugoApi.Refresh:output_type -> centrifugal.centrifugo.api.RefreshResponse
+	53,  // 312: centrifugal.centrifugo.api.CentrifugoApi.Channels:output_type -> centrifugal.centrifugo.api.ChannelsResponse
+	57,  // 313: centrifugal.centrifugo.api.CentrifugoApi.Connections:output_type -> centrifugal.centrifugo.api.ConnectionsResponse
+	65,  // 314: centrifugal.centrifugo.api.CentrifugoApi.UpdateUserStatus:output_type -> centrifugal.centrifugo.api.UpdateUserStatusResponse
+	68,  // 315: centrifugal.centrifugo.api.CentrifugoApi.GetUserStatus:output_type -> centrifugal.centrifugo.api.GetUserStatusResponse
+	72,  // 316: centrifugal.centrifugo.api.CentrifugoApi.DeleteUserStatus:output_type -> centrifugal.centrifugo.api.DeleteUserStatusResponse
+	76,  // 317: centrifugal.centrifugo.api.CentrifugoApi.BlockUser:output_type -> centrifugal.centrifugo.api.BlockUserResponse
+	79,  // 318: centrifugal.centrifugo.api.CentrifugoApi.UnblockUser:output_type -> centrifugal.centrifugo.api.UnblockUserResponse
+	82,  // 319: centrifugal.centrifugo.api.CentrifugoApi.RevokeToken:output_type -> centrifugal.centrifugo.api.RevokeTokenResponse
+	85,  // 320: centrifugal.centrifugo.api.CentrifugoApi.InvalidateUserTokens:output_type -> centrifugal.centrifugo.api.InvalidateUserTokensResponse
+	102, // 321: centrifugal.centrifugo.api.CentrifugoApi.DeviceRegister:output_type -> centrifugal.centrifugo.api.DeviceRegisterResponse
+	103, // 322: centrifugal.centrifugo.api.CentrifugoApi.DeviceUpdate:output_type -> centrifugal.centrifugo.api.DeviceUpdateResponse
+	104, // 323: centrifugal.centrifugo.api.CentrifugoApi.DeviceRemove:output_type -> centrifugal.centrifugo.api.DeviceRemoveResponse
+	105, // 324: centrifugal.centrifugo.api.CentrifugoApi.DeviceList:output_type -> centrifugal.centrifugo.api.DeviceListResponse
+	106, // 325: centrifugal.centrifugo.api.CentrifugoApi.DeviceTopicList:output_type -> centrifugal.centrifugo.api.DeviceTopicListResponse
+	108, // 326: centrifugal.centrifugo.api.CentrifugoApi.DeviceTopicUpdate:output_type -> centrifugal.centrifugo.api.DeviceTopicUpdateResponse
+	107, // 327: centrifugal.centrifugo.api.CentrifugoApi.UserTopicList:output_type -> centrifugal.centrifugo.api.UserTopicListResponse
+	109, // 328: centrifugal.centrifugo.api.CentrifugoApi.UserTopicUpdate:output_type -> centrifugal.centrifugo.api.UserTopicUpdateResponse
+	133, // 329: centrifugal.centrifugo.api.CentrifugoApi.SendPushNotification:output_type -> centrifugal.centrifugo.api.SendPushNotificationResponse
+	136, // 330: centrifugal.centrifugo.api.CentrifugoApi.UpdatePushStatus:output_type -> centrifugal.centrifugo.api.UpdatePushStatusResponse
+	139, // 331: centrifugal.centrifugo.api.CentrifugoApi.CancelPush:output_type -> centrifugal.centrifugo.api.CancelPushResponse
+	142, // 332: centrifugal.centrifugo.api.CentrifugoApi.MapPublish:output_type -> centrifugal.centrifugo.api.MapPublishResponse
+	145, // 333: centrifugal.centrifugo.api.CentrifugoApi.MapRemove:output_type -> centrifugal.centrifugo.api.MapRemoveResponse
+	148, // 334: centrifugal.centrifugo.api.CentrifugoApi.MapReadState:output_type -> centrifugal.centrifugo.api.MapReadStateResponse
+	152, // 335: centrifugal.centrifugo.api.CentrifugoApi.MapReadStream:output_type -> centrifugal.centrifugo.api.MapReadStreamResponse
+	155, // 336: centrifugal.centrifugo.api.CentrifugoApi.MapStats:output_type -> centrifugal.centrifugo.api.MapStatsResponse
+	158, // 337: centrifugal.centrifugo.api.CentrifugoApi.MapClear:output_type -> centrifugal.centrifugo.api.MapClearResponse
+	168, // 338: centrifugal.centrifugo.api.CentrifugoApi.SharedPollPublish:output_type -> centrifugal.centrifugo.api.SharedPollPublishResponse
+	162, // 339: centrifugal.centrifugo.api.CentrifugoApi.MapExport:output_type -> centrifugal.centrifugo.api.MapExportResponse
+	165, // 340: centrifugal.centrifugo.api.CentrifugoApi.MapImport:output_type -> centrifugal.centrifugo.api.MapImportResponse
+	172, // 341: centrifugal.centrifugo.api.CentrifugoApi.ScheduledList:output_type -> centrifugal.centrifugo.api.ScheduledListResponse
+	175, // 342: centrifugal.centrifugo.api.CentrifugoApi.ScheduledCancel:output_type -> centrifugal.centrifugo.api.ScheduledCancelResponse
+	299, // [299:343] is the sub-list for method output_type
+	255, // [255:299] is the sub-list for method input_type
+	255, // [255:255] is the sub-list for extension type_name
+	255, // [255:255] is the sub-list for extension extendee
+	0,   // [0:255] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_init() }
@@ -11369,7 +12466,7 @@ func file_api_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_rawDesc), len(file_api_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   182,
+			NumMessages:   197,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
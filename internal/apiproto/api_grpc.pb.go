@@ -59,6 +59,10 @@ const (
 	CentrifugoApi_MapStats_FullMethodName             = "/centrifugal.centrifugo.api.CentrifugoApi/MapStats"
 	CentrifugoApi_MapClear_FullMethodName             = "/centrifugal.centrifugo.api.CentrifugoApi/MapClear"
 	CentrifugoApi_SharedPollPublish_FullMethodName    = "/centrifugal.centrifugo.api.CentrifugoApi/SharedPollPublish"
+	CentrifugoApi_MapExport_FullMethodName            = "/centrifugal.centrifugo.api.CentrifugoApi/MapExport"
+	CentrifugoApi_MapImport_FullMethodName            = "/centrifugal.centrifugo.api.CentrifugoApi/MapImport"
+	CentrifugoApi_ScheduledList_FullMethodName        = "/centrifugal.centrifugo.api.CentrifugoApi/ScheduledList"
+	CentrifugoApi_ScheduledCancel_FullMethodName      = "/centrifugal.centrifugo.api.CentrifugoApi/ScheduledCancel"
 )
 
 // CentrifugoApiClient is the client API for CentrifugoApi service.
@@ -105,6 +109,10 @@ type CentrifugoApiClient interface {
 	MapStats(ctx context.Context, in *MapStatsRequest, opts ...grpc.CallOption) (*MapStatsResponse, error)
 	MapClear(ctx context.Context, in *MapClearRequest, opts ...grpc.CallOption) (*MapClearResponse, error)
 	SharedPollPublish(ctx context.Context, in *SharedPollPublishRequest, opts ...grpc.CallOption) (*SharedPollPublishResponse, error)
+	MapExport(ctx context.Context, in *MapExportRequest, opts ...grpc.CallOption) (*MapExportResponse, error)
+	MapImport(ctx context.Context, in *MapImportRequest, opts ...grpc.CallOption) (*MapImportResponse, error)
+	ScheduledList(ctx context.Context, in *ScheduledListRequest, opts ...grpc.CallOption) (*ScheduledListResponse, error)
+	ScheduledCancel(ctx context.Context, in *ScheduledCancelRequest, opts ...grpc.CallOption) (*ScheduledCancelResponse, error)
 }
 
 type centrifugoApiClient struct {
@@ -515,6 +523,46 @@ func (c *centrifugoApiClient) SharedPollPublish(ctx context.Context, in *SharedP
 	return out, nil
 }
 
+func (c *centrifugoApiClient) MapExport(ctx context.Context, in *MapExportRequest, opts ...grpc.CallOption) (*MapExportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MapExportResponse)
+	err := c.cc.Invoke(ctx, CentrifugoApi_MapExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *centrifugoApiClient) MapImport(ctx context.Context, in *MapImportRequest, opts ...grpc.CallOption) (*MapImportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MapImportResponse)
+	err := c.cc.Invoke(ctx, CentrifugoApi_MapImport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *centrifugoApiClient) ScheduledList(ctx context.Context, in *ScheduledListRequest, opts ...grpc.CallOption) (*ScheduledListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScheduledListResponse)
+	err := c.cc.Invoke(ctx, CentrifugoApi_ScheduledList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *centrifugoApiClient) ScheduledCancel(ctx context.Context, in *ScheduledCancelRequest, opts ...grpc.CallOption) (*ScheduledCancelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScheduledCancelResponse)
+	err := c.cc.Invoke(ctx, CentrifugoApi_ScheduledCancel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CentrifugoApiServer is the server API for CentrifugoApi service.
 // All implementations must embed UnimplementedCentrifugoApiServer
 // for forward compatibility.
@@ -559,6 +607,10 @@ type CentrifugoApiServer interface {
 	MapStats(context.Context, *MapStatsRequest) (*MapStatsResponse, error)
 	MapClear(context.Context, *MapClearRequest) (*MapClearResponse, error)
 	SharedPollPublish(context.Context, *SharedPollPublishRequest) (*SharedPollPublishResponse, error)
+	MapExport(context.Context, *MapExportRequest) (*MapExportResponse, error)
+	MapImport(context.Context, *MapImportRequest) (*MapImportResponse, error)
+	ScheduledList(context.Context, *ScheduledListRequest) (*ScheduledListResponse, error)
+	ScheduledCancel(context.Context, *ScheduledCancelRequest) (*ScheduledCancelResponse, error)
 	mustEmbedUnimplementedCentrifugoApiServer()
 }
 
@@ -689,6 +741,18 @@ func (UnimplementedCentrifugoApiServer) MapClear(context.Context, *MapClearReque
 func (UnimplementedCentrifugoApiServer) SharedPollPublish(context.Context, *SharedPollPublishRequest) (*SharedPollPublishResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method SharedPollPublish not implemented")
 }
+func (UnimplementedCentrifugoApiServer) MapExport(context.Context, *MapExportRequest) (*MapExportResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method MapExport not implemented")
+}
+func (UnimplementedCentrifugoApiServer) MapImport(context.Context, *MapImportRequest) (*MapImportResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method MapImport not implemented")
+}
+func (UnimplementedCentrifugoApiServer) ScheduledList(context.Context, *ScheduledListRequest) (*ScheduledListResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ScheduledList not implemented")
+}
+func (UnimplementedCentrifugoApiServer) ScheduledCancel(context.Context, *ScheduledCancelRequest) (*ScheduledCancelResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ScheduledCancel not implemented")
+}
 func (UnimplementedCentrifugoApiServer) mustEmbedUnimplementedCentrifugoApiServer() {}
 func (UnimplementedCentrifugoApiServer) testEmbeddedByValue()                       {}
 
@@ -1430,6 +1494,78 @@ func _CentrifugoApi_SharedPollPublish_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CentrifugoApi_MapExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MapExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CentrifugoApiServer).MapExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CentrifugoApi_MapExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CentrifugoApiServer).MapExport(ctx, req.(*MapExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CentrifugoApi_MapImport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MapImportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CentrifugoApiServer).MapImport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CentrifugoApi_MapImport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CentrifugoApiServer).MapImport(ctx, req.(*MapImportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CentrifugoApi_ScheduledList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduledListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CentrifugoApiServer).ScheduledList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CentrifugoApi_ScheduledList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CentrifugoApiServer).ScheduledList(ctx, req.(*ScheduledListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CentrifugoApi_ScheduledCancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduledCancelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CentrifugoApiServer).ScheduledCancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CentrifugoApi_ScheduledCancel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CentrifugoApiServer).ScheduledCancel(ctx, req.(*ScheduledCancelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CentrifugoApi_ServiceDesc is the grpc.ServiceDesc for CentrifugoApi service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1597,6 +1733,22 @@ var CentrifugoApi_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SharedPollPublish",
 			Handler:    _CentrifugoApi_SharedPollPublish_Handler,
 		},
+		{
+			MethodName: "MapExport",
+			Handler:    _CentrifugoApi_MapExport_Handler,
+		},
+		{
+			MethodName: "MapImport",
+			Handler:    _CentrifugoApi_MapImport_Handler,
+		},
+		{
+			MethodName: "ScheduledList",
+			Handler:    _CentrifugoApi_ScheduledList_Handler,
+		},
+		{
+			MethodName: "ScheduledCancel",
+			Handler:    _CentrifugoApi_ScheduledCancel_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api.proto",
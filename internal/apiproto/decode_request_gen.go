@@ -223,3 +223,43 @@ func (d *JSONRequestDecoder) DecodeSharedPollPublish(data []byte) (*SharedPollPu
 	}
 	return &p, nil
 }
+
+// DecodeMapExport ...
+func (d *JSONRequestDecoder) DecodeMapExport(data []byte) (*MapExportRequest, error) {
+	var p MapExportRequest
+	err := json.Unmarshal(data, &p)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// DecodeMapImport ...
+func (d *JSONRequestDecoder) DecodeMapImport(data []byte) (*MapImportRequest, error) {
+	var p MapImportRequest
+	err := json.Unmarshal(data, &p)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// DecodeScheduledList ...
+func (d *JSONRequestDecoder) DecodeScheduledList(data []byte) (*ScheduledListRequest, error) {
+	var p ScheduledListRequest
+	err := json.Unmarshal(data, &p)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// DecodeScheduledCancel ...
+func (d *JSONRequestDecoder) DecodeScheduledCancel(data []byte) (*ScheduledCancelRequest, error) {
+	var p ScheduledCancelRequest
+	err := json.Unmarshal(data, &p)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
@@ -115,3 +115,23 @@ func (e *JSONResponseEncoder) EncodeMapClear(response *MapClearResponse) ([]byte
 func (e *JSONResponseEncoder) EncodeSharedPollPublish(response *SharedPollPublishResponse) ([]byte, error) {
 	return json.Marshal(response)
 }
+
+// EncodeMapExport ...
+func (e *JSONResponseEncoder) EncodeMapExport(response *MapExportResponse) ([]byte, error) {
+	return json.Marshal(response)
+}
+
+// EncodeMapImport ...
+func (e *JSONResponseEncoder) EncodeMapImport(response *MapImportResponse) ([]byte, error) {
+	return json.Marshal(response)
+}
+
+// EncodeScheduledList ...
+func (e *JSONResponseEncoder) EncodeScheduledList(response *ScheduledListResponse) ([]byte, error) {
+	return json.Marshal(response)
+}
+
+// EncodeScheduledCancel ...
+func (e *JSONResponseEncoder) EncodeScheduledCancel(response *ScheduledCancelResponse) ([]byte, error) {
+	return json.Marshal(response)
+}
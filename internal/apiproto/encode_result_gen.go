@@ -113,3 +113,23 @@ func (e *JSONResultEncoder) EncodeMapClear(res *MapClearResult) ([]byte, error)
 func (e *JSONResultEncoder) EncodeSharedPollPublish(res *SharedPollPublishResult) ([]byte, error) {
 	return json.Marshal(res)
 }
+
+// EncodeMapExport ...
+func (e *JSONResultEncoder) EncodeMapExport(res *MapExportResult) ([]byte, error) {
+	return json.Marshal(res)
+}
+
+// EncodeMapImport ...
+func (e *JSONResultEncoder) EncodeMapImport(res *MapImportResult) ([]byte, error) {
+	return json.Marshal(res)
+}
+
+// EncodeScheduledList ...
+func (e *JSONResultEncoder) EncodeScheduledList(res *ScheduledListResult) ([]byte, error) {
+	return json.Marshal(res)
+}
+
+// EncodeScheduledCancel ...
+func (e *JSONResultEncoder) EncodeScheduledCancel(res *ScheduledCancelResult) ([]byte, error) {
+	return json.Marshal(res)
+}
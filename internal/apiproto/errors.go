@@ -24,6 +24,12 @@ var (
 		Code:    102,
 		Message: "unknown channel",
 	}
+	// ErrorPermissionDenied means that request is not allowed to operate
+	// on a resource, for example on a channel of another tenant.
+	ErrorPermissionDenied = &Error{
+		Code:    103,
+		Message: "permission denied",
+	}
 	// ErrorNotFound means that method sent in command does not exist.
 	ErrorNotFound = &Error{
 		Code:    104,
@@ -40,6 +46,11 @@ var (
 		Code:    108,
 		Message: "not available",
 	}
+	// ErrorTooManyRequests means that request hit a rate limit.
+	ErrorTooManyRequests = &Error{
+		Code:    111,
+		Message: "too many requests",
+	}
 	// ErrorUnrecoverablePosition means that stream does not contain required
 	// range of publications to fulfill a history query. This can be happen to
 	// expiration, size limitation or due to wrong epoch.
@@ -52,6 +63,12 @@ var (
 		Code:    113,
 		Message: "conflict",
 	}
+	// ErrorSchemaViolation means that publication data does not match JSON Schema
+	// configured for channel namespace.
+	ErrorSchemaViolation = &Error{
+		Code:    114,
+		Message: "schema violation",
+	}
 )
 
 func MapErrorToHTTPCode(err *Error) int {
@@ -60,12 +77,16 @@ func MapErrorToHTTPCode(err *Error) int {
 		return http.StatusInternalServerError
 	case ErrorUnknownChannel.Code, ErrorNotFound.Code:
 		return http.StatusNotFound
-	case ErrorBadRequest.Code, ErrorNotAvailable.Code:
+	case ErrorBadRequest.Code, ErrorNotAvailable.Code, ErrorSchemaViolation.Code:
 		return http.StatusBadRequest
 	case ErrorUnrecoverablePosition.Code:
 		return http.StatusRequestedRangeNotSatisfiable
 	case ErrorConflict.Code:
 		return http.StatusConflict
+	case ErrorPermissionDenied.Code:
+		return http.StatusForbidden
+	case ErrorTooManyRequests.Code:
+		return http.StatusTooManyRequests
 	default:
 		// Default to Internal Server Error for unmapped errors.
 		// In general should be avoided - all new API errors must be explicitly described here.
@@ -79,12 +100,16 @@ func MapErrorToGRPCCode(err *Error) codes.Code {
 		return codes.Internal
 	case ErrorUnknownChannel.Code, ErrorNotFound.Code:
 		return codes.NotFound
-	case ErrorBadRequest.Code, ErrorNotAvailable.Code:
+	case ErrorBadRequest.Code, ErrorNotAvailable.Code, ErrorSchemaViolation.Code:
 		return codes.InvalidArgument
 	case ErrorUnrecoverablePosition.Code:
 		return codes.OutOfRange
 	case ErrorConflict.Code:
 		return codes.AlreadyExists
+	case ErrorPermissionDenied.Code:
+		return codes.PermissionDenied
+	case ErrorTooManyRequests.Code:
+		return codes.ResourceExhausted
 	default:
 		// Default to Internal Error for unmapped errors.
 		// In general should be avoided - all new API errors must be explicitly described here.
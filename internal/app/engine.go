@@ -4,24 +4,37 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/centrifugal/centrifugo/v6/internal/chstate"
 	"github.com/centrifugal/centrifugo/v6/internal/config"
 	"github.com/centrifugal/centrifugo/v6/internal/confighelpers"
 	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/connquota"
 	"github.com/centrifugal/centrifugo/v6/internal/controllers"
+	"github.com/centrifugal/centrifugo/v6/internal/envelope"
+	"github.com/centrifugal/centrifugo/v6/internal/historyarchive"
+	"github.com/centrifugal/centrifugo/v6/internal/historyquery"
+	"github.com/centrifugal/centrifugo/v6/internal/mapfilter"
 	"github.com/centrifugal/centrifugo/v6/internal/natsbroker"
 	"github.com/centrifugal/centrifugo/v6/internal/pgmapbroker"
 	"github.com/centrifugal/centrifugo/v6/internal/pgstreambroker"
+	"github.com/centrifugal/centrifugo/v6/internal/proxy"
 	"github.com/centrifugal/centrifugo/v6/internal/redisnatsbroker"
+	"github.com/centrifugal/centrifugo/v6/internal/schedule"
+	"github.com/centrifugal/centrifugo/v6/internal/sharedpoll"
 
 	"github.com/centrifugal/centrifuge"
 	"github.com/rs/zerolog/log"
 )
 
-func configureEngines(node *centrifuge.Node, cfgContainer *config.Container) error {
+// configureEngines sets broker, presence manager and controller to node. Returns
+// configured broker before wrapping it with history archive, encryption and
+// channel state brokers.
+func configureEngines(node *centrifuge.Node, cfgContainer *config.Container, channelState *chstate.Notifier, archiver *historyarchive.Archiver) (centrifuge.Broker, centrifuge.Controller, centrifuge.PresenceManager, error) {
 	cfg := cfgContainer.Config()
 
 	var broker centrifuge.Broker
 	var presenceManager centrifuge.PresenceManager
+	var controller centrifuge.Controller
 
 	if !cfg.Broker.Enabled || !cfg.PresenceManager.Enabled {
 		var err error
@@ -32,7 +45,7 @@ func configureEngines(node *centrifuge.Node, cfgContainer *config.Container) err
 		case "redis":
 			broker, presenceManager, engineMode, err = createRedisEngine(node, cfgContainer)
 		default:
-			return fmt.Errorf("unknown engine type: %s", cfg.Engine.Type)
+			return nil, nil, nil, fmt.Errorf("unknown engine type: %s", cfg.Engine.Type)
 		}
 		event := log.Info().Str("engine_type", cfg.Engine.Type)
 		if engineMode != "" {
@@ -40,7 +53,7 @@ func configureEngines(node *centrifuge.Node, cfgContainer *config.Container) err
 		}
 		event.Msg("initializing engine")
 		if err != nil {
-			return fmt.Errorf("error creating engine: %v", err)
+			return nil, nil, nil, fmt.Errorf("error creating engine: %v", err)
 		}
 	} else {
 		log.Info().Msgf("both broker and presence manager enabled, skip engine initialization")
@@ -58,31 +71,35 @@ func configureEngines(node *centrifuge.Node, cfgContainer *config.Container) err
 			broker, err = NatsBroker(node, cfg)
 			brokerMode = "nats"
 		case "postgres":
-			broker, err = createPostgresStreamBroker(node, cfg.Broker.Postgres)
+			var partitionArchiver pgstreambroker.PartitionArchiver
+			if archiver != nil && cfg.HistoryArchive.Source == historyarchive.SourcePostgresPartitions {
+				partitionArchiver = archiver
+			}
+			broker, err = createPostgresStreamBroker(node, cfg.Broker.Postgres, partitionArchiver)
 			brokerMode = "postgres"
 		case "redisnats":
 			if !cfg.EnableUnreleasedFeatures {
-				return fmt.Errorf("redisnats broker requires enable_unreleased_features on")
+				return nil, nil, nil, fmt.Errorf("redisnats broker requires enable_unreleased_features on")
 			}
 			log.Warn().Msg("redisnats broker is not released, it may be changed or removed at any point")
 			redisBroker, redisBrokerMode, err := createRedisBroker(node, cfgContainer)
 			if err != nil {
-				return fmt.Errorf("error creating redis broker: %v", err)
+				return nil, nil, nil, fmt.Errorf("error creating redis broker: %v", err)
 			}
 			brokerMode = redisBrokerMode + "+nats"
 			natsBroker, err := NatsBroker(node, cfg)
 			if err != nil {
-				return fmt.Errorf("error creating nats broker: %v", err)
+				return nil, nil, nil, fmt.Errorf("error creating nats broker: %v", err)
 			}
 			broker, err = redisnatsbroker.New(natsBroker, redisBroker)
 			if err != nil {
-				return fmt.Errorf("error creating redisnats broker: %v", err)
+				return nil, nil, nil, fmt.Errorf("error creating redisnats broker: %v", err)
 			}
 		default:
-			return fmt.Errorf("unknown broker type: %s", cfg.Broker.Type)
+			return nil, nil, nil, fmt.Errorf("unknown broker type: %s", cfg.Broker.Type)
 		}
 		if err != nil {
-			return fmt.Errorf("error creating broker: %v", err)
+			return nil, nil, nil, fmt.Errorf("error creating broker: %v", err)
 		}
 		event := log.Info().Str("broker_type", cfg.Broker.Type)
 		if brokerMode != "" {
@@ -102,10 +119,10 @@ func configureEngines(node *centrifuge.Node, cfgContainer *config.Container) err
 		case "redis":
 			presenceManager, presenceManagerMode, err = createRedisPresenceManager(node, cfgContainer)
 		default:
-			return fmt.Errorf("unknown presence manager type: %s", cfg.PresenceManager.Type)
+			return nil, nil, nil, fmt.Errorf("unknown presence manager type: %s", cfg.PresenceManager.Type)
 		}
 		if err != nil {
-			return fmt.Errorf("error creating presence manager: %v", err)
+			return nil, nil, nil, fmt.Errorf("error creating presence manager: %v", err)
 		}
 		event := log.Info().Str("presence_manager_type", cfg.PresenceManager.Type)
 		if presenceManagerMode != "" {
@@ -117,18 +134,41 @@ func configureEngines(node *centrifuge.Node, cfgContainer *config.Container) err
 	}
 
 	if cfg.Controller.Enabled {
-		controller, err := controllers.New(node, cfg.Controller)
+		var err error
+		controller, err = controllers.New(node, cfg.Controller)
 		if err != nil {
-			return fmt.Errorf("error creating controller: %v", err)
+			return nil, nil, nil, fmt.Errorf("error creating controller: %v", err)
 		}
 		if controller != nil {
 			node.SetController(controller)
 		}
 	}
 
+	// Keep configured broker to expose its optional capabilities (like scheduled
+	// publications store or history range reads) which are hidden by wrappers below.
+	configuredBroker := broker
+
+	if archiver != nil && cfg.HistoryArchive.Source == historyarchive.SourcePublications {
+		// Wrap below encryption, so that archive keeps data as history does.
+		broker = historyarchive.NewBroker(broker, archiver)
+	}
+
+	if channelEncryptionEnabled(cfg) {
+		keyring, err := envelope.NewKeyring(cfg.Channel.Encryption)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("error creating encryption keyring: %v", err)
+		}
+		broker = envelope.NewBroker(broker, keyring, cfgContainer)
+		log.Info().Msg("publication data encryption is enabled")
+	}
+
+	if channelState != nil {
+		broker = chstate.NewBroker(broker, channelState)
+	}
+
 	node.SetBroker(broker)
 	node.SetPresenceManager(presenceManager)
-	return nil
+	return configuredBroker, controller, presenceManager, nil
 }
 
 func createMemoryBroker(n *centrifuge.Node) (centrifuge.Broker, error) {
@@ -143,7 +183,7 @@ func createMemoryBroker(n *centrifuge.Node) (centrifuge.Broker, error) {
 	return broker, nil
 }
 
-func createPostgresStreamBroker(node *centrifuge.Node, pgCfg configtypes.PostgresStreamBroker) (centrifuge.Broker, error) {
+func createPostgresStreamBroker(node *centrifuge.Node, pgCfg configtypes.PostgresStreamBroker, partitionArchiver pgstreambroker.PartitionArchiver) (centrifuge.Broker, error) {
 	pgBrokerCfg := pgstreambroker.PostgresStreamBrokerConfig{
 		DSN:                       pgCfg.DSN,
 		TLS:                       pgCfg.TLS,
@@ -158,6 +198,7 @@ func createPostgresStreamBroker(node *centrifuge.Node, pgCfg configtypes.Postgre
 		TablePrefix:               pgCfg.TablePrefix,
 		PartitionLookaheadDays:    pgCfg.PartitionLookaheadDays,
 		PartitionRetentionDays: pgCfg.PartitionRetentionDays,
+		PartitionArchiver:      partitionArchiver,
 		Outbox: pgstreambroker.OutboxConfig{
 			PollInterval: pgCfg.Outbox.PollInterval.ToDuration(),
 			BatchSize:    pgCfg.Outbox.BatchSize,
@@ -261,7 +302,7 @@ func createRedisPresenceManager(n *centrifuge.Node, cfgContainer *config.Contain
 	return presenceManager, mode, nil
 }
 
-func configureMapBroker(node *centrifuge.Node, cfgContainer *config.Container) error {
+func configureMapBroker(node *centrifuge.Node, cfgContainer *config.Container) (centrifuge.MapBroker, error) {
 	cfg := cfgContainer.Config()
 	var mapBroker centrifuge.MapBroker
 	var mapBrokerMode string
@@ -273,7 +314,7 @@ func configureMapBroker(node *centrifuge.Node, cfgContainer *config.Container) e
 		var redisShards []*centrifuge.RedisShard
 		redisShards, mapBrokerMode, err = confighelpers.CentrifugeRedisShards(node, cfg.MapBroker.Redis.Redis)
 		if err != nil {
-			return fmt.Errorf("error creating Redis shards for map broker: %w", err)
+			return nil, fmt.Errorf("error creating Redis shards for map broker: %w", err)
 		}
 		mapBroker, err = confighelpers.CentrifugeRedisMapBroker(
 			node, cfg.MapBroker.Redis.Prefix, redisShards, cfg.MapBroker.Redis.RedisMapBrokerCommon)
@@ -301,26 +342,215 @@ func configureMapBroker(node *centrifuge.Node, cfgContainer *config.Container) e
 		}
 		mapBroker, err = pgmapbroker.NewPostgresMapBroker(node, pgBrokerCfg)
 		if err != nil {
-			return fmt.Errorf("error creating Postgres map broker: %w", err)
+			return nil, fmt.Errorf("error creating Postgres map broker: %w", err)
 		}
 		if !pgCfg.SkipSchemaInit {
 			pgBroker := mapBroker.(*pgmapbroker.PostgresMapBroker)
 			if schemaErr := pgBroker.EnsureSchema(context.Background()); schemaErr != nil {
-				return fmt.Errorf("error initializing Postgres map broker schema: %w", schemaErr)
+				return nil, fmt.Errorf("error initializing Postgres map broker schema: %w", schemaErr)
+			}
+			if indexes := collectMapIndexes(cfg); len(indexes) > 0 {
+				if pgCfg.BinaryData {
+					log.Warn().Msg("map indexes are ignored by Postgres map broker in binary_data mode, filtered state reads will scan state in memory")
+				} else if indexErr := pgBroker.EnsureStateIndexes(context.Background(), indexes); indexErr != nil {
+					return nil, fmt.Errorf("error creating Postgres map broker state indexes: %w", indexErr)
+				}
 			}
 		}
 		mapBrokerMode = "postgres"
 	default:
-		return fmt.Errorf("unknown map broker type: %s", cfg.MapBroker.Type)
+		return nil, fmt.Errorf("unknown map broker type: %s", cfg.MapBroker.Type)
 	}
 	if err != nil {
-		return fmt.Errorf("error creating map broker: %v", err)
+		return nil, fmt.Errorf("error creating map broker: %v", err)
 	}
 	event := log.Info().Str("map_broker_type", cfg.MapBroker.Type)
 	if mapBrokerMode != "" {
 		event.Str("map_broker_mode", mapBrokerMode)
 	}
 	event.Msg("initializing map broker")
+	if channelEncryptionEnabled(cfg) {
+		keyring, err := envelope.NewKeyring(cfg.Channel.Encryption)
+		if err != nil {
+			return nil, fmt.Errorf("error creating encryption keyring: %v", err)
+		}
+		mapBroker = envelope.NewMapBroker(mapBroker, keyring, cfgContainer)
+	}
 	node.SetMapBroker(mapBroker)
-	return nil
+	return mapBroker, nil
+}
+
+// collectMapIndexes returns secondary index definitions of all map namespaces.
+func collectMapIndexes(cfg config.Config) []mapfilter.Index {
+	indexes := cfg.Channel.WithoutNamespace.Map.Indexes.FilterIndexes()
+	for _, ns := range cfg.Channel.Namespaces {
+		indexes = append(indexes, ns.Map.Indexes.FilterIndexes()...)
+	}
+	return indexes
+}
+
+// channelEncryptionEnabled returns true when any namespace uses publication data
+// encryption.
+func channelEncryptionEnabled(cfg config.Config) bool {
+	if cfg.Channel.WithoutNamespace.Encryption.Enabled {
+		return true
+	}
+	for _, ns := range cfg.Channel.Namespaces {
+		if ns.Encryption.Enabled {
+			return true
+		}
+	}
+	return false
+}
+
+// configureSharedPollCoordinator creates Coordinator when any namespace uses coordinated
+// shared poll. Returns nil, nil otherwise.
+func configureSharedPollCoordinator(node *centrifuge.Node, cfgContainer *config.Container, controller centrifuge.Controller) (*sharedpoll.Coordinator, error) {
+	cfg := cfgContainer.Config()
+	coordinated := cfg.Channel.WithoutNamespace.SubscriptionType == "shared_poll" && cfg.Channel.WithoutNamespace.SharedPoll.Coordinated
+	for _, ns := range cfg.Channel.Namespaces {
+		if ns.SubscriptionType == "shared_poll" && ns.SharedPoll.Coordinated {
+			coordinated = true
+			break
+		}
+	}
+	if !coordinated {
+		return nil, nil
+	}
+
+	var store sharedpoll.Store
+	switch cfg.SharedPoll.Coordination.Type {
+	case "redis":
+		redisStore, err := sharedpoll.NewRedisStore(cfg.SharedPoll.Coordination.Redis)
+		if err != nil {
+			return nil, err
+		}
+		store = redisStore
+	case "postgres":
+		pgStore, ok := controller.(sharedpoll.Store)
+		if !ok {
+			return nil, fmt.Errorf("postgres shared poll coordination requires postgres controller")
+		}
+		store = pgStore
+	default:
+		return nil, fmt.Errorf("unknown shared poll coordination type: %s", cfg.SharedPoll.Coordination.Type)
+	}
+	log.Info().Str("coordination_type", cfg.SharedPoll.Coordination.Type).Msg("shared poll coordination is enabled")
+	return sharedpoll.NewCoordinator(node, store), nil
+}
+
+// configureHistoryRangeReader returns reader of history by time range if broker
+// supports it natively. Returns nil, nil otherwise – time range history queries
+// then filter channel history stream.
+func configureHistoryRangeReader(cfgContainer *config.Container, broker centrifuge.Broker) (historyquery.RangeReader, error) {
+	rangeReader, ok := broker.(historyquery.RangeReader)
+	if !ok {
+		return nil, nil
+	}
+	cfg := cfgContainer.Config()
+	if channelEncryptionEnabled(cfg) {
+		// Publications read by time range must be decrypted same way as history.
+		keyring, err := envelope.NewKeyring(cfg.Channel.Encryption)
+		if err != nil {
+			return nil, fmt.Errorf("error creating encryption keyring: %v", err)
+		}
+		return envelope.NewBroker(broker, keyring, cfgContainer), nil
+	}
+	return rangeReader, nil
+}
+
+// configureHistoryArchive creates history archiver if history archive is enabled.
+// It must be registered in service manager to flush archived publications.
+func configureHistoryArchive(node *centrifuge.Node, cfgContainer *config.Container) (*historyarchive.Archiver, error) {
+	cfg := cfgContainer.Config()
+	if !cfg.HistoryArchive.Enabled {
+		return nil, nil
+	}
+	storage, err := historyarchive.NewStorage(context.Background(), cfg.HistoryArchive)
+	if err != nil {
+		return nil, fmt.Errorf("error creating history archive storage: %w", err)
+	}
+	log.Info().Str("source", cfg.HistoryArchive.Source).Str("storage", cfg.HistoryArchive.Storage).Msg("history archive is enabled")
+	return historyarchive.New(node.ID(), storage, cfg.HistoryArchive), nil
+}
+
+// configureScheduler creates Scheduler of publications with publish_at or delay set.
+// Returns nil, nil if scheduled publications are not enabled.
+func configureScheduler(cfgContainer *config.Container, broker centrifuge.Broker, handler schedule.Handler) (*schedule.Scheduler, error) {
+	cfg := cfgContainer.Config()
+	if !cfg.ScheduledPublications.Enabled {
+		return nil, nil
+	}
+
+	var store schedule.Store
+	switch cfg.ScheduledPublications.Type {
+	case "redis":
+		redisStore, err := schedule.NewRedisStore(cfg.ScheduledPublications.Redis)
+		if err != nil {
+			return nil, err
+		}
+		store = redisStore
+	case "postgres":
+		pgBroker, ok := broker.(*pgstreambroker.PostgresStreamBroker)
+		if !ok {
+			return nil, fmt.Errorf("postgres scheduled publications require postgres broker")
+		}
+		store = pgBroker.ScheduleStore()
+	default:
+		return nil, fmt.Errorf("unknown scheduled publications type: %s", cfg.ScheduledPublications.Type)
+	}
+	log.Info().Str("type", cfg.ScheduledPublications.Type).Msg("scheduled publications are enabled")
+	return schedule.New(store, cfg.ScheduledPublications, handler), nil
+}
+
+// configureConnectionQuota creates Quota when per-user or per-IP connection quota
+// is set. Returns nil, nil otherwise.
+func configureConnectionQuota(node *centrifuge.Node, cfgContainer *config.Container, presenceManager centrifuge.PresenceManager) (*connquota.Quota, error) {
+	cfg := cfgContainer.Config()
+	if !cfg.Client.ConnectionQuota.Enabled() {
+		return nil, nil
+	}
+
+	var store connquota.Store
+	switch cfg.Client.ConnectionQuota.Type {
+	case "presence":
+		store = connquota.NewPresenceStore(presenceManager)
+	case "redis":
+		redisStore, err := connquota.NewRedisStore(cfg.Client.ConnectionQuota.Redis)
+		if err != nil {
+			return nil, err
+		}
+		store = redisStore
+	default:
+		return nil, fmt.Errorf("unknown connection quota type: %s", cfg.Client.ConnectionQuota.Type)
+	}
+	log.Info().Str("type", cfg.Client.ConnectionQuota.Type).Str("policy", cfg.Client.ConnectionQuota.Policy).
+		Int("user_limit", cfg.Client.ConnectionQuota.UserLimit).Int("ip_limit", cfg.Client.ConnectionQuota.IPLimit).
+		Msg("cluster-wide connection quota is enabled")
+	return connquota.New(node, store, cfg.Client.ConnectionQuota), nil
+}
+
+// configureChannelState creates Notifier when channel state proxy is enabled for
+// some channels. Returns nil, nil otherwise.
+func configureChannelState(node *centrifuge.Node, cfgContainer *config.Container, handlers map[string]*proxy.ChannelStateHandler) (*chstate.Notifier, error) {
+	if len(handlers) == 0 {
+		return nil, nil
+	}
+	cfg := cfgContainer.Config()
+
+	var store chstate.Store
+	switch cfg.Channel.StateEvents.Type {
+	case "memory":
+		store = chstate.NewMemoryStore()
+	case "redis":
+		redisStore, err := chstate.NewRedisStore(cfg.Channel.StateEvents.Redis)
+		if err != nil {
+			return nil, err
+		}
+		store = redisStore
+	default:
+		return nil, fmt.Errorf("unknown channel state events type: %s", cfg.Channel.StateEvents.Type)
+	}
+	log.Info().Str("type", cfg.Channel.StateEvents.Type).Msg("channel state events are enabled")
+	return chstate.New(node, store, cfgContainer, handlers, cfg.Channel.StateEvents), nil
 }
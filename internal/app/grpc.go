@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/centrifugal/centrifugo/v6/internal/api"
+	"github.com/centrifugal/centrifugo/v6/internal/bidigrpc"
 	"github.com/centrifugal/centrifugo/v6/internal/config"
 	"github.com/centrifugal/centrifugo/v6/internal/tools"
 	"github.com/centrifugal/centrifugo/v6/internal/unigrpc"
@@ -21,7 +22,7 @@ import (
 	"google.golang.org/grpc/reflection"
 )
 
-func runGRPCAPIServer(cfg config.Config, node *centrifuge.Node, useAPIOpentelemetry bool, grpcAPIExecutor *api.Executor) (*grpc.Server, error) {
+func runGRPCAPIServer(cfg config.Config, node *centrifuge.Node, useAPIOpentelemetry bool, grpcAPIExecutor *api.Executor, keyAuth *api.GRPCKeyAuth) (*grpc.Server, error) {
 	grpcAPIAddr := net.JoinHostPort(cfg.GrpcAPI.Address, strconv.Itoa(cfg.GrpcAPI.Port))
 	grpcAPIConn, err := net.Listen("tcp", grpcAPIAddr)
 	if err != nil {
@@ -29,8 +30,8 @@ func runGRPCAPIServer(cfg config.Config, node *centrifuge.Node, useAPIOpenteleme
 	}
 	var grpcOpts []grpc.ServerOption
 
-	if cfg.GrpcAPI.Key != "" {
-		grpcOpts = append(grpcOpts, api.GRPCKeyAuth(cfg.GrpcAPI.Key))
+	if keyAuth != nil {
+		grpcOpts = append(grpcOpts, keyAuth.ServerOption())
 	}
 	if cfg.GrpcAPI.MaxReceiveMessageSize > 0 {
 		grpcOpts = append(grpcOpts, grpc.MaxRecvMsgSize(cfg.GrpcAPI.MaxReceiveMessageSize))
@@ -111,3 +112,35 @@ func runGRPCUniServer(cfg config.Config, node *centrifuge.Node) (*grpc.Server, e
 	}()
 	return grpcUniServer, nil
 }
+
+func runGRPCBidiServer(cfg config.Config, node *centrifuge.Node) (*grpc.Server, error) {
+	grpcBidiAddr := net.JoinHostPort(cfg.BidiGRPC.Address, strconv.Itoa(cfg.BidiGRPC.Port))
+	grpcBidiConn, err := net.Listen("tcp", grpcBidiAddr)
+	if err != nil {
+		return nil, fmt.Errorf("cannot listen to address %s", grpcBidiAddr)
+	}
+	var grpcOpts []grpc.ServerOption
+	grpcOpts = append(grpcOpts, grpc.ForceServerCodec(&bidigrpc.RawCodec{}))
+
+	if cfg.BidiGRPC.MaxReceiveMessageSize > 0 {
+		grpcOpts = append(grpcOpts, grpc.MaxRecvMsgSize(cfg.BidiGRPC.MaxReceiveMessageSize))
+	}
+
+	if cfg.BidiGRPC.TLS.Enabled {
+		bidiGrpcTLSConfig, err := cfg.BidiGRPC.TLS.ToGoTLSConfig("bidi_grpc")
+		if err != nil {
+			return nil, fmt.Errorf("error getting TLS config for bidi GRPC: %v", err)
+		}
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(bidiGrpcTLSConfig)))
+	}
+	grpcOpts = append(grpcOpts, bidigrpc.KeepaliveServerOptions(cfg.BidiGRPC.Keepalive)...)
+	grpcBidiServer := grpc.NewServer(grpcOpts...)
+	_ = bidigrpc.RegisterService(grpcBidiServer, bidigrpc.NewService(node, cfg.BidiGRPC))
+	log.Info().Msgf("serving bidirectional GRPC on %s", grpcBidiAddr)
+	go func() {
+		if err := grpcBidiServer.Serve(grpcBidiConn); err != nil {
+			log.Fatal().Err(err).Msg("serve bidi GRPC")
+		}
+	}()
+	return grpcBidiServer, nil
+}
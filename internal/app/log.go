@@ -1,6 +1,7 @@
 package app
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/centrifugal/centrifugo/v6/internal/config"
@@ -48,3 +49,38 @@ func (w *httpErrorLogWriter) Write(data []byte) (int, error) {
 	w.Logger.Warn().Msg(strings.TrimSpace(string(data)))
 	return len(data), nil
 }
+
+// reloadableSecretPaths are configuration path prefixes of options applied on
+// SIGHUP. Secrets in other options are resolved on reload but only applied after
+// restart.
+var reloadableSecretPaths = []string{
+	"client.token.",
+	"client.subscription_token.",
+	"http_api.key",
+	"http_api.named_keys[",
+	"grpc_api.key",
+	"grpc_api.named_keys[",
+	"tenants[",
+	"channel.",
+}
+
+func logSecretsReloaded(secretRefs map[string]string) {
+	var restartRequired []string
+	for path := range secretRefs {
+		reloadable := false
+		for _, prefix := range reloadableSecretPaths {
+			if strings.HasPrefix(path, prefix) {
+				reloadable = true
+				break
+			}
+		}
+		if !reloadable {
+			restartRequired = append(restartRequired, path)
+		}
+	}
+	log.Info().Int("num_secrets", len(secretRefs)).Msg("secret references re-resolved")
+	if len(restartRequired) > 0 {
+		slices.Sort(restartRequired)
+		log.Warn().Strs("keys", restartRequired).Msg("changes of secrets in these options are applied on restart only")
+	}
+}
@@ -13,11 +13,17 @@ import (
 
 	"github.com/centrifugal/centrifugo/v6/internal/admin"
 	"github.com/centrifugal/centrifugo/v6/internal/api"
+	"github.com/centrifugal/centrifugo/v6/internal/audit"
+	"github.com/centrifugal/centrifugo/v6/internal/bidiws"
 	"github.com/centrifugal/centrifugo/v6/internal/config"
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
 	"github.com/centrifugal/centrifugo/v6/internal/conninit"
 	"github.com/centrifugal/centrifugo/v6/internal/devpage"
+	"github.com/centrifugal/centrifugo/v6/internal/drain"
 	"github.com/centrifugal/centrifugo/v6/internal/health"
+	"github.com/centrifugal/centrifugo/v6/internal/longpoll"
 	"github.com/centrifugal/centrifugo/v6/internal/middleware"
+	"github.com/centrifugal/centrifugo/v6/internal/streamwriter"
 	"github.com/centrifugal/centrifugo/v6/internal/swaggerui"
 	"github.com/centrifugal/centrifugo/v6/internal/tools"
 	"github.com/centrifugal/centrifugo/v6/internal/unihttpstream"
@@ -70,6 +76,8 @@ const (
 	HandlerSwagger
 	// HandlerDev handles development page.
 	HandlerDev
+	// HandlerLongPoll enables bidirectional long polling endpoint (with emulation layer).
+	HandlerLongPoll
 )
 
 var handlerText = map[HandlerFlag]string{
@@ -89,10 +97,11 @@ var handlerText = map[HandlerFlag]string{
 	HandlerInit:          "init",
 	HandlerSwagger:       "swagger",
 	HandlerDev:           "dev",
+	HandlerLongPoll:      "long_poll",
 }
 
 func (flags HandlerFlag) String() string {
-	flagsOrdered := []HandlerFlag{HandlerWebsocket, HandlerWebtransport, HandlerHTTPStream, HandlerSSE, HandlerEmulation, HandlerAPI, HandlerAdmin, HandlerPrometheus, HandlerDebug, HandlerHealth, HandlerUniWebsocket, HandlerUniSSE, HandlerUniHTTPStream, HandlerSwagger, HandlerDev, HandlerInit}
+	flagsOrdered := []HandlerFlag{HandlerWebsocket, HandlerWebtransport, HandlerHTTPStream, HandlerSSE, HandlerLongPoll, HandlerEmulation, HandlerAPI, HandlerAdmin, HandlerPrometheus, HandlerDebug, HandlerHealth, HandlerUniWebsocket, HandlerUniSSE, HandlerUniHTTPStream, HandlerSwagger, HandlerDev, HandlerInit}
 	var endpoints []string
 	for _, flag := range flagsOrdered {
 		text, ok := handlerText[flag]
@@ -108,7 +117,7 @@ func (flags HandlerFlag) String() string {
 
 // Mux returns a mux including set of default handlers for Centrifugo server.
 func Mux(
-	n *centrifuge.Node, cfgContainer *config.Container, apiExecutor *api.Executor, flags HandlerFlag, keepHeadersInContext bool, wtServer *webtransport.Server,
+	n *centrifuge.Node, cfgContainer *config.Container, apiExecutor *api.Executor, apiKeyAuth *middleware.APIKeyAuth, flags HandlerFlag, keepHeadersInContext bool, wtServer *webtransport.Server, longPollHub *longpoll.Hub, drainer *drain.Drainer, auditor *audit.Logger,
 ) *http.ServeMux {
 	mux := http.NewServeMux()
 	cfg := cfgContainer.Config()
@@ -153,6 +162,16 @@ func Mux(
 		connLimitMW := middleware.NewConnLimit(n, cfgContainer)
 		connMiddlewares = append(connMiddlewares, connLimitMW.Middleware)
 	}
+	if cfg.Client.ConnectionQuota.IPLimit > 0 {
+		trustedProxies, err := tools.ParseTrustedProxies(cfg.Client.ConnectionQuota.TrustedProxies)
+		if err != nil {
+			log.Fatal().Err(err).Msg("error parsing connection quota trusted proxies")
+		}
+		connMiddlewares = append(connMiddlewares, middleware.ClientIPToContext(cfg.Client.ConnectionQuota.IPHeader, trustedProxies))
+	}
+	if cfg.Tenancy.Enabled && cfg.Tenancy.Resolve == "host" {
+		connMiddlewares = append(connMiddlewares, middleware.TenantFromHost(cfgContainer))
+	}
 	userIDHTTPHeader := cfg.Client.UserIDHTTPHeader
 	if userIDHTTPHeader != "" {
 		connMiddlewares = append(connMiddlewares, middleware.UserHeaderAuth(userIDHTTPHeader))
@@ -169,7 +188,15 @@ func Mux(
 		if wsPrefix == "" {
 			wsPrefix = "/"
 		}
-		mux.Handle(wsPrefix, connChain.Then(centrifuge.NewWebsocketHandler(n, websocketHandlerConfig(cfg))))
+		var wsHandler http.Handler = centrifuge.NewWebsocketHandler(n, websocketHandlerConfig(cfg))
+		if cfg.WebSocket.CompressionContext.Enabled {
+			// Context takeover is not supported by centrifuge WebSocket handler, such
+			// connections are handled by bidiws, others passed to centrifuge handler.
+			contextTakeoverHandler := bidiws.NewHandler(n, cfg.WebSocket, wsHandler, getCheckOrigin(cfg), getPingPongConfig(cfg))
+			contextTakeoverHandler.SetCompressionDictionary(cfgContainer.WebSocketCompressionDictionary)
+			wsHandler = contextTakeoverHandler
+		}
+		mux.Handle(wsPrefix, connChain.Then(wsHandler))
 	}
 
 	if flags&HandlerWebtransport != 0 {
@@ -196,7 +223,8 @@ func Mux(
 		if streamPrefix == "" {
 			streamPrefix = "/"
 		}
-		mux.Handle(streamPrefix, connChain.Then(centrifuge.NewHTTPStreamHandler(n, httpStreamHandlerConfig(cfg))))
+		mux.Handle(streamPrefix, connChain.Append(streamwriter.Middleware(cfg.HTTPStream.WriteCoalescing)).Then(
+			centrifuge.NewHTTPStreamHandler(n, httpStreamHandlerConfig(cfg))))
 	}
 	if flags&HandlerSSE != 0 {
 		// register bidirectional SSE connection endpoint.
@@ -204,7 +232,22 @@ func Mux(
 		if ssePrefix == "" {
 			ssePrefix = "/"
 		}
-		mux.Handle(ssePrefix, connChain.Then(centrifuge.NewSSEHandler(n, sseHandlerConfig(cfg))))
+		mux.Handle(ssePrefix, connChain.Append(streamwriter.Middleware(cfg.SSE.WriteCoalescing)).Then(
+			centrifuge.NewSSEHandler(n, sseHandlerConfig(cfg))))
+	}
+
+	if flags&HandlerLongPoll != 0 {
+		// register bidirectional long polling connection and poll endpoints.
+		longPollPrefix := strings.TrimRight(cfg.LongPoll.HandlerPrefix, "/")
+		longPollConnectPrefix := longPollPrefix
+		if longPollConnectPrefix == "" {
+			longPollConnectPrefix = "/"
+		}
+		mux.Handle(longPollConnectPrefix, connChain.Then(longpoll.NewHandler(longPollHub)))
+		// Poll requests do not establish new connections, so they only go through CORS.
+		pollMiddlewares := append([]alice.Constructor{}, commonMiddlewares...)
+		pollMiddlewares = append(pollMiddlewares, middleware.NewCORS(getCheckOrigin(cfg)).Middleware)
+		mux.Handle(longPollPrefix+"/poll", alice.New(pollMiddlewares...).Then(longpoll.NewPollHandler(longPollHub)))
 	}
 
 	if flags&HandlerUniWebsocket != 0 {
@@ -213,8 +256,9 @@ func Mux(
 		if wsPrefix == "" {
 			wsPrefix = "/"
 		}
-		mux.Handle(wsPrefix, connChain.Then(
-			uniws.NewHandler(n, cfg.UniWS, getCheckOrigin(cfg), getPingPongConfig(cfg))))
+		uniWSHandler := uniws.NewHandler(n, cfg.UniWS, getCheckOrigin(cfg), getPingPongConfig(cfg))
+		uniWSHandler.SetCompressionDictionary(cfgContainer.WebSocketCompressionDictionary)
+		mux.Handle(wsPrefix, connChain.Then(uniWSHandler))
 	}
 
 	if flags&HandlerUniSSE != 0 {
@@ -258,8 +302,8 @@ func Mux(
 				apiMiddlewares = append(apiMiddlewares, otelHandler.Middleware)
 			}
 			apiMiddlewares = append(apiMiddlewares, middleware.Post)
-			if !cfg.HttpAPI.Insecure {
-				apiMiddlewares = append(apiMiddlewares, middleware.NewAPIKeyAuth(cfg.HttpAPI.Key).Middleware)
+			if apiKeyAuth != nil {
+				apiMiddlewares = append(apiMiddlewares, apiKeyAuth.Middleware)
 			}
 			apiChain := alice.New(apiMiddlewares...)
 			return apiChain
@@ -298,7 +342,14 @@ func Mux(
 
 	if flags&HandlerAdmin != 0 {
 		adminPrefix := strings.TrimRight(cfg.Admin.HandlerPrefix, "/")
-		mux.Handle(adminPrefix+"/", basicChain.Then(admin.NewHandler(n, apiExecutor, cfg.Admin)))
+		adminHandler := admin.NewHandler(n, apiExecutor, cfg.Admin)
+		if drainer != nil {
+			adminHandler.SetDrainer(drainer)
+		}
+		if auditor != nil {
+			adminHandler.SetAuditor(auditor)
+		}
+		mux.Handle(adminPrefix+"/", basicChain.Then(adminHandler))
 	}
 
 	if flags&HandlerHealth != 0 {
@@ -306,7 +357,11 @@ func Mux(
 		if healthPrefix == "" {
 			healthPrefix = "/"
 		}
-		mux.Handle(healthPrefix, basicChain.Then(health.NewHandler(n, health.Config{})))
+		healthConfig := health.Config{}
+		if drainer != nil {
+			healthConfig.Draining = drainer.Draining
+		}
+		mux.Handle(healthPrefix, basicChain.Then(health.NewHandler(n, healthConfig)))
 	}
 
 	if flags&HandlerDev != 0 {
@@ -317,9 +372,42 @@ func Mux(
 		mux.Handle(devPrefix+"/", basicChain.Then(devpage.NewHandler(cfg)))
 	}
 
+	if cfg.Tenancy.Enabled && cfg.Tenancy.Resolve == "path" {
+		// Tenant connection endpoints are served by the same handlers with tenant
+		// name in context, e.g. /tenants/acme/connection/websocket.
+		tenantPrefix := strings.TrimRight(cfg.Tenancy.PathPrefix, "/") + "/"
+		mux.Handle(tenantPrefix, middleware.TenantFromPath(cfgContainer, tenantPrefix, mux))
+	}
+
 	return mux
 }
 
+// namedAPIKeys returns key names keyed by API key.
+func namedAPIKeys(keys []configtypes.NamedAPIKey) map[string]string {
+	if len(keys) == 0 {
+		return nil
+	}
+	named := make(map[string]string, len(keys))
+	for _, k := range keys {
+		named[k.Key] = k.Name
+	}
+	return named
+}
+
+// tenantAPIKeys returns tenant names keyed by tenant HTTP API keys.
+func tenantAPIKeys(cfg config.Config) map[string]string {
+	if !cfg.Tenancy.Enabled {
+		return nil
+	}
+	keys := make(map[string]string)
+	for _, t := range cfg.Tenants {
+		if t.APIKey != "" {
+			keys[t.APIKey] = t.Name
+		}
+	}
+	return keys
+}
+
 func getPingPongConfig(cfg config.Config) centrifuge.PingPongConfig {
 	pingInterval := cfg.Client.PingInterval
 	pongTimeout := cfg.Client.PongTimeout
@@ -370,7 +458,7 @@ func emulationHandlerConfig(cfg config.Config) centrifuge.EmulationConfig {
 }
 
 func runHTTPServers(
-	n *centrifuge.Node, cfgContainer *config.Container, apiExecutor *api.Executor, keepHeadersInContext bool,
+	n *centrifuge.Node, cfgContainer *config.Container, apiExecutor *api.Executor, apiKeyAuth *middleware.APIKeyAuth, keepHeadersInContext bool, longPollHub *longpoll.Hub, drainer *drain.Drainer, auditor *audit.Logger,
 ) ([]*http.Server, error) {
 	cfg := cfgContainer.Config()
 
@@ -427,7 +515,10 @@ func runHTTPServers(
 	if cfg.HTTPStream.Enabled {
 		portFlags |= HandlerHTTPStream
 	}
-	if cfg.SSE.Enabled || cfg.HTTPStream.Enabled {
+	if cfg.LongPoll.Enabled {
+		portFlags |= HandlerLongPoll
+	}
+	if cfg.SSE.Enabled || cfg.HTTPStream.Enabled || cfg.LongPoll.Enabled {
 		portFlags |= HandlerEmulation
 	}
 	if useAdmin && adminExternal {
@@ -515,7 +606,7 @@ func runHTTPServers(
 			}
 		}
 
-		mux := Mux(n, cfgContainer, apiExecutor, handlerFlags, keepHeadersInContext, wtServer)
+		mux := Mux(n, cfgContainer, apiExecutor, apiKeyAuth, handlerFlags, keepHeadersInContext, wtServer, longPollHub, drainer, auditor)
 
 		var h3Server *http3.Server
 		if useHTTP3 {
@@ -87,7 +87,8 @@ func centrifugeNodeConfig(version string, edition string, cfgContainer *config.C
 				Mode:                   chOpts.SharedPoll.Mode,
 				ChannelShutdownDelay:   chOpts.SharedPoll.ChannelShutdownDelay.ToDuration(),
 				TrackExpiredExtraDelay: chOpts.SharedPoll.TrackExpiredExtraDelay.ToDuration(),
-				PublishEnabled:         chOpts.SharedPoll.PublishEnabled,
+				// Coordinated channels get results polled by other nodes over the broker.
+				PublishEnabled: chOpts.SharedPoll.PublishEnabled || chOpts.SharedPoll.Coordinated,
 			}, true
 		}
 	}
@@ -25,6 +25,8 @@ func buildProxyMap(cfg config.Config) (*client.ProxyMap, bool, error) {
 		MapPublishProxies:        map[string]proxy.MapPublishProxy{},
 		MapRemoveProxies:         map[string]proxy.MapRemoveProxy{},
 		SharedPollRefreshProxies: map[string]*proxy.SharedPollRefreshHandler{},
+		ChannelStateProxies:      map[string]*proxy.ChannelStateHandler{},
+		CacheEmptyProxies:        map[string]*proxy.CacheEmptyHandler{},
 	}
 
 	var keepHeadersInContext bool
@@ -395,6 +397,57 @@ func buildProxyMap(cfg config.Config) (*client.ProxyMap, bool, error) {
 			}
 			log.Info().Str("proxy_name", sharedPollProxyName).Str("endpoint", tools.RedactedLogURLs(p.Endpoint)[0]).Str("namespace", ns.Name).Msg("shared poll refresh proxy enabled for channels in namespace")
 		}
+
+		channelStateProxyEnabled := ns.ChannelStateProxyEnabled
+		channelStateProxyName := ns.ChannelStateProxyName
+		if channelStateProxyEnabled {
+			var p proxy.Config
+			if channelStateProxyName == config.DefaultProxyName {
+				p = cfg.Channel.Proxy.ChannelState
+			} else {
+				p, proxyFound = namedProxies[channelStateProxyName]
+				if !proxyFound {
+					return nil, false, fmt.Errorf("channel state proxy not found: %s", channelStateProxyName)
+				}
+			}
+			if _, ok := proxyMap.ChannelStateProxies[channelStateProxyName]; !ok {
+				csp, err := proxy.GetChannelStateProxy(channelStateProxyName, p)
+				if err != nil {
+					return nil, false, fmt.Errorf("error creating channel state proxy %s: %w", channelStateProxyName, err)
+				}
+				proxyMap.ChannelStateProxies[channelStateProxyName] = proxy.NewChannelStateHandler(proxy.ChannelStateHandlerConfig{
+					Proxy: csp,
+					Name:  channelStateProxyName,
+				})
+			}
+			log.Info().Str("proxy_name", channelStateProxyName).Str("endpoint", tools.RedactedLogURLs(p.Endpoint)[0]).Str("namespace", ns.Name).Msg("channel state proxy enabled for channels in namespace")
+		}
+
+		cacheEmptyProxyEnabled := ns.CacheEmptyProxyEnabled
+		cacheEmptyProxyName := ns.CacheEmptyProxyName
+		if cacheEmptyProxyEnabled {
+			var p proxy.Config
+			if cacheEmptyProxyName == config.DefaultProxyName {
+				p = cfg.Channel.Proxy.CacheEmpty
+			} else {
+				p, proxyFound = namedProxies[cacheEmptyProxyName]
+				if !proxyFound {
+					return nil, false, fmt.Errorf("cache empty proxy not found: %s", cacheEmptyProxyName)
+				}
+			}
+			if _, ok := proxyMap.CacheEmptyProxies[cacheEmptyProxyName]; !ok {
+				cep, err := proxy.GetCacheEmptyProxy(cacheEmptyProxyName, p)
+				if err != nil {
+					return nil, false, fmt.Errorf("error creating cache empty proxy %s: %w", cacheEmptyProxyName, err)
+				}
+				proxyMap.CacheEmptyProxies[cacheEmptyProxyName] = proxy.NewCacheEmptyHandler(proxy.CacheEmptyHandlerConfig{
+					Proxy:   cep,
+					Name:    cacheEmptyProxyName,
+					Timeout: p.Timeout.ToDuration(),
+				})
+			}
+			log.Info().Str("proxy_name", cacheEmptyProxyName).Str("endpoint", tools.RedactedLogURLs(p.Endpoint)[0]).Str("namespace", ns.Name).Msg("cache empty proxy enabled for channels in namespace")
+		}
 	}
 
 	// Also check without-namespace channels for shared poll proxy.
@@ -426,6 +479,56 @@ func buildProxyMap(cfg config.Config) (*client.ProxyMap, bool, error) {
 		log.Info().Str("proxy_name", sharedPollProxyName).Str("endpoint", tools.RedactedLogURLs(p.Endpoint)[0]).Msg("shared poll refresh proxy enabled for channels without namespace")
 	}
 
+	channelStateProxyEnabled := cfg.Channel.WithoutNamespace.ChannelStateProxyEnabled
+	channelStateProxyName := cfg.Channel.WithoutNamespace.ChannelStateProxyName
+	if channelStateProxyEnabled {
+		var p proxy.Config
+		if channelStateProxyName == config.DefaultProxyName {
+			p = cfg.Channel.Proxy.ChannelState
+		} else {
+			p, proxyFound = namedProxies[channelStateProxyName]
+			if !proxyFound {
+				return nil, false, fmt.Errorf("channel state proxy not found: %s", channelStateProxyName)
+			}
+		}
+		if _, ok := proxyMap.ChannelStateProxies[channelStateProxyName]; !ok {
+			csp, err := proxy.GetChannelStateProxy(channelStateProxyName, p)
+			if err != nil {
+				return nil, false, fmt.Errorf("error creating channel state proxy %s: %w", channelStateProxyName, err)
+			}
+			proxyMap.ChannelStateProxies[channelStateProxyName] = proxy.NewChannelStateHandler(proxy.ChannelStateHandlerConfig{
+				Proxy: csp,
+				Name:  channelStateProxyName,
+			})
+		}
+		log.Info().Str("proxy_name", channelStateProxyName).Str("endpoint", tools.RedactedLogURLs(p.Endpoint)[0]).Msg("channel state proxy enabled for channels without namespace")
+	}
+
+	cacheEmptyProxyEnabled := cfg.Channel.WithoutNamespace.CacheEmptyProxyEnabled
+	cacheEmptyProxyName := cfg.Channel.WithoutNamespace.CacheEmptyProxyName
+	if cacheEmptyProxyEnabled {
+		var p proxy.Config
+		if cacheEmptyProxyName == config.DefaultProxyName {
+			p = cfg.Channel.Proxy.CacheEmpty
+		} else {
+			p, proxyFound = namedProxies[cacheEmptyProxyName]
+			if !proxyFound {
+				return nil, false, fmt.Errorf("cache empty proxy not found: %s", cacheEmptyProxyName)
+			}
+		}
+		if _, ok := proxyMap.CacheEmptyProxies[cacheEmptyProxyName]; !ok {
+			cep, err := proxy.GetCacheEmptyProxy(cacheEmptyProxyName, p)
+			if err != nil {
+				return nil, false, fmt.Errorf("error creating cache empty proxy %s: %w", cacheEmptyProxyName, err)
+			}
+			proxyMap.CacheEmptyProxies[cacheEmptyProxyName] = proxy.NewCacheEmptyHandler(proxy.CacheEmptyHandlerConfig{
+				Proxy: cep,
+				Name:  cacheEmptyProxyName,
+			})
+		}
+		log.Info().Str("proxy_name", cacheEmptyProxyName).Str("endpoint", tools.RedactedLogURLs(p.Endpoint)[0]).Msg("cache empty proxy enabled for channels without namespace")
+	}
+
 	rpcProxyEnabled := cfg.RPC.WithoutNamespace.ProxyEnabled
 	rpcProxyName := cfg.RPC.WithoutNamespace.ProxyName
 	if rpcProxyEnabled {
@@ -15,18 +15,26 @@ import (
 	"time"
 
 	"github.com/centrifugal/centrifugo/v6/internal/api"
+	"github.com/centrifugal/centrifugo/v6/internal/audit"
 	"github.com/centrifugal/centrifugo/v6/internal/build"
 	"github.com/centrifugal/centrifugo/v6/internal/client"
 	"github.com/centrifugal/centrifugo/v6/internal/config"
 	"github.com/centrifugal/centrifugo/v6/internal/confighelpers"
 	"github.com/centrifugal/centrifugo/v6/internal/consuming"
+	"github.com/centrifugal/centrifugo/v6/internal/drain"
+	"github.com/centrifugal/centrifugo/v6/internal/historyquery"
+	"github.com/centrifugal/centrifugo/v6/internal/introspect"
 	"github.com/centrifugal/centrifugo/v6/internal/jwtverify"
 	"github.com/centrifugal/centrifugo/v6/internal/logging"
+	"github.com/centrifugal/centrifugo/v6/internal/longpoll"
 	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+	"github.com/centrifugal/centrifugo/v6/internal/middleware"
 	"github.com/centrifugal/centrifugo/v6/internal/notify"
 	"github.com/centrifugal/centrifugo/v6/internal/service"
 	"github.com/centrifugal/centrifugo/v6/internal/survey"
 	"github.com/centrifugal/centrifugo/v6/internal/telemetry"
+	"github.com/centrifugal/centrifugo/v6/internal/tenant"
+	"github.com/centrifugal/centrifugo/v6/internal/throttle"
 	"github.com/centrifugal/centrifugo/v6/internal/tools"
 	"github.com/centrifugal/centrifugo/v6/internal/usage"
 
@@ -111,7 +119,7 @@ func Run(cmd *cobra.Command, configFile string) {
 
 	err = cfg.Validate()
 	if err != nil {
-		log.Fatal().Err(err).Msg("error validating config")
+		log.Fatal().Str("error", cfgMeta.RedactSecrets(err.Error())).Msg("error validating config")
 	}
 	cfgContainer, err := config.NewContainer(cfg)
 	if err != nil {
@@ -148,12 +156,28 @@ func Run(cmd *cobra.Command, configFile string) {
 		}
 	}
 
-	err = configureEngines(node, cfgContainer)
+	channelState, err := configureChannelState(node, cfgContainer, proxyMap.ChannelStateProxies)
+	if err != nil {
+		log.Fatal().Err(err).Msg("configure channel state events error")
+	}
+	if channelState != nil {
+		serviceManager.Register(channelState)
+	}
+
+	historyArchiver, err := configureHistoryArchive(node, cfgContainer)
+	if err != nil {
+		log.Fatal().Err(err).Msg("configure history archive error")
+	}
+	if historyArchiver != nil {
+		serviceManager.Register(historyArchiver)
+	}
+
+	broker, controller, presenceManager, err := configureEngines(node, cfgContainer, channelState, historyArchiver)
 	if err != nil {
 		log.Fatal().Err(err).Msg("configure engines error")
 	}
 
-	err = configureMapBroker(node, cfgContainer)
+	mapBroker, err := configureMapBroker(node, cfgContainer)
 	if err != nil {
 		log.Fatal().Err(err).Msg("configure map broker error")
 	}
@@ -181,7 +205,48 @@ func Run(cmd *cobra.Command, configFile string) {
 		}
 	}
 
+	sharedPollCoordinator, err := configureSharedPollCoordinator(node, cfgContainer, controller)
+	if err != nil {
+		log.Fatal().Err(err).Msg("configure shared poll coordination error")
+	}
+
+	connQuota, err := configureConnectionQuota(node, cfgContainer, presenceManager)
+	if err != nil {
+		log.Fatal().Err(err).Msg("configure connection quota error")
+	}
+
 	clientHandler := client.NewHandler(node, cfgContainer, tokenVerifier, subTokenVerifier, proxyMap)
+	if cfg.Client.TokenIntrospection.Enabled {
+		introspectVerifier, err := introspect.NewVerifier(cfg.Client.TokenIntrospection)
+		if err != nil {
+			log.Fatal().Err(err).Msg("error creating token introspection verifier")
+		}
+		clientHandler.SetConnectTokenVerifier(introspectVerifier)
+		serviceManager.Register(introspectVerifier)
+		log.Info().Str("endpoint", strings.Join(tools.RedactedLogURLs(cfg.Client.TokenIntrospection.Endpoint), ",")).
+			Msg("connection tokens verified using introspection endpoint")
+	}
+	if sharedPollCoordinator != nil {
+		clientHandler.SetSharedPollCoordinator(sharedPollCoordinator)
+	}
+	if connQuota != nil {
+		clientHandler.SetConnectionQuota(connQuota)
+		serviceManager.Register(connQuota)
+	}
+	var tenants *tenant.Registry
+	if cfg.Tenancy.Enabled {
+		tenants, err = tenant.New(cfgContainer)
+		if err != nil {
+			log.Fatal().Err(err).Msg("error creating tenant registry")
+		}
+		clientHandler.SetTenants(tenants)
+		log.Info().Int("num_tenants", len(cfg.Tenants)).Str("resolve", cfg.Tenancy.Resolve).Msg("multi-tenancy enabled")
+	}
+	var drainer *drain.Drainer
+	if cfg.Shutdown.Drain.Enabled {
+		drainer = drain.New(node, cfg.Shutdown.Drain)
+		clientHandler.SetDrainer(drainer)
+	}
 	err = clientHandler.Setup()
 	if err != nil {
 		log.Fatal().Err(err).Msg("error setting up client handler")
@@ -193,8 +258,19 @@ func Run(cmd *cobra.Command, configFile string) {
 		})
 	}
 
+	// Throttler is shared between client handler and API executors so that publications
+	// coming from different sources are coalesced within the same window.
+	throttler := throttle.New()
+	clientHandler.SetThrottler(throttler)
+
 	surveyCaller := survey.NewCaller(node)
 
+	var longPollHub *longpoll.Hub
+	if cfg.LongPoll.Enabled {
+		longPollHub = longpoll.NewHub(node, surveyCaller, cfg.LongPoll, getPingPongConfig(cfg))
+		serviceManager.Register(longPollHub)
+	}
+
 	useAPIOpentelemetry := cfg.OpenTelemetry.Enabled && cfg.OpenTelemetry.API
 	useConsumingOpentelemetry := cfg.OpenTelemetry.Enabled && cfg.OpenTelemetry.Consuming
 
@@ -210,6 +286,59 @@ func Run(cmd *cobra.Command, configFile string) {
 		Protocol:         "consuming",
 		UseOpenTelemetry: useConsumingOpentelemetry,
 	})
+	httpAPIExecutor.SetThrottler(throttler)
+	grpcAPIExecutor.SetThrottler(throttler)
+	consumingAPIExecutor.SetThrottler(throttler)
+	if tenants != nil {
+		httpAPIExecutor.SetTenants(tenants)
+		grpcAPIExecutor.SetTenants(tenants)
+		consumingAPIExecutor.SetTenants(tenants)
+	}
+	if filterReader, ok := mapBroker.(api.MapStateFilterReader); ok {
+		httpAPIExecutor.SetMapStateFilterReader(filterReader)
+		grpcAPIExecutor.SetMapStateFilterReader(filterReader)
+		consumingAPIExecutor.SetMapStateFilterReader(filterReader)
+	}
+	historyRangeReader, err := configureHistoryRangeReader(cfgContainer, broker)
+	if err != nil {
+		log.Fatal().Err(err).Msg("configure history range reader error")
+	}
+	if historyRangeReader != nil {
+		httpAPIExecutor.SetHistoryRangeReader(historyRangeReader)
+		grpcAPIExecutor.SetHistoryRangeReader(historyRangeReader)
+		consumingAPIExecutor.SetHistoryRangeReader(historyRangeReader)
+	}
+	if cfg.RPC.HistoryRange.Enabled {
+		log.Info().Str("method", cfg.RPC.HistoryRange.Method).Msg("RPC history range extension enabled")
+		clientHandler.SetHistoryReader(historyquery.NewReader(node, historyRangeReader))
+		clientHandler.SetRPCExtension(cfg.RPC.HistoryRange.Method, clientHandler.OnHistoryRange)
+	}
+	var auditor *audit.Logger
+	if cfg.Audit.Enabled {
+		auditor, err = audit.New(node.ID(), cfg.Audit)
+		if err != nil {
+			log.Fatal().Err(err).Msg("error creating audit logger")
+		}
+		httpAPIExecutor.SetAuditor(auditor)
+		grpcAPIExecutor.SetAuditor(auditor)
+		consumingAPIExecutor.SetAuditor(auditor)
+		serviceManager.Register(auditor)
+	}
+	if snapshotImporter, ok := mapBroker.(api.MapSnapshotImporter); ok {
+		httpAPIExecutor.SetMapSnapshotImporter(snapshotImporter)
+		grpcAPIExecutor.SetMapSnapshotImporter(snapshotImporter)
+		consumingAPIExecutor.SetMapSnapshotImporter(snapshotImporter)
+	}
+	scheduler, err := configureScheduler(cfgContainer, broker, httpAPIExecutor.ExecuteScheduled)
+	if err != nil {
+		log.Fatal().Err(err).Msg("configure scheduled publications error")
+	}
+	if scheduler != nil {
+		httpAPIExecutor.SetScheduler(scheduler)
+		grpcAPIExecutor.SetScheduler(scheduler)
+		consumingAPIExecutor.SetScheduler(scheduler)
+		serviceManager.Register(scheduler)
+	}
 
 	consumingHandler := api.NewConsumingHandler(node, consumingAPIExecutor, api.ConsumingHandlerConfig{
 		UseOpenTelemetry: useConsumingOpentelemetry,
@@ -245,7 +374,9 @@ func Run(cmd *cobra.Command, configFile string) {
 			UniHTTPStream: cfg.UniHTTPStream.Enabled,
 			UniSSE:        cfg.UniSSE.Enabled,
 			UniGRPC:       cfg.UniGRPC.Enabled,
+			BidiGRPC:      cfg.BidiGRPC.Enabled,
 			WebTransport:  cfg.WebTransport.Enabled,
+			LongPoll:      cfg.LongPoll.Enabled,
 
 			EnabledConsumers: usage.GetEnabledConsumers(cfg.Consumers),
 
@@ -282,10 +413,14 @@ func Run(cmd *cobra.Command, configFile string) {
 		}
 	}()
 
+	var grpcAPIKeyAuth *api.GRPCKeyAuth
 	var grpcAPIServer *grpc.Server
 	if cfg.GrpcAPI.Enabled {
+		if cfg.GrpcAPI.Key != "" || len(cfg.GrpcAPI.NamedKeys) > 0 {
+			grpcAPIKeyAuth = api.NewGRPCKeyAuth(cfg.GrpcAPI.Key, namedAPIKeys(cfg.GrpcAPI.NamedKeys))
+		}
 		var err error
-		grpcAPIServer, err = runGRPCAPIServer(cfg, node, useAPIOpentelemetry, grpcAPIExecutor)
+		grpcAPIServer, err = runGRPCAPIServer(cfg, node, useAPIOpentelemetry, grpcAPIExecutor, grpcAPIKeyAuth)
 		if err != nil {
 			log.Fatal().Err(err).Msg("error creating GRPC API server")
 		}
@@ -294,13 +429,27 @@ func Run(cmd *cobra.Command, configFile string) {
 	var grpcUniServer *grpc.Server
 	if cfg.UniGRPC.Enabled {
 		var err error
-		grpcAPIServer, err = runGRPCUniServer(cfg, node)
+		grpcUniServer, err = runGRPCUniServer(cfg, node)
 		if err != nil {
 			log.Fatal().Err(err).Msg("error creating GRPC API server")
 		}
 	}
 
-	httpServers, err := runHTTPServers(node, cfgContainer, httpAPIExecutor, keepHeadersInContext)
+	var grpcBidiServer *grpc.Server
+	if cfg.BidiGRPC.Enabled {
+		var err error
+		grpcBidiServer, err = runGRPCBidiServer(cfg, node)
+		if err != nil {
+			log.Fatal().Err(err).Msg("error creating bidirectional GRPC server")
+		}
+	}
+
+	var httpAPIKeyAuth *middleware.APIKeyAuth
+	if !cfg.HttpAPI.Insecure {
+		httpAPIKeyAuth = middleware.NewAPIKeyAuth(cfg.HttpAPI.Key).WithNamedKeys(namedAPIKeys(cfg.HttpAPI.NamedKeys)).WithTenantKeys(tenantAPIKeys(cfg))
+	}
+
+	httpServers, err := runHTTPServers(node, cfgContainer, httpAPIExecutor, httpAPIKeyAuth, keepHeadersInContext, longPollHub, drainer, auditor)
 	if err != nil {
 		log.Fatal().Err(err).Msg("error running HTTP server")
 	}
@@ -308,21 +457,27 @@ func Run(cmd *cobra.Command, configFile string) {
 	logStartWarnings(cfg, cfgMeta)
 
 	handleSignals(
-		cmd, configFile, node, cfgContainer, tokenVerifier, subTokenVerifier,
-		httpServers, grpcAPIServer, grpcUniServer,
-		serviceDone, serviceCancel,
+		cmd, configFile, node, cfgContainer, tokenVerifier, subTokenVerifier, httpAPIKeyAuth, grpcAPIKeyAuth,
+		httpServers, grpcAPIServer, grpcUniServer, grpcBidiServer,
+		serviceDone, serviceCancel, throttler, drainer, tenants,
 	)
 }
 
 func handleSignals(
 	cmd *cobra.Command, configFile string, n *centrifuge.Node, cfgContainer *config.Container,
-	tokenVerifier *jwtverify.VerifierJWT, subTokenVerifier *jwtverify.VerifierJWT, httpServers []*http.Server,
-	grpcAPIServer *grpc.Server, grpcUniServer *grpc.Server, serviceDone chan struct{},
-	serviceCancel context.CancelFunc,
+	tokenVerifier *jwtverify.VerifierJWT, subTokenVerifier *jwtverify.VerifierJWT,
+	httpAPIKeyAuth *middleware.APIKeyAuth, grpcAPIKeyAuth *api.GRPCKeyAuth, httpServers []*http.Server,
+	grpcAPIServer *grpc.Server, grpcUniServer *grpc.Server, grpcBidiServer *grpc.Server, serviceDone chan struct{},
+	serviceCancel context.CancelFunc, throttler *throttle.Throttler, drainer *drain.Drainer,
+	tenants *tenant.Registry,
 ) {
 	cfg := cfgContainer.Config()
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGHUP, syscall.SIGINT, os.Interrupt, syscall.SIGTERM)
+	signals := []os.Signal{syscall.SIGHUP, syscall.SIGINT, os.Interrupt, syscall.SIGTERM}
+	if drainSignal != nil {
+		signals = append(signals, drainSignal)
+	}
+	signal.Notify(sigCh, signals...)
 	for {
 		sig := <-sigCh
 		log.Info().Msgf("signal received: %v", sig)
@@ -332,13 +487,13 @@ func handleSignals(
 			// Note that Centrifugo can't reload config for everything – just best effort to reload what's possible.
 			// We can now reload channel options and token verifiers.
 			log.Info().Msg("reloading configuration")
-			newCfg, _, err := config.GetConfig(cmd, configFile)
+			newCfg, newCfgMeta, err := config.GetConfig(cmd, configFile)
 			if err != nil {
 				log.Err(err).Msg("error reading config")
 				continue
 			}
 			if err = newCfg.Validate(); err != nil {
-				log.Error().Msgf("error validating config: %v", err)
+				log.Error().Msgf("error validating config: %s", newCfgMeta.RedactSecrets(err.Error()))
 				continue
 			}
 			verifierConfig, err := confighelpers.MakeVerifierConfig(newCfg.Client.Token)
@@ -361,11 +516,38 @@ func handleSignals(
 					continue
 				}
 			}
+			if tenants != nil {
+				if err = tenants.Reload(newCfg); err != nil {
+					log.Error().Msgf("error reloading: %v", err)
+					continue
+				}
+			}
 			if err = cfgContainer.Reload(newCfg); err != nil {
 				log.Error().Msgf("error reloading: %v", err)
 				continue
 			}
+			// API keys may come from secret references, apply rotated values.
+			if httpAPIKeyAuth != nil {
+				httpAPIKeyAuth.Reload(middleware.APIKeys{
+					Key:        newCfg.HttpAPI.Key,
+					NamedKeys:  namedAPIKeys(newCfg.HttpAPI.NamedKeys),
+					TenantKeys: tenantAPIKeys(newCfg),
+				})
+			}
+			if grpcAPIKeyAuth != nil {
+				grpcAPIKeyAuth.Reload(newCfg.GrpcAPI.Key, namedAPIKeys(newCfg.GrpcAPI.NamedKeys))
+			}
+			if len(newCfgMeta.SecretRefs) > 0 {
+				logSecretsReloaded(newCfgMeta.SecretRefs)
+			}
 			log.Info().Msg("configuration successfully reloaded")
+		case drainSignal:
+			// Start draining node connections without shutting down on SIGUSR1.
+			if drainer == nil {
+				log.Warn().Msg("connection draining is not enabled")
+				continue
+			}
+			drainer.Start(context.Background())
 		case syscall.SIGINT, os.Interrupt, syscall.SIGTERM:
 			log.Info().Msg("shutting down ...")
 			pidFile := cfg.PidFile
@@ -377,6 +559,20 @@ func handleSignals(
 				log.Fatal().Msg("shutdown timeout reached")
 			})
 
+			if drainer != nil {
+				// Disconnect clients gradually before stopping servers so that they
+				// spread over other nodes instead of reconnecting at once.
+				drainTimeout := cfg.Shutdown.Drain.Timeout.ToDuration()
+				drainCtx, drainCancel := context.WithTimeout(context.Background(), drainTimeout)
+				drainer.Start(drainCtx)
+				select {
+				case <-drainer.Done():
+				case <-drainCtx.Done():
+					log.Warn().Msg("drain timeout reached, shutting down with remaining connections")
+				}
+				drainCancel()
+			}
+
 			var wg sync.WaitGroup
 
 			if grpcAPIServer != nil {
@@ -395,6 +591,14 @@ func handleSignals(
 				}()
 			}
 
+			if grpcBidiServer != nil {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					grpcBidiServer.GracefulStop()
+				}()
+			}
+
 			for _, srv := range httpServers {
 				wg.Add(1)
 				go func(srv *http.Server) {
@@ -403,6 +607,8 @@ func handleSignals(
 				}(srv)
 			}
 
+			// Deliver publications pending in throttle windows before node shutdown.
+			throttler.Close()
 			_ = n.Shutdown(context.Background()) // We have a separate timeout goroutine.
 			wg.Wait()
 
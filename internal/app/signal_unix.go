@@ -0,0 +1,11 @@
+//go:build !windows
+
+package app
+
+import (
+	"os"
+	"syscall"
+)
+
+// drainSignal starts draining node connections without shutting down.
+var drainSignal os.Signal = syscall.SIGUSR1
@@ -0,0 +1,8 @@
+//go:build windows
+
+package app
+
+import "os"
+
+// drainSignal is not available on Windows, draining may be started over admin API.
+var drainSignal os.Signal
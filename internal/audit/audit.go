@@ -0,0 +1,247 @@
+// Package audit records server API commands and admin actions together with caller
+// identity and writes records to configured sinks.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Record is a single audit log entry.
+type Record struct {
+	Time     time.Time       `json:"time"`
+	Node     string          `json:"node"`
+	Source   string          `json:"source"`
+	Identity string          `json:"identity"`
+	Method   string          `json:"method"`
+	Channels []string        `json:"channels,omitempty"`
+	Users    []string        `json:"users,omitempty"`
+	Params   json.RawMessage `json:"params,omitempty"`
+	Success  bool            `json:"success"`
+	Error    *Error          `json:"error,omitempty"`
+}
+
+// Error of recorded command.
+type Error struct {
+	Code    uint32 `json:"code"`
+	Message string `json:"message"`
+}
+
+// Sink writes audit records.
+type Sink interface {
+	// Name of sink used in logs and metrics.
+	Name() string
+	Write(ctx context.Context, records []Record) error
+	Close() error
+}
+
+const (
+	redactedValue    = "[redacted]"
+	sinkWriteTimeout = 10 * time.Second
+)
+
+var defaultRedactFields = []string{"data", "b64data", "info", "b64info", "meta", "params"}
+
+// Logger filters audit records and writes them to sinks in batches.
+type Logger struct {
+	config  configtypes.Audit
+	nodeID  string
+	sinks   []Sink
+	records chan Record
+	methods map[string]struct{}
+	exclude map[string]struct{}
+	redact  map[string]struct{}
+}
+
+// New creates Logger with sinks enabled in configuration. Logger must be run to
+// write records.
+func New(nodeID string, cfg configtypes.Audit) (*Logger, error) {
+	var sinks []Sink
+	if cfg.File.Enabled {
+		sink, err := newFileSink(cfg.File)
+		if err != nil {
+			return nil, fmt.Errorf("error creating file sink: %w", err)
+		}
+		sinks = append(sinks, sink)
+	}
+	if cfg.Postgres.Enabled {
+		sink, err := newPostgresSink(cfg.Postgres)
+		if err != nil {
+			closeSinks(sinks)
+			return nil, fmt.Errorf("error creating postgresql sink: %w", err)
+		}
+		sinks = append(sinks, sink)
+	}
+	if cfg.RedisStream.Enabled {
+		sink, err := newRedisStreamSink(cfg.RedisStream)
+		if err != nil {
+			closeSinks(sinks)
+			return nil, fmt.Errorf("error creating redis stream sink: %w", err)
+		}
+		sinks = append(sinks, sink)
+	}
+	return newLogger(nodeID, cfg, sinks), nil
+}
+
+func newLogger(nodeID string, cfg configtypes.Audit, sinks []Sink) *Logger {
+	redactFields := cfg.RedactFields
+	if len(redactFields) == 0 {
+		redactFields = defaultRedactFields
+	}
+	return &Logger{
+		config:  cfg,
+		nodeID:  nodeID,
+		sinks:   sinks,
+		records: make(chan Record, cfg.BufferSize),
+		methods: toSet(cfg.Methods),
+		exclude: toSet(cfg.ExcludeMethods),
+		redact:  toSet(redactFields),
+	}
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
+
+// Enabled checks whether method passes method filters.
+func (l *Logger) Enabled(method string) bool {
+	if _, ok := l.exclude[method]; ok {
+		return false
+	}
+	if len(l.methods) == 0 {
+		return true
+	}
+	_, ok := l.methods[method]
+	return ok
+}
+
+// RecordParams reports whether records must contain request params.
+func (l *Logger) RecordParams() bool {
+	return l.config.RecordParams
+}
+
+// Log enqueues record for writing. Records of filtered out methods are skipped, params
+// are redacted. Log never blocks: record is dropped if buffer is full.
+func (l *Logger) Log(r Record) {
+	if !l.Enabled(r.Method) {
+		return
+	}
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	r.Node = l.nodeID
+	r.Success = r.Error == nil
+	if !l.config.RecordParams {
+		r.Params = nil
+	} else if len(r.Params) > 0 {
+		r.Params = l.redactParams(r.Params)
+	}
+	select {
+	case l.records <- r:
+	default:
+		metrics.IncAuditRecordDropped()
+	}
+}
+
+func (l *Logger) redactParams(params json.RawMessage) json.RawMessage {
+	decoder := json.NewDecoder(bytes.NewReader(params))
+	decoder.UseNumber()
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil
+	}
+	redacted, err := json.Marshal(l.redactValue(v))
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
+
+func (l *Logger) redactValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, item := range val {
+			if _, ok := l.redact[k]; ok {
+				val[k] = redactedValue
+				continue
+			}
+			val[k] = l.redactValue(item)
+		}
+		return val
+	case []any:
+		for i, item := range val {
+			val[i] = l.redactValue(item)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+// Run writes records to sinks until ctx is done. Buffered records are flushed and
+// sinks are closed upon exit.
+func (l *Logger) Run(ctx context.Context) error {
+	ticker := time.NewTicker(l.config.FlushInterval.ToDuration())
+	defer ticker.Stop()
+	batch := make([]Record, 0, l.config.BatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			// Run is the only reader, so receive never blocks here.
+			for len(l.records) > 0 {
+				batch = append(batch, <-l.records)
+				if len(batch) >= l.config.BatchSize {
+					l.flush(batch)
+					batch = batch[:0]
+				}
+			}
+			l.flush(batch)
+			closeSinks(l.sinks)
+			return nil
+		case r := <-l.records:
+			batch = append(batch, r)
+			if len(batch) >= l.config.BatchSize {
+				l.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			l.flush(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+func (l *Logger) flush(batch []Record) {
+	if len(batch) == 0 {
+		return
+	}
+	for _, sink := range l.sinks {
+		ctx, cancel := context.WithTimeout(context.Background(), sinkWriteTimeout)
+		err := sink.Write(ctx, batch)
+		cancel()
+		if err != nil {
+			metrics.IncAuditSinkError(sink.Name())
+			log.Error().Err(err).Str("sink", sink.Name()).Int("num_records", len(batch)).Msg("error writing audit records")
+		}
+	}
+}
+
+func closeSinks(sinks []Sink) {
+	for _, sink := range sinks {
+		if err := sink.Close(); err != nil {
+			log.Error().Err(err).Str("sink", sink.Name()).Msg("error closing audit sink")
+		}
+	}
+}
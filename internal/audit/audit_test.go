@@ -0,0 +1,197 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	_ = metrics.Init(metrics.Config{
+		Registerer: prometheus.NewRegistry(),
+	})
+	os.Exit(m.Run())
+}
+
+type testSink struct {
+	mu      sync.Mutex
+	batches [][]Record
+	closed  bool
+}
+
+func (s *testSink) Name() string {
+	return "test"
+}
+
+func (s *testSink) Write(_ context.Context, records []Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, append([]Record(nil), records...))
+	return nil
+}
+
+func (s *testSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *testSink) records() []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var records []Record
+	for _, b := range s.batches {
+		records = append(records, b...)
+	}
+	return records
+}
+
+func testConfig() configtypes.Audit {
+	return configtypes.Audit{
+		Enabled:       true,
+		BufferSize:    100,
+		BatchSize:     10,
+		FlushInterval: configtypes.Duration(time.Hour),
+	}
+}
+
+func TestLoggerEnabled(t *testing.T) {
+	cfg := testConfig()
+	l := newLogger("node", cfg, nil)
+	require.True(t, l.Enabled("publish"))
+	require.True(t, l.Enabled("info"))
+
+	cfg.Methods = []string{"publish", "disconnect"}
+	cfg.ExcludeMethods = []string{"disconnect"}
+	l = newLogger("node", cfg, nil)
+	require.True(t, l.Enabled("publish"))
+	require.False(t, l.Enabled("disconnect"))
+	require.False(t, l.Enabled("info"))
+}
+
+func TestLoggerRedactParams(t *testing.T) {
+	cfg := testConfig()
+	cfg.RecordParams = true
+	l := newLogger("node", cfg, nil)
+	l.Log(Record{
+		Method: "broadcast",
+		Params: json.RawMessage(`{"channels":["a","b"],"data":{"secret":1},"tags":{"data":"x","k":"v"},"items":[{"b64data":"eA=="}]}`),
+	})
+	r := <-l.records
+	require.Equal(t, "node", r.Node)
+	require.True(t, r.Success)
+	require.False(t, r.Time.IsZero())
+	require.JSONEq(t, `{"channels":["a","b"],"data":"[redacted]","tags":{"data":"[redacted]","k":"v"},"items":[{"b64data":"[redacted]"}]}`, string(r.Params))
+}
+
+func TestLoggerParamsNotRecorded(t *testing.T) {
+	l := newLogger("node", testConfig(), nil)
+	l.Log(Record{
+		Method: "publish",
+		Params: json.RawMessage(`{"channel":"test"}`),
+		Error:  &Error{Code: 103, Message: "permission denied"},
+	})
+	r := <-l.records
+	require.Nil(t, r.Params)
+	require.False(t, r.Success)
+}
+
+func TestLoggerDropsWhenBufferFull(t *testing.T) {
+	cfg := testConfig()
+	cfg.BufferSize = 1
+	l := newLogger("node", cfg, nil)
+	l.Log(Record{Method: "publish"})
+	l.Log(Record{Method: "publish"})
+	require.Len(t, l.records, 1)
+}
+
+func TestLoggerRun(t *testing.T) {
+	cfg := testConfig()
+	cfg.BatchSize = 2
+	sink := &testSink{}
+	l := newLogger("node", cfg, []Sink{sink})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- l.Run(ctx) }()
+
+	for i := 0; i < 3; i++ {
+		l.Log(Record{Method: "publish"})
+	}
+	require.Eventually(t, func() bool {
+		return len(sink.records()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+	require.Len(t, sink.records(), 3)
+	require.True(t, sink.closed)
+}
+
+func readLines(t *testing.T, path string) []Record {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r Record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+		records = append(records, r)
+	}
+	require.NoError(t, scanner.Err())
+	return records
+}
+
+func TestFileSinkRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := newFileSink(configtypes.AuditFileSink{
+		Enabled:    true,
+		Path:       path,
+		MaxSizeMB:  1,
+		MaxBackups: 2,
+	})
+	require.NoError(t, err)
+	defer func() { _ = sink.Close() }()
+	// Lower size limit to rotate after each record.
+	sink.maxSize = 10
+
+	for _, method := range []string{"publish", "broadcast", "disconnect", "unsubscribe"} {
+		require.NoError(t, sink.Write(context.Background(), []Record{{Method: method}}))
+	}
+
+	current := readLines(t, path)
+	require.Len(t, current, 1)
+	require.Equal(t, "unsubscribe", current[0].Method)
+	require.Equal(t, "disconnect", readLines(t, path+".1")[0].Method)
+	require.Equal(t, "broadcast", readLines(t, path+".2")[0].Method)
+	_, err = os.Stat(path + ".3")
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestFileSinkAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	cfg := configtypes.AuditFileSink{Enabled: true, Path: path, MaxSizeMB: 1, MaxBackups: 1}
+	sink, err := newFileSink(cfg)
+	require.NoError(t, err)
+	require.NoError(t, sink.Write(context.Background(), []Record{{Method: "publish"}}))
+	require.NoError(t, sink.Close())
+
+	sink, err = newFileSink(cfg)
+	require.NoError(t, err)
+	require.NoError(t, sink.Write(context.Background(), []Record{{Method: "broadcast"}}))
+	require.NoError(t, sink.Close())
+
+	require.Len(t, readLines(t, path), 2)
+}
@@ -0,0 +1,108 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+)
+
+// fileSink writes records to a JSON-lines file and rotates it by size.
+type fileSink struct {
+	config  configtypes.AuditFileSink
+	maxSize int64
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func newFileSink(cfg configtypes.AuditFileSink) (*fileSink, error) {
+	s := &fileSink{
+		config:  cfg,
+		maxSize: int64(cfg.MaxSizeMB) * 1024 * 1024,
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileSink) Name() string {
+	return "file"
+}
+
+func (s *fileSink) open() error {
+	f, err := os.OpenFile(s.config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	s.file = f
+	s.size = info.Size()
+	return nil
+}
+
+// rotate shifts rotated files (path.1 becomes path.2 and so on), moves current file
+// to path.1 and opens a new one. The oldest file is removed when MaxBackups reached.
+func (s *fileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	if s.config.MaxBackups == 0 {
+		if err := os.Remove(s.config.Path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return s.open()
+	}
+	_ = os.Remove(s.backupPath(s.config.MaxBackups))
+	for i := s.config.MaxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(s.backupPath(i), s.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(s.config.Path, s.backupPath(1)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return s.open()
+}
+
+func (s *fileSink) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", s.config.Path, n)
+}
+
+func (s *fileSink) Write(_ context.Context, records []Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range records {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		line = append(line, '\n')
+		if s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+			if err := s.rotate(); err != nil {
+				return fmt.Errorf("error rotating file: %w", err)
+			}
+		}
+		n, err := s.file.Write(line)
+		s.size += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
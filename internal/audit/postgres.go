@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// postgresSink inserts records into PostgreSQL table. Table name is validated as SQL
+// identifier in configuration, so it's safe to use in queries.
+type postgresSink struct {
+	pool   *pgxpool.Pool
+	config configtypes.AuditPostgresSink
+}
+
+func newPostgresSink(cfg configtypes.AuditPostgresSink) (*postgresSink, error) {
+	conf, err := pgxpool.ParseConfig(cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing postgresql DSN: %w", err)
+	}
+	pool, err := pgxpool.NewWithConfig(context.Background(), conf)
+	if err != nil {
+		return nil, fmt.Errorf("error creating postgresql pool: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	s := &postgresSink{pool: pool, config: cfg}
+	if cfg.CreateTable {
+		if err := s.createTable(ctx); err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("error creating table: %w", err)
+		}
+	}
+	return s, nil
+}
+
+func (s *postgresSink) createTable(ctx context.Context) error {
+	_, err := s.pool.Exec(ctx, `CREATE TABLE IF NOT EXISTS `+s.config.TableName+` (
+	id BIGSERIAL PRIMARY KEY,
+	time TIMESTAMPTZ NOT NULL,
+	node TEXT NOT NULL,
+	source TEXT NOT NULL,
+	identity TEXT NOT NULL,
+	method TEXT NOT NULL,
+	channels TEXT[],
+	users TEXT[],
+	params JSONB,
+	success BOOLEAN NOT NULL,
+	error_code INTEGER,
+	error_message TEXT
+)`)
+	return err
+}
+
+func (s *postgresSink) Name() string {
+	return "postgresql"
+}
+
+func (s *postgresSink) Write(ctx context.Context, records []Record) error {
+	query := `INSERT INTO ` + s.config.TableName + ` (time, node, source, identity, method, channels, users, params, success, error_code, error_message) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	batch := &pgx.Batch{}
+	for _, r := range records {
+		var params any
+		if len(r.Params) > 0 {
+			params = string(r.Params)
+		}
+		var errorCode *uint32
+		var errorMessage *string
+		if r.Error != nil {
+			errorCode = &r.Error.Code
+			errorMessage = &r.Error.Message
+		}
+		batch.Queue(query, r.Time, r.Node, r.Source, r.Identity, r.Method, r.Channels, r.Users, params, r.Success, errorCode, errorMessage)
+	}
+	return s.pool.SendBatch(ctx, batch).Close()
+}
+
+func (s *postgresSink) Close() error {
+	s.pool.Close()
+	return nil
+}
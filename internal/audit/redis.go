@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/redisqueue"
+	"github.com/centrifugal/centrifugo/v6/internal/redisshard"
+)
+
+// redisStreamSink adds records to Redis stream, one stream message per record.
+type redisStreamSink struct {
+	shard    *redisshard.RedisShard
+	producer *redisqueue.Producer
+}
+
+func newRedisStreamSink(cfg configtypes.AuditRedisStreamSink) (*redisStreamSink, error) {
+	shards, err := redisshard.BuildRedisShards(cfg.Redis)
+	if err != nil {
+		return nil, err
+	}
+	if len(shards) == 0 {
+		return nil, errors.New("no redis shards configured")
+	}
+	// Single stream key always belongs to one shard, extra shards are not used.
+	for _, shard := range shards[1:] {
+		shard.Close()
+	}
+	producer, err := redisqueue.NewProducer(shards[0], redisqueue.ProducerOptions{
+		Stream:               cfg.Stream,
+		StreamMaxLength:      cfg.MaxLength,
+		ApproximateMaxLength: true,
+	})
+	if err != nil {
+		shards[0].Close()
+		return nil, err
+	}
+	return &redisStreamSink{shard: shards[0], producer: producer}, nil
+}
+
+func (s *redisStreamSink) Name() string {
+	return "redis_stream"
+}
+
+func (s *redisStreamSink) Write(_ context.Context, records []Record) error {
+	messages := make([]*redisqueue.Message, 0, len(records))
+	for _, r := range records {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, &redisqueue.Message{
+			Values: map[string]string{"record": string(data)},
+		})
+	}
+	return s.producer.Enqueue(messages...)
+}
+
+func (s *redisStreamSink) Close() error {
+	s.shard.Close()
+	return nil
+}
@@ -0,0 +1,11 @@
+#!/bin/bash
+
+set -e
+
+# go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@latest
+# git clone https://github.com/centrifugal/protocol /tmp/protocol
+
+protoc -I ./ -I /tmp/protocol/definitions \
+  bidistream.proto \
+  --go-grpc_out=. \
+  --go-grpc_opt=Mclient.proto=github.com/centrifugal/protocol
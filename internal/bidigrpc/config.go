@@ -0,0 +1,65 @@
+package bidigrpc
+
+import (
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+
+	"github.com/centrifugal/centrifuge"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+type Config = configtypes.BidiGRPC
+
+// Defaults used when corresponding options are not set.
+const (
+	DefaultPingInterval     = 25 * time.Second
+	DefaultPongTimeout      = 8 * time.Second
+	DefaultKeepaliveTime    = 25 * time.Second
+	DefaultKeepaliveTimeout = 5 * time.Second
+	DefaultKeepaliveMinTime = 5 * time.Second
+)
+
+// pingPongConfig returns application-level ping-pong configuration. Zero values
+// are replaced with defaults, negative values disable pings or pong checks.
+func pingPongConfig(c configtypes.PingPong) centrifuge.PingPongConfig {
+	pingInterval := c.PingInterval.ToDuration()
+	if pingInterval == 0 {
+		pingInterval = DefaultPingInterval
+	}
+	pongTimeout := c.PongTimeout.ToDuration()
+	if pongTimeout == 0 {
+		pongTimeout = DefaultPongTimeout
+	}
+	return centrifuge.PingPongConfig{
+		PingInterval: pingInterval,
+		PongTimeout:  pongTimeout,
+	}
+}
+
+// KeepaliveServerOptions returns gRPC server options with HTTP/2 keepalive
+// configuration. Zero values are replaced with defaults.
+func KeepaliveServerOptions(c configtypes.GRPCKeepalive) []grpc.ServerOption {
+	keepaliveTime := c.Time.ToDuration()
+	if keepaliveTime == 0 {
+		keepaliveTime = DefaultKeepaliveTime
+	}
+	keepaliveTimeout := c.Timeout.ToDuration()
+	if keepaliveTimeout == 0 {
+		keepaliveTimeout = DefaultKeepaliveTimeout
+	}
+	minTime := c.MinTime.ToDuration()
+	if minTime == 0 {
+		minTime = DefaultKeepaliveMinTime
+	}
+	return []grpc.ServerOption{
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime: minTime,
+		}),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    keepaliveTime,
+			Timeout: keepaliveTimeout,
+		}),
+	}
+}
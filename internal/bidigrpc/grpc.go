@@ -0,0 +1,112 @@
+package bidigrpc
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/bidigrpc/bidistream"
+	"github.com/centrifugal/centrifugo/v6/internal/logging"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/centrifugal/protocol"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RegisterService ...
+func RegisterService(server *grpc.Server, service bidistream.CentrifugoBidiStreamServer) error {
+	bidistream.RegisterCentrifugoBidiStreamServer(server, service)
+	return nil
+}
+
+// Service can work with bidirectional client GRPC connections.
+type Service struct {
+	bidistream.UnimplementedCentrifugoBidiStreamServer
+	config Config
+	node   *centrifuge.Node
+}
+
+// NewService creates new Service.
+func NewService(n *centrifuge.Node, c Config) *Service {
+	return &Service{
+		config: c,
+		node:   n,
+	}
+}
+
+// Communicate is a bidirectional stream carrying client protocol commands and replies.
+func (s *Service) Communicate(stream bidistream.CentrifugoBidiStream_CommunicateServer) error {
+	transport := newGRPCTransport(stream, pingPongConfig(s.config.PingPong))
+
+	select {
+	case <-s.node.NotifyShutdown():
+		return nil
+	default:
+	}
+
+	c, closeFn, err := centrifuge.NewClient(stream.Context(), s.node, transport)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = closeFn() }()
+
+	if logging.Enabled(logging.DebugLevel) {
+		log.Debug().Str("transport", transport.Name()).Str("client", c.ID()).Msg("client connection established")
+		defer func(started time.Time) {
+			log.Debug().Str("transport", transport.Name()).Str("client", c.ID()).
+				Str("duration", time.Since(started).String()).Msg("client connection completed")
+		}(time.Now())
+	}
+
+	// Commands are read in a separate goroutine so that server-side disconnect
+	// completes the stream without waiting for the next client command.
+	readDone := make(chan struct{})
+	go func() {
+		token := tokenFromMetadata(stream)
+		for {
+			cmd := &protocol.Command{}
+			if err := stream.RecvMsg(cmd); err != nil {
+				if !errors.Is(err, io.EOF) && stream.Context().Err() == nil {
+					log.Info().Err(err).Str("transport", transportName).Str("client", c.ID()).Msg("error receiving command")
+				}
+				close(readDone)
+				return
+			}
+			if cmd.Connect != nil && cmd.Connect.Token == "" && token != "" {
+				cmd.Connect.Token = token
+			}
+			if ok := c.HandleCommand(cmd, cmd.SizeVT()); !ok {
+				// Client is being disconnected, transport will be closed.
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-transport.closeCh:
+	case <-readDone:
+	case <-stream.Context().Done():
+	}
+	return nil
+}
+
+// tokenFromMetadata extracts connection token from authorization metadata
+// in "Bearer <token>" format.
+func tokenFromMetadata(stream grpc.ServerStream) string {
+	md, ok := metadata.FromIncomingContext(stream.Context())
+	if !ok {
+		return ""
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ""
+	}
+	token, found := strings.CutPrefix(values[0], "Bearer ")
+	if !found {
+		return ""
+	}
+	return token
+}
@@ -0,0 +1,101 @@
+package bidigrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/bidigrpc/bidistream"
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/centrifugal/protocol"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T, node *centrifuge.Node) bidistream.CentrifugoBidiStreamClient {
+	t.Helper()
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.ForceServerCodec(&RawCodec{}))
+	require.NoError(t, RegisterService(server, NewService(node, Config{
+		PingPong: configtypes.PingPong{
+			PingInterval: configtypes.Duration(25 * time.Second),
+			PongTimeout:  configtypes.Duration(8 * time.Second),
+		},
+	})))
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return bidistream.NewCentrifugoBidiStreamClient(conn)
+}
+
+func TestService_Communicate(t *testing.T) {
+	t.Parallel()
+	node, err := centrifuge.New(centrifuge.Config{})
+	require.NoError(t, err)
+	tokenCh := make(chan string, 1)
+	node.OnConnecting(func(ctx context.Context, event centrifuge.ConnectEvent) (centrifuge.ConnectReply, error) {
+		tokenCh <- event.Token
+		return centrifuge.ConnectReply{
+			Credentials: &centrifuge.Credentials{UserID: "42"},
+		}, nil
+	})
+	node.OnConnect(func(client *centrifuge.Client) {
+		client.OnRPC(func(event centrifuge.RPCEvent, callback centrifuge.RPCCallback) {
+			callback(centrifuge.RPCReply{Data: event.Data}, nil)
+		})
+	})
+	require.NoError(t, node.Run())
+	t.Cleanup(func() { _ = node.Shutdown(context.Background()) })
+
+	client := newTestClient(t, node)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer secret")
+
+	stream, err := client.Communicate(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(&protocol.Command{Id: 1, Connect: &protocol.ConnectRequest{}}))
+	reply, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), reply.Id)
+	require.NotNil(t, reply.Connect)
+	require.NotEmpty(t, reply.Connect.Client)
+	require.Equal(t, "secret", <-tokenCh)
+
+	require.NoError(t, stream.Send(&protocol.Command{Id: 2, Rpc: &protocol.RPCRequest{Data: []byte(`{"ok":true}`)}}))
+	reply, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), reply.Id)
+	require.NotNil(t, reply.Rpc)
+	require.Equal(t, `{"ok":true}`, string(reply.Rpc.Data))
+}
+
+func TestPingPongConfig(t *testing.T) {
+	require.Equal(t, centrifuge.PingPongConfig{
+		PingInterval: DefaultPingInterval,
+		PongTimeout:  DefaultPongTimeout,
+	}, pingPongConfig(configtypes.PingPong{}))
+
+	require.Equal(t, centrifuge.PingPongConfig{
+		PingInterval: -1,
+		PongTimeout:  time.Second,
+	}, pingPongConfig(configtypes.PingPong{
+		PingInterval: -1,
+		PongTimeout:  configtypes.Duration(time.Second),
+	}))
+}
@@ -0,0 +1,100 @@
+package bidigrpc
+
+import (
+	"sync"
+
+	"github.com/centrifugal/centrifugo/v6/internal/bidigrpc/bidistream"
+
+	"github.com/centrifugal/centrifuge"
+)
+
+// grpcTransport wraps a stream.
+type grpcTransport struct {
+	mu             sync.RWMutex
+	writeMu        sync.Mutex
+	stream         bidistream.CentrifugoBidiStream_CommunicateServer
+	closed         bool
+	closeCh        chan struct{}
+	pingPongConfig centrifuge.PingPongConfig
+}
+
+func newGRPCTransport(stream bidistream.CentrifugoBidiStream_CommunicateServer, pingPongConfig centrifuge.PingPongConfig) *grpcTransport {
+	return &grpcTransport{
+		stream:         stream,
+		closeCh:        make(chan struct{}),
+		pingPongConfig: pingPongConfig,
+	}
+}
+
+const transportName = "grpc"
+
+func (t *grpcTransport) Name() string {
+	return transportName
+}
+
+func (t *grpcTransport) AcceptProtocol() string {
+	return "h2" // Always for GRPC for now.
+}
+
+func (t *grpcTransport) Protocol() centrifuge.ProtocolType {
+	return centrifuge.ProtocolTypeProtobuf
+}
+
+// ProtocolVersion returns transport protocol version.
+func (t *grpcTransport) ProtocolVersion() centrifuge.ProtocolVersion {
+	return centrifuge.ProtocolVersion2
+}
+
+// Unidirectional returns whether transport is unidirectional.
+func (t *grpcTransport) Unidirectional() bool {
+	return false
+}
+
+// DisabledPushFlags ...
+func (t *grpcTransport) DisabledPushFlags() uint64 {
+	return 0
+}
+
+// PingPongConfig ...
+func (t *grpcTransport) PingPongConfig() centrifuge.PingPongConfig {
+	return t.pingPongConfig
+}
+
+// Emulation ...
+func (t *grpcTransport) Emulation() bool {
+	return false
+}
+
+func (t *grpcTransport) Write(message []byte) error {
+	return t.WriteMany(message)
+}
+
+func (t *grpcTransport) WriteMany(messages ...[]byte) error {
+	t.mu.RLock()
+	if t.closed {
+		t.mu.RUnlock()
+		return nil
+	}
+	t.mu.RUnlock()
+	// SendMsg must not be called on the same stream in different goroutines.
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	for i := 0; i < len(messages); i++ {
+		err := t.stream.SendMsg(rawFrame(messages[i]))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *grpcTransport) Close(_ centrifuge.Disconnect) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	close(t.closeCh)
+	return nil
+}
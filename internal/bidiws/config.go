@@ -0,0 +1,20 @@
+package bidiws
+
+import (
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+)
+
+// Defaults.
+const (
+	DefaultWebsocketWriteTimeout     = 1 * time.Second
+	DefaultWebsocketMessageSizeLimit = 65536 // 64KB
+)
+
+// DefaultWebsocketDecompressedMessageSizeLimitMultiplier is the default factor
+// applied to MessageSizeLimit to derive the maximum allowed decompressed message
+// size when compression is negotiated and DecompressedMessageSizeLimit is not set.
+const DefaultWebsocketDecompressedMessageSizeLimitMultiplier = 10
+
+type Config = configtypes.WebSocket
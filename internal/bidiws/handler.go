@@ -0,0 +1,185 @@
+package bidiws
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/logging"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+	"github.com/centrifugal/centrifugo/v6/internal/websocket"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/rs/zerolog/log"
+)
+
+// Handler handles bidirectional WebSocket client connections which negotiate
+// permessage-deflate context takeover, not supported by centrifuge.WebsocketHandler.
+// All other connections are served by wsHandler passed to NewHandler.
+type Handler struct {
+	node      *centrifuge.Node
+	wsHandler http.Handler
+	upgrade   *websocket.Upgrader
+	config    Config
+	pingPong  centrifuge.PingPongConfig
+}
+
+const protobufSubprotocol = "centrifuge-protobuf"
+
+// Clients may select a preset compression dictionary by namespace name.
+const dictionaryUrlParam = "cf_ws_dictionary"
+
+var writeBufferPool = &sync.Pool{}
+
+// NewHandler creates new Handler. wsHandler is usually centrifuge.WebsocketHandler
+// created with the same options.
+func NewHandler(
+	n *centrifuge.Node, c Config, wsHandler http.Handler, checkOrigin func(r *http.Request) bool, pingPong centrifuge.PingPongConfig,
+) *Handler {
+	upgrade := &websocket.Upgrader{
+		ReadBufferSize:    c.ReadBufferSize,
+		EnableCompression: c.Compression,
+		Subprotocols:      []string{protobufSubprotocol},
+		CheckOrigin:       checkOrigin,
+		CompressionObserver: func(uncompressed int, compressed int) {
+			metrics.ObserveWebSocketCompression(transportName, uncompressed, compressed)
+		},
+	}
+	if c.UseWriteBufferPool {
+		upgrade.WriteBufferPool = writeBufferPool
+	} else {
+		upgrade.WriteBufferSize = c.WriteBufferSize
+	}
+	if c.CompressionContext.Enabled {
+		upgrade.CompressionContextTakeover = true
+		upgrade.CompressionWindowBits = c.CompressionContext.WindowBits
+		if c.CompressionContext.MemoryLimit > 0 {
+			upgrade.CompressionWindowBudget = websocket.NewWindowBudget(int64(c.CompressionContext.MemoryLimit))
+		}
+	}
+	return &Handler{
+		node:      n,
+		wsHandler: wsHandler,
+		config:    c,
+		upgrade:   upgrade,
+		pingPong:  pingPong,
+	}
+}
+
+// SetCompressionDictionary sets a function to look up preset compression
+// dictionary by namespace name passed by client in URL.
+func (s *Handler) SetCompressionDictionary(dictionary func(namespace string) []byte) {
+	s.upgrade.CompressionDictionary = func(r *http.Request) []byte {
+		namespace := r.URL.Query().Get(dictionaryUrlParam)
+		if namespace == "" {
+			return nil
+		}
+		return dictionary(namespace)
+	}
+}
+
+func (s *Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if !s.upgrade.CompressionContextTakeoverOffered(r) {
+		s.wsHandler.ServeHTTP(rw, r)
+		return
+	}
+
+	conn, subprotocol, err := s.upgrade.Upgrade(rw, r, nil)
+	if err != nil {
+		log.Info().Err(err).Str("transport", transportName).Msg("websocket upgrade error")
+		return
+	}
+
+	protoType := centrifuge.ProtocolTypeJSON
+	if subprotocol == protobufSubprotocol || (r.URL.RawQuery != "" && r.URL.Query().Get("cf_protocol") == "protobuf") {
+		protoType = centrifuge.ProtocolTypeProtobuf
+	}
+
+	if s.config.Compression {
+		err := conn.SetCompressionLevel(s.config.CompressionLevel)
+		if err != nil {
+			log.Error().Err(err).Msg("websocket error setting compression level")
+		}
+	}
+
+	writeTimeout := s.config.WriteTimeout.ToDuration()
+	if writeTimeout == 0 {
+		writeTimeout = DefaultWebsocketWriteTimeout
+	}
+	messageSizeLimit := s.config.MessageSizeLimit
+	if messageSizeLimit == 0 {
+		messageSizeLimit = DefaultWebsocketMessageSizeLimit
+	}
+	if messageSizeLimit > 0 {
+		conn.SetReadLimit(int64(messageSizeLimit))
+	}
+	if s.config.Compression {
+		decompressedMessageSizeLimit := s.config.DecompressedMessageSizeLimit
+		if decompressedMessageSizeLimit == 0 {
+			decompressedMessageSizeLimit = messageSizeLimit * DefaultWebsocketDecompressedMessageSizeLimitMultiplier
+		}
+		if decompressedMessageSizeLimit > 0 {
+			conn.SetDecompressedReadLimit(int64(decompressedMessageSizeLimit))
+		}
+	}
+
+	// Record server-sent (outgoing) close codes for observability.
+	defer func() {
+		if code, incoming := conn.CloseCode(); code != 0 && !incoming {
+			s.node.IncTransportOutgoingClose(transportName, code)
+		}
+	}()
+
+	graceCh := make(chan struct{})
+	transport := newWebsocketTransport(conn, websocketTransportOptions{
+		protoType:          protoType,
+		writeTimeout:       writeTimeout,
+		compressionMinSize: s.config.CompressionMinSize,
+		pingPongConfig:     s.pingPong,
+		protoMajor:         r.ProtoMajor,
+	}, graceCh)
+
+	select {
+	case <-s.node.NotifyShutdown():
+		_ = transport.Close(centrifuge.DisconnectShutdown)
+		return
+	default:
+	}
+
+	c, closeFn, err := centrifuge.NewClient(r.Context(), s.node, transport)
+	if err != nil {
+		log.Error().Err(err).Str("transport", transportName).Msg("error creating client")
+		_ = conn.Close()
+		return
+	}
+	defer func() { _ = closeFn() }()
+
+	if logging.Enabled(logging.DebugLevel) {
+		log.Debug().Str("transport", transportName).Str("client", c.ID()).Msg("client connection established")
+		defer func(started time.Time) {
+			log.Debug().Str("transport", transportName).Str("client", c.ID()).
+				Str("duration", time.Since(started).String()).Msg("client connection completed")
+		}(time.Now())
+	}
+
+	for {
+		_, reader, err := conn.NextReader()
+		if err != nil {
+			break
+		}
+		if !centrifuge.HandleReadFrame(c, reader) {
+			break
+		}
+	}
+
+	// https://github.com/gorilla/websocket/issues/448
+	conn.SetPingHandler(nil)
+	conn.SetPongHandler(nil)
+	_ = conn.SetReadDeadline(time.Now().Add(closeFrameWait))
+	for {
+		if _, _, err := conn.NextReader(); err != nil {
+			close(graceCh)
+			break
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package bidiws
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+	"github.com/centrifugal/centrifugo/v6/internal/websocket"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/centrifugal/protocol"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	_ = metrics.Init(metrics.Config{
+		Registerer: prometheus.NewRegistry(),
+	})
+	os.Exit(m.Run())
+}
+
+func newTestServer(t *testing.T, config Config) string {
+	t.Helper()
+	node, err := centrifuge.New(centrifuge.Config{})
+	require.NoError(t, err)
+	node.OnConnecting(func(ctx context.Context, event centrifuge.ConnectEvent) (centrifuge.ConnectReply, error) {
+		return centrifuge.ConnectReply{
+			Credentials: &centrifuge.Credentials{UserID: "42"},
+			Subscriptions: map[string]centrifuge.SubscribeOptions{
+				"chat:index": {},
+			},
+		}, nil
+	})
+	require.NoError(t, node.Run())
+	t.Cleanup(func() { _ = node.Shutdown(context.Background()) })
+
+	checkOrigin := func(r *http.Request) bool { return true }
+	pingPong := centrifuge.PingPongConfig{
+		PingInterval: 5 * time.Second,
+		PongTimeout:  time.Second,
+	}
+	wsHandler := centrifuge.NewWebsocketHandler(node, centrifuge.WebsocketConfig{
+		Compression:    config.Compression,
+		CheckOrigin:    checkOrigin,
+		PingPongConfig: pingPong,
+	})
+	handler := NewHandler(node, config, wsHandler, checkOrigin, pingPong)
+	handler.SetCompressionDictionary(func(namespace string) []byte {
+		if namespace == "chat" {
+			return []byte(`{"push":{"channel":"chat:index","pub":{"data":{"text":""}}}}`)
+		}
+		return nil
+	})
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func readReply(t *testing.T, conn *websocket.Conn) *protocol.Reply {
+	t.Helper()
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	var reply protocol.Reply
+	require.NoError(t, json.Unmarshal(data, &reply))
+	return &reply
+}
+
+func TestHandler_Connect(t *testing.T) {
+	t.Parallel()
+	wsURL := newTestServer(t, Config{})
+
+	conn, _, _, err := (&websocket.Dialer{}).Dial(wsURL, nil)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"id":1,"connect":{}}`)))
+	reply := readReply(t, conn)
+	require.Equal(t, uint32(1), reply.Id)
+	require.NotNil(t, reply.Connect)
+	require.NotEmpty(t, reply.Connect.Client)
+}
+
+func TestHandler_BadCommand(t *testing.T) {
+	t.Parallel()
+	wsURL := newTestServer(t, Config{})
+
+	conn, _, _, err := (&websocket.Dialer{}).Dial(wsURL, nil)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`invalid`)))
+	_, _, err = conn.ReadMessage()
+	var closeErr *websocket.CloseError
+	require.ErrorAs(t, err, &closeErr)
+	require.Equal(t, int(centrifuge.DisconnectBadRequest.Code), closeErr.Code)
+}
+
+func TestHandler_CompressionContextTakeoverFallback(t *testing.T) {
+	t.Parallel()
+	wsURL := newTestServer(t, Config{
+		Compression: true,
+		CompressionContext: configtypes.WebSocketCompressionContext{
+			Enabled:    true,
+			WindowBits: 15,
+		},
+	})
+
+	// Dialer only supports compression without context takeover, server must
+	// accept such offer.
+	dialer := websocket.Dialer{EnableCompression: true}
+	conn, resp, _, err := dialer.Dial(wsURL+"?cf_ws_dictionary=chat", nil)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	require.Equal(t,
+		"permessage-deflate; server_no_context_takeover; client_no_context_takeover",
+		resp.Header.Get("Sec-Websocket-Extensions"),
+	)
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"id":1,"connect":{}}`)))
+	reply := readReply(t, conn)
+	require.NotNil(t, reply.Connect)
+	require.Contains(t, reply.Connect.Subs, "chat:index")
+}
+
+func TestHandler_CompressionContextTakeover(t *testing.T) {
+	t.Parallel()
+	wsURL := newTestServer(t, Config{
+		Compression: true,
+		CompressionContext: configtypes.WebSocketCompressionContext{
+			Enabled:    true,
+			WindowBits: 15,
+		},
+	})
+
+	// Offer with context takeover is accepted by Handler itself. Dialer does not
+	// support context takeover so it rejects the response, but the negotiated
+	// extension is visible in response headers.
+	header := http.Header{}
+	header.Set("Sec-Websocket-Extensions", "permessage-deflate")
+	_, resp, _, err := (&websocket.Dialer{}).Dial(wsURL, header)
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	require.Equal(t, "permessage-deflate", resp.Header.Get("Sec-Websocket-Extensions"))
+}
@@ -0,0 +1,178 @@
+package bidiws
+
+import (
+	"sync"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/timers"
+	"github.com/centrifugal/centrifugo/v6/internal/tools"
+	"github.com/centrifugal/centrifugo/v6/internal/websocket"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/centrifugal/protocol"
+)
+
+// Use the same name as WebSocket transport of centrifuge library, this is the
+// same transport from client's point of view.
+const transportName = "websocket"
+
+// websocketTransport is a wrapper struct over websocket connection to fit session
+// interface so client will accept it.
+type websocketTransport struct {
+	mu      sync.RWMutex
+	writeMu sync.Mutex
+	conn    *websocket.Conn
+	closeCh chan struct{}
+	graceCh chan struct{}
+	opts    websocketTransportOptions
+	closed  bool
+}
+
+type websocketTransportOptions struct {
+	protoType          centrifuge.ProtocolType
+	writeTimeout       time.Duration
+	compressionMinSize int
+	pingPongConfig     centrifuge.PingPongConfig
+	protoMajor         int
+}
+
+func newWebsocketTransport(conn *websocket.Conn, opts websocketTransportOptions, graceCh chan struct{}) *websocketTransport {
+	return &websocketTransport{
+		conn:    conn,
+		closeCh: make(chan struct{}),
+		graceCh: graceCh,
+		opts:    opts,
+	}
+}
+
+// Name returns name of transport.
+func (t *websocketTransport) Name() string {
+	return transportName
+}
+
+// Protocol returns transport protocol.
+func (t *websocketTransport) Protocol() centrifuge.ProtocolType {
+	return t.opts.protoType
+}
+
+// ProtocolVersion returns transport protocol version.
+func (t *websocketTransport) ProtocolVersion() centrifuge.ProtocolVersion {
+	return centrifuge.ProtocolVersion2
+}
+
+// Unidirectional returns whether transport is unidirectional.
+func (t *websocketTransport) Unidirectional() bool {
+	return false
+}
+
+// DisabledPushFlags disables disconnect push since disconnect is sent in close frame.
+func (t *websocketTransport) DisabledPushFlags() uint64 {
+	return centrifuge.PushFlagDisconnect
+}
+
+// PingPongConfig ...
+func (t *websocketTransport) PingPongConfig() centrifuge.PingPongConfig {
+	return t.opts.pingPongConfig
+}
+
+// Emulation ...
+func (t *websocketTransport) Emulation() bool {
+	return false
+}
+
+// AcceptProtocol ...
+func (t *websocketTransport) AcceptProtocol() string {
+	return tools.GetAcceptProtocolLabel(t.opts.protoMajor)
+}
+
+func (t *websocketTransport) writeData(data []byte) error {
+	if t.opts.compressionMinSize > 0 {
+		t.conn.EnableWriteCompression(len(data) > t.opts.compressionMinSize)
+	}
+	var messageType = websocket.TextMessage
+	if t.Protocol() == centrifuge.ProtocolTypeProtobuf {
+		messageType = websocket.BinaryMessage
+	}
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if t.opts.writeTimeout > 0 {
+		_ = t.conn.SetWriteDeadline(time.Now().Add(t.opts.writeTimeout))
+	}
+	err := t.conn.WriteMessage(messageType, data)
+	if err != nil {
+		return err
+	}
+	if t.opts.writeTimeout > 0 {
+		_ = t.conn.SetWriteDeadline(time.Time{})
+		if t.opts.protoMajor > 1 {
+			// For HTTP/2 connections expired deadlines on the underlying stream
+			// can't be extended, so it must be cleared explicitly.
+			return t.conn.NetConn().SetWriteDeadline(time.Time{})
+		}
+	}
+	return nil
+}
+
+// Write data to transport.
+func (t *websocketTransport) Write(message []byte) error {
+	return t.WriteMany(message)
+}
+
+// WriteMany data to transport.
+func (t *websocketTransport) WriteMany(messages ...[]byte) error {
+	select {
+	case <-t.closeCh:
+		return nil
+	default:
+		if len(messages) == 1 && t.Protocol() == centrifuge.ProtocolTypeJSON {
+			// Fast path for one JSON message.
+			return t.writeData(messages[0])
+		}
+		protoType := protocol.TypeJSON
+		if t.Protocol() == centrifuge.ProtocolTypeProtobuf {
+			protoType = protocol.TypeProtobuf
+		}
+		encoder := protocol.GetDataEncoder(protoType)
+		defer protocol.PutDataEncoder(protoType, encoder)
+		for i := range messages {
+			_ = encoder.Encode(messages[i])
+		}
+		return t.writeData(encoder.Finish())
+	}
+}
+
+const closeFrameWait = 5 * time.Second
+
+// Close closes transport.
+func (t *websocketTransport) Close(disconnect centrifuge.Disconnect) error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	close(t.closeCh)
+	t.mu.Unlock()
+
+	if disconnect.Code != centrifuge.DisconnectConnectionClosed.Code {
+		msg := websocket.FormatCloseMessage(int(disconnect.Code), disconnect.Reason)
+		err := t.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(t.opts.writeTimeout))
+		if err != nil {
+			return t.conn.Close()
+		}
+		select {
+		case <-t.graceCh:
+		default:
+			// Wait for closing handshake completion.
+			tm := timers.AcquireTimer(closeFrameWait)
+			select {
+			case <-t.graceCh:
+			case <-tm.C:
+			}
+			timers.ReleaseTimer(tm)
+		}
+		return t.conn.Close()
+	}
+	return t.conn.Close()
+}
@@ -0,0 +1,187 @@
+// Package capability implements channel capabilities – a list of channel patterns
+// with operations allowed in matching channels. Capabilities may be issued in a
+// connection JWT (caps claim) or in connect proxy result. They grant permissions
+// for the lifetime of connection so that no subscribe proxy round-trip or namespace
+// level permission options are required.
+package capability
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gobwas/glob"
+)
+
+// Operation is a channel operation which may be allowed by capability.
+type Operation string
+
+const (
+	// Subscribe allows subscribing to a channel.
+	Subscribe Operation = "sub"
+	// Publish allows publishing into a channel.
+	Publish Operation = "pub"
+	// Presence allows calling presence and presence stats of a channel.
+	Presence Operation = "prs"
+	// History allows calling history of a channel.
+	History Operation = "hst"
+	// MapPublish allows publishing into a map channel.
+	MapPublish Operation = "map_pub"
+	// MapRemove allows removing keys from a map channel.
+	MapRemove Operation = "map_rm"
+)
+
+// Operations is a set of allowed operations.
+type Operations uint8
+
+const (
+	opSubscribe Operations = 1 << iota
+	opPublish
+	opPresence
+	opHistory
+	opMapPublish
+	opMapRemove
+)
+
+var operationBits = map[Operation]Operations{
+	Subscribe:  opSubscribe,
+	Publish:    opPublish,
+	Presence:   opPresence,
+	History:    opHistory,
+	MapPublish: opMapPublish,
+	MapRemove:  opMapRemove,
+}
+
+// ParseOperations parses a list of operation names.
+func ParseOperations(allow []string) (Operations, error) {
+	var ops Operations
+	for _, name := range allow {
+		bit, ok := operationBits[Operation(name)]
+		if !ok {
+			return 0, fmt.Errorf("unknown operation %q", name)
+		}
+		ops |= bit
+	}
+	return ops, nil
+}
+
+// Has checks whether operation is in set.
+func (o Operations) Has(op Operation) bool {
+	return o&operationBits[op] != 0
+}
+
+// Match types of channel patterns.
+const (
+	// MatchExact matches channels equal to one of patterns. Used when match not set.
+	MatchExact = "exact"
+	// MatchPrefix matches channels starting with one of patterns.
+	MatchPrefix = "prefix"
+	// MatchGlob matches channels using glob patterns, ex. news:*.
+	MatchGlob = "glob"
+	// MatchRegex matches channels using regular expressions.
+	MatchRegex = "regex"
+)
+
+// ChannelsCapability allows operations in channels matching patterns.
+type ChannelsCapability struct {
+	// Channels is a list of channel patterns.
+	Channels []string `json:"channels"`
+	// Allow is a list of allowed operations.
+	Allow []string `json:"allow"`
+	// Match is a type of channel patterns: exact (default), prefix, glob or regex.
+	Match string `json:"match,omitempty"`
+}
+
+type rule struct {
+	ops      Operations
+	exact    map[string]struct{}
+	prefixes []string
+	globs    []glob.Glob
+	regexps  []*regexp.Regexp
+}
+
+func (r *rule) match(channel string) bool {
+	if _, ok := r.exact[channel]; ok {
+		return true
+	}
+	for _, prefix := range r.prefixes {
+		if strings.HasPrefix(channel, prefix) {
+			return true
+		}
+	}
+	for _, g := range r.globs {
+		if g.Match(channel) {
+			return true
+		}
+	}
+	for _, re := range r.regexps {
+		if re.MatchString(channel) {
+			return true
+		}
+	}
+	return false
+}
+
+// Checker checks operations against compiled capabilities. Nil Checker allows nothing.
+type Checker struct {
+	rules []rule
+}
+
+// Compile validates capabilities and compiles channel patterns. Returns nil Checker
+// for empty capabilities.
+func Compile(caps []ChannelsCapability) (*Checker, error) {
+	if len(caps) == 0 {
+		return nil, nil
+	}
+	rules := make([]rule, 0, len(caps))
+	for i, c := range caps {
+		ops, err := ParseOperations(c.Allow)
+		if err != nil {
+			return nil, fmt.Errorf("capability %d: %w", i, err)
+		}
+		r := rule{ops: ops}
+		switch c.Match {
+		case "", MatchExact:
+			r.exact = make(map[string]struct{}, len(c.Channels))
+			for _, ch := range c.Channels {
+				r.exact[ch] = struct{}{}
+			}
+		case MatchPrefix:
+			r.prefixes = c.Channels
+		case MatchGlob:
+			for _, pattern := range c.Channels {
+				g, err := glob.Compile(pattern)
+				if err != nil {
+					return nil, fmt.Errorf("capability %d: invalid glob pattern %q: %w", i, pattern, err)
+				}
+				r.globs = append(r.globs, g)
+			}
+		case MatchRegex:
+			for _, pattern := range c.Channels {
+				re, err := regexp.Compile(pattern)
+				if err != nil {
+					return nil, fmt.Errorf("capability %d: invalid regex pattern %q: %w", i, pattern, err)
+				}
+				r.regexps = append(r.regexps, re)
+			}
+		default:
+			return nil, fmt.Errorf("capability %d: unknown match type %q", i, c.Match)
+		}
+		rules = append(rules, r)
+	}
+	return &Checker{rules: rules}, nil
+}
+
+// Allowed checks whether operation is allowed in channel.
+func (c *Checker) Allowed(channel string, op Operation) bool {
+	if c == nil {
+		return false
+	}
+	bit := operationBits[op]
+	for i := range c.rules {
+		if c.rules[i].ops&bit != 0 && c.rules[i].match(channel) {
+			return true
+		}
+	}
+	return false
+}
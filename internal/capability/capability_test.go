@@ -0,0 +1,58 @@
+package capability
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker(t *testing.T) {
+	checker, err := Compile([]ChannelsCapability{
+		{Channels: []string{"news"}, Allow: []string{"sub", "hst"}},
+		{Channels: []string{"chat:"}, Allow: []string{"sub", "pub"}, Match: MatchPrefix},
+		{Channels: []string{"game:*"}, Allow: []string{"prs"}, Match: MatchGlob},
+		{Channels: []string{`^board:\d+$`}, Allow: []string{"map_pub", "map_rm"}, Match: MatchRegex},
+	})
+	require.NoError(t, err)
+
+	require.True(t, checker.Allowed("news", Subscribe))
+	require.True(t, checker.Allowed("news", History))
+	require.False(t, checker.Allowed("news", Publish))
+	require.False(t, checker.Allowed("news2", Subscribe))
+
+	require.True(t, checker.Allowed("chat:1", Publish))
+	require.False(t, checker.Allowed("xchat:1", Publish))
+
+	require.True(t, checker.Allowed("game:1", Presence))
+	require.False(t, checker.Allowed("game:1", Subscribe))
+
+	require.True(t, checker.Allowed("board:42", MapPublish))
+	require.True(t, checker.Allowed("board:42", MapRemove))
+	require.False(t, checker.Allowed("board:x", MapPublish))
+}
+
+func TestCheckerNil(t *testing.T) {
+	checker, err := Compile(nil)
+	require.NoError(t, err)
+	require.Nil(t, checker)
+	require.False(t, checker.Allowed("news", Subscribe))
+}
+
+func TestCompileErrors(t *testing.T) {
+	_, err := Compile([]ChannelsCapability{{Channels: []string{"news"}, Allow: []string{"unknown"}}})
+	require.ErrorContains(t, err, "unknown operation")
+	_, err = Compile([]ChannelsCapability{{Channels: []string{"news"}, Allow: []string{"sub"}, Match: "fuzzy"}})
+	require.ErrorContains(t, err, "unknown match type")
+	_, err = Compile([]ChannelsCapability{{Channels: []string{"("}, Allow: []string{"sub"}, Match: MatchRegex}})
+	require.ErrorContains(t, err, "invalid regex")
+	_, err = Compile([]ChannelsCapability{{Channels: []string{"[a"}, Allow: []string{"sub"}, Match: MatchGlob}})
+	require.ErrorContains(t, err, "invalid glob")
+}
+
+func TestParseOperations(t *testing.T) {
+	ops, err := ParseOperations([]string{"pub", "hst"})
+	require.NoError(t, err)
+	require.True(t, ops.Has(Publish))
+	require.True(t, ops.Has(History))
+	require.False(t, ops.Has(Presence))
+}
@@ -0,0 +1,38 @@
+package chstate
+
+import (
+	"github.com/centrifugal/centrifuge"
+)
+
+// Broker wraps centrifuge.Broker to notify Notifier about channels node subscribes
+// to and unsubscribes from. Node subscribes to broker channel when it gets the first
+// subscriber in it and unsubscribes when the last subscriber leaves.
+type Broker struct {
+	centrifuge.Broker
+	notifier *Notifier
+}
+
+// NewBroker creates Broker.
+func NewBroker(broker centrifuge.Broker, notifier *Notifier) *Broker {
+	return &Broker{
+		Broker:   broker,
+		notifier: notifier,
+	}
+}
+
+// Subscribe ...
+func (b *Broker) Subscribe(channels ...string) error {
+	if err := b.Broker.Subscribe(channels...); err != nil {
+		return err
+	}
+	b.notifier.Subscribed(channels...)
+	return nil
+}
+
+// Unsubscribe ...
+func (b *Broker) Unsubscribe(channels ...string) error {
+	err := b.Broker.Unsubscribe(channels...)
+	// Node forgets about channel subscribers even if broker returned an error.
+	b.notifier.Unsubscribed(channels...)
+	return err
+}
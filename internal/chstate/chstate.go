@@ -0,0 +1,323 @@
+// Package chstate tracks channel occupancy in a cluster and notifies application
+// backend over channel state proxy when channels become occupied or vacated.
+package chstate
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/config"
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+	"github.com/centrifugal/centrifugo/v6/internal/proxy"
+	"github.com/centrifugal/centrifugo/v6/internal/proxyproto"
+	"github.com/centrifugal/centrifugo/v6/internal/timers"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/rs/zerolog/log"
+)
+
+type Config = configtypes.ChannelStateEvents
+
+// Event types sent to proxy.
+const (
+	EventOccupied = "occupied"
+	EventVacated  = "vacated"
+)
+
+const (
+	// Changes of a channel are always processed by the same worker to keep order.
+	numWorkers     = 16
+	eventQueueSize = 4096
+	// Maximum time of a single store operation or proxy request.
+	operationTimeout = 10 * time.Second
+)
+
+type change struct {
+	channel  string
+	occupied bool
+}
+
+// Notifier receives changes of channel subscriptions on this node, deduplicates them
+// across nodes using Store and sends batches of channel events to proxies configured
+// for channel namespaces.
+type Notifier struct {
+	node         *centrifuge.Node
+	store        Store
+	cfgContainer *config.Container
+	handlers     map[string]*proxy.ChannelStateHandler
+	config       Config
+
+	workers []*changeQueue
+	events  map[string]chan *proxyproto.ChannelEvent
+
+	mu    sync.Mutex
+	local map[string]struct{}
+}
+
+// New creates Notifier. Handlers map contains channel state proxy handlers by proxy name.
+func New(node *centrifuge.Node, store Store, cfgContainer *config.Container, handlers map[string]*proxy.ChannelStateHandler, cfg Config) *Notifier {
+	n := &Notifier{
+		node:         node,
+		store:        store,
+		cfgContainer: cfgContainer,
+		handlers:     handlers,
+		config:       cfg,
+		workers:      make([]*changeQueue, numWorkers),
+		events:       make(map[string]chan *proxyproto.ChannelEvent, len(handlers)),
+		local:        make(map[string]struct{}),
+	}
+	for i := range n.workers {
+		n.workers[i] = newChangeQueue()
+	}
+	for name := range handlers {
+		n.events[name] = make(chan *proxyproto.ChannelEvent, eventQueueSize)
+	}
+	return n
+}
+
+// proxyName returns name of channel state proxy for channel, empty string if
+// channel state events are not enabled for channel.
+func (n *Notifier) proxyName(ch string) string {
+	_, _, chOpts, found, err := n.cfgContainer.ChannelOptions(ch)
+	if err != nil || !found || !chOpts.ChannelStateProxyEnabled {
+		return ""
+	}
+	if _, ok := n.handlers[chOpts.ChannelStateProxyName]; !ok {
+		return ""
+	}
+	return chOpts.ChannelStateProxyName
+}
+
+// Subscribed must be called when node got its first subscriber in channels.
+func (n *Notifier) Subscribed(channels ...string) {
+	n.enqueue(channels, true)
+}
+
+// Unsubscribed must be called when node lost its last subscriber in channels.
+func (n *Notifier) Unsubscribed(channels ...string) {
+	n.enqueue(channels, false)
+}
+
+func (n *Notifier) enqueue(channels []string, occupied bool) {
+	for _, ch := range channels {
+		if n.proxyName(ch) == "" {
+			continue
+		}
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(ch))
+		n.workers[h.Sum32()%numWorkers].push(change{channel: ch, occupied: occupied})
+	}
+}
+
+// changeQueue is an unbounded queue of channel changes. It keeps only the latest
+// not yet processed change of a channel, so its size is limited by the number of
+// channels on node, and pushing never blocks broker Subscribe/Unsubscribe calls.
+type changeQueue struct {
+	mu      sync.Mutex
+	order   []string
+	pending map[string]bool
+	notify  chan struct{}
+}
+
+func newChangeQueue() *changeQueue {
+	return &changeQueue{
+		pending: make(map[string]bool),
+		notify:  make(chan struct{}, 1),
+	}
+}
+
+func (q *changeQueue) push(c change) {
+	q.mu.Lock()
+	if _, ok := q.pending[c.channel]; ok {
+		// Only the final state matters: store deduplicates occupy and vacate calls.
+		metrics.IncChannelStateChangeCoalesced()
+	} else {
+		q.order = append(q.order, c.channel)
+	}
+	q.pending[c.channel] = c.occupied
+	q.mu.Unlock()
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (q *changeQueue) pop() (change, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.order) == 0 {
+		return change{}, false
+	}
+	ch := q.order[0]
+	q.order[0] = ""
+	q.order = q.order[1:]
+	if len(q.order) == 0 {
+		q.order = nil
+	}
+	occupied := q.pending[ch]
+	delete(q.pending, ch)
+	return change{channel: ch, occupied: occupied}, true
+}
+
+// Run processes subscription changes and sends channel events until ctx is done.
+// It also periodically refreshes channels occupied by this node in Store and picks
+// up channels vacated by crashed nodes.
+func (n *Notifier) Run(ctx context.Context) error {
+	for _, w := range n.workers {
+		go n.runWorker(ctx, w)
+	}
+	for name, events := range n.events {
+		go n.runSender(ctx, n.handlers[name], name, events)
+	}
+	interval := max(n.config.TTL.ToDuration()/3, time.Second)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			n.refresh(ctx)
+		}
+	}
+}
+
+func (n *Notifier) runWorker(ctx context.Context, changes *changeQueue) {
+	for {
+		for {
+			c, ok := changes.pop()
+			if !ok {
+				break
+			}
+			n.process(ctx, c)
+			if ctx.Err() != nil {
+				return
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-changes.notify:
+		}
+	}
+}
+
+func (n *Notifier) process(ctx context.Context, c change) {
+	ctx, cancel := context.WithTimeout(ctx, operationTimeout)
+	defer cancel()
+	if c.occupied {
+		n.mu.Lock()
+		n.local[c.channel] = struct{}{}
+		n.mu.Unlock()
+		occupied, err := n.store.Occupy(ctx, c.channel, n.node.ID(), n.config.TTL.ToDuration())
+		if err != nil {
+			log.Error().Err(err).Str("channel", c.channel).Msg("error occupying channel in channel state store")
+			return
+		}
+		if occupied {
+			n.emit(ctx, c.channel, EventOccupied)
+		}
+		return
+	}
+	n.mu.Lock()
+	delete(n.local, c.channel)
+	n.mu.Unlock()
+	vacated, err := n.store.Vacate(ctx, c.channel, n.node.ID())
+	if err != nil {
+		log.Error().Err(err).Str("channel", c.channel).Msg("error vacating channel in channel state store")
+		return
+	}
+	if vacated {
+		n.emit(ctx, c.channel, EventVacated)
+	}
+}
+
+func (n *Notifier) emit(ctx context.Context, ch string, eventType string) {
+	name := n.proxyName(ch)
+	if name == "" {
+		return
+	}
+	event := &proxyproto.ChannelEvent{
+		TimeMs:  time.Now().UnixMilli(),
+		Channel: ch,
+		Type:    eventType,
+	}
+	select {
+	case n.events[name] <- event:
+	case <-ctx.Done():
+		log.Error().Str("channel", ch).Str("type", eventType).Msg("channel state event dropped")
+	}
+}
+
+func (n *Notifier) runSender(ctx context.Context, handler *proxy.ChannelStateHandler, name string, events chan *proxyproto.ChannelEvent) {
+	batchSize := max(n.config.BatchSize, 1)
+	maxDelay := n.config.BatchMaxDelay.ToDuration()
+	for {
+		var batch []*proxyproto.ChannelEvent
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-events:
+			batch = append(batch, e)
+		}
+		if maxDelay > 0 {
+			tm := timers.AcquireTimer(maxDelay)
+		loop:
+			for len(batch) < batchSize {
+				select {
+				case e := <-events:
+					batch = append(batch, e)
+				case <-tm.C:
+					break loop
+				case <-ctx.Done():
+					break loop
+				}
+			}
+			timers.ReleaseTimer(tm)
+		}
+		n.send(ctx, handler, name, batch)
+	}
+}
+
+func (n *Notifier) send(ctx context.Context, handler *proxy.ChannelStateHandler, name string, batch []*proxyproto.ChannelEvent) {
+	// Events must be delivered even if Run context is done during shutdown.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), operationTimeout)
+	defer cancel()
+	if err := handler.Notify(ctx, batch); err != nil {
+		log.Error().Err(err).Str("proxy_name", name).Int("num_events", len(batch)).Msg("error sending channel state events")
+	}
+}
+
+func (n *Notifier) refresh(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, operationTimeout)
+	defer cancel()
+
+	n.mu.Lock()
+	channels := make([]string, 0, len(n.local))
+	for ch := range n.local {
+		channels = append(channels, ch)
+	}
+	n.mu.Unlock()
+
+	if len(channels) > 0 {
+		occupied, err := n.store.Refresh(ctx, channels, n.node.ID(), n.config.TTL.ToDuration())
+		if err != nil {
+			log.Error().Err(err).Msg("error refreshing channels in channel state store")
+		}
+		for _, ch := range occupied {
+			n.emit(ctx, ch, EventOccupied)
+		}
+	}
+
+	vacated, err := n.store.Expired(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("error getting expired channels from channel state store")
+		return
+	}
+	for _, ch := range vacated {
+		n.emit(ctx, ch, EventVacated)
+	}
+}
@@ -0,0 +1,154 @@
+package chstate
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/config"
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+	"github.com/centrifugal/centrifugo/v6/internal/proxy"
+	"github.com/centrifugal/centrifugo/v6/internal/proxyproto"
+	"github.com/centrifugal/centrifugo/v6/internal/tools"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	_ = metrics.Init(metrics.Config{
+		Registerer: prometheus.NewRegistry(),
+	})
+	os.Exit(m.Run())
+}
+
+type testChannelStateProxy struct {
+	mu     sync.Mutex
+	events []*proxyproto.ChannelEvent
+	sent   chan struct{}
+}
+
+func (p *testChannelStateProxy) NotifyChannelState(_ context.Context, req *proxyproto.NotifyChannelStateRequest) (*proxyproto.NotifyChannelStateResponse, error) {
+	p.mu.Lock()
+	p.events = append(p.events, req.Events...)
+	p.mu.Unlock()
+	p.sent <- struct{}{}
+	return &proxyproto.NotifyChannelStateResponse{Result: &proxyproto.NotifyChannelStateResult{}}, nil
+}
+
+func (p *testChannelStateProxy) Protocol() string {
+	return "test"
+}
+
+func (p *testChannelStateProxy) waitEvents(t *testing.T, n int) []*proxyproto.ChannelEvent {
+	t.Helper()
+	for {
+		p.mu.Lock()
+		if len(p.events) >= n {
+			events := p.events
+			p.mu.Unlock()
+			return events
+		}
+		p.mu.Unlock()
+		select {
+		case <-p.sent:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for channel state events")
+		}
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	occupied, err := store.Occupy(ctx, "test", "node", time.Minute)
+	require.NoError(t, err)
+	require.True(t, occupied)
+	occupied, err = store.Occupy(ctx, "test", "node", time.Minute)
+	require.NoError(t, err)
+	require.False(t, occupied)
+
+	vacated, err := store.Vacate(ctx, "test", "node")
+	require.NoError(t, err)
+	require.True(t, vacated)
+	vacated, err = store.Vacate(ctx, "test", "node")
+	require.NoError(t, err)
+	require.False(t, vacated)
+}
+
+func TestNotifier(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Channel.Namespaces = []configtypes.ChannelNamespace{
+		{
+			Name: "feeds",
+			ChannelOptions: configtypes.ChannelOptions{
+				ChannelStateProxyEnabled: true,
+				ChannelStateProxyName:    "default",
+			},
+		},
+		{
+			Name: "chat",
+		},
+	}
+	cfgContainer, err := config.NewContainer(cfg)
+	require.NoError(t, err)
+
+	node := tools.NodeWithMemoryEngineNoHandlers()
+	t.Cleanup(func() { _ = node.Shutdown(context.Background()) })
+
+	p := &testChannelStateProxy{sent: make(chan struct{}, 16)}
+	handlers := map[string]*proxy.ChannelStateHandler{
+		"default": proxy.NewChannelStateHandler(proxy.ChannelStateHandlerConfig{Proxy: p, Name: "default"}),
+	}
+	stateCfg := cfg.Channel.StateEvents
+	stateCfg.BatchMaxDelay = configtypes.Duration(10 * time.Millisecond)
+	notifier := New(node, NewMemoryStore(), cfgContainer, handlers, stateCfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() { _ = notifier.Run(ctx) }()
+
+	memoryBroker, err := centrifuge.NewMemoryBroker(node, centrifuge.MemoryBrokerConfig{})
+	require.NoError(t, err)
+	broker := NewBroker(memoryBroker, notifier)
+
+	require.NoError(t, broker.Subscribe("chat:index", "feeds:btc"))
+	events := p.waitEvents(t, 1)
+	require.Len(t, events, 1)
+	require.Equal(t, "feeds:btc", events[0].Channel)
+	require.Equal(t, EventOccupied, events[0].Type)
+	require.NotZero(t, events[0].TimeMs)
+
+	require.NoError(t, broker.Unsubscribe("feeds:btc", "chat:index"))
+	events = p.waitEvents(t, 2)
+	require.Len(t, events, 2)
+	require.Equal(t, "feeds:btc", events[1].Channel)
+	require.Equal(t, EventVacated, events[1].Type)
+}
+
+func TestChangeQueue(t *testing.T) {
+	q := newChangeQueue()
+	q.push(change{channel: "a", occupied: true})
+	q.push(change{channel: "b", occupied: true})
+	q.push(change{channel: "a", occupied: false})
+
+	c, ok := q.pop()
+	require.True(t, ok)
+	require.Equal(t, change{channel: "a", occupied: false}, c)
+	c, ok = q.pop()
+	require.True(t, ok)
+	require.Equal(t, change{channel: "b", occupied: true}, c)
+	_, ok = q.pop()
+	require.False(t, ok)
+
+	select {
+	case <-q.notify:
+	default:
+		t.Fatal("expected notification")
+	}
+}
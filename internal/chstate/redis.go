@@ -0,0 +1,230 @@
+package chstate
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/redisshard"
+
+	"github.com/redis/rueidis"
+)
+
+// RedisStore is a Store on top of Redis. Nodes of each channel are kept in a sorted
+// set scored by expiration time. Occupied channels are also kept in an index sorted
+// set scored by the latest expiration time of their nodes, presence of channel in
+// index means occupied event was sent. All keys of a shard share a hash tag so that
+// scripts work in Redis Cluster. Channels are distributed over shards by hash.
+type RedisStore struct {
+	shards []*redisshard.RedisShard
+	prefix string
+}
+
+var _ Store = (*RedisStore)(nil)
+
+// NewRedisStore creates RedisStore.
+func NewRedisStore(cfg configtypes.RedisPrefixed) (*RedisStore, error) {
+	shards, err := redisshard.BuildRedisShards(cfg.Redis)
+	if err != nil {
+		return nil, fmt.Errorf("error building Redis shards for channel state: %w", err)
+	}
+	if len(shards) == 0 {
+		return nil, fmt.Errorf("no Redis shards configured for channel state")
+	}
+	return &RedisStore{shards: shards, prefix: cfg.Prefix}, nil
+}
+
+// Maximum number of channels processed by one script call.
+const scriptBatchSize = 100
+
+var (
+	// KEYS[1] – channel nodes zset, KEYS[2] – index zset. ARGV[1] – current time in
+	// milliseconds, ARGV[2] – expiration time in milliseconds, ARGV[3] – TTL in
+	// milliseconds, ARGV[4] – node ID, ARGV[5] – channel.
+	occupyScript = rueidis.NewLuaScript(`
+redis.call("zremrangebyscore", KEYS[1], "-inf", ARGV[1])
+redis.call("zadd", KEYS[1], ARGV[2], ARGV[4])
+redis.call("pexpire", KEYS[1], ARGV[3])
+local score = redis.call("zscore", KEYS[2], ARGV[5])
+if score == false then
+  redis.call("zadd", KEYS[2], ARGV[2], ARGV[5])
+  return 1
+end
+if tonumber(score) < tonumber(ARGV[2]) then
+  redis.call("zadd", KEYS[2], ARGV[2], ARGV[5])
+end
+return 0
+`)
+	// KEYS[1] – channel nodes zset, KEYS[2] – index zset. ARGV[1] – current time in
+	// milliseconds, ARGV[2] – node ID, ARGV[3] – channel.
+	vacateScript = rueidis.NewLuaScript(`
+redis.call("zrem", KEYS[1], ARGV[2])
+redis.call("zremrangebyscore", KEYS[1], "-inf", ARGV[1])
+if redis.call("zcard", KEYS[1]) > 0 then
+  return 0
+end
+return redis.call("zrem", KEYS[2], ARGV[3])
+`)
+	// KEYS[1] – index zset, KEYS[2:] – channel nodes zsets. ARGV[1] – expiration time
+	// in milliseconds, ARGV[2] – TTL in milliseconds, ARGV[3] – node ID, ARGV[4:] – channels.
+	// Returns channels missing in index, i.e. considered vacant after node registration
+	// expired, these are occupied again.
+	refreshScript = rueidis.NewLuaScript(`
+local occupied = {}
+for i = 2, #KEYS do
+  local ch = ARGV[i + 2]
+  redis.call("zadd", KEYS[i], ARGV[1], ARGV[3])
+  redis.call("pexpire", KEYS[i], ARGV[2])
+  if redis.call("zscore", KEYS[1], ch) == false then
+    table.insert(occupied, ch)
+  end
+  redis.call("zadd", KEYS[1], ARGV[1], ch)
+end
+return occupied
+`)
+	// KEYS[1] – index zset. ARGV[1] – current time in milliseconds, ARGV[2] – channel
+	// nodes key prefix, ARGV[3] – limit.
+	expiredScript = rueidis.NewLuaScript(`
+local channels = redis.call("zrangebyscore", KEYS[1], "-inf", ARGV[1], "LIMIT", 0, ARGV[3])
+local vacated = {}
+for _, ch in ipairs(channels) do
+  local key = ARGV[2] .. ch
+  redis.call("zremrangebyscore", key, "-inf", ARGV[1])
+  local last = redis.call("zrange", key, -1, -1, "WITHSCORES")
+  if #last == 0 then
+    redis.call("zrem", KEYS[1], ch)
+    table.insert(vacated, ch)
+  else
+    redis.call("zadd", KEYS[1], last[2], ch)
+  end
+end
+return vacated
+`)
+)
+
+func (s *RedisStore) shardIndex(ch string) int {
+	if len(s.shards) == 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(ch))
+	return int(h.Sum32() % uint32(len(s.shards)))
+}
+
+func (s *RedisStore) hashTag() string {
+	return "{" + s.prefix + ".channel_state}"
+}
+
+func (s *RedisStore) indexKey() string {
+	return s.hashTag() + ".index"
+}
+
+func (s *RedisStore) channelKeyPrefix() string {
+	return s.hashTag() + ".nodes."
+}
+
+func (s *RedisStore) channelKey(ch string) string {
+	return s.channelKeyPrefix() + ch
+}
+
+// Occupy ...
+func (s *RedisStore) Occupy(ctx context.Context, ch string, nodeID string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	args := []string{
+		strconv.FormatInt(now.UnixMilli(), 10),
+		strconv.FormatInt(now.Add(ttl).UnixMilli(), 10),
+		strconv.FormatInt(ttl.Milliseconds(), 10),
+		nodeID,
+		ch,
+	}
+	res := s.shards[s.shardIndex(ch)].RunOp(func(client rueidis.Client) rueidis.RedisResult {
+		return occupyScript.Exec(ctx, client, []string{s.channelKey(ch), s.indexKey()}, args)
+	})
+	occupied, err := res.AsInt64()
+	if err != nil {
+		return false, err
+	}
+	return occupied == 1, nil
+}
+
+// Vacate ...
+func (s *RedisStore) Vacate(ctx context.Context, ch string, nodeID string) (bool, error) {
+	args := []string{
+		strconv.FormatInt(time.Now().UnixMilli(), 10),
+		nodeID,
+		ch,
+	}
+	res := s.shards[s.shardIndex(ch)].RunOp(func(client rueidis.Client) rueidis.RedisResult {
+		return vacateScript.Exec(ctx, client, []string{s.channelKey(ch), s.indexKey()}, args)
+	})
+	vacated, err := res.AsInt64()
+	if err != nil {
+		return false, err
+	}
+	return vacated == 1, nil
+}
+
+// Refresh ...
+func (s *RedisStore) Refresh(ctx context.Context, channels []string, nodeID string, ttl time.Duration) ([]string, error) {
+	var occupied []string
+	byShard := make([][]string, len(s.shards))
+	for _, ch := range channels {
+		i := s.shardIndex(ch)
+		byShard[i] = append(byShard[i], ch)
+	}
+	expireAt := strconv.FormatInt(time.Now().Add(ttl).UnixMilli(), 10)
+	ttlMs := strconv.FormatInt(ttl.Milliseconds(), 10)
+	for i, shardChannels := range byShard {
+		for start := 0; start < len(shardChannels); start += scriptBatchSize {
+			batch := shardChannels[start:min(start+scriptBatchSize, len(shardChannels))]
+			keys := make([]string, 0, len(batch)+1)
+			keys = append(keys, s.indexKey())
+			args := make([]string, 0, len(batch)+3)
+			args = append(args, expireAt, ttlMs, nodeID)
+			for _, ch := range batch {
+				keys = append(keys, s.channelKey(ch))
+				args = append(args, ch)
+			}
+			res := s.shards[i].RunOp(func(client rueidis.Client) rueidis.RedisResult {
+				return refreshScript.Exec(ctx, client, keys, args)
+			})
+			batchOccupied, err := res.AsStrSlice()
+			if err != nil {
+				return occupied, err
+			}
+			occupied = append(occupied, batchOccupied...)
+		}
+	}
+	return occupied, nil
+}
+
+// Expired ...
+func (s *RedisStore) Expired(ctx context.Context) ([]string, error) {
+	var vacated []string
+	args := []string{
+		strconv.FormatInt(time.Now().UnixMilli(), 10),
+		s.channelKeyPrefix(),
+		strconv.Itoa(scriptBatchSize),
+	}
+	for _, shard := range s.shards {
+		res := shard.RunOp(func(client rueidis.Client) rueidis.RedisResult {
+			return expiredScript.Exec(ctx, client, []string{s.indexKey()}, args)
+		})
+		channels, err := res.AsStrSlice()
+		if err != nil {
+			return nil, err
+		}
+		vacated = append(vacated, channels...)
+	}
+	return vacated, nil
+}
+
+// Close releases Redis shard connections.
+func (s *RedisStore) Close() {
+	for _, shard := range s.shards {
+		shard.Close()
+	}
+}
@@ -0,0 +1,70 @@
+package chstate
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Store keeps channel occupancy shared between nodes. A channel is occupied while
+// at least one node has subscribers in it.
+type Store interface {
+	// Occupy registers node having subscribers in channel. Returns true if channel
+	// became occupied, i.e. it was vacant in a cluster before.
+	Occupy(ctx context.Context, ch string, nodeID string, ttl time.Duration) (bool, error)
+	// Vacate unregisters node from channel. Returns true if channel became vacant,
+	// i.e. no other node has subscribers in it.
+	Vacate(ctx context.Context, ch string, nodeID string) (bool, error)
+	// Refresh prolongs node registration in channels. Returns channels which became
+	// occupied again, i.e. were reported by Expired since node registration expired.
+	Refresh(ctx context.Context, channels []string, nodeID string, ttl time.Duration) ([]string, error)
+	// Expired returns channels which became vacant since all nodes registered in
+	// them were not refreshed during ttl – e.g. crashed. Each channel is returned
+	// only once in a cluster.
+	Expired(ctx context.Context) ([]string, error)
+}
+
+// MemoryStore is a Store for a single node setup.
+type MemoryStore struct {
+	mu       sync.Mutex
+	channels map[string]struct{}
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{channels: make(map[string]struct{})}
+}
+
+// Occupy ...
+func (s *MemoryStore) Occupy(_ context.Context, ch string, _ string, _ time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.channels[ch]; ok {
+		return false, nil
+	}
+	s.channels[ch] = struct{}{}
+	return true, nil
+}
+
+// Vacate ...
+func (s *MemoryStore) Vacate(_ context.Context, ch string, _ string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.channels[ch]; !ok {
+		return false, nil
+	}
+	delete(s.channels, ch)
+	return true, nil
+}
+
+// Refresh ...
+func (s *MemoryStore) Refresh(_ context.Context, _ []string, _ string, _ time.Duration) ([]string, error) {
+	return nil, nil
+}
+
+// Expired ...
+func (s *MemoryStore) Expired(_ context.Context) ([]string, error) {
+	return nil, nil
+}
@@ -38,7 +38,7 @@ func checkConfig(cmd *cobra.Command, checkConfigFile string, strict bool) {
 	}
 	err = cfg.Validate()
 	if err != nil {
-		fmt.Printf("error validating config: %s\n", err)
+		fmt.Printf("error validating config: %s\n", cfgMeta.RedactSecrets(err.Error()))
 		os.Exit(1)
 	}
 	if strict && len(cfgMeta.UnknownKeys) > 0 {
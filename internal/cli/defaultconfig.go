@@ -56,6 +56,13 @@ func defaultConfig(configFile string, baseFile string, dryRun bool) {
 		os.Exit(1)
 	}
 
+	// Never write resolved secrets into generated config – keep references instead.
+	conf, _, err = config.GetConfigWithSecretReferences(nil, baseFile)
+	if err != nil {
+		fmt.Printf("error: %v\n", err)
+		os.Exit(1)
+	}
+
 	var b []byte
 
 	ext := filepath.Ext(configFile)
@@ -33,7 +33,7 @@ func DefaultEnv() *cobra.Command {
 }
 
 func defaultEnv(baseFile string, baseNonZeroOnly bool) {
-	conf, meta, err := config.GetConfig(nil, baseFile)
+	conf, _, err := config.GetConfig(nil, baseFile)
 	if err != nil {
 		fmt.Printf("error: %v\n", err)
 		os.Exit(1)
@@ -42,6 +42,12 @@ func defaultEnv(baseFile string, baseNonZeroOnly bool) {
 		fmt.Printf("error: %v\n", err)
 		os.Exit(1)
 	}
+	// Never print resolved secrets – keep references instead.
+	_, meta, err := config.GetConfigWithSecretReferences(nil, baseFile)
+	if err != nil {
+		fmt.Printf("error: %v\n", err)
+		os.Exit(1)
+	}
 	printSortedEnvVars(meta.KnownEnvVars, baseNonZeroOnly)
 }
 
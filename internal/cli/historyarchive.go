@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/config"
+	"github.com/centrifugal/centrifugo/v6/internal/historyarchive"
+
+	"github.com/spf13/cobra"
+)
+
+func HistoryArchive() *cobra.Command {
+	var historyArchiveCmd = &cobra.Command{
+		Use:   "historyarchive",
+		Short: "Read archived channel history",
+		Long:  `Read channel history archived to local disk or S3-compatible storage`,
+	}
+
+	var readConfigFile string
+	var readChannel string
+	var readFrom string
+	var readTo string
+	var readOutput string
+	var readCmd = &cobra.Command{
+		Use:   "read",
+		Short: "Read archived publications of a channel",
+		Long:  `Read archived publications of a channel within time range and write them as NDJSON records`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg, _, err := config.GetConfig(cmd, readConfigFile)
+			if err != nil {
+				fmt.Printf("error getting config: %v\n", err)
+				os.Exit(1)
+			}
+			if err := historyArchiveRead(cfg, readChannel, readFrom, readTo, readOutput); err != nil {
+				fmt.Printf("error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+	readCmd.Flags().StringVarP(&readConfigFile, "config", "c", "config.json", "path to config file with history_archive section")
+	readCmd.Flags().StringVar(&readChannel, "channel", "", "channel to read archived publications of")
+	readCmd.Flags().StringVar(&readFrom, "from", "", "start of time range (inclusive) in RFC3339 format")
+	readCmd.Flags().StringVar(&readTo, "to", "", "end of time range (exclusive) in RFC3339 format, current time if not set")
+	readCmd.Flags().StringVarP(&readOutput, "output", "o", "-", "path to output file, - for stdout")
+
+	historyArchiveCmd.AddCommand(readCmd)
+	return historyArchiveCmd
+}
+
+func historyArchiveRead(cfg config.Config, channel string, fromStr string, toStr string, output string) error {
+	if channel == "" {
+		return errors.New("provide channel to read with --channel")
+	}
+	if fromStr == "" {
+		return errors.New("provide start of time range with --from")
+	}
+	from, err := time.Parse(time.RFC3339, fromStr)
+	if err != nil {
+		return fmt.Errorf("invalid --from: %w", err)
+	}
+	to := time.Now()
+	if toStr != "" {
+		to, err = time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return fmt.Errorf("invalid --to: %w", err)
+		}
+	}
+	ctx := context.Background()
+	storage, err := historyarchive.NewStorage(ctx, cfg.HistoryArchive)
+	if err != nil {
+		return err
+	}
+	out := io.Writer(os.Stdout)
+	if output != "-" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		out = f
+	}
+	w := bufio.NewWriter(out)
+	encoder := json.NewEncoder(w)
+	var count int
+	err = historyarchive.NewReader(storage, cfg.HistoryArchive.Prefix).Read(ctx, channel, from, to, func(r historyarchive.Record) error {
+		count++
+		return encoder.Encode(r)
+	})
+	if err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if output != "-" {
+		fmt.Printf("read %d archived publications of %s\n", count, channel)
+	}
+	return nil
+}
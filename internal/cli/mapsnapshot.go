@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/apiproto"
+	"github.com/centrifugal/centrifugo/v6/internal/mapsnapshot"
+
+	"github.com/spf13/cobra"
+)
+
+type mapSnapshotAPIOptions struct {
+	endpoint string
+	key      string
+	timeout  time.Duration
+}
+
+func MapSnapshot() *cobra.Command {
+	var apiOpts mapSnapshotAPIOptions
+	var mapSnapshotCmd = &cobra.Command{
+		Use:   "mapsnapshot",
+		Short: "Export and import map channel snapshots",
+		Long:  `Export and import map channel snapshots (state and stream position) over server HTTP API`,
+	}
+	mapSnapshotCmd.PersistentFlags().StringVarP(&apiOpts.endpoint, "api", "a", "http://localhost:8000/api", "server HTTP API endpoint")
+	mapSnapshotCmd.PersistentFlags().StringVarP(&apiOpts.key, "api_key", "k", "", "server HTTP API key")
+	mapSnapshotCmd.PersistentFlags().DurationVarP(&apiOpts.timeout, "timeout", "t", time.Minute, "timeout of a single API request")
+
+	var exportChannels []string
+	var exportOutput string
+	var exportFormat string
+	var exportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "Export map channels to a snapshot file",
+		Long:  `Export map channels to a snapshot file`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := mapSnapshotExport(apiOpts, exportChannels, exportOutput, exportFormat); err != nil {
+				fmt.Printf("error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+	exportCmd.Flags().StringArrayVarP(&exportChannels, "channel", "c", nil, "map channel to export, can be repeated")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "-", "path to snapshot file, - for stdout")
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", mapsnapshot.FormatNDJSON, "snapshot file format: ndjson or protobuf")
+
+	var importInput string
+	var importFormat string
+	var importReplace bool
+	var importCmd = &cobra.Command{
+		Use:   "import",
+		Short: "Import map channels from a snapshot file",
+		Long:  `Import map channels from a snapshot file`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := mapSnapshotImport(apiOpts, importInput, importFormat, importReplace); err != nil {
+				fmt.Printf("error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+	importCmd.Flags().StringVarP(&importInput, "input", "i", "-", "path to snapshot file, - for stdin")
+	importCmd.Flags().StringVarP(&importFormat, "format", "f", mapsnapshot.FormatNDJSON, "snapshot file format: ndjson or protobuf")
+	importCmd.Flags().BoolVar(&importReplace, "replace", false, "replace data of channels which are not empty")
+
+	mapSnapshotCmd.AddCommand(exportCmd, importCmd)
+	return mapSnapshotCmd
+}
+
+func mapSnapshotExport(apiOpts mapSnapshotAPIOptions, channels []string, output string, format string) error {
+	if len(channels) == 0 {
+		return errors.New("provide at least one channel to export with --channel")
+	}
+	out := io.Writer(os.Stdout)
+	if output != "-" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		out = f
+	}
+	w, err := mapsnapshot.NewWriter(out, format)
+	if err != nil {
+		return err
+	}
+	for _, ch := range channels {
+		var resp apiproto.MapExportResponse
+		if err := callMapSnapshotAPI(apiOpts, "map_export", &apiproto.MapExportRequest{Channel: ch}, &resp); err != nil {
+			return fmt.Errorf("export %s: %w", ch, err)
+		}
+		if resp.Error != nil {
+			return fmt.Errorf("export %s: %w", ch, resp.Error)
+		}
+		if err := w.Write(resp.Result.GetSnapshot()); err != nil {
+			return err
+		}
+		if output != "-" {
+			fmt.Printf("exported %s: %d entries at offset %d, epoch %s\n", ch, len(resp.Result.GetSnapshot().GetEntries()), resp.Result.GetSnapshot().GetOffset(), resp.Result.GetSnapshot().GetEpoch())
+		}
+	}
+	return w.Flush()
+}
+
+func mapSnapshotImport(apiOpts mapSnapshotAPIOptions, input string, format string, replace bool) error {
+	in := io.Reader(os.Stdin)
+	if input != "-" {
+		f, err := os.Open(input)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		in = f
+	}
+	r, err := mapsnapshot.NewReader(in, format)
+	if err != nil {
+		return err
+	}
+	for {
+		snapshot, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var resp apiproto.MapImportResponse
+		req := &apiproto.MapImportRequest{Snapshot: snapshot, Replace: replace}
+		if err := callMapSnapshotAPI(apiOpts, "map_import", req, &resp); err != nil {
+			return fmt.Errorf("import %s: %w", snapshot.Channel, err)
+		}
+		if resp.Error != nil {
+			return fmt.Errorf("import %s: %w", snapshot.Channel, resp.Error)
+		}
+		result := resp.Result
+		if !result.GetPositionPreserved() {
+			fmt.Printf("imported %s: %d entries, stream position not preserved by map broker, new offset %d, epoch %s\n", snapshot.Channel, len(snapshot.Entries), result.GetOffset(), result.GetEpoch())
+		} else {
+			fmt.Printf("imported %s: %d entries at offset %d, epoch %s\n", snapshot.Channel, len(snapshot.Entries), result.GetOffset(), result.GetEpoch())
+		}
+	}
+}
+
+func callMapSnapshotAPI(apiOpts mapSnapshotAPIOptions, method string, req any, resp any) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(apiOpts.endpoint, "/")+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if apiOpts.key != "" {
+		httpReq.Header.Set("X-API-Key", apiOpts.key)
+	}
+	client := &http.Client{Timeout: apiOpts.timeout}
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+	data, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return err
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d: %s", httpResp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return json.Unmarshal(data, resp)
+}
@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/centrifugal/centrifugo/v6/internal/config"
+	"github.com/centrifugal/centrifugo/v6/internal/tools"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func MigrateConfig() *cobra.Command {
+	var configFile string
+	var outputFile string
+	var dryRun bool
+	var migrateConfigCmd = &cobra.Command{
+		Use:   "migrateconfig",
+		Short: "Migrate configuration file from older Centrifugo versions",
+		Long:  `Translate legacy flat configuration keys (Centrifugo v5 and earlier) to the current configuration structure`,
+		Run: func(cmd *cobra.Command, args []string) {
+			migrateConfig(configFile, outputFile, dryRun)
+		},
+	}
+	migrateConfigCmd.Flags().StringVarP(&configFile, "config", "c", "config.json", "path to config file to migrate")
+	migrateConfigCmd.Flags().StringVarP(&outputFile, "output", "o", "config_migrated.json", "path to migrated config file to generate, format is detected by extension")
+	migrateConfigCmd.Flags().BoolVarP(&dryRun, "dry-run", "d", false, "dry run mode (do not write file and just print migrated config to stdout)")
+	return migrateConfigCmd
+}
+
+func migrateConfig(configFile string, outputFile string, dryRun bool) {
+	if !dryRun {
+		exists, err := tools.PathExists(outputFile)
+		if err != nil {
+			fmt.Printf("error: %v\n", err)
+			os.Exit(1)
+		}
+		if exists {
+			fmt.Printf("error: target file already exists\n")
+			os.Exit(1)
+		}
+	}
+
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		fmt.Printf("error: %v\n", err)
+		os.Exit(1)
+	}
+	settings, err := decodeSettings(data, fileFormat(configFile))
+	if err != nil {
+		fmt.Printf("error decoding %s: %v\n", configFile, err)
+		os.Exit(1)
+	}
+
+	original := flattenSettings(settings)
+	warnings := config.MigrateSettings(settings)
+	if len(warnings) == 0 {
+		fmt.Println("no legacy configuration keys found")
+		return
+	}
+
+	b, err := encodeSettings(settings, fileFormat(outputFile))
+	if err != nil {
+		fmt.Printf("error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Diff goes to stderr in dry run mode to keep stdout a valid config.
+	diffOut := os.Stdout
+	if dryRun {
+		diffOut = os.Stderr
+	}
+	for _, w := range warnings {
+		_, _ = fmt.Fprintln(diffOut, w)
+	}
+	_, _ = fmt.Fprintln(diffOut)
+	_, _ = fmt.Fprint(diffOut, settingsDiff(original, flattenSettings(settings)))
+
+	if dryRun {
+		fmt.Println(string(b))
+		return
+	}
+
+	err = os.WriteFile(outputFile, b, 0600)
+	if err != nil {
+		fmt.Printf("error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("migrated config written to %s\n", outputFile)
+}
+
+func fileFormat(path string) string {
+	ext := filepath.Ext(path)
+	if len(ext) > 1 {
+		ext = ext[1:]
+	}
+	if ext == "yml" {
+		return "yaml"
+	}
+	return ext
+}
+
+var errUnsupportedFormat = errors.New("config file must have one of supported extensions: json, toml, yaml, yml")
+
+func decodeSettings(data []byte, format string) (map[string]any, error) {
+	settings := map[string]any{}
+	var err error
+	switch format {
+	case "json":
+		err = json.Unmarshal(data, &settings)
+	case "toml":
+		err = toml.Unmarshal(data, &settings)
+	case "yaml":
+		err = yaml.Unmarshal(data, &settings)
+	default:
+		err = errUnsupportedFormat
+	}
+	return settings, err
+}
+
+func encodeSettings(settings map[string]any, format string) ([]byte, error) {
+	switch format {
+	case "json":
+		return json.MarshalIndent(settings, "", "  ")
+	case "toml":
+		return toml.Marshal(settings)
+	case "yaml":
+		return yaml.Marshal(settings)
+	default:
+		return nil, errUnsupportedFormat
+	}
+}
+
+// flattenSettings converts nested settings to a map of dot-separated paths to
+// JSON encoded values. Arrays of objects are flattened with element indexes.
+func flattenSettings(settings map[string]any) map[string]string {
+	result := map[string]string{}
+	var walk func(prefix string, value any)
+	walk = func(prefix string, value any) {
+		switch v := value.(type) {
+		case map[string]any:
+			for key, item := range v {
+				path := key
+				if prefix != "" {
+					path = prefix + "." + key
+				}
+				walk(path, item)
+			}
+		case []any:
+			hasObjects := false
+			for _, item := range v {
+				if _, ok := item.(map[string]any); ok {
+					hasObjects = true
+					break
+				}
+			}
+			if !hasObjects {
+				b, _ := json.Marshal(v)
+				result[prefix] = string(b)
+				return
+			}
+			for i, item := range v {
+				walk(fmt.Sprintf("%s[%d]", prefix, i), item)
+			}
+		default:
+			b, _ := json.Marshal(v)
+			result[prefix] = string(b)
+		}
+	}
+	walk("", settings)
+	return result
+}
+
+// settingsDiff returns lines removed from and added to flattened settings.
+func settingsDiff(before map[string]string, after map[string]string) string {
+	var lines []string
+	for path, value := range before {
+		if afterValue, ok := after[path]; !ok || afterValue != value {
+			lines = append(lines, "- "+path+" = "+value)
+		}
+	}
+	for path, value := range after {
+		if beforeValue, ok := before[path]; !ok || beforeValue != value {
+			lines = append(lines, "+ "+path+" = "+value)
+		}
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i][2:] < lines[j][2:]
+	})
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
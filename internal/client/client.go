@@ -11,6 +11,7 @@ type Client interface {
 	ID() string
 	UserID() string
 	IsSubscribed(string) bool
+	Channels() []string
 	Context() context.Context
 	Transport() centrifuge.TransportInfo
 	AcquireStorage() (map[string]any, func(map[string]any))
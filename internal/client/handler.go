@@ -1,21 +1,32 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"sync"
 	"time"
 	"unicode"
 
+	"github.com/centrifugal/centrifugo/v6/internal/capability"
 	"github.com/centrifugal/centrifugo/v6/internal/clientcontext"
 	"github.com/centrifugal/centrifugo/v6/internal/clientstorage"
 	"github.com/centrifugal/centrifugo/v6/internal/config"
 	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/connquota"
+	"github.com/centrifugal/centrifugo/v6/internal/drain"
+	"github.com/centrifugal/centrifugo/v6/internal/historyquery"
 	"github.com/centrifugal/centrifugo/v6/internal/jwtverify"
 	"github.com/centrifugal/centrifugo/v6/internal/logging"
+	"github.com/centrifugal/centrifugo/v6/internal/mapfilter"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
 	"github.com/centrifugal/centrifugo/v6/internal/proxy"
+	"github.com/centrifugal/centrifugo/v6/internal/sharedpoll"
 	"github.com/centrifugal/centrifugo/v6/internal/subsource"
+	"github.com/centrifugal/centrifugo/v6/internal/tenant"
+	"github.com/centrifugal/centrifugo/v6/internal/throttle"
 
 	"github.com/centrifugal/centrifuge"
 	"github.com/rs/zerolog/log"
@@ -37,16 +48,37 @@ type ProxyMap struct {
 	MapPublishProxies        map[string]proxy.MapPublishProxy
 	MapRemoveProxies         map[string]proxy.MapRemoveProxy
 	SharedPollRefreshProxies map[string]*proxy.SharedPollRefreshHandler
+	ChannelStateProxies      map[string]*proxy.ChannelStateHandler
+	CacheEmptyProxies        map[string]*proxy.CacheEmptyHandler
+}
+
+// errSchemaViolation returned to clients when publication data does not match
+// JSON Schema configured for channel namespace. Uses the same code as server API.
+var errSchemaViolation = &centrifuge.Error{
+	Code:    114,
+	Message: "schema violation",
+}
+
+// ConnectTokenVerifier verifies connection tokens.
+type ConnectTokenVerifier interface {
+	VerifyConnectToken(token string, skipVerify bool) (jwtverify.ConnectToken, error)
 }
 
 // Handler for client connections.
 type Handler struct {
-	node             *centrifuge.Node
-	cfgContainer     *config.Container
-	tokenVerifier    *jwtverify.VerifierJWT
-	subTokenVerifier *jwtverify.VerifierJWT
-	proxyMap         *ProxyMap
-	rpcExtension     map[string]RPCExtensionFunc
+	node                 *centrifuge.Node
+	cfgContainer         *config.Container
+	tokenVerifier        *jwtverify.VerifierJWT
+	subTokenVerifier     *jwtverify.VerifierJWT
+	connectTokenVerifier ConnectTokenVerifier
+	proxyMap             *ProxyMap
+	rpcExtension         map[string]RPCExtensionFunc
+	throttler            *throttle.Throttler
+	sharedPoll           *sharedpoll.Coordinator
+	connQuota            *connquota.Quota
+	drainer              *drain.Drainer
+	tenants              *tenant.Registry
+	history              *historyquery.Reader
 
 	trackSigMu              sync.RWMutex
 	trackSigVerifier        *trackSignatureVerifier
@@ -63,7 +95,7 @@ func NewHandler(
 	subTokenVerifier *jwtverify.VerifierJWT,
 	proxyMap *ProxyMap,
 ) *Handler {
-	return &Handler{
+	h := &Handler{
 		node:             node,
 		cfgContainer:     cfgContainer,
 		tokenVerifier:    tokenVerifier,
@@ -71,6 +103,10 @@ func NewHandler(
 		proxyMap:         proxyMap,
 		rpcExtension:     make(map[string]RPCExtensionFunc),
 	}
+	if tokenVerifier != nil {
+		h.connectTokenVerifier = tokenVerifier
+	}
+	return h
 }
 
 // SetRPCExtension ...
@@ -78,6 +114,47 @@ func (h *Handler) SetRPCExtension(method string, handler RPCExtensionFunc) {
 	h.rpcExtension[method] = handler
 }
 
+// SetHistoryReader sets Reader used by OnHistoryRange.
+func (h *Handler) SetHistoryReader(r *historyquery.Reader) {
+	h.history = r
+}
+
+// SetThrottler sets Throttler used to coalesce client publications in namespaces
+// with throttle enabled.
+func (h *Handler) SetThrottler(t *throttle.Throttler) {
+	h.throttler = t
+}
+
+// SetConnectTokenVerifier replaces verifier used for connection tokens in connect and
+// refresh, e.g. with token introspection. Must be called before Setup.
+func (h *Handler) SetConnectTokenVerifier(v ConnectTokenVerifier) {
+	h.connectTokenVerifier = v
+}
+
+// SetSharedPollCoordinator sets Coordinator used for shared poll channels in namespaces
+// with coordinated option on. Must be called before Setup.
+func (h *Handler) SetSharedPollCoordinator(c *sharedpoll.Coordinator) {
+	h.sharedPoll = c
+}
+
+// SetConnectionQuota sets Quota used to enforce cluster-wide per-user and per-IP
+// connection quotas. Must be called before Setup.
+func (h *Handler) SetConnectionQuota(q *connquota.Quota) {
+	h.connQuota = q
+}
+
+// SetDrainer sets Drainer used to reject new connections while node is draining.
+// Must be called before Setup.
+func (h *Handler) SetDrainer(d *drain.Drainer) {
+	h.drainer = d
+}
+
+// SetTenants sets Registry used to verify tokens of tenant connections and to enforce
+// tenant limits. Must be called before Setup.
+func (h *Handler) SetTenants(r *tenant.Registry) {
+	h.tenants = r
+}
+
 // Setup event handlers.
 func (h *Handler) Setup() error {
 	var connectProxyHandler proxy.ConnectingHandlerFunc
@@ -158,6 +235,7 @@ func (h *Handler) Setup() error {
 			handlers[name] = handler.Handle(h.node)
 		}
 		cfgContainer := h.cfgContainer
+		coordinator := h.sharedPoll
 		h.node.OnSharedPoll(func(ctx context.Context, event centrifuge.SharedPollEvent) (centrifuge.SharedPollResult, error) {
 			_, _, chOpts, found, err := cfgContainer.ChannelOptions(event.Channel)
 			if err != nil || !found {
@@ -171,14 +249,65 @@ func (h *Handler) Setup() error {
 			if !ok {
 				return centrifuge.SharedPollResult{}, centrifuge.ErrorInternal
 			}
+			if chOpts.SharedPoll.Coordinated && coordinator != nil {
+				return coordinator.Handle(ctx, event, sharedpoll.ChannelOptions{
+					RefreshInterval:  chOpts.SharedPoll.RefreshInterval.ToDuration(),
+					RefreshBatchSize: chOpts.SharedPoll.RefreshBatchSize,
+				}, handler)
+			}
 			return handler(ctx, event)
 		})
 	}
 
+	// Wire cache empty proxy handlers with per-namespace dispatch.
+	if len(h.proxyMap.CacheEmptyProxies) > 0 {
+		handlers := make(map[string]proxy.CacheEmptyHandlerFunc, len(h.proxyMap.CacheEmptyProxies))
+		for name, handler := range h.proxyMap.CacheEmptyProxies {
+			handlers[name] = handler.Handle(h.node)
+		}
+		cfgContainer := h.cfgContainer
+		h.node.OnCacheEmpty(func(event centrifuge.CacheEmptyEvent) (centrifuge.CacheEmptyReply, error) {
+			_, _, chOpts, found, err := cfgContainer.ChannelOptions(event.Channel)
+			if err != nil || !found {
+				return centrifuge.CacheEmptyReply{}, centrifuge.ErrorInternal
+			}
+			if !chOpts.CacheEmptyProxyEnabled {
+				return centrifuge.CacheEmptyReply{}, nil
+			}
+			handler, ok := handlers[chOpts.CacheEmptyProxyName]
+			if !ok {
+				return centrifuge.CacheEmptyReply{}, centrifuge.ErrorInternal
+			}
+			return handler(event, chOpts)
+		})
+	}
+
 	cfg := h.cfgContainer.Config()
 	concurrency := cfg.Client.Concurrency
 
 	h.node.OnConnect(func(client *centrifuge.Client) {
+		var tenantName string
+		if h.tenants != nil {
+			tenantName = connectionTenant(client)
+			if tenantName != "" {
+				h.tenants.AddConnection(tenantName)
+			}
+		}
+		if h.connQuota != nil {
+			ip, _ := clientcontext.GetClientIPFromContext(client.Context())
+			h.connQuota.Register(client.Context(), client.ID(), client.UserID(), ip)
+		}
+		if tenantName != "" || h.connQuota != nil {
+			// Connection may have only one disconnect handler.
+			client.OnDisconnect(func(e centrifuge.DisconnectEvent) {
+				if tenantName != "" {
+					h.tenants.RemoveConnection(tenantName)
+				}
+				if h.connQuota != nil {
+					h.connQuota.Unregister(context.Background(), client.ID())
+				}
+			})
+		}
 
 		var semaphore chan struct{}
 		if concurrency > 1 {
@@ -278,6 +407,9 @@ func (h *Handler) Setup() error {
 		})
 
 		client.OnUnsubscribe(func(e centrifuge.UnsubscribeEvent) {
+			storage, release := client.AcquireStorage()
+			delete(storage, clientstorage.KeySubAllowPrefix+e.Channel)
+			release(storage)
 			if len(h.proxyMap.SubscribeStreamProxies) > 0 {
 				storage, release := client.AcquireStorage()
 				streamCancelKey := "stream_cancel_" + e.Channel
@@ -335,6 +467,11 @@ func (h *Handler) OnClientConnecting(
 	connectProxyHandler proxy.ConnectingHandlerFunc,
 	refreshProxyEnabled bool,
 ) (centrifuge.ConnectReply, error) {
+	if h.drainer != nil && h.drainer.Draining() {
+		metrics.IncDrainRejected()
+		return centrifuge.ConnectReply{}, h.drainer.Disconnect()
+	}
+
 	var (
 		credentials *centrifuge.Credentials
 		data        []byte
@@ -347,8 +484,22 @@ func (h *Handler) OnClientConnecting(
 
 	storage := map[string]any{}
 
+	var tenantName string
+	connectTokenVerifier := h.connectTokenVerifier
+	if h.tenants != nil {
+		tenantName, _ = clientcontext.GetTenantFromContext(ctx)
+		if tenantName != "" {
+			verifier, ok := h.tenants.Verifier(tenantName)
+			if !ok {
+				log.Info().Str("tenant", tenantName).Str("client", e.ClientID).Msg("unknown connection tenant")
+				return centrifuge.ConnectReply{}, centrifuge.DisconnectBadRequest
+			}
+			connectTokenVerifier = verifier
+		}
+	}
+
 	if e.Token != "" {
-		token, err := h.tokenVerifier.VerifyConnectToken(e.Token, cfg.Client.InsecureSkipTokenSignatureVerify)
+		token, err := connectTokenVerifier.VerifyConnectToken(e.Token, cfg.Client.InsecureSkipTokenSignatureVerify)
 		if err != nil {
 			if errors.Is(err, jwtverify.ErrTokenExpired) {
 				return centrifuge.ConnectReply{}, centrifuge.ErrorTokenExpired
@@ -383,6 +534,9 @@ func (h *Handler) OnClientConnecting(
 		if token.Meta != nil {
 			storage[clientstorage.KeyMeta] = token.Meta
 		}
+		if token.Caps != nil {
+			storage[clientstorage.KeyCaps] = token.Caps
+		}
 
 		processClientChannels = true
 	} else if connectProxyHandler != nil {
@@ -409,8 +563,60 @@ func (h *Handler) OnClientConnecting(
 		processClientChannels = true
 	}
 
+	if tenantName != "" && !h.tenants.ConnectionAllowed(tenantName) {
+		if logging.Enabled(logging.DebugLevel) {
+			log.Debug().Str("tenant", tenantName).Str("client", e.ClientID).Msg("connection rejected by tenant connection limit")
+		}
+		return centrifuge.ConnectReply{}, centrifuge.DisconnectConnectionLimit
+	}
+
+	// Check cluster-wide connection quotas before establishing connection.
+	if h.connQuota != nil {
+		var userID string
+		if credentials != nil {
+			userID = credentials.UserID
+		} else if cred, ok := centrifuge.GetCredentials(ctx); ok {
+			userID = cred.UserID
+		}
+		ip, _ := clientcontext.GetClientIPFromContext(ctx)
+		disconnect, err := h.connQuota.Check(ctx, e.ClientID, userID, ip)
+		if err != nil {
+			log.Error().Err(err).Str("user", userID).Str("client", e.ClientID).Msg("error checking connection quota in connecting")
+			return centrifuge.ConnectReply{}, centrifuge.DisconnectServerError
+		}
+		if disconnect != nil {
+			if logging.Enabled(logging.DebugLevel) {
+				log.Debug().Str("user", userID).Str("client", e.ClientID).Str("reason", disconnect.Reason).Msg("connection rejected by quota")
+			}
+			return centrifuge.ConnectReply{}, *disconnect
+		}
+	}
+
 	// Handle single connection enforcement before establishing connection.
-	if credentials != nil && cfg.Client.SubscribeToUserPersonalChannel.Enabled && cfg.Client.SubscribeToUserPersonalChannel.SingleConnection && credentials.UserID != "" {
+	if credentials != nil && cfg.Client.SubscribeToUserPersonalChannel.Enabled && cfg.Client.SubscribeToUserPersonalChannel.SingleConnection && credentials.UserID != "" && tenantName != "" {
+		// User IDs are not unique across tenants, so only connections found in tenant
+		// personal channel are disconnected.
+		personalChannel := h.cfgContainer.TenantChannel(tenantName, h.cfgContainer.PersonalChannel(credentials.UserID))
+		presence, err := h.node.Presence(personalChannel)
+		if err != nil {
+			log.Error().Err(err).Str("channel", personalChannel).Str("user", credentials.UserID).Str("client", e.ClientID).Msg("error calling presence in connecting")
+			return centrifuge.ConnectReply{}, centrifuge.DisconnectServerError
+		}
+		for clientID := range presence.Presence {
+			if clientID == e.ClientID {
+				continue
+			}
+			err = h.node.Disconnect(
+				credentials.UserID,
+				centrifuge.WithCustomDisconnect(centrifuge.DisconnectConnectionLimit),
+				centrifuge.WithDisconnectClient(clientID),
+			)
+			if err != nil {
+				log.Error().Err(err).Str("user", credentials.UserID).Str("client", e.ClientID).Msg("error disconnecting user in connecting")
+				return centrifuge.ConnectReply{}, centrifuge.DisconnectServerError
+			}
+		}
+	} else if credentials != nil && cfg.Client.SubscribeToUserPersonalChannel.Enabled && cfg.Client.SubscribeToUserPersonalChannel.SingleConnection && credentials.UserID != "" {
 		personalChannel := h.cfgContainer.PersonalChannel(credentials.UserID)
 		presenceStats, err := h.node.PresenceStats(personalChannel)
 		if err != nil {
@@ -433,6 +639,9 @@ func (h *Handler) OnClientConnecting(
 	// Automatically subscribe on personal server-side channel.
 	if credentials != nil && cfg.Client.SubscribeToUserPersonalChannel.Enabled && credentials.UserID != "" {
 		personalChannel := h.cfgContainer.PersonalChannel(credentials.UserID)
+		if tenantName != "" {
+			personalChannel = h.cfgContainer.TenantChannel(tenantName, personalChannel)
+		}
 		_, _, chOpts, found, err := h.cfgContainer.ChannelOptions(personalChannel)
 		if err != nil {
 			log.Error().Err(err).Str("channel", personalChannel).Msg("error getting personal channel options")
@@ -538,6 +747,18 @@ func (h *Handler) OnClientConnecting(
 		}
 	}
 
+	if h.tenants != nil {
+		// Connections never get server-side subscriptions to channels of other tenants.
+		for ch := range subscriptions {
+			if !h.tenantChannelAllowed(tenantName, ch) {
+				delete(subscriptions, ch)
+			}
+		}
+		if tenantName != "" {
+			storage[clientstorage.KeyTenant] = tenantName
+		}
+	}
+
 	finalReply := centrifuge.ConnectReply{
 		Storage:           storage,
 		Credentials:       credentials,
@@ -581,6 +802,29 @@ func setStorageMeta(c Client, meta json.RawMessage) {
 	storage[clientstorage.KeyMeta] = meta
 }
 
+func setStorageCaps(c Client, caps *capability.Checker) {
+	storage, release := c.AcquireStorage()
+	defer release(storage)
+	if caps == nil {
+		delete(storage, clientstorage.KeyCaps)
+		return
+	}
+	storage[clientstorage.KeyCaps] = caps
+}
+
+// capsAllowed checks whether operation in channel is allowed by connection capabilities
+// or, for subscribed channel, by operations allowed in subscription token.
+func capsAllowed(c Client, channel string, op capability.Operation, forceSubscribed bool) bool {
+	storage, release := c.AcquireStorage()
+	caps, _ := storage[clientstorage.KeyCaps].(*capability.Checker)
+	subAllow, _ := storage[clientstorage.KeySubAllowPrefix+channel].(capability.Operations)
+	release(storage)
+	if caps.Allowed(channel, op) {
+		return true
+	}
+	return subAllow.Has(op) && (forceSubscribed || c.IsSubscribed(channel))
+}
+
 // OnRefresh ...
 func (h *Handler) OnRefresh(c Client, e centrifuge.RefreshEvent, refreshProxyHandler proxy.RefreshHandlerFunc) (centrifuge.RefreshReply, RefreshExtra, error) {
 	if refreshProxyHandler != nil {
@@ -588,9 +832,20 @@ func (h *Handler) OnRefresh(c Client, e centrifuge.RefreshEvent, refreshProxyHan
 		if err == nil && extra.Meta != nil {
 			setStorageMeta(c, extra.Meta)
 		}
+		if err == nil && extra.Caps != nil {
+			setStorageCaps(c, extra.Caps)
+		}
 		return r, RefreshExtra{}, err
 	}
-	token, err := h.tokenVerifier.VerifyConnectToken(e.Token, h.cfgContainer.Config().Client.InsecureSkipTokenSignatureVerify)
+	connectTokenVerifier := h.connectTokenVerifier
+	if tenantName := connectionTenant(c); tenantName != "" {
+		verifier, ok := h.tenants.Verifier(tenantName)
+		if !ok {
+			return centrifuge.RefreshReply{}, RefreshExtra{}, centrifuge.DisconnectInvalidToken
+		}
+		connectTokenVerifier = verifier
+	}
+	token, err := connectTokenVerifier.VerifyConnectToken(e.Token, h.cfgContainer.Config().Client.InsecureSkipTokenSignatureVerify)
 	if err != nil {
 		if errors.Is(err, jwtverify.ErrTokenExpired) {
 			return centrifuge.RefreshReply{Expired: true}, RefreshExtra{}, nil
@@ -609,6 +864,8 @@ func (h *Handler) OnRefresh(c Client, e centrifuge.RefreshEvent, refreshProxyHan
 	if token.Meta != nil {
 		setStorageMeta(c, token.Meta)
 	}
+	// Refreshed token carries actual capabilities of connection.
+	setStorageCaps(c, token.Caps)
 	return centrifuge.RefreshReply{
 		ExpireAt: token.ExpireAt,
 		Info:     token.Info,
@@ -714,6 +971,10 @@ func (h *Handler) validChannelName(rest string, chOpts configtypes.ChannelOption
 }
 
 func (h *Handler) validateChannelName(c Client, rest string, chOpts configtypes.ChannelOptions, channel string) error {
+	if h.tenants != nil && !h.tenantChannelAllowed(connectionTenant(c), channel) {
+		log.Info().Str("channel", channel).Str("client", c.ID()).Str("user", c.UserID()).Msg("attempt to access channel of another tenant")
+		return centrifuge.ErrorPermissionDenied
+	}
 	ok, err := h.validChannelName(rest, chOpts, channel)
 	if err != nil {
 		log.Info().Err(err).Str("channel", channel).Str("client", c.ID()).Str("user", c.UserID()).Msg("error checking channel name")
@@ -726,6 +987,21 @@ func (h *Handler) validateChannelName(c Client, rest string, chOpts configtypes.
 	return nil
 }
 
+// connectionTenant returns tenant of connection, empty string for connections
+// outside of tenants.
+func connectionTenant(c Client) string {
+	storage, release := c.AcquireStorage()
+	tenantName, _ := storage[clientstorage.KeyTenant].(string)
+	release(storage)
+	return tenantName
+}
+
+// tenantChannelAllowed checks whether channel belongs to tenant, connections without
+// tenant may only use channels outside of tenants.
+func (h *Handler) tenantChannelAllowed(tenantName string, channel string) bool {
+	return h.cfgContainer.ChannelTenant(channel) == tenantName
+}
+
 type SubscribeExtra struct {
 }
 
@@ -751,6 +1027,13 @@ func (h *Handler) OnSubscribe(c Client, e centrifuge.SubscribeEvent, subscribePr
 		return centrifuge.SubscribeReply{}, SubscribeExtra{}, err
 	}
 
+	if h.tenants != nil {
+		if tenantName := connectionTenant(c); tenantName != "" && !h.tenants.ChannelAllowed(tenantName, len(c.Channels())) {
+			log.Info().Str("channel", e.Channel).Str("client", c.ID()).Str("user", c.UserID()).Str("tenant", tenantName).Msg("tenant channel limit reached")
+			return centrifuge.SubscribeReply{}, SubscribeExtra{}, centrifuge.ErrorLimitExceeded
+		}
+	}
+
 	if !isSubscriptionTypeAllowed(e.Type, chOpts.SubscriptionType) {
 		log.Info().Str("channel", e.Channel).Str("client", c.ID()).Str("user", c.UserID()).Msg("subscription type not allowed for namespace")
 		return centrifuge.SubscribeReply{}, SubscribeExtra{}, centrifuge.ErrorPermissionDenied
@@ -790,6 +1073,13 @@ func (h *Handler) OnSubscribe(c Client, e centrifuge.SubscribeEvent, subscribePr
 		if h.subTokenVerifier != nil {
 			tokenVerifier = h.subTokenVerifier
 		}
+		if tenantName := connectionTenant(c); tenantName != "" {
+			verifier, ok := h.tenants.Verifier(tenantName)
+			if !ok {
+				return centrifuge.SubscribeReply{}, SubscribeExtra{}, centrifuge.ErrorPermissionDenied
+			}
+			tokenVerifier = verifier
+		}
 		token, err := tokenVerifier.VerifySubscribeToken(e.Token, h.cfgContainer.Config().Client.InsecureSkipTokenSignatureVerify)
 		if err != nil {
 			if errors.Is(err, jwtverify.ErrTokenExpired) {
@@ -817,6 +1107,16 @@ func (h *Handler) OnSubscribe(c Client, e centrifuge.SubscribeEvent, subscribePr
 		options = token.Options
 		allowed = true
 		options.Source = subsource.SubscriptionToken
+		if token.Allow != 0 {
+			storage, release := c.AcquireStorage()
+			storage[clientstorage.KeySubAllowPrefix+e.Channel] = token.Allow
+			release(storage)
+		}
+	} else if !chOpts.SubscribeStreamProxyEnabled && capsAllowed(c, e.Channel, capability.Subscribe, false) {
+		// Capabilities are granted by backend at connect time, so no subscribe
+		// proxy call is required. Stream proxy channels still need a stream.
+		allowed = true
+		options.Source = subsource.ConnectionCap
 	} else if isUserLimitedChannel && h.cfgContainer.UserAllowed(e.Channel, c.UserID()) {
 		allowed = true
 		options.Source = subsource.UserLimited
@@ -897,7 +1197,7 @@ func (h *Handler) OnSubscribe(c Client, e centrifuge.SubscribeEvent, subscribePr
 func (h *Handler) OnPublish(c Client, e centrifuge.PublishEvent, publishProxyHandler proxy.PublishHandlerFunc) (centrifuge.PublishReply, error) {
 	cfg := h.cfgContainer.Config()
 
-	_, rest, chOpts, found, err := h.cfgContainer.ChannelOptions(e.Channel)
+	nsName, rest, chOpts, found, err := h.cfgContainer.ChannelOptions(e.Channel)
 	if err != nil {
 		log.Error().Err(err).Str("channel", e.Channel).Str("client", c.ID()).Str("user", c.UserID()).Msg("error getting channel options")
 		return centrifuge.PublishReply{}, err
@@ -915,10 +1215,26 @@ func (h *Handler) OnPublish(c Client, e centrifuge.PublishEvent, publishProxyHan
 		log.Info().Err(err).Str("channel", e.Channel).Str("client", c.ID()).Str("user", c.UserID()).Msg("publish data validation failed")
 		return centrifuge.PublishReply{}, centrifuge.ErrorBadRequest
 	}
+	if err := config.ValidatePublicationDataSchema(e.Data, chOpts); err != nil {
+		metrics.IncPublicationSchemaViolation(nsName, "client")
+		log.Info().Err(err).Str("channel", e.Channel).Str("client", c.ID()).Str("user", c.UserID()).Msg("publish data schema violation")
+		return centrifuge.PublishReply{}, errSchemaViolation
+	}
+
+	var tenantName string
+	if h.tenants != nil {
+		tenantName = h.cfgContainer.ChannelTenant(e.Channel)
+		if tenantName != "" && !h.tenants.PublishAllowed(tenantName) {
+			log.Info().Str("channel", e.Channel).Str("client", c.ID()).Str("user", c.UserID()).Str("tenant", tenantName).Msg("tenant publish rate limit reached")
+			return centrifuge.PublishReply{}, centrifuge.ErrorTooManyRequests
+		}
+	}
 
 	var allowed bool
 
-	if chOpts.PublishProxyEnabled {
+	if chOpts.PublishForCaps && capsAllowed(c, e.Channel, capability.Publish, false) {
+		allowed = true
+	} else if chOpts.PublishProxyEnabled {
 		if publishProxyHandler == nil {
 			log.Info().Str("channel", e.Channel).Str("client", c.ID()).Str("user", c.UserID()).Msg("publish proxy not enabled")
 			return centrifuge.PublishReply{}, centrifuge.ErrorNotAvailable
@@ -954,14 +1270,39 @@ func (h *Handler) OnPublish(c Client, e centrifuge.PublishEvent, publishProxyHan
 		return centrifuge.PublishReply{}, centrifuge.ErrorPermissionDenied
 	}
 
-	result, err := h.node.Publish(
-		e.Channel, e.Data,
-		centrifuge.WithClientInfo(e.ClientInfo),
-		centrifuge.WithHistory(chOpts.HistorySize, chOpts.HistoryTTL.ToDuration(), chOpts.HistoryMetaTTL.ToDuration()),
-		centrifuge.WithDelta(chOpts.DeltaPublish),
-	)
+	publish := func() (centrifuge.PublishResult, error) {
+		return h.node.Publish(
+			e.Channel, e.Data,
+			centrifuge.WithClientInfo(e.ClientInfo),
+			centrifuge.WithHistory(chOpts.HistorySize, chOpts.HistoryTTL.ToDuration(), chOpts.HistoryMetaTTL.ToDuration()),
+			centrifuge.WithDelta(chOpts.DeltaPublish),
+		)
+	}
+
+	if h.throttler != nil && chOpts.Throttle.Enabled {
+		// Publication may be deferred till the end of the throttle window, make sure
+		// data does not reference connection read buffers.
+		e.Data = bytes.Clone(e.Data)
+		// Client publications carry no tags, so coalescing with tag_key set happens per channel.
+		key := throttle.Key(e.Channel, chOpts.Throttle.TagKey, nil)
+		runNow, replaced := h.throttler.Submit(key, chOpts.Throttle.Interval.ToDuration(), func() {
+			if _, err := publish(); err != nil {
+				log.Error().Err(err).Str("channel", e.Channel).Str("client", c.ID()).Str("user", c.UserID()).Msg("error publishing throttled message")
+			}
+		})
+		if replaced {
+			metrics.IncThrottleCoalesced(nsName)
+		}
+		if !runNow {
+			return centrifuge.PublishReply{Result: &centrifuge.PublishResult{}}, nil
+		}
+	}
+
+	result, err := publish()
 	if err != nil {
 		log.Error().Err(err).Str("channel", e.Channel).Str("client", c.ID()).Str("user", c.UserID()).Msg("error publishing message")
+	} else if tenantName != "" {
+		metrics.IncTenantPublication(tenantName, "client")
 	}
 	return centrifuge.PublishReply{Result: &result}, err
 }
@@ -970,7 +1311,7 @@ func (h *Handler) OnPublish(c Client, e centrifuge.PublishEvent, publishProxyHan
 func (h *Handler) OnMapPublish(c Client, e centrifuge.MapPublishEvent, mapPublishProxyHandler proxy.MapPublishHandlerFunc) (centrifuge.MapPublishReply, error) {
 	cfg := h.cfgContainer.Config()
 
-	_, rest, chOpts, found, err := h.cfgContainer.ChannelOptions(e.Channel)
+	nsName, rest, chOpts, found, err := h.cfgContainer.ChannelOptions(e.Channel)
 	if err != nil {
 		log.Error().Err(err).Str("channel", e.Channel).Str("client", c.ID()).Str("user", c.UserID()).Msg("error getting channel options")
 		return centrifuge.MapPublishReply{}, err
@@ -982,8 +1323,17 @@ func (h *Handler) OnMapPublish(c Client, e centrifuge.MapPublishEvent, mapPublis
 	if err = h.validateChannelName(c, rest, chOpts, e.Channel); err != nil {
 		return centrifuge.MapPublishReply{}, err
 	}
+	if len(e.Data) > 0 {
+		if err := config.ValidatePublicationDataSchema(e.Data, chOpts); err != nil {
+			metrics.IncPublicationSchemaViolation(nsName, "client")
+			log.Info().Err(err).Str("channel", e.Channel).Str("client", c.ID()).Str("user", c.UserID()).Msg("map publish data schema violation")
+			return centrifuge.MapPublishReply{}, errSchemaViolation
+		}
+	}
 
-	if chOpts.Map.PublishProxyEnabled {
+	mapPublishCapsAllowed := capsAllowed(c, e.Channel, capability.MapPublish, false)
+
+	if chOpts.Map.PublishProxyEnabled && !mapPublishCapsAllowed {
 		if mapPublishProxyHandler == nil {
 			log.Info().Str("channel", e.Channel).Str("client", c.ID()).Str("user", c.UserID()).Msg("map publish proxy not enabled")
 			return centrifuge.MapPublishReply{}, centrifuge.ErrorNotAvailable
@@ -992,7 +1342,9 @@ func (h *Handler) OnMapPublish(c Client, e centrifuge.MapPublishEvent, mapPublis
 	}
 
 	var allowed bool
-	if chOpts.Map.AllowPublishForClient && (c.UserID() != "" || chOpts.Map.AllowPublishForAnonymous) {
+	if mapPublishCapsAllowed {
+		allowed = true
+	} else if chOpts.Map.AllowPublishForClient && (c.UserID() != "" || chOpts.Map.AllowPublishForAnonymous) {
 		allowed = true
 	} else if chOpts.Map.AllowPublishForSubscriber && c.IsSubscribed(e.Channel) && (c.UserID() != "" || chOpts.Map.AllowPublishForAnonymous) {
 		allowed = true
@@ -1028,9 +1380,26 @@ func (h *Handler) OnMapPublish(c Client, e centrifuge.MapPublishEvent, mapPublis
 		reply.Key = e.Key
 	}
 
+	setMapIndexTags(&reply, e, chOpts)
 	return reply, nil
 }
 
+// setMapIndexTags adds indexed field values of published data to publication tags when
+// map.index_tags is on, so that subscribers can filter map entries with tags filter.
+func setMapIndexTags(reply *centrifuge.MapPublishReply, e centrifuge.MapPublishEvent, chOpts configtypes.ChannelOptions) {
+	if !chOpts.Map.IndexTags {
+		return
+	}
+	data := reply.Options.Data
+	if len(data) == 0 {
+		data = e.Data
+	}
+	if len(data) == 0 {
+		return
+	}
+	reply.Options.Tags = mapfilter.Tags(chOpts.Map.Indexes.FilterIndexes(), data, reply.Options.Tags)
+}
+
 // OnMapRemove ...
 func (h *Handler) OnMapRemove(c Client, e centrifuge.MapRemoveEvent, mapRemoveProxyHandler proxy.MapRemoveHandlerFunc) (centrifuge.MapRemoveReply, error) {
 	cfg := h.cfgContainer.Config()
@@ -1048,7 +1417,9 @@ func (h *Handler) OnMapRemove(c Client, e centrifuge.MapRemoveEvent, mapRemovePr
 		return centrifuge.MapRemoveReply{}, err
 	}
 
-	if chOpts.Map.RemoveProxyEnabled {
+	mapRemoveCapsAllowed := capsAllowed(c, e.Channel, capability.MapRemove, false)
+
+	if chOpts.Map.RemoveProxyEnabled && !mapRemoveCapsAllowed {
 		if mapRemoveProxyHandler == nil {
 			log.Info().Str("channel", e.Channel).Str("client", c.ID()).Str("user", c.UserID()).Msg("map remove proxy not enabled")
 			return centrifuge.MapRemoveReply{}, centrifuge.ErrorNotAvailable
@@ -1057,7 +1428,9 @@ func (h *Handler) OnMapRemove(c Client, e centrifuge.MapRemoveEvent, mapRemovePr
 	}
 
 	var allowed bool
-	if chOpts.Map.AllowRemoveForClient && (c.UserID() != "" || chOpts.Map.AllowRemoveForAnonymous) {
+	if mapRemoveCapsAllowed {
+		allowed = true
+	} else if chOpts.Map.AllowRemoveForClient && (c.UserID() != "" || chOpts.Map.AllowRemoveForAnonymous) {
 		allowed = true
 	} else if chOpts.Map.AllowRemoveForSubscriber && c.IsSubscribed(e.Channel) && (c.UserID() != "" || chOpts.Map.AllowRemoveForAnonymous) {
 		allowed = true
@@ -1183,7 +1556,9 @@ func (h *Handler) getTrackSignatureVerifiers(cfg configtypes.SharedPoll) (*track
 }
 
 func (h *Handler) hasAccessToPresence(c Client, channel string, chOpts configtypes.ChannelOptions, forceSubscribed bool) bool {
-	if chOpts.PresenceForClient && (c.UserID() != "" || chOpts.PresenceForAnonymous) {
+	if capsAllowed(c, channel, capability.Presence, forceSubscribed) {
+		return true
+	} else if chOpts.PresenceForClient && (c.UserID() != "" || chOpts.PresenceForAnonymous) {
 		return true
 	} else if chOpts.PresenceForSubscriber && (forceSubscribed || c.IsSubscribed(channel)) && (c.UserID() != "" || chOpts.PresenceForAnonymous) {
 		return true
@@ -1250,7 +1625,9 @@ func (h *Handler) OnPresenceStats(c Client, e centrifuge.PresenceStatsEvent) (ce
 }
 
 func (h *Handler) hasAccessToHistory(c Client, channel string, chOpts configtypes.ChannelOptions, forceSubscribed bool) bool {
-	if chOpts.HistoryForClient && (c.UserID() != "" || chOpts.HistoryForAnonymous) {
+	if capsAllowed(c, channel, capability.History, forceSubscribed) {
+		return true
+	} else if chOpts.HistoryForClient && (c.UserID() != "" || chOpts.HistoryForAnonymous) {
 		return true
 	} else if chOpts.HistoryForSubscriber && (forceSubscribed || c.IsSubscribed(channel)) && (c.UserID() != "" || chOpts.HistoryForAnonymous) {
 		return true
@@ -1262,28 +1639,138 @@ func (h *Handler) hasAccessToHistory(c Client, channel string, chOpts configtype
 
 // OnHistory ...
 func (h *Handler) OnHistory(c Client, e centrifuge.HistoryEvent) (centrifuge.HistoryReply, error) {
-	_, rest, chOpts, found, err := h.cfgContainer.ChannelOptions(e.Channel)
-	if err != nil {
-		log.Error().Err(err).Str("channel", e.Channel).Str("client", c.ID()).Str("user", c.UserID()).Msg("error getting channel options")
+	if _, err := h.checkHistory(c, e.Channel); err != nil {
 		return centrifuge.HistoryReply{}, err
 	}
+	return centrifuge.HistoryReply{}, nil
+}
+
+// checkHistory checks whether client can access channel history and returns
+// channel options.
+func (h *Handler) checkHistory(c Client, channel string) (configtypes.ChannelOptions, error) {
+	_, rest, chOpts, found, err := h.cfgContainer.ChannelOptions(channel)
+	if err != nil {
+		log.Error().Err(err).Str("channel", channel).Str("client", c.ID()).Str("user", c.UserID()).Msg("error getting channel options")
+		return configtypes.ChannelOptions{}, err
+	}
 	if !found {
-		log.Info().Str("channel", e.Channel).Str("client", c.ID()).Str("user", c.UserID()).Msg("history for unknown channel")
-		return centrifuge.HistoryReply{}, centrifuge.ErrorUnknownChannel
+		log.Info().Str("channel", channel).Str("client", c.ID()).Str("user", c.UserID()).Msg("history for unknown channel")
+		return configtypes.ChannelOptions{}, centrifuge.ErrorUnknownChannel
 	}
-	if err = h.validateChannelName(c, rest, chOpts, e.Channel); err != nil {
-		return centrifuge.HistoryReply{}, err
+	if err = h.validateChannelName(c, rest, chOpts, channel); err != nil {
+		return configtypes.ChannelOptions{}, err
 	}
 	if chOpts.HistorySize <= 0 || chOpts.HistoryTTL <= 0 {
-		return centrifuge.HistoryReply{}, centrifuge.ErrorNotAvailable
+		return configtypes.ChannelOptions{}, centrifuge.ErrorNotAvailable
 	}
 
-	allowed := h.hasAccessToHistory(c, e.Channel, chOpts, false)
+	allowed := h.hasAccessToHistory(c, channel, chOpts, false)
 
 	if !allowed {
-		log.Info().Str("channel", e.Channel).Str("client", c.ID()).Str("user", c.UserID()).Msg("attempt to call history without sufficient permission")
-		return centrifuge.HistoryReply{}, centrifuge.ErrorPermissionDenied
+		log.Info().Str("channel", channel).Str("client", c.ID()).Str("user", c.UserID()).Msg("attempt to call history without sufficient permission")
+		return configtypes.ChannelOptions{}, centrifuge.ErrorPermissionDenied
 	}
 
-	return centrifuge.HistoryReply{}, nil
+	return chOpts, nil
+}
+
+// HistoryRangeRequest is a JSON data of history range RPC method.
+type HistoryRangeRequest struct {
+	Channel string `json:"channel"`
+	Limit   int    `json:"limit,omitempty"`
+	Reverse bool   `json:"reverse,omitempty"`
+	// Cursor returned in previous HistoryRangeResult to continue iteration.
+	Cursor string `json:"cursor,omitempty"`
+	// FromTime and ToTime are Unix milliseconds, FromTime is inclusive, ToTime
+	// is exclusive.
+	FromTime int64 `json:"from_time,omitempty"`
+	ToTime   int64 `json:"to_time,omitempty"`
+}
+
+// HistoryRangePublication is a publication in HistoryRangeResult. Data is set
+// for JSON payloads, B64Data for binary ones.
+type HistoryRangePublication struct {
+	Offset  uint64            `json:"offset"`
+	Data    json.RawMessage   `json:"data,omitempty"`
+	B64Data string            `json:"b64data,omitempty"`
+	Tags    map[string]string `json:"tags,omitempty"`
+	Time    int64             `json:"time,omitempty"`
+	User    string            `json:"user,omitempty"`
+	Client  string            `json:"client,omitempty"`
+}
+
+// HistoryRangeResult is a JSON data of history range RPC method reply.
+type HistoryRangeResult struct {
+	Publications []HistoryRangePublication `json:"publications"`
+	Offset       uint64                    `json:"offset"`
+	Epoch        string                    `json:"epoch"`
+	NextCursor   string                    `json:"next_cursor,omitempty"`
+}
+
+// OnHistoryRange is a built-in RPC method to query channel history by
+// publication time range. Client protocol history request has no time range
+// fields, so clients call this method instead. Access rules are the same as for
+// client history requests, limit is capped by client.history_max_publication_limit.
+func (h *Handler) OnHistoryRange(c Client, e centrifuge.RPCEvent) (centrifuge.RPCReply, error) {
+	if h.history == nil {
+		return centrifuge.RPCReply{}, centrifuge.ErrorNotAvailable
+	}
+	var req HistoryRangeRequest
+	if err := json.Unmarshal(e.Data, &req); err != nil || req.Channel == "" || req.FromTime < 0 || req.ToTime < 0 {
+		return centrifuge.RPCReply{}, centrifuge.ErrorBadRequest
+	}
+	since, err := historyquery.ParseCursor(req.Cursor)
+	if err != nil {
+		return centrifuge.RPCReply{}, centrifuge.ErrorBadRequest
+	}
+	chOpts, err := h.checkHistory(c, req.Channel)
+	if err != nil {
+		return centrifuge.RPCReply{}, err
+	}
+	limit := req.Limit
+	if maxLimit := h.cfgContainer.Config().Client.HistoryMaxPublicationLimit; maxLimit > 0 && (limit < 0 || limit > maxLimit) {
+		limit = maxLimit
+	}
+	res, err := h.history.History(req.Channel, historyquery.Query{
+		Since:   since,
+		Limit:   limit,
+		Reverse: req.Reverse,
+		Range:   historyquery.TimeRange{From: req.FromTime, To: req.ToTime},
+		MetaTTL: chOpts.HistoryMetaTTL.ToDuration(),
+	})
+	if err != nil {
+		if errors.Is(err, centrifuge.ErrorUnrecoverablePosition) {
+			return centrifuge.RPCReply{}, centrifuge.ErrorUnrecoverablePosition
+		}
+		log.Error().Err(err).Str("channel", req.Channel).Str("client", c.ID()).Str("user", c.UserID()).Msg("error getting history range")
+		return centrifuge.RPCReply{}, centrifuge.ErrorInternal
+	}
+	result := HistoryRangeResult{
+		Publications: make([]HistoryRangePublication, 0, len(res.Publications)),
+		Offset:       res.StreamPosition.Offset,
+		Epoch:        res.StreamPosition.Epoch,
+		NextCursor:   res.NextCursor,
+	}
+	for _, pub := range res.Publications {
+		p := HistoryRangePublication{
+			Offset: pub.Offset,
+			Tags:   pub.Tags,
+			Time:   pub.Time,
+		}
+		if json.Valid(pub.Data) {
+			p.Data = pub.Data
+		} else {
+			p.B64Data = base64.StdEncoding.EncodeToString(pub.Data)
+		}
+		if pub.Info != nil {
+			p.User = pub.Info.UserID
+			p.Client = pub.Info.ClientID
+		}
+		result.Publications = append(result.Publications, p)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return centrifuge.RPCReply{}, err
+	}
+	return centrifuge.RPCReply{Data: data}, nil
 }
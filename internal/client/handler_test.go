@@ -5,21 +5,37 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
+	"encoding/json"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/centrifugal/centrifugo/v6/internal/capability"
+	"github.com/centrifugal/centrifugo/v6/internal/clientstorage"
 	"github.com/centrifugal/centrifugo/v6/internal/config"
 	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/connquota"
+	"github.com/centrifugal/centrifugo/v6/internal/historyquery"
 	"github.com/centrifugal/centrifugo/v6/internal/jwtverify"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
 	"github.com/centrifugal/centrifugo/v6/internal/proxy"
+	"github.com/centrifugal/centrifugo/v6/internal/subsource"
 	"github.com/centrifugal/centrifugo/v6/internal/tools"
 
 	"github.com/centrifugal/centrifuge"
 	"github.com/centrifugal/protocol"
 	"github.com/cristalhq/jwt/v5"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 )
 
+func TestMain(m *testing.M) {
+	_ = metrics.Init(metrics.Config{
+		Registerer: prometheus.NewRegistry(),
+	})
+	os.Exit(m.Run())
+}
+
 func generateTestRSAKeys(t *testing.T) (*rsa.PrivateKey, *rsa.PublicKey) {
 	reader := rand.Reader
 	bitSize := 2048
@@ -619,6 +635,83 @@ func TestClientSubscribeChannelUserLimitedOK(t *testing.T) {
 	require.NoError(t, err)
 }
 
+type testConnectTokenVerifier struct {
+	token jwtverify.ConnectToken
+	err   error
+}
+
+func (v *testConnectTokenVerifier) VerifyConnectToken(_ string, _ bool) (jwtverify.ConnectToken, error) {
+	return v.token, v.err
+}
+
+func TestClientConnectingWithConnectTokenVerifier(t *testing.T) {
+	node := tools.NodeWithMemoryEngineNoHandlers()
+	defer func() { _ = node.Shutdown(context.Background()) }()
+
+	cfg := config.DefaultConfig()
+	cfgContainer, err := config.NewContainer(cfg)
+	require.NoError(t, err)
+	h := NewHandler(node, cfgContainer, hmacJWTVerifier(t, cfgContainer), nil, &ProxyMap{})
+	verifier := &testConnectTokenVerifier{token: jwtverify.ConnectToken{UserID: "42", Info: []byte(`{}`)}}
+	h.SetConnectTokenVerifier(verifier)
+
+	reply, err := h.OnClientConnecting(context.Background(), centrifuge.ConnectEvent{
+		Token: "opaque",
+	}, nil, false)
+	require.NoError(t, err)
+	require.Equal(t, "42", reply.Credentials.UserID)
+
+	verifier.err = jwtverify.ErrInvalidToken
+	_, err = h.OnClientConnecting(context.Background(), centrifuge.ConnectEvent{
+		Token: "opaque",
+	}, nil, false)
+	require.Equal(t, centrifuge.DisconnectInvalidToken, err)
+
+	verifier.err = jwtverify.ErrTokenExpired
+	_, err = h.OnClientConnecting(context.Background(), centrifuge.ConnectEvent{
+		Token: "opaque",
+	}, nil, false)
+	require.Equal(t, centrifuge.ErrorTokenExpired, err)
+}
+
+func TestClientConnectingConnectionQuota(t *testing.T) {
+	node := tools.NodeWithMemoryEngineNoHandlers()
+	defer func() { _ = node.Shutdown(context.Background()) }()
+
+	cfg := config.DefaultConfig()
+	cfg.Client.ConnectionQuota.UserLimit = 1
+	cfgContainer, err := config.NewContainer(cfg)
+	require.NoError(t, err)
+	h := NewHandler(node, cfgContainer, hmacJWTVerifier(t, cfgContainer), nil, &ProxyMap{})
+	presenceManager, err := centrifuge.NewMemoryPresenceManager(node, centrifuge.MemoryPresenceManagerConfig{})
+	require.NoError(t, err)
+	quota := connquota.New(node, connquota.NewPresenceStore(presenceManager), cfg.Client.ConnectionQuota)
+	h.SetConnectionQuota(quota)
+
+	token := getConnTokenHS("42", 0)
+
+	reply, err := h.OnClientConnecting(context.Background(), centrifuge.ConnectEvent{
+		ClientID: "c1",
+		Token:    token,
+	}, nil, false)
+	require.NoError(t, err)
+	require.Equal(t, "42", reply.Credentials.UserID)
+	quota.Register(context.Background(), "c1", "42", "")
+
+	_, err = h.OnClientConnecting(context.Background(), centrifuge.ConnectEvent{
+		ClientID: "c2",
+		Token:    token,
+	}, nil, false)
+	require.Equal(t, connquota.DisconnectUserQuota, err)
+
+	quota.Unregister(context.Background(), "c1")
+	_, err = h.OnClientConnecting(context.Background(), centrifuge.ConnectEvent{
+		ClientID: "c2",
+		Token:    token,
+	}, nil, false)
+	require.NoError(t, err)
+}
+
 func TestClientSubscribeWithToken(t *testing.T) {
 	node := tools.NodeWithMemoryEngineNoHandlers()
 	defer func() { _ = node.Shutdown(context.Background()) }()
@@ -1000,6 +1093,65 @@ func TestClientHistoryError(t *testing.T) {
 	require.Equal(t, centrifuge.ErrorPermissionDenied, err)
 }
 
+func TestClientHistoryRange(t *testing.T) {
+	node := tools.NodeWithMemoryEngineNoHandlers()
+	defer func() { _ = node.Shutdown(context.Background()) }()
+
+	cfg := config.DefaultConfig()
+	cfg.Channel.WithoutNamespace.HistorySize = 10
+	cfg.Channel.WithoutNamespace.HistoryTTL = configtypes.Duration(300 * time.Second)
+	cfgContainer, err := config.NewContainer(cfg)
+	require.NoError(t, err)
+	h := NewHandler(node, cfgContainer, hmacJWTVerifier(t, cfgContainer), nil, &ProxyMap{})
+
+	_, err = h.OnHistoryRange(&centrifuge.Client{}, centrifuge.RPCEvent{Data: []byte(`{"channel":"test1"}`)})
+	require.Equal(t, centrifuge.ErrorNotAvailable, err)
+
+	h.SetHistoryReader(historyquery.NewReader(node, nil))
+
+	for i := 0; i < 3; i++ {
+		_, err = node.Publish("test1", []byte(`{}`), centrifuge.WithHistory(10, 300*time.Second))
+		require.NoError(t, err)
+	}
+
+	for _, data := range []string{`{`, `{}`, `{"channel":"test1","from_time":-1}`, `{"channel":"test1","cursor":"x"}`} {
+		_, err = h.OnHistoryRange(&centrifuge.Client{}, centrifuge.RPCEvent{Data: []byte(data)})
+		require.Equal(t, centrifuge.ErrorBadRequest, err, data)
+	}
+
+	_, err = h.OnHistoryRange(&centrifuge.Client{}, centrifuge.RPCEvent{Data: []byte(`{"channel":"test1"}`)})
+	require.Equal(t, centrifuge.ErrorPermissionDenied, err)
+
+	cfg = cfgContainer.Config()
+	cfg.Client.Insecure = true
+	require.NoError(t, cfgContainer.Reload(cfg))
+
+	reply, err := h.OnHistoryRange(&centrifuge.Client{}, centrifuge.RPCEvent{Data: []byte(`{"channel":"test1","limit":2}`)})
+	require.NoError(t, err)
+	var result HistoryRangeResult
+	require.NoError(t, json.Unmarshal(reply.Data, &result))
+	require.Len(t, result.Publications, 2)
+	require.JSONEq(t, `{}`, string(result.Publications[0].Data))
+	require.Equal(t, uint64(3), result.Offset)
+	require.NotEmpty(t, result.NextCursor)
+
+	reply, err = h.OnHistoryRange(&centrifuge.Client{}, centrifuge.RPCEvent{Data: []byte(`{"channel":"test1","limit":2,"cursor":"` + result.NextCursor + `"}`)})
+	require.NoError(t, err)
+	result = HistoryRangeResult{}
+	require.NoError(t, json.Unmarshal(reply.Data, &result))
+	require.Len(t, result.Publications, 1)
+	require.Equal(t, uint64(3), result.Publications[0].Offset)
+	require.Empty(t, result.NextCursor)
+
+	// All publications are outside of time range.
+	reply, err = h.OnHistoryRange(&centrifuge.Client{}, centrifuge.RPCEvent{Data: []byte(`{"channel":"test1","limit":2,"from_time":1,"to_time":2}`)})
+	require.NoError(t, err)
+	result = HistoryRangeResult{}
+	require.NoError(t, json.Unmarshal(reply.Data, &result))
+	require.Empty(t, result.Publications)
+	require.Equal(t, uint64(3), result.Offset)
+}
+
 func TestClientPresence(t *testing.T) {
 	node := tools.NodeWithMemoryEngineNoHandlers()
 	defer func() { _ = node.Shutdown(context.Background()) }()
@@ -1209,6 +1361,158 @@ func TestClientOnSubscribe_UserLimitedChannelNotAllowedForAnotherUser(t *testing
 	require.ErrorIs(t, err, centrifuge.ErrorPermissionDenied)
 }
 
+func TestClientConnectionCaps(t *testing.T) {
+	node := tools.NodeWithMemoryEngineNoHandlers()
+	defer func() { _ = node.Shutdown(context.Background()) }()
+
+	cfg := config.DefaultConfig()
+	cfg.Channel.WithoutNamespace.Presence = true
+	cfg.Channel.WithoutNamespace.HistorySize = 10
+	cfg.Channel.WithoutNamespace.HistoryTTL = configtypes.Duration(300 * time.Second)
+	cfg.Channel.WithoutNamespace.PublishForCaps = true
+	cfgContainer, err := config.NewContainer(cfg)
+	require.NoError(t, err)
+
+	h := NewHandler(node, cfgContainer, nil, nil, &ProxyMap{})
+
+	caps, err := capability.Compile([]capability.ChannelsCapability{
+		{Channels: []string{"chat"}, Allow: []string{"sub", "pub", "prs"}, Match: capability.MatchPrefix},
+	})
+	require.NoError(t, err)
+
+	client := &tools.TestClientMock{
+		IDFunc: func() string {
+			return "1"
+		},
+		UserIDFunc: func() string {
+			return "42"
+		},
+		IsSubscribedFunc: func(string) bool {
+			return false
+		},
+	}
+	storage, release := client.AcquireStorage()
+	storage[clientstorage.KeyCaps] = caps
+	release(storage)
+
+	reply, _, err := h.OnSubscribe(client, centrifuge.SubscribeEvent{
+		Channel: "chat_1",
+	}, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, subsource.ConnectionCap, reply.Options.Source)
+
+	_, _, err = h.OnSubscribe(client, centrifuge.SubscribeEvent{
+		Channel: "news",
+	}, nil, nil)
+	require.ErrorIs(t, err, centrifuge.ErrorPermissionDenied)
+
+	_, err = h.OnPublish(client, centrifuge.PublishEvent{
+		Channel: "chat_1",
+		Data:    []byte(`{}`),
+	}, nil)
+	require.NoError(t, err)
+
+	_, err = h.OnPresence(client, centrifuge.PresenceEvent{
+		Channel: "chat_1",
+	})
+	require.NoError(t, err)
+
+	_, err = h.OnHistory(client, centrifuge.HistoryEvent{
+		Channel: "chat_1",
+	})
+	require.ErrorIs(t, err, centrifuge.ErrorPermissionDenied)
+}
+
+func TestClientConnectionCaps_PublishNotAllowedInNamespace(t *testing.T) {
+	node := tools.NodeWithMemoryEngineNoHandlers()
+	defer func() { _ = node.Shutdown(context.Background()) }()
+
+	cfgContainer, err := config.NewContainer(config.DefaultConfig())
+	require.NoError(t, err)
+
+	h := NewHandler(node, cfgContainer, nil, nil, &ProxyMap{})
+
+	caps, err := capability.Compile([]capability.ChannelsCapability{
+		{Channels: []string{"chat"}, Allow: []string{"pub"}},
+	})
+	require.NoError(t, err)
+
+	client := &tools.TestClientMock{
+		IDFunc: func() string {
+			return "1"
+		},
+		UserIDFunc: func() string {
+			return "42"
+		},
+		IsSubscribedFunc: func(string) bool {
+			return true
+		},
+	}
+	storage, release := client.AcquireStorage()
+	storage[clientstorage.KeyCaps] = caps
+	release(storage)
+
+	_, err = h.OnPublish(client, centrifuge.PublishEvent{
+		Channel: "chat",
+		Data:    []byte(`{}`),
+	}, nil)
+	require.ErrorIs(t, err, centrifuge.ErrorPermissionDenied)
+}
+
+func TestClientSubscribeTokenAllow(t *testing.T) {
+	node := tools.NodeWithMemoryEngineNoHandlers()
+	defer func() { _ = node.Shutdown(context.Background()) }()
+
+	cfg := config.DefaultConfig()
+	cfg.Channel.WithoutNamespace.PublishForCaps = true
+	cfgContainer, err := config.NewContainer(cfg)
+	require.NoError(t, err)
+
+	h := NewHandler(node, cfgContainer, hmacJWTVerifier(t, cfgContainer), nil, &ProxyMap{})
+
+	builder := getTokenBuilder(nil, "secret")
+	token, err := builder.Build(&jwtverify.SubscribeTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: "12",
+		},
+		Channel: "$chat",
+		Allow:   []string{"pub"},
+	})
+	require.NoError(t, err)
+
+	subscribed := false
+	client := &tools.TestClientMock{
+		IDFunc: func() string {
+			return "1"
+		},
+		UserIDFunc: func() string {
+			return "12"
+		},
+		IsSubscribedFunc: func(string) bool {
+			return subscribed
+		},
+	}
+
+	_, _, err = h.OnSubscribe(client, centrifuge.SubscribeEvent{
+		Channel: "$chat",
+		Token:   token.String(),
+	}, nil, nil)
+	require.NoError(t, err)
+
+	_, err = h.OnPublish(client, centrifuge.PublishEvent{
+		Channel: "$chat",
+		Data:    []byte(`{}`),
+	}, nil)
+	require.ErrorIs(t, err, centrifuge.ErrorPermissionDenied)
+
+	subscribed = true
+	_, err = h.OnPublish(client, centrifuge.PublishEvent{
+		Channel: "$chat",
+		Data:    []byte(`{}`),
+	}, nil)
+	require.NoError(t, err)
+}
+
 func TestClientOnSubscribe_SubRefreshProxy(t *testing.T) {
 	node := tools.NodeWithMemoryEngineNoHandlers()
 	defer func() { _ = node.Shutdown(context.Background()) }()
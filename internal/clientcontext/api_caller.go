@@ -0,0 +1,30 @@
+package clientcontext
+
+import (
+	"context"
+)
+
+// APICaller describes who called server API command.
+type APICaller struct {
+	// Source of command, like http_api, grpc_api, consuming or admin.
+	Source string
+	// Identity of caller: api_key, api_key:{name} for named API keys, tenant:{name}
+	// for tenant API keys, consumer name or admin identity.
+	Identity string
+}
+
+type apiCallerKey struct{}
+
+// GetAPICallerFromContext returns API caller from context.
+func GetAPICallerFromContext(ctx context.Context) (APICaller, bool) {
+	if val := ctx.Value(apiCallerKey{}); val != nil {
+		caller, ok := val.(APICaller)
+		return caller, ok
+	}
+	return APICaller{}, false
+}
+
+// SetAPICallerToContext sets API caller to context.
+func SetAPICallerToContext(ctx context.Context, caller APICaller) context.Context {
+	return context.WithValue(ctx, apiCallerKey{}, caller)
+}
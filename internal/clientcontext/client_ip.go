@@ -0,0 +1,24 @@
+package clientcontext
+
+import (
+	"context"
+)
+
+type clientIPKey struct{}
+
+// GetClientIPFromContext returns client IP address from context.
+func GetClientIPFromContext(ctx context.Context) (string, bool) {
+	if val := ctx.Value(clientIPKey{}); val != nil {
+		ip, ok := val.(string)
+		return ip, ok
+	}
+	return "", false
+}
+
+// SetClientIPToContext sets client IP address to context.
+func SetClientIPToContext(ctx context.Context, ip string) context.Context {
+	if ip == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
@@ -0,0 +1,24 @@
+package clientcontext
+
+import (
+	"context"
+)
+
+type tenantKey struct{}
+
+// GetTenantFromContext returns tenant name from context.
+func GetTenantFromContext(ctx context.Context) (string, bool) {
+	if val := ctx.Value(tenantKey{}); val != nil {
+		tenant, ok := val.(string)
+		return tenant, ok
+	}
+	return "", false
+}
+
+// SetTenantToContext sets tenant name to context.
+func SetTenantToContext(ctx context.Context, tenant string) context.Context {
+	if tenant == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
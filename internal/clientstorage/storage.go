@@ -2,4 +2,11 @@ package clientstorage
 
 const (
 	KeyMeta = "meta"
+	// KeyCaps keeps connection channel capabilities (*capability.Checker).
+	KeyCaps = "caps"
+	// KeySubAllowPrefix is a prefix of keys keeping operations allowed in a
+	// channel by subscription token (capability.Operations).
+	KeySubAllowPrefix = "sub_allow_"
+	// KeyTenant keeps name of connection tenant (string).
+	KeyTenant = "tenant"
 )
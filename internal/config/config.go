@@ -63,6 +63,11 @@ type Config struct {
 	// in different channel namespaces.
 	Proxies configtypes.NamedProxies `mapstructure:"proxies" default:"[]" json:"proxies" envconfig:"proxies" yaml:"proxies" toml:"proxies" doc:"Defines named proxies that namespaces can reference to forward channel-related events to your backend."`
 
+	// Tenancy is a configuration of multi-tenant isolation.
+	Tenancy configtypes.Tenancy `mapstructure:"tenancy" json:"tenancy" envconfig:"tenancy" toml:"tenancy" yaml:"tenancy" doc:"Configures multi-tenancy: how tenant is resolved for connections and how tenant channels are named."`
+	// Tenants is an array of tenants – isolated applications hosted on the same Centrifugo cluster.
+	Tenants configtypes.Tenants `mapstructure:"tenants" default:"[]" json:"tenants" envconfig:"tenants" yaml:"tenants" toml:"tenants" doc:"Defines tenants with their own token verification keys, API key, channel namespaces and limits. Used when <<tenancy.enabled>> is on."`
+
 	// HttpAPI is a configuration for HTTP server API. It's enabled by default.
 	HttpAPI configtypes.HttpAPI `mapstructure:"http_api" json:"http_api" envconfig:"http_api" toml:"http_api" yaml:"http_api" doc:"Configures the server HTTP API (enabled by default): API key, insecure mode, and whether it's exposed on the external port."`
 	// GrpcAPI is a configuration for gRPC server API. It's disabled by default.
@@ -72,12 +77,24 @@ type Config struct {
 	// messages from PostgreSQL transactional outbox table, or from Kafka topics.
 	Consumers configtypes.Consumers `mapstructure:"consumers" default:"[]" json:"consumers" envconfig:"consumers" toml:"consumers" yaml:"consumers" doc:"Defines message queue consumers that ingest commands from external sources such as a PostgreSQL outbox table or Kafka topics."`
 
+	// Audit is a configuration of audit log for server API commands and admin actions.
+	Audit configtypes.Audit `mapstructure:"audit" json:"audit" envconfig:"audit" toml:"audit" yaml:"audit" doc:"Configures audit log recording who called server API commands and admin actions, with method filters, params redaction and file, PostgreSQL or Redis stream sinks."`
+
+	// ScheduledPublications is a configuration of durable scheduler for publications with
+	// publish_at or delay set.
+	ScheduledPublications configtypes.ScheduledPublications `mapstructure:"scheduled_publications" json:"scheduled_publications" envconfig:"scheduled_publications" toml:"scheduled_publications" yaml:"scheduled_publications" doc:"Configures scheduled and delayed publications fired exactly once across the cluster, with the scheduler state kept in Redis or in Postgres stream broker tables."`
+
+	// HistoryArchive is a configuration of long-term history archiving.
+	HistoryArchive configtypes.HistoryArchive `mapstructure:"history_archive" json:"history_archive" envconfig:"history_archive" toml:"history_archive" yaml:"history_archive" doc:"Configures long-term archiving of channel history to compressed NDJSON files on local disk or in S3-compatible object storage, archived history can be read with <<centrifugo historyarchive read>>. Parquet format is not supported."`
+
 	// WebSocket configuration. This transport is enabled by default.
 	WebSocket configtypes.WebSocket `mapstructure:"websocket" json:"websocket" envconfig:"websocket" toml:"websocket" yaml:"websocket" doc:"Configures the bidirectional WebSocket transport (enabled by default): message size limits, compression, ping/pong, and write timeouts."`
 	// SSE is a configuration for Server-Sent Events based bidirectional emulation transport.
 	SSE configtypes.SSE `mapstructure:"sse" json:"sse" envconfig:"sse" toml:"sse" yaml:"sse" doc:"Configures the bidirectional Server-Sent Events (EventSource) transport, which relies on the emulation endpoint. Disabled by default."`
 	// HTTPStream is a configuration for HTTP streaming based bidirectional emulation transport.
 	HTTPStream configtypes.HTTPStream `mapstructure:"http_stream" json:"http_stream" envconfig:"http_stream" toml:"http_stream" yaml:"http_stream" doc:"Configures the bidirectional HTTP-streaming transport, which relies on the emulation endpoint. Disabled by default."`
+	// LongPoll is a configuration for HTTP long-polling based bidirectional emulation transport.
+	LongPoll configtypes.LongPoll `mapstructure:"long_poll" json:"long_poll" envconfig:"long_poll" toml:"long_poll" yaml:"long_poll" doc:"Configures the bidirectional HTTP long-polling transport, which relies on the emulation endpoint. Useful behind proxies which buffer streaming responses. Disabled by default."`
 	// WebTransport is a configuration for WebTransport transport. EXPERIMENTAL.
 	WebTransport configtypes.WebTransport `mapstructure:"webtransport" json:"webtransport" envconfig:"webtransport" toml:"webtransport" yaml:"webtransport" doc:"Configures the bidirectional WebTransport (HTTP/3) transport. Experimental and disabled by default."`
 	// UniSSE is a configuration for unidirectional Server-Sent Events transport.
@@ -88,9 +105,11 @@ type Config struct {
 	UniWS configtypes.UniWebSocket `mapstructure:"uni_websocket" json:"uni_websocket" envconfig:"uni_websocket" toml:"uni_websocket" yaml:"uni_websocket" doc:"Configures the unidirectional WebSocket transport. Disabled by default."`
 	// UniGRPC is a configuration for unidirectional gRPC transport.
 	UniGRPC configtypes.UniGRPC `mapstructure:"uni_grpc" json:"uni_grpc" envconfig:"uni_grpc" toml:"uni_grpc" yaml:"uni_grpc" doc:"Configures the unidirectional gRPC transport: enable flag, port, and TLS. Disabled by default."`
+	// BidiGRPC is a configuration for bidirectional gRPC transport.
+	BidiGRPC configtypes.BidiGRPC `mapstructure:"bidi_grpc" json:"bidi_grpc" envconfig:"bidi_grpc" toml:"bidi_grpc" yaml:"bidi_grpc" doc:"Configures the bidirectional gRPC transport for service clients: enable flag, port, TLS and ping-pong. Disabled by default."`
 	// Emulation endpoint is enabled automatically when at least one bidirectional emulation transport
-	// is configured (SSE or HTTP Stream).
-	Emulation configtypes.Emulation `mapstructure:"emulation" json:"emulation" envconfig:"emulation" toml:"emulation" yaml:"emulation" doc:"Configures the emulation endpoint used by bidirectional SSE and HTTP-streaming transports. Enabled automatically when one of those transports is enabled."`
+	// is configured (SSE, HTTP Stream or long polling).
+	Emulation configtypes.Emulation `mapstructure:"emulation" json:"emulation" envconfig:"emulation" toml:"emulation" yaml:"emulation" doc:"Configures the emulation endpoint used by bidirectional SSE, HTTP-streaming and long-polling transports. Enabled automatically when one of those transports is enabled."`
 	// Admin web UI configuration.
 	Admin configtypes.Admin `mapstructure:"admin" json:"admin" envconfig:"admin" toml:"admin" yaml:"admin" doc:"Configures the admin web UI and its API endpoints."`
 	// Prometheus metrics configuration.
@@ -130,6 +149,11 @@ type Meta struct {
 	UnknownEnvs         []string
 	KnownEnvVars        map[string]envconfig.VarInfo
 	DeprecationWarnings []string
+	// SecretRefs contains original values with secret references keyed by config
+	// path for fields which were resolved during loading.
+	SecretRefs map[string]string
+
+	secretValues []string
 }
 
 func DefineFlags(rootCmd *cobra.Command) {
@@ -169,7 +193,19 @@ func DefineFlags(rootCmd *cobra.Command) {
 	rootCmd.Flags().IntP("uni_grpc.port", "", 11000, "port to bind unidirectional GRPC server to")
 }
 
+// GetConfig loads Centrifugo configuration from file, environment and command flags.
+// Secret references in string values are resolved.
 func GetConfig(cmd *cobra.Command, configFile string) (Config, Meta, error) {
+	return loadConfig(cmd, configFile, true)
+}
+
+// GetConfigWithSecretReferences is like GetConfig but keeps secret references in
+// string values as is. Useful to output configuration without leaking secrets.
+func GetConfigWithSecretReferences(cmd *cobra.Command, configFile string) (Config, Meta, error) {
+	return loadConfig(cmd, configFile, false)
+}
+
+func loadConfig(cmd *cobra.Command, configFile string, resolveSecretRefs bool) (Config, Meta, error) {
 	v := viper.NewWithOptions(viper.WithDecodeHook(mapstructure.ComposeDecodeHookFunc(
 		mapstructure.StringToTimeDurationHookFunc(),
 		configtypes.StringToDurationHookFunc(),
@@ -207,6 +243,13 @@ func GetConfig(cmd *cobra.Command, configFile string) (Config, Meta, error) {
 				return Config{}, Meta{}, fmt.Errorf("error reading config file %s: %w", configFile, err)
 			}
 		}
+		if !meta.FileNotFound {
+			deprecationWarnings, err := migrateConfigFile(v, configFile)
+			if err != nil {
+				return Config{}, Meta{}, fmt.Errorf("error migrating config file %s: %w", configFile, err)
+			}
+			meta.DeprecationWarnings = deprecationWarnings
+		}
 	}
 
 	conf := &Config{}
@@ -259,6 +302,15 @@ func GetConfig(cmd *cobra.Command, configFile string) (Config, Meta, error) {
 		extendKnownEnvVars(knownEnvVars, varInfo)
 	}
 
+	for i, item := range conf.Tenants {
+		varInfo, err = envconfig.Process("CENTRIFUGO_TENANTS_"+configtypes.NameForEnv(item.Name), &item)
+		if err != nil {
+			return Config{}, Meta{}, fmt.Errorf("error processing env tenants: %w", err)
+		}
+		conf.Tenants[i] = item
+		extendKnownEnvVars(knownEnvVars, varInfo)
+	}
+
 	for i, item := range conf.Consumers {
 		varInfo, err = envconfig.Process("CENTRIFUGO_CONSUMERS_"+configtypes.NameForEnv(item.Name), &item)
 		if err != nil {
@@ -268,15 +320,20 @@ func GetConfig(cmd *cobra.Command, configFile string) (Config, Meta, error) {
 		extendKnownEnvVars(knownEnvVars, varInfo)
 	}
 
+	if resolveSecretRefs {
+		secretRefs, secretValues, err := resolveSecrets(conf)
+		if err != nil {
+			return Config{}, Meta{}, err
+		}
+		meta.SecretRefs = secretRefs
+		meta.secretValues = secretValues
+	}
+
 	meta.UnknownKeys = findUnknownKeys(v.AllSettings(), conf, "")
 	meta.UnknownEnvs = checkEnvironmentVars(knownEnvVars)
 	meta.KnownEnvVars = knownEnvVars
 
-	cfg := *conf
-	cfg, deprecationWarnings := applyConfigMigrations(cfg)
-	meta.DeprecationWarnings = deprecationWarnings
-
-	return cfg, meta, nil
+	return *conf, meta, nil
 }
 
 func extendKnownEnvVars(knownEnvVars map[string]envconfig.VarInfo, varInfo []envconfig.VarInfo) {
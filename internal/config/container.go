@@ -1,6 +1,10 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
 	"regexp"
 	"strings"
 	"sync/atomic"
@@ -42,6 +46,18 @@ func getPreparedConfig(config Config) (*Config, error) {
 	if err != nil {
 		return &config, err
 	}
+	config, err = buildCompiledDataSchemas(config)
+	if err != nil {
+		return &config, err
+	}
+	config, err = buildCompressionDictionaries(config)
+	if err != nil {
+		return &config, err
+	}
+	config, err = buildCompiledTenantNamespaces(config)
+	if err != nil {
+		return &config, err
+	}
 	return &config, nil
 }
 
@@ -81,6 +97,96 @@ func buildCompiledRegexes(config Config) (Config, error) {
 	return config, nil
 }
 
+func buildCompiledDataSchemas(config Config) (Config, error) {
+	schema, err := compileDataSchema(config.Channel.WithoutNamespace)
+	if err != nil {
+		return config, err
+	}
+	config.Channel.WithoutNamespace.Compiled.CompiledDataSchema = schema
+
+	namespaces := make([]configtypes.ChannelNamespace, 0, len(config.Channel.Namespaces))
+	for _, ns := range config.Channel.Namespaces {
+		schema, err := compileDataSchema(ns.ChannelOptions)
+		if err != nil {
+			return config, fmt.Errorf("namespace %s: %w", ns.Name, err)
+		}
+		ns.Compiled.CompiledDataSchema = schema
+		namespaces = append(namespaces, ns)
+	}
+	config.Channel.Namespaces = namespaces
+	return config, nil
+}
+
+func buildCompressionDictionaries(config Config) (Config, error) {
+	namespaces := make([]configtypes.ChannelNamespace, 0, len(config.Channel.Namespaces))
+	for _, ns := range config.Channel.Namespaces {
+		if ns.WebSocketCompressionDictionaryFile != "" {
+			dict, err := loadCompressionDictionary(ns.WebSocketCompressionDictionaryFile)
+			if err != nil {
+				return config, fmt.Errorf("namespace %s: %w", ns.Name, err)
+			}
+			ns.Compiled.WebSocketCompressionDictionary = dict
+		}
+		namespaces = append(namespaces, ns)
+	}
+	config.Channel.Namespaces = namespaces
+	return config, nil
+}
+
+func buildCompiledTenantNamespaces(config Config) (Config, error) {
+	tenants := make(configtypes.Tenants, 0, len(config.Tenants))
+	for _, t := range config.Tenants {
+		namespaces := make(configtypes.ChannelNamespaces, 0, len(t.Namespaces))
+		for _, ns := range t.Namespaces {
+			if ns.ChannelRegex != "" {
+				p, err := regexp.Compile(ns.ChannelRegex)
+				if err != nil {
+					return config, fmt.Errorf("tenant %s namespace %s: %w", t.Name, ns.Name, err)
+				}
+				ns.Compiled.CompiledChannelRegex = p
+			}
+			schema, err := compileDataSchema(ns.ChannelOptions)
+			if err != nil {
+				return config, fmt.Errorf("tenant %s namespace %s: %w", t.Name, ns.Name, err)
+			}
+			ns.Compiled.CompiledDataSchema = schema
+			namespaces = append(namespaces, ns)
+		}
+		t.Namespaces = namespaces
+		tenants = append(tenants, t)
+	}
+	config.Tenants = tenants
+	return config, nil
+}
+
+func loadCompressionDictionary(path string) ([]byte, error) {
+	dict, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading websocket_compression_dictionary_file: %w", err)
+	}
+	if len(dict) == 0 {
+		return nil, errors.New("websocket_compression_dictionary_file is empty")
+	}
+	return dict, nil
+}
+
+// tenantName returns tenant name and the rest of channel without private prefix and
+// tenant prefix if channel belongs to a configured tenant.
+func (n *Container) tenantName(config Config, ch string) (string, string, bool) {
+	if !config.Tenancy.Enabled {
+		return "", "", false
+	}
+	cTrim := strings.TrimPrefix(ch, config.Channel.PrivatePrefix)
+	name, rest, found := strings.Cut(cTrim, config.Tenancy.ChannelBoundary)
+	if !found {
+		return "", "", false
+	}
+	if _, ok := findTenant(&config, name); !ok {
+		return "", "", false
+	}
+	return name, rest, true
+}
+
 // namespaceName returns namespace name from channel if exists.
 func (n *Container) namespaceName(config Config, ch string) (string, string) {
 	cTrim := strings.TrimPrefix(ch, config.Channel.PrivatePrefix)
@@ -107,8 +213,20 @@ func (n *Container) ChannelOptions(ch string) (string, string, configtypes.Chann
 		}
 	}
 	cfg := n.configValue.Load().(Config)
-	nsName, rest := n.namespaceName(cfg, ch)
-	chOpts, ok, err := channelOpts(&cfg, nsName)
+	var (
+		nsName string
+		rest   string
+		chOpts configtypes.ChannelOptions
+		ok     bool
+		err    error
+	)
+	if tenantName, tenantRest, isTenant := n.tenantName(cfg, ch); isTenant {
+		nsName, rest = n.namespaceName(cfg, tenantRest)
+		chOpts, ok, err = tenantChannelOpts(&cfg, tenantName, nsName)
+	} else {
+		nsName, rest = n.namespaceName(cfg, ch)
+		chOpts, ok, err = channelOpts(&cfg, nsName)
+	}
 
 	// Apply global publication_data_format default if not set at namespace level
 	if chOpts.PublicationDataFormat == "" && cfg.Channel.PublicationDataFormat != "" {
@@ -147,6 +265,77 @@ func channelOpts(c *Config, namespaceName string) (configtypes.ChannelOptions, b
 	return configtypes.ChannelOptions{}, false, nil
 }
 
+// tenantChannelOpts searches for channel options for specified namespace key of tenant.
+// Tenant channels without namespace use global options for channels without namespace.
+func tenantChannelOpts(c *Config, tenantName string, namespaceName string) (configtypes.ChannelOptions, bool, error) {
+	if namespaceName == "" {
+		return c.Channel.WithoutNamespace, true, nil
+	}
+	t, _ := findTenant(c, tenantName)
+	for _, n := range t.Namespaces {
+		if n.Name == namespaceName {
+			return n.ChannelOptions, true, nil
+		}
+	}
+	return configtypes.ChannelOptions{}, false, nil
+}
+
+func findTenant(c *Config, name string) (configtypes.Tenant, bool) {
+	for _, t := range c.Tenants {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return configtypes.Tenant{}, false
+}
+
+// ChannelTenant returns name of tenant channel belongs to, empty string for channels
+// outside of tenants or when tenancy is not enabled.
+func (n *Container) ChannelTenant(ch string) string {
+	cfg := n.configValue.Load().(Config)
+	name, _, _ := n.tenantName(cfg, ch)
+	return name
+}
+
+// TenantChannel returns channel of tenant. Private prefix of channel is kept in front
+// of tenant prefix.
+func (n *Container) TenantChannel(tenant string, ch string) string {
+	cfg := n.configValue.Load().(Config)
+	prefix := tenant + cfg.Tenancy.ChannelBoundary
+	if cfg.Channel.PrivatePrefix != "" && strings.HasPrefix(ch, cfg.Channel.PrivatePrefix) {
+		return cfg.Channel.PrivatePrefix + prefix + strings.TrimPrefix(ch, cfg.Channel.PrivatePrefix)
+	}
+	return prefix + ch
+}
+
+// Tenant returns tenant configuration by name.
+func (n *Container) Tenant(name string) (configtypes.Tenant, bool) {
+	cfg := n.configValue.Load().(Config)
+	if !cfg.Tenancy.Enabled {
+		return configtypes.Tenant{}, false
+	}
+	return findTenant(&cfg, name)
+}
+
+// TenantByHost returns name of tenant request host belongs to. Port in host is ignored.
+func (n *Container) TenantByHost(host string) (string, bool) {
+	cfg := n.configValue.Load().(Config)
+	if !cfg.Tenancy.Enabled {
+		return "", false
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	for _, t := range cfg.Tenants {
+		for _, tenantHost := range t.Hosts {
+			if strings.EqualFold(tenantHost, host) {
+				return t.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
 // PersonalChannel returns personal channel for user based on node configuration.
 func (n *Container) PersonalChannel(user string) string {
 	cfg := n.Config()
@@ -156,6 +345,18 @@ func (n *Container) PersonalChannel(user string) string {
 	return cfg.Client.SubscribeToUserPersonalChannel.PersonalChannelNamespace + cfg.Channel.NamespaceBoundary + cfg.Channel.UserBoundary + user
 }
 
+// WebSocketCompressionDictionary returns preset WebSocket compression dictionary
+// configured for namespace, nil if not configured.
+func (n *Container) WebSocketCompressionDictionary(namespace string) []byte {
+	cfg := n.configValue.Load().(Config)
+	for _, ns := range cfg.Channel.Namespaces {
+		if ns.Name == namespace {
+			return ns.Compiled.WebSocketCompressionDictionary
+		}
+	}
+	return nil
+}
+
 // Config returns a copy of node Config.
 func (n *Container) Config() Config {
 	return n.configValue.Load().(Config)
@@ -342,3 +342,57 @@ func BenchmarkContainer_Config(b *testing.B) {
 		}
 	})
 }
+
+func TestTenantChannelOptions(t *testing.T) {
+	c := defaultConfig(t)
+	c.Tenancy.Enabled = true
+	c.Tenants = []configtypes.Tenant{
+		{
+			Name:  "acme",
+			Hosts: []string{"acme.example.com"},
+			Token: configtypes.Token{HMACSecretKey: "secret"},
+			Namespaces: []configtypes.ChannelNamespace{
+				{
+					Name:           "chat",
+					ChannelOptions: configtypes.ChannelOptions{Presence: true},
+				},
+			},
+		},
+	}
+	require.NoError(t, c.Validate())
+	container, err := NewContainer(c)
+	require.NoError(t, err)
+
+	nsName, _, chOpts, found, err := container.ChannelOptions("acme/chat:index")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "chat", nsName)
+	require.True(t, chOpts.Presence)
+
+	_, _, _, found, err = container.ChannelOptions("acme/index")
+	require.NoError(t, err)
+	require.True(t, found)
+
+	_, _, _, found, err = container.ChannelOptions("acme/news:index")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	// Tenant namespaces are not visible outside tenant.
+	_, _, _, found, err = container.ChannelOptions("chat:index")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	require.Equal(t, "acme", container.ChannelTenant("acme/chat:index"))
+	require.Equal(t, "acme", container.ChannelTenant("$acme/chat:index"))
+	require.Equal(t, "", container.ChannelTenant("globex/chat:index"))
+	require.Equal(t, "", container.ChannelTenant("chat:index"))
+
+	require.Equal(t, "acme/chat:index", container.TenantChannel("acme", "chat:index"))
+	require.Equal(t, "$acme/chat:index", container.TenantChannel("acme", "$chat:index"))
+
+	tenant, ok := container.TenantByHost("ACME.example.com:8000")
+	require.True(t, ok)
+	require.Equal(t, "acme", tenant)
+	_, ok = container.TenantByHost("globex.example.com")
+	require.False(t, ok)
+}
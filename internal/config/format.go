@@ -1,12 +1,21 @@
 package config
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
 	"github.com/centrifugal/centrifugo/v6/internal/tools"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
 )
 
+// ErrSchemaViolation is returned when publication data does not match JSON Schema
+// configured for namespace.
+var ErrSchemaViolation = errors.New("publication data does not match schema")
+
 // ValidatePublicationData validates publication data according to the specified format.
 // Returns an error if the data doesn't match the format requirements.
 func ValidatePublicationData(data []byte, format string) error {
@@ -29,3 +38,50 @@ func ValidatePublicationData(data []byte, format string) error {
 	}
 	return nil
 }
+
+// ValidatePublicationDataSchema validates publication data against compiled JSON Schema
+// of channel options. Returns nil if schema is not configured.
+func ValidatePublicationDataSchema(data []byte, chOpts configtypes.ChannelOptions) error {
+	schema := chOpts.Compiled.CompiledDataSchema
+	if schema == nil {
+		return nil
+	}
+	inst, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%w: data is not valid JSON", ErrSchemaViolation)
+	}
+	if err := schema.Validate(inst); err != nil {
+		return fmt.Errorf("%w: %v", ErrSchemaViolation, err)
+	}
+	return nil
+}
+
+// compileDataSchema compiles JSON Schema embedded into channel options or loaded
+// from schema file. Returns nil if schema is not configured.
+func compileDataSchema(chOpts configtypes.ChannelOptions) (*jsonschema.Schema, error) {
+	c := jsonschema.NewCompiler()
+	switch {
+	case chOpts.PublicationDataSchema != "":
+		doc, err := jsonschema.UnmarshalJSON(strings.NewReader(chOpts.PublicationDataSchema))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing publication_data_schema: %w", err)
+		}
+		const url = "publication_data_schema.json"
+		if err := c.AddResource(url, doc); err != nil {
+			return nil, fmt.Errorf("error adding publication_data_schema: %w", err)
+		}
+		schema, err := c.Compile(url)
+		if err != nil {
+			return nil, fmt.Errorf("error compiling publication_data_schema: %w", err)
+		}
+		return schema, nil
+	case chOpts.PublicationDataSchemaFile != "":
+		schema, err := c.Compile(chOpts.PublicationDataSchemaFile)
+		if err != nil {
+			return nil, fmt.Errorf("error compiling publication_data_schema_file: %w", err)
+		}
+		return schema, nil
+	default:
+		return nil, nil
+	}
+}
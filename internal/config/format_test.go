@@ -1,6 +1,8 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
@@ -157,3 +159,42 @@ func TestValidatePublicationData(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatePublicationDataSchema(t *testing.T) {
+	chOpts := configtypes.ChannelOptions{
+		PublicationDataSchema: `{"type": "object", "required": ["id"], "properties": {"id": {"type": "integer"}}}`,
+	}
+	schema, err := compileDataSchema(chOpts)
+	require.NoError(t, err)
+	require.NotNil(t, schema)
+	chOpts.Compiled.CompiledDataSchema = schema
+
+	require.NoError(t, ValidatePublicationDataSchema([]byte(`{"id": 1}`), chOpts))
+
+	err = ValidatePublicationDataSchema([]byte(`{"id": "1"}`), chOpts)
+	require.ErrorIs(t, err, ErrSchemaViolation)
+
+	err = ValidatePublicationDataSchema([]byte(`{}`), chOpts)
+	require.ErrorIs(t, err, ErrSchemaViolation)
+
+	err = ValidatePublicationDataSchema([]byte(`not json`), chOpts)
+	require.ErrorIs(t, err, ErrSchemaViolation)
+
+	// No schema configured - any data passes.
+	require.NoError(t, ValidatePublicationDataSchema([]byte(`not json`), configtypes.ChannelOptions{}))
+}
+
+func TestCompileDataSchemaFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"type": "array"}`), 0600))
+
+	schema, err := compileDataSchema(configtypes.ChannelOptions{PublicationDataSchemaFile: path})
+	require.NoError(t, err)
+	chOpts := configtypes.ChannelOptions{}
+	chOpts.Compiled.CompiledDataSchema = schema
+	require.NoError(t, ValidatePublicationDataSchema([]byte(`[1, 2]`), chOpts))
+	require.ErrorIs(t, ValidatePublicationDataSchema([]byte(`{}`), chOpts), ErrSchemaViolation)
+
+	_, err = compileDataSchema(configtypes.ChannelOptions{PublicationDataSchemaFile: filepath.Join(t.TempDir(), "missing.json")})
+	require.Error(t, err)
+}
@@ -1,6 +1,362 @@
 package config
 
-func applyConfigMigrations(cfg Config) (Config, []string) {
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// keyMigration describes how a legacy configuration key (flat format used by
+// Centrifugo v5 and earlier) maps to the current nested configuration structure.
+type keyMigration struct {
+	// From is a legacy top-level key.
+	From string
+	// To is a dot-separated path of the key in the current configuration.
+	To string
+	// Enable is an optional dot-separated path of a boolean option set to true
+	// when legacy key migrated. Used for features which were enabled implicitly
+	// in previous versions, like proxies enabled by setting an endpoint.
+	Enable string
+}
+
+// legacyKeyMigrations is an ordered list of supported top-level key migrations.
+var legacyKeyMigrations = []keyMigration{
+	// HTTP server.
+	{From: "address", To: "http_server.address"},
+	{From: "port", To: "http_server.port"},
+	{From: "internal_address", To: "http_server.internal_address"},
+	{From: "internal_port", To: "http_server.internal_port"},
+	{From: "tls", To: "http_server.tls.enabled"},
+	{From: "tls_cert", To: "http_server.tls.cert_pem"},
+	{From: "tls_key", To: "http_server.tls.key_pem"},
+	{From: "tls_external", To: "http_server.tls_external"},
+
+	// Logging, node and shutdown.
+	{From: "log_level", To: "log.level"},
+	{From: "log_file", To: "log.file"},
+	{From: "name", To: "node.name"},
+	{From: "node_info_metrics_aggregate_interval", To: "node.info_metrics_aggregate_interval"},
+	{From: "shutdown_timeout", To: "shutdown.timeout"},
+
+	// Engine and broker.
+	{From: "engine", To: "engine.type"},
+	{From: "redis_address", To: "engine.redis.address"},
+	{From: "redis_cluster_address", To: "engine.redis.cluster_address"},
+	{From: "redis_sentinel_address", To: "engine.redis.sentinel_address"},
+	{From: "redis_sentinel_master_name", To: "engine.redis.sentinel_master_name"},
+	{From: "redis_user", To: "engine.redis.user"},
+	{From: "redis_password", To: "engine.redis.password"},
+	{From: "redis_db", To: "engine.redis.db"},
+	{From: "redis_prefix", To: "engine.redis.prefix"},
+	{From: "redis_connect_timeout", To: "engine.redis.connect_timeout"},
+	{From: "redis_io_timeout", To: "engine.redis.io_timeout"},
+	{From: "redis_force_resp2", To: "engine.redis.force_resp2"},
+	{From: "redis_presence_ttl", To: "engine.redis.presence_ttl"},
+	{From: "broker", To: "broker.type", Enable: "broker.enabled"},
+	{From: "nats_url", To: "broker.nats.url"},
+	{From: "nats_prefix", To: "broker.nats.prefix"},
+	{From: "nats_dial_timeout", To: "broker.nats.dial_timeout"},
+	{From: "nats_write_timeout", To: "broker.nats.write_timeout"},
+
+	// Client connections.
+	{From: "allowed_origins", To: "client.allowed_origins"},
+	{From: "client_insecure", To: "client.insecure"},
+	{From: "anonymous_connect_without_token", To: "client.allow_anonymous_connect_without_token"},
+	{From: "disallow_anonymous_connection_tokens", To: "client.disallow_anonymous_connection_tokens"},
+	{From: "ping_interval", To: "client.ping_interval"},
+	{From: "pong_timeout", To: "client.pong_timeout"},
+	{From: "client_channel_limit", To: "client.channel_limit"},
+	{From: "client_connection_limit", To: "client.connection_limit"},
+	{From: "client_user_connection_limit", To: "client.user_connection_limit"},
+	{From: "client_connection_rate_limit", To: "client.connection_rate_limit"},
+	{From: "client_queue_max_size", To: "client.queue_max_size"},
+	{From: "client_concurrency", To: "client.concurrency"},
+	{From: "client_expired_close_delay", To: "client.expired_close_delay"},
+	{From: "client_expired_sub_close_delay", To: "client.expired_sub_close_delay"},
+	{From: "client_stale_close_delay", To: "client.stale_close_delay"},
+	{From: "client_presence_update_interval", To: "client.presence_update_interval"},
+	{From: "client_channel_position_check_delay", To: "client.channel_position_check_delay"},
+	{From: "client_channel_position_max_time_lag", To: "client.channel_position_max_time_lag"},
+	{From: "client_history_max_publication_limit", To: "client.history_max_publication_limit"},
+	{From: "client_recovery_max_publication_limit", To: "client.recovery_max_publication_limit"},
+	{From: "client_connect_include_server_time", To: "client.connect_include_server_time"},
+	{From: "client_user_id_http_header", To: "client.user_id_http_header"},
+
+	// Tokens.
+	{From: "token_hmac_secret_key", To: "client.token.hmac_secret_key"},
+	{From: "token_rsa_public_key", To: "client.token.rsa_public_key"},
+	{From: "token_ecdsa_public_key", To: "client.token.ecdsa_public_key"},
+	{From: "token_jwks_public_endpoint", To: "client.token.jwks_public_endpoint"},
+	{From: "token_audience", To: "client.token.audience"},
+	{From: "token_audience_regex", To: "client.token.audience_regex"},
+	{From: "token_issuer", To: "client.token.issuer"},
+	{From: "token_issuer_regex", To: "client.token.issuer_regex"},
+	{From: "token_user_id_claim", To: "client.token.user_id_claim"},
+	{From: "separate_subscription_token_config", To: "client.subscription_token.enabled"},
+	{From: "subscription_token_hmac_secret_key", To: "client.subscription_token.hmac_secret_key"},
+	{From: "subscription_token_rsa_public_key", To: "client.subscription_token.rsa_public_key"},
+	{From: "subscription_token_ecdsa_public_key", To: "client.subscription_token.ecdsa_public_key"},
+	{From: "subscription_token_jwks_public_endpoint", To: "client.subscription_token.jwks_public_endpoint"},
+	{From: "subscription_token_audience", To: "client.subscription_token.audience"},
+	{From: "subscription_token_audience_regex", To: "client.subscription_token.audience_regex"},
+	{From: "subscription_token_issuer", To: "client.subscription_token.issuer"},
+	{From: "subscription_token_issuer_regex", To: "client.subscription_token.issuer_regex"},
+	{From: "subscription_token_user_id_claim", To: "client.subscription_token.user_id_claim"},
+
+	// Channels.
+	{From: "namespaces", To: "channel.namespaces"},
+	{From: "channel_max_length", To: "channel.max_length"},
+	{From: "channel_private_prefix", To: "channel.private_prefix"},
+	{From: "channel_namespace_boundary", To: "channel.namespace_boundary"},
+	{From: "channel_user_boundary", To: "channel.user_boundary"},
+	{From: "channel_user_separator", To: "channel.user_separator"},
+	{From: "history_meta_ttl", To: "channel.history_meta_ttl"},
+	{From: "presence", To: "channel.without_namespace.presence"},
+	{From: "join_leave", To: "channel.without_namespace.join_leave"},
+	{From: "force_push_join_leave", To: "channel.without_namespace.force_push_join_leave"},
+	{From: "history_size", To: "channel.without_namespace.history_size"},
+	{From: "history_ttl", To: "channel.without_namespace.history_ttl"},
+	{From: "force_positioning", To: "channel.without_namespace.force_positioning"},
+	{From: "allow_positioning", To: "channel.without_namespace.allow_positioning"},
+	{From: "force_recovery", To: "channel.without_namespace.force_recovery"},
+	{From: "allow_recovery", To: "channel.without_namespace.allow_recovery"},
+	{From: "allowed_delta_types", To: "channel.without_namespace.allowed_delta_types"},
+	{From: "delta_publish", To: "channel.without_namespace.delta_publish"},
+	{From: "allow_subscribe_for_client", To: "channel.without_namespace.allow_subscribe_for_client"},
+	{From: "allow_subscribe_for_anonymous", To: "channel.without_namespace.allow_subscribe_for_anonymous"},
+	{From: "allow_publish_for_subscriber", To: "channel.without_namespace.allow_publish_for_subscriber"},
+	{From: "allow_publish_for_client", To: "channel.without_namespace.allow_publish_for_client"},
+	{From: "allow_publish_for_anonymous", To: "channel.without_namespace.allow_publish_for_anonymous"},
+	{From: "allow_history_for_subscriber", To: "channel.without_namespace.allow_history_for_subscriber"},
+	{From: "allow_history_for_client", To: "channel.without_namespace.allow_history_for_client"},
+	{From: "allow_history_for_anonymous", To: "channel.without_namespace.allow_history_for_anonymous"},
+	{From: "allow_presence_for_subscriber", To: "channel.without_namespace.allow_presence_for_subscriber"},
+	{From: "allow_presence_for_client", To: "channel.without_namespace.allow_presence_for_client"},
+	{From: "allow_presence_for_anonymous", To: "channel.without_namespace.allow_presence_for_anonymous"},
+	{From: "allow_user_limited_channels", To: "channel.without_namespace.allow_user_limited_channels"},
+	{From: "channel_regex", To: "channel.without_namespace.channel_regex"},
+	{From: "proxy_subscribe", To: "channel.without_namespace.subscribe_proxy_enabled"},
+	{From: "proxy_publish", To: "channel.without_namespace.publish_proxy_enabled"},
+	{From: "proxy_sub_refresh", To: "channel.without_namespace.sub_refresh_proxy_enabled"},
+	{From: "proxy_subscribe_stream", To: "channel.without_namespace.subscribe_stream_proxy_enabled"},
+
+	// RPC.
+	{From: "rpc_namespaces", To: "rpc.namespaces"},
+	{From: "rpc_namespace_boundary", To: "rpc.namespace_boundary"},
+
+	// Proxies.
+	{From: "proxy_connect_endpoint", To: "client.proxy.connect.endpoint", Enable: "client.proxy.connect.enabled"},
+	{From: "proxy_connect_timeout", To: "client.proxy.connect.timeout"},
+	{From: "proxy_refresh_endpoint", To: "client.proxy.refresh.endpoint", Enable: "client.proxy.refresh.enabled"},
+	{From: "proxy_refresh_timeout", To: "client.proxy.refresh.timeout"},
+	{From: "proxy_rpc_endpoint", To: "rpc.proxy.endpoint", Enable: "rpc.without_namespace.proxy_enabled"},
+	{From: "proxy_rpc_timeout", To: "rpc.proxy.timeout"},
+	{From: "proxy_subscribe_endpoint", To: "channel.proxy.subscribe.endpoint"},
+	{From: "proxy_subscribe_timeout", To: "channel.proxy.subscribe.timeout"},
+	{From: "proxy_publish_endpoint", To: "channel.proxy.publish.endpoint"},
+	{From: "proxy_publish_timeout", To: "channel.proxy.publish.timeout"},
+	{From: "proxy_sub_refresh_endpoint", To: "channel.proxy.sub_refresh.endpoint"},
+	{From: "proxy_sub_refresh_timeout", To: "channel.proxy.sub_refresh.timeout"},
+	{From: "proxy_subscribe_stream_endpoint", To: "channel.proxy.subscribe_stream.endpoint"},
+	{From: "proxy_subscribe_stream_timeout", To: "channel.proxy.subscribe_stream.timeout"},
+
+	// Server APIs.
+	{From: "api_key", To: "http_api.key"},
+	{From: "api_insecure", To: "http_api.insecure"},
+	{From: "api_external", To: "http_api.external"},
+	{From: "api_handler_prefix", To: "http_api.handler_prefix"},
+	{From: "grpc_api", To: "grpc_api.enabled"},
+	{From: "grpc_api_address", To: "grpc_api.address"},
+	{From: "grpc_api_port", To: "grpc_api.port"},
+	{From: "grpc_api_key", To: "grpc_api.key"},
+	{From: "grpc_api_reflection", To: "grpc_api.reflection"},
+
+	// Transports.
+	{From: "websocket_disable", To: "websocket.disabled"},
+	{From: "websocket_handler_prefix", To: "websocket.handler_prefix"},
+	{From: "websocket_compression", To: "websocket.compression"},
+	{From: "websocket_compression_min_size", To: "websocket.compression_min_size"},
+	{From: "websocket_compression_level", To: "websocket.compression_level"},
+	{From: "websocket_read_buffer_size", To: "websocket.read_buffer_size"},
+	{From: "websocket_write_buffer_size", To: "websocket.write_buffer_size"},
+	{From: "websocket_use_write_buffer_pool", To: "websocket.use_write_buffer_pool"},
+	{From: "websocket_write_timeout", To: "websocket.write_timeout"},
+	{From: "websocket_message_size_limit", To: "websocket.message_size_limit"},
+	{From: "sse", To: "sse.enabled"},
+	{From: "http_stream", To: "http_stream.enabled"},
+	{From: "uni_websocket", To: "uni_websocket.enabled"},
+	{From: "uni_sse", To: "uni_sse.enabled"},
+	{From: "uni_http_stream", To: "uni_http_stream.enabled"},
+	{From: "uni_grpc", To: "uni_grpc.enabled"},
+	{From: "uni_grpc_address", To: "uni_grpc.address"},
+	{From: "uni_grpc_port", To: "uni_grpc.port"},
+
+	// Admin and observability.
+	{From: "admin", To: "admin.enabled"},
+	{From: "admin_password", To: "admin.password"},
+	{From: "admin_secret", To: "admin.secret"},
+	{From: "admin_insecure", To: "admin.insecure"},
+	{From: "admin_external", To: "admin.external"},
+	{From: "admin_handler_prefix", To: "admin.handler_prefix"},
+	{From: "admin_web_path", To: "admin.web_path"},
+	{From: "prometheus", To: "prometheus.enabled"},
+	{From: "health", To: "health.enabled"},
+	{From: "debug", To: "debug.enabled"},
+	{From: "swagger", To: "swagger.enabled"},
+	{From: "opentelemetry", To: "opentelemetry.enabled"},
+	{From: "graphite", To: "graphite.enabled"},
+	{From: "graphite_host", To: "graphite.host"},
+	{From: "graphite_port", To: "graphite.port"},
+	{From: "graphite_prefix", To: "graphite.prefix"},
+	{From: "graphite_interval", To: "graphite.interval"},
+	{From: "graphite_tags", To: "graphite.tags"},
+	{From: "usage_stats_disable", To: "usage_stats.disabled"},
+}
+
+// legacyNamespaceKeyMigrations rename legacy keys inside channel namespace objects.
+var legacyNamespaceKeyMigrations = []keyMigration{
+	{From: "proxy_subscribe", To: "subscribe_proxy_enabled"},
+	{From: "proxy_publish", To: "publish_proxy_enabled"},
+	{From: "proxy_sub_refresh", To: "sub_refresh_proxy_enabled"},
+	{From: "proxy_subscribe_stream", To: "subscribe_stream_proxy_enabled"},
+}
+
+// applyConfigMigrations moves values of legacy configuration keys in raw settings
+// (as read from configuration file) to their current location. Settings are
+// modified in place. Every applied migration is reported in returned warnings.
+func applyConfigMigrations(settings map[string]any) []string {
 	var warnings []string
-	return cfg, warnings
+
+	type legacyValue struct {
+		migration keyMigration
+		value     any
+	}
+	// Collect all legacy values first: some legacy keys (like "admin") share names
+	// with current sections and must be taken before other migrations create them.
+	var legacyValues []legacyValue
+	for _, m := range legacyKeyMigrations {
+		value, ok := settings[m.From]
+		if !ok {
+			continue
+		}
+		if _, isMap := value.(map[string]any); isMap {
+			// Already in current format.
+			continue
+		}
+		legacyValues = append(legacyValues, legacyValue{migration: m, value: value})
+		delete(settings, m.From)
+	}
+
+	for _, lv := range legacyValues {
+		m := lv.migration
+		if _, exists := getSettingsPath(settings, m.To); exists {
+			warnings = append(warnings, fmt.Sprintf(
+				"legacy configuration key %q ignored since %q is already set", m.From, m.To))
+			continue
+		}
+		setSettingsPath(settings, m.To, lv.value)
+		if m.Enable != "" {
+			if _, exists := getSettingsPath(settings, m.Enable); !exists {
+				setSettingsPath(settings, m.Enable, true)
+			}
+		}
+		warnings = append(warnings, fmt.Sprintf(
+			"legacy configuration key %q migrated to %q, use centrifugo migrateconfig command to update configuration file", m.From, m.To))
+	}
+
+	if namespaces, ok := getSettingsPath(settings, "channel.namespaces"); ok {
+		if items, ok := namespaces.([]any); ok {
+			for i, item := range items {
+				ns, ok := item.(map[string]any)
+				if !ok {
+					continue
+				}
+				for _, m := range legacyNamespaceKeyMigrations {
+					value, ok := ns[m.From]
+					if !ok {
+						continue
+					}
+					delete(ns, m.From)
+					path := fmt.Sprintf("channel.namespaces[%d]", i)
+					if _, exists := ns[m.To]; exists {
+						warnings = append(warnings, fmt.Sprintf(
+							"legacy configuration key %q ignored since %q is already set", path+"."+m.From, path+"."+m.To))
+						continue
+					}
+					ns[m.To] = value
+					warnings = append(warnings, fmt.Sprintf(
+						"legacy configuration key %q migrated to %q, use centrifugo migrateconfig command to update configuration file", path+"."+m.From, path+"."+m.To))
+				}
+			}
+		}
+	}
+
+	return warnings
+}
+
+// MigrateSettings applies legacy key migrations to raw settings decoded from
+// configuration file. Used by migrateconfig command.
+func MigrateSettings(settings map[string]any) []string {
+	return applyConfigMigrations(settings)
+}
+
+// migrateConfigFile applies migrations to settings of configuration file. If any
+// migration applied, viper config values are replaced with migrated settings.
+func migrateConfigFile(v *viper.Viper, configFile string) ([]string, error) {
+	fv := viper.New()
+	fv.SetConfigFile(configFile)
+	if err := fv.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	settings := fv.AllSettings()
+	warnings := applyConfigMigrations(settings)
+	if len(warnings) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+	v.SetConfigType("json")
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
+
+func getSettingsPath(settings map[string]any, path string) (any, bool) {
+	parts := strings.Split(path, ".")
+	current := settings
+	for i, part := range parts {
+		value, ok := current[part]
+		if !ok {
+			return nil, false
+		}
+		if i == len(parts)-1 {
+			return value, true
+		}
+		next, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	return nil, false
+}
+
+func setSettingsPath(settings map[string]any, path string, value any) {
+	parts := strings.Split(path, ".")
+	current := settings
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			current[part] = next
+		}
+		current = next
+	}
+	current[parts[len(parts)-1]] = value
 }
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyConfigMigrations(t *testing.T) {
+	settings := map[string]any{
+		"allowed_origins":        []any{"http://localhost:3000"},
+		"token_hmac_secret_key":  "secret",
+		"admin":                  true,
+		"admin_password":         "password",
+		"engine":                 "redis",
+		"redis_address":          "redis://localhost:6379",
+		"proxy_connect_endpoint": "http://localhost:3000/connect",
+		"namespaces": []any{
+			map[string]any{"name": "chat", "proxy_subscribe": true},
+		},
+		"client": map[string]any{"insecure": true},
+	}
+	warnings := applyConfigMigrations(settings)
+	require.Len(t, warnings, 9)
+
+	require.Equal(t, map[string]any{
+		"allowed_origins": []any{"http://localhost:3000"},
+		"insecure":        true,
+		"token":           map[string]any{"hmac_secret_key": "secret"},
+		"proxy": map[string]any{
+			"connect": map[string]any{"endpoint": "http://localhost:3000/connect", "enabled": true},
+		},
+	}, settings["client"])
+	require.Equal(t, map[string]any{"enabled": true, "password": "password"}, settings["admin"])
+	require.Equal(t, map[string]any{"type": "redis", "redis": map[string]any{"address": "redis://localhost:6379"}}, settings["engine"])
+	require.Equal(t, map[string]any{
+		"namespaces": []any{
+			map[string]any{"name": "chat", "subscribe_proxy_enabled": true},
+		},
+	}, settings["channel"])
+	require.NotContains(t, settings, "namespaces")
+	require.NotContains(t, settings, "token_hmac_secret_key")
+}
+
+func TestApplyConfigMigrations_CurrentFormat(t *testing.T) {
+	settings := map[string]any{
+		"admin":  map[string]any{"enabled": true},
+		"engine": map[string]any{"type": "memory"},
+	}
+	require.Empty(t, applyConfigMigrations(settings))
+}
+
+func TestApplyConfigMigrations_Conflict(t *testing.T) {
+	settings := map[string]any{
+		"api_key":  "legacy",
+		"http_api": map[string]any{"key": "current"},
+	}
+	warnings := applyConfigMigrations(settings)
+	require.Len(t, warnings, 1)
+	require.Contains(t, warnings[0], "ignored")
+	require.Equal(t, map[string]any{"key": "current"}, settings["http_api"])
+	require.NotContains(t, settings, "api_key")
+}
+
+func TestGetConfigLegacyKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"allowed_origins": ["http://localhost:3000"],
+		"token_hmac_secret_key": "secret",
+		"history_size": 10,
+		"history_ttl": "300s",
+		"namespaces": [{"name": "chat", "presence": true}]
+	}`), 0600))
+
+	conf, meta := getConfig(t, path)
+	require.Equal(t, []string{"http://localhost:3000"}, conf.Client.AllowedOrigins)
+	require.Equal(t, "secret", conf.Client.Token.HMACSecretKey)
+	require.Equal(t, 10, conf.Channel.WithoutNamespace.HistorySize)
+	require.Equal(t, configtypes.Duration(300*time.Second), conf.Channel.WithoutNamespace.HistoryTTL)
+	require.Len(t, conf.Channel.Namespaces, 1)
+	require.True(t, conf.Channel.Namespaces[0].Presence)
+	require.Len(t, meta.DeprecationWarnings, 5)
+	require.Empty(t, meta.UnknownKeys)
+}
@@ -0,0 +1,247 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SecretProvider resolves secret references of form ${<scheme>:<ref>} found in
+// string configuration values. Providers for external secret managers may be
+// registered with RegisterSecretProvider. A reference escaped as $${<scheme>:<ref>}
+// is not resolved and results into literal ${<scheme>:<ref>}.
+type SecretProvider interface {
+	// Scheme is a reference prefix handled by provider, e.g. "file" or "env".
+	Scheme() string
+	// Resolve returns secret value for a reference.
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// secretResolveTimeout limits the time spent resolving all secret references
+// during a single configuration load.
+const secretResolveTimeout = 30 * time.Second
+
+// RedactedValue replaces secret values in output meant to be shown to humans.
+const RedactedValue = "<redacted>"
+
+// secretRefRegex matches secret references together with optional escaping $.
+var secretRefRegex = regexp.MustCompile(`(\$?)\$\{([a-z][a-z0-9_]*):([^}]*)}`)
+
+var (
+	secretProvidersMu sync.RWMutex
+	secretProviders   = map[string]SecretProvider{
+		"file": fileSecretProvider{},
+		"env":  envSecretProvider{},
+	}
+)
+
+// RegisterSecretProvider makes a provider available for resolving references
+// with its scheme. Registering a provider with the scheme of already registered
+// one replaces it.
+func RegisterSecretProvider(p SecretProvider) {
+	secretProvidersMu.Lock()
+	defer secretProvidersMu.Unlock()
+	secretProviders[p.Scheme()] = p
+}
+
+func getSecretProvider(scheme string) (SecretProvider, bool) {
+	secretProvidersMu.RLock()
+	defer secretProvidersMu.RUnlock()
+	p, ok := secretProviders[scheme]
+	return p, ok
+}
+
+// IsSecretReference reports whether value contains at least one secret reference
+// which is not escaped.
+func IsSecretReference(value string) bool {
+	for _, submatches := range secretRefRegex.FindAllStringSubmatch(value, -1) {
+		if submatches[1] == "" {
+			return true
+		}
+	}
+	return false
+}
+
+type fileSecretProvider struct{}
+
+func (fileSecretProvider) Scheme() string { return "file" }
+
+func (fileSecretProvider) Resolve(_ context.Context, ref string) (string, error) {
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return "", err
+	}
+	// Secret files (Docker, Kubernetes) often end with a newline which is never
+	// a part of the secret itself.
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+type envSecretProvider struct{}
+
+func (envSecretProvider) Scheme() string { return "env" }
+
+func (envSecretProvider) Resolve(_ context.Context, ref string) (string, error) {
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("environment variable %q not found", ref)
+	}
+	return value, nil
+}
+
+// RedactSecrets replaces resolved secret values found in s with RedactedValue. Use
+// it for output which may include configuration values, such as validation errors.
+func (m Meta) RedactSecrets(s string) string {
+	for _, secret := range m.secretValues {
+		s = strings.ReplaceAll(s, secret, RedactedValue)
+	}
+	return s
+}
+
+// resolveSecrets replaces secret references in all string fields of Config with
+// resolved values. It returns original values of changed fields keyed by config
+// path and resolved secret values.
+func resolveSecrets(conf *Config) (map[string]string, []string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), secretResolveTimeout)
+	defer cancel()
+	refs := map[string]string{}
+	var secrets []string
+	err := walkConfigStrings(reflect.ValueOf(conf).Elem(), "", func(path string, value string) (string, bool, error) {
+		if !secretRefRegex.MatchString(value) {
+			return "", false, nil
+		}
+		resolved, err := resolveSecretValue(ctx, value, func(secret string) {
+			if secret != "" && !slices.Contains(secrets, secret) {
+				secrets = append(secrets, secret)
+			}
+		})
+		if err != nil {
+			return "", false, fmt.Errorf("error resolving secret in %s: %w", path, err)
+		}
+		if IsSecretReference(value) {
+			refs[path] = value
+		}
+		return resolved, true, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return refs, secrets, nil
+}
+
+func resolveSecretValue(ctx context.Context, value string, onSecret func(string)) (string, error) {
+	var resolveErr error
+	resolved := secretRefRegex.ReplaceAllStringFunc(value, func(match string) string {
+		if resolveErr != nil {
+			return match
+		}
+		submatches := secretRefRegex.FindStringSubmatch(match)
+		if submatches[1] != "" {
+			// Escaped reference, strip escaping $.
+			return match[1:]
+		}
+		scheme, ref := submatches[2], submatches[3]
+		p, ok := getSecretProvider(scheme)
+		if !ok {
+			resolveErr = fmt.Errorf("unknown secret provider %q", scheme)
+			return match
+		}
+		if ref == "" {
+			resolveErr = fmt.Errorf("empty %s secret reference", scheme)
+			return match
+		}
+		secret, err := p.Resolve(ctx, ref)
+		if err != nil {
+			resolveErr = fmt.Errorf("%s provider: %w", scheme, err)
+			return match
+		}
+		onSecret(secret)
+		return secret
+	})
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+	return resolved, nil
+}
+
+type stringVisitor func(path string, value string) (string, bool, error)
+
+// walkConfigStrings calls visit for every string value reachable from v: string
+// struct fields, elements of string slices and values of string maps. When visit
+// returns true, the value is replaced.
+func walkConfigStrings(v reflect.Value, path string, visit stringVisitor) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return walkConfigStrings(v.Elem(), path, visit)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldPath := path
+			if key := configKey(field); key != "" {
+				fieldPath = appendKeyPath(path, key)
+			}
+			if err := walkConfigStrings(v.Field(i), fieldPath, visit); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := walkConfigStrings(v.Index(i), path+"["+strconv.Itoa(i)+"]", visit); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String || v.Type().Elem().Kind() != reflect.String {
+			return nil
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			newValue, ok, err := visit(appendKeyPath(path, iter.Key().String()), iter.Value().String())
+			if err != nil {
+				return err
+			}
+			if ok {
+				v.SetMapIndex(iter.Key(), reflect.ValueOf(newValue).Convert(v.Type().Elem()))
+			}
+		}
+	case reflect.String:
+		if !v.CanSet() {
+			return nil
+		}
+		newValue, ok, err := visit(path, v.String())
+		if err != nil {
+			return err
+		}
+		if ok {
+			v.SetString(newValue)
+		}
+	default:
+	}
+	return nil
+}
+
+// configKey returns a key of field in configuration path, empty for squashed fields.
+func configKey(field reflect.StructField) string {
+	tag := field.Tag.Get("mapstructure")
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" && strings.Contains(tag, "squash") {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
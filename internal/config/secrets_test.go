@@ -0,0 +1,137 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeSecretsTestConfig(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+type testSecretProvider struct{}
+
+func (testSecretProvider) Scheme() string { return "testkeyring" }
+
+func (testSecretProvider) Resolve(_ context.Context, ref string) (string, error) {
+	return "keyring-" + ref, nil
+}
+
+func TestSecretReferences(t *testing.T) {
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "hmac")
+	require.NoError(t, os.WriteFile(secretFile, []byte("file-secret\n"), 0600))
+	t.Setenv("TEST_SECRET_API_KEY", "env-secret")
+	t.Setenv("TEST_SECRET_REDIS_PASSWORD", "redis-pass")
+	RegisterSecretProvider(testSecretProvider{})
+
+	configFile := writeSecretsTestConfig(t, `{
+		"client": {"token": {"hmac_secret_key": "${file:`+secretFile+`}"}},
+		"http_api": {"key": "${env:TEST_SECRET_API_KEY}"},
+		"engine": {"type": "redis", "redis": {"address": ["redis://:${env:TEST_SECRET_REDIS_PASSWORD}@localhost:6379"]}},
+		"proxies": [{"name": "backend", "endpoint": "http://localhost:3000", "timeout": "1s", "http": {"static_headers": {"Authorization": "Bearer ${testkeyring:proxy}"}}}]
+	}`)
+
+	t.Run("resolved", func(t *testing.T) {
+		conf, meta, err := GetConfig(nil, configFile)
+		require.NoError(t, err)
+		require.Equal(t, "file-secret", conf.Client.Token.HMACSecretKey)
+		require.Equal(t, "env-secret", conf.HttpAPI.Key)
+		require.Equal(t, []string{"redis://:redis-pass@localhost:6379"}, conf.Engine.Redis.Address)
+		require.Len(t, conf.Proxies[0].HTTP.StaticHeaders, 1)
+		for _, v := range conf.Proxies[0].HTTP.StaticHeaders {
+			require.Equal(t, "Bearer keyring-proxy", v)
+		}
+		require.Equal(t, "${env:TEST_SECRET_API_KEY}", meta.SecretRefs["http_api.key"])
+		require.Equal(t, "${file:"+secretFile+"}", meta.SecretRefs["client.token.hmac_secret_key"])
+		require.Len(t, meta.SecretRefs, 4)
+	})
+
+	t.Run("with_references", func(t *testing.T) {
+		conf, meta, err := GetConfigWithSecretReferences(nil, configFile)
+		require.NoError(t, err)
+		require.Equal(t, "${file:"+secretFile+"}", conf.Client.Token.HMACSecretKey)
+		require.Equal(t, "${env:TEST_SECRET_API_KEY}", conf.HttpAPI.Key)
+		require.Empty(t, meta.SecretRefs)
+	})
+
+	t.Run("redacted", func(t *testing.T) {
+		_, meta, err := GetConfig(nil, configFile)
+		require.NoError(t, err)
+		require.Equal(t, "invalid address redis://:"+RedactedValue+"@localhost:6379", meta.RedactSecrets("invalid address redis://:redis-pass@localhost:6379"))
+	})
+
+	t.Run("re_resolved", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(secretFile, []byte("rotated"), 0600))
+		conf, _, err := GetConfig(nil, configFile)
+		require.NoError(t, err)
+		require.Equal(t, "rotated", conf.Client.Token.HMACSecretKey)
+	})
+}
+
+func TestSecretReferencesErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  string
+		errText string
+	}{
+		{
+			name:    "missing_env",
+			config:  `{"http_api": {"key": "${env:TEST_SECRET_NOT_EXISTING}"}}`,
+			errText: "error resolving secret in http_api.key",
+		},
+		{
+			name:    "missing_file",
+			config:  `{"http_api": {"key": "${file:/not/existing/secret}"}}`,
+			errText: "file provider",
+		},
+		{
+			name:    "unknown_provider",
+			config:  `{"http_api": {"key": "${vault:secret/data/api}"}}`,
+			errText: "unknown secret provider \"vault\"",
+		},
+		{
+			name:    "empty_reference",
+			config:  `{"http_api": {"key": "${env:}"}}`,
+			errText: "empty env secret reference",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := GetConfig(nil, writeSecretsTestConfig(t, tc.config))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.errText)
+		})
+	}
+}
+
+func TestIsSecretReference(t *testing.T) {
+	require.True(t, IsSecretReference("${file:/run/secrets/x}"))
+	require.True(t, IsSecretReference("postgres://user:${env:PG_PASSWORD}@localhost/db"))
+	require.False(t, IsSecretReference("${CENTRIFUGO_VAR_X}"))
+	require.False(t, IsSecretReference("plain"))
+	require.False(t, IsSecretReference("$${env:NOT_A_SECRET}"))
+	require.True(t, IsSecretReference("$${env:NOT_A_SECRET}${env:SECRET}"))
+}
+
+func TestSecretReferencesEscaped(t *testing.T) {
+	t.Setenv("TEST_SECRET_API_KEY", "env-secret")
+	configFile := writeSecretsTestConfig(t, `{
+		"http_api": {"key": "$${env:TEST_SECRET_API_KEY}"},
+		"grpc_api": {"key": "$${env:TEST_SECRET_API_KEY}-${env:TEST_SECRET_API_KEY}"}
+	}`)
+	conf, meta, err := GetConfig(nil, configFile)
+	require.NoError(t, err)
+	require.Equal(t, "${env:TEST_SECRET_API_KEY}", conf.HttpAPI.Key)
+	require.Equal(t, "${env:TEST_SECRET_API_KEY}-env-secret", conf.GrpcAPI.Key)
+	require.Len(t, meta.SecretRefs, 1)
+	require.Contains(t, meta.SecretRefs, "grpc_api.key")
+}
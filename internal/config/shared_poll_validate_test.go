@@ -238,3 +238,72 @@ func TestSharedPollConfig_VersionlessPublishEnabled(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func sharedPollCoordinatedConfig(coordinationType string) Config {
+	cfg := DefaultConfig()
+	cfg.SharedPoll.HMACSecretKey = "secret"
+	cfg.SharedPoll.Coordination.Type = coordinationType
+	cfg.Channel.Proxy.SharedPollRefresh = sharedPollDefaultProxy()
+	cfg.Channel.Namespaces = []configtypes.ChannelNamespace{
+		{
+			Name: "poll",
+			ChannelOptions: configtypes.ChannelOptions{
+				SubscriptionType: "shared_poll",
+				SharedPoll: configtypes.SharedPollConfig{
+					Mode:            "versioned",
+					RefreshInterval: configtypes.Duration(5 * time.Second),
+					Coordinated:     true,
+				},
+			},
+		},
+	}
+	return cfg
+}
+
+func TestSharedPollConfig_Coordinated(t *testing.T) {
+	t.Run("redis_accepted", func(t *testing.T) {
+		cfg := sharedPollCoordinatedConfig("redis")
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("missing_coordination_type", func(t *testing.T) {
+		cfg := sharedPollCoordinatedConfig("")
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "shared_poll.coordination.type is required")
+	})
+
+	t.Run("unknown_coordination_type", func(t *testing.T) {
+		cfg := sharedPollCoordinatedConfig("etcd")
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unknown shared_poll.coordination.type")
+	})
+
+	t.Run("postgres_requires_controller", func(t *testing.T) {
+		cfg := sharedPollCoordinatedConfig("postgres")
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "requires controller enabled")
+
+		cfg.Controller.Enabled = true
+		cfg.Controller.Type = "postgres"
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("versionless_mode_rejected", func(t *testing.T) {
+		cfg := sharedPollCoordinatedConfig("redis")
+		cfg.Channel.Namespaces[0].SharedPoll.Mode = "versionless"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "coordinated requires versioned mode")
+	})
+
+	t.Run("missing_refresh_interval", func(t *testing.T) {
+		cfg := sharedPollCoordinatedConfig("redis")
+		cfg.Channel.Namespaces[0].SharedPoll.RefreshInterval = 0
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "requires refresh_interval")
+	})
+}
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"net/url"
 	"regexp"
 	"slices"
 	"strconv"
@@ -10,6 +12,7 @@ import (
 	"time"
 
 	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/mapfilter"
 	"github.com/centrifugal/centrifugo/v6/internal/tools"
 
 	"github.com/centrifugal/centrifuge"
@@ -53,6 +56,16 @@ func (c Config) Validate() error {
 		return fmt.Errorf("unknown channel.publication_data_format: \"%s\"", c.Channel.PublicationDataFormat)
 	}
 
+	if err := validateConnectionQuota(c.Client.ConnectionQuota); err != nil {
+		return fmt.Errorf("in client.connection_quota: %v", err)
+	}
+
+	if channelStateProxyEnabled(c) {
+		if err := validateChannelStateEvents(c.Channel.StateEvents); err != nil {
+			return fmt.Errorf("in channel.state_events: %v", err)
+		}
+	}
+
 	if err := validateCodeToUniDisconnectTransforms(c.Client.ConnectCodeToUnidirectionalDisconnect.Transforms); err != nil {
 		return fmt.Errorf("in client.connect_code_to_unidirectional_disconnect: %v", err)
 	}
@@ -72,6 +85,10 @@ func (c Config) Validate() error {
 	}
 	proxyNames = append(proxyNames, DefaultProxyName) // channel options can use default proxy name.
 
+	if err := validateEncryptionKeys(c.Channel.Encryption.Keys); err != nil {
+		return fmt.Errorf("in channel.encryption: %v", err)
+	}
+
 	if err := validateChannelOptions(c.Channel.WithoutNamespace, c.Channel.HistoryMetaTTL, proxyNames, c); err != nil {
 		return fmt.Errorf("in channel.without_namespace: %v", err)
 	}
@@ -112,6 +129,12 @@ func (c Config) Validate() error {
 		return fmt.Errorf("namespace for user personal channel not found: %s", personalChannelNamespace)
 	}
 
+	if c.Tenancy.Enabled {
+		if err := validateTenants(c, proxyNames); err != nil {
+			return err
+		}
+	}
+
 	// Validate that map presence channel prefix references point to existing namespaces with map type.
 	boundary := c.Channel.NamespaceBoundary
 	if boundary == "" {
@@ -143,6 +166,15 @@ func (c Config) Validate() error {
 		rpcNss = append(rpcNss, n.Name)
 	}
 
+	if c.RPC.HistoryRange.Enabled {
+		if c.RPC.HistoryRange.Method == "" {
+			return errors.New("in rpc.history_range: method must be set")
+		}
+		if c.RPC.Ping.Enabled && c.RPC.Ping.Method == c.RPC.HistoryRange.Method {
+			return fmt.Errorf("in rpc.history_range: method %s is already used by rpc.ping", c.RPC.HistoryRange.Method)
+		}
+	}
+
 	var consumerNames []string
 	for _, config := range c.Consumers {
 		if !consumerNameRe.Match([]byte(config.Name)) {
@@ -186,6 +218,71 @@ func (c Config) Validate() error {
 	if err := validateConnectCodeTransforms(c.UniHTTPStream.ConnectCodeToHTTPResponse.Transforms); err != nil {
 		return fmt.Errorf("in uni_http_stream.connect_code_to_http_status.transforms: %v", err)
 	}
+	if err := validateWriteCoalescing(c.UniSSE.WriteCoalescing); err != nil {
+		return fmt.Errorf("in uni_sse.write_coalescing: %v", err)
+	}
+	if err := validateWriteCoalescing(c.UniHTTPStream.WriteCoalescing); err != nil {
+		return fmt.Errorf("in uni_http_stream.write_coalescing: %v", err)
+	}
+	if err := validateWriteCoalescing(c.SSE.WriteCoalescing); err != nil {
+		return fmt.Errorf("in sse.write_coalescing: %v", err)
+	}
+	if err := validateWriteCoalescing(c.HTTPStream.WriteCoalescing); err != nil {
+		return fmt.Errorf("in http_stream.write_coalescing: %v", err)
+	}
+	if err := validateDrain(c.Shutdown.Drain, c.Shutdown.Timeout); err != nil {
+		return fmt.Errorf("in shutdown.drain: %v", err)
+	}
+	if err := validateNamedAPIKeys(c.HttpAPI.Key, c.HttpAPI.NamedKeys); err != nil {
+		return fmt.Errorf("in http_api.named_keys: %v", err)
+	}
+	if err := validateNamedAPIKeys(c.GrpcAPI.Key, c.GrpcAPI.NamedKeys); err != nil {
+		return fmt.Errorf("in grpc_api.named_keys: %v", err)
+	}
+	if c.Admin.Enabled && c.Admin.SessionTTL <= 0 {
+		return errors.New("admin.session_ttl must be positive")
+	}
+	if err := validateAdminOIDC(c.Admin); err != nil {
+		return fmt.Errorf("in admin.oidc: %v", err)
+	}
+	if err := validateAudit(c.Audit); err != nil {
+		return fmt.Errorf("in audit: %v", err)
+	}
+	if err := validateScheduledPublications(c.ScheduledPublications, c.Broker); err != nil {
+		return fmt.Errorf("in scheduled_publications: %v", err)
+	}
+	if err := validateHistoryArchive(c.HistoryArchive, c.Broker); err != nil {
+		return fmt.Errorf("in history_archive: %v", err)
+	}
+	if c.BidiGRPC.Enabled {
+		pingInterval, pongTimeout := c.BidiGRPC.PingPong.PingInterval, c.BidiGRPC.PingPong.PongTimeout
+		if pingInterval > 0 && pongTimeout > 0 && pingInterval <= pongTimeout {
+			return fmt.Errorf("in bidi_grpc.ping_pong: ping_interval (%s) must be greater than pong_timeout (%s)", pingInterval, pongTimeout)
+		}
+		keepalive := c.BidiGRPC.Keepalive
+		if keepalive.Time < 0 || keepalive.Timeout < 0 || keepalive.MinTime < 0 {
+			return errors.New("in bidi_grpc.keepalive: durations can not be negative")
+		}
+	}
+
+	if err := validateCompressionContext(c.WebSocket.CompressionContext, c.WebSocket.Compression); err != nil {
+		return fmt.Errorf("in websocket.compression_context: %v", err)
+	}
+	if err := validateCompressionContext(c.UniWS.CompressionContext, c.UniWS.Compression); err != nil {
+		return fmt.Errorf("in uni_websocket.compression_context: %v", err)
+	}
+	if c.Channel.WithoutNamespace.WebSocketCompressionDictionaryFile != "" {
+		return errors.New("websocket_compression_dictionary_file can only be set for namespaces")
+	}
+
+	if c.LongPoll.Enabled {
+		if c.LongPoll.PollTimeout <= 0 {
+			return errors.New("long_poll.poll_timeout must be positive")
+		}
+		if c.LongPoll.SessionTimeout <= c.LongPoll.PollTimeout {
+			return errors.New("long_poll.session_timeout must be greater than long_poll.poll_timeout")
+		}
+	}
 
 	// Map broker validation.
 	var knownMapBrokers = []string{"memory", "redis", "postgres"}
@@ -246,6 +343,9 @@ func validateChannelOptions(c configtypes.ChannelOptions, globalHistoryMetaTTL c
 	if c.AutoCacheRecover && (!c.ForceRecovery || c.ForceRecoveryMode != "cache") {
 		return errors.New("auto_cache_recover requires force_recovery and force_recovery_mode set to cache")
 	}
+	if c.CacheEmptyProxyEnabled && (!c.ForceRecovery || c.ForceRecoveryMode != "cache") {
+		return errors.New("cache_empty_proxy_enabled requires force_recovery and force_recovery_mode set to cache")
+	}
 	if c.ChannelRegex != "" {
 		if _, err := regexp.Compile(c.ChannelRegex); err != nil {
 			return fmt.Errorf("invalid channel regex %s: %w", c.ChannelRegex, err)
@@ -254,6 +354,9 @@ func validateChannelOptions(c configtypes.ChannelOptions, globalHistoryMetaTTL c
 	if (c.SubscribeStreamProxyEnabled) && (c.SubscribeProxyEnabled || c.PublishProxyEnabled || c.SubRefreshProxyEnabled) {
 		return fmt.Errorf("can't use subscribe stream proxy together with subscribe, publish or sub refresh proxies")
 	}
+	if c.SubscribeStreamProxyEnabled && c.PublishForCaps {
+		return errors.New("can't use subscribe stream proxy together with allow_publish_for_caps")
+	}
 	if len(c.AllowedDeltaTypes) > 0 {
 		for _, dt := range c.AllowedDeltaTypes {
 			if !slices.Contains([]centrifuge.DeltaType{centrifuge.DeltaTypeFossil}, dt) {
@@ -269,6 +372,43 @@ func validateChannelOptions(c configtypes.ChannelOptions, globalHistoryMetaTTL c
 		return fmt.Errorf("unknown publication_data_format: \"%s\"", c.PublicationDataFormat)
 	}
 
+	if c.PublicationDataSchema != "" && c.PublicationDataSchemaFile != "" {
+		return errors.New("publication_data_schema and publication_data_schema_file can not be used together")
+	}
+	if (c.PublicationDataSchema != "" || c.PublicationDataSchemaFile != "") && c.PublicationDataFormat == configtypes.PublicationDataFormatBinary {
+		return errors.New("publication data schema can not be used with binary publication_data_format")
+	}
+	if _, err := compileDataSchema(c); err != nil {
+		return err
+	}
+	if c.WebSocketCompressionDictionaryFile != "" {
+		if _, err := loadCompressionDictionary(c.WebSocketCompressionDictionaryFile); err != nil {
+			return err
+		}
+	}
+
+	if c.Throttle.Enabled {
+		if c.Throttle.Interval <= 0 {
+			return errors.New("throttle.interval must be positive when throttle enabled")
+		}
+		if c.SubscriptionType != "" && c.SubscriptionType != "stream" {
+			return fmt.Errorf("throttle is only supported for stream subscription type, got: \"%s\"", c.SubscriptionType)
+		}
+	}
+
+	if c.Encryption.Enabled {
+		if c.SubscriptionType == "shared_poll" {
+			return errors.New("encryption is not supported for shared_poll subscription type")
+		}
+		if len(c.Map.Indexes) > 0 {
+			// Indexed fields are extracted from stored map values which are ciphertext.
+			return errors.New("encryption can not be used together with map.indexes")
+		}
+		if len(cfg.Channel.Encryption.Keys) == 0 && cfg.Channel.Encryption.KeyringFile == "" {
+			return errors.New("encryption requires keys or keyring_file in channel.encryption")
+		}
+	}
+
 	if c.SubscribeProxyName != "" && !slices.Contains(proxyNames, c.SubscribeProxyName) {
 		return fmt.Errorf("subscribe proxy with name \"%s\" not found", c.SubscribeProxyName)
 	}
@@ -323,6 +463,24 @@ func validateChannelOptions(c configtypes.ChannelOptions, globalHistoryMetaTTL c
 		}
 	}
 
+	if c.ChannelStateProxyName != "" && !slices.Contains(proxyNames, c.ChannelStateProxyName) {
+		return fmt.Errorf("channel state proxy with name \"%s\" not found", c.ChannelStateProxyName)
+	}
+	if c.ChannelStateProxyEnabled && c.ChannelStateProxyName == DefaultProxyName {
+		if err := validateProxy("default", cfg.Channel.Proxy.ChannelState); err != nil {
+			return fmt.Errorf("in channel.proxy.channel_state: %v", err)
+		}
+	}
+
+	if c.CacheEmptyProxyName != "" && !slices.Contains(proxyNames, c.CacheEmptyProxyName) {
+		return fmt.Errorf("cache empty proxy with name \"%s\" not found", c.CacheEmptyProxyName)
+	}
+	if c.CacheEmptyProxyEnabled && c.CacheEmptyProxyName == DefaultProxyName {
+		if err := validateProxy("default", cfg.Channel.Proxy.CacheEmpty); err != nil {
+			return fmt.Errorf("in channel.proxy.cache_empty: %v", err)
+		}
+	}
+
 	if c.Map.ClientKey != "" && !slices.Contains([]string{"client_id", "user_id"}, c.Map.ClientKey) {
 		return fmt.Errorf("unknown map.client_key: %q (valid: \"client_id\", \"user_id\")", c.Map.ClientKey)
 	}
@@ -364,6 +522,25 @@ func validateChannelOptions(c configtypes.ChannelOptions, globalHistoryMetaTTL c
 		if c.SharedPoll.PublishEnabled && (c.SharedPoll.Mode == "" || c.SharedPoll.Mode == "versionless") {
 			return fmt.Errorf("shared_poll publish_enabled is incompatible with versionless mode (requires explicit versions)")
 		}
+		if c.SharedPoll.Coordinated {
+			if c.SharedPoll.Mode != "versioned" {
+				return fmt.Errorf("shared_poll coordinated requires versioned mode")
+			}
+			if c.SharedPoll.RefreshInterval <= 0 {
+				return fmt.Errorf("shared_poll coordinated requires refresh_interval to be set")
+			}
+			switch cfg.SharedPoll.Coordination.Type {
+			case "redis":
+			case "postgres":
+				if !cfg.Controller.Enabled || cfg.Controller.Type != "postgres" {
+					return fmt.Errorf("shared_poll.coordination.type \"postgres\" requires controller enabled with type \"postgres\"")
+				}
+			case "":
+				return fmt.Errorf("shared_poll.coordination.type is required when shared_poll coordinated is on")
+			default:
+				return fmt.Errorf("unknown shared_poll.coordination.type: %q", cfg.SharedPoll.Coordination.Type)
+			}
+		}
 	}
 	if c.Map.Mode != "" && !slices.Contains([]string{"ephemeral", "recoverable", "persistent"}, c.Map.Mode) {
 		return fmt.Errorf("unknown map.mode: %q (valid: \"ephemeral\", \"recoverable\", \"persistent\")", c.Map.Mode)
@@ -429,6 +606,25 @@ func validateChannelOptions(c configtypes.ChannelOptions, globalHistoryMetaTTL c
 	if c.Map.RemoveClientOnUnsubscribe && !hasMapType {
 		return fmt.Errorf("map.remove_client_on_unsubscribe requires subscription_type to be a map type")
 	}
+	if len(c.Map.Indexes) > 0 {
+		if !hasMapType {
+			return fmt.Errorf("map.indexes requires subscription_type to be a map type")
+		}
+		if err := mapfilter.ValidateIndexes(c.Map.Indexes.FilterIndexes()); err != nil {
+			return fmt.Errorf("in map.indexes: %w", err)
+		}
+	}
+	if c.Map.IndexTags {
+		if len(c.Map.Indexes) == 0 {
+			return fmt.Errorf("map.index_tags requires map.indexes")
+		}
+		if !c.AllowTagsFilter {
+			return fmt.Errorf("map.index_tags requires allow_tags_filter")
+		}
+	}
+	if c.Map.FilterScanLimit < 0 {
+		return fmt.Errorf("map.filter_scan_limit can not be negative")
+	}
 
 	// Map presence prefixes must resolve to a namespace whose subscription_type
 	// is the matching map-presence flavour. Otherwise publishes to those
@@ -508,6 +704,10 @@ var proxyNameRe = regexp.MustCompile(proxyNamePattern)
 var consumerNamePattern = "^[a-zA-Z0-9_]{2,}$"
 var consumerNameRe = regexp.MustCompile(consumerNamePattern)
 
+// sqlIdentifierRe matches table names which are safe to use in queries without quoting,
+// optionally qualified with schema name.
+var sqlIdentifierRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)?$`)
+
 func validateSecondPrecisionDuration(duration configtypes.Duration) error {
 	if duration > 0 && duration.ToDuration()%time.Second != 0 {
 		return fmt.Errorf("malformed duration %s, sub-second precision is not supported for this key", duration)
@@ -547,6 +747,225 @@ func validateTokens(cfg Config) error {
 			return fmt.Errorf("invalid subscription token custom user ID claim: %s, must match %s regular expression", cfg.Client.SubscriptionToken.UserIDClaim, customClaimRe.String())
 		}
 	}
+	if cfg.Client.TokenIntrospection.Enabled {
+		if cfg.Client.TokenIntrospection.Endpoint == "" {
+			return errors.New("token introspection endpoint is required when token introspection is enabled")
+		}
+		if cfg.Client.TokenIntrospection.Timeout <= 0 {
+			return errors.New("token introspection timeout must be positive")
+		}
+		if cfg.Client.TokenIntrospection.CacheMaxTTL < 0 {
+			return errors.New("token introspection cache_max_ttl can not be negative")
+		}
+	}
+	return nil
+}
+
+func validateEncryptionKeys(keys configtypes.EncryptionKeys) error {
+	ids := make([]string, 0, len(keys))
+	for i, key := range keys {
+		if key.ID == "" {
+			return fmt.Errorf("keys[%d]: id is required", i)
+		}
+		if slices.Contains(ids, key.ID) {
+			return fmt.Errorf("keys[%d]: duplicate key id: %s", i, key.ID)
+		}
+		ids = append(ids, key.ID)
+		decoded, err := base64.StdEncoding.DecodeString(key.Key)
+		if err != nil {
+			return fmt.Errorf("keys[%d]: key must be base64 encoded: %v", i, err)
+		}
+		if !slices.Contains([]int{16, 24, 32}, len(decoded)) {
+			return fmt.Errorf("keys[%d]: key must be 16, 24 or 32 bytes, got %d", i, len(decoded))
+		}
+	}
+	return nil
+}
+
+func validateConnectionQuota(q configtypes.ConnectionQuota) error {
+	if q.UserLimit < 0 || q.IPLimit < 0 {
+		return errors.New("limits can not be negative")
+	}
+	if !q.Enabled() {
+		return nil
+	}
+	if !slices.Contains([]string{"reject", "evict_oldest"}, q.Policy) {
+		return fmt.Errorf("unknown policy: %q", q.Policy)
+	}
+	if !slices.Contains([]string{"presence", "redis"}, q.Type) {
+		return fmt.Errorf("unknown type: %q", q.Type)
+	}
+	if q.TTL <= 0 {
+		return errors.New("ttl must be positive")
+	}
+	if len(q.TrustedProxies) > 0 && q.IPHeader == "" {
+		return errors.New("trusted_proxies requires ip_header")
+	}
+	if _, err := tools.ParseTrustedProxies(q.TrustedProxies); err != nil {
+		return err
+	}
+	return nil
+}
+
+func validateTenants(c Config, proxyNames []string) error {
+	if !slices.Contains([]string{"host", "path"}, c.Tenancy.Resolve) {
+		return fmt.Errorf("in tenancy: unknown resolve: %q", c.Tenancy.Resolve)
+	}
+	if c.Tenancy.Resolve == "path" && !strings.HasPrefix(c.Tenancy.PathPrefix, "/") {
+		return errors.New("in tenancy: path_prefix must start with /")
+	}
+	boundary := c.Tenancy.ChannelBoundary
+	if boundary == "" {
+		return errors.New("in tenancy: channel_boundary is required")
+	}
+	if boundary == c.Channel.NamespaceBoundary {
+		return errors.New("in tenancy: channel_boundary must differ from channel.namespace_boundary")
+	}
+	if len(c.Tenants) == 0 {
+		return errors.New("tenancy enabled but no tenants configured")
+	}
+	var names, hosts, apiKeys []string
+	for _, t := range c.Tenants {
+		if !nameRe.MatchString(t.Name) || strings.Contains(t.Name, boundary) {
+			return fmt.Errorf("invalid tenant name – %s (must match %s regular expression and must not contain channel boundary)", t.Name, namePattern)
+		}
+		if slices.Contains(names, t.Name) {
+			return fmt.Errorf("tenant name must be unique: %s", t.Name)
+		}
+		names = append(names, t.Name)
+		if err := validateTenant(c, t, proxyNames); err != nil {
+			return fmt.Errorf("tenant %s: %v", t.Name, err)
+		}
+		for _, host := range t.Hosts {
+			if slices.Contains(hosts, host) {
+				return fmt.Errorf("tenant %s: host belongs to several tenants: %s", t.Name, host)
+			}
+			hosts = append(hosts, host)
+		}
+		if t.APIKey != "" {
+			if t.APIKey == c.HttpAPI.Key || slices.Contains(apiKeys, t.APIKey) || slices.ContainsFunc(c.HttpAPI.NamedKeys, func(k configtypes.NamedAPIKey) bool {
+				return k.Key == t.APIKey
+			}) {
+				return fmt.Errorf("tenant %s: api_key must be unique", t.Name)
+			}
+			apiKeys = append(apiKeys, t.APIKey)
+		}
+	}
+	return nil
+}
+
+func validateNamedAPIKeys(mainKey string, keys []configtypes.NamedAPIKey) error {
+	var names, values []string
+	for _, k := range keys {
+		if !nameRe.MatchString(k.Name) {
+			return fmt.Errorf("invalid key name – %s (must match %s regular expression)", k.Name, namePattern)
+		}
+		if slices.Contains(names, k.Name) {
+			return fmt.Errorf("key name must be unique: %s", k.Name)
+		}
+		names = append(names, k.Name)
+		if k.Key == "" {
+			return fmt.Errorf("key %s: key value is required", k.Name)
+		}
+		if k.Key == mainKey || slices.Contains(values, k.Key) {
+			return fmt.Errorf("key %s: key value must be unique", k.Name)
+		}
+		values = append(values, k.Key)
+	}
+	return nil
+}
+
+func validateTenant(c Config, t configtypes.Tenant, proxyNames []string) error {
+	if c.Tenancy.Resolve == "host" && len(t.Hosts) == 0 {
+		return errors.New("hosts required when tenancy resolve is host")
+	}
+	if t.Token.UserIDClaim != "" && !customClaimRe.MatchString(t.Token.UserIDClaim) {
+		return fmt.Errorf("invalid token custom user ID claim: %s, must match %s regular expression", t.Token.UserIDClaim, customClaimRe.String())
+	}
+	if t.ConnectionLimit < 0 || t.ChannelLimit < 0 || t.PublishRateLimit < 0 {
+		return errors.New("limits can not be negative")
+	}
+	nss := make([]string, 0, len(t.Namespaces))
+	for _, n := range t.Namespaces {
+		if slices.Contains(nss, n.Name) {
+			return fmt.Errorf("namespace name must be unique: %s", n.Name)
+		}
+		if option := unsupportedTenantNamespaceOption(n.ChannelOptions); option != "" {
+			return fmt.Errorf("namespace %s: %s is not supported in tenant namespaces", n.Name, option)
+		}
+		if err := validateNamespace(n, c.Channel.HistoryMetaTTL, proxyNames, c); err != nil {
+			return fmt.Errorf("namespace %s: %v", n.Name, err)
+		}
+		nss = append(nss, n.Name)
+	}
+	personalChannelNamespace := c.Client.SubscribeToUserPersonalChannel.PersonalChannelNamespace
+	if c.Client.SubscribeToUserPersonalChannel.Enabled && personalChannelNamespace != "" && !slices.Contains(nss, personalChannelNamespace) {
+		return fmt.Errorf("namespace for user personal channel not found: %s", personalChannelNamespace)
+	}
+	return nil
+}
+
+// unsupportedTenantNamespaceOption returns name of option which requires node-level
+// setup made only for channel.namespaces, empty string if there is no such option.
+func unsupportedTenantNamespaceOption(o configtypes.ChannelOptions) string {
+	switch {
+	case o.SubscribeProxyEnabled:
+		return "subscribe_proxy_enabled"
+	case o.PublishProxyEnabled:
+		return "publish_proxy_enabled"
+	case o.SubRefreshProxyEnabled:
+		return "sub_refresh_proxy_enabled"
+	case o.SubscribeStreamProxyEnabled:
+		return "subscribe_stream_proxy_enabled"
+	case o.ChannelStateProxyEnabled:
+		return "channel_state_proxy_enabled"
+	case o.CacheEmptyProxyEnabled:
+		return "cache_empty_proxy_enabled"
+	case o.Map.PublishProxyEnabled:
+		return "map.publish_proxy_enabled"
+	case o.Map.RemoveProxyEnabled:
+		return "map.remove_proxy_enabled"
+	case len(o.Map.Indexes) > 0:
+		return "map.indexes"
+	case o.MapClientsPresenceChannelPrefix != "":
+		return "map_clients_presence_channel_prefix"
+	case o.MapUsersPresenceChannelPrefix != "":
+		return "map_users_presence_channel_prefix"
+	case o.SubscriptionType == "shared_poll":
+		return "shared_poll subscription type"
+	case o.Encryption.Enabled:
+		return "encryption"
+	case o.WebSocketCompressionDictionaryFile != "":
+		return "websocket_compression_dictionary_file"
+	}
+	return ""
+}
+
+func channelStateProxyEnabled(c Config) bool {
+	if c.Channel.WithoutNamespace.ChannelStateProxyEnabled {
+		return true
+	}
+	for _, ns := range c.Channel.Namespaces {
+		if ns.ChannelStateProxyEnabled {
+			return true
+		}
+	}
+	return false
+}
+
+func validateChannelStateEvents(c configtypes.ChannelStateEvents) error {
+	if !slices.Contains([]string{"memory", "redis"}, c.Type) {
+		return fmt.Errorf("unknown type: %q", c.Type)
+	}
+	if c.TTL <= 0 {
+		return errors.New("ttl must be positive")
+	}
+	if c.BatchSize < 0 {
+		return errors.New("batch_size can not be negative")
+	}
+	if c.BatchMaxDelay < 0 {
+		return errors.New("batch_max_delay can not be negative")
+	}
 	return nil
 }
 
@@ -604,3 +1023,213 @@ func validateCodeToUniDisconnectTransforms(transforms configtypes.UniConnectCode
 	}
 	return nil
 }
+
+func validateWriteCoalescing(c configtypes.WriteCoalescing) error {
+	if c.Window < 0 {
+		return errors.New("window can not be negative")
+	}
+	if c.MaxBatchBytes < 0 {
+		return errors.New("max_batch_bytes can not be negative")
+	}
+	return nil
+}
+
+func validateDrain(c configtypes.Drain, shutdownTimeout configtypes.Duration) error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Rate < 0 {
+		return errors.New("rate can not be negative")
+	}
+	if c.MaxRetryAfter < 0 {
+		return errors.New("max_retry_after can not be negative")
+	}
+	if c.Timeout <= 0 {
+		return errors.New("timeout must be positive")
+	}
+	if c.Timeout >= shutdownTimeout {
+		return fmt.Errorf("timeout (%s) must be less than shutdown timeout (%s)", c.Timeout, shutdownTimeout)
+	}
+	return nil
+}
+
+func validateAdminOIDC(c configtypes.Admin) error {
+	if !c.OIDC.Enabled {
+		return nil
+	}
+	if c.Secret == "" {
+		return errors.New("admin secret is required to sign admin sessions")
+	}
+	if c.OIDC.Issuer == "" {
+		return errors.New("issuer is required")
+	}
+	if u, err := url.Parse(c.OIDC.Issuer); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("issuer must be http or https URL: %s", c.OIDC.Issuer)
+	}
+	if c.OIDC.ClientID == "" {
+		return errors.New("client_id is required")
+	}
+	if c.OIDC.RedirectURL == "" {
+		return errors.New("redirect_url is required")
+	}
+	if _, err := url.Parse(c.OIDC.RedirectURL); err != nil {
+		return fmt.Errorf("malformed redirect_url: %v", err)
+	}
+	if c.OIDC.RolesClaim == "" {
+		return errors.New("roles_claim is required")
+	}
+	if len(c.OIDC.ViewerRoles) == 0 && len(c.OIDC.OperatorRoles) == 0 && len(c.OIDC.AdminRoles) == 0 {
+		return errors.New("at least one of viewer_roles, operator_roles or admin_roles must be set")
+	}
+	return nil
+}
+
+func validateAudit(c configtypes.Audit) error {
+	if !c.Enabled {
+		return nil
+	}
+	if !c.File.Enabled && !c.Postgres.Enabled && !c.RedisStream.Enabled {
+		return errors.New("at least one sink must be enabled")
+	}
+	if c.BufferSize <= 0 || c.BatchSize <= 0 {
+		return errors.New("buffer_size and batch_size must be positive")
+	}
+	if c.FlushInterval <= 0 {
+		return errors.New("flush_interval must be positive")
+	}
+	if c.File.Enabled {
+		if c.File.Path == "" {
+			return errors.New("file.path is required")
+		}
+		if c.File.MaxSizeMB <= 0 {
+			return errors.New("file.max_size_mb must be positive")
+		}
+		if c.File.MaxBackups < 0 {
+			return errors.New("file.max_backups can not be negative")
+		}
+	}
+	if c.Postgres.Enabled {
+		if c.Postgres.DSN == "" {
+			return errors.New("postgresql.dsn is required")
+		}
+		if !sqlIdentifierRe.MatchString(c.Postgres.TableName) {
+			return fmt.Errorf("invalid postgresql.table_name: %q", c.Postgres.TableName)
+		}
+	}
+	if c.RedisStream.Enabled && c.RedisStream.Stream == "" {
+		return errors.New("redis_stream.stream is required")
+	}
+	return nil
+}
+
+func validateScheduledPublications(c configtypes.ScheduledPublications, broker configtypes.Broker) error {
+	if !c.Enabled {
+		return nil
+	}
+	switch c.Type {
+	case "redis":
+	case "postgres":
+		if !broker.Enabled || broker.Type != "postgres" {
+			return errors.New("type \"postgres\" requires broker enabled with type \"postgres\"")
+		}
+	default:
+		return fmt.Errorf("unknown type: %q", c.Type)
+	}
+	if c.PollInterval <= 0 {
+		return errors.New("poll_interval must be positive")
+	}
+	if c.BatchSize <= 0 {
+		return errors.New("batch_size must be positive")
+	}
+	if c.ClaimTimeout <= c.PollInterval {
+		return errors.New("claim_timeout must be greater than poll_interval")
+	}
+	if c.MaxDelay <= 0 {
+		return errors.New("max_delay must be positive")
+	}
+	// Publication not completed by a node is fired again by another node after the
+	// claim expires and the node polls. Repeated publish is only suppressed while
+	// broker keeps idempotent result of the first one. Firing of a claimed batch
+	// takes up to claim_timeout, so the interval between two attempts is bounded by
+	// two claim timeouts plus poll interval.
+	idempotentResultTTL := defaultIdempotentResultTTL
+	if broker.Enabled && broker.Type == "postgres" {
+		idempotentResultTTL = broker.Postgres.IdempotentResultTTL.ToDuration()
+	}
+	maxRetryInterval := 2*c.ClaimTimeout.ToDuration() + c.PollInterval.ToDuration()
+	if idempotentResultTTL <= maxRetryInterval {
+		return fmt.Errorf("broker idempotent_result_ttl (%s) must be greater than 2 * claim_timeout + poll_interval (%s)", idempotentResultTTL, maxRetryInterval)
+	}
+	return nil
+}
+
+// defaultIdempotentResultTTL is used by brokers without configurable idempotent
+// result TTL.
+const defaultIdempotentResultTTL = 5 * time.Minute
+
+func validateHistoryArchive(c configtypes.HistoryArchive, broker configtypes.Broker) error {
+	if !c.Enabled {
+		return nil
+	}
+	switch c.Source {
+	case "publications":
+		if c.FlushInterval <= 0 {
+			return errors.New("flush_interval must be positive")
+		}
+		if c.MaxBufferSize <= 0 {
+			return errors.New("max_buffer_size must be positive")
+		}
+		if c.MaxPendingSize < c.MaxBufferSize {
+			return fmt.Errorf("max_pending_size (%d) must not be less than max_buffer_size (%d)", c.MaxPendingSize, c.MaxBufferSize)
+		}
+	case "postgres_partitions":
+		if !broker.Enabled || broker.Type != "postgres" {
+			return errors.New("source \"postgres_partitions\" requires broker enabled with type \"postgres\"")
+		}
+		if broker.Postgres.PartitionRetentionDays <= 0 {
+			return errors.New("source \"postgres_partitions\" requires broker.postgres.partition_retention_days to be positive")
+		}
+	default:
+		return fmt.Errorf("unknown source: %q", c.Source)
+	}
+	switch c.Storage {
+	case "local":
+		if c.Local.Dir == "" {
+			return errors.New("local.dir is required")
+		}
+	case "s3":
+		if c.S3.Bucket == "" {
+			return errors.New("s3.bucket is required")
+		}
+		if c.S3.Region == "" {
+			return errors.New("s3.region is required")
+		}
+		if (c.S3.AccessKeyID == "") != (c.S3.SecretAccessKey == "") {
+			return errors.New("s3.access_key_id and s3.secret_access_key must be set together")
+		}
+	default:
+		return fmt.Errorf("unknown storage: %q", c.Storage)
+	}
+	switch c.Compression {
+	case "gzip", "zstd":
+	default:
+		return fmt.Errorf("unknown compression: %q", c.Compression)
+	}
+	return nil
+}
+
+func validateCompressionContext(c configtypes.WebSocketCompressionContext, compression bool) error {
+	if !c.Enabled {
+		return nil
+	}
+	if !compression {
+		return errors.New("compression must be enabled to use compression context takeover")
+	}
+	if c.WindowBits != 0 && (c.WindowBits < 9 || c.WindowBits > 15) {
+		return fmt.Errorf("window_bits must be in range from 9 to 15, got %d", c.WindowBits)
+	}
+	if c.MemoryLimit < 0 {
+		return errors.New("memory_limit can not be negative")
+	}
+	return nil
+}
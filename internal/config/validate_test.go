@@ -1,6 +1,9 @@
 package config
 
 import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -265,6 +268,49 @@ func TestValidateAutoCacheRecover(t *testing.T) {
 	})
 }
 
+func TestValidatePublishForCaps(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Channel.WithoutNamespace.PublishForCaps = true
+	require.NoError(t, cfg.Validate())
+
+	cfg.Channel.Proxy.SubscribeStream.Endpoint = "grpc://localhost:12000"
+	cfg.Channel.WithoutNamespace.SubscribeStreamProxyEnabled = true
+	err := cfg.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "allow_publish_for_caps")
+}
+
+func TestValidateThrottle(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		cfg := DefaultConfig()
+		opts := &cfg.Channel.WithoutNamespace
+		opts.Throttle.Enabled = true
+		opts.Throttle.Interval = configtypes.Duration(100 * time.Millisecond)
+		opts.Throttle.TagKey = "symbol"
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("requires_interval", func(t *testing.T) {
+		cfg := DefaultConfig()
+		opts := &cfg.Channel.WithoutNamespace
+		opts.Throttle.Enabled = true
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "throttle.interval must be positive")
+	})
+
+	t.Run("stream_only", func(t *testing.T) {
+		cfg := DefaultConfig()
+		opts := &cfg.Channel.WithoutNamespace
+		opts.SubscriptionType = "shared_poll"
+		opts.Throttle.Enabled = true
+		opts.Throttle.Interval = configtypes.Duration(100 * time.Millisecond)
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "throttle is only supported for stream subscription type")
+	})
+}
+
 func TestValidateMapNamespace_Recoverable(t *testing.T) {
 	t.Run("valid_minimal_defaults_zero", func(t *testing.T) {
 		// Recoverable with all stream options at zero is valid
@@ -400,6 +446,56 @@ func TestValidateMapNamespace_Persistent(t *testing.T) {
 	})
 }
 
+func TestValidateMapNamespace_Indexes(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		cfg := mapDefaultConfig()
+		ns := mapNamespace("orders", "persistent")
+		ns.Map.Indexes = configtypes.MapIndexes{
+			{Name: "status", Field: "status"},
+			{Name: "updated_at", Field: "meta.updated_at", Type: "number"},
+		}
+		cfg.Channel.Namespaces = []configtypes.ChannelNamespace{ns}
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("invalid_index", func(t *testing.T) {
+		cfg := mapDefaultConfig()
+		ns := mapNamespace("orders", "persistent")
+		ns.Map.Indexes = configtypes.MapIndexes{{Name: "status", Field: "status", Type: "bool"}}
+		cfg.Channel.Namespaces = []configtypes.ChannelNamespace{ns}
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "in map.indexes")
+	})
+
+	t.Run("requires_map_type", func(t *testing.T) {
+		cfg := mapDefaultConfig()
+		cfg.Channel.WithoutNamespace.Map.Indexes = configtypes.MapIndexes{{Name: "status", Field: "status"}}
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "map.indexes requires subscription_type to be a map type")
+	})
+
+	t.Run("index_tags", func(t *testing.T) {
+		cfg := mapDefaultConfig()
+		ns := mapNamespace("orders", "persistent")
+		ns.Map.Indexes = configtypes.MapIndexes{{Name: "status", Field: "status"}}
+		ns.Map.IndexTags = true
+		cfg.Channel.Namespaces = []configtypes.ChannelNamespace{ns}
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "map.index_tags requires allow_tags_filter")
+
+		cfg.Channel.Namespaces[0].AllowTagsFilter = true
+		require.NoError(t, cfg.Validate())
+
+		cfg.Channel.Namespaces[0].Map.Indexes = nil
+		err = cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "map.index_tags requires map.indexes")
+	})
+}
+
 func TestValidateMapNamespace_RequiredFields(t *testing.T) {
 	t.Run("missing_mode", func(t *testing.T) {
 		cfg := mapDefaultConfig()
@@ -607,3 +703,889 @@ func TestValidateMapNamespace_MapBrokerType(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateLongPoll(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.LongPoll.Enabled = true
+		cfg.LongPoll.PollTimeout = configtypes.Duration(25 * time.Second)
+		cfg.LongPoll.SessionTimeout = configtypes.Duration(time.Minute)
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("requires_poll_timeout", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.LongPoll.Enabled = true
+		cfg.LongPoll.PollTimeout = 0
+		cfg.LongPoll.SessionTimeout = configtypes.Duration(time.Minute)
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "long_poll.poll_timeout must be positive")
+	})
+
+	t.Run("session_timeout_exceeds_poll_timeout", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.LongPoll.Enabled = true
+		cfg.LongPoll.PollTimeout = configtypes.Duration(25 * time.Second)
+		cfg.LongPoll.SessionTimeout = configtypes.Duration(20 * time.Second)
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "long_poll.session_timeout must be greater")
+	})
+}
+
+func TestValidateTokenIntrospection(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Client.TokenIntrospection.Enabled = true
+		cfg.Client.TokenIntrospection.Endpoint = "https://auth.example.com/introspect"
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("requires_endpoint", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Client.TokenIntrospection.Enabled = true
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "token introspection endpoint is required")
+	})
+
+	t.Run("requires_timeout", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Client.TokenIntrospection.Enabled = true
+		cfg.Client.TokenIntrospection.Endpoint = "https://auth.example.com/introspect"
+		cfg.Client.TokenIntrospection.Timeout = 0
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "token introspection timeout must be positive")
+	})
+}
+
+func TestValidateConnectionQuota(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Client.ConnectionQuota.UserLimit = 10
+		cfg.Client.ConnectionQuota.Policy = "evict_oldest"
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("negative_limit", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Client.ConnectionQuota.IPLimit = -1
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "limits can not be negative")
+	})
+
+	t.Run("unknown_policy", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Client.ConnectionQuota.UserLimit = 10
+		cfg.Client.ConnectionQuota.Policy = "evict_newest"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unknown policy")
+	})
+
+	t.Run("unknown_type", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Client.ConnectionQuota.IPLimit = 10
+		cfg.Client.ConnectionQuota.Type = "memory"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unknown type")
+	})
+
+	t.Run("requires_ttl", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Client.ConnectionQuota.IPLimit = 10
+		cfg.Client.ConnectionQuota.TTL = 0
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ttl must be positive")
+	})
+
+	t.Run("trusted_proxies", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Client.ConnectionQuota.IPLimit = 10
+		cfg.Client.ConnectionQuota.IPHeader = "X-Forwarded-For"
+		cfg.Client.ConnectionQuota.TrustedProxies = []string{"10.0.0.0/8", "192.168.1.1", "::1"}
+		require.NoError(t, cfg.Validate())
+
+		cfg.Client.ConnectionQuota.TrustedProxies = []string{"10.0.0.0/33"}
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid trusted proxy")
+
+		cfg.Client.ConnectionQuota.IPHeader = ""
+		cfg.Client.ConnectionQuota.TrustedProxies = []string{"10.0.0.1"}
+		err = cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "trusted_proxies requires ip_header")
+	})
+}
+
+func TestValidateChannelEncryption(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(make([]byte, 32))
+
+	t.Run("valid", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Channel.Encryption.Keys = configtypes.EncryptionKeys{{ID: "k1", Key: key}}
+		cfg.Channel.Namespaces = []configtypes.ChannelNamespace{
+			{Name: "secret", ChannelOptions: configtypes.ChannelOptions{Encryption: configtypes.EncryptionConfig{Enabled: true}}},
+		}
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("requires_keys", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Channel.Namespaces = []configtypes.ChannelNamespace{
+			{Name: "secret", ChannelOptions: configtypes.ChannelOptions{Encryption: configtypes.EncryptionConfig{Enabled: true}}},
+		}
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "encryption requires keys")
+	})
+
+	t.Run("map", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Channel.Encryption.KeyringFile = "keyring.json"
+		cfg.Channel.WithoutNamespace.Encryption.Enabled = true
+		cfg.Channel.WithoutNamespace.SubscriptionType = "map"
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("map_indexes", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Channel.Encryption.KeyringFile = "keyring.json"
+		cfg.Channel.WithoutNamespace.Encryption.Enabled = true
+		cfg.Channel.WithoutNamespace.SubscriptionType = "map"
+		cfg.Channel.WithoutNamespace.Map.Indexes = configtypes.MapIndexes{{Name: "status", Field: "status"}}
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "encryption can not be used together with map.indexes")
+	})
+
+	t.Run("shared_poll", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Channel.Encryption.KeyringFile = "keyring.json"
+		cfg.Channel.WithoutNamespace.Encryption.Enabled = true
+		cfg.Channel.WithoutNamespace.SubscriptionType = "shared_poll"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "encryption is not supported for shared_poll subscription type")
+	})
+
+	t.Run("invalid_key", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Channel.Encryption.Keys = configtypes.EncryptionKeys{{ID: "k1", Key: base64.StdEncoding.EncodeToString(make([]byte, 10))}}
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key must be 16, 24 or 32 bytes")
+	})
+
+	t.Run("duplicate_key_id", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Channel.Encryption.Keys = configtypes.EncryptionKeys{{ID: "k1", Key: key}, {ID: "k1", Key: key}}
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "duplicate key id")
+	})
+}
+
+func TestValidatePublicationDataSchemaOptions(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Channel.Namespaces = []configtypes.ChannelNamespace{
+			{Name: "events", ChannelOptions: configtypes.ChannelOptions{PublicationDataSchema: `{"type": "object"}`}},
+		}
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("both_set", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Channel.WithoutNamespace.PublicationDataSchema = `{"type": "object"}`
+		cfg.Channel.WithoutNamespace.PublicationDataSchemaFile = "schema.json"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "can not be used together")
+	})
+
+	t.Run("invalid_schema", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Channel.Namespaces = []configtypes.ChannelNamespace{
+			{Name: "events", ChannelOptions: configtypes.ChannelOptions{PublicationDataSchema: `{"type": 1}`}},
+		}
+		require.Error(t, cfg.Validate())
+	})
+
+	t.Run("binary_format", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Channel.WithoutNamespace.PublicationDataSchema = `{"type": "object"}`
+		cfg.Channel.WithoutNamespace.PublicationDataFormat = configtypes.PublicationDataFormatBinary
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "binary publication_data_format")
+	})
+}
+
+func TestValidateCompressionContext(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.WebSocket.Compression = true
+		cfg.WebSocket.CompressionContext.Enabled = true
+		cfg.WebSocket.CompressionContext.WindowBits = 12
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("compression_disabled", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.WebSocket.CompressionContext.Enabled = true
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "compression must be enabled")
+	})
+
+	t.Run("window_bits_out_of_range", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.UniWS.Compression = true
+		cfg.UniWS.CompressionContext.Enabled = true
+		cfg.UniWS.CompressionContext.WindowBits = 8
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "window_bits")
+	})
+
+	t.Run("dictionary_without_namespace", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Channel.WithoutNamespace.WebSocketCompressionDictionaryFile = "dictionary.json"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "can only be set for namespaces")
+	})
+
+	t.Run("empty_dictionary", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dictionary.json")
+		require.NoError(t, os.WriteFile(path, nil, 0600))
+		cfg := DefaultConfig()
+		cfg.Channel.Namespaces = []configtypes.ChannelNamespace{
+			{Name: "chat", ChannelOptions: configtypes.ChannelOptions{WebSocketCompressionDictionaryFile: path}},
+		}
+		require.Error(t, cfg.Validate())
+	})
+}
+
+func TestValidateNamedAPIKeys(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.HttpAPI.Key = "main"
+		cfg.HttpAPI.NamedKeys = []configtypes.NamedAPIKey{{Name: "billing", Key: "k1"}, {Name: "ops", Key: "k2"}}
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("invalid_name", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.HttpAPI.NamedKeys = []configtypes.NamedAPIKey{{Name: "b", Key: "k1"}}
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "in http_api.named_keys: invalid key name")
+	})
+
+	t.Run("duplicate_name", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.GrpcAPI.NamedKeys = []configtypes.NamedAPIKey{{Name: "billing", Key: "k1"}, {Name: "billing", Key: "k2"}}
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "in grpc_api.named_keys: key name must be unique")
+	})
+
+	t.Run("empty_key", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.HttpAPI.NamedKeys = []configtypes.NamedAPIKey{{Name: "billing"}}
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key value is required")
+	})
+
+	t.Run("same_as_main_key", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.HttpAPI.Key = "main"
+		cfg.HttpAPI.NamedKeys = []configtypes.NamedAPIKey{{Name: "billing", Key: "main"}}
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key value must be unique")
+	})
+}
+
+func TestValidateWriteCoalescing(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.UniSSE.WriteCoalescing.Window = configtypes.Duration(5 * time.Millisecond)
+	require.NoError(t, cfg.Validate())
+
+	cfg = DefaultConfig()
+	cfg.UniHTTPStream.WriteCoalescing.MaxBatchBytes = -1
+	err := cfg.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "uni_http_stream.write_coalescing")
+
+	cfg = DefaultConfig()
+	cfg.SSE.WriteCoalescing.Window = configtypes.Duration(-time.Millisecond)
+	err = cfg.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "sse.write_coalescing")
+
+	cfg = DefaultConfig()
+	cfg.HTTPStream.WriteCoalescing.MaxBatchBytes = -1
+	err = cfg.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "http_stream.write_coalescing")
+}
+
+func TestValidateDrain(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Shutdown.Drain.Enabled = true
+	require.NoError(t, cfg.Validate())
+
+	cfg = DefaultConfig()
+	cfg.Shutdown.Drain.Enabled = true
+	cfg.Shutdown.Drain.Timeout = cfg.Shutdown.Timeout
+	err := cfg.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "shutdown.drain")
+
+	cfg = DefaultConfig()
+	cfg.Shutdown.Drain.Enabled = true
+	cfg.Shutdown.Drain.Rate = -1
+	require.Error(t, cfg.Validate())
+}
+
+func TestValidateChannelStateEvents(t *testing.T) {
+	newConfig := func() Config {
+		cfg := DefaultConfig()
+		cfg.Proxies = []configtypes.NamedProxy{
+			{
+				Name: "feeds",
+				Proxy: configtypes.Proxy{
+					Endpoint: "http://localhost:3001/channel_state",
+					Timeout:  configtypes.Duration(time.Second),
+				},
+			},
+		}
+		cfg.Channel.Namespaces = []configtypes.ChannelNamespace{
+			{
+				Name: "prices",
+				ChannelOptions: configtypes.ChannelOptions{
+					ChannelStateProxyEnabled: true,
+					ChannelStateProxyName:    "feeds",
+				},
+			},
+		}
+		return cfg
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		cfg := newConfig()
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("proxy_not_found", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Channel.Namespaces[0].ChannelStateProxyName = "unknown"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "channel state proxy with name \"unknown\" not found")
+	})
+
+	t.Run("unknown_type", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Channel.StateEvents.Type = "postgres"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "in channel.state_events: unknown type")
+	})
+
+	t.Run("negative_batch_size", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Channel.StateEvents.BatchSize = -1
+		require.Error(t, cfg.Validate())
+	})
+}
+
+func TestValidateCacheEmptyProxy(t *testing.T) {
+	newConfig := func() Config {
+		cfg := DefaultConfig()
+		cfg.Proxies = []configtypes.NamedProxy{
+			{
+				Name: "cache",
+				Proxy: configtypes.Proxy{
+					Endpoint: "http://localhost:3001/cache_empty",
+					Timeout:  configtypes.Duration(time.Second),
+				},
+			},
+		}
+		cfg.Channel.Namespaces = []configtypes.ChannelNamespace{
+			{
+				Name: "tickers",
+				ChannelOptions: configtypes.ChannelOptions{
+					HistorySize:            1,
+					HistoryTTL:             configtypes.Duration(time.Hour),
+					ForceRecovery:          true,
+					ForceRecoveryMode:      "cache",
+					CacheEmptyProxyEnabled: true,
+					CacheEmptyProxyName:    "cache",
+				},
+			},
+		}
+		return cfg
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		cfg := newConfig()
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("proxy_not_found", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Channel.Namespaces[0].CacheEmptyProxyName = "unknown"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cache empty proxy with name \"unknown\" not found")
+	})
+
+	t.Run("requires_cache_mode", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Channel.Namespaces[0].ForceRecoveryMode = "stream"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cache_empty_proxy_enabled requires force_recovery")
+	})
+
+	t.Run("default_proxy_without_endpoint", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Channel.Namespaces[0].CacheEmptyProxyName = "default"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "channel.proxy.cache_empty")
+	})
+}
+
+func TestValidateTenants(t *testing.T) {
+	newConfig := func() Config {
+		cfg := DefaultConfig()
+		cfg.Tenancy.Enabled = true
+		cfg.Tenants = []configtypes.Tenant{
+			{
+				Name:   "acme",
+				Hosts:  []string{"acme.example.com"},
+				APIKey: "acme-key",
+				Token:  configtypes.Token{HMACSecretKey: "acme-secret"},
+				Namespaces: []configtypes.ChannelNamespace{
+					{Name: "chat"},
+				},
+			},
+			{
+				Name:   "globex",
+				Hosts:  []string{"globex.example.com"},
+				APIKey: "globex-key",
+				Token:  configtypes.Token{HMACSecretKey: "globex-secret"},
+			},
+		}
+		return cfg
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		cfg := newConfig()
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("no_tenants", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Tenants = nil
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no tenants configured")
+	})
+
+	t.Run("unknown_resolve", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Tenancy.Resolve = "header"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unknown resolve")
+	})
+
+	t.Run("path_resolve_without_hosts", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Tenancy.Resolve = "path"
+		cfg.Tenants[0].Hosts = nil
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("host_resolve_without_hosts", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Tenants[0].Hosts = nil
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "hosts required")
+	})
+
+	t.Run("duplicate_name", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Tenants[1].Name = "acme"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "tenant name must be unique")
+	})
+
+	t.Run("name_with_boundary", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Tenants[0].Name = "ac/me"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid tenant name")
+	})
+
+	t.Run("shared_host", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Tenants[1].Hosts = []string{"ACME.example.com"}
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "host belongs to several tenants")
+	})
+
+	t.Run("shared_api_key", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Tenants[1].APIKey = "acme-key"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "api_key must be unique")
+	})
+
+	t.Run("negative_limit", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Tenants[0].PublishRateLimit = -1
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "limits can not be negative")
+	})
+
+	t.Run("unsupported_namespace_option", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Tenants[0].Namespaces[0].SubscribeProxyEnabled = true
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not supported in tenant namespaces")
+	})
+
+	t.Run("disabled_not_validated", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Tenancy.Enabled = false
+		cfg.Tenants[1].Name = "acme"
+		require.NoError(t, cfg.Validate())
+	})
+}
+
+func TestValidateAdminOIDC(t *testing.T) {
+	newConfig := func() Config {
+		cfg := DefaultConfig()
+		cfg.Admin.Enabled = true
+		cfg.Admin.Secret = "secret"
+		cfg.Admin.SessionTTL = configtypes.Duration(time.Hour)
+		cfg.Admin.OIDC = configtypes.AdminOIDC{
+			Enabled:       true,
+			Issuer:        "https://id.example.com/realms/main",
+			ClientID:      "centrifugo",
+			RedirectURL:   "https://centrifugo.example.com/admin/oidc/callback",
+			RolesClaim:    "roles",
+			ViewerRoles:   []string{"staff"},
+			IdentityClaim: "email",
+		}
+		return cfg
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		cfg := newConfig()
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("no_secret", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Admin.Secret = ""
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "admin secret is required")
+	})
+
+	t.Run("issuer_not_url", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Admin.OIDC.Issuer = "id.example.com"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "issuer must be http or https URL")
+	})
+
+	t.Run("no_client_id", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Admin.OIDC.ClientID = ""
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "client_id is required")
+	})
+
+	t.Run("no_roles", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Admin.OIDC.ViewerRoles = nil
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "at least one of viewer_roles, operator_roles or admin_roles")
+	})
+
+	t.Run("zero_session_ttl", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Admin.SessionTTL = 0
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "admin.session_ttl must be positive")
+	})
+}
+
+func TestValidateAudit(t *testing.T) {
+	newConfig := func() Config {
+		cfg := DefaultConfig()
+		cfg.Audit.Enabled = true
+		cfg.Audit.File.Enabled = true
+		return cfg
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		cfg := newConfig()
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("no_sinks", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Audit.File.Enabled = false
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "at least one sink must be enabled")
+	})
+
+	t.Run("zero_batch_size", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Audit.BatchSize = 0
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "buffer_size and batch_size must be positive")
+	})
+
+	t.Run("negative_max_backups", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Audit.File.MaxBackups = -1
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "file.max_backups can not be negative")
+	})
+
+	t.Run("postgresql_no_dsn", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Audit.Postgres.Enabled = true
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "postgresql.dsn is required")
+	})
+
+	t.Run("postgresql_invalid_table_name", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Audit.Postgres.Enabled = true
+		cfg.Audit.Postgres.DSN = "postgres://localhost:5432/test"
+		cfg.Audit.Postgres.TableName = "audit; DROP TABLE users"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid postgresql.table_name")
+	})
+
+	t.Run("disabled_not_validated", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Audit.Enabled = false
+		cfg.Audit.File.Enabled = false
+		require.NoError(t, cfg.Validate())
+	})
+}
+
+func TestValidateScheduledPublications(t *testing.T) {
+	newConfig := func() Config {
+		cfg := DefaultConfig()
+		cfg.ScheduledPublications.Enabled = true
+		return cfg
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		cfg := newConfig()
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("unknown_type", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.ScheduledPublications.Type = "memory"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unknown type")
+	})
+
+	t.Run("postgres_without_postgres_broker", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.ScheduledPublications.Type = "postgres"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "requires broker enabled with type \"postgres\"")
+	})
+
+	t.Run("claim_timeout_not_greater_than_poll_interval", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.ScheduledPublications.ClaimTimeout = cfg.ScheduledPublications.PollInterval
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "claim_timeout must be greater than poll_interval")
+	})
+
+	t.Run("claim_timeout_exceeds_idempotent_result_ttl", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.ScheduledPublications.ClaimTimeout = configtypes.Duration(3 * time.Minute)
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "broker idempotent_result_ttl (5m0s) must be greater than")
+	})
+
+	t.Run("postgres_idempotent_result_ttl", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Broker.Enabled = true
+		cfg.Broker.Type = "postgres"
+		cfg.Broker.Postgres.DSN = "postgres://localhost:5432/test"
+		cfg.ScheduledPublications.Type = "postgres"
+		cfg.Broker.Postgres.IdempotentResultTTL = configtypes.Duration(time.Minute)
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "broker idempotent_result_ttl (1m0s) must be greater than")
+		cfg.Broker.Postgres.IdempotentResultTTL = configtypes.Duration(2 * time.Minute)
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("disabled_not_validated", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.ScheduledPublications.Enabled = false
+		cfg.ScheduledPublications.Type = "unknown"
+		require.NoError(t, cfg.Validate())
+	})
+}
+
+func TestValidateHistoryArchive(t *testing.T) {
+	newConfig := func() Config {
+		cfg := DefaultConfig()
+		cfg.HistoryArchive.Enabled = true
+		return cfg
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		cfg := newConfig()
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("unknown_source", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.HistoryArchive.Source = "redis"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unknown source")
+	})
+
+	t.Run("postgres_partitions_without_postgres_broker", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.HistoryArchive.Source = "postgres_partitions"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "requires broker enabled with type \"postgres\"")
+	})
+
+	t.Run("s3_without_bucket", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.HistoryArchive.Storage = "s3"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "s3.bucket is required")
+	})
+
+	t.Run("s3_partial_credentials", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.HistoryArchive.Storage = "s3"
+		cfg.HistoryArchive.S3.Bucket = "archive"
+		cfg.HistoryArchive.S3.AccessKeyID = "key"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "must be set together")
+	})
+
+	t.Run("max_pending_size_less_than_max_buffer_size", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.HistoryArchive.MaxPendingSize = cfg.HistoryArchive.MaxBufferSize - 1
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "max_pending_size")
+	})
+
+	t.Run("unknown_compression", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.HistoryArchive.Compression = "lz4"
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unknown compression")
+	})
+
+	t.Run("disabled_not_validated", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.HistoryArchive.Enabled = false
+		cfg.HistoryArchive.Storage = "unknown"
+		require.NoError(t, cfg.Validate())
+	})
+}
+
+func TestValidateBidiGRPC(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.BidiGRPC.Enabled = true
+	require.NoError(t, cfg.Validate())
+	require.Equal(t, configtypes.Duration(25*time.Second), cfg.BidiGRPC.Keepalive.Time)
+
+	cfg = DefaultConfig()
+	cfg.BidiGRPC.Enabled = true
+	cfg.BidiGRPC.PingPong.PongTimeout = cfg.BidiGRPC.PingPong.PingInterval
+	err := cfg.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "bidi_grpc.ping_pong")
+
+	cfg = DefaultConfig()
+	cfg.BidiGRPC.Enabled = true
+	cfg.BidiGRPC.Keepalive.Timeout = -1
+	err = cfg.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "bidi_grpc.keepalive")
+}
+
+func TestValidateRPCHistoryRange(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.RPC.HistoryRange.Enabled = true
+	require.NoError(t, cfg.Validate())
+	require.Equal(t, "history_range", cfg.RPC.HistoryRange.Method)
+
+	cfg = DefaultConfig()
+	cfg.RPC.HistoryRange.Enabled = true
+	cfg.RPC.HistoryRange.Method = ""
+	err := cfg.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "rpc.history_range")
+
+	cfg = DefaultConfig()
+	cfg.RPC.Ping.Enabled = true
+	cfg.RPC.HistoryRange.Enabled = true
+	cfg.RPC.HistoryRange.Method = cfg.RPC.Ping.Method
+	err = cfg.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already used by rpc.ping")
+}
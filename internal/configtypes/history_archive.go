@@ -0,0 +1,51 @@
+package configtypes
+
+// HistoryArchive configures long-term archiving of channel history to compressed
+// NDJSON files on local disk or in S3-compatible object storage. Parquet format
+// is not supported.
+type HistoryArchive struct {
+	// Enabled turns on history archiving.
+	Enabled bool `mapstructure:"enabled" json:"enabled" envconfig:"enabled" yaml:"enabled" toml:"enabled" doc:"Enables archiving of channel history to compressed NDJSON files on local disk or in S3-compatible object storage. Parquet format is not supported."`
+	// Source of archived publications: "publications" or "postgres_partitions".
+	Source string `mapstructure:"source" json:"source" envconfig:"source" default:"publications" yaml:"source" toml:"source" expose:"full" doc:"What to archive. <<publications>> archives every publication kept in history as it is published, works with any broker. <<postgres_partitions>> archives daily partitions of the Postgres stream broker before they are dropped by retention, it requires the broker to be enabled with type <<postgres>>. Default <<publications>>."`
+	// Storage of archive files: "local" or "s3".
+	Storage string `mapstructure:"storage" json:"storage" envconfig:"storage" default:"local" yaml:"storage" toml:"storage" expose:"full" doc:"Archive storage, <<local>> or <<s3>>. Default <<local>>."`
+	// Local is a configuration of "local" storage.
+	Local HistoryArchiveLocal `mapstructure:"local" json:"local" envconfig:"local" yaml:"local" toml:"local" doc:"Local disk storage configuration, used when storage is <<local>>."`
+	// S3 is a configuration of "s3" storage.
+	S3 HistoryArchiveS3 `mapstructure:"s3" json:"s3" envconfig:"s3" yaml:"s3" toml:"s3" doc:"S3-compatible object storage configuration, used when storage is <<s3>>."`
+	// Prefix of archive file names.
+	Prefix string `mapstructure:"prefix" json:"prefix" envconfig:"prefix" default:"history" yaml:"prefix" toml:"prefix" expose:"full" doc:"Prefix of archive file paths (object keys). Files are laid out as <<prefix/YYYY/MM/DD/name.ndjson.ext>>. Default <<history>>."`
+	// Compression of archive files: "gzip" or "zstd".
+	Compression string `mapstructure:"compression" json:"compression" envconfig:"compression" default:"gzip" yaml:"compression" toml:"compression" expose:"full" doc:"Compression of archive files, <<gzip>> or <<zstd>>. Default <<gzip>>."`
+	// FlushInterval is how often buffered publications are written to a new archive file.
+	FlushInterval Duration `mapstructure:"flush_interval" json:"flush_interval" envconfig:"flush_interval" default:"1m" yaml:"flush_interval" toml:"flush_interval" doc:"How often publications buffered by a node are written to a new archive file, used with source <<publications>>. Default <<1m>>."`
+	// MaxBufferSize is a maximum number of buffered publications, buffer is flushed
+	// earlier when reached.
+	MaxBufferSize int `mapstructure:"max_buffer_size" json:"max_buffer_size" envconfig:"max_buffer_size" default:"100000" yaml:"max_buffer_size" toml:"max_buffer_size" doc:"Maximum number of publications buffered by a node, buffer is written to a file earlier when reached. Used with source <<publications>>. Default <<100000>>."`
+	// MaxPendingSize is a maximum number of publications kept by node: buffered and
+	// waiting for retry after failed writes. Publications over it are dropped.
+	MaxPendingSize int `mapstructure:"max_pending_size" json:"max_pending_size" envconfig:"max_pending_size" default:"1000000" yaml:"max_pending_size" toml:"max_pending_size" doc:"Maximum number of publications kept in memory by a node, both buffered and waiting for retry when archive storage is slow or unavailable (failed writes are retried with backoff). Publications over it are not archived and counted in <<centrifugo_history_archive_records_total>> with result <<dropped>>. Must not be less than <<max_buffer_size>>. Used with source <<publications>>. Default <<1000000>>."`
+}
+
+// HistoryArchiveLocal configures local disk archive storage.
+type HistoryArchiveLocal struct {
+	// Dir is a directory to keep archive files in.
+	Dir string `mapstructure:"dir" json:"dir" envconfig:"dir" default:"archive" yaml:"dir" toml:"dir" expose:"full" doc:"Directory to keep archive files in. Default <<archive>>."`
+}
+
+// HistoryArchiveS3 configures S3-compatible archive storage.
+type HistoryArchiveS3 struct {
+	// Endpoint of S3-compatible storage. AWS S3 regional endpoint is used if not set.
+	Endpoint string `mapstructure:"endpoint" json:"endpoint" envconfig:"endpoint" yaml:"endpoint" toml:"endpoint" expose:"url" doc:"Endpoint URL of S3-compatible storage, for example <<http://localhost:9000>> for MinIO. AWS S3 regional endpoint is used if not set."`
+	// Region used to sign requests.
+	Region string `mapstructure:"region" json:"region" envconfig:"region" default:"us-east-1" yaml:"region" toml:"region" expose:"full" doc:"Region used to sign requests. Default <<us-east-1>>."`
+	// Bucket to keep archive files in.
+	Bucket string `mapstructure:"bucket" json:"bucket" envconfig:"bucket" yaml:"bucket" toml:"bucket" expose:"full" doc:"Bucket to keep archive files in."`
+	// UsePathStyle addresses bucket in URL path instead of host name.
+	UsePathStyle bool `mapstructure:"use_path_style" json:"use_path_style" envconfig:"use_path_style" yaml:"use_path_style" toml:"use_path_style" expose:"full" doc:"Addresses bucket in URL path instead of host name, required by most S3-compatible storages."`
+	// AccessKeyID and SecretAccessKey are static credentials. If not set, default AWS
+	// credentials chain is used.
+	AccessKeyID     string `mapstructure:"access_key_id" json:"access_key_id" envconfig:"access_key_id" yaml:"access_key_id" toml:"access_key_id" doc:"Access key ID. If not set, default AWS credentials chain (environment, shared credentials file, instance role) is used."`
+	SecretAccessKey string `mapstructure:"secret_access_key" json:"secret_access_key" envconfig:"secret_access_key" yaml:"secret_access_key" toml:"secret_access_key" doc:"Secret access key, used together with access_key_id."`
+}
@@ -4,7 +4,10 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/centrifugal/centrifugo/v6/internal/mapfilter"
+
 	"github.com/centrifugal/centrifuge"
+	"github.com/santhosh-tekuri/jsonschema/v6"
 )
 
 const (
@@ -148,6 +151,10 @@ type ChannelOptions struct {
 	// PublishForClient allows authenticated clients to publish messages into channels in namespace.
 	PublishForClient bool `mapstructure:"allow_publish_for_client" json:"allow_publish_for_client" envconfig:"allow_publish_for_client" yaml:"allow_publish_for_client" toml:"allow_publish_for_client" doc:"Allows authenticated clients to publish into channels in this namespace even without being subscribed. Client-side publish bypasses your backend — use with care."`
 
+	// PublishForCaps allows clients to publish messages into channels in namespace when publish
+	// is granted by connection capabilities or subscription token.
+	PublishForCaps bool `mapstructure:"allow_publish_for_caps" json:"allow_publish_for_caps" envconfig:"allow_publish_for_caps" yaml:"allow_publish_for_caps" toml:"allow_publish_for_caps" doc:"Allows clients to publish into channels in this namespace when publish is granted by connection capabilities (<<caps>> claim of connection token or connect proxy result) or by <<allow>> claim of subscription token. Such publications bypass publish proxy. Can't be used together with subscribe stream proxy."`
+
 	// PresenceForAnonymous allows anonymous clients to get presence information for channels in namespace.
 	PresenceForAnonymous bool `mapstructure:"allow_presence_for_anonymous" json:"allow_presence_for_anonymous" envconfig:"allow_presence_for_anonymous" yaml:"allow_presence_for_anonymous" toml:"allow_presence_for_anonymous" doc:"Allows anonymous clients to call presence on channels in this namespace. Requires presence to be enabled."`
 
@@ -179,6 +186,11 @@ type ChannelOptions struct {
 	// If "binary" - allow empty data to be published.
 	PublicationDataFormat string `mapstructure:"publication_data_format" json:"publication_data_format" envconfig:"publication_data_format" yaml:"publication_data_format" toml:"publication_data_format" expose:"full" doc:"Validation applied to publication data. Empty (default) rejects empty data; <<json>> requires valid JSON; <<json_object>> requires a JSON object; <<binary>> allows empty/binary data."`
 
+	// PublicationDataSchema is a JSON Schema document to validate publication data against.
+	PublicationDataSchema string `mapstructure:"publication_data_schema" json:"publication_data_schema" envconfig:"publication_data_schema" yaml:"publication_data_schema" toml:"publication_data_schema" expose:"full" doc:"JSON Schema document embedded as a string. Publication data from server API, client publish, map publish and consumers is validated against it before reaching the broker. Publications which do not match are rejected with schema violation error."`
+	// PublicationDataSchemaFile is a path to a file with JSON Schema document.
+	PublicationDataSchemaFile string `mapstructure:"publication_data_schema_file" json:"publication_data_schema_file" envconfig:"publication_data_schema_file" yaml:"publication_data_schema_file" toml:"publication_data_schema_file" expose:"full" doc:"Path to a file with JSON Schema document to validate publication data against. Alternative to <<publication_data_schema>>."`
+
 	// SubscribeProxyEnabled turns on using proxy for subscribe operations in namespace.
 	SubscribeProxyEnabled bool `mapstructure:"subscribe_proxy_enabled" json:"subscribe_proxy_enabled" envconfig:"subscribe_proxy_enabled" yaml:"subscribe_proxy_enabled" toml:"subscribe_proxy_enabled" doc:"Proxies subscribe events in this namespace to your backend for authorization. Requires a configured subscribe proxy."`
 	// SubscribeProxyName of proxy to use for subscribe operations in namespace.
@@ -198,6 +210,15 @@ type ChannelOptions struct {
 	SubscribeStreamProxyEnabled bool `mapstructure:"subscribe_stream_proxy_enabled" json:"subscribe_stream_proxy_enabled" envconfig:"subscribe_stream_proxy_enabled" yaml:"subscribe_stream_proxy_enabled" toml:"subscribe_stream_proxy_enabled" doc:"Proxies subscriptions in this namespace to a stream proxy that streams publications from your backend instead of the PUB/SUB engine. Requires a configured subscribe stream proxy."`
 	// SubscribeStreamProxyName of proxy to use for subscribe stream operations in namespace.
 	SubscribeStreamProxyName string `mapstructure:"subscribe_stream_proxy_name" default:"default" json:"subscribe_stream_proxy_name" envconfig:"subscribe_stream_proxy_name" yaml:"subscribe_stream_proxy_name" toml:"subscribe_stream_proxy_name" expose:"full" doc:"Name of the configured proxy to use for subscribe stream in this namespace. Defaults to <<default>>."`
+	// ChannelStateProxyEnabled turns on sending channel occupied and vacated events to proxy in namespace.
+	ChannelStateProxyEnabled bool `mapstructure:"channel_state_proxy_enabled" json:"channel_state_proxy_enabled" envconfig:"channel_state_proxy_enabled" yaml:"channel_state_proxy_enabled" toml:"channel_state_proxy_enabled" doc:"Sends <<occupied>> and <<vacated>> events of channels in this namespace to your backend, so it can start and stop producing data only while channels have subscribers. Requires a configured channel state proxy."`
+	// ChannelStateProxyName of proxy to use for channel state events in namespace.
+	ChannelStateProxyName string `mapstructure:"channel_state_proxy_name" default:"default" json:"channel_state_proxy_name" envconfig:"channel_state_proxy_name" yaml:"channel_state_proxy_name" toml:"channel_state_proxy_name" expose:"full" doc:"Name of the configured proxy to use for channel state events in this namespace. Defaults to <<default>>."`
+	// CacheEmptyProxyEnabled turns on asking proxy to load missing cache in namespace.
+	CacheEmptyProxyEnabled bool `mapstructure:"cache_empty_proxy_enabled" json:"cache_empty_proxy_enabled" envconfig:"cache_empty_proxy_enabled" yaml:"cache_empty_proxy_enabled" toml:"cache_empty_proxy_enabled" doc:"Asks your backend to load the latest publication when a subscriber in cache recovery mode finds channel history empty. Requires force_recovery_mode set to <<cache>> and a configured cache empty proxy."`
+	// CacheEmptyProxyName of proxy to use for cache empty events in namespace.
+	CacheEmptyProxyName string `mapstructure:"cache_empty_proxy_name" default:"default" json:"cache_empty_proxy_name" envconfig:"cache_empty_proxy_name" yaml:"cache_empty_proxy_name" toml:"cache_empty_proxy_name" expose:"full" doc:"Name of the configured proxy to use for cache empty events in this namespace. Defaults to <<default>>."`
+
 	// SubscribeStreamBidirectional enables using bidirectional stream proxy for the namespace.
 	SubscribeStreamBidirectional bool `mapstructure:"subscribe_stream_proxy_bidirectional" json:"subscribe_stream_proxy_bidirectional" envconfig:"subscribe_stream_proxy_bidirectional" yaml:"subscribe_stream_proxy_bidirectional" toml:"subscribe_stream_proxy_bidirectional" doc:"Enables bidirectional mode for the subscribe stream proxy, letting clients also send data into the stream."`
 
@@ -212,9 +233,35 @@ type ChannelOptions struct {
 	// SharedPoll contains configuration for shared poll subscription type.
 	SharedPoll SharedPollConfig `mapstructure:"shared_poll" json:"shared_poll" envconfig:"shared_poll" yaml:"shared_poll" toml:"shared_poll" doc:"Configuration for the <<shared_poll>> subscription type."`
 
+	// Throttle contains configuration for server-side publication coalescing.
+	Throttle ThrottleConfig `mapstructure:"throttle" json:"throttle" envconfig:"throttle" yaml:"throttle" toml:"throttle" doc:"Configuration for server-side publication throttling in this namespace. Only applies to <<stream>> subscription type."`
+
+	// Encryption contains configuration for envelope encryption of publication data.
+	Encryption EncryptionConfig `mapstructure:"encryption" json:"encryption" envconfig:"encryption" yaml:"encryption" toml:"encryption" doc:"Configuration for envelope encryption of publication data in this namespace, so brokers and history storage only keep ciphertext. Keys are configured in <<channel.encryption>>."`
+
+	// WebSocketCompressionDictionaryFile is a path to a preset permessage-deflate dictionary.
+	WebSocketCompressionDictionaryFile string `mapstructure:"websocket_compression_dictionary_file" json:"websocket_compression_dictionary_file" envconfig:"websocket_compression_dictionary_file" yaml:"websocket_compression_dictionary_file" toml:"websocket_compression_dictionary_file" expose:"full" doc:"Path to a file with a preset permessage-deflate dictionary, for example typical publications of this namespace. Used by WebSocket connections which pass the namespace name in <<cf_ws_dictionary>> URL parameter when compression context takeover is negotiated. The client must prime its compression context with the same dictionary. Only the last <<compression_context.window_bits>> worth of bytes are used."`
+
 	Compiled `json:"-" yaml:"-" toml:"-"`
 }
 
+// ThrottleConfig contains configuration for server-side publication throttling. When
+// enabled, publications are coalesced per channel (or per channel and tag value) within
+// a time window and only the latest one is delivered.
+type ThrottleConfig struct {
+	Enabled  bool     `mapstructure:"enabled" json:"enabled" envconfig:"enabled" yaml:"enabled" toml:"enabled" doc:"Enables publication throttling for channels in this namespace. The first publication in a window is delivered immediately, subsequent ones within the window are coalesced so that only the latest is delivered when the window ends. Applies to server API publications and to client publications not handled by a publish proxy. Coalescing happens on the node which receives the publish request."`
+	Interval Duration `mapstructure:"interval" json:"interval" envconfig:"interval" yaml:"interval" toml:"interval" doc:"Coalescing window, e.g. <<100ms>> to deliver at most 10 updates per second per channel. Required when throttling is enabled."`
+	TagKey   string   `mapstructure:"tag_key" json:"tag_key" envconfig:"tag_key" yaml:"tag_key" toml:"tag_key" expose:"full" doc:"Optional publication tag key to coalesce by. When set, publications are coalesced per channel and value of this tag, so updates for different keys (e.g. different tickers in one channel) do not replace each other."`
+}
+
+// EncryptionConfig contains configuration for envelope encryption of publication data.
+// Data is encrypted before it reaches broker and decrypted when delivered to subscribers
+// or returned from history, unless passthrough is on.
+type EncryptionConfig struct {
+	Enabled     bool `mapstructure:"enabled" json:"enabled" envconfig:"enabled" yaml:"enabled" toml:"enabled" doc:"Enables AES-GCM envelope encryption of publication data for channels in this namespace. Supported for stream and map subscription types, can not be used together with <<map.indexes>>."`
+	Passthrough bool `mapstructure:"passthrough" json:"passthrough" envconfig:"passthrough" yaml:"passthrough" toml:"passthrough" doc:"Delivers encrypted envelopes to subscribers as is, for clients which decrypt publications themselves."`
+}
+
 // MapConfig contains configuration for map subscription types (map, map_clients, map_users).
 type MapConfig struct {
 	Mode                              string   `mapstructure:"mode" json:"mode" envconfig:"mode" yaml:"mode" toml:"mode" expose:"full" doc:"Map mode controlling how keys are stored and delivered. See map subscription docs for supported values."`
@@ -240,6 +287,41 @@ type MapConfig struct {
 	MaxPageSize                       int      `mapstructure:"max_page_size" json:"max_page_size" envconfig:"max_page_size" yaml:"max_page_size" toml:"max_page_size" doc:"Maximum page size a client may request when paginating map keys."`
 	LiveTransitionMaxPublicationLimit int      `mapstructure:"live_transition_max_publication_limit" json:"live_transition_max_publication_limit" envconfig:"live_transition_max_publication_limit" yaml:"live_transition_max_publication_limit" toml:"live_transition_max_publication_limit" doc:"Maximum number of buffered publications allowed when a subscriber transitions to the live state. Exceeding it falls back to a full state load."`
 	SubscribeCatchUpTimeout           Duration `mapstructure:"subscribe_catch_up_timeout" json:"subscribe_catch_up_timeout" envconfig:"subscribe_catch_up_timeout" yaml:"subscribe_catch_up_timeout" toml:"subscribe_catch_up_timeout" doc:"Maximum time a subscriber may spend catching up to the live map state before the attempt is aborted, e.g. <<10s>>."`
+
+	// Indexes describe secondary indexes on JSON fields of map values. Filtering
+	// is native only for Postgres map broker in JSON data mode, other brokers
+	// scan the whole state bounded by FilterScanLimit.
+	Indexes MapIndexes `mapstructure:"indexes" json:"indexes" envconfig:"indexes" yaml:"indexes" toml:"indexes" doc:"Secondary indexes on JSON fields of map values. Indexed fields can be used in filter and sort expressions when reading map state. Only Postgres map broker in JSON data mode filters and sorts natively using database indexes. With memory and Redis map brokers (and Postgres map broker in binary data mode) filtered reads are a full scan of channel state on every page request, bounded by <<filter_scan_limit>> – reads of larger states fail, so use Postgres map broker for large keyed state."`
+	// IndexTags adds indexed field values to publication tags.
+	IndexTags bool `mapstructure:"index_tags" json:"index_tags" envconfig:"index_tags" yaml:"index_tags" toml:"index_tags" doc:"Adds values of indexed fields to tags of map publications (tag key is the index name), so that client map subscriptions can filter entries by indexed fields using subscription tags filter. Requires <<allow_tags_filter>> in the namespace."`
+	// FilterScanLimit limits in-memory scan of state for filtered reads.
+	FilterScanLimit int `mapstructure:"filter_scan_limit" json:"filter_scan_limit" envconfig:"filter_scan_limit" yaml:"filter_scan_limit" toml:"filter_scan_limit" doc:"Maximum number of state entries scanned in memory to serve a filtered or sorted state read when map broker can not filter natively (memory and Redis map brokers, Postgres map broker in binary data mode). Each page request, including pages requested by cursor, scans the state from the beginning. Reads of states with more entries fail. Zero means 100000."`
+}
+
+type MapIndexes []MapIndex
+
+// Decode to implement the envconfig.Decoder interface
+func (d *MapIndexes) Decode(value string) error {
+	return decodeToNamedSlice(value, d)
+}
+
+// FilterIndexes converts indexes to the form used by filter and sort expressions.
+func (d MapIndexes) FilterIndexes() []mapfilter.Index {
+	indexes := make([]mapfilter.Index, 0, len(d))
+	for _, idx := range d {
+		indexes = append(indexes, mapfilter.Index{Name: idx.Name, Field: idx.Field, Type: idx.Type})
+	}
+	return indexes
+}
+
+// MapIndex describes a secondary index on a JSON field of map values.
+type MapIndex struct {
+	// Name is a unique index name, used to reference the field in filter and sort expressions.
+	Name string `mapstructure:"name" json:"name" envconfig:"name" yaml:"name" toml:"name" expose:"full" doc:"Unique index name within the namespace. Used to reference the indexed field in filter and sort expressions, e.g. <<status>>."`
+	// Field is a path to a field in JSON value.
+	Field string `mapstructure:"field" json:"field" envconfig:"field" yaml:"field" toml:"field" expose:"full" doc:"Dot-separated path to the indexed field inside the JSON map value, e.g. <<status>> or <<order.updated_at>>."`
+	// Type of indexed field value.
+	Type string `mapstructure:"type" json:"type" envconfig:"type" yaml:"type" toml:"type" expose:"full" doc:"Type of the indexed value, one of <<string>> (default) or <<number>>. Determines how values are compared and sorted."`
 }
 
 // SharedPollConfig contains configuration for shared poll subscription type.
@@ -252,8 +334,14 @@ type SharedPollConfig struct {
 	ChannelShutdownDelay   Duration `mapstructure:"channel_shutdown_delay" json:"channel_shutdown_delay" envconfig:"channel_shutdown_delay" yaml:"channel_shutdown_delay" toml:"channel_shutdown_delay" doc:"Delay before a channel with no subscribers stops polling and shuts down, e.g. <<10s>>. Avoids churn from quick resubscribes."`
 	TrackExpiredExtraDelay Duration `mapstructure:"track_expired_extra_delay" json:"track_expired_extra_delay" envconfig:"track_expired_extra_delay" yaml:"track_expired_extra_delay" toml:"track_expired_extra_delay" doc:"Extra time an expired key keeps being tracked before removal, e.g. <<5s>>."`
 	PublishEnabled         bool     `mapstructure:"publish_enabled" json:"publish_enabled" envconfig:"publish_enabled" yaml:"publish_enabled" toml:"publish_enabled" doc:"Allows publishing into shared-poll channels in addition to backend polling."`
+
+	// Coordinated enables cluster-wide coordination of polling, see SharedPollCoordination.
+	Coordinated bool `mapstructure:"coordinated" json:"coordinated" envconfig:"coordinated" yaml:"coordinated" toml:"coordinated" doc:"Elects a single node per channel across the cluster to poll the backend, other nodes get results over the broker and the coordination store. Requires <<shared_poll.coordination>>, <<versioned>> mode and explicit <<refresh_interval>>, implies <<publish_enabled>>."`
 }
 
 type Compiled struct {
-	CompiledChannelRegex *regexp.Regexp `json:"-" yaml:"-" toml:"-" envconfig:"-"`
+	CompiledChannelRegex *regexp.Regexp     `json:"-" yaml:"-" toml:"-" envconfig:"-"`
+	CompiledDataSchema   *jsonschema.Schema `json:"-" yaml:"-" toml:"-" envconfig:"-"`
+
+	WebSocketCompressionDictionary []byte `json:"-" yaml:"-" toml:"-" envconfig:"-"`
 }
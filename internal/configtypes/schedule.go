@@ -0,0 +1,21 @@
+package configtypes
+
+// ScheduledPublications configures durable scheduler of publications sent over
+// server API with publish_at or delay set.
+type ScheduledPublications struct {
+	// Enabled turns on scheduled publications.
+	Enabled bool `mapstructure:"enabled" json:"enabled" envconfig:"enabled" yaml:"enabled" toml:"enabled" doc:"Enables scheduled and delayed publications: <<publish>> and <<broadcast>> server API methods accept <<publish_at>> and <<delay>> fields."`
+	// Type of scheduler store: "redis" or "postgres".
+	Type string `mapstructure:"type" json:"type" envconfig:"type" default:"redis" yaml:"type" toml:"type" expose:"full" doc:"Scheduler store type, <<redis>> or <<postgres>>. The <<postgres>> type uses tables of the Postgres stream broker, so it requires the broker to be enabled with type <<postgres>>. Default <<redis>>."`
+	// Redis is a configuration for "redis" scheduler store.
+	Redis RedisPrefixed `mapstructure:"redis" json:"redis" envconfig:"redis" yaml:"redis" toml:"redis" doc:"Redis configuration, used when type is <<redis>>."`
+	// PollInterval is how often each node checks for due publications.
+	PollInterval Duration `mapstructure:"poll_interval" json:"poll_interval" envconfig:"poll_interval" default:"1s" yaml:"poll_interval" toml:"poll_interval" doc:"How often each node checks the store for due publications. Default <<1s>>."`
+	// BatchSize is a maximum number of publications claimed at once.
+	BatchSize int `mapstructure:"batch_size" json:"batch_size" envconfig:"batch_size" default:"100" yaml:"batch_size" toml:"batch_size" doc:"Maximum number of due publications claimed by a node at once. Default <<100>>."`
+	// ClaimTimeout is a time after which publication claimed by a node which did not
+	// complete it can be claimed by another node.
+	ClaimTimeout Duration `mapstructure:"claim_timeout" json:"claim_timeout" envconfig:"claim_timeout" default:"30s" yaml:"claim_timeout" toml:"claim_timeout" doc:"Time after which a publication claimed by a node which failed to publish it (for example, crashed) is claimed again. Default <<30s>>."`
+	// MaxDelay limits how far in the future publication can be scheduled.
+	MaxDelay Duration `mapstructure:"max_delay" json:"max_delay" envconfig:"max_delay" default:"720h" yaml:"max_delay" toml:"max_delay" doc:"Maximum time in the future publication can be scheduled at. Default <<720h>>."`
+}
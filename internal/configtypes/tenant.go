@@ -0,0 +1,43 @@
+package configtypes
+
+// Tenancy configures multi-tenant isolation of customer applications hosted on
+// one Centrifugo cluster. Tenants themselves are defined in Tenants.
+type Tenancy struct {
+	// Enabled turns on tenant resolution, tenant channel routing and tenant limits.
+	Enabled bool `mapstructure:"enabled" json:"enabled" envconfig:"enabled" yaml:"enabled" toml:"enabled" doc:"Enables multi-tenancy. Tenants are defined in the top-level <<tenants>> array."`
+	// Resolve defines how tenant is resolved for real-time connections: "host" or "path".
+	Resolve string `mapstructure:"resolve" json:"resolve" envconfig:"resolve" default:"host" yaml:"resolve" toml:"resolve" expose:"full" doc:"How tenant of a real-time connection is resolved. <<host>> matches request Host against tenant <<hosts>>, <<path>> extracts tenant name from URL path, like <<{path_prefix}/{tenant}/connection/websocket>>. Default <<host>>."`
+	// PathPrefix is a URL path prefix before tenant name when Resolve is "path".
+	PathPrefix string `mapstructure:"path_prefix" json:"path_prefix" envconfig:"path_prefix" default:"/tenants" yaml:"path_prefix" toml:"path_prefix" expose:"full" doc:"URL path prefix followed by tenant name when resolve is <<path>>. Default <<\"/tenants\">>."`
+	// ChannelBoundary separates tenant name from the rest of channel name.
+	ChannelBoundary string `mapstructure:"channel_boundary" json:"channel_boundary" envconfig:"channel_boundary" default:"/" yaml:"channel_boundary" toml:"channel_boundary" expose:"full" doc:"Separator between tenant name and the rest of channel name, tenant channels look like <<acme/chat:index>>. Private channel prefix goes before tenant name. Default <<\"/\">>."`
+}
+
+// Tenant is an isolated customer application with its own token verification keys,
+// API key, channel namespaces and limits.
+type Tenant struct {
+	// Name is a unique tenant name, also used as a prefix of tenant channels.
+	Name string `mapstructure:"name" json:"name" envconfig:"name" yaml:"name" toml:"name" expose:"full" doc:"Unique tenant name. Tenant channels are prefixed with it, it's also a value of <<tenant>> metric label."`
+	// Hosts are request hosts which belong to tenant when tenancy resolve is "host".
+	Hosts []string `mapstructure:"hosts" json:"hosts" envconfig:"hosts" yaml:"hosts" toml:"hosts" expose:"full" doc:"Request hosts (without port) resolving to this tenant when tenancy resolve is <<host>>."`
+	// Token is a configuration of connection and subscription token verification for tenant.
+	Token Token `mapstructure:"token" json:"token" envconfig:"token" yaml:"token" toml:"token" doc:"JWT verification configuration for connection and subscription tokens of tenant connections. Replaces <<client.token>> for them."`
+	// APIKey is a key for server HTTP API limited to tenant channels.
+	APIKey string `mapstructure:"api_key" json:"api_key" envconfig:"api_key" yaml:"api_key" toml:"api_key" doc:"HTTP API key of tenant. Requests authorized with it can only operate on tenant channels."`
+	// Namespaces are channel namespaces of tenant.
+	Namespaces ChannelNamespaces `mapstructure:"namespaces" default:"[]" json:"namespaces" envconfig:"namespaces" yaml:"namespaces" toml:"namespaces" doc:"Channel namespaces of tenant. Tenant channels without namespace use <<channel.without_namespace>> options."`
+	// ConnectionLimit is a maximum number of tenant connections on one node.
+	ConnectionLimit int `mapstructure:"connection_limit" json:"connection_limit" envconfig:"connection_limit" yaml:"connection_limit" toml:"connection_limit" doc:"Maximum number of tenant connections on one node. Zero means no limit."`
+	// ChannelLimit is a maximum number of channels tenant connection can subscribe to.
+	ChannelLimit int `mapstructure:"channel_limit" json:"channel_limit" envconfig:"channel_limit" yaml:"channel_limit" toml:"channel_limit" doc:"Maximum number of channels one tenant connection can subscribe to. Zero means <<client.channel_limit>> only."`
+	// PublishRateLimit is a maximum number of publications per second into tenant
+	// channels on one node.
+	PublishRateLimit int `mapstructure:"publish_rate_limit" json:"publish_rate_limit" envconfig:"publish_rate_limit" yaml:"publish_rate_limit" toml:"publish_rate_limit" doc:"Maximum number of publications per second into tenant channels on one node, from clients and server API together. Zero means no limit."`
+}
+
+type Tenants []Tenant
+
+// Decode to implement the envconfig.Decoder interface
+func (d *Tenants) Decode(value string) error {
+	return decodeToNamedSlice(value, d)
+}
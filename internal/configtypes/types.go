@@ -63,6 +63,106 @@ type Token struct {
 	IssuerRegex                         string `mapstructure:"issuer_regex" json:"issuer_regex" envconfig:"issuer_regex" yaml:"issuer_regex" toml:"issuer_regex" expose:"full" doc:"Regular expression that the JWT issuer claim (<<iss>>) must match. Alternative to issuer for more flexible matching."`
 	UserIDClaim                         string `mapstructure:"user_id_claim" json:"user_id_claim" envconfig:"user_id_claim" yaml:"user_id_claim" toml:"user_id_claim" expose:"full" doc:"JWT claim name to extract the user ID from. When empty, the standard <<sub>> claim is used."`
 	InsecureSkipJWKSEndpointSafetyCheck bool   `mapstructure:"insecure_skip_jwks_endpoint_safety_check" json:"insecure_skip_jwks_endpoint_safety_check" envconfig:"insecure_skip_jwks_endpoint_safety_check" yaml:"insecure_skip_jwks_endpoint_safety_check" toml:"insecure_skip_jwks_endpoint_safety_check" doc:"Disables the safety check that prevents using a JWKS endpoint accessible from the internet without additional protection. Use only in trusted environments."`
+
+	// Ed25519PublicKey is a PEM encoded Ed25519 public key used to verify EdDSA signed tokens.
+	Ed25519PublicKey string `mapstructure:"ed25519_public_key" json:"ed25519_public_key" envconfig:"ed25519_public_key" yaml:"ed25519_public_key" toml:"ed25519_public_key" expose:"full" doc:"Ed25519 public key (PEM) used to verify EdDSA signed tokens."`
+	// JWKSEndpoints is a list of JWKS endpoints tried in order to find a token key by kid.
+	JWKSEndpoints JWKSEndpoints `mapstructure:"jwks_endpoints" json:"jwks_endpoints" envconfig:"jwks_endpoints" yaml:"jwks_endpoints" toml:"jwks_endpoints" doc:"List of JWKS endpoints tried in order to find a token key by <<kid>>. Each endpoint may be bound to a specific issuer and audience, so tokens of several identity providers may be accepted at the same time. Can not be used together with <<jwks_public_endpoint>>."`
+}
+
+// JWKSEndpoint is one of several JWKS endpoints used to verify tokens.
+type JWKSEndpoint struct {
+	// Name of endpoint used in metrics and logs.
+	Name string `mapstructure:"name" json:"name" envconfig:"name" yaml:"name" toml:"name" expose:"full" doc:"Name of the endpoint used in metrics and logs. When not set, endpoint host is used."`
+	// URL of JWKS endpoint.
+	URL string `mapstructure:"url" json:"url" envconfig:"url" yaml:"url" toml:"url" expose:"full" doc:"URL of the JWKS endpoint. Supports the same <<{{var}}>> templating as <<jwks_public_endpoint>>."`
+	// Issuer binds endpoint to tokens with matching iss claim.
+	Issuer string `mapstructure:"issuer" json:"issuer" envconfig:"issuer" yaml:"issuer" toml:"issuer" expose:"full" doc:"When set, the endpoint is only used for tokens with matching issuer claim (<<iss>>)."`
+	// Audience binds endpoint to tokens with matching aud claim.
+	Audience string `mapstructure:"audience" json:"audience" envconfig:"audience" yaml:"audience" toml:"audience" expose:"full" doc:"When set, the endpoint is only used for tokens with matching audience claim (<<aud>>)."`
+}
+
+type JWKSEndpoints []JWKSEndpoint
+
+// Decode to implement the envconfig.Decoder interface
+func (d *JWKSEndpoints) Decode(value string) error {
+	return decodeToNamedSlice(value, d)
+}
+
+// TokenIntrospection configures verification of opaque connection tokens over RFC 7662.
+type TokenIntrospection struct {
+	// Enabled turns on token introspection for connection tokens.
+	Enabled bool `mapstructure:"enabled" json:"enabled" envconfig:"enabled" yaml:"enabled" toml:"enabled" doc:"Enables verification of connection tokens using the introspection endpoint. When enabled, connection tokens are not verified as JWT."`
+	// Endpoint is a URL of introspection endpoint.
+	Endpoint string `mapstructure:"endpoint" json:"endpoint" envconfig:"endpoint" yaml:"endpoint" toml:"endpoint" expose:"full" doc:"URL of the RFC 7662 token introspection endpoint."`
+	// ClientID is used for HTTP basic authentication in introspection requests.
+	ClientID string `mapstructure:"client_id" json:"client_id" envconfig:"client_id" yaml:"client_id" toml:"client_id" expose:"full" doc:"Client ID used for HTTP basic authentication in introspection requests."`
+	// ClientSecret is used for HTTP basic authentication in introspection requests.
+	ClientSecret string `mapstructure:"client_secret" json:"client_secret" envconfig:"client_secret" yaml:"client_secret" toml:"client_secret" doc:"Client secret used for HTTP basic authentication in introspection requests."`
+	// Timeout for introspection request.
+	Timeout Duration `mapstructure:"timeout" json:"timeout" envconfig:"timeout" default:"1s" yaml:"timeout" toml:"timeout" doc:"Timeout for introspection requests. Default <<1s>>."`
+	// UserIDClaim is a claim in introspection response to extract user ID from.
+	UserIDClaim string `mapstructure:"user_id_claim" json:"user_id_claim" envconfig:"user_id_claim" yaml:"user_id_claim" toml:"user_id_claim" expose:"full" doc:"Introspection response claim to extract the user ID from. When empty, the standard <<sub>> claim is used."`
+	// InfoClaim is a claim in introspection response used as connection info.
+	InfoClaim string `mapstructure:"info_claim" json:"info_claim" envconfig:"info_claim" yaml:"info_claim" toml:"info_claim" expose:"full" doc:"Introspection response claim used as connection info. Info is not set when empty."`
+	// MetaClaim is a claim in introspection response used as connection meta.
+	MetaClaim string `mapstructure:"meta_claim" json:"meta_claim" envconfig:"meta_claim" yaml:"meta_claim" toml:"meta_claim" expose:"full" doc:"Introspection response claim used as connection meta. Meta is not set when empty."`
+	// CacheMaxTTL limits time active introspection results are cached.
+	CacheMaxTTL Duration `mapstructure:"cache_max_ttl" json:"cache_max_ttl" envconfig:"cache_max_ttl" default:"5m" yaml:"cache_max_ttl" toml:"cache_max_ttl" doc:"Maximum time an active introspection result is cached. Results are never cached beyond token <<exp>>. Zero disables caching. Default <<5m>>."`
+}
+
+// ConnectionQuota configures per-user and per-IP connection quotas enforced across
+// all Centrifugo nodes.
+type ConnectionQuota struct {
+	// UserLimit is a maximum number of connections of a single user in a cluster.
+	UserLimit int `mapstructure:"user_limit" json:"user_limit" envconfig:"user_limit" yaml:"user_limit" toml:"user_limit" doc:"Maximum number of simultaneous connections allowed per user ID across all nodes. Zero means no limit."`
+	// IPLimit is a maximum number of connections from a single IP address in a cluster.
+	IPLimit int `mapstructure:"ip_limit" json:"ip_limit" envconfig:"ip_limit" yaml:"ip_limit" toml:"ip_limit" doc:"Maximum number of simultaneous connections allowed per client IP address across all nodes. Zero means no limit."`
+	// Policy defines what to do with a new connection when quota is exhausted.
+	Policy string `mapstructure:"policy" json:"policy" envconfig:"policy" default:"reject" yaml:"policy" toml:"policy" expose:"full" doc:"What to do when quota is exhausted: <<reject>> rejects the new connection, <<evict_oldest>> disconnects the oldest connections to make room for the new one. Default <<reject>>."`
+	// IPHeader is a name of HTTP header to extract client IP address from.
+	IPHeader string `mapstructure:"ip_header" json:"ip_header" envconfig:"ip_header" yaml:"ip_header" toml:"ip_header" expose:"full" doc:"HTTP header to extract the client IP address from, e.g. <<X-Real-IP>> when Centrifugo is behind a trusted reverse proxy. When empty, the remote address of the connection is used. For lists of addresses like <<X-Forwarded-For>> the rightmost address not belonging to <<trusted_proxies>> is used."`
+	// TrustedProxies is a list of IP addresses and CIDR ranges of trusted reverse proxies.
+	TrustedProxies []string `mapstructure:"trusted_proxies" json:"trusted_proxies" envconfig:"trusted_proxies" yaml:"trusted_proxies" toml:"trusted_proxies" expose:"full" doc:"IP addresses and CIDR ranges of trusted reverse proxies. When set, <<ip_header>> is only used for requests coming from trusted proxies, and trusted proxy addresses are skipped when walking the header address list from the right."`
+	// Type of store to keep connections: "presence" or "redis".
+	Type string `mapstructure:"type" json:"type" envconfig:"type" default:"presence" yaml:"type" toml:"type" expose:"full" doc:"Store keeping connections counted by quotas. <<presence>> uses the configured presence manager with hidden per-user and per-IP channels, <<redis>> uses a separate Redis. Default <<presence>>."`
+	// Redis is a configuration for "redis" store.
+	Redis RedisPrefixed `mapstructure:"redis" json:"redis" envconfig:"redis" yaml:"redis" toml:"redis" doc:"Redis configuration, used when type is <<redis>>."`
+	// TTL is a time connection is counted without being refreshed by its node.
+	TTL Duration `mapstructure:"ttl" json:"ttl" envconfig:"ttl" default:"60s" yaml:"ttl" toml:"ttl" doc:"How long a connection is counted without being refreshed by its node, so connections of crashed nodes are eventually released. Used by <<redis>> type, presence manager TTL applies for <<presence>> type. Default <<60s>>."`
+}
+
+// Enabled returns true if any of connection quotas is set.
+func (q ConnectionQuota) Enabled() bool {
+	return q.UserLimit > 0 || q.IPLimit > 0
+}
+
+type EncryptionKeys []EncryptionKey
+
+// Decode to implement the envconfig.Decoder interface
+func (d *EncryptionKeys) Decode(value string) error {
+	return decodeToNamedSlice(value, d)
+}
+
+// EncryptionKey is a key used for envelope encryption of publication data.
+type EncryptionKey struct {
+	// ID is put into envelope to find a key for decryption.
+	ID string `mapstructure:"id" json:"id" envconfig:"id" yaml:"id" toml:"id" expose:"full" doc:"Unique key ID, included into every envelope to find the key for decryption."`
+	// Key is a base64 encoded AES key.
+	Key string `mapstructure:"key" json:"key" envconfig:"key" yaml:"key" toml:"key" doc:"Base64 encoded AES key of 16, 24 or 32 bytes."`
+	// ActiveFrom is a Unix time since which key is used for encryption.
+	ActiveFrom int64 `mapstructure:"active_from" json:"active_from" envconfig:"active_from" yaml:"active_from" toml:"active_from" doc:"Unix timestamp since which the key is used to encrypt new publications. The key with the latest active_from in the past is used, which allows scheduling key rotation. Keys are used for decryption regardless of this value."`
+}
+
+// ChannelEncryption contains keys for envelope encryption of publication data in
+// namespaces with encryption enabled.
+type ChannelEncryption struct {
+	// Keys configured inline.
+	Keys EncryptionKeys `mapstructure:"keys" default:"[]" json:"keys" envconfig:"keys" yaml:"keys" toml:"keys" doc:"List of encryption keys."`
+	// KeyringFile is a path to JSON file with keys.
+	KeyringFile string `mapstructure:"keyring_file" json:"keyring_file" envconfig:"keyring_file" yaml:"keyring_file" toml:"keyring_file" expose:"full" doc:"Path to a local JSON keyring file, an object with <<keys>> array of the same format as inline keys. Keys from the file are added to inline keys."`
+	// AllowPlaintext is a migration flag.
+	AllowPlaintext bool `mapstructure:"allow_plaintext" json:"allow_plaintext" envconfig:"allow_plaintext" yaml:"allow_plaintext" toml:"allow_plaintext" doc:"Accept data without envelope when reading publications and map state of namespaces with encryption enabled, such data is returned as is. By default it is rejected. Use temporarily while enabling encryption for namespaces with existing plaintext history or map state."`
 }
 
 // SubscriptionToken can be used to set custom configuration for subscription tokens.
@@ -78,6 +178,18 @@ type SharedPoll struct {
 	HMACPreviousSecretKey           string `mapstructure:"hmac_previous_secret_key" json:"hmac_previous_secret_key" envconfig:"hmac_previous_secret_key" yaml:"hmac_previous_secret_key" toml:"hmac_previous_secret_key" doc:"Previous HMAC secret key for shared poll tokens, accepted during key rotation alongside the current hmac_secret_key."`
 	HMACPreviousSecretKeyValidUntil int64  `mapstructure:"hmac_previous_secret_key_valid_until" json:"hmac_previous_secret_key_valid_until" envconfig:"hmac_previous_secret_key_valid_until" yaml:"hmac_previous_secret_key_valid_until" toml:"hmac_previous_secret_key_valid_until" doc:"Unix timestamp until which the previous shared poll HMAC secret key remains valid."`
 	ConcurrencyLimit                int    `mapstructure:"concurrency_limit" json:"concurrency_limit" envconfig:"concurrency_limit" yaml:"concurrency_limit" toml:"concurrency_limit" doc:"Maximum number of concurrent shared poll subscription requests processed simultaneously. Zero means no limit."`
+
+	// Coordination is used by namespaces with coordinated shared poll.
+	Coordination SharedPollCoordination `mapstructure:"coordination" json:"coordination" envconfig:"coordination" yaml:"coordination" toml:"coordination" doc:"Store used to coordinate polling of shared poll channels with <<coordinated>> option across nodes."`
+}
+
+// SharedPollCoordination configures a store which keeps channel poll leases,
+// tracked keys and last polled items shared between nodes.
+type SharedPollCoordination struct {
+	// Type of coordination store: "redis" or "postgres".
+	Type string `mapstructure:"type" json:"type" envconfig:"type" yaml:"type" toml:"type" expose:"full" doc:"Coordination store type, <<redis>> or <<postgres>>. The <<postgres>> type uses tables of the Postgres controller, so it requires the controller to be enabled with type <<postgres>>."`
+	// Redis is a configuration for "redis" coordination store.
+	Redis RedisPrefixed `mapstructure:"redis" json:"redis" envconfig:"redis" yaml:"redis" toml:"redis" doc:"Redis configuration, used when type is <<redis>>."`
 }
 
 // HTTP3 is EXPERIMENTAL.
@@ -103,6 +215,17 @@ type WebSocket struct {
 	// larger amount of memory (a "decompression bomb"). When zero, the limit is derived from
 	// message_size_limit multiplied by the default multiplier (10).
 	DecompressedMessageSizeLimit int `mapstructure:"decompressed_message_size_limit" json:"decompressed_message_size_limit" envconfig:"decompressed_message_size_limit" yaml:"decompressed_message_size_limit" toml:"decompressed_message_size_limit" doc:"Maximum allowed WebSocket message size in bytes after permessage-deflate decompression. Only used when compression is enabled. Zero derives the limit from message_size_limit times the default multiplier (10)."`
+
+	// CompressionContext contains permessage-deflate context takeover options.
+	CompressionContext WebSocketCompressionContext `mapstructure:"compression_context" json:"compression_context" envconfig:"compression_context" yaml:"compression_context" toml:"compression_context" doc:"Configuration for permessage-deflate context takeover. When enabled, the bidirectional WebSocket endpoint is served by Centrifugo's own WebSocket handler."`
+}
+
+// WebSocketCompressionContext configures permessage-deflate context takeover, where
+// each message is compressed using previous messages of the connection as a history.
+type WebSocketCompressionContext struct {
+	Enabled     bool `mapstructure:"enabled" json:"enabled" envconfig:"enabled" yaml:"enabled" toml:"enabled" doc:"Enables negotiating permessage-deflate context takeover. Requires <<compression>>. Improves compression ratio for small similar messages at the cost of CPU and a sliding window per connection and direction. Compression levels below 7 are raised to 7 for connections with context takeover."`
+	WindowBits  int  `mapstructure:"window_bits" json:"window_bits" envconfig:"window_bits" default:"15" yaml:"window_bits" toml:"window_bits" doc:"Size of per connection sliding window as a power of two, from 9 to 15. Lower values reduce memory and CPU usage. When less than 15, client context takeover is only used if the client supports <<client_max_window_bits>>. Default <<15>>."`
+	MemoryLimit int  `mapstructure:"memory_limit" json:"memory_limit" envconfig:"memory_limit" yaml:"memory_limit" toml:"memory_limit" doc:"Maximum total memory in bytes for sliding windows across all connections of the transport. New connections over the limit fall back to no context takeover. Zero means no limit."`
 }
 
 // SSE client real-time transport configuration.
@@ -110,6 +233,8 @@ type SSE struct {
 	Enabled            bool   `mapstructure:"enabled" json:"enabled" envconfig:"enabled" yaml:"enabled" toml:"enabled" doc:"Enables the SSE (Server-Sent Events) bidirectional emulation transport."`
 	HandlerPrefix      string `mapstructure:"handler_prefix" json:"handler_prefix" envconfig:"handler_prefix" default:"/connection/sse" yaml:"handler_prefix" toml:"handler_prefix" expose:"full" doc:"URL prefix for the SSE handler. Default <</connection/sse>>."`
 	MaxRequestBodySize int    `mapstructure:"max_request_body_size" json:"max_request_body_size" envconfig:"max_request_body_size" default:"65536" yaml:"max_request_body_size" toml:"max_request_body_size" doc:"Maximum allowed request body size in bytes for SSE connect requests. Default <<65536>>."`
+
+	WriteCoalescing WriteCoalescing `mapstructure:"write_coalescing" json:"write_coalescing" envconfig:"write_coalescing" yaml:"write_coalescing" toml:"write_coalescing" doc:"Configuration for coalescing writes of the SSE transport."`
 }
 
 // HTTPStream client real-time transport configuration.
@@ -117,6 +242,20 @@ type HTTPStream struct {
 	Enabled            bool   `mapstructure:"enabled" json:"enabled" envconfig:"enabled" yaml:"enabled" toml:"enabled" doc:"Enables the HTTP streaming bidirectional emulation transport."`
 	HandlerPrefix      string `mapstructure:"handler_prefix" json:"handler_prefix" envconfig:"handler_prefix" default:"/connection/http_stream" yaml:"handler_prefix" toml:"handler_prefix" expose:"full" doc:"URL prefix for the HTTP streaming handler. Default <</connection/http_stream>>."`
 	MaxRequestBodySize int    `mapstructure:"max_request_body_size" json:"max_request_body_size" envconfig:"max_request_body_size" default:"65536" yaml:"max_request_body_size" toml:"max_request_body_size" doc:"Maximum allowed request body size in bytes for HTTP stream connect requests. Default <<65536>>."`
+
+	WriteCoalescing WriteCoalescing `mapstructure:"write_coalescing" json:"write_coalescing" envconfig:"write_coalescing" yaml:"write_coalescing" toml:"write_coalescing" doc:"Configuration for coalescing writes of the HTTP streaming transport."`
+}
+
+// LongPoll client real-time transport configuration.
+type LongPoll struct {
+	Enabled            bool     `mapstructure:"enabled" json:"enabled" envconfig:"enabled" yaml:"enabled" toml:"enabled" doc:"Enables the HTTP long-polling bidirectional emulation transport."`
+	HandlerPrefix      string   `mapstructure:"handler_prefix" json:"handler_prefix" envconfig:"handler_prefix" default:"/connection/long_poll" yaml:"handler_prefix" toml:"handler_prefix" expose:"full" doc:"URL prefix for the long-polling handler. Connect requests are sent to the prefix, poll requests to <<prefix/poll>>. Default <</connection/long_poll>>."`
+	MaxRequestBodySize int      `mapstructure:"max_request_body_size" json:"max_request_body_size" envconfig:"max_request_body_size" default:"65536" yaml:"max_request_body_size" toml:"max_request_body_size" doc:"Maximum allowed request body size in bytes for long-polling connect and poll requests. Default <<65536>>."`
+	PollTimeout        Duration `mapstructure:"poll_timeout" json:"poll_timeout" envconfig:"poll_timeout" default:"25s" yaml:"poll_timeout" toml:"poll_timeout" doc:"How long a poll request waits for new messages before returning an empty response. Should be lower than proxy and HTTP server write timeouts. Default <<25s>>."`
+	SessionTimeout     Duration `mapstructure:"session_timeout" json:"session_timeout" envconfig:"session_timeout" default:"60s" yaml:"session_timeout" toml:"session_timeout" doc:"Connection is closed when the client does not send a poll request within this interval. Must be greater than poll_timeout. Default <<60s>>."`
+	BatchMaxSize       int      `mapstructure:"batch_max_size" json:"batch_max_size" envconfig:"batch_max_size" default:"1000" yaml:"batch_max_size" toml:"batch_max_size" doc:"Maximum number of messages returned in one poll response. Default <<1000>>."`
+	BatchMaxDelay      Duration `mapstructure:"batch_max_delay" json:"batch_max_delay" envconfig:"batch_max_delay" yaml:"batch_max_delay" toml:"batch_max_delay" doc:"How long a poll request waits for more messages after the first one arrived, to return them in one response. Zero returns messages immediately."`
+	MaxQueueSize       int      `mapstructure:"max_queue_size" json:"max_queue_size" envconfig:"max_queue_size" default:"10000" yaml:"max_queue_size" toml:"max_queue_size" doc:"Maximum number of messages buffered for a client between polls. The client is disconnected when the queue overflows. Default <<10000>>."`
 }
 
 // WebTransport client real-time transport configuration.
@@ -159,6 +298,9 @@ type UniWebSocket struct {
 	// overhead when sending many small messages. The client side must be ready to handle such
 	// joined messages coming in one WebSocket frame.
 	JoinPushMessages bool `mapstructure:"join_push_messages" json:"join_push_messages" envconfig:"join_push_messages" yaml:"join_push_messages" toml:"join_push_messages" doc:"Joins multiple push messages into a single WebSocket frame using protocol delimiters, reducing system call overhead when sending many small messages. The client must support joined frames."`
+
+	// CompressionContext contains permessage-deflate context takeover options.
+	CompressionContext WebSocketCompressionContext `mapstructure:"compression_context" json:"compression_context" envconfig:"compression_context" yaml:"compression_context" toml:"compression_context" doc:"Configuration for permessage-deflate context takeover for the unidirectional WebSocket transport."`
 }
 
 // UniHTTPStream client real-time transport configuration.
@@ -167,6 +309,8 @@ type UniHTTPStream struct {
 	HandlerPrefix             string                    `mapstructure:"handler_prefix" json:"handler_prefix" envconfig:"handler_prefix" default:"/connection/uni_http_stream" yaml:"handler_prefix" toml:"handler_prefix" expose:"full" doc:"URL prefix for the unidirectional HTTP streaming handler. Default <</connection/uni_http_stream>>."`
 	MaxRequestBodySize        int                       `mapstructure:"max_request_body_size" json:"max_request_body_size" envconfig:"max_request_body_size" default:"65536" yaml:"max_request_body_size" toml:"max_request_body_size" doc:"Maximum allowed request body size in bytes for unidirectional HTTP stream connect requests. Default <<65536>>."`
 	ConnectCodeToHTTPResponse ConnectCodeToHTTPResponse `mapstructure:"connect_code_to_http_response" json:"connect_code_to_http_response" envconfig:"connect_code_to_http_response" yaml:"connect_code_to_http_response" toml:"connect_code_to_http_response" doc:"Configuration for mapping Centrifugo connect error codes to HTTP response status codes and body for the unidirectional HTTP stream transport."`
+
+	WriteCoalescing WriteCoalescing `mapstructure:"write_coalescing" json:"write_coalescing" envconfig:"write_coalescing" yaml:"write_coalescing" toml:"write_coalescing" doc:"Configuration for coalescing writes of the unidirectional HTTP stream transport."`
 }
 
 // UniSSE client real-time transport configuration.
@@ -175,6 +319,15 @@ type UniSSE struct {
 	HandlerPrefix             string                    `mapstructure:"handler_prefix" json:"handler_prefix" envconfig:"handler_prefix" default:"/connection/uni_sse" yaml:"handler_prefix" toml:"handler_prefix" expose:"full" doc:"URL prefix for the unidirectional SSE handler. Default <</connection/uni_sse>>."`
 	MaxRequestBodySize        int                       `mapstructure:"max_request_body_size" json:"max_request_body_size" envconfig:"max_request_body_size" default:"65536" yaml:"max_request_body_size" toml:"max_request_body_size" doc:"Maximum allowed request body size in bytes for unidirectional SSE connect requests. Default <<65536>>."`
 	ConnectCodeToHTTPResponse ConnectCodeToHTTPResponse `mapstructure:"connect_code_to_http_response" json:"connect_code_to_http_response" envconfig:"connect_code_to_http_response" yaml:"connect_code_to_http_response" toml:"connect_code_to_http_response" doc:"Configuration for mapping Centrifugo connect error codes to HTTP response status codes and body for the unidirectional SSE transport."`
+
+	WriteCoalescing WriteCoalescing `mapstructure:"write_coalescing" json:"write_coalescing" envconfig:"write_coalescing" yaml:"write_coalescing" toml:"write_coalescing" doc:"Configuration for coalescing writes of the unidirectional SSE transport."`
+}
+
+// WriteCoalescing configures joining messages sent to a streaming connection
+// within a short window into one network write.
+type WriteCoalescing struct {
+	Window        Duration `mapstructure:"window" json:"window" envconfig:"window" yaml:"window" toml:"window" doc:"How long to wait for more messages after the first one before writing them to connection together. Zero disables coalescing – messages are written immediately."`
+	MaxBatchBytes int      `mapstructure:"max_batch_bytes" json:"max_batch_bytes" envconfig:"max_batch_bytes" default:"16384" yaml:"max_batch_bytes" toml:"max_batch_bytes" doc:"Pending messages are written without waiting for the window end once their total size reaches this number of bytes. Default <<16384>>."`
 }
 
 type ConnInit struct {
@@ -220,6 +373,24 @@ type UniGRPC struct {
 	TLS                   TLSConfig `mapstructure:"tls" json:"tls" envconfig:"tls" yaml:"tls" toml:"tls" doc:"TLS configuration for the unidirectional gRPC server."`
 }
 
+// BidiGRPC client real-time transport configuration.
+type BidiGRPC struct {
+	Enabled               bool          `mapstructure:"enabled" json:"enabled" envconfig:"enabled" yaml:"enabled" toml:"enabled" doc:"Enables the bidirectional gRPC transport."`
+	Address               string        `mapstructure:"address" json:"address" envconfig:"address" yaml:"address" toml:"address" expose:"full" doc:"Address (host) to bind the bidirectional gRPC server to."`
+	Port                  int           `mapstructure:"port" json:"port" envconfig:"port" default:"11001" yaml:"port" toml:"port" doc:"Port to bind the bidirectional gRPC server to. Default <<11001>>."`
+	MaxReceiveMessageSize int           `mapstructure:"max_receive_message_size" json:"max_receive_message_size" envconfig:"max_receive_message_size" yaml:"max_receive_message_size" toml:"max_receive_message_size" doc:"Maximum allowed gRPC message size in bytes for the bidirectional gRPC transport. Zero uses the gRPC default."`
+	TLS                   TLSConfig     `mapstructure:"tls" json:"tls" envconfig:"tls" yaml:"tls" toml:"tls" doc:"TLS configuration for the bidirectional gRPC server."`
+	PingPong              PingPong      `mapstructure:"ping_pong" json:"ping_pong" envconfig:"ping_pong" yaml:"ping_pong" toml:"ping_pong" doc:"Application-level ping-pong configuration for bidirectional gRPC connections. Service clients usually tolerate longer intervals than browsers."`
+	Keepalive             GRPCKeepalive `mapstructure:"keepalive" json:"keepalive" envconfig:"keepalive" yaml:"keepalive" toml:"keepalive" doc:"HTTP/2 keepalive configuration of the bidirectional gRPC server, detects dead connections below application-level ping-pong."`
+}
+
+// GRPCKeepalive configures HTTP/2 keepalive of gRPC server.
+type GRPCKeepalive struct {
+	Time    Duration `mapstructure:"time" json:"time" envconfig:"time" default:"25s" yaml:"time" toml:"time" doc:"How often server sends HTTP/2 PING frames over idle connections. Default <<25s>>."`
+	Timeout Duration `mapstructure:"timeout" json:"timeout" envconfig:"timeout" default:"5s" yaml:"timeout" toml:"timeout" doc:"How long server waits for HTTP/2 PING acknowledgement before closing connection. Default <<5s>>."`
+	MinTime Duration `mapstructure:"min_time" json:"min_time" envconfig:"min_time" default:"5s" yaml:"min_time" toml:"min_time" doc:"Minimum interval between client keepalive pings, connections of clients pinging more often are closed. Default <<5s>>."`
+}
+
 // PingPong allows configuring application level ping-pong behavior.
 // Note that in current implementation PingPongConfig.PingInterval must be greater than PingPongConfig.PongTimeout.
 type PingPong struct {
@@ -321,6 +492,22 @@ type HttpAPI struct {
 	ErrorMode     string `mapstructure:"error_mode" json:"error_mode" envconfig:"error_mode" yaml:"error_mode" toml:"error_mode" expose:"full" doc:"Controls error response format for the HTTP API. Possible values: <<transport>> (default), <<custom>>."`
 	External      bool   `mapstructure:"external" json:"external" envconfig:"external" yaml:"external" toml:"external" doc:"Runs the HTTP API on the external port instead of the internal port."`
 	Insecure      bool   `mapstructure:"insecure" json:"insecure" envconfig:"insecure" yaml:"insecure" toml:"insecure" doc:"Disables API key authentication for the HTTP API. Use only in trusted network environments."`
+
+	// NamedKeys are additional API keys which allow to identify callers.
+	NamedKeys NamedAPIKeys `mapstructure:"named_keys" default:"[]" json:"named_keys" envconfig:"named_keys" yaml:"named_keys" toml:"named_keys" doc:"Additional API keys with names. Name of used key is recorded as caller identity <<api_key:{name}>> in audit log."`
+}
+
+type NamedAPIKeys []NamedAPIKey
+
+// Decode to implement the envconfig.Decoder interface
+func (d *NamedAPIKeys) Decode(value string) error {
+	return decodeToNamedSlice(value, d)
+}
+
+// NamedAPIKey is an API key with a name identifying caller.
+type NamedAPIKey struct {
+	Name string `mapstructure:"name" json:"name" envconfig:"name" yaml:"name" toml:"name" expose:"full" doc:"Name of API key, must be unique. Must match <<^[-a-zA-Z0-9_.]{2,}$>> regular expression."`
+	Key  string `mapstructure:"key" json:"key" envconfig:"key" yaml:"key" toml:"key" doc:"API key value."`
 }
 
 type GrpcAPI struct {
@@ -332,6 +519,9 @@ type GrpcAPI struct {
 	TLS                   TLSConfig `mapstructure:"tls" json:"tls" envconfig:"tls" yaml:"tls" toml:"tls" doc:"TLS configuration for the gRPC API server."`
 	Reflection            bool      `mapstructure:"reflection" json:"reflection" envconfig:"reflection" yaml:"reflection" toml:"reflection" doc:"Enables gRPC server reflection, allowing tools like grpcurl to discover available methods."`
 	MaxReceiveMessageSize int       `mapstructure:"max_receive_message_size" json:"max_receive_message_size" envconfig:"max_receive_message_size" yaml:"max_receive_message_size" toml:"max_receive_message_size" doc:"Maximum size of a message received by the gRPC API server, in bytes. Zero uses the gRPC default."`
+
+	// NamedKeys are additional API keys which allow to identify callers.
+	NamedKeys NamedAPIKeys `mapstructure:"named_keys" default:"[]" json:"named_keys" envconfig:"named_keys" yaml:"named_keys" toml:"named_keys" doc:"Additional API keys with names. Name of used key is recorded as caller identity <<api_key:{name}>> in audit log."`
 }
 
 type Graphite struct {
@@ -393,6 +583,18 @@ type Dev struct {
 
 type Shutdown struct {
 	Timeout Duration `mapstructure:"timeout" json:"timeout" envconfig:"timeout" default:"30s" yaml:"timeout" toml:"timeout" doc:"Maximum time to wait for graceful shutdown before forcefully stopping. Default <<30s>>."`
+
+	Drain Drain `mapstructure:"drain" json:"drain" envconfig:"drain" yaml:"drain" toml:"drain" doc:"Configures connection draining – gradual disconnect of clients to avoid a reconnect stampede onto the remaining nodes."`
+}
+
+// Drain configures connection draining. Draining may be started over admin API or
+// with SIGUSR1 signal, and also on shutdown signal when enabled.
+type Drain struct {
+	Enabled       bool     `mapstructure:"enabled" json:"enabled" envconfig:"enabled" yaml:"enabled" toml:"enabled" doc:"Enables connection draining. When enabled, node drains connections upon shutdown signal before stopping, draining may also be started with SIGUSR1 signal or over admin API."`
+	Rate          int      `mapstructure:"rate" json:"rate" envconfig:"rate" default:"100" yaml:"rate" toml:"rate" doc:"Number of connections disconnected per second while draining. Default <<100>>."`
+	Timeout       Duration `mapstructure:"timeout" json:"timeout" envconfig:"timeout" default:"20s" yaml:"timeout" toml:"timeout" doc:"Maximum duration of draining upon shutdown, remaining connections are disconnected at once after it. Must be less than shutdown timeout. Default <<20s>>."`
+	MaxRetryAfter Duration `mapstructure:"max_retry_after" json:"max_retry_after" envconfig:"max_retry_after" default:"5s" yaml:"max_retry_after" toml:"max_retry_after" doc:"Upper bound of a random reconnect delay advised to each disconnected client in disconnect reason. Default <<5s>>."`
+	RedirectHints bool     `mapstructure:"redirect_hints" json:"redirect_hints" envconfig:"redirect_hints" yaml:"redirect_hints" toml:"redirect_hints" doc:"Includes the name of the least loaded other node into disconnect reason. Useful when node names are set to client-reachable addresses."`
 }
 
 type ConnectProxy struct {
@@ -480,6 +682,12 @@ type Client struct {
 	// ConnectCodeToUnidirectionalDisconnect is a configuration for a feature to transform connect error codes to the disconnect code
 	// for unidirectional transports.
 	ConnectCodeToUnidirectionalDisconnect ConnectCodeToUnidirectionalDisconnect `mapstructure:"connect_code_to_unidirectional_disconnect" json:"connect_code_to_unidirectional_disconnect" envconfig:"connect_code_to_unidirectional_disconnect" yaml:"connect_code_to_unidirectional_disconnect" toml:"connect_code_to_unidirectional_disconnect" doc:"Configuration for mapping connect error codes to disconnect codes for unidirectional transports."`
+
+	// TokenIntrospection allows verifying opaque connection tokens using RFC 7662 introspection endpoint.
+	TokenIntrospection TokenIntrospection `mapstructure:"token_introspection" json:"token_introspection" envconfig:"token_introspection" yaml:"token_introspection" toml:"token_introspection" doc:"Configuration for verifying opaque connection tokens using an OAuth 2.0 token introspection endpoint (RFC 7662) instead of JWT verification."`
+
+	// ConnectionQuota allows limiting connections per user and per IP across all nodes.
+	ConnectionQuota ConnectionQuota `mapstructure:"connection_quota" json:"connection_quota" envconfig:"connection_quota" yaml:"connection_quota" toml:"connection_quota" doc:"Configuration of per-user and per-IP connection quotas enforced cluster-wide. Unlike <<user_connection_limit>> and <<connection_limit>>, which are checked per node, quotas count connections on all nodes."`
 }
 
 type UniConnectCodeToDisconnectTransforms []UniConnectCodeToDisconnectTransform
@@ -525,6 +733,24 @@ type ChannelProxyContainer struct {
 	MapRemove Proxy `mapstructure:"map_remove" json:"map_remove" envconfig:"map_remove" yaml:"map_remove" toml:"map_remove" doc:"Default map remove proxy configuration used for channels that reference the proxy by name <<default>>."`
 	// SharedPollRefresh proxy configuration.
 	SharedPollRefresh Proxy `mapstructure:"shared_poll_refresh" json:"shared_poll_refresh" envconfig:"shared_poll_refresh" yaml:"shared_poll_refresh" toml:"shared_poll_refresh" doc:"Default shared poll refresh proxy configuration used for channels that reference the proxy by name <<default>>."`
+	// ChannelState proxy configuration.
+	ChannelState Proxy `mapstructure:"channel_state" json:"channel_state" envconfig:"channel_state" yaml:"channel_state" toml:"channel_state" doc:"Default channel state proxy configuration used for channels that reference the proxy by name <<default>>."`
+	// CacheEmpty proxy configuration.
+	CacheEmpty Proxy `mapstructure:"cache_empty" json:"cache_empty" envconfig:"cache_empty" yaml:"cache_empty" toml:"cache_empty" doc:"Default cache empty proxy configuration used for channels that reference the proxy by name <<default>>."`
+}
+
+// ChannelStateEvents configures tracking of channel occupancy in a cluster.
+type ChannelStateEvents struct {
+	// Type of store to track channel occupancy: "memory" or "redis".
+	Type string `mapstructure:"type" json:"type" envconfig:"type" default:"memory" yaml:"type" toml:"type" expose:"full" doc:"Store tracking which nodes have subscribers in a channel. <<memory>> only works for a single node setup, <<redis>> deduplicates events across nodes. Default <<memory>>."`
+	// Redis is a configuration for "redis" store.
+	Redis RedisPrefixed `mapstructure:"redis" json:"redis" envconfig:"redis" yaml:"redis" toml:"redis" doc:"Redis configuration, used when type is <<redis>>."`
+	// TTL is a time node is considered having subscribers in a channel without refresh.
+	TTL Duration `mapstructure:"ttl" json:"ttl" envconfig:"ttl" default:"60s" yaml:"ttl" toml:"ttl" doc:"How long a node is considered having subscribers in a channel without being refreshed, so that channels occupied by crashed nodes are eventually released. Used by <<redis>> type. Default <<60s>>."`
+	// BatchSize is a maximum number of events sent to proxy in one request.
+	BatchSize int `mapstructure:"batch_size" json:"batch_size" envconfig:"batch_size" default:"100" yaml:"batch_size" toml:"batch_size" doc:"Maximum number of events sent to the channel state proxy in one request. Default <<100>>."`
+	// BatchMaxDelay is a maximum time event waits before being sent to proxy.
+	BatchMaxDelay Duration `mapstructure:"batch_max_delay" json:"batch_max_delay" envconfig:"batch_max_delay" default:"100ms" yaml:"batch_max_delay" toml:"batch_max_delay" doc:"Maximum time an event may wait to be batched with others before being sent to the channel state proxy. Default <<100ms>>."`
 }
 
 type Channel struct {
@@ -537,6 +763,9 @@ type Channel struct {
 	// Namespaces is a list of channel namespaces. Each channel namespace can have its own set of rules.
 	Namespaces ChannelNamespaces `mapstructure:"namespaces" default:"[]" json:"namespaces" envconfig:"namespaces" yaml:"namespaces" toml:"namespaces" doc:"List of channel namespaces. Each namespace defines its own channel options and is matched by the channel name prefix."`
 
+	// StateEvents configures delivery of channel occupied and vacated events.
+	StateEvents ChannelStateEvents `mapstructure:"state_events" json:"state_events" envconfig:"state_events" yaml:"state_events" toml:"state_events" doc:"Configures how channel <<occupied>> and <<vacated>> events are tracked across nodes and batched for namespaces with channel state proxy enabled."`
+
 	// HistoryMetaTTL is a time how long to keep history meta information. This is a global option for all channels,
 	// but it can be overridden in channel namespace.
 	HistoryMetaTTL Duration `mapstructure:"history_meta_ttl" json:"history_meta_ttl" envconfig:"history_meta_ttl" default:"720h" yaml:"history_meta_ttl" toml:"history_meta_ttl" doc:"Global default for how long history stream meta information (offset and epoch) is retained. Default <<720h>> (30 days). Can be overridden per namespace."`
@@ -545,6 +774,9 @@ type Channel struct {
 	// Empty string means default behavior (reject empty data). Possible values: "", "json", "json_object", "binary".
 	PublicationDataFormat string `mapstructure:"publication_data_format" json:"publication_data_format" envconfig:"publication_data_format" default:"" yaml:"publication_data_format" toml:"publication_data_format" expose:"full" doc:"Global publication data format for all channels. Possible values: <<json>>, <<json_object>>, <<binary>>, or empty (default — reject empty data). Can be overridden per namespace."`
 
+	// Encryption contains keys for namespaces with encryption enabled.
+	Encryption ChannelEncryption `mapstructure:"encryption" json:"encryption" envconfig:"encryption" yaml:"encryption" toml:"encryption" doc:"Keys for envelope encryption of publication data in namespaces with <<encryption.enabled>>."`
+
 	// MaxLength is a maximum length of a channel name. This is a global option for all channels.
 	MaxLength int `mapstructure:"max_length" json:"max_length" envconfig:"max_length" default:"255" yaml:"max_length" toml:"max_length" doc:"Maximum allowed channel name length in characters. Default <<255>>."`
 	// PrivatePrefix is a prefix for private channels. Private channels can't be subscribed without
@@ -568,6 +800,8 @@ type RPC struct {
 	Namespaces RPCNamespaces `mapstructure:"namespaces" default:"[]" json:"namespaces" envconfig:"namespaces" yaml:"namespaces" toml:"namespaces" doc:"List of RPC namespaces, each with its own options and proxy configuration."`
 	// Ping is a configuration for RPC ping method.
 	Ping RPCPing `mapstructure:"ping" json:"ping" envconfig:"ping" yaml:"ping" toml:"ping" doc:"Built-in RPC ping method configuration."`
+	// HistoryRange is a configuration for RPC method to query channel history by time range.
+	HistoryRange RPCHistoryRange `mapstructure:"history_range" json:"history_range" envconfig:"history_range" yaml:"history_range" toml:"history_range" doc:"Built-in RPC method to query channel history by publication time range."`
 	// NamespaceBoundary allows to set a custom boundary for rpc namespaces.
 	NamespaceBoundary string `mapstructure:"namespace_boundary" json:"namespace_boundary" envconfig:"namespace_boundary" default:":" yaml:"namespace_boundary" toml:"namespace_boundary" expose:"full" doc:"Separator between the RPC namespace name and the method name. Default <<:>>."`
 }
@@ -579,6 +813,16 @@ type RPCPing struct {
 	Method string `mapstructure:"method" json:"method" envconfig:"method" default:"ping" yaml:"method" toml:"method" expose:"full" doc:"Name of the built-in RPC ping method. Default <<ping>>."`
 }
 
+// RPCHistoryRange is a configuration of built-in RPC method for history queries
+// by publication time range. Client protocol history request has no time range
+// fields, so clients use this RPC method instead.
+type RPCHistoryRange struct {
+	// Enabled allows to enable history range method.
+	Enabled bool `mapstructure:"enabled" json:"enabled" envconfig:"enabled" yaml:"enabled" toml:"enabled" doc:"Enables the built-in RPC method which returns channel history filtered by publication time range. Permission checks are the same as for client history requests."`
+	// Method can be used to override the name of history range method to use.
+	Method string `mapstructure:"method" json:"method" envconfig:"method" default:"history_range" yaml:"method" toml:"method" expose:"full" doc:"Name of the built-in RPC history range method. Default <<history_range>>."`
+}
+
 type NamedProxy struct {
 	// Name of proxy.
 	Name  string `mapstructure:"name" json:"name" envconfig:"name" yaml:"name" toml:"name" expose:"full" doc:"Unique name of the proxy. Referenced by this name in channel or RPC namespace configuration."`
@@ -632,6 +876,37 @@ type Admin struct {
 	WebProxyAddress string `mapstructure:"web_proxy_address" json:"web_proxy_address" envconfig:"web_proxy_address" yaml:"web_proxy_address" toml:"web_proxy_address" expose:"full" doc:"Address of a running admin web app to proxy requests to. Used for local development of the admin UI."`
 	// External is a flag to run admin interface on external port.
 	External bool `mapstructure:"external" json:"external" envconfig:"external" yaml:"external" toml:"external" doc:"Serves the admin UI on the external HTTP port instead of the internal port."`
+	// SessionTTL is a lifetime of admin session token issued after login.
+	SessionTTL Duration `mapstructure:"session_ttl" json:"session_ttl" envconfig:"session_ttl" default:"8h" yaml:"session_ttl" toml:"session_ttl" doc:"Lifetime of admin session issued after password or OIDC login. Default <<8h>>."`
+	// OIDC configures admin login over OpenID Connect.
+	OIDC AdminOIDC `mapstructure:"oidc" json:"oidc" envconfig:"oidc" yaml:"oidc" toml:"oidc" doc:"Configures admin web UI login over OpenID Connect with roles mapped from ID token claims."`
+}
+
+// AdminOIDC configures admin login with OpenID Connect authorization code flow with PKCE.
+// Admin role is taken from ID token claim values.
+type AdminOIDC struct {
+	// Enabled turns on OIDC login for admin web UI.
+	Enabled bool `mapstructure:"enabled" json:"enabled" envconfig:"enabled" yaml:"enabled" toml:"enabled" doc:"Enables admin login over OpenID Connect. Admin <<secret>> must be set to sign admin session tokens."`
+	// Issuer is an OpenID provider issuer URL, provider metadata is discovered from it.
+	Issuer string `mapstructure:"issuer" json:"issuer" envconfig:"issuer" yaml:"issuer" toml:"issuer" expose:"full" doc:"OpenID provider issuer URL. Provider endpoints are discovered from <<{issuer}/.well-known/openid-configuration>>."`
+	// ClientID is a client ID registered at OpenID provider.
+	ClientID string `mapstructure:"client_id" json:"client_id" envconfig:"client_id" yaml:"client_id" toml:"client_id" expose:"full" doc:"Client ID registered at OpenID provider. ID tokens must contain it in <<aud>> claim."`
+	// ClientSecret is a client secret, may be empty for public clients.
+	ClientSecret string `mapstructure:"client_secret" json:"client_secret" envconfig:"client_secret" yaml:"client_secret" toml:"client_secret" doc:"Client secret sent in token request. May be empty for public clients since PKCE is always used."`
+	// RedirectURL is a full URL of admin OIDC callback endpoint.
+	RedirectURL string `mapstructure:"redirect_url" json:"redirect_url" envconfig:"redirect_url" yaml:"redirect_url" toml:"redirect_url" expose:"full" doc:"Full URL of admin callback endpoint registered at OpenID provider, like <<https://centrifugo.example.com/admin/oidc/callback>>."`
+	// Scopes requested from OpenID provider.
+	Scopes []string `mapstructure:"scopes" json:"scopes" envconfig:"scopes" yaml:"scopes" toml:"scopes" expose:"full" doc:"Scopes requested from OpenID provider. When empty <<openid>>, <<profile>> and <<email>> are requested. <<openid>> is always added."`
+	// IdentityClaim is an ID token claim identifying admin in logs.
+	IdentityClaim string `mapstructure:"identity_claim" json:"identity_claim" envconfig:"identity_claim" default:"email" yaml:"identity_claim" toml:"identity_claim" expose:"full" doc:"ID token claim used as admin identity in admin action logs. Falls back to <<sub>> when claim is missing. Default <<email>>."`
+	// RolesClaim is an ID token claim with role values, string or array of strings.
+	RolesClaim string `mapstructure:"roles_claim" json:"roles_claim" envconfig:"roles_claim" default:"roles" yaml:"roles_claim" toml:"roles_claim" expose:"full" doc:"ID token claim containing role values, string or array of strings. Nested claims may be addressed with dots, like <<realm_access.roles>>. Default <<roles>>."`
+	// ViewerRoles are claim values granting viewer role.
+	ViewerRoles []string `mapstructure:"viewer_roles" json:"viewer_roles" envconfig:"viewer_roles" yaml:"viewer_roles" toml:"viewer_roles" expose:"full" doc:"Claim values granting <<viewer>> role: read-only commands like info, channels, presence and history."`
+	// OperatorRoles are claim values granting operator role.
+	OperatorRoles []string `mapstructure:"operator_roles" json:"operator_roles" envconfig:"operator_roles" yaml:"operator_roles" toml:"operator_roles" expose:"full" doc:"Claim values granting <<operator>> role: viewer commands plus publish, broadcast, subscribe, unsubscribe, disconnect and refresh."`
+	// AdminRoles are claim values granting admin role.
+	AdminRoles []string `mapstructure:"admin_roles" json:"admin_roles" envconfig:"admin_roles" yaml:"admin_roles" toml:"admin_roles" expose:"full" doc:"Claim values granting <<admin>> role: all commands, including history removal, RPC and node draining."`
 }
 
 type TransformError struct {
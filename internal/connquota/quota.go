@@ -0,0 +1,190 @@
+// Package connquota enforces per-user and per-IP connection quotas across all
+// Centrifugo nodes.
+package connquota
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/rs/zerolog/log"
+)
+
+type Config = configtypes.ConnectionQuota
+
+const (
+	// PolicyReject rejects a new connection when quota is exhausted.
+	PolicyReject = "reject"
+	// PolicyEvictOldest disconnects the oldest connections to make room for a new one.
+	PolicyEvictOldest = "evict_oldest"
+)
+
+// Disconnects used by quotas. Clients should not reconnect automatically: reconnect
+// will be rejected again or will evict another connection.
+var (
+	DisconnectUserQuota = centrifuge.Disconnect{Code: 3520, Reason: "user connection quota exceeded"}
+	DisconnectIPQuota   = centrifuge.Disconnect{Code: 3521, Reason: "ip connection quota exceeded"}
+	DisconnectEvicted   = centrifuge.Disconnect{Code: 3522, Reason: "evicted by connection quota"}
+)
+
+type quotaKey struct {
+	// name is used as metric label.
+	name       string
+	key        string
+	limit      int
+	disconnect centrifuge.Disconnect
+}
+
+type registration struct {
+	entry Entry
+	keys  []quotaKey
+}
+
+// Quota checks connection quotas before connection is established and keeps
+// connections of this node registered in Store while they are alive.
+type Quota struct {
+	node   *centrifuge.Node
+	store  Store
+	config Config
+
+	mu          sync.Mutex
+	connections map[string]registration
+}
+
+// New creates Quota.
+func New(node *centrifuge.Node, store Store, cfg Config) *Quota {
+	return &Quota{
+		node:        node,
+		store:       store,
+		config:      cfg,
+		connections: make(map[string]registration),
+	}
+}
+
+func (q *Quota) keys(userID string, ip string) []quotaKey {
+	var keys []quotaKey
+	if q.config.UserLimit > 0 && userID != "" {
+		keys = append(keys, quotaKey{name: "user", key: "user:" + userID, limit: q.config.UserLimit, disconnect: DisconnectUserQuota})
+	}
+	if q.config.IPLimit > 0 && ip != "" {
+		keys = append(keys, quotaKey{name: "ip", key: "ip:" + ip, limit: q.config.IPLimit, disconnect: DisconnectIPQuota})
+	}
+	return keys
+}
+
+// Check returns non-nil Disconnect if connection must be rejected. With evict_oldest
+// policy the oldest connections are disconnected instead. Connections which are
+// checked concurrently on different nodes may slightly exceed quota.
+func (q *Quota) Check(ctx context.Context, clientID string, userID string, ip string) (*centrifuge.Disconnect, error) {
+	for _, k := range q.keys(userID, ip) {
+		entries, err := q.store.Entries(ctx, k.key)
+		if err != nil {
+			return nil, err
+		}
+		numExceeding := len(entries) - k.limit + 1
+		if numExceeding <= 0 {
+			continue
+		}
+		if q.config.Policy != PolicyEvictOldest {
+			metrics.IncConnQuotaRejected(k.name)
+			disconnect := k.disconnect
+			return &disconnect, nil
+		}
+		for _, entry := range entries[:numExceeding] {
+			if entry.ClientID == clientID {
+				continue
+			}
+			if err := q.evict(ctx, k, entry); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (q *Quota) evict(ctx context.Context, k quotaKey, entry Entry) error {
+	err := q.node.Disconnect(
+		entry.UserID,
+		centrifuge.WithCustomDisconnect(DisconnectEvicted),
+		centrifuge.WithDisconnectClient(entry.ClientID),
+	)
+	if err != nil {
+		return err
+	}
+	metrics.IncConnQuotaEvicted(k.name)
+	// Node of evicted connection removes it from all keys upon disconnect, but
+	// this happens asynchronously – so remove from the checked key right away.
+	return q.store.Remove(ctx, k.key, entry)
+}
+
+// Register starts counting connection in quotas.
+func (q *Quota) Register(ctx context.Context, clientID string, userID string, ip string) {
+	keys := q.keys(userID, ip)
+	if len(keys) == 0 {
+		return
+	}
+	r := registration{
+		entry: Entry{ClientID: clientID, UserID: userID, ConnectedAt: time.Now().UnixMilli()},
+		keys:  keys,
+	}
+	q.mu.Lock()
+	q.connections[clientID] = r
+	q.mu.Unlock()
+	q.add(ctx, r)
+}
+
+// Unregister stops counting connection in quotas.
+func (q *Quota) Unregister(ctx context.Context, clientID string) {
+	q.mu.Lock()
+	r, ok := q.connections[clientID]
+	delete(q.connections, clientID)
+	q.mu.Unlock()
+	if !ok {
+		return
+	}
+	for _, k := range r.keys {
+		if err := q.store.Remove(ctx, k.key, r.entry); err != nil {
+			log.Error().Err(err).Str("client", clientID).Str("quota", k.name).Msg("error removing connection from quota")
+		}
+	}
+}
+
+func (q *Quota) add(ctx context.Context, r registration) {
+	for _, k := range r.keys {
+		if err := q.store.Add(ctx, k.key, r.entry, q.config.TTL.ToDuration()); err != nil {
+			log.Error().Err(err).Str("client", r.entry.ClientID).Str("quota", k.name).Msg("error adding connection to quota")
+		}
+	}
+}
+
+// Run periodically refreshes connections of this node in Store so that they are
+// not expired while alive.
+func (q *Quota) Run(ctx context.Context) error {
+	interval := max(q.config.TTL.ToDuration()/3, time.Second)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			q.refresh(ctx)
+		}
+	}
+}
+
+func (q *Quota) refresh(ctx context.Context) {
+	q.mu.Lock()
+	registrations := make([]registration, 0, len(q.connections))
+	for _, r := range q.connections {
+		registrations = append(registrations, r)
+	}
+	q.mu.Unlock()
+	for _, r := range registrations {
+		q.add(ctx, r)
+	}
+}
@@ -0,0 +1,132 @@
+package connquota
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+	"github.com/centrifugal/centrifugo/v6/internal/tools"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	_ = metrics.Init(metrics.Config{
+		Registerer: prometheus.NewRegistry(),
+	})
+	os.Exit(m.Run())
+}
+
+func newTestQuota(t *testing.T, cfg Config) (*Quota, Store) {
+	t.Helper()
+	node := tools.NodeWithMemoryEngineNoHandlers()
+	t.Cleanup(func() { _ = node.Shutdown(context.Background()) })
+	presenceManager, err := centrifuge.NewMemoryPresenceManager(node, centrifuge.MemoryPresenceManagerConfig{})
+	require.NoError(t, err)
+	store := NewPresenceStore(presenceManager)
+	if cfg.TTL == 0 {
+		cfg.TTL = configtypes.Duration(time.Minute)
+	}
+	return New(node, store, cfg), store
+}
+
+func TestPresenceStore(t *testing.T) {
+	_, store := newTestQuota(t, Config{})
+	ctx := context.Background()
+	require.NoError(t, store.Add(ctx, "user:42", Entry{ClientID: "c2", UserID: "42", ConnectedAt: 2}, time.Minute))
+	require.NoError(t, store.Add(ctx, "user:42", Entry{ClientID: "c1", UserID: "42", ConnectedAt: 1}, time.Minute))
+	require.NoError(t, store.Add(ctx, "user:43", Entry{ClientID: "c3", UserID: "43", ConnectedAt: 3}, time.Minute))
+
+	entries, err := store.Entries(ctx, "user:42")
+	require.NoError(t, err)
+	require.Equal(t, []Entry{
+		{ClientID: "c1", UserID: "42", ConnectedAt: 1},
+		{ClientID: "c2", UserID: "42", ConnectedAt: 2},
+	}, entries)
+
+	require.NoError(t, store.Remove(ctx, "user:42", Entry{ClientID: "c1", UserID: "42"}))
+	entries, err = store.Entries(ctx, "user:42")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "c2", entries[0].ClientID)
+}
+
+func TestQuota_Reject(t *testing.T) {
+	q, _ := newTestQuota(t, Config{UserLimit: 2, IPLimit: 3, Policy: PolicyReject})
+	ctx := context.Background()
+
+	q.Register(ctx, "c1", "42", "10.0.0.1")
+	q.Register(ctx, "c2", "42", "10.0.0.1")
+
+	rejectedBefore := testutil.ToFloat64(metrics.ConnQuotaRejectedTotal.WithLabelValues("user"))
+	disconnect, err := q.Check(ctx, "c3", "42", "10.0.0.1")
+	require.NoError(t, err)
+	require.NotNil(t, disconnect)
+	require.Equal(t, DisconnectUserQuota, *disconnect)
+	require.Equal(t, rejectedBefore+1, testutil.ToFloat64(metrics.ConnQuotaRejectedTotal.WithLabelValues("user")))
+
+	// Other user from the same IP is still allowed.
+	disconnect, err = q.Check(ctx, "c3", "43", "10.0.0.1")
+	require.NoError(t, err)
+	require.Nil(t, disconnect)
+	q.Register(ctx, "c3", "43", "10.0.0.1")
+
+	disconnect, err = q.Check(ctx, "c4", "44", "10.0.0.1")
+	require.NoError(t, err)
+	require.NotNil(t, disconnect)
+	require.Equal(t, DisconnectIPQuota, *disconnect)
+
+	// Quotas are released after disconnect.
+	q.Unregister(ctx, "c1")
+	disconnect, err = q.Check(ctx, "c4", "42", "10.0.0.2")
+	require.NoError(t, err)
+	require.Nil(t, disconnect)
+}
+
+func TestQuota_AnonymousNotLimitedByUser(t *testing.T) {
+	q, _ := newTestQuota(t, Config{UserLimit: 1, Policy: PolicyReject})
+	ctx := context.Background()
+	q.Register(ctx, "c1", "", "10.0.0.1")
+	disconnect, err := q.Check(ctx, "c2", "", "10.0.0.1")
+	require.NoError(t, err)
+	require.Nil(t, disconnect)
+}
+
+func TestQuota_EvictOldest(t *testing.T) {
+	q, store := newTestQuota(t, Config{UserLimit: 2, Policy: PolicyEvictOldest})
+	ctx := context.Background()
+
+	q.Register(ctx, "c1", "42", "")
+	time.Sleep(2 * time.Millisecond)
+	q.Register(ctx, "c2", "42", "")
+
+	evictedBefore := testutil.ToFloat64(metrics.ConnQuotaEvictedTotal.WithLabelValues("user"))
+	disconnect, err := q.Check(ctx, "c3", "42", "")
+	require.NoError(t, err)
+	require.Nil(t, disconnect)
+	require.Equal(t, evictedBefore+1, testutil.ToFloat64(metrics.ConnQuotaEvictedTotal.WithLabelValues("user")))
+
+	entries, err := store.Entries(ctx, "user:42")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "c2", entries[0].ClientID)
+}
+
+func TestQuota_Refresh(t *testing.T) {
+	q, store := newTestQuota(t, Config{UserLimit: 2})
+	ctx := context.Background()
+	q.Register(ctx, "c1", "42", "")
+	require.NoError(t, store.Remove(ctx, "user:42", Entry{ClientID: "c1", UserID: "42"}))
+
+	q.refresh(ctx)
+	entries, err := store.Entries(ctx, "user:42")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "c1", entries[0].ClientID)
+}
@@ -0,0 +1,121 @@
+package connquota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/redisshard"
+
+	"github.com/redis/rueidis"
+)
+
+// RedisStore is a Store on top of Redis. Connections of each key are kept in a
+// sorted set scored by expiration time. Keys are distributed over shards by hash.
+type RedisStore struct {
+	shards []*redisshard.RedisShard
+	prefix string
+}
+
+var _ Store = (*RedisStore)(nil)
+
+// NewRedisStore creates RedisStore.
+func NewRedisStore(cfg configtypes.RedisPrefixed) (*RedisStore, error) {
+	shards, err := redisshard.BuildRedisShards(cfg.Redis)
+	if err != nil {
+		return nil, fmt.Errorf("error building Redis shards for connection quota: %w", err)
+	}
+	if len(shards) == 0 {
+		return nil, fmt.Errorf("no Redis shards configured for connection quota")
+	}
+	return &RedisStore{shards: shards, prefix: cfg.Prefix}, nil
+}
+
+var (
+	// KEYS[1] – connections zset. ARGV[1] – expiration time in milliseconds,
+	// ARGV[2] – TTL in milliseconds, ARGV[3] – entry.
+	addScript = rueidis.NewLuaScript(`
+redis.call("zadd", KEYS[1], ARGV[1], ARGV[3])
+redis.call("pexpire", KEYS[1], ARGV[2])
+return 1
+`)
+	// KEYS[1] – connections zset. ARGV[1] – current time in milliseconds.
+	entriesScript = rueidis.NewLuaScript(`
+redis.call("zremrangebyscore", KEYS[1], "-inf", ARGV[1])
+return redis.call("zrange", KEYS[1], 0, -1)
+`)
+)
+
+func (s *RedisStore) shard(key string) *redisshard.RedisShard {
+	if len(s.shards) == 1 {
+		return s.shards[0]
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return s.shards[int(h.Sum32()%uint32(len(s.shards)))]
+}
+
+func (s *RedisStore) connectionsKey(key string) string {
+	return s.prefix + ".conn_quota." + key
+}
+
+// Add ...
+func (s *RedisStore) Add(ctx context.Context, key string, entry Entry, ttl time.Duration) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	args := []string{
+		strconv.FormatInt(time.Now().Add(ttl).UnixMilli(), 10),
+		strconv.FormatInt(ttl.Milliseconds(), 10),
+		string(data),
+	}
+	res := s.shard(key).RunOp(func(client rueidis.Client) rueidis.RedisResult {
+		return addScript.Exec(ctx, client, []string{s.connectionsKey(key)}, args)
+	})
+	return res.Error()
+}
+
+// Remove ...
+func (s *RedisStore) Remove(ctx context.Context, key string, entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	res := s.shard(key).RunOp(func(client rueidis.Client) rueidis.RedisResult {
+		return client.Do(ctx, client.B().Zrem().Key(s.connectionsKey(key)).Member(string(data)).Build())
+	})
+	return res.Error()
+}
+
+// Entries ...
+func (s *RedisStore) Entries(ctx context.Context, key string) ([]Entry, error) {
+	res := s.shard(key).RunOp(func(client rueidis.Client) rueidis.RedisResult {
+		return entriesScript.Exec(ctx, client, []string{s.connectionsKey(key)}, []string{strconv.FormatInt(time.Now().UnixMilli(), 10)})
+	})
+	values, err := res.AsStrSlice()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(values))
+	for _, value := range values {
+		var entry Entry
+		if err := json.Unmarshal([]byte(value), &entry); err != nil {
+			return nil, fmt.Errorf("malformed connection quota entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	sortEntries(entries)
+	return entries, nil
+}
+
+// Close releases Redis shard connections.
+func (s *RedisStore) Close() {
+	for _, shard := range s.shards {
+		shard.Close()
+	}
+}
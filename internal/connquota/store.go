@@ -0,0 +1,89 @@
+package connquota
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/centrifugal/centrifuge"
+)
+
+// Entry is a connection counted by quota.
+type Entry struct {
+	ClientID string `json:"c"`
+	UserID   string `json:"u,omitempty"`
+	// ConnectedAt is Unix time in milliseconds, used to find the oldest connections.
+	ConnectedAt int64 `json:"t"`
+}
+
+// Store keeps connections of quota keys shared between nodes.
+type Store interface {
+	// Add registers connection under key or refreshes it, connection is counted
+	// until ttl passes without refresh.
+	Add(ctx context.Context, key string, entry Entry, ttl time.Duration) error
+	// Remove unregisters connection under key.
+	Remove(ctx context.Context, key string, entry Entry) error
+	// Entries returns connections registered under key sorted from the oldest.
+	Entries(ctx context.Context, key string) ([]Entry, error)
+}
+
+// PresenceStore is a Store on top of centrifuge.PresenceManager. Each key is kept in
+// a hidden channel, its presence TTL is controlled by presence manager configuration.
+type PresenceStore struct {
+	presenceManager centrifuge.PresenceManager
+}
+
+var _ Store = (*PresenceStore)(nil)
+
+// NewPresenceStore creates PresenceStore.
+func NewPresenceStore(presenceManager centrifuge.PresenceManager) *PresenceStore {
+	return &PresenceStore{presenceManager: presenceManager}
+}
+
+// Channel name is not valid for client subscriptions since namespace does not exist.
+func presenceChannel(key string) string {
+	return "_connquota:" + key
+}
+
+// Add ...
+func (s *PresenceStore) Add(_ context.Context, key string, entry Entry, _ time.Duration) error {
+	return s.presenceManager.AddPresence(presenceChannel(key), entry.ClientID, &centrifuge.ClientInfo{
+		ClientID: entry.ClientID,
+		UserID:   entry.UserID,
+		ChanInfo: []byte(strconv.FormatInt(entry.ConnectedAt, 10)),
+	})
+}
+
+// Remove ...
+func (s *PresenceStore) Remove(_ context.Context, key string, entry Entry) error {
+	return s.presenceManager.RemovePresence(presenceChannel(key), entry.ClientID, entry.UserID)
+}
+
+// Entries ...
+func (s *PresenceStore) Entries(_ context.Context, key string) ([]Entry, error) {
+	presence, err := s.presenceManager.Presence(presenceChannel(key))
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(presence))
+	for _, info := range presence {
+		connectedAt, _ := strconv.ParseInt(string(info.ChanInfo), 10, 64)
+		entries = append(entries, Entry{
+			ClientID:    info.ClientID,
+			UserID:      info.UserID,
+			ConnectedAt: connectedAt,
+		})
+	}
+	sortEntries(entries)
+	return entries, nil
+}
+
+func sortEntries(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].ConnectedAt == entries[j].ConnectedAt {
+			return entries[i].ClientID < entries[j].ClientID
+		}
+		return entries[i].ConnectedAt < entries[j].ConnectedAt
+	})
+}
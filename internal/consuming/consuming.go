@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/centrifugal/centrifugo/v6/internal/api"
+	"github.com/centrifugal/centrifugo/v6/internal/clientcontext"
 	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
 	"github.com/centrifugal/centrifugo/v6/internal/metrics"
 	"github.com/centrifugal/centrifugo/v6/internal/service"
@@ -20,6 +21,21 @@ type Dispatcher interface {
 	DispatchPublication(ctx context.Context, channels []string, pub api.ConsumedPublication) error
 }
 
+// callerDispatcher puts consumer identity to context of dispatched commands so that
+// commands are attributed to consumer in audit log.
+type callerDispatcher struct {
+	dispatcher Dispatcher
+	caller     clientcontext.APICaller
+}
+
+func (d *callerDispatcher) DispatchCommand(ctx context.Context, method string, data []byte) error {
+	return d.dispatcher.DispatchCommand(clientcontext.SetAPICallerToContext(ctx, d.caller), method, data)
+}
+
+func (d *callerDispatcher) DispatchPublication(ctx context.Context, channels []string, pub api.ConsumedPublication) error {
+	return d.dispatcher.DispatchPublication(clientcontext.SetAPICallerToContext(ctx, d.caller), channels, pub)
+}
+
 type consumerCommon struct {
 	name   string
 	log    zerolog.Logger
@@ -27,7 +43,7 @@ type consumerCommon struct {
 }
 
 func New(nodeID string, consumingHandler *api.ConsumingHandler, configs []ConsumerConfig) ([]service.Service, error) {
-	dispatcher := api.NewDispatcher(consumingHandler)
+	apiDispatcher := api.NewDispatcher(consumingHandler)
 
 	var services []service.Service
 	for _, config := range configs {
@@ -43,6 +59,10 @@ func New(nodeID string, consumingHandler *api.ConsumingHandler, configs []Consum
 			log:    log.With().Str("consumer", config.Name).Logger(),
 			nodeID: nodeID,
 		}
+		dispatcher := &callerDispatcher{
+			dispatcher: apiDispatcher,
+			caller:     clientcontext.APICaller{Source: "consuming", Identity: config.Name},
+		}
 		// Initialize consumer metrics with zero values.
 		metrics.InitConsumerMetrics(config.Name)
 		var consumer service.Service
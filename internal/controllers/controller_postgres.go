@@ -37,7 +37,22 @@ func (c *PostgresController) tablePrefix() string {
 	return strings.TrimRight(c.conf.TablePrefix, "_") + "_"
 }
 
-var controllerSchemaVersion = 1
+var controllerSchemaVersion = 2
+
+//go:embed controller_postgres_migration_v2.sql
+var controllerMigrationV2 string
+
+// controllerSchemaMigrations maps target version to a migration SQL template
+// using the same __PREFIX__ placeholder as the schema template. Version 1 is
+// the baseline applied via full DDL. Fresh installs run only the DDL, so the
+// schema template must reflect the end state of all migrations.
+var controllerSchemaMigrations = map[int]string{
+	2: controllerMigrationV2,
+}
+
+func init() {
+	pgschema.ValidateMigrationMap("postgres-controller", controllerSchemaVersion, controllerSchemaMigrations)
+}
 
 // PostgresControllerConfig configures the PostgreSQL controller.
 type PostgresControllerConfig struct {
@@ -117,6 +132,10 @@ type controllerNames struct {
 	schemaVersion string // e.g. cf_controller_schema_version
 	publishFunc   string // e.g. cf_controller_publish
 	notifyChannel string // e.g. cf_controller_notify
+
+	sharedPollLease string // e.g. cf_shared_poll_lease
+	sharedPollKeys  string // e.g. cf_shared_poll_keys
+	sharedPollItems string // e.g. cf_shared_poll_items
 }
 
 func newControllerNames(prefix string) controllerNames {
@@ -128,6 +147,10 @@ func newControllerNames(prefix string) controllerNames {
 		schemaVersion: p + "controller_schema_version",
 		publishFunc:   p + "controller_publish",
 		notifyChannel: p + "controller_notify",
+
+		sharedPollLease: p + "shared_poll_lease",
+		sharedPollKeys:  p + "shared_poll_keys",
+		sharedPollItems: p + "shared_poll_items",
 	}
 }
 
@@ -152,6 +175,10 @@ type PostgresController struct {
 	// PollInterval instead. Production tolerates this race because
 	// PollInterval is the fallback.
 	notifyListenerReady atomic.Bool
+
+	// sharedPollLastSweep is a unix nano time of the last removal of expired
+	// shared poll coordination rows.
+	sharedPollLastSweep atomic.Int64
 }
 
 var _ centrifuge.Controller = (*PostgresController)(nil)
@@ -276,12 +303,14 @@ func (c *PostgresController) EnsureSchema(ctx context.Context) error {
 		}
 	}
 
-	// The controller has no migrations registered today (controllerSchemaVersion=1).
-	// When the first one ships it goes here, mirroring pgmapbroker/pgstreambroker
-	// — pgschema.ApplyMigrationInTx per version with a single MigrationVariant
-	// (controller has just one prefix), before the DDL render below. Migration
-	// SQL should use the same __PREFIX__ placeholder as the schema template
-	// so it works with any user-configured TablePrefix.
+	// Run pending migrations before DDL under an advisory lock, mirroring
+	// pgmapbroker — pgschema.ApplyMigrationInTx per version with a single
+	// MigrationVariant (controller has just one prefix).
+	if !isFresh {
+		if err := c.runMigrationsUnderLock(ctx, label); err != nil {
+			return err
+		}
+	}
 
 	// Render schema template.
 	prefix := c.tablePrefix()
@@ -312,6 +341,42 @@ func (c *PostgresController) EnsureSchema(ctx context.Context) error {
 	return pgschema.SetSchemaVersion(ctx, c.pool, label, controllerSchemaVersion, []string{c.names.schemaVersion})
 }
 
+// runMigrationsUnderLock re-reads schema_version under the migration advisory
+// lock (another node may have finished the upgrade while we were waiting) and
+// applies pending migrations, each in its own transaction with a version bump.
+func (c *PostgresController) runMigrationsUnderLock(ctx context.Context, label string) error {
+	release, err := pgschema.AcquireMigrationLock(ctx, c.pool, label)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	dbVersion, isFresh, err := pgschema.ReadSchemaVersion(ctx, c.pool, c.names.schemaVersion)
+	if err != nil {
+		return err
+	}
+	if isFresh {
+		return nil
+	}
+	if err := pgschema.CheckDowngrade(label, dbVersion, controllerSchemaVersion); err != nil {
+		return err
+	}
+	for v := dbVersion + 1; v <= controllerSchemaVersion; v++ {
+		sql, ok := controllerSchemaMigrations[v]
+		if !ok {
+			return fmt.Errorf("%s: missing controllerSchemaMigrations[%d] at runtime (controllerSchemaVersion=%d)", label, v, controllerSchemaVersion)
+		}
+		variants := []pgschema.MigrationVariant{{
+			SQL:          renderControllerTemplate(sql, c.tablePrefix()),
+			VersionTable: c.names.schemaVersion,
+		}}
+		if err := pgschema.ApplyMigrationInTx(ctx, c.pool, label, v, variants); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // splitControllerSchemaSQL separates DDL from function definitions.
 func splitControllerSchemaSQL(sql string) (ddl, funcs string) {
 	const marker = "CREATE OR REPLACE FUNCTION"
@@ -0,0 +1,35 @@
+-- Migration to schema version 2: coordinated shared poll state tables.
+-- Placeholders: __PREFIX__ → e.g. "cf_" (includes trailing underscore).
+-- Keep in sync with controller_postgres_schema.sql.
+CREATE TABLE IF NOT EXISTS __PREFIX__shared_poll_lease (
+    channel     TEXT PRIMARY KEY,
+    node_id     TEXT NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS __PREFIX__shared_poll_lease_expires_at_idx
+    ON __PREFIX__shared_poll_lease (expires_at);
+
+CREATE TABLE IF NOT EXISTS __PREFIX__shared_poll_keys (
+    channel     TEXT NOT NULL,
+    key         TEXT NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (channel, key)
+);
+
+CREATE INDEX IF NOT EXISTS __PREFIX__shared_poll_keys_expires_at_idx
+    ON __PREFIX__shared_poll_keys (expires_at);
+
+CREATE TABLE IF NOT EXISTS __PREFIX__shared_poll_items (
+    channel     TEXT NOT NULL,
+    key         TEXT NOT NULL,
+    version     BIGINT NOT NULL DEFAULT 0,
+    epoch       TEXT NOT NULL DEFAULT '',
+    data        BYTEA,
+    removed     BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (channel, key)
+);
+
+CREATE INDEX IF NOT EXISTS __PREFIX__shared_poll_items_expires_at_idx
+    ON __PREFIX__shared_poll_items (expires_at);
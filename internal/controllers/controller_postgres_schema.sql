@@ -5,6 +5,9 @@
 --   __PREFIX__controller_messages        — control message outbox (partitioned by created_at, daily)
 --   __PREFIX__controller_shard_lock      — per-shard serialization lock rows
 --   __PREFIX__controller_schema_version  — schema version tracking
+--   __PREFIX__shared_poll_lease          — coordinated shared poll channel leases
+--   __PREFIX__shared_poll_keys           — keys tracked in coordinated shared poll channels
+--   __PREFIX__shared_poll_items          — last polled state of coordinated shared poll keys
 --
 -- Functions:
 --   __PREFIX__controller_publish(BYTEA, TEXT, INTEGER) — shard lock + INSERT + NOTIFY
@@ -29,6 +32,41 @@ CREATE TABLE IF NOT EXISTS __PREFIX__controller_shard_lock (
     shard_id SMALLINT PRIMARY KEY
 );
 
+-- Coordinated shared poll state. Keep in sync with
+-- controller_postgres_migration_v2.sql.
+CREATE TABLE IF NOT EXISTS __PREFIX__shared_poll_lease (
+    channel     TEXT PRIMARY KEY,
+    node_id     TEXT NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS __PREFIX__shared_poll_lease_expires_at_idx
+    ON __PREFIX__shared_poll_lease (expires_at);
+
+CREATE TABLE IF NOT EXISTS __PREFIX__shared_poll_keys (
+    channel     TEXT NOT NULL,
+    key         TEXT NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (channel, key)
+);
+
+CREATE INDEX IF NOT EXISTS __PREFIX__shared_poll_keys_expires_at_idx
+    ON __PREFIX__shared_poll_keys (expires_at);
+
+CREATE TABLE IF NOT EXISTS __PREFIX__shared_poll_items (
+    channel     TEXT NOT NULL,
+    key         TEXT NOT NULL,
+    version     BIGINT NOT NULL DEFAULT 0,
+    epoch       TEXT NOT NULL DEFAULT '',
+    data        BYTEA,
+    removed     BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (channel, key)
+);
+
+CREATE INDEX IF NOT EXISTS __PREFIX__shared_poll_items_expires_at_idx
+    ON __PREFIX__shared_poll_items (expires_at);
+
 -- Schema version tracking.
 CREATE TABLE IF NOT EXISTS __PREFIX__controller_schema_version (
     id              INTEGER PRIMARY KEY,
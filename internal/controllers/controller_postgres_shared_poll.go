@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/sharedpoll"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// PostgresController also serves as a shared poll coordination store, so that
+// coordinated shared poll channels do not require a separate Redis setup.
+var _ sharedpoll.Store = (*PostgresController)(nil)
+
+const (
+	// sharedPollSweepInterval is how often expired shared poll rows are removed.
+	sharedPollSweepInterval = time.Minute
+	// sharedPollSweepLimit limits the number of expired rows removed from each
+	// shared poll table at once.
+	sharedPollSweepLimit = 1000
+)
+
+// AcquireLease ...
+func (c *PostgresController) AcquireLease(ctx context.Context, channel string, nodeID string, ttl time.Duration) (bool, error) {
+	var holder string
+	err := c.pool.QueryRow(ctx, fmt.Sprintf(`
+		INSERT INTO %[1]s (channel, node_id, expires_at) VALUES ($1, $2, NOW() + $3 * INTERVAL '1 millisecond')
+		ON CONFLICT (channel) DO UPDATE SET node_id = EXCLUDED.node_id, expires_at = EXCLUDED.expires_at
+		WHERE %[1]s.node_id = EXCLUDED.node_id OR %[1]s.expires_at < NOW()
+		RETURNING node_id
+	`, c.names.sharedPollLease), channel, nodeID, ttl.Milliseconds()).Scan(&holder)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return holder == nodeID, nil
+}
+
+// TrackKeys ...
+func (c *PostgresController) TrackKeys(ctx context.Context, channel string, keys []string, ttl time.Duration) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	batch := &pgx.Batch{}
+	batch.Queue(fmt.Sprintf(`
+		INSERT INTO %s (channel, key, expires_at)
+		SELECT $1, k, NOW() + $3 * INTERVAL '1 millisecond' FROM UNNEST($2::text[]) AS k
+		ON CONFLICT (channel, key) DO UPDATE SET expires_at = EXCLUDED.expires_at
+	`, c.names.sharedPollKeys), channel, keys, ttl.Milliseconds())
+	batch.Queue(fmt.Sprintf(`
+		UPDATE %s SET expires_at = NOW() + $3 * INTERVAL '1 millisecond'
+		WHERE channel = $1 AND key = ANY($2::text[])
+	`, c.names.sharedPollItems), channel, keys, ttl.Milliseconds())
+	return c.pool.SendBatch(ctx, batch).Close()
+}
+
+// TrackedKeys ...
+func (c *PostgresController) TrackedKeys(ctx context.Context, channel string) ([]string, error) {
+	// Called by channel leaders once per refresh interval, a good moment to
+	// remove expired rows of all channels from time to time.
+	now := time.Now().UnixNano()
+	last := c.sharedPollLastSweep.Load()
+	if now-last > int64(sharedPollSweepInterval) && c.sharedPollLastSweep.CompareAndSwap(last, now) {
+		if err := c.sweepSharedPoll(ctx); err != nil {
+			c.logError("error removing expired shared poll rows", err)
+		}
+	}
+	rows, err := c.pool.Query(ctx, fmt.Sprintf(
+		`SELECT key FROM %s WHERE channel = $1 AND expires_at > NOW()`,
+		c.names.sharedPollKeys), channel)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+func (c *PostgresController) sweepSharedPoll(ctx context.Context) error {
+	for _, table := range []string{c.names.sharedPollLease, c.names.sharedPollKeys, c.names.sharedPollItems} {
+		if _, err := c.pool.Exec(ctx, fmt.Sprintf(
+			`DELETE FROM %[1]s WHERE ctid IN (SELECT ctid FROM %[1]s WHERE expires_at < NOW() LIMIT $1)`,
+			table), sharedPollSweepLimit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveItems ...
+func (c *PostgresController) SaveItems(ctx context.Context, channel string, items []sharedpoll.Item, ttl time.Duration) error {
+	if len(items) == 0 {
+		return nil
+	}
+	query := fmt.Sprintf(`
+		INSERT INTO %s (channel, key, version, epoch, data, removed, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW() + $7 * INTERVAL '1 millisecond')
+		ON CONFLICT (channel, key) DO UPDATE SET
+			version = EXCLUDED.version, epoch = EXCLUDED.epoch, data = EXCLUDED.data,
+			removed = EXCLUDED.removed, expires_at = EXCLUDED.expires_at
+	`, c.names.sharedPollItems)
+	batch := &pgx.Batch{}
+	for _, item := range items {
+		batch.Queue(query, channel, item.Key, int64(item.Version), item.Epoch, item.Data, item.Removed, ttl.Milliseconds())
+	}
+	return c.pool.SendBatch(ctx, batch).Close()
+}
+
+// LoadItems ...
+func (c *PostgresController) LoadItems(ctx context.Context, channel string, keys []string) (map[string]sharedpoll.Item, error) {
+	items := make(map[string]sharedpoll.Item, len(keys))
+	if len(keys) == 0 {
+		return items, nil
+	}
+	rows, err := c.pool.Query(ctx, fmt.Sprintf(`
+		SELECT key, version, epoch, data, removed FROM %s
+		WHERE channel = $1 AND key = ANY($2::text[]) AND expires_at > NOW()
+	`, c.names.sharedPollItems), channel, keys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var item sharedpoll.Item
+		var version int64
+		if err := rows.Scan(&item.Key, &version, &item.Epoch, &item.Data, &item.Removed); err != nil {
+			return nil, err
+		}
+		item.Version = uint64(version)
+		items[item.Key] = item
+	}
+	return items, rows.Err()
+}
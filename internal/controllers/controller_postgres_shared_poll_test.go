@@ -0,0 +1,87 @@
+//go:build integration
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/sharedpoll"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresController_SharedPollLease(t *testing.T) {
+	c := newTestPostgresController(t, PostgresControllerConfig{}, nil)
+	ctx := context.Background()
+
+	ok, err := c.AcquireLease(ctx, "ch", "n1", time.Second)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// Lease is held by n1 until expiration.
+	ok, err = c.AcquireLease(ctx, "ch", "n2", time.Second)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// Holder prolongs the lease.
+	ok, err = c.AcquireLease(ctx, "ch", "n1", 100*time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	time.Sleep(200 * time.Millisecond)
+	ok, err = c.AcquireLease(ctx, "ch", "n2", time.Second)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestPostgresController_SharedPollKeysAndItems(t *testing.T) {
+	c := newTestPostgresController(t, PostgresControllerConfig{}, nil)
+	ctx := context.Background()
+
+	require.NoError(t, c.TrackKeys(ctx, "ch", []string{"a", "b"}, time.Second))
+	require.NoError(t, c.TrackKeys(ctx, "ch", []string{"c"}, 100*time.Millisecond))
+	require.NoError(t, c.SaveItems(ctx, "ch", []sharedpoll.Item{
+		{Key: "a", Version: 2, Epoch: "e", Data: []byte(`{"v":2}`)},
+		{Key: "b", Removed: true},
+	}, time.Second))
+
+	items, err := c.LoadItems(ctx, "ch", []string{"a", "b", "x"})
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.Equal(t, uint64(2), items["a"].Version)
+	require.Equal(t, "e", items["a"].Epoch)
+	require.JSONEq(t, `{"v":2}`, string(items["a"].Data))
+	require.True(t, items["b"].Removed)
+
+	time.Sleep(200 * time.Millisecond)
+	keys, err := c.TrackedKeys(ctx, "ch")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"a", "b"}, keys)
+}
+
+func TestPostgresController_SchemaMigrationV2(t *testing.T) {
+	c := newTestPostgresController(t, PostgresControllerConfig{}, nil)
+	ctx := context.Background()
+
+	// Roll back to the v1 shape.
+	for _, tbl := range []string{c.names.sharedPollLease, c.names.sharedPollKeys, c.names.sharedPollItems} {
+		_, err := c.pool.Exec(ctx, fmt.Sprintf("DROP TABLE %s", tbl))
+		require.NoError(t, err)
+	}
+	_, err := c.pool.Exec(ctx, fmt.Sprintf(`UPDATE %s SET schema_version = 1 WHERE id = 1`, c.names.schemaVersion))
+	require.NoError(t, err)
+
+	require.NoError(t, c.EnsureSchema(ctx))
+
+	var version int
+	require.NoError(t, c.pool.QueryRow(ctx, fmt.Sprintf(
+		`SELECT schema_version FROM %s WHERE id = 1`, c.names.schemaVersion)).Scan(&version))
+	require.Equal(t, controllerSchemaVersion, version)
+
+	ok, err := c.AcquireLease(ctx, "ch", "n1", time.Second)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
@@ -108,7 +108,10 @@ func newTestPostgresController(tb testing.TB, conf PostgresControllerConfig, han
 func dropTestControllerSchema(tb testing.TB, c *PostgresController) {
 	tb.Helper()
 	ctx := context.Background()
-	for _, tbl := range []string{c.names.messages, c.names.shardLock, c.names.schemaVersion} {
+	for _, tbl := range []string{
+		c.names.messages, c.names.shardLock, c.names.schemaVersion,
+		c.names.sharedPollLease, c.names.sharedPollKeys, c.names.sharedPollItems,
+	} {
 		_, _ = c.pool.Exec(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %s CASCADE", tbl))
 	}
 	_, _ = c.pool.Exec(ctx, fmt.Sprintf("DROP FUNCTION IF EXISTS %s", c.names.publishFunc))
@@ -0,0 +1,165 @@
+// Package drain implements gradual disconnect of node connections so that clients
+// reconnect to other nodes without a stampede.
+package drain
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/rs/zerolog/log"
+)
+
+type Config = configtypes.Drain
+
+// DisconnectCode is used for connections closed or rejected while draining. It's
+// in the reconnect range, so clients reconnect – expectedly to other nodes.
+const DisconnectCode uint32 = 3050
+
+const defaultRate = 100
+
+// Close frame payload in WebSocket is limited by 125 bytes, code takes 2 of them.
+const maxReasonLength = 123
+
+// batchInterval is how often a batch of connections is disconnected.
+const batchInterval = 100 * time.Millisecond
+
+// Drainer stops accepting connections and disconnects existing ones gradually.
+type Drainer struct {
+	node   *centrifuge.Node
+	config Config
+
+	draining atomic.Bool
+	once     sync.Once
+	done     chan struct{}
+
+	mu       sync.RWMutex
+	redirect string
+}
+
+// New creates Drainer.
+func New(node *centrifuge.Node, cfg Config) *Drainer {
+	return &Drainer{
+		node:   node,
+		config: cfg,
+		done:   make(chan struct{}),
+	}
+}
+
+// Draining reports whether node is draining. New connections must be rejected
+// while draining.
+func (d *Drainer) Draining() bool {
+	return d.draining.Load()
+}
+
+// Done returns a channel closed when draining completes, i.e. node has no
+// connections left.
+func (d *Drainer) Done() <-chan struct{} {
+	return d.done
+}
+
+// Start begins draining in background. Returns false if draining was already
+// started. Draining stops early when ctx is canceled.
+func (d *Drainer) Start(ctx context.Context) bool {
+	started := false
+	d.once.Do(func() {
+		started = true
+		d.draining.Store(true)
+		log.Info().Int("num_clients", d.node.Hub().NumClients()).Msg("node draining started")
+		go d.run(ctx)
+	})
+	return started
+}
+
+// Disconnect returns a Disconnect for a connection closed or rejected while draining.
+// Its reason is a JSON object with randomized reconnect delay in milliseconds,
+// so that clients don't reconnect at the same moment, and an optional hint with
+// another node name.
+func (d *Drainer) Disconnect() centrifuge.Disconnect {
+	hint := struct {
+		RetryAfter int64  `json:"retry_after"`
+		Redirect   string `json:"redirect,omitempty"`
+	}{}
+	if maxRetryAfter := d.config.MaxRetryAfter.ToDuration(); maxRetryAfter > 0 {
+		hint.RetryAfter = rand.Int64N(maxRetryAfter.Milliseconds() + 1)
+	}
+	d.mu.RLock()
+	hint.Redirect = d.redirect
+	d.mu.RUnlock()
+	reason, _ := json.Marshal(hint)
+	if len(reason) > maxReasonLength {
+		hint.Redirect = ""
+		reason, _ = json.Marshal(hint)
+	}
+	return centrifuge.Disconnect{Code: DisconnectCode, Reason: string(reason)}
+}
+
+func (d *Drainer) run(ctx context.Context) {
+	defer close(d.done)
+	rate := d.config.Rate
+	if rate <= 0 {
+		rate = defaultRate
+	}
+	batchSize := max(1, rate*int(batchInterval)/int(time.Second))
+	ticker := time.NewTicker(batchInterval)
+	defer ticker.Stop()
+
+	for {
+		connections := d.node.Hub().Connections()
+		if len(connections) == 0 {
+			log.Info().Msg("node draining completed")
+			return
+		}
+		if d.config.RedirectHints {
+			d.updateRedirect()
+		}
+		i := 0
+		for _, c := range connections {
+			if i > 0 && i%batchSize == 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+			c.Disconnect(d.Disconnect())
+			metrics.IncDrainDisconnect()
+			i++
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// updateRedirect selects the least loaded node other than the current one.
+func (d *Drainer) updateRedirect() {
+	info, err := d.node.Info()
+	if err != nil {
+		log.Error().Err(err).Msg("error getting node info for drain redirect hint")
+		return
+	}
+	var redirect string
+	var minClients uint32
+	for _, n := range info.Nodes {
+		if n.UID == d.node.ID() || n.Name == "" {
+			continue
+		}
+		if redirect == "" || n.NumClients < minClients {
+			redirect = n.Name
+			minClients = n.NumClients
+		}
+	}
+	d.mu.Lock()
+	d.redirect = redirect
+	d.mu.Unlock()
+}
@@ -0,0 +1,72 @@
+package drain
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	_ = metrics.Init(metrics.Config{
+		Registerer: prometheus.NewRegistry(),
+	})
+	os.Exit(m.Run())
+}
+
+func newTestNode(t *testing.T) *centrifuge.Node {
+	t.Helper()
+	node, err := centrifuge.New(centrifuge.Config{})
+	require.NoError(t, err)
+	require.NoError(t, node.Run())
+	t.Cleanup(func() { _ = node.Shutdown(context.Background()) })
+	return node
+}
+
+func TestDrainer_Disconnect(t *testing.T) {
+	d := New(newTestNode(t), Config{MaxRetryAfter: configtypes.Duration(time.Second)})
+	d.redirect = "node-2.example.com:8000"
+
+	for i := 0; i < 10; i++ {
+		disconnect := d.Disconnect()
+		require.Equal(t, DisconnectCode, disconnect.Code)
+		var hint struct {
+			RetryAfter int64  `json:"retry_after"`
+			Redirect   string `json:"redirect"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(disconnect.Reason), &hint))
+		require.GreaterOrEqual(t, hint.RetryAfter, int64(0))
+		require.LessOrEqual(t, hint.RetryAfter, int64(1000))
+		require.Equal(t, "node-2.example.com:8000", hint.Redirect)
+	}
+}
+
+func TestDrainer_DisconnectLongRedirect(t *testing.T) {
+	d := New(newTestNode(t), Config{})
+	d.redirect = string(make([]byte, 200))
+	disconnect := d.Disconnect()
+	require.LessOrEqual(t, len(disconnect.Reason), maxReasonLength)
+	require.JSONEq(t, `{"retry_after":0}`, disconnect.Reason)
+}
+
+func TestDrainer_Start(t *testing.T) {
+	d := New(newTestNode(t), Config{Rate: 10})
+	require.False(t, d.Draining())
+	require.True(t, d.Start(context.Background()))
+	require.True(t, d.Draining())
+	require.False(t, d.Start(context.Background()))
+
+	select {
+	case <-d.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for drain completion")
+	}
+}
@@ -0,0 +1,184 @@
+package envelope
+
+import (
+	"context"
+	"errors"
+
+	"github.com/centrifugal/centrifugo/v6/internal/config"
+	"github.com/centrifugal/centrifugo/v6/internal/historyquery"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/rs/zerolog/log"
+)
+
+// Broker wraps centrifuge.Broker to encrypt publication data in namespaces with
+// encryption enabled before it reaches underlying broker, so that broker and
+// history storage only see envelopes. Publications are decrypted when delivered
+// to node and when loaded from history, unless namespace uses passthrough.
+type Broker struct {
+	centrifuge.Broker
+	*codec
+}
+
+// NewBroker creates Broker.
+func NewBroker(broker centrifuge.Broker, keyring *Keyring, cfgContainer *config.Container) *Broker {
+	return &Broker{
+		Broker: broker,
+		codec:  &codec{keyring: keyring, cfgContainer: cfgContainer},
+	}
+}
+
+// codec encrypts and decrypts publication data according to namespace options,
+// shared by Broker and MapBroker.
+type codec struct {
+	keyring      *Keyring
+	cfgContainer *config.Container
+}
+
+func (c *codec) encryptionOptions(ch string) (enabled bool, passthrough bool) {
+	_, _, chOpts, found, err := c.cfgContainer.ChannelOptions(ch)
+	if err != nil || !found {
+		return false, false
+	}
+	return chOpts.Encryption.Enabled, chOpts.Encryption.Passthrough
+}
+
+func (c *codec) shouldDecrypt(ch string) bool {
+	enabled, passthrough := c.encryptionOptions(ch)
+	// Data is decrypted even if encryption was disabled later, so that history
+	// published with encryption on is still readable.
+	return !enabled || !passthrough
+}
+
+// encrypt returns data encrypted if encryption is enabled for channel.
+func (c *codec) encrypt(ch string, data []byte) ([]byte, error) {
+	if enabled, _ := c.encryptionOptions(ch); !enabled {
+		return data, nil
+	}
+	encrypted, err := c.keyring.Encrypt(ch, data)
+	if err != nil {
+		log.Error().Err(err).Str("channel", ch).Msg("error encrypting publication data")
+		return nil, centrifuge.ErrorInternal
+	}
+	return encrypted, nil
+}
+
+// Publish ...
+func (b *Broker) Publish(ch string, data []byte, opts centrifuge.PublishOptions) (centrifuge.PublishResult, error) {
+	data, err := b.encrypt(ch, data)
+	if err != nil {
+		return centrifuge.PublishResult{}, err
+	}
+	return b.Broker.Publish(ch, data, opts)
+}
+
+// History ...
+func (b *Broker) History(ch string, opts centrifuge.HistoryOptions) ([]*centrifuge.Publication, centrifuge.StreamPosition, error) {
+	pubs, sp, err := b.Broker.History(ch, opts)
+	return b.decryptHistory(ch, pubs, sp, err)
+}
+
+var _ historyquery.RangeReader = (*Broker)(nil)
+
+// HistoryRange decrypts publications read with time range. Underlying broker
+// must implement historyquery.RangeReader.
+func (b *Broker) HistoryRange(ch string, opts centrifuge.HistoryOptions, tr historyquery.TimeRange) ([]*centrifuge.Publication, centrifuge.StreamPosition, error) {
+	rangeReader, ok := b.Broker.(historyquery.RangeReader)
+	if !ok {
+		return nil, centrifuge.StreamPosition{}, errors.ErrUnsupported
+	}
+	pubs, sp, err := rangeReader.HistoryRange(ch, opts, tr)
+	return b.decryptHistory(ch, pubs, sp, err)
+}
+
+func (b *Broker) decryptHistory(ch string, pubs []*centrifuge.Publication, sp centrifuge.StreamPosition, err error) ([]*centrifuge.Publication, centrifuge.StreamPosition, error) {
+	if err != nil {
+		return pubs, sp, err
+	}
+	pubs, err = b.decryptPublications(ch, pubs)
+	return pubs, sp, err
+}
+
+// decryptPublications returns copies of publications with decrypted data.
+func (c *codec) decryptPublications(ch string, pubs []*centrifuge.Publication) ([]*centrifuge.Publication, error) {
+	if len(pubs) == 0 || !c.shouldDecrypt(ch) {
+		return pubs, nil
+	}
+	decrypted := make([]*centrifuge.Publication, len(pubs))
+	for i, pub := range pubs {
+		var err error
+		decrypted[i], err = c.decryptPublication(ch, pub)
+		if err != nil {
+			log.Error().Err(err).Str("channel", ch).Uint64("offset", pub.Offset).Str("key", pub.Key).Msg("error decrypting publication")
+			return nil, centrifuge.ErrorInternal
+		}
+	}
+	return decrypted, nil
+}
+
+// decryptPublication returns a copy of publication with decrypted data since
+// underlying broker may keep and share publication objects. In namespaces without
+// encryption only envelopes left from the time encryption was on are decrypted,
+// in namespaces with encryption data without envelope is rejected by Keyring
+// unless plaintext is allowed during migration.
+func (c *codec) decryptPublication(ch string, pub *centrifuge.Publication) (*centrifuge.Publication, error) {
+	if pub == nil {
+		return nil, nil
+	}
+	if enabled, _ := c.encryptionOptions(ch); !enabled && !IsEnvelope(pub.Data) {
+		return pub, nil
+	}
+	data, err := c.keyring.Decrypt(ch, pub.Data)
+	if err != nil {
+		return nil, err
+	}
+	decrypted := *pub
+	decrypted.Data = data
+	return &decrypted, nil
+}
+
+// RegisterBrokerEventHandler ...
+func (b *Broker) RegisterBrokerEventHandler(h centrifuge.BrokerEventHandler) error {
+	return b.Broker.RegisterBrokerEventHandler(&eventHandler{BrokerEventHandler: h, codec: b.codec})
+}
+
+// ReliableDelivery reports whether underlying broker guarantees no-gaps delivery.
+func (b *Broker) ReliableDelivery() bool {
+	if r, ok := b.Broker.(interface{ ReliableDelivery() bool }); ok {
+		return r.ReliableDelivery()
+	}
+	return false
+}
+
+// Close ...
+func (b *Broker) Close(ctx context.Context) error {
+	if closer, ok := b.Broker.(centrifuge.Closer); ok {
+		return closer.Close(ctx)
+	}
+	return nil
+}
+
+type eventHandler struct {
+	centrifuge.BrokerEventHandler
+	codec *codec
+}
+
+// HandlePublication decrypts publication (and previous publication used for
+// delta calculation) before passing it to node.
+func (h *eventHandler) HandlePublication(ch string, pub *centrifuge.Publication, sp centrifuge.StreamPosition, delta bool, prevPub *centrifuge.Publication) error {
+	if h.codec.shouldDecrypt(ch) {
+		var err error
+		pub, err = h.codec.decryptPublication(ch, pub)
+		if err != nil {
+			log.Error().Err(err).Str("channel", ch).Msg("error decrypting publication")
+			return err
+		}
+		prevPub, err = h.codec.decryptPublication(ch, prevPub)
+		if err != nil {
+			// Delta can not be calculated, deliver full publication.
+			prevPub = nil
+			delta = false
+		}
+	}
+	return h.BrokerEventHandler.HandlePublication(ch, pub, sp, delta, prevPub)
+}
@@ -0,0 +1,125 @@
+package envelope
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/config"
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/stretchr/testify/require"
+)
+
+type testBrokerEventHandler struct {
+	centrifuge.BrokerEventHandler
+	mu   sync.Mutex
+	pubs []*centrifuge.Publication
+}
+
+func (h *testBrokerEventHandler) HandlePublication(_ string, pub *centrifuge.Publication, _ centrifuge.StreamPosition, _ bool, _ *centrifuge.Publication) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pubs = append(h.pubs, pub)
+	return nil
+}
+
+func (h *testBrokerEventHandler) lastData() []byte {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.pubs) == 0 {
+		return nil
+	}
+	return h.pubs[len(h.pubs)-1].Data
+}
+
+func newTestBroker(t *testing.T) (*Broker, centrifuge.Broker, *testBrokerEventHandler) {
+	t.Helper()
+	node, err := centrifuge.New(centrifuge.Config{})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = node.Shutdown(context.Background()) })
+
+	cfg := config.DefaultConfig()
+	cfg.Channel.Encryption.Keys = configtypes.EncryptionKeys{{ID: "k1", Key: testKey(1, 32)}}
+	cfg.Channel.Namespaces = []configtypes.ChannelNamespace{
+		{Name: "secret", ChannelOptions: configtypes.ChannelOptions{Encryption: configtypes.EncryptionConfig{Enabled: true}}},
+		{Name: "e2e", ChannelOptions: configtypes.ChannelOptions{Encryption: configtypes.EncryptionConfig{Enabled: true, Passthrough: true}}},
+		{Name: "public", ChannelOptions: configtypes.ChannelOptions{}},
+	}
+	cfgContainer, err := config.NewContainer(cfg)
+	require.NoError(t, err)
+
+	keyring, err := NewKeyring(cfg.Channel.Encryption)
+	require.NoError(t, err)
+	memoryBroker, err := centrifuge.NewMemoryBroker(node, centrifuge.MemoryBrokerConfig{})
+	require.NoError(t, err)
+	b := NewBroker(memoryBroker, keyring, cfgContainer)
+	handler := &testBrokerEventHandler{}
+	require.NoError(t, b.RegisterBrokerEventHandler(handler))
+	return b, memoryBroker, handler
+}
+
+var testPublishOptions = centrifuge.PublishOptions{HistorySize: 10, HistoryTTL: time.Minute}
+
+var testHistoryOptions = centrifuge.HistoryOptions{Filter: centrifuge.HistoryFilter{Limit: -1}}
+
+func TestBroker_Encrypted(t *testing.T) {
+	b, memoryBroker, handler := newTestBroker(t)
+	data := []byte(`{"secret":"value"}`)
+
+	_, err := b.Publish("secret:1", data, testPublishOptions)
+	require.NoError(t, err)
+	require.Equal(t, data, handler.lastData())
+
+	// Underlying broker keeps envelopes only.
+	pubs, _, err := memoryBroker.History("secret:1", testHistoryOptions)
+	require.NoError(t, err)
+	require.Len(t, pubs, 1)
+	require.NotContains(t, string(pubs[0].Data), "value")
+
+	pubs, _, err = b.History("secret:1", testHistoryOptions)
+	require.NoError(t, err)
+	require.Len(t, pubs, 1)
+	require.Equal(t, data, pubs[0].Data)
+}
+
+func TestBroker_Passthrough(t *testing.T) {
+	b, _, handler := newTestBroker(t)
+	data := []byte(`{"secret":"value"}`)
+
+	_, err := b.Publish("e2e:1", data, testPublishOptions)
+	require.NoError(t, err)
+	require.Contains(t, string(handler.lastData()), `"kid":"k1"`)
+
+	pubs, _, err := b.History("e2e:1", testHistoryOptions)
+	require.NoError(t, err)
+	require.Len(t, pubs, 1)
+	decrypted, err := b.keyring.Decrypt("e2e:1", pubs[0].Data)
+	require.NoError(t, err)
+	require.Equal(t, data, decrypted)
+}
+
+func TestBroker_NotEncrypted(t *testing.T) {
+	b, memoryBroker, handler := newTestBroker(t)
+	data := []byte(`{"public":"value"}`)
+
+	_, err := b.Publish("public:1", data, testPublishOptions)
+	require.NoError(t, err)
+	require.Equal(t, data, handler.lastData())
+
+	pubs, _, err := memoryBroker.History("public:1", testHistoryOptions)
+	require.NoError(t, err)
+	require.Len(t, pubs, 1)
+	require.Equal(t, data, pubs[0].Data)
+}
+
+func TestBroker_PlaintextRejected(t *testing.T) {
+	b, memoryBroker, _ := newTestBroker(t)
+	_, err := memoryBroker.Publish("secret:1", []byte(`{"secret":"value"}`), testPublishOptions)
+	require.NoError(t, err)
+
+	_, _, err = b.History("secret:1", testHistoryOptions)
+	require.ErrorIs(t, err, centrifuge.ErrorInternal)
+}
@@ -0,0 +1,188 @@
+// Package envelope implements AES-GCM envelope encryption of publication data.
+package envelope
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+)
+
+type Config = configtypes.ChannelEncryption
+
+var (
+	errNoActiveKey = errors.New("no active encryption key")
+	errUnknownKey  = errors.New("unknown encryption key")
+	// ErrNotEnvelope returned from Decrypt for data which is not an envelope.
+	ErrNotEnvelope = errors.New("data is not an encryption envelope")
+)
+
+// Envelope is what stored in publication data instead of plaintext. Ciphertext and
+// nonce are base64 encoded in JSON, channel name is used as additional data so that
+// envelope can not be replayed into another channel.
+type Envelope struct {
+	Alg        string `json:"alg"`
+	KeyID      string `json:"kid"`
+	Nonce      []byte `json:"iv"`
+	Ciphertext []byte `json:"ct"`
+}
+
+// Envelope JSON always starts with alg field, this allows to detect data which
+// was published before encryption was enabled without unmarshalling it.
+var envelopePrefix = []byte(`{"alg":"A`)
+
+// IsEnvelope reports whether data looks like an envelope.
+func IsEnvelope(data []byte) bool {
+	return bytes.HasPrefix(data, envelopePrefix)
+}
+
+type key struct {
+	id         string
+	alg        string
+	aead       cipher.AEAD
+	activeFrom int64
+}
+
+// Keyring keeps keys by ID. New publications are encrypted with the key with the
+// latest ActiveFrom in the past, any known key may be used for decryption.
+type Keyring struct {
+	keys     map[string]*key
+	schedule []*key
+	now      func() time.Time
+	// allowPlaintext makes Decrypt return data which is not an envelope as is.
+	allowPlaintext bool
+}
+
+type keyringFile struct {
+	Keys []configtypes.EncryptionKey `json:"keys"`
+}
+
+// NewKeyring creates Keyring from inline keys and keys from keyring file.
+func NewKeyring(cfg Config) (*Keyring, error) {
+	keys := append([]configtypes.EncryptionKey{}, cfg.Keys...)
+	if cfg.KeyringFile != "" {
+		data, err := os.ReadFile(cfg.KeyringFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading keyring file: %w", err)
+		}
+		var f keyringFile
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("error decoding keyring file: %w", err)
+		}
+		keys = append(keys, f.Keys...)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no encryption keys configured")
+	}
+	k := &Keyring{
+		keys:           make(map[string]*key, len(keys)),
+		now:            time.Now,
+		allowPlaintext: cfg.AllowPlaintext,
+	}
+	for _, cfgKey := range keys {
+		if _, ok := k.keys[cfgKey.ID]; ok {
+			return nil, fmt.Errorf("duplicate encryption key id: %s", cfgKey.ID)
+		}
+		parsed, err := parseKey(cfgKey)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %s: %w", cfgKey.ID, err)
+		}
+		k.keys[cfgKey.ID] = parsed
+		k.schedule = append(k.schedule, parsed)
+	}
+	sort.SliceStable(k.schedule, func(i, j int) bool {
+		return k.schedule[i].activeFrom > k.schedule[j].activeFrom
+	})
+	return k, nil
+}
+
+func parseKey(cfgKey configtypes.EncryptionKey) (*key, error) {
+	if cfgKey.ID == "" {
+		return nil, errors.New("id is required")
+	}
+	secret, err := base64.StdEncoding.DecodeString(cfgKey.Key)
+	if err != nil {
+		return nil, fmt.Errorf("key must be base64 encoded: %w", err)
+	}
+	block, err := aes.NewCipher(secret)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &key{
+		id:         cfgKey.ID,
+		alg:        fmt.Sprintf("A%dGCM", len(secret)*8),
+		aead:       aead,
+		activeFrom: cfgKey.ActiveFrom,
+	}, nil
+}
+
+func (k *Keyring) activeKey() (*key, error) {
+	now := k.now().Unix()
+	for _, candidate := range k.schedule {
+		if candidate.activeFrom <= now {
+			return candidate, nil
+		}
+	}
+	return nil, errNoActiveKey
+}
+
+// Encrypt returns envelope with data encrypted by active key.
+func (k *Keyring) Encrypt(channel string, data []byte) ([]byte, error) {
+	active, err := k.activeKey()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, active.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return json.Marshal(Envelope{
+		Alg:        active.alg,
+		KeyID:      active.id,
+		Nonce:      nonce,
+		Ciphertext: active.aead.Seal(nil, nonce, data, []byte(channel)),
+	})
+}
+
+// Decrypt returns plaintext of envelope. Data which is not an envelope is rejected
+// with ErrNotEnvelope, unless keyring allows plaintext – then it is returned as is.
+func (k *Keyring) Decrypt(channel string, data []byte) ([]byte, error) {
+	if !IsEnvelope(data) {
+		return k.plaintext(data)
+	}
+	var e Envelope
+	if err := json.Unmarshal(data, &e); err != nil {
+		return k.plaintext(data)
+	}
+	decryptKey, ok := k.keys[e.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnknownKey, e.KeyID)
+	}
+	if e.Alg != decryptKey.alg {
+		return nil, fmt.Errorf("envelope alg %s does not match key %s", e.Alg, e.KeyID)
+	}
+	if len(e.Nonce) != decryptKey.aead.NonceSize() {
+		return nil, errors.New("invalid envelope nonce size")
+	}
+	return decryptKey.aead.Open(nil, e.Nonce, e.Ciphertext, []byte(channel))
+}
+
+func (k *Keyring) plaintext(data []byte) ([]byte, error) {
+	if !k.allowPlaintext {
+		return nil, ErrNotEnvelope
+	}
+	return data, nil
+}
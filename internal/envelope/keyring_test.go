@@ -0,0 +1,140 @@
+package envelope
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte, size int) string {
+	secret := make([]byte, size)
+	for i := range secret {
+		secret[i] = b
+	}
+	return base64.StdEncoding.EncodeToString(secret)
+}
+
+func TestKeyring_EncryptDecrypt(t *testing.T) {
+	k, err := NewKeyring(Config{Keys: configtypes.EncryptionKeys{{ID: "k1", Key: testKey(1, 32)}}})
+	require.NoError(t, err)
+
+	data := []byte(`{"secret":"value"}`)
+	encrypted, err := k.Encrypt("secret:1", data)
+	require.NoError(t, err)
+	require.NotContains(t, string(encrypted), "value")
+
+	var e Envelope
+	require.NoError(t, json.Unmarshal(encrypted, &e))
+	require.Equal(t, "A256GCM", e.Alg)
+	require.Equal(t, "k1", e.KeyID)
+
+	decrypted, err := k.Decrypt("secret:1", encrypted)
+	require.NoError(t, err)
+	require.Equal(t, data, decrypted)
+
+	// Envelope is bound to channel.
+	_, err = k.Decrypt("secret:2", encrypted)
+	require.Error(t, err)
+
+	// Data which is not an envelope is rejected.
+	_, err = k.Decrypt("secret:1", data)
+	require.ErrorIs(t, err, ErrNotEnvelope)
+}
+
+func TestKeyring_AllowPlaintext(t *testing.T) {
+	k, err := NewKeyring(Config{Keys: configtypes.EncryptionKeys{{ID: "k1", Key: testKey(1, 32)}}, AllowPlaintext: true})
+	require.NoError(t, err)
+
+	data := []byte(`{"secret":"value"}`)
+	decrypted, err := k.Decrypt("secret:1", data)
+	require.NoError(t, err)
+	require.Equal(t, data, decrypted)
+
+	// Envelopes are still decrypted.
+	encrypted, err := k.Encrypt("secret:1", data)
+	require.NoError(t, err)
+	decrypted, err = k.Decrypt("secret:1", encrypted)
+	require.NoError(t, err)
+	require.Equal(t, data, decrypted)
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	now := time.Now()
+	k, err := NewKeyring(Config{Keys: configtypes.EncryptionKeys{
+		{ID: "k1", Key: testKey(1, 16)},
+		{ID: "k2", Key: testKey(2, 32), ActiveFrom: now.Add(time.Hour).Unix()},
+	}})
+	require.NoError(t, err)
+
+	oldEnvelope, err := k.Encrypt("ch", []byte("1"))
+	require.NoError(t, err)
+	var e Envelope
+	require.NoError(t, json.Unmarshal(oldEnvelope, &e))
+	require.Equal(t, "k1", e.KeyID)
+	require.Equal(t, "A128GCM", e.Alg)
+
+	k.now = func() time.Time { return now.Add(2 * time.Hour) }
+	newEnvelope, err := k.Encrypt("ch", []byte("2"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(newEnvelope, &e))
+	require.Equal(t, "k2", e.KeyID)
+
+	// Publications encrypted with previous key are still readable.
+	decrypted, err := k.Decrypt("ch", oldEnvelope)
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), decrypted)
+}
+
+func TestKeyring_NoActiveKey(t *testing.T) {
+	k, err := NewKeyring(Config{Keys: configtypes.EncryptionKeys{
+		{ID: "k1", Key: testKey(1, 32), ActiveFrom: time.Now().Add(time.Hour).Unix()},
+	}})
+	require.NoError(t, err)
+	_, err = k.Encrypt("ch", []byte("1"))
+	require.ErrorIs(t, err, errNoActiveKey)
+}
+
+func TestKeyring_UnknownKey(t *testing.T) {
+	k1, err := NewKeyring(Config{Keys: configtypes.EncryptionKeys{{ID: "k1", Key: testKey(1, 32)}}})
+	require.NoError(t, err)
+	k2, err := NewKeyring(Config{Keys: configtypes.EncryptionKeys{{ID: "k2", Key: testKey(2, 32)}}})
+	require.NoError(t, err)
+	encrypted, err := k1.Encrypt("ch", []byte("1"))
+	require.NoError(t, err)
+	_, err = k2.Decrypt("ch", encrypted)
+	require.ErrorIs(t, err, errUnknownKey)
+}
+
+func TestNewKeyring_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"keys": [{"id": "file", "key": "`+testKey(3, 32)+`"}]}`), 0600))
+
+	k, err := NewKeyring(Config{
+		Keys:        configtypes.EncryptionKeys{{ID: "inline", Key: testKey(1, 32), ActiveFrom: 1}},
+		KeyringFile: path,
+	})
+	require.NoError(t, err)
+	require.Len(t, k.keys, 2)
+
+	_, err = NewKeyring(Config{
+		Keys:        configtypes.EncryptionKeys{{ID: "file", Key: testKey(1, 32)}},
+		KeyringFile: path,
+	})
+	require.ErrorContains(t, err, "duplicate encryption key id")
+}
+
+func TestNewKeyring_InvalidKey(t *testing.T) {
+	_, err := NewKeyring(Config{})
+	require.Error(t, err)
+	_, err = NewKeyring(Config{Keys: configtypes.EncryptionKeys{{ID: "k1", Key: "not base64"}}})
+	require.Error(t, err)
+	_, err = NewKeyring(Config{Keys: configtypes.EncryptionKeys{{ID: "k1", Key: testKey(1, 10)}}})
+	require.Error(t, err)
+}
@@ -0,0 +1,144 @@
+package envelope
+
+import (
+	"context"
+	"errors"
+
+	"github.com/centrifugal/centrifugo/v6/internal/config"
+	"github.com/centrifugal/centrifugo/v6/internal/mapfilter"
+
+	"github.com/centrifugal/centrifuge"
+)
+
+// MapBroker wraps centrifuge.MapBroker to encrypt map values in namespaces with
+// encryption enabled, so that map state and map stream only keep envelopes. Values
+// are decrypted when delivered to node and when state or stream is read, unless
+// namespace uses passthrough.
+type MapBroker struct {
+	centrifuge.MapBroker
+	*codec
+}
+
+type mapStateFilterReader interface {
+	ReadFilteredState(ctx context.Context, ch string, q mapfilter.Query, opts centrifuge.MapReadStateOptions) (centrifuge.MapStateResult, error)
+}
+
+type mapSnapshotImporter interface {
+	ImportSnapshot(ctx context.Context, ch string, pos centrifuge.StreamPosition, entries []*centrifuge.Publication, replace bool) error
+}
+
+// NewMapBroker creates MapBroker. When underlying broker can read filtered state
+// and import snapshots natively (Postgres map broker) the returned broker does too.
+func NewMapBroker(broker centrifuge.MapBroker, keyring *Keyring, cfgContainer *config.Container) centrifuge.MapBroker {
+	b := &MapBroker{
+		MapBroker: broker,
+		codec:     &codec{keyring: keyring, cfgContainer: cfgContainer},
+	}
+	_, isFilterReader := broker.(mapStateFilterReader)
+	_, isImporter := broker.(mapSnapshotImporter)
+	if isFilterReader && isImporter {
+		return &nativeMapBroker{MapBroker: b}
+	}
+	return b
+}
+
+// RegisterEventHandler ...
+func (b *MapBroker) RegisterEventHandler(h centrifuge.BrokerEventHandler) error {
+	return b.MapBroker.RegisterEventHandler(&eventHandler{BrokerEventHandler: h, codec: b.codec})
+}
+
+// Publish ...
+func (b *MapBroker) Publish(ctx context.Context, ch string, key string, opts centrifuge.MapPublishOptions) (centrifuge.MapUpdateResult, error) {
+	if len(opts.Data) > 0 {
+		data, err := b.encrypt(ch, opts.Data)
+		if err != nil {
+			return centrifuge.MapUpdateResult{}, err
+		}
+		opts.Data = data
+	}
+	return b.MapBroker.Publish(ctx, ch, key, opts)
+}
+
+// ReadState ...
+func (b *MapBroker) ReadState(ctx context.Context, ch string, opts centrifuge.MapReadStateOptions) (centrifuge.MapStateResult, error) {
+	res, err := b.MapBroker.ReadState(ctx, ch, opts)
+	return b.decryptState(ch, res, err)
+}
+
+// ReadStream ...
+func (b *MapBroker) ReadStream(ctx context.Context, ch string, opts centrifuge.MapReadStreamOptions) (centrifuge.MapStreamResult, error) {
+	res, err := b.MapBroker.ReadStream(ctx, ch, opts)
+	if err != nil {
+		return res, err
+	}
+	res.Publications, err = b.decryptPublications(ch, res.Publications)
+	return res, err
+}
+
+func (b *MapBroker) decryptState(ch string, res centrifuge.MapStateResult, err error) (centrifuge.MapStateResult, error) {
+	if err != nil {
+		return res, err
+	}
+	res.Publications, err = b.decryptPublications(ch, res.Publications)
+	return res, err
+}
+
+// ReliableDelivery reports whether underlying broker guarantees no-gaps delivery.
+func (b *MapBroker) ReliableDelivery() bool {
+	if r, ok := b.MapBroker.(interface{ ReliableDelivery() bool }); ok {
+		return r.ReliableDelivery()
+	}
+	return false
+}
+
+// Close ...
+func (b *MapBroker) Close(ctx context.Context) error {
+	if closer, ok := b.MapBroker.(centrifuge.Closer); ok {
+		return closer.Close(ctx)
+	}
+	return nil
+}
+
+// nativeMapBroker is MapBroker over broker which can read filtered state and
+// import snapshots natively.
+type nativeMapBroker struct {
+	*MapBroker
+}
+
+// ReadFilteredState decrypts state read with filter. Encryption can not be enabled
+// together with map indexes, so only namespaces without encryption are filtered.
+func (b *nativeMapBroker) ReadFilteredState(ctx context.Context, ch string, q mapfilter.Query, opts centrifuge.MapReadStateOptions) (centrifuge.MapStateResult, error) {
+	filterReader, ok := b.MapBroker.MapBroker.(mapStateFilterReader)
+	if !ok {
+		return centrifuge.MapStateResult{}, errors.ErrUnsupported
+	}
+	res, err := filterReader.ReadFilteredState(ctx, ch, q, opts)
+	return b.decryptState(ch, res, err)
+}
+
+// ImportSnapshot encrypts imported entries. In passthrough namespaces exported
+// entries are already envelopes and stored as is.
+func (b *nativeMapBroker) ImportSnapshot(ctx context.Context, ch string, pos centrifuge.StreamPosition, entries []*centrifuge.Publication, replace bool) error {
+	importer, ok := b.MapBroker.MapBroker.(mapSnapshotImporter)
+	if !ok {
+		return errors.ErrUnsupported
+	}
+	if enabled, passthrough := b.encryptionOptions(ch); enabled {
+		encrypted := make([]*centrifuge.Publication, len(entries))
+		for i, entry := range entries {
+			if passthrough && IsEnvelope(entry.Data) {
+				encrypted[i] = entry
+				continue
+			}
+			data, err := b.encrypt(ch, entry.Data)
+			if err != nil {
+				return err
+			}
+			pub := *entry
+			pub.Data = data
+			encrypted[i] = &pub
+		}
+		entries = encrypted
+	}
+	return importer.ImportSnapshot(ctx, ch, pos, entries, replace)
+}
@@ -0,0 +1,105 @@
+package envelope
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/config"
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMapBroker(t *testing.T, allowPlaintext bool) (centrifuge.MapBroker, centrifuge.MapBroker) {
+	t.Helper()
+	node, err := centrifuge.New(centrifuge.Config{
+		Map: centrifuge.MapConfig{
+			GetMapChannelOptions: func(channel string) centrifuge.MapChannelOptions {
+				return centrifuge.MapChannelOptions{
+					Mode:   centrifuge.MapModeEphemeral,
+					KeyTTL: time.Minute,
+				}
+			},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = node.Shutdown(context.Background()) })
+
+	cfg := config.DefaultConfig()
+	cfg.Channel.Encryption.Keys = configtypes.EncryptionKeys{{ID: "k1", Key: testKey(1, 32)}}
+	cfg.Channel.Encryption.AllowPlaintext = allowPlaintext
+	cfg.Channel.Namespaces = []configtypes.ChannelNamespace{
+		{Name: "secret", ChannelOptions: configtypes.ChannelOptions{SubscriptionType: "map", Encryption: configtypes.EncryptionConfig{Enabled: true}}},
+		{Name: "public", ChannelOptions: configtypes.ChannelOptions{SubscriptionType: "map"}},
+	}
+	cfgContainer, err := config.NewContainer(cfg)
+	require.NoError(t, err)
+
+	keyring, err := NewKeyring(cfg.Channel.Encryption)
+	require.NoError(t, err)
+	memoryBroker, err := centrifuge.NewMemoryMapBroker(node, centrifuge.MemoryMapBrokerConfig{})
+	require.NoError(t, err)
+	return NewMapBroker(memoryBroker, keyring, cfgContainer), memoryBroker
+}
+
+func TestMapBroker_Encrypted(t *testing.T) {
+	ctx := context.Background()
+	b, memoryBroker := newTestMapBroker(t, false)
+	data := []byte(`{"secret":"value"}`)
+
+	_, err := b.Publish(ctx, "secret:1", "key", centrifuge.MapPublishOptions{Data: data})
+	require.NoError(t, err)
+
+	// Underlying broker keeps envelopes only.
+	state, err := memoryBroker.ReadState(ctx, "secret:1", centrifuge.MapReadStateOptions{Limit: 100})
+	require.NoError(t, err)
+	require.Len(t, state.Publications, 1)
+	require.True(t, IsEnvelope(state.Publications[0].Data))
+
+	state, err = b.ReadState(ctx, "secret:1", centrifuge.MapReadStateOptions{Limit: 100})
+	require.NoError(t, err)
+	require.Len(t, state.Publications, 1)
+	require.Equal(t, data, state.Publications[0].Data)
+
+	stream, err := b.ReadStream(ctx, "secret:1", centrifuge.MapReadStreamOptions{Filter: centrifuge.StreamFilter{Limit: -1}})
+	require.NoError(t, err)
+	require.Len(t, stream.Publications, 1)
+	require.Equal(t, data, stream.Publications[0].Data)
+}
+
+func TestMapBroker_NotEncrypted(t *testing.T) {
+	ctx := context.Background()
+	b, memoryBroker := newTestMapBroker(t, false)
+	data := []byte(`{"public":"value"}`)
+
+	_, err := b.Publish(ctx, "public:1", "key", centrifuge.MapPublishOptions{Data: data})
+	require.NoError(t, err)
+
+	state, err := memoryBroker.ReadState(ctx, "public:1", centrifuge.MapReadStateOptions{Limit: 100})
+	require.NoError(t, err)
+	require.Len(t, state.Publications, 1)
+	require.Equal(t, data, state.Publications[0].Data)
+}
+
+func TestMapBroker_Plaintext(t *testing.T) {
+	ctx := context.Background()
+	data := []byte(`{"secret":"value"}`)
+
+	// State written before encryption was enabled is rejected.
+	b, memoryBroker := newTestMapBroker(t, false)
+	_, err := memoryBroker.Publish(ctx, "secret:1", "key", centrifuge.MapPublishOptions{Data: data})
+	require.NoError(t, err)
+	_, err = b.ReadState(ctx, "secret:1", centrifuge.MapReadStateOptions{Limit: 100})
+	require.ErrorIs(t, err, centrifuge.ErrorInternal)
+
+	// Unless plaintext is allowed during migration.
+	b, memoryBroker = newTestMapBroker(t, true)
+	_, err = memoryBroker.Publish(ctx, "secret:1", "key", centrifuge.MapPublishOptions{Data: data})
+	require.NoError(t, err)
+	state, err := b.ReadState(ctx, "secret:1", centrifuge.MapReadStateOptions{Limit: 100})
+	require.NoError(t, err)
+	require.Len(t, state.Publications, 1)
+	require.Equal(t, data, state.Publications[0].Data)
+}
@@ -32,6 +32,7 @@ func (h *ConsumingHandler) handle{{ .RequestCapitalized }}(ctx context.Context,
 		return nil, ErrInvalidData
 	}
 	resp := h.api.{{ .RequestCapitalized }}(ctx, req)
+	h.api.audit(ctx, "{{ .RequestSnake }}", req, resp.Error)
 	if h.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
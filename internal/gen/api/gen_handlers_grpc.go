@@ -25,6 +25,7 @@ var templateFuncHandlersGRPC = `
 func (s *grpcAPIService) {{ .RequestCapitalized }}(ctx context.Context, req *{{ .RequestCapitalized }}Request) (*{{ .RequestCapitalized }}Response, error) {
 	resp := s.api.{{ .RequestCapitalized }}(ctx, req)
 {{- if ne .RequestCapitalized "Batch" }}
+	s.api.audit(ctx, "{{ .RequestSnake }}", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(ctx)
 		span.SetStatus(codes.Error, resp.Error.Error())
@@ -39,6 +39,7 @@ func (s *Handler) handle{{ .RequestCapitalized }}(w http.ResponseWriter, r *http
 	resp := s.api.{{ .RequestCapitalized }}(r.Context(), req)
 
 {{- if ne .RequestCapitalized "Batch" }}
+	s.api.audit(r.Context(), "{{ .RequestSnake }}", req, resp.Error)
 	if s.config.UseOpenTelemetry && resp.Error != nil {
 		span := trace.SpanFromContext(r.Context())
 		span.SetStatus(codes.Error, resp.Error.Error())
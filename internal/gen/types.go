@@ -22,4 +22,8 @@ var Requests = []string{
 	"MapStats",
 	"MapClear",
 	"SharedPollPublish",
+	"MapExport",
+	"MapImport",
+	"ScheduledList",
+	"ScheduledCancel",
 }
@@ -7,7 +7,11 @@ import (
 )
 
 // Config of health check handler.
-type Config struct{}
+type Config struct {
+	// Draining when set and returns true makes handler report node as not ready,
+	// so that load balancers stop routing new connections to it.
+	Draining func() bool
+}
 
 // Handler handles health endpoint.
 type Handler struct {
@@ -26,5 +30,10 @@ func NewHandler(n *centrifuge.Node, c Config) *Handler {
 
 func (h *Handler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
+	if h.config.Draining != nil && h.config.Draining() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"status":"draining"}`))
+		return
+	}
 	_, _ = w.Write([]byte(`{}`))
 }
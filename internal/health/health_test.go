@@ -40,3 +40,26 @@ func nodeWithMemoryEngine() *centrifuge.Node {
 	}
 	return n
 }
+
+func TestHealthHandlerDraining(t *testing.T) {
+	node := nodeWithMemoryEngine()
+	draining := false
+	h := NewHandler(node, Config{Draining: func() bool { return draining }})
+
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	draining = true
+	res, err = http.Get(ts.URL)
+	require.NoError(t, err)
+	defer func() { _ = res.Body.Close() }()
+	require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	data, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"status":"draining"}`, string(data))
+}
@@ -0,0 +1,40 @@
+package historyarchive
+
+import (
+	"time"
+
+	"github.com/centrifugal/centrifuge"
+)
+
+// Broker wraps centrifuge.Broker to archive publications kept in history as
+// they are published. Only publications published by this node are archived
+// by it, so every publication is archived once across the cluster.
+type Broker struct {
+	centrifuge.Broker
+	archiver *Archiver
+}
+
+// NewBroker creates Broker.
+func NewBroker(broker centrifuge.Broker, archiver *Archiver) *Broker {
+	return &Broker{
+		Broker:   broker,
+		archiver: archiver,
+	}
+}
+
+// Publish ...
+func (b *Broker) Publish(ch string, data []byte, opts centrifuge.PublishOptions) (centrifuge.PublishResult, error) {
+	res, err := b.Broker.Publish(ch, data, opts)
+	if err != nil || res.Suppressed || opts.HistorySize <= 0 || opts.HistoryTTL <= 0 {
+		return res, err
+	}
+	b.archiver.Add(NewRecord(ch, &centrifuge.Publication{
+		Offset: res.Offset,
+		Epoch:  res.Epoch,
+		Time:   time.Now().UnixMilli(),
+		Data:   data,
+		Tags:   opts.Tags,
+		Info:   opts.ClientInfo,
+	}))
+	return res, nil
+}
@@ -0,0 +1,425 @@
+// Package historyarchive archives channel history to compressed NDJSON files
+// kept on local disk or in S3-compatible object storage, so that publications
+// are available after they leave history by TTL, size or partition retention.
+//
+// Files are laid out by UTC day as prefix/YYYY/MM/DD/name.ndjson.gz (or .zst),
+// each line is a Record. Only NDJSON format is supported, Parquet is not
+// implemented. Every archive file has an index at prefix.index/YYYY/MM/DD/name.json
+// with channels of its records and their time bounds, Reader uses it to skip
+// files without publications of requested channel and time range.
+//
+// Publications are archived either as they are published (Broker wrapper
+// buffers them and Archiver flushes buffer to a new file per node periodically)
+// or by whole daily partitions of PostgreSQL stream broker right before
+// retention drops them (ArchivePartition). Files which failed to be written are
+// kept in memory and retried with backoff, while the number of publications
+// kept by Archiver is bounded by max_pending_size – publications over it are
+// dropped. Partition files have stable names, so several nodes archiving the
+// same partition produce the same file. Archived data is the same as kept in
+// history – with channel encryption enabled it is encrypted.
+package historyarchive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/klauspost/compress/zstd"
+	"github.com/rs/zerolog/log"
+)
+
+// Sources of archived publications used in metrics.
+const (
+	SourcePublications       = "publications"
+	SourcePostgresPartitions = "postgres_partitions"
+)
+
+// Record is one archived publication.
+type Record struct {
+	Channel string `json:"channel"`
+	Offset  uint64 `json:"offset,omitempty"`
+	Epoch   string `json:"epoch,omitempty"`
+	// Time of publication in Unix milliseconds.
+	Time int64 `json:"time"`
+	// Data is set if publication data is JSON, B64Data otherwise.
+	Data    json.RawMessage   `json:"data,omitempty"`
+	B64Data string            `json:"b64data,omitempty"`
+	Tags    map[string]string `json:"tags,omitempty"`
+	User    string            `json:"user,omitempty"`
+	Client  string            `json:"client,omitempty"`
+}
+
+// NewRecord creates Record from channel publication.
+func NewRecord(ch string, pub *centrifuge.Publication) Record {
+	r := Record{
+		Channel: ch,
+		Offset:  pub.Offset,
+		Epoch:   pub.Epoch,
+		Time:    pub.Time,
+		Tags:    pub.Tags,
+	}
+	if len(pub.Data) > 0 && json.Valid(pub.Data) {
+		r.Data = json.RawMessage(pub.Data)
+	} else if len(pub.Data) > 0 {
+		r.B64Data = base64.StdEncoding.EncodeToString(pub.Data)
+	}
+	if pub.Info != nil {
+		r.User = pub.Info.UserID
+		r.Client = pub.Info.ClientID
+	}
+	return r
+}
+
+// Bounds of delay between attempts to write pending archive files.
+const (
+	minRetryBackoff = time.Second
+	maxRetryBackoff = time.Minute
+)
+
+// retryBackoff returns delay before next attempt to write pending archive files
+// after number of failed attempts in a row.
+func retryBackoff(retries int) time.Duration {
+	//nolint:gosec // it's a jitter.
+	jitter := time.Duration(rand.Int63n(minRetryBackoff.Milliseconds())) * time.Millisecond
+	return min((minRetryBackoff+jitter)*(1<<min(retries-1, 10)), maxRetryBackoff)
+}
+
+// fileExtension returns archive file extension for compression.
+func fileExtension(compression string) string {
+	if compression == "zstd" {
+		return ".ndjson.zst"
+	}
+	return ".ndjson.gz"
+}
+
+// dayPrefix returns prefix of archive files of UTC day.
+func dayPrefix(prefix string, day time.Time) string {
+	return prefix + "/" + day.UTC().Format("2006/01/02") + "/"
+}
+
+// indexKey returns key of index of archive file with key.
+func indexKey(prefix string, key string) string {
+	return prefix + ".index/" + strings.TrimPrefix(key, prefix+"/") + ".json"
+}
+
+// timeBounds of channel publications in archive file, Unix milliseconds.
+type timeBounds struct {
+	Min int64 `json:"min"`
+	Max int64 `json:"max"`
+}
+
+// fileIndex lists channels of archive file records.
+type fileIndex struct {
+	Channels map[string]timeBounds `json:"channels"`
+}
+
+func (idx *fileIndex) add(r Record) {
+	b, ok := idx.Channels[r.Channel]
+	if !ok {
+		idx.Channels[r.Channel] = timeBounds{Min: r.Time, Max: r.Time}
+		return
+	}
+	b.Min = min(b.Min, r.Time)
+	b.Max = max(b.Max, r.Time)
+	idx.Channels[r.Channel] = b
+}
+
+// fileWriter writes compressed NDJSON archive to a temporary file, so that
+// archives larger than memory can be uploaded.
+type fileWriter struct {
+	file    *os.File
+	buf     *bufio.Writer
+	encoder *json.Encoder
+	closer  io.Closer
+}
+
+func newFileWriter(compression string) (*fileWriter, error) {
+	f, err := os.CreateTemp("", "centrifugo-history-archive-*")
+	if err != nil {
+		return nil, err
+	}
+	w := &fileWriter{file: f, buf: bufio.NewWriter(f)}
+	var out io.Writer
+	switch compression {
+	case "zstd":
+		zw, err := zstd.NewWriter(w.buf)
+		if err != nil {
+			w.abort()
+			return nil, err
+		}
+		out, w.closer = zw, zw
+	default:
+		gw := gzip.NewWriter(w.buf)
+		out, w.closer = gw, gw
+	}
+	w.encoder = json.NewEncoder(out)
+	return w, nil
+}
+
+func (w *fileWriter) write(r Record) error {
+	return w.encoder.Encode(r)
+}
+
+// finish completes compressed stream and rewinds file for reading.
+func (w *fileWriter) finish() (*os.File, error) {
+	if err := w.closer.Close(); err != nil {
+		return nil, err
+	}
+	if err := w.buf.Flush(); err != nil {
+		return nil, err
+	}
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return w.file, nil
+}
+
+func (w *fileWriter) abort() {
+	_ = w.file.Close()
+	_ = os.Remove(w.file.Name())
+}
+
+// batch of buffered publications to be written to archive file with key.
+type batch struct {
+	key     string
+	records []Record
+}
+
+// Archiver writes publications to archive Storage.
+type Archiver struct {
+	nodeID  string
+	storage Storage
+	cfg     configtypes.HistoryArchive
+
+	mu     sync.Mutex
+	buffer []Record
+	// held is a number of buffered and pending publications.
+	held     int
+	dropping bool
+	seq      uint64
+	fullCh   chan struct{}
+
+	// pending batches in order of cut, batch is removed once written.
+	pending []batch
+}
+
+// New creates Archiver. Node ID is a part of file names written by node, so that
+// nodes never overwrite files of each other.
+func New(nodeID string, storage Storage, cfg configtypes.HistoryArchive) *Archiver {
+	return &Archiver{
+		nodeID:  nodeID,
+		storage: storage,
+		cfg:     cfg,
+		fullCh:  make(chan struct{}, 1),
+	}
+}
+
+// Add buffers publication until next flush. Publication is dropped if Archiver
+// already keeps max_pending_size publications – this happens when archive
+// storage is slow or unavailable.
+func (a *Archiver) Add(r Record) {
+	a.mu.Lock()
+	if a.held >= a.cfg.MaxPendingSize {
+		dropping := a.dropping
+		a.dropping = true
+		a.mu.Unlock()
+		if !dropping {
+			log.Warn().Int("max_pending_size", a.cfg.MaxPendingSize).Msg("history archive buffer is full, publications are dropped")
+		}
+		metrics.AddHistoryArchiveRecords(SourcePublications, "dropped", 1)
+		return
+	}
+	a.dropping = false
+	a.buffer = append(a.buffer, r)
+	a.held++
+	full := len(a.buffer) >= a.cfg.MaxBufferSize
+	a.mu.Unlock()
+	if full {
+		select {
+		case a.fullCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run flushes buffered publications every flush interval or when buffer is full
+// until context is done. If writing fails, pending files are retried with
+// backoff, buffer is still cut into new pending files meanwhile. Pending
+// publications are flushed on return.
+func (a *Archiver) Run(ctx context.Context) error {
+	ticker := time.NewTicker(a.cfg.FlushInterval.ToDuration())
+	defer ticker.Stop()
+	var (
+		retries int
+		retryCh <-chan time.Time
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			// Parent context is canceled on shutdown, so give last flush own timeout.
+			flushCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			err := a.flush(flushCtx)
+			cancel()
+			if err != nil {
+				log.Error().Err(err).Int("num_records", a.numHeld()).Msg("error flushing history archive on shutdown, pending publications are not archived")
+			}
+			return nil
+		case <-ticker.C:
+		case <-a.fullCh:
+		case <-retryCh:
+			retryCh = nil
+		}
+		a.cut()
+		if retryCh != nil {
+			continue
+		}
+		if err := a.writePending(ctx); err != nil {
+			retries++
+			retryCh = time.After(retryBackoff(retries))
+			continue
+		}
+		retries = 0
+	}
+}
+
+func (a *Archiver) numHeld() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.held
+}
+
+// flush writes buffered and pending publications.
+func (a *Archiver) flush(ctx context.Context) error {
+	a.cut()
+	return a.writePending(ctx)
+}
+
+// cut moves buffered publications to a new pending batch.
+func (a *Archiver) cut() {
+	a.mu.Lock()
+	records := a.buffer
+	a.buffer = nil
+	if len(records) == 0 {
+		a.mu.Unlock()
+		return
+	}
+	a.seq++
+	seq := a.seq
+	a.mu.Unlock()
+	first := time.UnixMilli(records[0].Time).UTC()
+	if records[0].Time == 0 {
+		first = time.Now().UTC()
+	}
+	key := dayPrefix(a.cfg.Prefix, first) + first.Format("150405") + "-" + a.nodeID + "-" + strconv.FormatUint(seq, 10) + fileExtension(a.cfg.Compression)
+	a.pending = append(a.pending, batch{key: key, records: records})
+}
+
+// writePending writes pending batches in order and stops on first error. Batch
+// keeps its key between attempts, so a file written partially is replaced.
+func (a *Archiver) writePending(ctx context.Context) error {
+	for len(a.pending) > 0 {
+		b := a.pending[0]
+		err := a.write(ctx, b.key, func(write func(Record) error) error {
+			for _, r := range b.records {
+				if err := write(r); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			log.Error().Err(err).Str("key", b.key).Int("num_records", len(b.records)).Int("num_pending_files", len(a.pending)).Msg("error writing history archive file, will retry")
+			metrics.AddHistoryArchiveRecords(SourcePublications, "error", len(b.records))
+			return err
+		}
+		metrics.AddHistoryArchiveRecords(SourcePublications, "ok", len(b.records))
+		a.pending[0] = batch{}
+		a.pending = a.pending[1:]
+		a.mu.Lock()
+		a.held -= len(b.records)
+		a.mu.Unlock()
+	}
+	return nil
+}
+
+// write writes archive file with key from records produced by read, and then
+// its index. Archive file without index is still read by Reader.
+func (a *Archiver) write(ctx context.Context, key string, read func(write func(Record) error) error) error {
+	w, err := newFileWriter(a.cfg.Compression)
+	if err != nil {
+		return err
+	}
+	defer w.abort()
+	idx := fileIndex{Channels: map[string]timeBounds{}}
+	if err := read(func(r Record) error {
+		idx.add(r)
+		return w.write(r)
+	}); err != nil {
+		return err
+	}
+	f, err := w.finish()
+	if err != nil {
+		return err
+	}
+	if err := a.storage.Put(ctx, key, f); err != nil {
+		return err
+	}
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	return a.storage.Put(ctx, indexKey(a.cfg.Prefix, key), bytes.NewReader(data))
+}
+
+// ArchivePartition writes publications of PostgreSQL stream broker daily
+// partition produced by read into a file named after partition. Partition must
+// not be dropped if ArchivePartition returns an error.
+func (a *Archiver) ArchivePartition(ctx context.Context, partition string, day time.Time, read func(write func(Record) error) error) error {
+	key := dayPrefix(a.cfg.Prefix, day) + "partition-" + partition + fileExtension(a.cfg.Compression)
+	var count int
+	err := a.write(ctx, key, func(write func(Record) error) error {
+		return read(func(r Record) error {
+			count++
+			return write(r)
+		})
+	})
+	if err != nil {
+		metrics.AddHistoryArchiveRecords(SourcePostgresPartitions, "error", count)
+		return fmt.Errorf("error archiving partition %s: %w", partition, err)
+	}
+	metrics.AddHistoryArchiveRecords(SourcePostgresPartitions, "ok", count)
+	log.Info().Str("partition", partition).Str("key", key).Int("num_records", count).Msg("history partition archived")
+	return nil
+}
+
+// decompress returns reader of decompressed archive file with key.
+func decompress(key string, r io.Reader) (io.ReadCloser, error) {
+	switch {
+	case strings.HasSuffix(key, ".zst"):
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	case strings.HasSuffix(key, ".gz"):
+		return gzip.NewReader(r)
+	default:
+		return nil, errors.New("unknown archive file extension")
+	}
+}
@@ -0,0 +1,469 @@
+package historyarchive
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	_ = metrics.Init(metrics.Config{
+		Registerer: prometheus.NewRegistry(),
+	})
+	os.Exit(m.Run())
+}
+
+func testConfig(compression string) configtypes.HistoryArchive {
+	return configtypes.HistoryArchive{
+		Enabled:        true,
+		Source:         SourcePublications,
+		Storage:        "local",
+		Prefix:         "history",
+		Compression:    compression,
+		FlushInterval:  configtypes.Duration(time.Minute),
+		MaxBufferSize:  100,
+		MaxPendingSize: 1000,
+	}
+}
+
+func readAll(t *testing.T, r *Reader, ch string, from, to time.Time) []Record {
+	t.Helper()
+	var records []Record
+	err := r.Read(context.Background(), ch, from, to, func(rec Record) error {
+		records = append(records, rec)
+		return nil
+	})
+	require.NoError(t, err)
+	return records
+}
+
+func TestNewRecord(t *testing.T) {
+	r := NewRecord("ch", &centrifuge.Publication{
+		Offset: 1,
+		Epoch:  "e",
+		Time:   1000,
+		Data:   []byte(`{"a":1}`),
+		Info:   &centrifuge.ClientInfo{UserID: "u", ClientID: "c"},
+	})
+	require.Equal(t, `{"a":1}`, string(r.Data))
+	require.Empty(t, r.B64Data)
+	require.Equal(t, "u", r.User)
+	require.Equal(t, "c", r.Client)
+
+	r = NewRecord("ch", &centrifuge.Publication{Data: []byte{0xff, 0x00}})
+	require.Empty(t, r.Data)
+	require.Equal(t, "/wA=", r.B64Data)
+}
+
+func TestArchiverFlushAndRead(t *testing.T) {
+	for _, compression := range []string{"gzip", "zstd"} {
+		t.Run(compression, func(t *testing.T) {
+			storage := NewLocalStorage(t.TempDir())
+			a := New("node", storage, testConfig(compression))
+
+			day := time.Date(2026, 3, 10, 23, 59, 0, 0, time.UTC)
+			a.Add(Record{Channel: "ch1", Offset: 1, Time: day.UnixMilli(), Data: []byte(`"1"`)})
+			a.Add(Record{Channel: "ch2", Offset: 1, Time: day.UnixMilli(), Data: []byte(`"x"`)})
+			// Publication of the next day ends up in a file of the previous day.
+			a.Add(Record{Channel: "ch1", Offset: 2, Time: day.Add(2 * time.Minute).UnixMilli(), Data: []byte(`"2"`)})
+			a.flush(context.Background())
+			a.flush(context.Background()) // Empty buffer, no file.
+
+			keys, err := storage.List(context.Background(), "history/")
+			require.NoError(t, err)
+			require.Equal(t, []string{"history/2026/03/10/235900-node-1" + fileExtension(compression)}, keys)
+
+			r := NewReader(storage, "history")
+			records := readAll(t, r, "ch1", day, day.Add(time.Hour))
+			require.Len(t, records, 2)
+			require.Equal(t, uint64(1), records[0].Offset)
+			require.Equal(t, `"2"`, string(records[1].Data))
+
+			// Range starting on the next day still finds publication in previous day file.
+			records = readAll(t, r, "ch1", day.Add(time.Minute), day.Add(time.Hour))
+			require.Len(t, records, 1)
+			require.Equal(t, uint64(2), records[0].Offset)
+
+			require.Empty(t, readAll(t, r, "ch3", day, day.Add(time.Hour)))
+		})
+	}
+}
+
+func TestArchiverMaxBufferSize(t *testing.T) {
+	cfg := testConfig("gzip")
+	cfg.MaxBufferSize = 2
+	a := New("node", NewLocalStorage(t.TempDir()), cfg)
+	a.Add(Record{Channel: "ch"})
+	select {
+	case <-a.fullCh:
+		require.Fail(t, "unexpected full buffer signal")
+	default:
+	}
+	a.Add(Record{Channel: "ch"})
+	select {
+	case <-a.fullCh:
+	default:
+		require.Fail(t, "expected full buffer signal")
+	}
+}
+
+// failingStorage fails Put while fail is set.
+type failingStorage struct {
+	Storage
+	mu   sync.Mutex
+	fail bool
+	puts int
+}
+
+func (s *failingStorage) setFail(fail bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fail = fail
+}
+
+func (s *failingStorage) Put(ctx context.Context, key string, body io.ReadSeeker) error {
+	s.mu.Lock()
+	s.puts++
+	fail := s.fail
+	s.mu.Unlock()
+	if fail {
+		return io.ErrUnexpectedEOF
+	}
+	return s.Storage.Put(ctx, key, body)
+}
+
+func TestArchiverRetriesFailedWrites(t *testing.T) {
+	storage := &failingStorage{Storage: NewLocalStorage(t.TempDir()), fail: true}
+	a := New("node", storage, testConfig("gzip"))
+
+	day := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	a.Add(Record{Channel: "ch", Offset: 1, Time: day.UnixMilli()})
+	require.ErrorIs(t, a.flush(context.Background()), io.ErrUnexpectedEOF)
+	a.Add(Record{Channel: "ch", Offset: 2, Time: day.UnixMilli()})
+	require.ErrorIs(t, a.flush(context.Background()), io.ErrUnexpectedEOF)
+	require.Len(t, a.pending, 2)
+	require.Equal(t, 2, a.numHeld())
+
+	storage.setFail(false)
+	require.NoError(t, a.flush(context.Background()))
+	require.Empty(t, a.pending)
+	require.Zero(t, a.numHeld())
+
+	// Retried batch keeps its key.
+	keys, err := storage.List(context.Background(), "history/")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"history/2026/03/10/120000-node-1.ndjson.gz",
+		"history/2026/03/10/120000-node-2.ndjson.gz",
+	}, keys)
+	records := readAll(t, NewReader(storage, "history"), "ch", day, day.Add(time.Hour))
+	require.Len(t, records, 2)
+}
+
+func TestArchiverMaxPendingSize(t *testing.T) {
+	cfg := testConfig("gzip")
+	cfg.MaxBufferSize = 2
+	cfg.MaxPendingSize = 3
+	storage := &failingStorage{Storage: NewLocalStorage(t.TempDir()), fail: true}
+	a := New("node", storage, cfg)
+	for i := 0; i < 2; i++ {
+		a.Add(Record{Channel: "ch", Offset: uint64(i + 1)})
+	}
+	require.Error(t, a.flush(context.Background()))
+	for i := 2; i < 5; i++ {
+		a.Add(Record{Channel: "ch", Offset: uint64(i + 1)})
+	}
+	require.Equal(t, 3, a.numHeld())
+	require.Len(t, a.buffer, 1, "publications over max pending size must be dropped")
+
+	storage.setFail(false)
+	require.NoError(t, a.flush(context.Background()))
+	a.Add(Record{Channel: "ch", Offset: 6})
+	require.Equal(t, 1, a.numHeld())
+}
+
+func TestArchiverRunRetriesWithBackoff(t *testing.T) {
+	storage := &failingStorage{Storage: NewLocalStorage(t.TempDir()), fail: true}
+	cfg := testConfig("gzip")
+	cfg.FlushInterval = configtypes.Duration(10 * time.Millisecond)
+	a := New("node", storage, cfg)
+	a.Add(Record{Channel: "ch", Time: time.Now().UnixMilli()})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = a.Run(ctx)
+	}()
+	time.Sleep(200 * time.Millisecond)
+	storage.mu.Lock()
+	puts := storage.puts
+	storage.mu.Unlock()
+	// Flush interval is much shorter, but writes are retried with backoff.
+	require.Equal(t, 1, puts)
+
+	storage.setFail(false)
+	cancel()
+	<-done
+	keys, err := storage.List(context.Background(), "history/")
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+}
+
+func TestReaderSkipsFilesByIndex(t *testing.T) {
+	storage := &countingStorage{Storage: NewLocalStorage(t.TempDir())}
+	a := New("node", storage, testConfig("gzip"))
+
+	day := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	a.Add(Record{Channel: "ch1", Offset: 1, Time: day.UnixMilli()})
+	require.NoError(t, a.flush(context.Background()))
+	a.Add(Record{Channel: "ch2", Offset: 1, Time: day.Add(time.Minute).UnixMilli()})
+	require.NoError(t, a.flush(context.Background()))
+
+	keys, err := storage.List(context.Background(), "history.index/2026/03/10/")
+	require.NoError(t, err)
+	require.Equal(t, []string{"history.index/2026/03/10/120000-node-1.ndjson.gz.json", "history.index/2026/03/10/120100-node-2.ndjson.gz.json"}, keys)
+
+	r := NewReader(storage, "history")
+	require.Len(t, readAll(t, r, "ch1", day, day.Add(time.Hour)), 1)
+	require.Equal(t, []string{
+		"history.index/2026/03/10/120000-node-1.ndjson.gz.json",
+		"history/2026/03/10/120000-node-1.ndjson.gz",
+		"history.index/2026/03/10/120100-node-2.ndjson.gz.json",
+	}, storage.gets)
+
+	// Time range does not intersect file bounds.
+	storage.gets = nil
+	require.Empty(t, readAll(t, r, "ch1", day.Add(time.Second), day.Add(time.Hour)))
+	require.Len(t, storage.gets, 2)
+
+	// File without index is scanned.
+	require.NoError(t, os.Remove(filepath.Join(storage.Storage.(*LocalStorage).dir, "history.index", "2026", "03", "10", "120000-node-1.ndjson.gz.json")))
+	storage.gets = nil
+	require.Len(t, readAll(t, r, "ch1", day, day.Add(time.Hour)), 1)
+	require.Equal(t, "history/2026/03/10/120000-node-1.ndjson.gz", storage.gets[0])
+}
+
+// countingStorage records keys passed to Get.
+type countingStorage struct {
+	Storage
+	gets []string
+}
+
+func (s *countingStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	s.gets = append(s.gets, key)
+	return s.Storage.Get(ctx, key)
+}
+
+func TestArchiverRunFlushesOnShutdown(t *testing.T) {
+	storage := NewLocalStorage(t.TempDir())
+	a := New("node", storage, testConfig("gzip"))
+	a.Add(Record{Channel: "ch", Time: time.Now().UnixMilli()})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, a.Run(ctx))
+
+	keys, err := storage.List(context.Background(), "history/")
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+}
+
+func TestArchivePartition(t *testing.T) {
+	storage := NewLocalStorage(t.TempDir())
+	cfg := testConfig("zstd")
+	cfg.Source = SourcePostgresPartitions
+	a := New("node", storage, cfg)
+
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		// Archiving the same partition again replaces the file.
+		err := a.ArchivePartition(context.Background(), "cf_stream_history_2026_03_10", day, func(write func(Record) error) error {
+			for offset := uint64(1); offset <= 3; offset++ {
+				if err := write(Record{Channel: "ch", Offset: offset, Time: day.Add(time.Duration(offset) * time.Hour).UnixMilli()}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	keys, err := storage.List(context.Background(), "history/2026/03/10/")
+	require.NoError(t, err)
+	require.Equal(t, []string{"history/2026/03/10/partition-cf_stream_history_2026_03_10.ndjson.zst"}, keys)
+
+	records := readAll(t, NewReader(storage, "history"), "ch", day, day.Add(24*time.Hour))
+	require.Len(t, records, 3)
+}
+
+func TestArchivePartitionReadError(t *testing.T) {
+	storage := NewLocalStorage(t.TempDir())
+	a := New("node", storage, testConfig("gzip"))
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	err := a.ArchivePartition(context.Background(), "p", day, func(write func(Record) error) error {
+		return io.ErrUnexpectedEOF
+	})
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	keys, err := storage.List(context.Background(), "history/")
+	require.NoError(t, err)
+	require.Empty(t, keys)
+}
+
+func TestLocalStorageGetNotFound(t *testing.T) {
+	_, err := NewLocalStorage(t.TempDir()).Get(context.Background(), "history/missing.ndjson.gz")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+// testS3Server is a minimal in-memory S3 serving path-style object requests.
+type testS3Server struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	auth    []string
+}
+
+func (s *testS3Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.auth = append(s.auth, r.Header.Get("Authorization"))
+	key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+	switch {
+	case r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		s.objects[key] = data
+	case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		prefix := r.URL.Query().Get("prefix")
+		var keys []string
+		for k := range s.objects {
+			if strings.HasPrefix(k, prefix) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		// Return one key per page to check continuation.
+		var result struct {
+			XMLName  xml.Name `xml:"ListBucketResult"`
+			Contents []struct {
+				Key string `xml:"Key"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken,omitempty"`
+		}
+		start := 0
+		if token := r.URL.Query().Get("continuation-token"); token != "" {
+			start = sort.SearchStrings(keys, token)
+		}
+		if start < len(keys) {
+			result.Contents = append(result.Contents, struct {
+				Key string `xml:"Key"`
+			}{Key: keys[start]})
+		}
+		if start+1 < len(keys) {
+			result.IsTruncated = true
+			result.NextContinuationToken = keys[start+1]
+		}
+		_ = xml.NewEncoder(w).Encode(result)
+	case r.Method == http.MethodGet:
+		data, ok := s.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(data)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	srv := &testS3Server{objects: map[string][]byte{}}
+	server := httptest.NewServer(srv)
+	defer server.Close()
+
+	storage, err := NewS3Storage(context.Background(), configtypes.HistoryArchiveS3{
+		Endpoint:        server.URL,
+		Region:          "us-east-1",
+		Bucket:          "bucket",
+		UsePathStyle:    true,
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+	})
+	require.NoError(t, err)
+
+	cfg := testConfig("gzip")
+	cfg.Storage = "s3"
+	a := New("node", storage, cfg)
+	day := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		a.Add(Record{Channel: "ch", Offset: uint64(i + 1), Time: day.UnixMilli()})
+		a.flush(context.Background())
+	}
+
+	keys, err := storage.List(context.Background(), "history/2026/03/10/")
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+
+	records := readAll(t, NewReader(storage, "history"), "ch", day, day.Add(time.Hour))
+	require.Len(t, records, 3)
+
+	_, err = storage.Get(context.Background(), "history/missing.ndjson.gz")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	for _, auth := range srv.auth {
+		require.True(t, strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=key/"), auth)
+	}
+}
+
+type testBroker struct {
+	centrifuge.Broker
+	result centrifuge.PublishResult
+}
+
+func (b *testBroker) Publish(_ string, _ []byte, _ centrifuge.PublishOptions) (centrifuge.PublishResult, error) {
+	return b.result, nil
+}
+
+func TestBrokerPublish(t *testing.T) {
+	a := New("node", NewLocalStorage(t.TempDir()), testConfig("gzip"))
+	inner := &testBroker{}
+	inner.result.StreamPosition = centrifuge.StreamPosition{Offset: 5, Epoch: "e"}
+	b := NewBroker(inner, a)
+
+	_, err := b.Publish("ch", []byte(`{}`), centrifuge.PublishOptions{})
+	require.NoError(t, err)
+	require.Empty(t, a.buffer, "publication without history must not be archived")
+
+	_, err = b.Publish("ch", []byte(`{}`), centrifuge.PublishOptions{
+		HistorySize: 10,
+		HistoryTTL:  time.Minute,
+		ClientInfo:  &centrifuge.ClientInfo{UserID: "u"},
+	})
+	require.NoError(t, err)
+	require.Len(t, a.buffer, 1)
+	require.Equal(t, uint64(5), a.buffer[0].Offset)
+	require.Equal(t, "e", a.buffer[0].Epoch)
+	require.Equal(t, "u", a.buffer[0].User)
+
+	inner.result.Suppressed = true
+	_, err = b.Publish("ch", []byte(`{}`), centrifuge.PublishOptions{HistorySize: 10, HistoryTTL: time.Minute})
+	require.NoError(t, err)
+	require.Len(t, a.buffer, 1, "suppressed publication must not be archived")
+}
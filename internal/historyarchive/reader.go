@@ -0,0 +1,117 @@
+package historyarchive
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// maxRecordSize limits size of a single NDJSON line when reading archive.
+const maxRecordSize = 64 * 1024 * 1024
+
+// Reader reads archived publications.
+type Reader struct {
+	storage Storage
+	prefix  string
+}
+
+// NewReader creates Reader of archive files under prefix.
+func NewReader(storage Storage, prefix string) *Reader {
+	return &Reader{storage: storage, prefix: prefix}
+}
+
+// Read calls fn for every archived publication of channel with time in range
+// [from, to). Publications are passed in order of archive files, which is time
+// order of publications within a file written by one node or a partition. Files
+// which have index are only opened if index contains channel with time bounds
+// intersecting range, files without index are scanned.
+func (r *Reader) Read(ctx context.Context, channel string, from time.Time, to time.Time, fn func(Record) error) error {
+	if !from.Before(to) {
+		return nil
+	}
+	fromMs, toMs := from.UnixMilli(), to.UnixMilli()
+	// File with publications buffered around midnight is put into a directory of
+	// its first publication, so one more day before range is checked.
+	day := from.UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		keys, err := r.storage.List(ctx, dayPrefix(r.prefix, day))
+		if err != nil {
+			return fmt.Errorf("error listing archive files: %w", err)
+		}
+		indexKeys, err := r.storage.List(ctx, dayPrefix(r.prefix+".index", day))
+		if err != nil {
+			return fmt.Errorf("error listing archive index files: %w", err)
+		}
+		for _, key := range keys {
+			// Keys returned by List are sorted.
+			if idxKey := indexKey(r.prefix, key); hasKey(indexKeys, idxKey) {
+				idx, err := r.readIndex(ctx, idxKey)
+				if err != nil {
+					return err
+				}
+				b, ok := idx.Channels[channel]
+				if !ok || b.Max < fromMs || b.Min >= toMs {
+					continue
+				}
+			}
+			if err := r.readFile(ctx, key, func(rec Record) error {
+				if rec.Channel != channel || rec.Time < fromMs || rec.Time >= toMs {
+					return nil
+				}
+				return fn(rec)
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func hasKey(keys []string, key string) bool {
+	_, found := slices.BinarySearch(keys, key)
+	return found
+}
+
+func (r *Reader) readIndex(ctx context.Context, key string) (fileIndex, error) {
+	body, err := r.storage.Get(ctx, key)
+	if err != nil {
+		return fileIndex{}, fmt.Errorf("error opening archive index file %s: %w", key, err)
+	}
+	defer func() { _ = body.Close() }()
+	var idx fileIndex
+	if err := json.NewDecoder(body).Decode(&idx); err != nil {
+		return fileIndex{}, fmt.Errorf("malformed archive index file %s: %w", key, err)
+	}
+	return idx, nil
+}
+
+func (r *Reader) readFile(ctx context.Context, key string, fn func(Record) error) error {
+	body, err := r.storage.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("error opening archive file %s: %w", key, err)
+	}
+	defer func() { _ = body.Close() }()
+	reader, err := decompress(key, body)
+	if err != nil {
+		return fmt.Errorf("error reading archive file %s: %w", key, err)
+	}
+	defer func() { _ = reader.Close() }()
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRecordSize)
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return fmt.Errorf("malformed record in archive file %s: %w", key, err)
+		}
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading archive file %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package historyarchive
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+// unsignedPayload tells S3 that request body is not included into signature, so
+// that archive files are streamed without hashing them upfront.
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3Storage keeps archive files in S3-compatible object storage. It only needs
+// a few object operations, so it talks to S3 REST API directly with requests
+// signed by AWS Signature Version 4.
+type S3Storage struct {
+	cfg         configtypes.HistoryArchiveS3
+	endpoint    *url.URL
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	client      *http.Client
+}
+
+var _ Storage = (*S3Storage)(nil)
+
+// NewS3Storage creates S3Storage.
+func NewS3Storage(ctx context.Context, cfg configtypes.HistoryArchiveS3) (*S3Storage, error) {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+	var provider aws.CredentialsProvider
+	if cfg.AccessKeyID != "" {
+		provider = credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, "")
+	} else {
+		awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.Region))
+		if err != nil {
+			return nil, fmt.Errorf("error loading AWS config: %w", err)
+		}
+		provider = awsCfg.Credentials
+	}
+	return &S3Storage{
+		cfg:         cfg,
+		endpoint:    u,
+		credentials: aws.NewCredentialsCache(provider),
+		signer: v4.NewSigner(func(o *v4.SignerOptions) {
+			// S3 signs already escaped path, same as AWS SDK S3 client does.
+			o.DisableURIPathEscaping = true
+		}),
+		client: &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+// objectURL returns URL of object with key, or of bucket if key is empty.
+func (s *S3Storage) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	path := strings.TrimSuffix(u.Path, "/")
+	if s.cfg.UsePathStyle {
+		path += "/" + s.cfg.Bucket
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+	}
+	u.Path = path + "/" + key
+	return &u
+}
+
+func (s *S3Storage) do(ctx context.Context, method string, u *url.URL, body io.ReadSeeker) (*http.Response, error) {
+	var contentLength int64
+	if body != nil {
+		size, err := body.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := body.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		contentLength = size
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = contentLength
+	}
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	creds, err := s.credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving AWS credentials: %w", err)
+	}
+	if err := s.signer.SignHTTP(ctx, creds, req, unsignedPayload, "s3", s.cfg.Region, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.client.Do(req)
+}
+
+func s3Error(resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fmt.Errorf("unexpected S3 response status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+}
+
+// Put ...
+func (s *S3Storage) Put(ctx context.Context, key string, body io.ReadSeeker) error {
+	resp, err := s.do(ctx, http.MethodPut, s.objectURL(key), body)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return s3Error(resp)
+	}
+	return nil
+}
+
+// Get ...
+func (s *S3Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, s.objectURL(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		_ = resp.Body.Close()
+		return nil, ErrNotFound
+	default:
+		defer func() { _ = resp.Body.Close() }()
+		return nil, s3Error(resp)
+	}
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List ...
+func (s *S3Storage) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	var token string
+	for {
+		u := s.objectURL("")
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", prefix)
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		u.RawQuery = query.Encode()
+		resp, err := s.do(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			err := s3Error(resp)
+			_ = resp.Body.Close()
+			return nil, err
+		}
+		var result listBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding S3 list response: %w", err)
+		}
+		for _, c := range result.Contents {
+			keys = append(keys, c.Key)
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			// S3 returns keys in UTF-8 binary order, so keys are already sorted.
+			return keys, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
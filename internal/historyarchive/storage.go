@@ -0,0 +1,119 @@
+package historyarchive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+)
+
+// ErrNotFound returned by Storage when archive file does not exist.
+var ErrNotFound = errors.New("archive file not found")
+
+// Storage keeps archive files. Keys are slash-separated paths.
+type Storage interface {
+	// Put writes file with key, replacing existing one.
+	Put(ctx context.Context, key string, body io.ReadSeeker) error
+	// Get opens file with key for reading.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// List returns sorted keys of files which start with prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// NewStorage creates Storage from configuration.
+func NewStorage(ctx context.Context, cfg configtypes.HistoryArchive) (Storage, error) {
+	switch cfg.Storage {
+	case "local":
+		return NewLocalStorage(cfg.Local.Dir), nil
+	case "s3":
+		return NewS3Storage(ctx, cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown history archive storage: %s", cfg.Storage)
+	}
+}
+
+// LocalStorage keeps archive files in a directory on local disk.
+type LocalStorage struct {
+	dir string
+}
+
+var _ Storage = (*LocalStorage)(nil)
+
+// NewLocalStorage creates LocalStorage.
+func NewLocalStorage(dir string) *LocalStorage {
+	return &LocalStorage{dir: dir}
+}
+
+// Put writes file atomically: to a temporary file which is then renamed, so
+// that readers never see partially written archive.
+func (s *LocalStorage) Put(_ context.Context, key string, body io.ReadSeeker) error {
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err := io.Copy(f, body); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Get ...
+func (s *LocalStorage) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// List ...
+func (s *LocalStorage) List(_ context.Context, prefix string) ([]string, error) {
+	// Walk only the directory part of prefix, file names are matched below.
+	root := filepath.Join(s.dir, filepath.FromSlash(prefix[:strings.LastIndex(prefix, "/")+1]))
+	var keys []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".tmp-") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
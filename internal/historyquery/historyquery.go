@@ -0,0 +1,267 @@
+// Package historyquery implements history queries limited by publication time
+// range, with cursors to paginate over results in both directions.
+//
+// Brokers which keep publication time in an indexed form (PostgreSQL stream
+// broker) implement RangeReader and filter by time natively. For other brokers
+// (memory and Redis brokers of Centrifuge library) Reader reads channel history
+// stream in batches and filters publications by Publication.Time. Such a scan is
+// capped by MaxScan publications per query: if the cap is reached before the
+// page is filled, Result contains publications found so far and NextCursor to
+// continue scanning from. Publications without time are skipped by time range
+// queries.
+package historyquery
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/centrifugal/centrifuge"
+)
+
+// MaxScan is a maximum number of publications Reader scans in channel history
+// stream to serve one time range query when broker does not implement
+// RangeReader.
+const MaxScan = 10000
+
+// scanBatchSize is a number of publications read from channel history stream at
+// once during time range scan.
+const scanBatchSize = 1000
+
+// ErrInvalidCursor returned when pagination cursor can't be parsed.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// TimeRange of publications in Unix milliseconds. From is inclusive, To is
+// exclusive, zero value means the range is not bounded from that side.
+type TimeRange struct {
+	From int64
+	To   int64
+}
+
+// IsZero reports whether range is not bounded at all.
+func (r TimeRange) IsZero() bool {
+	return r.From == 0 && r.To == 0
+}
+
+// Contains reports whether publication time is within range.
+func (r TimeRange) Contains(t int64) bool {
+	if r.IsZero() {
+		return true
+	}
+	if t == 0 {
+		return false
+	}
+	return (r.From == 0 || t >= r.From) && (r.To == 0 || t < r.To)
+}
+
+// RangeReader may be implemented by centrifuge.Broker to filter history by time
+// range natively. It has the same semantics as centrifuge.Broker History method
+// and only returns publications within time range.
+type RangeReader interface {
+	HistoryRange(ch string, opts centrifuge.HistoryOptions, tr TimeRange) ([]*centrifuge.Publication, centrifuge.StreamPosition, error)
+}
+
+// FormatCursor returns cursor pointing to publication at stream position. The
+// cursor may be passed as since position to continue iteration in the same
+// direction.
+func FormatCursor(sp centrifuge.StreamPosition) string {
+	return strconv.FormatUint(sp.Offset, 10) + ":" + sp.Epoch
+}
+
+// ParseCursor parses cursor returned by FormatCursor. Empty cursor results into
+// nil position.
+func ParseCursor(cursor string) (*centrifuge.StreamPosition, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	offsetStr, epoch, ok := strings.Cut(cursor, ":")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	offset, err := strconv.ParseUint(offsetStr, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &centrifuge.StreamPosition{Offset: offset, Epoch: epoch}, nil
+}
+
+// Query of channel history.
+type Query struct {
+	// Since is a position to start iteration after (or before if Reverse is set).
+	Since *centrifuge.StreamPosition
+	// Limit of publications to return, 0 returns only stream position, negative
+	// value means no limit.
+	Limit int
+	// Reverse iteration from the newest publications to the oldest ones.
+	Reverse bool
+	// Range of publication time.
+	Range TimeRange
+	// MetaTTL passed to broker.
+	MetaTTL time.Duration
+}
+
+// Result of history Query.
+type Result struct {
+	Publications []*centrifuge.Publication
+	centrifuge.StreamPosition
+	// NextCursor is set if there are more publications to iterate over. Note,
+	// for time range queries scanning history stream the page may contain less
+	// publications than requested (even zero) while NextCursor is set – this
+	// happens when MaxScan is reached.
+	NextCursor string
+}
+
+type historyFunc func(ch string, opts ...centrifuge.HistoryOption) (centrifuge.HistoryResult, error)
+
+// Reader performs history queries.
+type Reader struct {
+	nodeHistory historyFunc
+	rangeReader RangeReader
+	maxScan     int
+	batchSize   int
+}
+
+// NewReader creates Reader. If rangeReader is nil, time range queries are
+// executed by filtering channel history stream.
+func NewReader(node *centrifuge.Node, rangeReader RangeReader) *Reader {
+	r := &Reader{rangeReader: rangeReader, maxScan: MaxScan, batchSize: scanBatchSize}
+	if node != nil {
+		r.nodeHistory = node.History
+	}
+	return r
+}
+
+// History returns channel history matching Query.
+func (r *Reader) History(ch string, q Query) (Result, error) {
+	if q.Range.IsZero() || q.Limit == 0 {
+		return r.history(ch, q)
+	}
+	if q.Range.From != 0 && q.Range.To != 0 && q.Range.From >= q.Range.To {
+		// Empty range, only stream position is needed.
+		q.Limit = 0
+		return r.history(ch, q)
+	}
+	if r.rangeReader != nil {
+		return r.historyRange(ch, q)
+	}
+	return r.historyFiltered(ch, q)
+}
+
+// history reads history with one publication more than requested to know
+// whether there is a next page.
+func (r *Reader) history(ch string, q Query) (Result, error) {
+	limit := q.Limit
+	if limit > 0 {
+		limit++
+	}
+	res, err := r.nodeHistory(
+		ch,
+		centrifuge.WithHistoryMetaTTL(q.MetaTTL),
+		centrifuge.WithLimit(limit),
+		centrifuge.WithSince(q.Since),
+		centrifuge.WithReverse(q.Reverse),
+	)
+	if err != nil {
+		return Result{}, err
+	}
+	return page(res.Publications, res.StreamPosition, q.Limit), nil
+}
+
+func (r *Reader) historyRange(ch string, q Query) (Result, error) {
+	limit := q.Limit
+	if limit > 0 {
+		limit++
+	}
+	pubs, sp, err := r.rangeReader.HistoryRange(ch, centrifuge.HistoryOptions{
+		Filter: centrifuge.HistoryFilter{
+			Since:   q.Since,
+			Limit:   limit,
+			Reverse: q.Reverse,
+		},
+		MetaTTL: q.MetaTTL,
+	}, q.Range)
+	if err != nil {
+		return Result{}, fmt.Errorf("error reading history range: %w", err)
+	}
+	if q.Since != nil && q.Since.Epoch != "" && q.Since.Epoch != sp.Epoch {
+		// Same as Centrifuge does for History: position from another epoch can't
+		// be used to continue iteration.
+		return Result{}, centrifuge.ErrorUnrecoverablePosition
+	}
+	return page(pubs, sp, q.Limit), nil
+}
+
+// historyFiltered reads channel history stream in batches and filters it by
+// publication time. At most maxScan publications are scanned, after that the
+// result is returned with NextCursor pointing to the last scanned publication.
+func (r *Reader) historyFiltered(ch string, q Query) (Result, error) {
+	var (
+		pubs    []*centrifuge.Publication
+		sp      centrifuge.StreamPosition
+		since   = q.Since
+		scanned int
+	)
+	for {
+		batchSize := min(r.batchSize, r.maxScan-scanned)
+		res, err := r.nodeHistory(
+			ch,
+			centrifuge.WithHistoryMetaTTL(q.MetaTTL),
+			centrifuge.WithLimit(batchSize),
+			centrifuge.WithSince(since),
+			centrifuge.WithReverse(q.Reverse),
+		)
+		if err != nil {
+			return Result{}, err
+		}
+		sp = res.StreamPosition
+		for _, pub := range res.Publications {
+			scanned++
+			if pub.Time != 0 {
+				// Publication time grows with offset, so iteration may stop as soon as
+				// range is passed.
+				if !q.Reverse && q.Range.To != 0 && pub.Time >= q.Range.To {
+					return page(pubs, sp, q.Limit), nil
+				}
+				if q.Reverse && q.Range.From != 0 && pub.Time < q.Range.From {
+					return page(pubs, sp, q.Limit), nil
+				}
+			}
+			if q.Range.Contains(pub.Time) {
+				pubs = append(pubs, pub)
+				if q.Limit > 0 && len(pubs) > q.Limit {
+					return page(pubs, sp, q.Limit), nil
+				}
+			}
+		}
+		if len(res.Publications) < batchSize {
+			// End of stream.
+			return page(pubs, sp, q.Limit), nil
+		}
+		last := centrifuge.StreamPosition{
+			Offset: res.Publications[len(res.Publications)-1].Offset,
+			Epoch:  sp.Epoch,
+		}
+		if scanned >= r.maxScan {
+			result := page(pubs, sp, q.Limit)
+			result.NextCursor = FormatCursor(last)
+			return result, nil
+		}
+		since = &last
+	}
+}
+
+// page cuts publications to limit and sets NextCursor if there were more
+// publications than limit.
+func page(pubs []*centrifuge.Publication, sp centrifuge.StreamPosition, limit int) Result {
+	res := Result{Publications: pubs, StreamPosition: sp}
+	if limit > 0 && len(pubs) > limit {
+		res.Publications = pubs[:limit]
+		res.NextCursor = FormatCursor(centrifuge.StreamPosition{
+			Offset: pubs[limit-1].Offset,
+			Epoch:  sp.Epoch,
+		})
+	}
+	return res
+}
@@ -0,0 +1,222 @@
+package historyquery
+
+import (
+	"testing"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/stretchr/testify/require"
+)
+
+type testRangeReader struct {
+	pubs  []*centrifuge.Publication
+	epoch string
+}
+
+func (r *testRangeReader) HistoryRange(_ string, opts centrifuge.HistoryOptions, tr TimeRange) ([]*centrifuge.Publication, centrifuge.StreamPosition, error) {
+	sp := centrifuge.StreamPosition{Offset: uint64(len(r.pubs)), Epoch: r.epoch}
+	var pubs []*centrifuge.Publication
+	for i := range r.pubs {
+		pub := r.pubs[i]
+		if opts.Filter.Reverse {
+			pub = r.pubs[len(r.pubs)-1-i]
+		}
+		if opts.Filter.Since != nil {
+			if !opts.Filter.Reverse && pub.Offset <= opts.Filter.Since.Offset {
+				continue
+			}
+			if opts.Filter.Reverse && pub.Offset >= opts.Filter.Since.Offset {
+				continue
+			}
+		}
+		if !tr.Contains(pub.Time) {
+			continue
+		}
+		if opts.Filter.Limit > 0 && len(pubs) == opts.Filter.Limit {
+			break
+		}
+		pubs = append(pubs, pub)
+	}
+	return pubs, sp, nil
+}
+
+func TestTimeRangeContains(t *testing.T) {
+	require.True(t, TimeRange{}.Contains(0))
+	require.True(t, TimeRange{}.Contains(10))
+	require.False(t, TimeRange{From: 10}.Contains(0))
+	require.False(t, TimeRange{From: 10}.Contains(9))
+	require.True(t, TimeRange{From: 10}.Contains(10))
+	require.True(t, TimeRange{To: 10}.Contains(9))
+	require.False(t, TimeRange{To: 10}.Contains(10))
+	require.True(t, TimeRange{From: 5, To: 10}.Contains(5))
+}
+
+func TestCursor(t *testing.T) {
+	sp, err := ParseCursor(FormatCursor(centrifuge.StreamPosition{Offset: 42, Epoch: "ab:c"}))
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), sp.Offset)
+	require.Equal(t, "ab:c", sp.Epoch)
+
+	sp, err = ParseCursor("")
+	require.NoError(t, err)
+	require.Nil(t, sp)
+
+	for _, cursor := range []string{"42", "x:abc", "-1:abc"} {
+		_, err = ParseCursor(cursor)
+		require.ErrorIs(t, err, ErrInvalidCursor, cursor)
+	}
+}
+
+func TestReaderHistoryRange(t *testing.T) {
+	rangeReader := &testRangeReader{epoch: "epoch"}
+	for i := 1; i <= 10; i++ {
+		rangeReader.pubs = append(rangeReader.pubs, &centrifuge.Publication{
+			Offset: uint64(i),
+			Time:   int64(i * 1000),
+		})
+	}
+	r := NewReader(nil, rangeReader)
+
+	collect := func(q Query) []uint64 {
+		var offsets []uint64
+		for {
+			res, err := r.History("test", q)
+			require.NoError(t, err)
+			require.Equal(t, uint64(10), res.StreamPosition.Offset)
+			for _, pub := range res.Publications {
+				offsets = append(offsets, pub.Offset)
+			}
+			if res.NextCursor == "" {
+				return offsets
+			}
+			q.Since, err = ParseCursor(res.NextCursor)
+			require.NoError(t, err)
+		}
+	}
+
+	require.Equal(t, []uint64{3, 4, 5, 6}, collect(Query{
+		Limit: 3,
+		Range: TimeRange{From: 3000, To: 7000},
+	}))
+	require.Equal(t, []uint64{6, 5, 4, 3}, collect(Query{
+		Limit:   3,
+		Reverse: true,
+		Range:   TimeRange{From: 3000, To: 7000},
+	}))
+	require.Equal(t, []uint64{10, 9}, collect(Query{
+		Limit:   2,
+		Reverse: true,
+		Range:   TimeRange{From: 9000},
+	}))
+
+	_, err := r.History("test", Query{
+		Limit: 3,
+		Since: &centrifuge.StreamPosition{Offset: 1, Epoch: "another"},
+		Range: TimeRange{From: 3000},
+	})
+	require.ErrorIs(t, err, centrifuge.ErrorUnrecoverablePosition)
+}
+
+func testNodeHistory(pubs []*centrifuge.Publication, epoch string) historyFunc {
+	return func(_ string, opts ...centrifuge.HistoryOption) (centrifuge.HistoryResult, error) {
+		historyOpts := &centrifuge.HistoryOptions{}
+		for _, opt := range opts {
+			opt(historyOpts)
+		}
+		f := historyOpts.Filter
+		sp := centrifuge.StreamPosition{Offset: uint64(len(pubs)), Epoch: epoch}
+		if f.Since != nil && f.Since.Epoch != epoch {
+			return centrifuge.HistoryResult{}, centrifuge.ErrorUnrecoverablePosition
+		}
+		var result []*centrifuge.Publication
+		for i := range pubs {
+			pub := pubs[i]
+			if f.Reverse {
+				pub = pubs[len(pubs)-1-i]
+			}
+			if f.Since != nil {
+				if !f.Reverse && pub.Offset <= f.Since.Offset {
+					continue
+				}
+				if f.Reverse && pub.Offset >= f.Since.Offset {
+					continue
+				}
+			}
+			if f.Limit >= 0 && len(result) == f.Limit {
+				break
+			}
+			result = append(result, pub)
+		}
+		return centrifuge.HistoryResult{Publications: result, StreamPosition: sp}, nil
+	}
+}
+
+func TestReaderHistoryFiltered(t *testing.T) {
+	var pubs []*centrifuge.Publication
+	for i := 1; i <= 10; i++ {
+		pubs = append(pubs, &centrifuge.Publication{
+			Offset: uint64(i),
+			Time:   int64(i * 1000),
+		})
+	}
+	r := NewReader(nil, nil)
+	r.nodeHistory = testNodeHistory(pubs, "epoch")
+	r.batchSize = 2
+	r.maxScan = 4
+
+	collect := func(q Query) ([]uint64, int) {
+		var offsets []uint64
+		var numPages int
+		for {
+			res, err := r.History("test", q)
+			require.NoError(t, err)
+			numPages++
+			require.Equal(t, uint64(10), res.StreamPosition.Offset)
+			require.LessOrEqual(t, len(res.Publications), q.Limit)
+			for _, pub := range res.Publications {
+				offsets = append(offsets, pub.Offset)
+			}
+			if res.NextCursor == "" {
+				return offsets, numPages
+			}
+			q.Since, err = ParseCursor(res.NextCursor)
+			require.NoError(t, err)
+		}
+	}
+
+	offsets, numPages := collect(Query{
+		Limit: 3,
+		Range: TimeRange{From: 3000, To: 7000},
+	})
+	require.Equal(t, []uint64{3, 4, 5, 6}, offsets)
+	require.Equal(t, 2, numPages)
+
+	offsets, _ = collect(Query{
+		Limit:   3,
+		Reverse: true,
+		Range:   TimeRange{From: 3000, To: 7000},
+	})
+	require.Equal(t, []uint64{6, 5, 4, 3}, offsets)
+
+	// Scan cap reached without matches – empty page with cursor to continue.
+	res, err := r.History("test", Query{
+		Limit: 3,
+		Range: TimeRange{From: 9000},
+	})
+	require.NoError(t, err)
+	require.Empty(t, res.Publications)
+	require.Equal(t, FormatCursor(centrifuge.StreamPosition{Offset: 4, Epoch: "epoch"}), res.NextCursor)
+
+	offsets, numPages = collect(Query{
+		Limit: 3,
+		Range: TimeRange{From: 9000},
+	})
+	require.Equal(t, []uint64{9, 10}, offsets)
+	require.Equal(t, 3, numPages)
+
+	_, err = r.History("test", Query{
+		Limit: 3,
+		Since: &centrifuge.StreamPosition{Offset: 1, Epoch: "another"},
+		Range: TimeRange{From: 3000},
+	})
+	require.ErrorIs(t, err, centrifuge.ErrorUnrecoverablePosition)
+}
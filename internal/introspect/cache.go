@@ -0,0 +1,77 @@
+package introspect
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/jwtverify"
+)
+
+type cacheItem struct {
+	token     jwtverify.ConnectToken
+	expiresAt time.Time
+}
+
+// cache is a TTL based in-memory cache of introspection results. Unlike jwks.TTLCache
+// each item has its own TTL bound to token expiration.
+type cache struct {
+	mu    sync.RWMutex
+	items map[string]cacheItem
+}
+
+func newCache() *cache {
+	return &cache{
+		items: make(map[string]cacheItem),
+	}
+}
+
+// run removes expired items until context is done.
+func (c *cache) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(max(interval, time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.cleanup()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *cache) cleanup() {
+	now := time.Now()
+	c.mu.Lock()
+	for key, item := range c.items {
+		if !now.Before(item.expiresAt) {
+			delete(c.items, key)
+		}
+	}
+	c.mu.Unlock()
+}
+
+func (c *cache) add(key string, token jwtverify.ConnectToken, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	c.items[key] = cacheItem{token: token, expiresAt: time.Now().Add(ttl)}
+	c.mu.Unlock()
+}
+
+func (c *cache) get(key string) (jwtverify.ConnectToken, bool) {
+	c.mu.RLock()
+	item, ok := c.items[key]
+	c.mu.RUnlock()
+	if !ok || !time.Now().Before(item.expiresAt) {
+		return jwtverify.ConnectToken{}, false
+	}
+	return item.token, true
+}
+
+func (c *cache) len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.items)
+}
@@ -0,0 +1,187 @@
+// Package introspect verifies opaque connection tokens using OAuth 2.0 token
+// introspection endpoint (RFC 7662).
+package introspect
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/jwtverify"
+
+	"github.com/tidwall/gjson"
+)
+
+type Config = configtypes.TokenIntrospection
+
+const defaultUserIDClaim = "sub"
+
+var errUnexpectedStatusCode = errors.New("unexpected introspection response status code")
+
+// Verifier verifies connection tokens by calling introspection endpoint. Active
+// results are cached until token expiration, but not longer than CacheMaxTTL.
+// Verifier must be run to clean up expired cache items, in-flight introspection
+// requests are canceled when Run returns.
+type Verifier struct {
+	config Config
+	client *http.Client
+	cache  *cache
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewVerifier creates Verifier.
+func NewVerifier(cfg Config) (*Verifier, error) {
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing introspection endpoint: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("introspection endpoint must have http:// or https:// scheme, got: %s", cfg.Endpoint)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	v := &Verifier{
+		config: cfg,
+		client: &http.Client{Timeout: cfg.Timeout.ToDuration()},
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	if cfg.CacheMaxTTL > 0 {
+		v.cache = newCache()
+	}
+	return v, nil
+}
+
+// Run cleans up expired cache items until context is done.
+func (v *Verifier) Run(ctx context.Context) error {
+	defer v.Close()
+	if v.cache != nil {
+		v.cache.run(ctx, v.config.CacheMaxTTL.ToDuration())
+	} else {
+		<-ctx.Done()
+	}
+	return ctx.Err()
+}
+
+// Close cancels in-flight introspection requests, verification fails after it.
+func (v *Verifier) Close() {
+	v.cancel()
+}
+
+// VerifyConnectToken introspects token. skipVerify is ignored since opaque tokens
+// can only be checked by the authorization server. Empty and inactive tokens
+// result into jwtverify.ErrInvalidToken, expired tokens into
+// jwtverify.ErrTokenExpired, so callers can handle them in the same way as JWT
+// verification errors. Errors of calling introspection endpoint (transport
+// errors, unexpected status codes, invalid responses) are returned as is.
+func (v *Verifier) VerifyConnectToken(token string, _ bool) (jwtverify.ConnectToken, error) {
+	if token == "" {
+		return jwtverify.ConnectToken{}, fmt.Errorf("%w: empty token", jwtverify.ErrInvalidToken)
+	}
+	key := cacheKey(token)
+	if v.cache != nil {
+		if ct, ok := v.cache.get(key); ok {
+			return ct, nil
+		}
+	}
+
+	data, err := v.introspect(v.ctx, token)
+	if err != nil {
+		return jwtverify.ConnectToken{}, err
+	}
+
+	if !gjson.GetBytes(data, "active").Bool() {
+		return jwtverify.ConnectToken{}, fmt.Errorf("%w: token is not active", jwtverify.ErrInvalidToken)
+	}
+
+	exp := gjson.GetBytes(data, "exp").Int()
+	now := time.Now()
+	if exp > 0 && exp <= now.Unix() {
+		return jwtverify.ConnectToken{}, jwtverify.ErrTokenExpired
+	}
+
+	userIDClaim := v.config.UserIDClaim
+	if userIDClaim == "" {
+		userIDClaim = defaultUserIDClaim
+	}
+	ct := jwtverify.ConnectToken{
+		UserID:   gjson.GetBytes(data, userIDClaim).String(),
+		ExpireAt: exp,
+	}
+	if v.config.InfoClaim != "" {
+		if value := gjson.GetBytes(data, v.config.InfoClaim); value.Exists() {
+			ct.Info = []byte(value.Raw)
+		}
+	}
+	if v.config.MetaClaim != "" {
+		if value := gjson.GetBytes(data, v.config.MetaClaim); value.Exists() {
+			ct.Meta = json.RawMessage(value.Raw)
+		}
+	}
+
+	if v.cache != nil {
+		ttl := v.config.CacheMaxTTL.ToDuration()
+		if exp > 0 {
+			if untilExp := time.Unix(exp, 0).Sub(now); untilExp < ttl {
+				ttl = untilExp
+			}
+		}
+		v.cache.add(key, ct, ttl)
+	}
+	return ct, nil
+}
+
+// maxResponseSize limits size of introspection response.
+const maxResponseSize = 1024 * 1024
+
+var errResponseTooLarge = errors.New("introspection response is too large")
+
+func (v *Verifier) introspect(ctx context.Context, token string) ([]byte, error) {
+	form := url.Values{}
+	form.Set("token", token)
+	form.Set("token_type_hint", "access_token")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.config.Endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if v.config.ClientID != "" {
+		req.SetBasicAuth(url.QueryEscape(v.config.ClientID), url.QueryEscape(v.config.ClientSecret))
+	}
+
+	resp, err := v.client.Do(req) //nolint:gosec // URL is from server configuration, not user input.
+	if err != nil {
+		return nil, fmt.Errorf("error calling introspection endpoint: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %d", errUnexpectedStatusCode, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading introspection response: %w", err)
+	}
+	if len(data) > maxResponseSize {
+		return nil, errResponseTooLarge
+	}
+	if !gjson.ValidBytes(data) {
+		return nil, errors.New("invalid introspection response JSON")
+	}
+	return data, nil
+}
+
+// cacheKey hashes token so raw tokens are not kept in memory longer than needed.
+func cacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,193 @@
+package introspect
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/jwtverify"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, numRequests *int32, response func(token string) string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(numRequests, 1)
+		require.Equal(t, http.MethodPost, r.Method)
+		user, password, ok := r.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "centrifugo", user)
+		require.Equal(t, "secret", password)
+		require.NoError(t, r.ParseForm())
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response(r.PostForm.Get("token"))))
+	}))
+}
+
+func testConfig(endpoint string) Config {
+	return Config{
+		Enabled:      true,
+		Endpoint:     endpoint,
+		ClientID:     "centrifugo",
+		ClientSecret: "secret",
+		Timeout:      configtypes.Duration(time.Second),
+		InfoClaim:    "profile",
+		MetaClaim:    "ext.meta",
+		CacheMaxTTL:  configtypes.Duration(time.Minute),
+	}
+}
+
+func TestVerifier_VerifyConnectToken(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Unix()
+	var numRequests int32
+	server := newTestServer(t, &numRequests, func(token string) string {
+		switch token {
+		case "active":
+			return `{"active": true, "sub": "42", "exp": ` + strconv.FormatInt(exp, 10) + `, "profile": {"name": "Alex"}, "ext": {"meta": {"plan": "pro"}}}`
+		case "expired":
+			return `{"active": true, "sub": "42", "exp": 1}`
+		default:
+			return `{"active": false}`
+		}
+	})
+	defer server.Close()
+
+	v, err := NewVerifier(testConfig(server.URL))
+	require.NoError(t, err)
+	defer v.Close()
+
+	ct, err := v.VerifyConnectToken("active", false)
+	require.NoError(t, err)
+	require.Equal(t, "42", ct.UserID)
+	require.Equal(t, exp, ct.ExpireAt)
+	require.JSONEq(t, `{"name": "Alex"}`, string(ct.Info))
+	require.JSONEq(t, `{"plan": "pro"}`, string(ct.Meta))
+
+	// Active result is cached.
+	_, err = v.VerifyConnectToken("active", false)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&numRequests))
+
+	_, err = v.VerifyConnectToken("expired", false)
+	require.ErrorIs(t, err, jwtverify.ErrTokenExpired)
+
+	// Inactive results are not cached.
+	_, err = v.VerifyConnectToken("inactive", false)
+	require.ErrorIs(t, err, jwtverify.ErrInvalidToken)
+	_, err = v.VerifyConnectToken("inactive", false)
+	require.ErrorIs(t, err, jwtverify.ErrInvalidToken)
+	require.Equal(t, int32(4), atomic.LoadInt32(&numRequests))
+	require.Equal(t, 1, v.cache.len())
+}
+
+func TestVerifier_CustomUserIDClaim(t *testing.T) {
+	var numRequests int32
+	server := newTestServer(t, &numRequests, func(token string) string {
+		return `{"active": true, "sub": "client", "user_id": "42"}`
+	})
+	defer server.Close()
+
+	cfg := testConfig(server.URL)
+	cfg.UserIDClaim = "user_id"
+	cfg.CacheMaxTTL = 0
+	v, err := NewVerifier(cfg)
+	require.NoError(t, err)
+	defer v.Close()
+
+	ct, err := v.VerifyConnectToken("token", false)
+	require.NoError(t, err)
+	require.Equal(t, "42", ct.UserID)
+	require.Zero(t, ct.ExpireAt)
+	require.Nil(t, ct.Info)
+
+	_, err = v.VerifyConnectToken("token", false)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&numRequests))
+}
+
+func TestVerifier_EndpointError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	v, err := NewVerifier(testConfig(server.URL))
+	require.NoError(t, err)
+	defer v.Close()
+
+	_, err = v.VerifyConnectToken("token", false)
+	require.ErrorIs(t, err, errUnexpectedStatusCode)
+	require.NotErrorIs(t, err, jwtverify.ErrInvalidToken)
+
+	_, err = v.VerifyConnectToken("", false)
+	require.ErrorIs(t, err, jwtverify.ErrInvalidToken)
+}
+
+func TestVerifier_ResponseTooLarge(t *testing.T) {
+	var numRequests int32
+	server := newTestServer(t, &numRequests, func(token string) string {
+		return `{"active":true,"sub":"` + strings.Repeat("x", maxResponseSize) + `"}`
+	})
+	defer server.Close()
+
+	v, err := NewVerifier(testConfig(server.URL))
+	require.NoError(t, err)
+	defer v.Close()
+
+	_, err = v.VerifyConnectToken("token", false)
+	require.ErrorIs(t, err, errResponseTooLarge)
+}
+
+func TestVerifier_Run(t *testing.T) {
+	var numRequests int32
+	server := newTestServer(t, &numRequests, func(token string) string {
+		return `{"active": true, "sub": "42"}`
+	})
+	defer server.Close()
+
+	v, err := NewVerifier(testConfig(server.URL))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- v.Run(ctx) }()
+	_, err = v.VerifyConnectToken("active", false)
+	require.NoError(t, err)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	_, err = v.VerifyConnectToken("another", false)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, int32(1), atomic.LoadInt32(&numRequests))
+}
+
+func TestNewVerifier_InvalidEndpoint(t *testing.T) {
+	_, err := NewVerifier(Config{Endpoint: "ftp://example.com"})
+	require.Error(t, err)
+}
+
+func TestCache(t *testing.T) {
+	c := newCache()
+	c.add("a", jwtverify.ConnectToken{UserID: "1"}, time.Minute)
+	c.add("b", jwtverify.ConnectToken{UserID: "2"}, -time.Second)
+	c.add("c", jwtverify.ConnectToken{UserID: "3"}, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	ct, ok := c.get("a")
+	require.True(t, ok)
+	require.Equal(t, "1", ct.UserID)
+	_, ok = c.get("b")
+	require.False(t, ok)
+	_, ok = c.get("c")
+	require.False(t, ok)
+
+	c.cleanup()
+	require.Equal(t, 1, c.len())
+}
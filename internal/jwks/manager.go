@@ -11,6 +11,8 @@ import (
 	"path"
 	"time"
 
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+
 	"github.com/rakutentech/jwk-go/jwk"
 	"github.com/rs/zerolog/log"
 	"github.com/valyala/fasttemplate"
@@ -45,6 +47,7 @@ var (
 // Manager fetches and returns JWK from public source.
 type Manager struct {
 	url      *fasttemplate.Template
+	name     string
 	cache    Cache
 	client   *http.Client
 	useCache bool
@@ -73,7 +76,8 @@ func NewManager(rawURL string, opts ...Option) (*Manager, error) {
 	urlTemplate := fasttemplate.New(rawURL, "{{", "}}")
 
 	mng := &Manager{
-		url: urlTemplate,
+		url:  urlTemplate,
+		name: u.Host,
 
 		cache:    NewTTLCache(_defaultTTL),
 		client:   defaultHTTPClient(),
@@ -92,6 +96,11 @@ func NewManager(rawURL string, opts ...Option) (*Manager, error) {
 	return mng, nil
 }
 
+// Name returns endpoint name used in metrics and logs.
+func (m *Manager) Name() string {
+	return m.name
+}
+
 // FetchKey fetches JWKS from public source or cache.
 //
 // The cache and singleflight keys are scoped to the resolved JWKS endpoint URL,
@@ -184,6 +193,7 @@ func (m *Manager) fetchKey(ctx context.Context, jwkURL, kid string) (*JWK, error
 	retries := m.retries
 	for {
 		if retries == 0 {
+			metrics.IncJWKSRefreshError(m.name)
 			return nil, lastError
 		}
 		retries--
@@ -197,8 +207,10 @@ func (m *Manager) fetchKey(ctx context.Context, jwkURL, kid string) (*JWK, error
 	}
 
 	if err := json.Unmarshal(data, &set); err != nil {
+		metrics.IncJWKSRefreshError(m.name)
 		return nil, fmt.Errorf("%w: %v", errUnmarshal, err)
 	}
+	metrics.IncJWKSRefresh(m.name)
 
 	if len(set.Keys) == 0 {
 		return nil, ErrPublicKeyNotFound
@@ -7,13 +7,25 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"sync/atomic"
 	"testing"
 
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/rakutentech/jwk-go/jwk"
 	"github.com/stretchr/testify/require"
 )
 
+func TestMain(m *testing.M) {
+	_ = metrics.Init(metrics.Config{
+		Registerer: prometheus.NewRegistry(),
+	})
+	os.Exit(m.Run())
+}
+
 type testKey struct {
 	Kid string
 	Key any
@@ -108,6 +120,35 @@ func TestManagerFetchKey_WrongStatusCode(t *testing.T) {
 	require.ErrorIs(t, err, errUnexpectedStatusCode)
 }
 
+func TestManagerFetchKey_Metrics(t *testing.T) {
+	_, pubKey, err := randomKeys()
+	require.NoError(t, err)
+
+	var fail atomic.Bool
+	handler := jwksHandler(testKey{"202101", pubKey})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	manager, err := NewManager(server.URL, WithName("test_metrics"), WithUseCache(false))
+	require.NoError(t, err)
+	require.Equal(t, "test_metrics", manager.Name())
+
+	_, err = manager.FetchKey(context.Background(), "202101", nil)
+	require.NoError(t, err)
+	require.Equal(t, float64(1), testutil.ToFloat64(metrics.JWKSRefreshesTotal.WithLabelValues("test_metrics")))
+
+	fail.Store(true)
+	_, err = manager.FetchKey(context.Background(), "202101", nil)
+	require.ErrorIs(t, err, errUnexpectedStatusCode)
+	require.Equal(t, float64(1), testutil.ToFloat64(metrics.JWKSRefreshErrorsTotal.WithLabelValues("test_metrics")))
+}
+
 func TestManagerInitialFetchKey(t *testing.T) {
 	_, pubKey, err := randomKeys()
 	require.NoError(t, err)
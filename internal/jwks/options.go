@@ -26,3 +26,8 @@ func WithUseCache(flag bool) Option {
 func WithMaxRetries(n uint) Option {
 	return func(m *Manager) { m.retries = n }
 }
+
+// WithName sets endpoint name used in metrics and logs. Default is endpoint host.
+func WithName(name string) Option {
+	return func(m *Manager) { m.name = name }
+}
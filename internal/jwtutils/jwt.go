@@ -2,6 +2,7 @@ package jwtutils
 
 import (
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
@@ -12,6 +13,7 @@ var (
 	errKeyMustBePEMEncoded = errors.New("key must be PEM encoded")
 	errNotRSAPublicKey     = errors.New("key is not a valid RSA public key")
 	errNotECDSAPublicKey   = errors.New("key is not a valid ECDSA public key")
+	errNotEd25519PublicKey = errors.New("key is not a valid Ed25519 public key")
 )
 
 // ParseRSAPublicKeyFromPEM parses PEM encoded PKCS1 or PKCS8 public key.
@@ -67,3 +69,30 @@ func ParseECDSAPublicKeyFromPEM(key []byte) (*ecdsa.PublicKey, error) {
 
 	return pkey, nil
 }
+
+// ParseEd25519PublicKeyFromPEM parses PEM encoded public key.
+func ParseEd25519PublicKeyFromPEM(key []byte) (ed25519.PublicKey, error) {
+	var err error
+
+	var block *pem.Block
+	if block, _ = pem.Decode(key); block == nil {
+		return nil, errKeyMustBePEMEncoded
+	}
+
+	var parsedKey any
+	if parsedKey, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+			parsedKey = cert.PublicKey
+		} else {
+			return nil, err
+		}
+	}
+
+	var pkey ed25519.PublicKey
+	var ok bool
+	if pkey, ok = parsedKey.(ed25519.PublicKey); !ok {
+		return nil, errNotEd25519PublicKey
+	}
+
+	return pkey, nil
+}
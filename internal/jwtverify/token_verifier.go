@@ -3,6 +3,8 @@ package jwtverify
 import (
 	"encoding/json"
 
+	"github.com/centrifugal/centrifugo/v6/internal/capability"
+
 	"github.com/centrifugal/centrifuge"
 )
 
@@ -30,6 +32,8 @@ type ConnectToken struct {
 	Meta json.RawMessage
 	// Subs is a map of channels to subscribe server-side with options.
 	Subs map[string]centrifuge.SubscribeOptions
+	// Caps is compiled channel capabilities, nil if token has no caps.
+	Caps *capability.Checker
 }
 
 type SubscribeToken struct {
@@ -42,4 +46,6 @@ type SubscribeToken struct {
 	Client string
 	// Options for subscription.
 	Options centrifuge.SubscribeOptions
+	// Allow is a set of operations allowed in channel while subscribed.
+	Allow capability.Operations
 }
@@ -3,6 +3,7 @@ package jwtverify
 import (
 	"context"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"encoding/base64"
 	"encoding/json"
@@ -13,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/centrifugal/centrifugo/v6/internal/capability"
 	"github.com/centrifugal/centrifugo/v6/internal/config"
 	"github.com/centrifugal/centrifugo/v6/internal/jwks"
 	"github.com/centrifugal/centrifugo/v6/internal/subsource"
@@ -47,11 +49,20 @@ type VerifierConfig struct {
 	// tokens generated using ECDSA. Zero value means that ECDSA tokens won't be allowed.
 	ECDSAPublicKey *ecdsa.PublicKey
 
+	// Ed25519PublicKey is a public key used to validate connection and subscription
+	// tokens generated using EdDSA. Zero value means that EdDSA tokens won't be allowed.
+	Ed25519PublicKey ed25519.PublicKey
+
 	// JWKSPublicEndpoint is a public url used to validate connection and subscription
 	// tokens generated using rotating RSA public keys. Zero value means that JSON Web Key Sets
 	// extension won't be used.
 	JWKSPublicEndpoint string
 
+	// JWKSEndpoints is a list of JWKS endpoints tried in order to find token key by kid.
+	// Each endpoint may be bound to a specific issuer and audience. Can not be used
+	// together with JWKSPublicEndpoint.
+	JWKSEndpoints []JWKSEndpoint
+
 	// Audience when set will enable audience token check. See
 	// https://datatracker.ietf.org/doc/html/rfc7519#section-4.1.3.
 	Audience string
@@ -79,6 +90,18 @@ type VerifierConfig struct {
 	InsecureSkipJWKSEndpointSafetyCheck bool
 }
 
+// JWKSEndpoint is one of JWKS endpoints used to verify tokens.
+type JWKSEndpoint struct {
+	// Name of endpoint used in metrics and logs. Endpoint host is used when not set.
+	Name string
+	// URL of JWKS endpoint.
+	URL string
+	// Issuer when set restricts endpoint to tokens with matching iss claim.
+	Issuer string
+	// Audience when set restricts endpoint to tokens with matching aud claim.
+	Audience string
+}
+
 func (c VerifierConfig) Validate() error {
 	if c.Audience != "" && c.AudienceRegex != "" {
 		return errors.New("can not use both token_audience and token_audience_regex, configure only one of them")
@@ -86,8 +109,21 @@ func (c VerifierConfig) Validate() error {
 	if c.Issuer != "" && c.IssuerRegex != "" {
 		return errors.New("can not use both token_issuer and token_issuer_regex, configure only one of them")
 	}
+	if c.JWKSPublicEndpoint != "" && len(c.JWKSEndpoints) > 0 {
+		return errors.New("can not use both jwks_public_endpoint and jwks_endpoints, configure only one of them")
+	}
+	endpoints := make([]string, 0, len(c.JWKSEndpoints)+1)
 	if c.JWKSPublicEndpoint != "" {
-		if err := validateJWKSEndpointSafety(c.JWKSPublicEndpoint, c.IssuerRegex, c.AudienceRegex); err != nil {
+		endpoints = append(endpoints, c.JWKSPublicEndpoint)
+	}
+	for i, e := range c.JWKSEndpoints {
+		if e.URL == "" {
+			return fmt.Errorf("jwks_endpoints[%d]: url is required", i)
+		}
+		endpoints = append(endpoints, e.URL)
+	}
+	for _, endpoint := range endpoints {
+		if err := validateJWKSEndpointSafety(endpoint, c.IssuerRegex, c.AudienceRegex); err != nil {
 			if c.InsecureSkipJWKSEndpointSafetyCheck {
 				log.Warn().Err(err).Msg("JWKS endpoint template safety check skipped — this is INSECURE and the escape hatch will be removed in a future release, please update your regex to use an explicit list of allowed values")
 			} else {
@@ -98,6 +134,31 @@ func (c VerifierConfig) Validate() error {
 	return nil
 }
 
+// newJWKSManagers creates JWKS managers for configured endpoints. Returns nil if no
+// JWKS endpoints configured.
+func newJWKSManagers(config VerifierConfig) ([]*jwksManager, error) {
+	if config.JWKSPublicEndpoint != "" {
+		mng, err := jwks.NewManager(config.JWKSPublicEndpoint)
+		if err != nil {
+			return nil, fmt.Errorf("error creating JWK manager: %w", err)
+		}
+		return []*jwksManager{{Manager: mng}}, nil
+	}
+	var managers []*jwksManager
+	for i, e := range config.JWKSEndpoints {
+		var opts []jwks.Option
+		if e.Name != "" {
+			opts = append(opts, jwks.WithName(e.Name))
+		}
+		mng, err := jwks.NewManager(e.URL, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("error creating JWK manager for jwks_endpoints[%d]: %w", i, err)
+		}
+		managers = append(managers, &jwksManager{Manager: mng, issuer: e.Issuer, audience: e.Audience})
+	}
+	return managers, nil
+}
+
 func NewTokenVerifierJWT(config VerifierConfig, cfgContainer *config.Container) (*VerifierJWT, error) {
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("error validating token verifier config: %w", err)
@@ -127,22 +188,29 @@ func NewTokenVerifierJWT(config VerifierConfig, cfgContainer *config.Container)
 		userIDClaim:  config.UserIDClaim,
 	}
 
-	if config.JWKSPublicEndpoint != "" {
-		mng, err := jwks.NewManager(config.JWKSPublicEndpoint)
-		if err != nil {
-			return nil, fmt.Errorf("error creating JWK manager: %w", err)
+	managers, err := newJWKSManagers(config)
+	if err != nil {
+		return nil, err
+	}
+	if len(managers) > 0 {
+		verifier.jwksManagers = managers
+		if config.JWKSPublicEndpoint != "" {
+			log.Info().Str("endpoint", strings.Join(tools.RedactedLogURLs(config.JWKSPublicEndpoint), ",")).
+				Msg("JWKS manager created")
+		} else {
+			for _, m := range managers {
+				log.Info().Str("name", m.Name()).Str("issuer", m.issuer).Str("audience", m.audience).
+					Msg("JWKS manager created")
+			}
 		}
-		verifier.jwksManager = &jwksManager{mng}
-		log.Info().Str("endpoint", strings.Join(tools.RedactedLogURLs(config.JWKSPublicEndpoint), ",")).
-			Msg("JWKS manager created")
 	} else {
-		alg, err := newAlgorithms(config.HMACSecretKey, config.RSAPublicKey, config.ECDSAPublicKey)
+		alg, err := newAlgorithms(config.HMACSecretKey, config.RSAPublicKey, config.ECDSAPublicKey, config.Ed25519PublicKey)
 		if err != nil {
 			return nil, fmt.Errorf("error initializing token algorithms: %w", err)
 		}
 		verifier.algorithms = alg
 		if config.HMACPreviousSecretKey != "" {
-			prevAlg, err := newAlgorithms(config.HMACPreviousSecretKey, nil, nil)
+			prevAlg, err := newAlgorithms(config.HMACPreviousSecretKey, nil, nil, nil)
 			if err != nil {
 				return nil, fmt.Errorf("error initializing previous HMAC token algorithms: %w", err)
 			}
@@ -156,7 +224,7 @@ func NewTokenVerifierJWT(config VerifierConfig, cfgContainer *config.Container)
 
 type VerifierJWT struct {
 	mu                              sync.RWMutex
-	jwksManager                     *jwksManager
+	jwksManagers                    []*jwksManager
 	algorithms                      *algorithms
 	previousHMACAlgorithms          *algorithms
 	hmacPreviousSecretKeyValidUntil int64
@@ -174,6 +242,7 @@ var (
 	errPublicKeyInvalid     = errors.New("public key is invalid")
 	errUnsupportedAlgorithm = errors.New("unsupported JWT algorithm")
 	errDisabledAlgorithm    = errors.New("disabled JWT algorithm")
+	errNoMatchingJWKS       = errors.New("no JWKS endpoint matches token issuer and audience")
 )
 
 // BoolValue allows override boolean option.
@@ -224,6 +293,8 @@ type ConnectTokenClaims struct {
 	Channels   []string                    `json:"channels,omitempty"`
 	Subs       map[string]SubscribeOptions `json:"subs,omitempty"`
 	Meta       json.RawMessage             `json:"meta,omitempty"`
+	// Caps is a list of channel capabilities.
+	Caps []capability.ChannelsCapability `json:"caps,omitempty"`
 	// Channel must never be set in connection tokens. We check this on verifying.
 	Channel string `json:"channel,omitempty"`
 	jwt.RegisteredClaims
@@ -235,18 +306,29 @@ type SubscribeTokenClaims struct {
 	Channel  string `json:"channel,omitempty"`
 	Client   string `json:"client,omitempty"`
 	ExpireAt *int64 `json:"expire_at,omitempty"`
+	// Allow is a list of operations allowed in channel while subscribed.
+	Allow []string `json:"allow,omitempty"`
 }
 
-type jwksManager struct{ *jwks.Manager }
-
-func (j *jwksManager) verify(token *jwt.Token, tokenVars map[string]any) error {
-	kid := token.Header().KeyID
+type jwksManager struct {
+	*jwks.Manager
+	issuer   string
+	audience string
+}
 
-	key, err := j.Manager.FetchKey(context.Background(), kid, tokenVars)
-	if err != nil {
-		return err
+// matches checks whether token claims match issuer and audience bound to endpoint.
+func (j *jwksManager) matches(claims jwt.RegisteredClaims) bool {
+	if j.issuer != "" && !claims.IsIssuer(j.issuer) {
+		return false
 	}
+	if j.audience != "" && !claims.IsForAudience(j.audience) {
+		return false
+	}
+	return true
+}
 
+// VerifyByJWK verifies token signature with a public key from JWKS.
+func VerifyByJWK(token *jwt.Token, key *jwks.JWK) error {
 	if key.Kty != "RSA" && key.Kty != "EC" && key.Kty != "OKP" {
 		return errUnsupportedAlgorithm
 	}
@@ -266,14 +348,20 @@ func (j *jwksManager) verify(token *jwt.Token, tokenVars map[string]any) error {
 		keySetAlgorithm := spec.Algorithm
 		if keySetAlgorithm == "" { // "alg" is optional: https://datatracker.ietf.org/doc/html/rfc7517#section-4.4.
 			switch token.Header().Algorithm {
-			case jwt.RS256, jwt.RS384, jwt.RS512: // Only allow algorithms from RSA family.
+			case jwt.RS256, jwt.RS384, jwt.RS512, jwt.PS256, jwt.PS384, jwt.PS512: // Only allow algorithms from RSA family.
 				keySetAlgorithm = string(token.Header().Algorithm)
 			default:
 				return fmt.Errorf("%w: no match in algorithms", errUnsupportedAlgorithm)
 			}
 		}
 
-		verifier, err := jwt.NewVerifierRS(jwt.Algorithm(keySetAlgorithm), pubKey)
+		var verifier jwt.Verifier
+		switch jwt.Algorithm(keySetAlgorithm) {
+		case jwt.PS256, jwt.PS384, jwt.PS512:
+			verifier, err = jwt.NewVerifierPS(jwt.Algorithm(keySetAlgorithm), pubKey)
+		default:
+			verifier, err = jwt.NewVerifierRS(jwt.Algorithm(keySetAlgorithm), pubKey)
+		}
 		if err != nil {
 			return fmt.Errorf("%w: %s", errUnsupportedAlgorithm, keySetAlgorithm)
 		}
@@ -328,9 +416,13 @@ type algorithms struct {
 	ES256 jwt.Verifier
 	ES384 jwt.Verifier
 	ES512 jwt.Verifier
+	PS256 jwt.Verifier
+	PS384 jwt.Verifier
+	PS512 jwt.Verifier
+	EdDSA jwt.Verifier
 }
 
-func newAlgorithms(tokenHMACSecretKey string, rsaPubKey *rsa.PublicKey, ecdsaPubKey *ecdsa.PublicKey) (*algorithms, error) {
+func newAlgorithms(tokenHMACSecretKey string, rsaPubKey *rsa.PublicKey, ecdsaPubKey *ecdsa.PublicKey, ed25519PubKey ed25519.PublicKey) (*algorithms, error) {
 	alg := &algorithms{}
 
 	var algorithms []string
@@ -381,6 +473,30 @@ func newAlgorithms(tokenHMACSecretKey string, rsaPubKey *rsa.PublicKey, ecdsaPub
 			alg.RS512 = verifierRS512
 			algorithms = append(algorithms, "RS512")
 		}
+		if verifierPS256, err := jwt.NewVerifierPS(jwt.PS256, rsaPubKey); err != nil {
+			if !errors.Is(err, jwt.ErrInvalidKey) {
+				return nil, err
+			}
+		} else {
+			alg.PS256 = verifierPS256
+			algorithms = append(algorithms, "PS256")
+		}
+		if verifierPS384, err := jwt.NewVerifierPS(jwt.PS384, rsaPubKey); err != nil {
+			if !errors.Is(err, jwt.ErrInvalidKey) {
+				return nil, err
+			}
+		} else {
+			alg.PS384 = verifierPS384
+			algorithms = append(algorithms, "PS384")
+		}
+		if verifierPS512, err := jwt.NewVerifierPS(jwt.PS512, rsaPubKey); err != nil {
+			if !errors.Is(err, jwt.ErrInvalidKey) {
+				return nil, err
+			}
+		} else {
+			alg.PS512 = verifierPS512
+			algorithms = append(algorithms, "PS512")
+		}
 	}
 
 	// ECDSA.
@@ -411,6 +527,16 @@ func newAlgorithms(tokenHMACSecretKey string, rsaPubKey *rsa.PublicKey, ecdsaPub
 		}
 	}
 
+	// EdDSA.
+	if ed25519PubKey != nil {
+		verifierEdDSA, err := jwt.NewVerifierEdDSA(ed25519PubKey)
+		if err != nil {
+			return nil, err
+		}
+		alg.EdDSA = verifierEdDSA
+		algorithms = append(algorithms, "EdDSA")
+	}
+
 	if len(algorithms) > 0 {
 		log.Info().Str("algorithms", strings.Join(algorithms, ", ")).Msg("enabled JWT verifiers")
 	}
@@ -439,6 +565,14 @@ func (s *algorithms) verify(token *jwt.Token) error {
 		verifier = s.ES384
 	case jwt.ES512:
 		verifier = s.ES512
+	case jwt.PS256:
+		verifier = s.PS256
+	case jwt.PS384:
+		verifier = s.PS384
+	case jwt.PS512:
+		verifier = s.PS512
+	case jwt.EdDSA:
+		verifier = s.EdDSA
 	default:
 		return fmt.Errorf("%w: %s", errUnsupportedAlgorithm, string(token.Header().Algorithm))
 	}
@@ -448,6 +582,12 @@ func (s *algorithms) verify(token *jwt.Token) error {
 	return verifier.Verify(token)
 }
 
+func (verifier *VerifierJWT) usesJWKS() bool {
+	verifier.mu.RLock()
+	defer verifier.mu.RUnlock()
+	return len(verifier.jwksManagers) > 0
+}
+
 func (verifier *VerifierJWT) verifySignature(token *jwt.Token) error {
 	verifier.mu.RLock()
 	defer verifier.mu.RUnlock()
@@ -464,11 +604,26 @@ func (verifier *VerifierJWT) verifySignature(token *jwt.Token) error {
 	return err
 }
 
-func (verifier *VerifierJWT) verifySignatureByJWK(token *jwt.Token, tokenVars map[string]any) error {
+// verifySignatureByJWK tries JWKS endpoints matching token claims in order until
+// one of them returns a key with token kid.
+func (verifier *VerifierJWT) verifySignatureByJWK(token *jwt.Token, claims jwt.RegisteredClaims, tokenVars map[string]any) error {
 	verifier.mu.RLock()
 	defer verifier.mu.RUnlock()
 
-	return verifier.jwksManager.verify(token, tokenVars)
+	kid := token.Header().KeyID
+	err := errNoMatchingJWKS
+	for _, m := range verifier.jwksManagers {
+		if !m.matches(claims) {
+			continue
+		}
+		key, fetchErr := m.FetchKey(context.Background(), kid, tokenVars)
+		if fetchErr != nil {
+			err = fetchErr
+			continue
+		}
+		return VerifyByJWK(token, key)
+	}
+	return err
 }
 
 // extractTokenVars extracts named group matches from issuer/audience regex into tokenVars.
@@ -544,8 +699,8 @@ func (verifier *VerifierJWT) VerifyConnectToken(t string, skipVerify bool) (Conn
 	}
 
 	if !skipVerify {
-		if verifier.jwksManager != nil {
-			err = verifier.verifySignatureByJWK(token, tokenVars)
+		if verifier.usesJWKS() {
+			err = verifier.verifySignatureByJWK(token, claims.RegisteredClaims, tokenVars)
 		} else {
 			err = verifier.verifySignature(token)
 		}
@@ -569,6 +724,11 @@ func (verifier *VerifierJWT) VerifyConnectToken(t string, skipVerify bool) (Conn
 		return ConnectToken{}, ErrTokenExpired
 	}
 
+	caps, err := capability.Compile(claims.Caps)
+	if err != nil {
+		return ConnectToken{}, fmt.Errorf("%w: invalid caps: %v", ErrInvalidToken, err)
+	}
+
 	subs := map[string]centrifuge.SubscribeOptions{}
 
 	if len(claims.Subs) > 0 {
@@ -687,6 +847,7 @@ func (verifier *VerifierJWT) VerifyConnectToken(t string, skipVerify bool) (Conn
 		Subs:     subs,
 		ExpireAt: expireAt,
 		Meta:     claims.Meta,
+		Caps:     caps,
 	}
 	if verifier.userIDClaim != "" {
 		value := gjson.GetBytes(token.Claims(), verifier.userIDClaim)
@@ -716,8 +877,8 @@ func (verifier *VerifierJWT) VerifySubscribeToken(t string, skipVerify bool) (Su
 	}
 
 	if !skipVerify {
-		if verifier.jwksManager != nil {
-			err = verifier.verifySignatureByJWK(token, tokenVars)
+		if verifier.usesJWKS() {
+			err = verifier.verifySignatureByJWK(token, claims.RegisteredClaims, tokenVars)
 		} else {
 			err = verifier.verifySignature(token)
 		}
@@ -800,10 +961,16 @@ func (verifier *VerifierJWT) VerifySubscribeToken(t string, skipVerify bool) (Su
 		}
 	}
 
+	allow, err := capability.ParseOperations(claims.Allow)
+	if err != nil {
+		return SubscribeToken{}, fmt.Errorf("%w: invalid allow: %v", ErrInvalidToken, err)
+	}
+
 	st := SubscribeToken{
 		UserID:  claims.RegisteredClaims.Subject,
 		Channel: claims.Channel,
 		Client:  claims.Client,
+		Allow:   allow,
 		Options: centrifuge.SubscribeOptions{
 			ExpireAt:          expireAt,
 			ChannelInfo:       info,
@@ -852,24 +1019,24 @@ func (verifier *VerifierJWT) Reload(config VerifierConfig) error {
 		}
 	}
 
-	if config.JWKSPublicEndpoint != "" {
-		mng, err := jwks.NewManager(config.JWKSPublicEndpoint)
-		if err != nil {
-			return fmt.Errorf("error creating JWK manager: %w", err)
-		}
-		verifier.jwksManager = &jwksManager{mng}
+	managers, err := newJWKSManagers(config)
+	if err != nil {
+		return err
+	}
+	if len(managers) > 0 {
+		verifier.jwksManagers = managers
 		verifier.algorithms = nil
 		verifier.previousHMACAlgorithms = nil
 		verifier.hmacPreviousSecretKeyValidUntil = 0
 	} else {
-		alg, err := newAlgorithms(config.HMACSecretKey, config.RSAPublicKey, config.ECDSAPublicKey)
+		alg, err := newAlgorithms(config.HMACSecretKey, config.RSAPublicKey, config.ECDSAPublicKey, config.Ed25519PublicKey)
 		if err != nil {
 			return err
 		}
 		verifier.algorithms = alg
-		verifier.jwksManager = nil
+		verifier.jwksManagers = nil
 		if config.HMACPreviousSecretKey != "" {
-			prevAlg, err := newAlgorithms(config.HMACPreviousSecretKey, nil, nil)
+			prevAlg, err := newAlgorithms(config.HMACPreviousSecretKey, nil, nil, nil)
 			if err != nil {
 				return fmt.Errorf("error initializing previous HMAC token algorithms: %w", err)
 			}
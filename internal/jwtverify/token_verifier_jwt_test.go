@@ -2,6 +2,7 @@ package jwtverify
 
 import (
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
@@ -11,17 +12,28 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/centrifugal/centrifugo/v6/internal/config"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
 
 	"github.com/centrifugal/centrifuge"
 	"github.com/cristalhq/jwt/v5"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 )
 
+func TestMain(m *testing.M) {
+	_ = metrics.Init(metrics.Config{
+		Registerer: prometheus.NewRegistry(),
+	})
+	os.Exit(m.Run())
+}
+
 // Use https://jwt.io to look at token contents.
 // noinspection ALL
 const (
@@ -302,7 +314,7 @@ func getJWKServer(pubKey *rsa.PublicKey, kty, use, kid string) *httptest.Server
 func Test_tokenVerifierJWT_Signer(t *testing.T) {
 	_, rsaPubKey := generateTestRSAKeys(t)
 	_, ecdsaPubKey := generateTestECDSAKeys(t)
-	signer, err := newAlgorithms("secret", rsaPubKey, ecdsaPubKey)
+	signer, err := newAlgorithms("secret", rsaPubKey, ecdsaPubKey, nil)
 	require.NoError(t, err)
 	require.NotNil(t, signer)
 }
@@ -953,6 +965,136 @@ func TestJWKS_NoAlg(t *testing.T) {
 	require.ErrorContains(t, err, "invalid token: unsupported JWT algorithm: ES256")
 }
 
+func getSignedConnToken(t *testing.T, signer jwt.Signer, user, issuer, kid string) string {
+	t.Helper()
+	var opts []jwt.BuilderOption
+	if kid != "" {
+		opts = append(opts, jwt.WithKeyID(kid))
+	}
+	builder := jwt.NewBuilder(signer, opts...)
+	token, err := builder.Build(&ConnectTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user,
+			Issuer:    issuer,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	require.NoError(t, err)
+	return token.String()
+}
+
+func Test_tokenVerifierJWT_EdDSA(t *testing.T) {
+	pubKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := jwt.NewSignerEdDSA(privateKey)
+	require.NoError(t, err)
+
+	cfg := config.DefaultConfig()
+	cfgContainer, err := config.NewContainer(cfg)
+	require.NoError(t, err)
+	verifier, err := NewTokenVerifierJWT(VerifierConfig{Ed25519PublicKey: pubKey}, cfgContainer)
+	require.NoError(t, err)
+
+	ct, err := verifier.VerifyConnectToken(getSignedConnToken(t, signer, "2694", "", ""), false)
+	require.NoError(t, err)
+	require.Equal(t, "2694", ct.UserID)
+
+	_, err = verifier.VerifyConnectToken(jwtValid, false)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func Test_tokenVerifierJWT_PS256(t *testing.T) {
+	privateKey, pubKey := generateTestRSAKeys(t)
+	cfg := config.DefaultConfig()
+	cfgContainer, err := config.NewContainer(cfg)
+	require.NoError(t, err)
+	verifier, err := NewTokenVerifierJWT(VerifierConfig{RSAPublicKey: pubKey}, cfgContainer)
+	require.NoError(t, err)
+
+	for _, alg := range []jwt.Algorithm{jwt.PS256, jwt.PS384, jwt.PS512} {
+		signer, err := jwt.NewSignerPS(alg, privateKey)
+		require.NoError(t, err)
+		ct, err := verifier.VerifyConnectToken(getSignedConnToken(t, signer, "2694", "", ""), false)
+		require.NoError(t, err, alg)
+		require.Equal(t, "2694", ct.UserID)
+	}
+}
+
+func TestJWKS_PS256(t *testing.T) {
+	privateKey, pubKey := generateTestRSAKeys(t)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeRSAJWKS(t, w, pubKey, "ps")
+	}))
+	defer ts.Close()
+
+	cfg := config.DefaultConfig()
+	cfgContainer, err := config.NewContainer(cfg)
+	require.NoError(t, err)
+	verifier, err := NewTokenVerifierJWT(VerifierConfig{JWKSPublicEndpoint: ts.URL}, cfgContainer)
+	require.NoError(t, err)
+
+	// Key set announces RS256, so PS256 token must be rejected.
+	signer, err := jwt.NewSignerPS(jwt.PS256, privateKey)
+	require.NoError(t, err)
+	_, err = verifier.VerifyConnectToken(getSignedConnToken(t, signer, "2694", "", "ps"), false)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestJWKS_MultipleEndpoints(t *testing.T) {
+	oldPrivateKey, oldPubKey := generateTestRSAKeys(t)
+	newPrivateKey, newPubKey := generateTestRSAKeys(t)
+
+	var oldRequests, newRequests int32
+	oldServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&oldRequests, 1)
+		writeRSAJWKS(t, w, oldPubKey, "old")
+	}))
+	defer oldServer.Close()
+	newServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&newRequests, 1)
+		writeRSAJWKS(t, w, newPubKey, "new")
+	}))
+	defer newServer.Close()
+
+	cfg := config.DefaultConfig()
+	cfgContainer, err := config.NewContainer(cfg)
+	require.NoError(t, err)
+	verifier, err := NewTokenVerifierJWT(VerifierConfig{
+		JWKSEndpoints: []JWKSEndpoint{
+			{Name: "old_idp", URL: oldServer.URL, Issuer: "old"},
+			{Name: "new_idp", URL: newServer.URL},
+		},
+	}, cfgContainer)
+	require.NoError(t, err)
+
+	oldSigner, err := jwt.NewSignerRS(jwt.RS256, oldPrivateKey)
+	require.NoError(t, err)
+	newSigner, err := jwt.NewSignerRS(jwt.RS256, newPrivateKey)
+	require.NoError(t, err)
+
+	ct, err := verifier.VerifyConnectToken(getSignedConnToken(t, oldSigner, "1", "old", "old"), false)
+	require.NoError(t, err)
+	require.Equal(t, "1", ct.UserID)
+	require.Equal(t, int32(1), atomic.LoadInt32(&oldRequests))
+	require.Equal(t, int32(0), atomic.LoadInt32(&newRequests))
+
+	// Token of new issuer skips endpoint bound to old issuer.
+	ct, err = verifier.VerifyConnectToken(getSignedConnToken(t, newSigner, "2", "new", "new"), false)
+	require.NoError(t, err)
+	require.Equal(t, "2", ct.UserID)
+	require.Equal(t, int32(1), atomic.LoadInt32(&oldRequests))
+	require.Equal(t, int32(1), atomic.LoadInt32(&newRequests))
+
+	// Key with unknown kid in first endpoint is looked up in next one.
+	ct, err = verifier.VerifyConnectToken(getSignedConnToken(t, newSigner, "3", "old", "new"), false)
+	require.NoError(t, err)
+	require.Equal(t, "3", ct.UserID)
+
+	// Key of old issuer can not be used for tokens of another issuer.
+	_, err = verifier.VerifyConnectToken(getSignedConnToken(t, oldSigner, "4", "new", "old"), false)
+	require.ErrorContains(t, err, "jwks: public key not found")
+}
+
 func getHMACConnToken(user string, exp int64, secret string) string {
 	signer, _ := jwt.NewSignerHS(jwt.HS256, []byte(secret))
 	builder := jwt.NewBuilder(signer)
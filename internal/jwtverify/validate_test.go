@@ -386,3 +386,30 @@ func Test_validateJWKSEndpointSafety_IntegrationWithValidate(t *testing.T) {
 	err = cfg.Validate()
 	require.NoError(t, err)
 }
+
+func TestVerifierConfigValidate_JWKSEndpoints(t *testing.T) {
+	cfg := VerifierConfig{
+		JWKSPublicEndpoint: "https://example.com/jwks.json",
+		JWKSEndpoints:      []JWKSEndpoint{{URL: "https://other.com/jwks.json"}},
+	}
+	require.ErrorContains(t, cfg.Validate(), "can not use both jwks_public_endpoint and jwks_endpoints")
+
+	cfg = VerifierConfig{
+		JWKSEndpoints: []JWKSEndpoint{{URL: "https://example.com/jwks.json"}, {Issuer: "other"}},
+	}
+	require.ErrorContains(t, cfg.Validate(), "jwks_endpoints[1]: url is required")
+
+	cfg = VerifierConfig{
+		JWKSEndpoints: []JWKSEndpoint{{URL: "https://{{host}}/jwks.json"}},
+		IssuerRegex:   `^(?P<host>[a-z0-9.-]+)$`,
+	}
+	require.ErrorContains(t, cfg.Validate(), "JWKS endpoint URL template")
+
+	cfg = VerifierConfig{
+		JWKSEndpoints: []JWKSEndpoint{
+			{URL: "https://old.example.com/jwks.json", Issuer: "old"},
+			{URL: "https://new.example.com/jwks.json", Issuer: "new"},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+}
@@ -0,0 +1,5 @@
+package longpoll
+
+import "github.com/centrifugal/centrifugo/v6/internal/configtypes"
+
+type Config = configtypes.LongPoll
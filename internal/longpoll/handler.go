@@ -0,0 +1,178 @@
+package longpoll
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/logging"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/centrifugal/protocol"
+	"github.com/rs/zerolog/log"
+	"github.com/segmentio/encoding/json"
+)
+
+// Handler accepts long polling connect requests. Request body contains client
+// commands (starting with connect command), response contains replies to them
+// together with client ID, secret session token and node ID to use in poll requests.
+type Handler struct {
+	hub *Hub
+}
+
+func NewHandler(hub *Hub) *Handler {
+	return &Handler{hub: hub}
+}
+
+// PollHandler returns messages sent to a long polling client. Poll requests
+// can come to any node, requests for clients of other nodes are forwarded to
+// the node the client is connected to.
+type PollHandler struct {
+	hub *Hub
+}
+
+func NewPollHandler(hub *Hub) *PollHandler {
+	return &PollHandler{hub: hub}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !checkMethod(w, r) {
+		return
+	}
+	body, ok := readBody(w, r, h.hub.config.MaxRequestBodySize)
+	if !ok {
+		return
+	}
+
+	token, err := newSessionToken()
+	if err != nil {
+		log.Error().Err(err).Str("transport", transportName).Msg("error generating session token")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	transport := newPollTransport(r.ProtoMajor, h.hub.config.MaxQueueSize, h.hub.pingPong)
+	// Client outlives connect request, keep request context values only.
+	c, closeFn, err := centrifuge.NewClient(context.WithoutCancel(r.Context()), h.hub.node, transport)
+	if err != nil {
+		log.Error().Err(err).Str("transport", transportName).Msg("error create client")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	h.hub.add(c.ID(), &session{token: token, transport: transport, closeFn: closeFn})
+
+	if logging.Enabled(logging.DebugLevel) {
+		log.Debug().Str("transport", transportName).Str("client", c.ID()).Msg("client connection established")
+	}
+
+	decoder := protocol.GetStreamCommandDecoderLimited(protocol.TypeJSON, bytes.NewReader(body), int64(h.hub.config.MaxRequestBodySize))
+	defer protocol.PutStreamCommandDecoder(protocol.TypeJSON, decoder)
+	for {
+		cmd, cmdSize, err := decoder.Decode()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				log.Info().Err(err).Str("transport", transportName).Str("client", c.ID()).Msg("error decoding command")
+				c.Disconnect(centrifuge.DisconnectBadRequest)
+			}
+			break
+		}
+		if ok := c.HandleCommand(cmd, cmdSize); !ok {
+			break
+		}
+	}
+
+	resp := h.hub.poll(r.Context(), pollRequest{Client: c.ID(), Session: token})
+	resp.Client = c.ID()
+	if !resp.Closed {
+		resp.Session = token
+	}
+	resp.Node = h.hub.node.ID()
+	writeResponse(w, resp)
+}
+
+func (h *PollHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !checkMethod(w, r) {
+		return
+	}
+	body, ok := readBody(w, r, h.hub.config.MaxRequestBodySize)
+	if !ok {
+		return
+	}
+	var req pollRequest
+	if err := json.Unmarshal(body, &req); err != nil || req.Client == "" || req.Session == "" {
+		if logging.Enabled(logging.DebugLevel) {
+			log.Debug().Err(err).Str("transport", transportName).Msg("malformed poll request")
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if req.Node == "" || req.Node == h.hub.node.ID() {
+		writeResponse(w, h.hub.poll(r.Context(), req))
+		return
+	}
+
+	started := time.Now()
+	data, err := h.hub.pollRemote(r.Context(), req)
+	if err != nil {
+		if r.Context().Err() == nil {
+			log.Error().Err(err).Str("transport", transportName).Str("node", req.Node).
+				Str("duration", time.Since(started).String()).Msg("error polling remote node")
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	setResponseHeaders(w)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+func checkMethod(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Access-Control-Max-Age", "300")
+		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+		w.WriteHeader(http.StatusNoContent)
+		return false
+	}
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return false
+	}
+	return true
+}
+
+func readBody(w http.ResponseWriter, r *http.Request, maxBytesSize int) ([]byte, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, int64(maxBytesSize))
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Info().Err(err).Str("transport", transportName).Msg("error reading long poll request body")
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		}
+		return nil, false
+	}
+	return data, true
+}
+
+func setResponseHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "private, no-cache, no-store, must-revalidate, max-age=0")
+	w.Header().Set("Pragma", "no-cache")
+	w.Header().Set("Expire", "0")
+}
+
+func writeResponse(w http.ResponseWriter, resp pollResponse) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		log.Error().Err(err).Str("transport", transportName).Msg("error encoding poll response")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	setResponseHeaders(w)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,154 @@
+package longpoll
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/survey"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/centrifugal/protocol"
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHub(t *testing.T) *Hub {
+	node, err := centrifuge.New(centrifuge.Config{})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = node.Shutdown(context.Background()) })
+
+	node.OnConnecting(func(ctx context.Context, event centrifuge.ConnectEvent) (centrifuge.ConnectReply, error) {
+		return centrifuge.ConnectReply{
+			Credentials: &centrifuge.Credentials{},
+		}, nil
+	})
+
+	return NewHub(node, survey.NewCaller(node), configtypes.LongPoll{
+		MaxRequestBodySize: 65536,
+		PollTimeout:        configtypes.Duration(200 * time.Millisecond),
+		SessionTimeout:     configtypes.Duration(time.Second),
+		BatchMaxSize:       100,
+		MaxQueueSize:       100,
+	}, centrifuge.PingPongConfig{
+		PingInterval: 5 * time.Second,
+		PongTimeout:  time.Second,
+	})
+}
+
+func postJSON(t *testing.T, url string, body string) (int, pollResponse) {
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	var pollResp pollResponse
+	if resp.StatusCode == http.StatusOK {
+		require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&pollResp))
+	}
+	return resp.StatusCode, pollResp
+}
+
+func TestLongPoll(t *testing.T) {
+	t.Parallel()
+	hub := newTestHub(t)
+
+	mux := http.NewServeMux()
+	mux.Handle("/connection/long_poll", NewHandler(hub))
+	mux.Handle("/connection/long_poll/poll", NewPollHandler(hub))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	code, resp := postJSON(t, server.URL+"/connection/long_poll", `{"id":1,"connect":{}}`)
+	require.Equal(t, http.StatusOK, code)
+	require.NotEmpty(t, resp.Client)
+	require.NotEmpty(t, resp.Session)
+	require.Equal(t, hub.node.ID(), resp.Node)
+	require.Len(t, resp.Messages, 1)
+	var reply protocol.Reply
+	require.NoError(t, json.Unmarshal(resp.Messages[0], &reply))
+	require.Equal(t, resp.Client, reply.Connect.Client)
+
+	t.Run("poll timeout", func(t *testing.T) {
+		code, pollResp := postJSON(t, server.URL+"/connection/long_poll/poll",
+			`{"client":"`+resp.Client+`","session":"`+resp.Session+`","node":"`+resp.Node+`","ack":1}`)
+		require.Equal(t, http.StatusOK, code)
+		require.Empty(t, pollResp.Messages)
+		require.Equal(t, uint64(1), pollResp.Offset)
+		require.False(t, pollResp.Closed)
+	})
+
+	t.Run("poll receives server message", func(t *testing.T) {
+		s, ok := hub.get(resp.Client)
+		require.True(t, ok)
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			_ = s.transport.Write([]byte(`{"push":{}}`))
+		}()
+		code, pollResp := postJSON(t, server.URL+"/connection/long_poll/poll",
+			`{"client":"`+resp.Client+`","session":"`+resp.Session+`","ack":1}`)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, pollResp.Messages, 1)
+		require.Equal(t, uint64(2), pollResp.Offset)
+	})
+
+	t.Run("unknown client", func(t *testing.T) {
+		code, pollResp := postJSON(t, server.URL+"/connection/long_poll/poll", `{"client":"unknown","session":"unknown"}`)
+		require.Equal(t, http.StatusOK, code)
+		require.True(t, pollResp.Closed)
+	})
+
+	t.Run("wrong session", func(t *testing.T) {
+		code, pollResp := postJSON(t, server.URL+"/connection/long_poll/poll",
+			`{"client":"`+resp.Client+`","session":"wrong","ack":1}`)
+		require.Equal(t, http.StatusOK, code)
+		require.True(t, pollResp.Closed)
+		require.Empty(t, pollResp.Messages)
+		_, ok := hub.get(resp.Client)
+		require.True(t, ok)
+	})
+
+	t.Run("missing session", func(t *testing.T) {
+		code, _ := postJSON(t, server.URL+"/connection/long_poll/poll", `{"client":"`+resp.Client+`"}`)
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("malformed poll request", func(t *testing.T) {
+		code, _ := postJSON(t, server.URL+"/connection/long_poll/poll", `{}`)
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("GET method not allowed", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/connection/long_poll")
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+
+	t.Run("idle client closed", func(t *testing.T) {
+		hub.closeIdle(time.Now().Add(time.Minute), time.Second)
+		_, ok := hub.get(resp.Client)
+		require.False(t, ok)
+	})
+}
+
+func TestLongPoll_ConnectRejected(t *testing.T) {
+	t.Parallel()
+	hub := newTestHub(t)
+	hub.node.OnConnecting(func(ctx context.Context, event centrifuge.ConnectEvent) (centrifuge.ConnectReply, error) {
+		return centrifuge.ConnectReply{}, centrifuge.DisconnectInvalidToken
+	})
+	server := httptest.NewServer(NewHandler(hub))
+	t.Cleanup(server.Close)
+
+	code, resp := postJSON(t, server.URL, `{"id":1,"connect":{}}`)
+	require.Equal(t, http.StatusOK, code)
+	require.True(t, resp.Closed)
+	require.NotNil(t, resp.Disconnect)
+	require.Equal(t, centrifuge.DisconnectInvalidToken.Code, resp.Disconnect.Code)
+	_, ok := hub.get(resp.Client)
+	require.False(t, ok)
+}
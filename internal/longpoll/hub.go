@@ -0,0 +1,208 @@
+package longpoll
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/survey"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/rs/zerolog/log"
+	"github.com/segmentio/encoding/json"
+)
+
+// SurveyOp is a survey operation used to serve poll requests of clients
+// connected to another node.
+const SurveyOp = "long_poll"
+
+// surveyExtraTimeout is added to poll timeout when polling remote node to
+// give it time to respond after its own poll timeout.
+const surveyExtraTimeout = 5 * time.Second
+
+type session struct {
+	// token is a secret issued to the client on connect. Client ID is visible to
+	// other clients (for example, in presence), so poll requests are authorized
+	// with the token.
+	token     string
+	transport *pollTransport
+	closeFn   func() error
+}
+
+func newSessionToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hub keeps long polling sessions of clients connected to this node.
+type Hub struct {
+	node         *centrifuge.Node
+	surveyCaller *survey.Caller
+	config       Config
+	pingPong     centrifuge.PingPongConfig
+
+	mu       sync.RWMutex
+	sessions map[string]*session
+}
+
+// NewHub creates Hub and registers survey handler on surveyCaller to serve poll
+// requests forwarded from other nodes, so it must be called before node is run.
+// Hub must be run to close sessions of clients which stopped polling.
+func NewHub(node *centrifuge.Node, surveyCaller *survey.Caller, config Config, pingPong centrifuge.PingPongConfig) *Hub {
+	h := &Hub{
+		node:         node,
+		surveyCaller: surveyCaller,
+		config:       config,
+		pingPong:     pingPong,
+		sessions:     make(map[string]*session),
+	}
+	surveyCaller.RegisterBlockingHandler(SurveyOp, h.handleSurvey)
+	return h
+}
+
+func (h *Hub) add(clientID string, s *session) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sessions[clientID] = s
+}
+
+func (h *Hub) get(clientID string) (*session, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	s, ok := h.sessions[clientID]
+	return s, ok
+}
+
+func (h *Hub) remove(clientID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.sessions, clientID)
+}
+
+// Run closes clients which did not poll within session timeout until
+// context is done.
+func (h *Hub) Run(ctx context.Context) error {
+	sessionTimeout := h.config.SessionTimeout.ToDuration()
+	interval := max(sessionTimeout/4, time.Second)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			h.closeIdle(now, sessionTimeout)
+		}
+	}
+}
+
+func (h *Hub) closeIdle(now time.Time, sessionTimeout time.Duration) {
+	var idle []*session
+	h.mu.Lock()
+	for clientID, s := range h.sessions {
+		if s.transport.idle(now, sessionTimeout) {
+			delete(h.sessions, clientID)
+			idle = append(idle, s)
+		}
+	}
+	h.mu.Unlock()
+	for _, s := range idle {
+		if err := s.closeFn(); err != nil {
+			log.Error().Err(err).Str("transport", transportName).Msg("error closing idle long poll client")
+		}
+	}
+}
+
+// pollRequest is sent by client to receive messages.
+type pollRequest struct {
+	// Client is an ID of client received in connect response.
+	Client string `json:"client"`
+	// Session is a secret token received in connect response.
+	Session string `json:"session"`
+	// Node is an ID of node the client is connected to.
+	Node string `json:"node,omitempty"`
+	// Ack is an offset of the next message client expects, all messages before
+	// it are considered delivered.
+	Ack uint64 `json:"ack,omitempty"`
+}
+
+type disconnectJSON struct {
+	Code   uint32 `json:"code"`
+	Reason string `json:"reason"`
+}
+
+type pollResponse struct {
+	Client     string            `json:"client,omitempty"`
+	Session    string            `json:"session,omitempty"`
+	Node       string            `json:"node,omitempty"`
+	Offset     uint64            `json:"offset"`
+	Messages   []json.RawMessage `json:"messages"`
+	Closed     bool              `json:"closed,omitempty"`
+	Disconnect *disconnectJSON   `json:"disconnect,omitempty"`
+}
+
+// poll serves poll request of client connected to this node.
+func (h *Hub) poll(ctx context.Context, req pollRequest) pollResponse {
+	s, ok := h.get(req.Client)
+	if !ok || subtle.ConstantTimeCompare([]byte(s.token), []byte(req.Session)) != 1 {
+		// Requests with wrong token get the same response as for unknown client
+		// to not reveal connected clients.
+		return pollResponse{Messages: []json.RawMessage{}, Closed: true}
+	}
+	result := s.transport.poll(
+		ctx, req.Ack, h.config.PollTimeout.ToDuration(), h.config.BatchMaxDelay.ToDuration(), h.config.BatchMaxSize)
+	resp := pollResponse{
+		Offset:   result.offset,
+		Messages: make([]json.RawMessage, 0, len(result.messages)),
+		Closed:   result.closed,
+	}
+	for _, message := range result.messages {
+		resp.Messages = append(resp.Messages, message)
+	}
+	if result.closed {
+		// Client got all messages, nothing to keep session for.
+		h.remove(req.Client)
+		resp.Disconnect = &disconnectJSON{Code: result.disconnect.Code, Reason: result.disconnect.Reason}
+	}
+	return resp
+}
+
+// pollRemote serves poll request of client connected to another node, poll request
+// is forwarded to that node with survey.
+func (h *Hub) pollRemote(ctx context.Context, req pollRequest) ([]byte, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	timeout := h.config.PollTimeout.ToDuration() + h.config.BatchMaxDelay.ToDuration() + surveyExtraTimeout
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	respData, err := h.surveyCaller.NodeSurvey(ctx, SurveyOp, data, req.Node)
+	if errors.Is(err, survey.ErrNodeNotFound) {
+		// Node left the cluster, so the client is gone too.
+		return json.Marshal(pollResponse{Messages: []json.RawMessage{}, Closed: true})
+	}
+	return respData, err
+}
+
+// handleSurvey answers poll requests forwarded by other nodes. It blocks up to
+// poll timeout so it is registered as blocking survey handler.
+func (h *Hub) handleSurvey(_ *centrifuge.Node, data []byte) centrifuge.SurveyReply {
+	var req pollRequest
+	if err := json.Unmarshal(data, &req); err != nil || req.Client == "" || req.Session == "" {
+		return centrifuge.SurveyReply{Code: survey.InvalidRequest}
+	}
+	resp := h.poll(context.Background(), req)
+	respData, err := json.Marshal(resp)
+	if err != nil {
+		return centrifuge.SurveyReply{Code: survey.InternalError}
+	}
+	return centrifuge.SurveyReply{Data: respData}
+}
@@ -0,0 +1,216 @@
+package longpoll
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/tools"
+
+	"github.com/centrifugal/centrifuge"
+)
+
+const transportName = "long_poll"
+
+var errQueueFull = errors.New("long poll message queue is full")
+
+// pollTransport keeps messages for a client between poll requests. Every message
+// gets a sequence number, client acknowledges received messages by sending offset
+// of the next message it expects. Unacknowledged messages are returned again, so
+// a poll response lost on the way does not lose messages.
+type pollTransport struct {
+	mu             sync.Mutex
+	messages       [][]byte
+	offset         uint64
+	notify         chan struct{}
+	closed         bool
+	disconnect     centrifuge.Disconnect
+	lastPoll       time.Time
+	polling        int
+	maxQueueSize   int
+	pingPongConfig centrifuge.PingPongConfig
+	protoMajor     int
+}
+
+func newPollTransport(protoMajor int, maxQueueSize int, pingPongConfig centrifuge.PingPongConfig) *pollTransport {
+	return &pollTransport{
+		notify:         make(chan struct{}),
+		lastPoll:       time.Now(),
+		maxQueueSize:   maxQueueSize,
+		pingPongConfig: pingPongConfig,
+		protoMajor:     protoMajor,
+	}
+}
+
+func (t *pollTransport) Name() string {
+	return transportName
+}
+
+func (t *pollTransport) Protocol() centrifuge.ProtocolType {
+	return centrifuge.ProtocolTypeJSON
+}
+
+// ProtocolVersion returns transport protocol version.
+func (t *pollTransport) ProtocolVersion() centrifuge.ProtocolVersion {
+	return centrifuge.ProtocolVersion2
+}
+
+// Unidirectional returns whether transport is unidirectional.
+func (t *pollTransport) Unidirectional() bool {
+	return false
+}
+
+// DisabledPushFlags ...
+func (t *pollTransport) DisabledPushFlags() uint64 {
+	return 0
+}
+
+// PingPongConfig ...
+func (t *pollTransport) PingPongConfig() centrifuge.PingPongConfig {
+	return t.pingPongConfig
+}
+
+// Emulation returns true so that client-to-server commands are accepted by
+// the emulation endpoint, which routes them to the node owning the client.
+func (t *pollTransport) Emulation() bool {
+	return true
+}
+
+// AcceptProtocol ...
+func (t *pollTransport) AcceptProtocol() string {
+	return tools.GetAcceptProtocolLabel(t.protoMajor)
+}
+
+func (t *pollTransport) Write(message []byte) error {
+	return t.WriteMany(message)
+}
+
+func (t *pollTransport) WriteMany(messages ...[]byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil
+	}
+	if t.maxQueueSize > 0 && len(t.messages)+len(messages) > t.maxQueueSize {
+		return errQueueFull
+	}
+	for _, message := range messages {
+		// Messages are kept until acknowledged, so they are copied to not
+		// depend on the lifetime of caller's buffers.
+		t.messages = append(t.messages, append([]byte(nil), message...))
+	}
+	t.signalLocked()
+	return nil
+}
+
+func (t *pollTransport) Close(disconnect centrifuge.Disconnect) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	t.disconnect = disconnect
+	t.signalLocked()
+	return nil
+}
+
+func (t *pollTransport) signalLocked() {
+	close(t.notify)
+	t.notify = make(chan struct{})
+}
+
+// pollResult is a batch of messages returned to a poll request.
+type pollResult struct {
+	messages   [][]byte
+	offset     uint64
+	closed     bool
+	disconnect centrifuge.Disconnect
+}
+
+// poll drops messages acknowledged by the client and waits for new messages up to
+// timeout. After the first message is available it waits up to batchDelay for more
+// messages to return them in one response.
+func (t *pollTransport) poll(ctx context.Context, ack uint64, timeout time.Duration, batchDelay time.Duration, batchSize int) pollResult {
+	t.mu.Lock()
+	t.ackLocked(ack)
+	t.lastPoll = time.Now()
+	t.polling++
+	t.mu.Unlock()
+
+	defer func() {
+		t.mu.Lock()
+		t.lastPoll = time.Now()
+		t.polling--
+		t.mu.Unlock()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	if t.wait(ctx, timer.C, func() bool { return len(t.messages) > 0 }) && batchDelay > 0 {
+		batchTimer := time.NewTimer(batchDelay)
+		defer batchTimer.Stop()
+		t.wait(ctx, batchTimer.C, func() bool { return batchSize > 0 && len(t.messages) >= batchSize })
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n := len(t.messages)
+	if batchSize > 0 && n > batchSize {
+		n = batchSize
+	}
+	return pollResult{
+		messages:   append([][]byte(nil), t.messages[:n]...),
+		offset:     t.offset + uint64(n),
+		closed:     t.closed && n == len(t.messages),
+		disconnect: t.disconnect,
+	}
+}
+
+// wait blocks until ready returns true, transport is closed, deadline fires or
+// context is done. Returns true if ready condition was met.
+func (t *pollTransport) wait(ctx context.Context, deadline <-chan time.Time, ready func() bool) bool {
+	for {
+		t.mu.Lock()
+		if ready() {
+			t.mu.Unlock()
+			return true
+		}
+		if t.closed {
+			t.mu.Unlock()
+			return false
+		}
+		notify := t.notify
+		t.mu.Unlock()
+		select {
+		case <-notify:
+		case <-deadline:
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+func (t *pollTransport) ackLocked(ack uint64) {
+	if ack <= t.offset {
+		return
+	}
+	n := ack - t.offset
+	if n > uint64(len(t.messages)) {
+		n = uint64(len(t.messages))
+	}
+	// Do not keep references to acknowledged messages in the underlying array.
+	clear(t.messages[:n])
+	t.messages = t.messages[n:]
+	t.offset += n
+}
+
+// idle returns true if there is no active poll request and no poll request
+// started within timeout.
+func (t *pollTransport) idle(now time.Time, timeout time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.polling == 0 && now.Sub(t.lastPoll) > timeout
+}
@@ -0,0 +1,100 @@
+package longpoll
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTransport(maxQueueSize int) *pollTransport {
+	return newPollTransport(1, maxQueueSize, centrifuge.PingPongConfig{})
+}
+
+func TestPollTransport_PollAck(t *testing.T) {
+	transport := newTestTransport(0)
+	require.NoError(t, transport.WriteMany([]byte(`{"a":1}`), []byte(`{"b":2}`)))
+
+	result := transport.poll(context.Background(), 0, time.Second, 0, 0)
+	require.Len(t, result.messages, 2)
+	require.Equal(t, uint64(2), result.offset)
+	require.False(t, result.closed)
+
+	// Not acknowledged messages are returned again.
+	result = transport.poll(context.Background(), 1, time.Second, 0, 0)
+	require.Equal(t, [][]byte{[]byte(`{"b":2}`)}, result.messages)
+	require.Equal(t, uint64(2), result.offset)
+
+	result = transport.poll(context.Background(), 2, 10*time.Millisecond, 0, 0)
+	require.Empty(t, result.messages)
+	require.Equal(t, uint64(2), result.offset)
+}
+
+func TestPollTransport_WaitsForMessages(t *testing.T) {
+	transport := newTestTransport(0)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = transport.Write([]byte(`{}`))
+	}()
+	started := time.Now()
+	result := transport.poll(context.Background(), 0, 5*time.Second, 0, 0)
+	require.Len(t, result.messages, 1)
+	require.Less(t, time.Since(started), 5*time.Second)
+}
+
+func TestPollTransport_Batching(t *testing.T) {
+	transport := newTestTransport(0)
+	go func() {
+		_ = transport.Write([]byte(`{"a":1}`))
+		time.Sleep(20 * time.Millisecond)
+		_ = transport.Write([]byte(`{"b":2}`))
+	}()
+	result := transport.poll(context.Background(), 0, 5*time.Second, time.Second, 2)
+	require.Len(t, result.messages, 2)
+
+	require.NoError(t, transport.WriteMany([]byte(`{"c":3}`), []byte(`{"d":4}`)))
+	result = transport.poll(context.Background(), result.offset, time.Second, 0, 1)
+	require.Equal(t, [][]byte{[]byte(`{"c":3}`)}, result.messages)
+	require.Equal(t, uint64(3), result.offset)
+}
+
+func TestPollTransport_QueueFull(t *testing.T) {
+	transport := newTestTransport(2)
+	require.NoError(t, transport.WriteMany([]byte(`{}`), []byte(`{}`)))
+	require.ErrorIs(t, transport.Write([]byte(`{}`)), errQueueFull)
+	result := transport.poll(context.Background(), 0, time.Second, 0, 0)
+	transport.poll(context.Background(), result.offset, time.Millisecond, 0, 0)
+	require.NoError(t, transport.Write([]byte(`{}`)))
+}
+
+func TestPollTransport_Close(t *testing.T) {
+	transport := newTestTransport(0)
+	require.NoError(t, transport.Write([]byte(`{}`)))
+	require.NoError(t, transport.Close(centrifuge.DisconnectForceNoReconnect))
+
+	// Closed is only reported after all messages are returned.
+	result := transport.poll(context.Background(), 0, time.Second, 0, 1)
+	require.Len(t, result.messages, 1)
+	require.True(t, result.closed)
+	require.Equal(t, centrifuge.DisconnectForceNoReconnect.Code, result.disconnect.Code)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		result := transport.poll(context.Background(), 1, 5*time.Second, 0, 0)
+		require.True(t, result.closed)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		require.Fail(t, "poll on closed transport must not wait")
+	}
+}
+
+func TestPollTransport_Idle(t *testing.T) {
+	transport := newTestTransport(0)
+	require.False(t, transport.idle(time.Now(), time.Second))
+	require.True(t, transport.idle(time.Now().Add(2*time.Second), time.Second))
+}
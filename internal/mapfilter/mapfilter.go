@@ -0,0 +1,415 @@
+// Package mapfilter implements filter and sort expressions over JSON values of
+// map channel state. Expressions may only reference fields which have a secondary
+// index configured for the namespace, e.g.:
+//
+//	filter: status = "open" AND total >= 100
+//	sort:   updated_at desc
+package mapfilter
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// Index value types.
+const (
+	TypeString = "string"
+	TypeNumber = "number"
+)
+
+// Index describes a secondary index on a JSON field of map values.
+type Index struct {
+	// Name used to reference the index in expressions.
+	Name string
+	// Field is a dot-separated path to a value inside JSON.
+	Field string
+	// Type is TypeString (when empty) or TypeNumber.
+	Type string
+}
+
+// IsNumber reports whether index values are compared as numbers.
+func (i Index) IsNumber() bool {
+	return i.Type == TypeNumber
+}
+
+// Path returns Field split into path segments.
+func (i Index) Path() []string {
+	return strings.Split(i.Field, ".")
+}
+
+var (
+	indexNameRe    = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+	fieldSegmentRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// ValidateIndexes checks index definitions.
+func ValidateIndexes(indexes []Index) error {
+	seen := make(map[string]struct{}, len(indexes))
+	for _, idx := range indexes {
+		if !indexNameRe.MatchString(idx.Name) {
+			return fmt.Errorf("invalid index name %q: must match %s", idx.Name, indexNameRe.String())
+		}
+		if _, ok := seen[idx.Name]; ok {
+			return fmt.Errorf("duplicate index name %q", idx.Name)
+		}
+		seen[idx.Name] = struct{}{}
+		if idx.Field == "" {
+			return fmt.Errorf("field required for index %q", idx.Name)
+		}
+		for _, segment := range idx.Path() {
+			if !fieldSegmentRe.MatchString(segment) {
+				return fmt.Errorf("invalid field %q for index %q: path segments must match %s", idx.Field, idx.Name, fieldSegmentRe.String())
+			}
+		}
+		if idx.Type != "" && idx.Type != TypeString && idx.Type != TypeNumber {
+			return fmt.Errorf("unknown type %q for index %q", idx.Type, idx.Name)
+		}
+	}
+	return nil
+}
+
+// Op is a comparison operator.
+type Op string
+
+// Supported comparison operators.
+const (
+	OpEq  Op = "="
+	OpNe  Op = "!="
+	OpLt  Op = "<"
+	OpLte Op = "<="
+	OpGt  Op = ">"
+	OpGte Op = ">="
+)
+
+// Condition compares indexed field value with a constant.
+type Condition struct {
+	Index  Index
+	Op     Op
+	Value  string
+	Number float64
+}
+
+// Sort defines ordering by indexed field value.
+type Sort struct {
+	Index Index
+	Desc  bool
+}
+
+// Query is a parsed filter and sort expression. Conditions are combined with AND.
+// Entries which do not have a value for a referenced field never match a condition.
+// When Sort is set, entries without a value for the sort field are skipped.
+type Query struct {
+	Conditions []Condition
+	Sort       *Sort
+}
+
+// IsEmpty reports whether Query has neither conditions nor sort.
+func (q Query) IsEmpty() bool {
+	return len(q.Conditions) == 0 && q.Sort == nil
+}
+
+var (
+	// ErrInvalidExpression returned when filter or sort expression can not be parsed.
+	ErrInvalidExpression = errors.New("invalid expression")
+	// ErrNotSupported may be returned by map brokers which can not evaluate Query natively.
+	ErrNotSupported = errors.New("filtered state read not supported")
+)
+
+func findIndex(indexes []Index, name string) (Index, bool) {
+	for _, idx := range indexes {
+		if idx.Name == name {
+			return idx, true
+		}
+	}
+	return Index{}, false
+}
+
+// Parse parses filter and sort expressions. Both can be empty.
+func Parse(filter string, sort string, indexes []Index) (Query, error) {
+	var q Query
+	if strings.TrimSpace(filter) != "" {
+		tokens, err := tokenize(filter)
+		if err != nil {
+			return Query{}, err
+		}
+		for len(tokens) > 0 {
+			if len(tokens) < 3 {
+				return Query{}, fmt.Errorf("%w: incomplete condition in filter", ErrInvalidExpression)
+			}
+			idx, ok := findIndex(indexes, tokens[0].text)
+			if !ok || tokens[0].quoted {
+				return Query{}, fmt.Errorf("%w: unknown index %q", ErrInvalidExpression, tokens[0].text)
+			}
+			op := Op(tokens[1].text)
+			switch op {
+			case OpEq, OpNe, OpLt, OpLte, OpGt, OpGte:
+			default:
+				return Query{}, fmt.Errorf("%w: unknown operator %q", ErrInvalidExpression, tokens[1].text)
+			}
+			cond := Condition{Index: idx, Op: op, Value: tokens[2].text}
+			if idx.IsNumber() {
+				num, err := strconv.ParseFloat(cond.Value, 64)
+				if err != nil || tokens[2].quoted {
+					return Query{}, fmt.Errorf("%w: number expected for index %q", ErrInvalidExpression, idx.Name)
+				}
+				cond.Number = num
+			}
+			q.Conditions = append(q.Conditions, cond)
+			tokens = tokens[3:]
+			if len(tokens) > 0 {
+				if tokens[0].quoted || !strings.EqualFold(tokens[0].text, "and") {
+					return Query{}, fmt.Errorf("%w: AND expected between conditions", ErrInvalidExpression)
+				}
+				tokens = tokens[1:]
+				if len(tokens) == 0 {
+					return Query{}, fmt.Errorf("%w: condition expected after AND", ErrInvalidExpression)
+				}
+			}
+		}
+	}
+	if fields := strings.Fields(sort); len(fields) > 0 {
+		if len(fields) > 2 {
+			return Query{}, fmt.Errorf("%w: sort must be in form \"<index> [asc|desc]\"", ErrInvalidExpression)
+		}
+		idx, ok := findIndex(indexes, fields[0])
+		if !ok {
+			return Query{}, fmt.Errorf("%w: unknown index %q", ErrInvalidExpression, fields[0])
+		}
+		s := &Sort{Index: idx}
+		if len(fields) == 2 {
+			switch strings.ToLower(fields[1]) {
+			case "asc":
+			case "desc":
+				s.Desc = true
+			default:
+				return Query{}, fmt.Errorf("%w: unknown sort direction %q", ErrInvalidExpression, fields[1])
+			}
+		}
+		q.Sort = s
+	}
+	return q, nil
+}
+
+type token struct {
+	text   string
+	quoted bool
+}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '"':
+			// JSON string literal, supports escapes.
+			j := i + 1
+			for j < len(s) && s[j] != '"' {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("%w: unterminated string", ErrInvalidExpression)
+			}
+			var str string
+			if err := json.Unmarshal([]byte(s[i:j+1]), &str); err != nil {
+				return nil, fmt.Errorf("%w: bad string literal", ErrInvalidExpression)
+			}
+			tokens = append(tokens, token{text: str, quoted: true})
+			i = j + 1
+		case c == '=' || c == '!' || c == '<' || c == '>':
+			j := i + 1
+			if j < len(s) && s[j] == '=' {
+				j++
+			}
+			tokens = append(tokens, token{text: s[i:j]})
+			i = j
+		default:
+			j := i
+			for j < len(s) && !strings.ContainsRune(" \t\n\"=!<>", rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, token{text: s[i:j]})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+// Value extracts indexed field value from JSON data. For number indexes only JSON
+// numbers are considered. Returns false if data has no value for the field.
+func Value(data []byte, idx Index) (string, float64, bool) {
+	res := gjson.GetBytes(data, idx.Field)
+	if !res.Exists() || res.Type == gjson.Null {
+		return "", 0, false
+	}
+	if idx.IsNumber() {
+		if res.Type != gjson.Number {
+			return "", 0, false
+		}
+		return strconv.FormatFloat(res.Num, 'f', -1, 64), res.Num, true
+	}
+	return res.String(), 0, true
+}
+
+// Tags returns publication tags extended with values of indexed fields found in
+// JSON data, keyed by index name. Index values override tags with the same key.
+// Tags are returned as is when data has no indexed values.
+func Tags(indexes []Index, data []byte, tags map[string]string) map[string]string {
+	var result map[string]string
+	for _, idx := range indexes {
+		value, _, ok := Value(data, idx)
+		if !ok {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]string, len(tags)+len(indexes))
+			for k, v := range tags {
+				result[k] = v
+			}
+		}
+		result[idx.Name] = value
+	}
+	if result == nil {
+		return tags
+	}
+	return result
+}
+
+func compareValues(idx Index, aStr string, aNum float64, bStr string, bNum float64) int {
+	if idx.IsNumber() {
+		switch {
+		case aNum < bNum:
+			return -1
+		case aNum > bNum:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(aStr, bStr)
+}
+
+// Match reports whether JSON data satisfies all query conditions and has a value
+// for the sort field (if sort is set).
+func (q Query) Match(data []byte) bool {
+	for _, c := range q.Conditions {
+		str, num, ok := Value(data, c.Index)
+		if !ok {
+			return false
+		}
+		cmp := compareValues(c.Index, str, num, c.Value, c.Number)
+		var matched bool
+		switch c.Op {
+		case OpEq:
+			matched = cmp == 0
+		case OpNe:
+			matched = cmp != 0
+		case OpLt:
+			matched = cmp < 0
+		case OpLte:
+			matched = cmp <= 0
+		case OpGt:
+			matched = cmp > 0
+		case OpGte:
+			matched = cmp >= 0
+		}
+		if !matched {
+			return false
+		}
+	}
+	if q.Sort != nil {
+		if _, _, ok := Value(data, q.Sort.Index); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Compare compares two entries according to query sort order, using keys as a
+// tiebreaker. Without sort entries are ordered by key.
+func (q Query) Compare(aKey string, aData []byte, bKey string, bData []byte) int {
+	if q.Sort == nil {
+		return strings.Compare(aKey, bKey)
+	}
+	aStr, aNum, _ := Value(aData, q.Sort.Index)
+	bStr, bNum, _ := Value(bData, q.Sort.Index)
+	cmp := compareValues(q.Sort.Index, aStr, aNum, bStr, bNum)
+	if cmp == 0 {
+		cmp = strings.Compare(aKey, bKey)
+	}
+	if q.Sort.Desc {
+		return -cmp
+	}
+	return cmp
+}
+
+// Position is a decoded pagination cursor.
+type Position struct {
+	// Value of the sort field for the last returned entry. Empty without sort.
+	Value string
+	// Number is Value parsed for number sort index.
+	Number float64
+	// Key of the last returned entry.
+	Key string
+}
+
+// MakeCursor builds a pagination cursor pointing to the entry. Without sort the cursor
+// is the entry key – same as for unfiltered map state reads.
+func (q Query) MakeCursor(key string, data []byte) string {
+	if q.Sort == nil {
+		return key
+	}
+	value, _, _ := Value(data, q.Sort.Index)
+	b, _ := json.Marshal([2]string{value, key})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// ParseCursor decodes cursor made by MakeCursor.
+func (q Query) ParseCursor(cursor string) (Position, error) {
+	if q.Sort == nil {
+		return Position{Key: cursor}, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return Position{}, fmt.Errorf("%w: malformed cursor", ErrInvalidExpression)
+	}
+	var parts [2]string
+	if err := json.Unmarshal(b, &parts); err != nil {
+		return Position{}, fmt.Errorf("%w: malformed cursor", ErrInvalidExpression)
+	}
+	pos := Position{Value: parts[0], Key: parts[1]}
+	if q.Sort.Index.IsNumber() {
+		pos.Number, err = strconv.ParseFloat(pos.Value, 64)
+		if err != nil {
+			return Position{}, fmt.Errorf("%w: malformed cursor", ErrInvalidExpression)
+		}
+	}
+	return pos, nil
+}
+
+// After reports whether entry goes after cursor position in query order.
+func (q Query) After(key string, data []byte, pos Position) bool {
+	if q.Sort == nil {
+		return key > pos.Key
+	}
+	str, num, _ := Value(data, q.Sort.Index)
+	cmp := compareValues(q.Sort.Index, str, num, pos.Value, pos.Number)
+	if cmp == 0 {
+		cmp = strings.Compare(key, pos.Key)
+	}
+	if q.Sort.Desc {
+		return cmp < 0
+	}
+	return cmp > 0
+}
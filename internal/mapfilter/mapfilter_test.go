@@ -0,0 +1,139 @@
+package mapfilter
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testIndexes = []Index{
+	{Name: "status", Field: "status"},
+	{Name: "total", Field: "total", Type: TypeNumber},
+	{Name: "updated_at", Field: "meta.updated_at", Type: TypeNumber},
+}
+
+func TestValidateIndexes(t *testing.T) {
+	require.NoError(t, ValidateIndexes(testIndexes))
+	require.Error(t, ValidateIndexes([]Index{{Name: "Status", Field: "status"}}))
+	require.Error(t, ValidateIndexes([]Index{{Name: "status", Field: ""}}))
+	require.Error(t, ValidateIndexes([]Index{{Name: "status", Field: "a.*"}}))
+	require.Error(t, ValidateIndexes([]Index{{Name: "status", Field: "status", Type: "bool"}}))
+	require.Error(t, ValidateIndexes([]Index{{Name: "status", Field: "a"}, {Name: "status", Field: "b"}}))
+}
+
+func TestParse(t *testing.T) {
+	q, err := Parse(`status = "open" and total>=100`, "updated_at desc", testIndexes)
+	require.NoError(t, err)
+	require.Len(t, q.Conditions, 2)
+	require.Equal(t, OpEq, q.Conditions[0].Op)
+	require.Equal(t, "open", q.Conditions[0].Value)
+	require.Equal(t, OpGte, q.Conditions[1].Op)
+	require.Equal(t, float64(100), q.Conditions[1].Number)
+	require.NotNil(t, q.Sort)
+	require.True(t, q.Sort.Desc)
+	require.Equal(t, "updated_at", q.Sort.Index.Name)
+
+	q, err = Parse("", "", testIndexes)
+	require.NoError(t, err)
+	require.True(t, q.IsEmpty())
+
+	q, err = Parse(`status = "say \"hi\""`, "", testIndexes)
+	require.NoError(t, err)
+	require.Equal(t, `say "hi"`, q.Conditions[0].Value)
+
+	for _, filter := range []string{
+		`unknown = 1`,
+		`status ~ open`,
+		`status =`,
+		`total = "100"`,
+		`total = abc`,
+		`status = open total = 1`,
+		`status = open AND`,
+		`status = "open`,
+	} {
+		_, err := Parse(filter, "", testIndexes)
+		require.True(t, errors.Is(err, ErrInvalidExpression), filter)
+	}
+	for _, s := range []string{"unknown", "total sideways", "total asc extra"} {
+		_, err := Parse("", s, testIndexes)
+		require.True(t, errors.Is(err, ErrInvalidExpression), s)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	q, err := Parse(`status = open AND total > 10`, "", testIndexes)
+	require.NoError(t, err)
+	require.True(t, q.Match([]byte(`{"status":"open","total":11}`)))
+	require.False(t, q.Match([]byte(`{"status":"open","total":10}`)))
+	require.False(t, q.Match([]byte(`{"status":"closed","total":11}`)))
+	require.False(t, q.Match([]byte(`{"status":"open","total":"11"}`)))
+	require.False(t, q.Match([]byte(`{"status":"open"}`)))
+	require.False(t, q.Match([]byte(`not json`)))
+
+	q, err = Parse(`status != open`, "updated_at", testIndexes)
+	require.NoError(t, err)
+	require.True(t, q.Match([]byte(`{"status":"closed","meta":{"updated_at":1}}`)))
+	require.False(t, q.Match([]byte(`{"status":"closed"}`)), "entries without sort field skipped")
+	require.False(t, q.Match([]byte(`{"meta":{"updated_at":1}}`)), "missing value never matches")
+}
+
+func TestTags(t *testing.T) {
+	tags := map[string]string{"source": "api", "status": "stale"}
+	result := Tags(testIndexes, []byte(`{"status":"open","total":12.5,"meta":{"updated_at":"x"}}`), tags)
+	require.Equal(t, map[string]string{"source": "api", "status": "open", "total": "12.5"}, result)
+	require.Equal(t, "stale", tags["status"], "original tags not modified")
+
+	require.Equal(t, tags, Tags(testIndexes, []byte(`{}`), tags))
+	require.Nil(t, Tags(testIndexes, []byte(`not json`), nil))
+}
+
+type entry struct {
+	key  string
+	data []byte
+}
+
+func TestSortAndCursor(t *testing.T) {
+	entries := []entry{
+		{"a", []byte(`{"meta":{"updated_at":3}}`)},
+		{"b", []byte(`{"meta":{"updated_at":1}}`)},
+		{"c", []byte(`{"meta":{"updated_at":3}}`)},
+		{"d", []byte(`{"meta":{"updated_at":2}}`)},
+	}
+	q, err := Parse("", "updated_at desc", testIndexes)
+	require.NoError(t, err)
+	sort.Slice(entries, func(i, j int) bool {
+		return q.Compare(entries[i].key, entries[i].data, entries[j].key, entries[j].data) < 0
+	})
+	var keys []string
+	for _, e := range entries {
+		keys = append(keys, e.key)
+	}
+	require.Equal(t, []string{"c", "a", "d", "b"}, keys)
+
+	cursor := q.MakeCursor(entries[1].key, entries[1].data)
+	pos, err := q.ParseCursor(cursor)
+	require.NoError(t, err)
+	require.Equal(t, "a", pos.Key)
+	require.Equal(t, float64(3), pos.Number)
+	var rest []string
+	for _, e := range entries {
+		if q.After(e.key, e.data, pos) {
+			rest = append(rest, e.key)
+		}
+	}
+	require.Equal(t, []string{"d", "b"}, rest)
+
+	_, err = q.ParseCursor("!!!")
+	require.True(t, errors.Is(err, ErrInvalidExpression))
+
+	// Without sort cursor is a plain key.
+	q, err = Parse(`status = open`, "", testIndexes)
+	require.NoError(t, err)
+	require.Equal(t, "k", q.MakeCursor("k", nil))
+	pos, err = q.ParseCursor("k")
+	require.NoError(t, err)
+	require.True(t, q.After("l", nil, pos))
+	require.False(t, q.After("j", nil, pos))
+}
@@ -0,0 +1,127 @@
+// Package mapsnapshot contains file formats for map channel snapshots made by
+// map_export server API method and consumed by map_import.
+//
+// A snapshot file is a sequence of apiproto.MapSnapshot messages, one per channel.
+// Two encodings are supported:
+//
+//   - ndjson – one JSON encoded snapshot per line. Entry data must be valid JSON.
+//   - protobuf – size-delimited protobuf messages. Suitable for binary data.
+package mapsnapshot
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/centrifugal/centrifugo/v6/internal/apiproto"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+)
+
+// Supported snapshot file formats.
+const (
+	FormatNDJSON   = "ndjson"
+	FormatProtobuf = "protobuf"
+)
+
+// ErrChannelNotEmpty returned when importing a snapshot into a channel which
+// already has data and replacing was not requested.
+var ErrChannelNotEmpty = errors.New("map channel is not empty")
+
+// maxLineSize limits the size of a single NDJSON snapshot line or a single
+// protobuf snapshot message.
+const maxLineSize = 512 * 1024 * 1024
+
+// Writer writes snapshots to a file in one of supported formats.
+type Writer struct {
+	w      *bufio.Writer
+	format string
+}
+
+// NewWriter creates Writer. Flush must be called after writing all snapshots.
+func NewWriter(w io.Writer, format string) (*Writer, error) {
+	if err := checkFormat(format); err != nil {
+		return nil, err
+	}
+	return &Writer{w: bufio.NewWriter(w), format: format}, nil
+}
+
+// Write writes a single channel snapshot.
+func (w *Writer) Write(s *apiproto.MapSnapshot) error {
+	if w.format == FormatProtobuf {
+		_, err := protodelim.MarshalTo(w.w, s)
+		return err
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if _, err := w.w.Write(data); err != nil {
+		return err
+	}
+	return w.w.WriteByte('\n')
+}
+
+// Flush writes buffered data to the underlying writer.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// Reader reads snapshots written by Writer.
+type Reader struct {
+	r       *bufio.Reader
+	scanner *bufio.Scanner
+	format  string
+}
+
+// NewReader creates Reader.
+func NewReader(r io.Reader, format string) (*Reader, error) {
+	if err := checkFormat(format); err != nil {
+		return nil, err
+	}
+	rd := &Reader{format: format}
+	if format == FormatProtobuf {
+		rd.r = bufio.NewReader(r)
+	} else {
+		rd.scanner = bufio.NewScanner(r)
+		rd.scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	}
+	return rd, nil
+}
+
+// Read returns the next snapshot or io.EOF when there are no more snapshots.
+func (r *Reader) Read() (*apiproto.MapSnapshot, error) {
+	s := &apiproto.MapSnapshot{}
+	if r.format == FormatProtobuf {
+		err := protodelim.UnmarshalOptions{MaxSize: maxLineSize}.UnmarshalFrom(r.r, s)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	for r.scanner.Scan() {
+		line := r.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(line, s); err != nil {
+			return nil, fmt.Errorf("malformed snapshot line: %w", err)
+		}
+		return s, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+func checkFormat(format string) error {
+	switch format {
+	case FormatNDJSON, FormatProtobuf:
+		return nil
+	default:
+		return fmt.Errorf("unknown snapshot format %q, use %q or %q", format, FormatNDJSON, FormatProtobuf)
+	}
+}
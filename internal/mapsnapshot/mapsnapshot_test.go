@@ -0,0 +1,83 @@
+package mapsnapshot
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/centrifugal/centrifugo/v6/internal/apiproto"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterReader(t *testing.T) {
+	snapshots := []*apiproto.MapSnapshot{
+		{
+			Channel: "orders:1",
+			Offset:  42,
+			Epoch:   "abc",
+			Entries: []*apiproto.MapEntry{
+				{Key: "a", Data: apiproto.Raw(`{"status":"open"}`), Offset: 40, Tags: map[string]string{"t": "v"}},
+				{Key: "b", Data: apiproto.Raw(`{"status":"closed"}`), Offset: 42},
+			},
+		},
+		{Channel: "orders:2", Offset: 1, Epoch: "xyz"},
+	}
+	for _, format := range []string{FormatNDJSON, FormatProtobuf} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(&buf, format)
+			require.NoError(t, err)
+			for _, s := range snapshots {
+				require.NoError(t, w.Write(s))
+			}
+			require.NoError(t, w.Flush())
+
+			r, err := NewReader(&buf, format)
+			require.NoError(t, err)
+			for _, expected := range snapshots {
+				s, err := r.Read()
+				require.NoError(t, err)
+				require.Equal(t, expected.Channel, s.Channel)
+				require.Equal(t, expected.Offset, s.Offset)
+				require.Equal(t, expected.Epoch, s.Epoch)
+				require.Len(t, s.Entries, len(expected.Entries))
+				for i, e := range expected.Entries {
+					require.Equal(t, e.Key, s.Entries[i].Key)
+					require.JSONEq(t, string(e.Data), string(s.Entries[i].Data))
+					require.Equal(t, e.Offset, s.Entries[i].Offset)
+				}
+			}
+			_, err = r.Read()
+			require.ErrorIs(t, err, io.EOF)
+		})
+	}
+}
+
+func TestWriterReaderLargeSnapshot(t *testing.T) {
+	// Larger than protodelim default limit of 4 MiB.
+	data := apiproto.Raw(bytes.Repeat([]byte{0xff}, 5*1024*1024))
+	snapshot := &apiproto.MapSnapshot{
+		Channel: "orders:1",
+		Entries: []*apiproto.MapEntry{{Key: "a", Data: data}},
+	}
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, FormatProtobuf)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(snapshot))
+	require.NoError(t, w.Flush())
+
+	r, err := NewReader(&buf, FormatProtobuf)
+	require.NoError(t, err)
+	s, err := r.Read()
+	require.NoError(t, err)
+	require.Len(t, s.Entries, 1)
+	require.Equal(t, []byte(data), []byte(s.Entries[0].Data))
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := NewWriter(&bytes.Buffer{}, "xml")
+	require.Error(t, err)
+	_, err = NewReader(&bytes.Buffer{}, "xml")
+	require.Error(t, err)
+}
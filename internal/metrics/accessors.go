@@ -46,6 +46,72 @@ var (
 	SharedPollProxyResponseItems *prometheus.HistogramVec
 )
 
+// Throttle metrics - exported for use by api and client packages
+var (
+	ThrottleCoalescedTotal *prometheus.CounterVec
+)
+
+// Shared poll coordination metrics - exported for use by sharedpoll package
+var (
+	SharedPollCoordinatedPollsTotal *prometheus.CounterVec
+)
+
+// JWKS metrics - exported for use by jwks package
+var (
+	JWKSRefreshesTotal     *prometheus.CounterVec
+	JWKSRefreshErrorsTotal *prometheus.CounterVec
+)
+
+// Connection quota metrics - exported for use by connquota package
+var (
+	ConnQuotaRejectedTotal *prometheus.CounterVec
+	ConnQuotaEvictedTotal  *prometheus.CounterVec
+)
+
+// Publication schema metrics - exported for use by api and client packages
+var (
+	PublicationSchemaViolationsTotal *prometheus.CounterVec
+)
+
+// WebSocket compression metrics - exported for use by WebSocket transports
+var (
+	WebSocketCompressionRatio *prometheus.HistogramVec
+)
+
+// Drain metrics - exported for use by drain and client packages
+var (
+	DrainDisconnectsTotal prometheus.Counter
+	DrainRejectedTotal    prometheus.Counter
+)
+
+// Tenant metrics - exported for use by tenant, client and api packages
+var (
+	TenantConnections        *prometheus.GaugeVec
+	TenantPublicationsTotal  *prometheus.CounterVec
+	TenantLimitRejectedTotal *prometheus.CounterVec
+)
+
+// Audit metrics - exported for use by audit package
+var (
+	AuditRecordsDroppedTotal prometheus.Counter
+	AuditSinkErrorsTotal     *prometheus.CounterVec
+)
+
+// Scheduled publications metrics - exported for use by schedule package
+var (
+	ScheduledPublicationsFiredTotal *prometheus.CounterVec
+)
+
+// Channel state metrics - exported for use by chstate package
+var (
+	ChannelStateChangesCoalescedTotal prometheus.Counter
+)
+
+// History archive metrics - exported for use by historyarchive package
+var (
+	HistoryArchiveRecordsTotal *prometheus.CounterVec
+)
+
 // Middleware metrics - exported for use by middleware package
 var (
 	ConnLimitReached  prometheus.Counter
@@ -38,3 +38,122 @@ func InitConsumerMetrics(consumerName string) {
 	ConsumerProcessedTotal.WithLabelValues(consumerName).Add(0)
 	ConsumerErrorsTotal.WithLabelValues(consumerName).Add(0)
 }
+
+// Throttle metric helper functions
+
+// IncThrottleCoalesced increments the counter of publications coalesced by throttling.
+func IncThrottleCoalesced(namespace string) {
+	ThrottleCoalescedTotal.WithLabelValues(namespace).Inc()
+}
+
+// Shared poll coordination metric helper functions
+
+// IncSharedPollCoordinatedPoll increments the counter of coordinated shared poll refreshes.
+func IncSharedPollCoordinatedPoll(role string) {
+	SharedPollCoordinatedPollsTotal.WithLabelValues(role).Inc()
+}
+
+// JWKS metric helper functions
+
+// IncJWKSRefresh increments the counter of successful JWKS fetches for endpoint.
+func IncJWKSRefresh(endpoint string) {
+	JWKSRefreshesTotal.WithLabelValues(endpoint).Inc()
+}
+
+// IncJWKSRefreshError increments the counter of failed JWKS fetches for endpoint.
+func IncJWKSRefreshError(endpoint string) {
+	JWKSRefreshErrorsTotal.WithLabelValues(endpoint).Inc()
+}
+
+// Connection quota metric helper functions
+
+// IncConnQuotaRejected increments the counter of connections rejected by quota.
+func IncConnQuotaRejected(quota string) {
+	ConnQuotaRejectedTotal.WithLabelValues(quota).Inc()
+}
+
+// IncConnQuotaEvicted increments the counter of connections evicted by quota.
+func IncConnQuotaEvicted(quota string) {
+	ConnQuotaEvictedTotal.WithLabelValues(quota).Inc()
+}
+
+// Publication schema metric helper functions
+
+// IncPublicationSchemaViolation increments the counter of publications rejected
+// due to JSON Schema violation.
+func IncPublicationSchemaViolation(namespace string, source string) {
+	PublicationSchemaViolationsTotal.WithLabelValues(namespace, source).Inc()
+}
+
+// WebSocket compression metric helper functions
+
+// ObserveWebSocketCompression observes the compression ratio of an outgoing
+// WebSocket message.
+func ObserveWebSocketCompression(transport string, uncompressed int, compressed int) {
+	if compressed <= 0 {
+		return
+	}
+	WebSocketCompressionRatio.WithLabelValues(transport).Observe(float64(uncompressed) / float64(compressed))
+}
+
+// Drain metric helper functions
+
+// IncDrainDisconnect increments the counter of connections disconnected while draining.
+func IncDrainDisconnect() {
+	DrainDisconnectsTotal.Inc()
+}
+
+// IncDrainRejected increments the counter of connections rejected while draining.
+func IncDrainRejected() {
+	DrainRejectedTotal.Inc()
+}
+
+// Tenant metric helper functions
+
+// SetTenantConnections sets the number of tenant connections on node.
+func SetTenantConnections(tenant string, n int) {
+	TenantConnections.WithLabelValues(tenant).Set(float64(n))
+}
+
+// IncTenantPublication increments the counter of publications into tenant channels.
+func IncTenantPublication(tenant string, source string) {
+	TenantPublicationsTotal.WithLabelValues(tenant, source).Inc()
+}
+
+// IncTenantLimitRejected increments the counter of operations rejected by tenant limit.
+func IncTenantLimitRejected(tenant string, limit string) {
+	TenantLimitRejectedTotal.WithLabelValues(tenant, limit).Inc()
+}
+
+// Audit metric helper functions
+
+// IncAuditRecordDropped increments the counter of audit records dropped due to full buffer.
+func IncAuditRecordDropped() {
+	AuditRecordsDroppedTotal.Inc()
+}
+
+// IncAuditSinkError increments the counter of errors writing audit records to sink.
+func IncAuditSinkError(sink string) {
+	AuditSinkErrorsTotal.WithLabelValues(sink).Inc()
+}
+
+// Scheduled publications metric helper functions
+
+// IncScheduledPublicationFired increments the counter of fired scheduled publications.
+func IncScheduledPublicationFired(method string, result string) {
+	ScheduledPublicationsFiredTotal.WithLabelValues(method, result).Inc()
+}
+
+// Channel state metric helper functions
+
+// IncChannelStateChangeCoalesced increments the counter of coalesced channel subscription changes.
+func IncChannelStateChangeCoalesced() {
+	ChannelStateChangesCoalescedTotal.Inc()
+}
+
+// History archive metric helper functions
+
+// AddHistoryArchiveRecords adds to the counter of archived publications.
+func AddHistoryArchiveRecords(source string, result string, n int) {
+	HistoryArchiveRecordsTotal.WithLabelValues(source, result).Add(float64(n))
+}
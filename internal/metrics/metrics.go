@@ -96,6 +96,48 @@ type Registry struct {
 	sharedPollProxyRequestItems  *prometheus.HistogramVec
 	sharedPollProxyResponseItems *prometheus.HistogramVec
 
+	// Throttle metrics
+	throttleCoalescedTotal *prometheus.CounterVec
+
+	// Shared poll coordination metrics
+	sharedPollCoordinatedPollsTotal *prometheus.CounterVec
+
+	// JWKS metrics
+	jwksRefreshesTotal     *prometheus.CounterVec
+	jwksRefreshErrorsTotal *prometheus.CounterVec
+
+	// Connection quota metrics
+	connQuotaRejectedTotal *prometheus.CounterVec
+	connQuotaEvictedTotal  *prometheus.CounterVec
+
+	// Publication schema metrics
+	publicationSchemaViolationsTotal *prometheus.CounterVec
+
+	// WebSocket compression metrics
+	websocketCompressionRatio *prometheus.HistogramVec
+
+	// Drain metrics
+	drainDisconnectsTotal prometheus.Counter
+	drainRejectedTotal    prometheus.Counter
+
+	// Tenant metrics
+	tenantConnections        *prometheus.GaugeVec
+	tenantPublicationsTotal  *prometheus.CounterVec
+	tenantLimitRejectedTotal *prometheus.CounterVec
+
+	// Audit metrics
+	auditRecordsDroppedTotal prometheus.Counter
+	auditSinkErrorsTotal     *prometheus.CounterVec
+
+	// Scheduled publications metrics
+	scheduledPublicationsFiredTotal *prometheus.CounterVec
+
+	// Channel state metrics
+	channelStateChangesCoalescedTotal prometheus.Counter
+
+	// History archive metrics
+	historyArchiveRecordsTotal *prometheus.CounterVec
+
 	// Middleware metrics
 	connLimitReached  prometheus.Counter
 	httpRequestsTotal *prometheus.CounterVec
@@ -139,6 +181,32 @@ func Init(cfg Config) error {
 	SharedPollProxyRequestItems = reg.sharedPollProxyRequestItems
 	SharedPollProxyResponseItems = reg.sharedPollProxyResponseItems
 
+	ThrottleCoalescedTotal = reg.throttleCoalescedTotal
+
+	SharedPollCoordinatedPollsTotal = reg.sharedPollCoordinatedPollsTotal
+
+	JWKSRefreshesTotal = reg.jwksRefreshesTotal
+	JWKSRefreshErrorsTotal = reg.jwksRefreshErrorsTotal
+
+	ConnQuotaRejectedTotal = reg.connQuotaRejectedTotal
+	ConnQuotaEvictedTotal = reg.connQuotaEvictedTotal
+
+	PublicationSchemaViolationsTotal = reg.publicationSchemaViolationsTotal
+
+	WebSocketCompressionRatio = reg.websocketCompressionRatio
+
+	DrainDisconnectsTotal = reg.drainDisconnectsTotal
+	DrainRejectedTotal = reg.drainRejectedTotal
+
+	TenantConnections = reg.tenantConnections
+	TenantPublicationsTotal = reg.tenantPublicationsTotal
+	TenantLimitRejectedTotal = reg.tenantLimitRejectedTotal
+	AuditRecordsDroppedTotal = reg.auditRecordsDroppedTotal
+	AuditSinkErrorsTotal = reg.auditSinkErrorsTotal
+	ScheduledPublicationsFiredTotal = reg.scheduledPublicationsFiredTotal
+	ChannelStateChangesCoalescedTotal = reg.channelStateChangesCoalescedTotal
+	HistoryArchiveRecordsTotal = reg.historyArchiveRecordsTotal
+
 	ConnLimitReached = reg.connLimitReached
 	HTTPRequestsTotal = reg.httpRequestsTotal
 
@@ -360,6 +428,151 @@ func newRegistry(cfg Config) (*Registry, error) {
 		ConstLabels: constLabels,
 	}, []string{"broker_name"})
 
+	m.throttleCoalescedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   metricsNamespace,
+		Subsystem:   "throttle",
+		Name:        "coalesced_publications_total",
+		Help:        "Total publications replaced by a newer one within a throttle window and never delivered.",
+		ConstLabels: constLabels,
+	}, []string{"namespace"})
+
+	m.sharedPollCoordinatedPollsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   metricsNamespace,
+		Subsystem:   "shared_poll",
+		Name:        "coordinated_polls_total",
+		Help:        "Total shared poll refreshes in coordinated mode by role of the node: leader, follower or fallback.",
+		ConstLabels: constLabels,
+	}, []string{"role"})
+
+	m.jwksRefreshesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   metricsNamespace,
+		Subsystem:   "jwks",
+		Name:        "refreshes_total",
+		Help:        "Total successful key set fetches from JWKS endpoint.",
+		ConstLabels: constLabels,
+	}, []string{"endpoint"})
+
+	m.jwksRefreshErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   metricsNamespace,
+		Subsystem:   "jwks",
+		Name:        "refresh_errors_total",
+		Help:        "Total failed key set fetches from JWKS endpoint.",
+		ConstLabels: constLabels,
+	}, []string{"endpoint"})
+
+	m.connQuotaRejectedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   metricsNamespace,
+		Subsystem:   "connection_quota",
+		Name:        "rejected_total",
+		Help:        "Total connections rejected due to cluster-wide connection quota.",
+		ConstLabels: constLabels,
+	}, []string{"quota"})
+
+	m.connQuotaEvictedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   metricsNamespace,
+		Subsystem:   "connection_quota",
+		Name:        "evicted_total",
+		Help:        "Total connections evicted to make room for new ones due to cluster-wide connection quota.",
+		ConstLabels: constLabels,
+	}, []string{"quota"})
+
+	m.publicationSchemaViolationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   metricsNamespace,
+		Subsystem:   "publication",
+		Name:        "schema_violations_total",
+		Help:        "Total publications rejected due to JSON Schema violation.",
+		ConstLabels: constLabels,
+	}, []string{"namespace", "source"})
+
+	m.websocketCompressionRatio = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   metricsNamespace,
+		Subsystem:   "websocket",
+		Name:        "compression_ratio",
+		Buckets:     []float64{1, 1.5, 2, 3, 4, 6, 8, 12, 16, 32},
+		Help:        "Histogram of ratio between uncompressed and compressed size of outgoing WebSocket messages.",
+		ConstLabels: constLabels,
+	}, []string{"transport"})
+
+	m.drainDisconnectsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   metricsNamespace,
+		Subsystem:   "drain",
+		Name:        "disconnects_total",
+		Help:        "Total connections disconnected while draining node.",
+		ConstLabels: constLabels,
+	})
+
+	m.drainRejectedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   metricsNamespace,
+		Subsystem:   "drain",
+		Name:        "rejected_total",
+		Help:        "Total new connections rejected while draining node.",
+		ConstLabels: constLabels,
+	})
+
+	m.tenantConnections = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace:   metricsNamespace,
+		Subsystem:   "tenant",
+		Name:        "connections",
+		Help:        "Number of tenant connections on node.",
+		ConstLabels: constLabels,
+	}, []string{"tenant"})
+
+	m.tenantPublicationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   metricsNamespace,
+		Subsystem:   "tenant",
+		Name:        "publications_total",
+		Help:        "Total publications into tenant channels.",
+		ConstLabels: constLabels,
+	}, []string{"tenant", "source"})
+
+	m.tenantLimitRejectedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   metricsNamespace,
+		Subsystem:   "tenant",
+		Name:        "limit_rejected_total",
+		Help:        "Total operations rejected due to tenant limits.",
+		ConstLabels: constLabels,
+	}, []string{"tenant", "limit"})
+
+	m.auditRecordsDroppedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   metricsNamespace,
+		Subsystem:   "audit",
+		Name:        "records_dropped_total",
+		Help:        "Total audit records dropped due to full buffer.",
+		ConstLabels: constLabels,
+	})
+
+	m.auditSinkErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   metricsNamespace,
+		Subsystem:   "audit",
+		Name:        "sink_errors_total",
+		Help:        "Total errors writing audit records to sink.",
+		ConstLabels: constLabels,
+	}, []string{"sink"})
+
+	m.scheduledPublicationsFiredTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   metricsNamespace,
+		Subsystem:   "scheduled_publications",
+		Name:        "fired_total",
+		Help:        "Total scheduled publications fired, by result.",
+		ConstLabels: constLabels,
+	}, []string{"method", "result"})
+
+	m.channelStateChangesCoalescedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   metricsNamespace,
+		Subsystem:   "channel_state",
+		Name:        "changes_coalesced_total",
+		Help:        "Total channel subscription changes replaced by a later change of the same channel before processing.",
+		ConstLabels: constLabels,
+	})
+
+	m.historyArchiveRecordsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   metricsNamespace,
+		Subsystem:   "history_archive",
+		Name:        "records_total",
+		Help:        "Total publications written to history archive, by source and result. Result error counts failed write attempts which are retried, dropped counts publications not archived because archiver buffer was full.",
+		ConstLabels: constLabels,
+	}, []string{"source", "result"})
+
 	// Register all metrics
 	var alreadyRegistered prometheus.AlreadyRegisteredError
 
@@ -377,6 +590,24 @@ func newRegistry(cfg Config) (*Registry, error) {
 		m.consumerErrorsTotal,
 		m.sharedPollProxyRequestItems,
 		m.sharedPollProxyResponseItems,
+		m.throttleCoalescedTotal,
+		m.sharedPollCoordinatedPollsTotal,
+		m.jwksRefreshesTotal,
+		m.jwksRefreshErrorsTotal,
+		m.connQuotaRejectedTotal,
+		m.connQuotaEvictedTotal,
+		m.publicationSchemaViolationsTotal,
+		m.websocketCompressionRatio,
+		m.drainDisconnectsTotal,
+		m.drainRejectedTotal,
+		m.tenantConnections,
+		m.tenantPublicationsTotal,
+		m.tenantLimitRejectedTotal,
+		m.auditRecordsDroppedTotal,
+		m.auditSinkErrorsTotal,
+		m.scheduledPublicationsFiredTotal,
+		m.channelStateChangesCoalescedTotal,
+		m.historyArchiveRecordsTotal,
 		m.connLimitReached,
 		m.httpRequestsTotal,
 		m.brokerPostgresCleanupRemovedTotal,
@@ -3,7 +3,9 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"sync/atomic"
 
+	"github.com/centrifugal/centrifugo/v6/internal/clientcontext"
 	"github.com/centrifugal/centrifugo/v6/internal/tools"
 
 	"github.com/rs/zerolog/log"
@@ -12,47 +14,112 @@ import (
 // APIKeyAuth middleware authorizes request using API key authorization.
 // It first tries to use Authorization header to extract API key
 // (Authorization: apikey <KEY>), then checks for api_key URL query parameter.
-// If key not found or invalid then 401 response code is returned.
+// If key not found or invalid then 401 response code is returned. Authorized
+// caller identity is put to request context. Keys may be changed with Reload.
 type APIKeyAuth struct {
-	key string
+	keys atomic.Pointer[APIKeys]
+}
+
+// APIKeys are keys accepted by APIKeyAuth.
+type APIKeys struct {
+	// Key is a main API key, caller identity is api_key.
+	Key string
+	// NamedKeys are key names keyed by API key, caller identity is api_key:{name}.
+	NamedKeys map[string]string
+	// TenantKeys are tenant names keyed by API key, caller identity is tenant:{name}.
+	TenantKeys map[string]string
 }
 
 func NewAPIKeyAuth(key string) *APIKeyAuth {
-	return &APIKeyAuth{key: key}
+	a := &APIKeyAuth{}
+	a.keys.Store(&APIKeys{Key: key})
+	return a
+}
+
+// WithNamedKeys additionally authorizes requests using named API keys (key names
+// keyed by API key). Name of matched key is put to caller identity.
+func (a *APIKeyAuth) WithNamedKeys(keys map[string]string) *APIKeyAuth {
+	k := *a.keys.Load()
+	k.NamedKeys = keys
+	a.keys.Store(&k)
+	return a
+}
+
+// WithTenantKeys additionally authorizes requests using API keys of tenants (tenant
+// names keyed by API key). Tenant of matched key is put to request context.
+func (a *APIKeyAuth) WithTenantKeys(keys map[string]string) *APIKeyAuth {
+	k := *a.keys.Load()
+	k.TenantKeys = keys
+	a.keys.Store(&k)
+	return a
+}
+
+// Reload replaces keys used for authorization, e.g. after secrets rotation.
+func (a *APIKeyAuth) Reload(keys APIKeys) {
+	a.keys.Store(&keys)
+}
+
+func (k *APIKeys) empty() bool {
+	return k.Key == "" && len(k.NamedKeys) == 0 && len(k.TenantKeys) == 0
+}
+
+// match checks provided key against configured keys and returns caller identity
+// and tenant name if key belongs to tenant.
+func (k *APIKeys) match(key string) (string, string, bool) {
+	if key == "" {
+		return "", "", false
+	}
+	if k.Key != "" && tools.SecureCompareString(k.Key, key) {
+		return "api_key", "", true
+	}
+	for namedKey, name := range k.NamedKeys {
+		if tools.SecureCompareString(namedKey, key) {
+			return "api_key:" + name, "", true
+		}
+	}
+	for tenantKey, tenant := range k.TenantKeys {
+		if tools.SecureCompareString(tenantKey, key) {
+			return "tenant:" + tenant, tenant, true
+		}
+	}
+	return "", "", false
 }
 
 func (a *APIKeyAuth) Middleware(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if a.key == "" {
+		keys := a.keys.Load()
+		if keys.empty() {
 			log.Error().Msg("API key is empty")
 			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
+		var identity, tenant string
 		authValid := false
 		authHeaderValue := r.Header.Get("X-API-Key")
 		if authHeaderValue != "" {
-			if tools.SecureCompareString(a.key, authHeaderValue) {
-				authValid = true
-			}
+			identity, tenant, authValid = keys.match(authHeaderValue)
 		} else {
 			authHeaderAuthorization := r.Header.Get("Authorization")
 			if authHeaderAuthorization != "" {
 				parts := strings.Fields(authHeaderAuthorization)
-				if len(parts) == 2 && strings.ToLower(parts[0]) == "apikey" && tools.SecureCompareString(a.key, parts[1]) {
-					authValid = true
+				if len(parts) == 2 && strings.ToLower(parts[0]) == "apikey" {
+					identity, tenant, authValid = keys.match(parts[1])
 				}
 			}
 		}
 		if !authValid && r.URL.RawQuery != "" {
 			// Check URL param.
-			if tools.SecureCompareString(a.key, r.URL.Query().Get("api_key")) {
-				authValid = true
-			}
+			identity, tenant, authValid = keys.match(r.URL.Query().Get("api_key"))
 		}
 		if !authValid {
 			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
+		ctx := r.Context()
+		if tenant != "" {
+			ctx = clientcontext.SetTenantToContext(ctx, tenant)
+		}
+		r = r.WithContext(clientcontext.SetAPICallerToContext(ctx, clientcontext.APICaller{Identity: identity}))
 		h.ServeHTTP(w, r)
 	})
 }
@@ -5,6 +5,8 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/centrifugal/centrifugo/v6/internal/clientcontext"
+
 	"github.com/stretchr/testify/require"
 )
 
@@ -103,3 +105,107 @@ func TestAPIKeyAuthQueryParam(t *testing.T) {
 	require.Equal(t, res.StatusCode, http.StatusOK)
 	_ = res.Body.Close()
 }
+
+func TestAPIKeyAuthTenantKeys(t *testing.T) {
+	var tenant string
+	var tenantFound bool
+	var caller clientcontext.APICaller
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		tenant, tenantFound = clientcontext.GetTenantFromContext(req.Context())
+		caller, _ = clientcontext.GetAPICallerFromContext(req.Context())
+	})
+	auth := NewAPIKeyAuth("test").WithTenantKeys(map[string]string{"acme-key": "acme"})
+	ts := httptest.NewServer(auth.Middleware(handler))
+	defer ts.Close()
+
+	req, err := http.NewRequest("POST", ts.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", "acme-key")
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	_ = res.Body.Close()
+	require.True(t, tenantFound)
+	require.Equal(t, "acme", tenant)
+	require.Equal(t, "tenant:acme", caller.Identity)
+
+	req, err = http.NewRequest("POST", ts.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", "test")
+	res, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	_ = res.Body.Close()
+	require.False(t, tenantFound)
+	require.Equal(t, "api_key", caller.Identity)
+
+	req, err = http.NewRequest("POST", ts.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", "globex-key")
+	res, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	_ = res.Body.Close()
+}
+
+func TestAPIKeyAuthOnlyTenantKeys(t *testing.T) {
+	auth := NewAPIKeyAuth("").WithTenantKeys(map[string]string{"acme-key": "acme"})
+	ts := httptest.NewServer(auth.Middleware(testHandler()))
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "?api_key=acme-key")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	_ = res.Body.Close()
+
+	// Empty key must not match empty global key.
+	req, err := http.NewRequest("POST", ts.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "apikey ")
+	res, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	_ = res.Body.Close()
+}
+
+func TestAPIKeyAuthReload(t *testing.T) {
+	auth := NewAPIKeyAuth("old")
+	ts := httptest.NewServer(auth.Middleware(testHandler()))
+	defer ts.Close()
+
+	auth.Reload(APIKeys{Key: "new", TenantKeys: map[string]string{"acme-key": "acme"}})
+
+	for key, status := range map[string]int{"old": http.StatusUnauthorized, "new": http.StatusOK, "acme-key": http.StatusOK} {
+		res, err := http.Get(ts.URL + "?api_key=" + key)
+		require.NoError(t, err)
+		require.Equal(t, status, res.StatusCode, key)
+		_ = res.Body.Close()
+	}
+}
+
+func TestAPIKeyAuthNamedKeys(t *testing.T) {
+	var caller clientcontext.APICaller
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		caller, _ = clientcontext.GetAPICallerFromContext(req.Context())
+	})
+	auth := NewAPIKeyAuth("").WithNamedKeys(map[string]string{"billing-key": "billing"})
+	ts := httptest.NewServer(auth.Middleware(handler))
+	defer ts.Close()
+
+	req, err := http.NewRequest("POST", ts.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "apikey billing-key")
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	_ = res.Body.Close()
+	require.Equal(t, "api_key:billing", caller.Identity)
+
+	req, err = http.NewRequest("POST", ts.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", "billing")
+	res, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	_ = res.Body.Close()
+}
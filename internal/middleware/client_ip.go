@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/centrifugal/centrifugo/v6/internal/clientcontext"
+)
+
+// ClientIPToContext is a middleware that puts client IP address to request context. If
+// ipHeaderName is set IP is extracted from that header, otherwise from request remote
+// address. Header value may be a list of addresses (like X-Forwarded-For) where each
+// proxy appends address it got request from, so the list is walked from the right: the
+// first address not belonging to trustedProxies is used. Without trustedProxies the
+// rightmost address is used. With trustedProxies the header is only taken into account
+// for requests coming from trusted proxies.
+func ClientIPToContext(ipHeaderName string, trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := clientIP(r, ipHeaderName, trustedProxies)
+			if ip != "" {
+				r = r.WithContext(clientcontext.SetClientIPToContext(r.Context(), ip))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func clientIP(r *http.Request, ipHeaderName string, trustedProxies []netip.Prefix) string {
+	remoteIP := remoteAddrIP(r)
+	if ipHeaderName == "" {
+		return remoteIP
+	}
+	if len(trustedProxies) > 0 && !isTrustedProxy(remoteIP, trustedProxies) {
+		return remoteIP
+	}
+	var addresses []string
+	for _, value := range r.Header.Values(ipHeaderName) {
+		for _, address := range strings.Split(value, ",") {
+			if address = strings.TrimSpace(address); address != "" {
+				addresses = append(addresses, address)
+			}
+		}
+	}
+	for i := len(addresses) - 1; i > 0; i-- {
+		if !isTrustedProxy(addresses[i], trustedProxies) {
+			return addresses[i]
+		}
+	}
+	if len(addresses) > 0 {
+		// All proxies in chain are trusted, so the leftmost address is client's.
+		return addresses[0]
+	}
+	return ""
+}
+
+func remoteAddrIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func isTrustedProxy(ip string, trustedProxies []netip.Prefix) bool {
+	if len(trustedProxies) == 0 {
+		return false
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
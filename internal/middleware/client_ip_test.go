@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/centrifugal/centrifugo/v6/internal/clientcontext"
+	"github.com/centrifugal/centrifugo/v6/internal/tools"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientIPToContext(t *testing.T) {
+	testCases := []struct {
+		name           string
+		headerName     string
+		header         string
+		trustedProxies []string
+		remoteAddr     string
+		expectedIP     string
+	}{
+		{name: "remote_addr", remoteAddr: "10.0.0.1:5555", expectedIP: "10.0.0.1"},
+		{name: "remote_addr_ipv6", remoteAddr: "[::1]:5555", expectedIP: "::1"},
+		{name: "header", headerName: "X-Real-IP", header: "192.168.1.1", remoteAddr: "10.0.0.1:5555", expectedIP: "192.168.1.1"},
+		{name: "header_list_rightmost", headerName: "X-Forwarded-For", header: "192.168.1.1, 10.0.0.2", remoteAddr: "10.0.0.1:5555", expectedIP: "10.0.0.2"},
+		{name: "header_list_trusted", headerName: "X-Forwarded-For", header: "6.6.6.6, 192.168.1.1, 10.0.0.2", trustedProxies: []string{"10.0.0.0/8"}, remoteAddr: "10.0.0.1:5555", expectedIP: "192.168.1.1"},
+		{name: "header_list_all_trusted", headerName: "X-Forwarded-For", header: "10.0.0.3, 10.0.0.2", trustedProxies: []string{"10.0.0.0/8"}, remoteAddr: "10.0.0.1:5555", expectedIP: "10.0.0.3"},
+		{name: "header_untrusted_remote", headerName: "X-Forwarded-For", header: "192.168.1.1", trustedProxies: []string{"10.0.0.1"}, remoteAddr: "172.16.0.1:5555", expectedIP: "172.16.0.1"},
+		{name: "header_missing", headerName: "X-Real-IP", remoteAddr: "10.0.0.1:5555", expectedIP: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.RemoteAddr = tc.remoteAddr
+			if tc.header != "" {
+				req.Header.Set(tc.headerName, tc.header)
+			}
+			var ip string
+			var ok bool
+			trustedProxies, err := tools.ParseTrustedProxies(tc.trustedProxies)
+			require.NoError(t, err)
+			handler := ClientIPToContext(tc.headerName, trustedProxies)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ip, ok = clientcontext.GetClientIPFromContext(r.Context())
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			require.Equal(t, tc.expectedIP != "", ok)
+			require.Equal(t, tc.expectedIP, ip)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/centrifugal/centrifugo/v6/internal/clientcontext"
+	"github.com/centrifugal/centrifugo/v6/internal/config"
+)
+
+// TenantFromHost is a middleware that puts tenant resolved from request Host to
+// request context. Requests with hosts not belonging to any tenant pass as is.
+func TenantFromHost(cfgContainer *config.Container) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if tenant, ok := cfgContainer.TenantByHost(r.Host); ok {
+				r = r.WithContext(clientcontext.SetTenantToContext(r.Context(), tenant))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// TenantFromPath returns a handler for requests like <prefix>/<tenant>/<path>. It puts
+// tenant to request context and passes request with <path> to next handler. Requests
+// to unknown tenants get 404 response.
+func TenantFromPath(cfgContainer *config.Container, prefix string, next http.Handler) http.Handler {
+	prefix = strings.TrimRight(prefix, "/") + "/"
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant, path, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, prefix), "/")
+		if _, ok := cfgContainer.Tenant(tenant); !ok {
+			http.NotFound(w, r)
+			return
+		}
+		r2 := r.Clone(clientcontext.SetTenantToContext(r.Context(), tenant))
+		r2.URL.Path = "/" + path
+		r2.URL.RawPath = ""
+		next.ServeHTTP(w, r2)
+	})
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/centrifugal/centrifugo/v6/internal/clientcontext"
+	"github.com/centrifugal/centrifugo/v6/internal/config"
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+
+	"github.com/stretchr/testify/require"
+)
+
+func tenantTestContainer(t *testing.T) *config.Container {
+	cfg := config.DefaultConfig()
+	cfg.Tenancy.Enabled = true
+	cfg.Tenants = []configtypes.Tenant{
+		{Name: "acme", Hosts: []string{"acme.example.com"}},
+	}
+	cfgContainer, err := config.NewContainer(cfg)
+	require.NoError(t, err)
+	return cfgContainer
+}
+
+func TestTenantFromHost(t *testing.T) {
+	var tenant string
+	handler := TenantFromHost(tenantTestContainer(t))(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		tenant, _ = clientcontext.GetTenantFromContext(req.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "http://acme.example.com:8000/connection/websocket", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	require.Equal(t, "acme", tenant)
+
+	req = httptest.NewRequest(http.MethodGet, "http://example.com/connection/websocket", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	require.Equal(t, "", tenant)
+}
+
+func TestTenantFromPath(t *testing.T) {
+	var tenant, path string
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		tenant, _ = clientcontext.GetTenantFromContext(req.Context())
+		path = req.URL.Path
+	})
+	handler := TenantFromPath(tenantTestContainer(t), "/tenants/", next)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tenants/acme/connection/websocket", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "acme", tenant)
+	require.Equal(t, "/connection/websocket", path)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tenants/globex/connection/websocket", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,169 @@
+package pgmapbroker
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/centrifugal/centrifugo/v6/internal/mapfilter"
+
+	"github.com/centrifugal/centrifuge"
+)
+
+// stateIndexExpr returns SQL expression extracting indexed field value from state data.
+// The same expression text is used for index creation and in queries, so PostgreSQL
+// planner can match them. Field path segments are validated by mapfilter.ValidateIndexes,
+// so embedding them into SQL is safe. Text values use "C" collation to compare
+// by bytes like mapfilter does in memory, regardless of database collation.
+func stateIndexExpr(idx mapfilter.Index) string {
+	path := "'{" + strings.Join(idx.Path(), ",") + "}'"
+	if idx.IsNumber() {
+		return fmt.Sprintf("(CASE WHEN jsonb_typeof(data #> %s) = 'number' THEN (data #>> %s)::numeric END)", path, path)
+	}
+	return fmt.Sprintf(`((data #>> %s) COLLATE "C")`, path)
+}
+
+// stateKeyExpr is a key used as a tiebreaker in filtered reads, ordered by bytes
+// same as in mapfilter.Query.Compare.
+const stateKeyExpr = `(key COLLATE "C")`
+
+func stateIndexCast(idx mapfilter.Index) string {
+	if idx.IsNumber() {
+		return "numeric"
+	}
+	return "text"
+}
+
+// maxIdentifierLen is the maximum length of PostgreSQL identifier (NAMEDATALEN - 1).
+// Longer names are silently truncated by PostgreSQL, so different indexes could
+// end up with the same name.
+const maxIdentifierLen = 63
+
+func (e *PostgresMapBroker) stateIndexName(idx mapfilter.Index) string {
+	// Hash of the whole definition makes sure indexes with the same name in different
+	// namespaces do not clash when they point to different fields, and keeps names
+	// unique when the readable part has to be shortened to fit identifier limit.
+	h := fnv.New64a()
+	// Expression is a part of the hash, so that changed index definition results
+	// into a new index.
+	_, _ = h.Write([]byte(e.names.state + "\x00" + idx.Name + "\x00" + idx.Field + "\x00" + idx.Type + "\x00" + stateIndexExpr(idx)))
+	suffix := fmt.Sprintf("_%016x", h.Sum64())
+	name := e.names.state + "_ix_" + idx.Name
+	if len(name)+len(suffix) > maxIdentifierLen {
+		name = name[:maxIdentifierLen-len(suffix)]
+	}
+	return name + suffix
+}
+
+// EnsureStateIndexes creates expression indexes over JSON fields of state data used
+// by filtered state reads. Indexes may come from different namespaces, identical
+// definitions are created once. Indexes are created concurrently to avoid blocking
+// writes, so the call may take a while on large tables. In binary data mode state
+// data is not JSONB, so nothing is created.
+func (e *PostgresMapBroker) EnsureStateIndexes(ctx context.Context, indexes []mapfilter.Index) error {
+	if e.conf.BinaryData {
+		return nil
+	}
+	created := make(map[string]struct{}, len(indexes))
+	for _, idx := range indexes {
+		if err := mapfilter.ValidateIndexes([]mapfilter.Index{idx}); err != nil {
+			return err
+		}
+		name := e.stateIndexName(idx)
+		if _, ok := created[name]; ok {
+			continue
+		}
+		created[name] = struct{}{}
+		query := fmt.Sprintf(
+			`CREATE INDEX CONCURRENTLY IF NOT EXISTS %s ON %s (channel, %s, %s)`,
+			name, e.names.state, stateIndexExpr(idx), stateKeyExpr,
+		)
+		if _, err := e.pool.Exec(ctx, query); err != nil {
+			return &SchemaError{Object: SchemaObject{Type: "index", Name: name}, Op: "create", Err: err}
+		}
+	}
+	return nil
+}
+
+// ReadFilteredState reads state entries matching the query, ordered by the query sort
+// field (or by key when sort is not set). Supports cursor pagination with cursors made
+// by mapfilter.Query.MakeCursor. Returns mapfilter.ErrNotSupported in binary data mode.
+func (e *PostgresMapBroker) ReadFilteredState(ctx context.Context, ch string, q mapfilter.Query, opts centrifuge.MapReadStateOptions) (centrifuge.MapStateResult, error) {
+	if e.conf.BinaryData {
+		return centrifuge.MapStateResult{}, mapfilter.ErrNotSupported
+	}
+	pool := e.getReadPool(ch, opts.AllowCached)
+
+	_, err := centrifuge.ResolveAndValidateMapChannelOptions(e.node.Config().Map.GetMapChannelOptions, ch)
+	if err != nil {
+		return centrifuge.MapStateResult{}, err
+	}
+
+	if opts.Limit == 0 {
+		return e.readStatePosition(ctx, pool, ch, opts)
+	}
+	limit := opts.Limit
+	if limit < 0 {
+		limit = 100000
+	}
+
+	args := []any{ch, limit + 1}
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	var where strings.Builder
+	where.WriteString("channel = $1")
+	for _, c := range q.Conditions {
+		where.WriteString(fmt.Sprintf(" AND %s %s %s::%s", stateIndexExpr(c.Index), c.Op, arg(c.Value), stateIndexCast(c.Index)))
+	}
+
+	direction := "ASC"
+	cmpOp := ">"
+	if q.Sort != nil && q.Sort.Desc {
+		direction = "DESC"
+		cmpOp = "<"
+	}
+
+	var orderBy string
+	if q.Sort != nil {
+		expr := stateIndexExpr(q.Sort.Index)
+		where.WriteString(fmt.Sprintf(" AND %s IS NOT NULL", expr))
+		orderBy = fmt.Sprintf("%s %s, %s %s", expr, direction, stateKeyExpr, direction)
+	} else {
+		orderBy = stateKeyExpr
+	}
+
+	if opts.Cursor != "" {
+		pos, err := q.ParseCursor(opts.Cursor)
+		if err != nil {
+			return centrifuge.MapStateResult{}, err
+		}
+		if q.Sort != nil {
+			where.WriteString(fmt.Sprintf(" AND (%s, %s) %s (%s::%s, %s)",
+				stateIndexExpr(q.Sort.Index), stateKeyExpr, cmpOp, arg(pos.Value), stateIndexCast(q.Sort.Index), arg(pos.Key)))
+		} else {
+			where.WriteString(fmt.Sprintf(" AND %s > %s", stateKeyExpr, arg(pos.Key)))
+		}
+	}
+
+	stateQuery := fmt.Sprintf(`
+		SELECT key, data, tags, key_offset, client_id, user_id, conn_info, chan_info, updated_at
+		FROM %s
+		WHERE %s
+		ORDER BY %s
+		LIMIT $2
+	`, e.names.state, where.String(), orderBy)
+
+	res, err := e.readStatePage(ctx, pool, ch, opts, stateQuery, args, limit)
+	if err != nil || len(res.Publications) <= limit {
+		return res, err
+	}
+	res.Publications = res.Publications[:limit]
+	last := res.Publications[limit-1]
+	res.Cursor = q.MakeCursor(last.Key, last.Data)
+	return res, nil
+}
@@ -0,0 +1,37 @@
+package pgmapbroker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/centrifugal/centrifugo/v6/internal/mapfilter"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateIndexName(t *testing.T) {
+	e := &PostgresMapBroker{names: newPgNames("cf", false)}
+
+	name := e.stateIndexName(mapfilter.Index{Name: "status", Field: "status"})
+	require.True(t, strings.HasPrefix(name, e.names.state+"_ix_status_"))
+	require.LessOrEqual(t, len(name), maxIdentifierLen)
+
+	e = &PostgresMapBroker{names: newPgNames("very_long_centrifugo_table_prefix_for_tenant", false)}
+	long1 := e.stateIndexName(mapfilter.Index{Name: "order_last_updated_at_first", Field: "order.updated_at"})
+	long2 := e.stateIndexName(mapfilter.Index{Name: "order_last_updated_at_second", Field: "order.updated_at"})
+	require.Len(t, long1, maxIdentifierLen)
+	require.Len(t, long2, maxIdentifierLen)
+	require.NotEqual(t, long1, long2)
+
+	// Same name pointing to different fields gets different index names.
+	require.NotEqual(t,
+		e.stateIndexName(mapfilter.Index{Name: "total", Field: "total", Type: mapfilter.TypeNumber}),
+		e.stateIndexName(mapfilter.Index{Name: "total", Field: "sum.total", Type: mapfilter.TypeNumber}),
+	)
+}
+
+func TestStateIndexExpr(t *testing.T) {
+	// Text values are compared by bytes same as in mapfilter.
+	require.Equal(t, `((data #>> '{order,status}') COLLATE "C")`, stateIndexExpr(mapfilter.Index{Name: "status", Field: "order.status"}))
+	require.NotContains(t, stateIndexExpr(mapfilter.Index{Name: "total", Field: "total", Type: mapfilter.TypeNumber}), "COLLATE")
+}
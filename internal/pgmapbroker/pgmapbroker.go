@@ -1204,7 +1204,7 @@ func (e *PostgresMapBroker) ReadState(ctx context.Context, ch string, opts centr
 	var stateArgs []any
 	if opts.Cursor == "" {
 		stateQuery = fmt.Sprintf(`
-			SELECT key, data, tags, key_offset, client_id, user_id, conn_info, chan_info
+			SELECT key, data, tags, key_offset, client_id, user_id, conn_info, chan_info, updated_at
 			FROM %s
 			WHERE channel = $1
 			ORDER BY key
@@ -1213,7 +1213,7 @@ func (e *PostgresMapBroker) ReadState(ctx context.Context, ch string, opts centr
 		stateArgs = []any{ch, limit + 1}
 	} else {
 		stateQuery = fmt.Sprintf(`
-			SELECT key, data, tags, key_offset, client_id, user_id, conn_info, chan_info
+			SELECT key, data, tags, key_offset, client_id, user_id, conn_info, chan_info, updated_at
 			FROM %s
 			WHERE channel = $1 AND key > $3
 			ORDER BY key
@@ -1222,6 +1222,20 @@ func (e *PostgresMapBroker) ReadState(ctx context.Context, ch string, opts centr
 		stateArgs = []any{ch, limit + 1, opts.Cursor}
 	}
 
+	res, err := e.readStatePage(ctx, pool, ch, opts, stateQuery, stateArgs, limit)
+	if err != nil || len(res.Publications) <= limit {
+		return res, err
+	}
+	res.Publications = res.Publications[:limit]
+	res.Cursor = res.Publications[limit-1].Key
+	return res, nil
+}
+
+// readStatePage runs stateQuery (which must select state columns in the standard
+// order and fetch up to limit+1 rows) together with a meta query in one pipelined
+// REPEATABLE READ batch. Returned result contains all fetched rows (possibly limit+1,
+// callers use the extra row for next-page detection) and no cursor.
+func (e *PostgresMapBroker) readStatePage(ctx context.Context, pool *pgxpool.Pool, ch string, opts centrifuge.MapReadStateOptions, stateQuery string, stateArgs []any, limit int) (centrifuge.MapStateResult, error) {
 	// Pipelined batch: meta + state in a single round trip with REPEATABLE READ.
 	metaQuery := fmt.Sprintf(`SELECT top_offset, epoch FROM %s WHERE channel = $1`, e.names.meta)
 	batch := &pgx.Batch{}
@@ -1241,7 +1255,7 @@ func (e *PostgresMapBroker) ReadState(ctx context.Context, ch string, opts centr
 	// Read meta.
 	var topOffset int64
 	var epoch string
-	err = br.QueryRow().Scan(&topOffset, &epoch)
+	err := br.QueryRow().Scan(&topOffset, &epoch)
 	if errors.Is(err, pgx.ErrNoRows) {
 		_ = br.Close()
 		if opts.Revision != nil && opts.Revision.Epoch != "" {
@@ -1277,7 +1291,8 @@ func (e *PostgresMapBroker) ReadState(ctx context.Context, ch string, opts centr
 	pubs := make([]*centrifuge.Publication, 0, allocHint)
 	// Use RawValues + arena to avoid per-row allocations.
 	// Column order: key(0), data(1), tags(2), key_offset(3),
-	//               client_id(4), user_id(5), conn_info(6), chan_info(7).
+	//               client_id(4), user_id(5), conn_info(6), chan_info(7),
+	//               updated_at(8).
 	var fmts pgColFormats
 	for rows.Next() {
 		if fmts == nil {
@@ -1290,6 +1305,7 @@ func (e *PostgresMapBroker) ReadState(ctx context.Context, ch string, opts centr
 		p.Data = e.rawDataBytes(&arena, raw[1], fmts[1])
 		p.Tags = pgRawJSONBMap(raw[2])
 		p.Offset = pgRawUint64(raw[3], fmts[3])
+		p.Time = pgRawTimestampMillis(raw[8], fmts[8])
 		if raw[4] != nil {
 			p.Info = &centrifuge.ClientInfo{
 				ClientID: pgRawString(&arena, raw[4]),
@@ -1310,13 +1326,7 @@ func (e *PostgresMapBroker) ReadState(ctx context.Context, ch string, opts centr
 		return centrifuge.MapStateResult{}, err
 	}
 
-	var nextCursor string
-	if len(pubs) > limit {
-		pubs = pubs[:limit]
-		nextCursor = pubs[limit-1].Key
-	}
-
-	return centrifuge.MapStateResult{Publications: pubs, Position: streamPos, Cursor: nextCursor}, nil
+	return centrifuge.MapStateResult{Publications: pubs, Position: streamPos}, nil
 }
 
 // readStatePosition returns just the stream position for a channel (no state entries).
@@ -1348,7 +1358,7 @@ func (e *PostgresMapBroker) readStatePosition(ctx context.Context, pool *pgxpool
 func (e *PostgresMapBroker) readStateKey(ctx context.Context, pool *pgxpool.Pool, ch string, opts centrifuge.MapReadStateOptions) (centrifuge.MapStateResult, error) {
 	metaQuery := fmt.Sprintf(`SELECT top_offset, epoch FROM %s WHERE channel = $1`, e.names.meta)
 	keyQuery := fmt.Sprintf(`
-		SELECT key, data, tags, key_offset, client_id, user_id, conn_info, chan_info
+		SELECT key, data, tags, key_offset, client_id, user_id, conn_info, chan_info, updated_at
 		FROM %s
 		WHERE channel = $1 AND key = $2
 	`, e.names.state)
@@ -1395,7 +1405,8 @@ func (e *PostgresMapBroker) readStateKey(ctx context.Context, pool *pgxpool.Pool
 	var tagsJSON []byte
 	var clientID, userID *string
 	var connInfo, chanInfo []byte
-	err = br.QueryRow().Scan(&p.Key, &p.Data, &tagsJSON, &p.Offset, &clientID, &userID, &connInfo, &chanInfo)
+	var updatedAt *time.Time
+	err = br.QueryRow().Scan(&p.Key, &p.Data, &tagsJSON, &p.Offset, &clientID, &userID, &connInfo, &chanInfo, &updatedAt)
 
 	// Consume COMMIT and close batch before processing results.
 	_, _ = br.Exec()
@@ -1410,6 +1421,9 @@ func (e *PostgresMapBroker) readStateKey(ctx context.Context, pool *pgxpool.Pool
 	if len(tagsJSON) > 0 {
 		_ = json.Unmarshal(tagsJSON, &p.Tags)
 	}
+	if updatedAt != nil {
+		p.Time = updatedAt.UnixMilli()
+	}
 	if clientID != nil {
 		p.Info = &centrifuge.ClientInfo{
 			ClientID: *clientID,
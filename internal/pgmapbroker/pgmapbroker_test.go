@@ -6,11 +6,15 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/centrifugal/centrifugo/v6/internal/mapfilter"
+	"github.com/centrifugal/centrifugo/v6/internal/mapsnapshot"
+
 	"github.com/centrifugal/centrifuge"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/prometheus/client_golang/prometheus"
@@ -286,6 +290,246 @@ func TestPostgresMapBroker_StatePagination(t *testing.T) {
 	require.Len(t, allKeys, 10)
 }
 
+// TestPostgresMapBroker_FilteredState tests filtered and sorted state reads over JSONB data.
+func TestPostgresMapBroker_FilteredState(t *testing.T) {
+	node, _ := centrifuge.New(centrifuge.Config{
+		Map: centrifuge.MapConfig{
+			GetMapChannelOptions: func(channel string) centrifuge.MapChannelOptions {
+				return centrifuge.MapChannelOptions{
+					Mode:   centrifuge.MapModeRecoverable,
+					KeyTTL: 60 * time.Second,
+				}
+			},
+		},
+	})
+	broker, err := NewPostgresMapBroker(node, PostgresMapBrokerConfig{
+		DSN:       getPostgresConnString(t),
+		NumShards: 4,
+		Outbox: OutboxConfig{
+			PollInterval: 10 * time.Millisecond,
+		},
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, broker.EnsureSchema(ctx))
+	cleanupTestTables(ctx, broker)
+	require.NoError(t, broker.RegisterEventHandler(nil))
+	t.Cleanup(func() {
+		_ = broker.Close(ctx)
+		_ = node.Shutdown(ctx)
+	})
+
+	indexes := []mapfilter.Index{
+		{Name: "status", Field: "status"},
+		{Name: "total", Field: "order.total", Type: mapfilter.TypeNumber},
+	}
+	require.NoError(t, broker.EnsureStateIndexes(ctx, indexes))
+	// Repeated call is a no-op.
+	require.NoError(t, broker.EnsureStateIndexes(ctx, indexes))
+
+	channel := "test_filtered_state"
+	for i := 0; i < 10; i++ {
+		status := "open"
+		if i%2 == 1 {
+			status = "closed"
+		}
+		_, err := broker.Publish(ctx, channel, fmt.Sprintf("key%d", i), centrifuge.MapPublishOptions{
+			Data: []byte(fmt.Sprintf(`{"status":%q,"order":{"total":%d}}`, status, 100-i*10)),
+		})
+		require.NoError(t, err)
+	}
+	// Entry without total is skipped when sorting by total.
+	_, err = broker.Publish(ctx, channel, "nototal", centrifuge.MapPublishOptions{
+		Data: []byte(`{"status":"open"}`),
+	})
+	require.NoError(t, err)
+
+	q, err := mapfilter.Parse(`status = "open" AND total > 20`, "total asc", indexes)
+	require.NoError(t, err)
+
+	var keys []string
+	var cursor string
+	for {
+		res, err := broker.ReadFilteredState(ctx, channel, q, centrifuge.MapReadStateOptions{
+			Limit:  2,
+			Cursor: cursor,
+		})
+		require.NoError(t, err)
+		require.NotZero(t, res.Position.Offset)
+		for _, pub := range res.Publications {
+			keys = append(keys, pub.Key)
+		}
+		if res.Cursor == "" {
+			break
+		}
+		cursor = res.Cursor
+	}
+	// key0=100, key2=80, key4=60, key6=40 match; ascending by total.
+	require.Equal(t, []string{"key6", "key4", "key2", "key0"}, keys)
+
+	q, err = mapfilter.Parse(`status = closed`, "", indexes)
+	require.NoError(t, err)
+	res, err := broker.ReadFilteredState(ctx, channel, q, centrifuge.MapReadStateOptions{Limit: -1})
+	require.NoError(t, err)
+	require.Len(t, res.Publications, 5)
+	require.Empty(t, res.Cursor)
+
+	binaryBroker := newTestPostgresMapBroker(t, node)
+	_, err = binaryBroker.ReadFilteredState(ctx, channel, q, centrifuge.MapReadStateOptions{Limit: 10})
+	require.ErrorIs(t, err, mapfilter.ErrNotSupported)
+}
+
+// TestPostgresMapBroker_FilteredStateByteOrder tests that text fields are compared
+// and sorted by bytes, same as in-memory filtering does, regardless of database
+// collation.
+func TestPostgresMapBroker_FilteredStateByteOrder(t *testing.T) {
+	node, _ := centrifuge.New(centrifuge.Config{
+		Map: centrifuge.MapConfig{
+			GetMapChannelOptions: func(channel string) centrifuge.MapChannelOptions {
+				return centrifuge.MapChannelOptions{
+					Mode:   centrifuge.MapModeRecoverable,
+					KeyTTL: 60 * time.Second,
+				}
+			},
+		},
+	})
+	broker, err := NewPostgresMapBroker(node, PostgresMapBrokerConfig{
+		DSN:       getPostgresConnString(t),
+		NumShards: 4,
+		Outbox: OutboxConfig{
+			PollInterval: 10 * time.Millisecond,
+		},
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, broker.EnsureSchema(ctx))
+	cleanupTestTables(ctx, broker)
+	require.NoError(t, broker.RegisterEventHandler(nil))
+	t.Cleanup(func() {
+		_ = broker.Close(ctx)
+		_ = node.Shutdown(ctx)
+	})
+
+	indexes := []mapfilter.Index{{Name: "status", Field: "status"}}
+	require.NoError(t, broker.EnsureStateIndexes(ctx, indexes))
+
+	channel := "test_filtered_state_byte_order"
+	data := map[string]string{
+		"a": "b", "B": "B", "c": "a", "D": "A", "e": "Zed", "F": "apple", "g": "B",
+	}
+	for key, status := range data {
+		_, err := broker.Publish(ctx, channel, key, centrifuge.MapPublishOptions{
+			Data: []byte(fmt.Sprintf(`{"status":%q}`, status)),
+		})
+		require.NoError(t, err)
+	}
+
+	for _, tc := range []struct {
+		filter   string
+		sort     string
+		expected []string
+	}{
+		{filter: `status < "b"`, sort: "status asc", expected: []string{"D", "B", "g", "e", "c", "F"}},
+		{filter: `status >= "a"`, sort: "status desc", expected: []string{"a", "F", "c"}},
+		{filter: "", sort: "", expected: []string{"B", "D", "F", "a", "c", "e", "g"}},
+	} {
+		q, err := mapfilter.Parse(tc.filter, tc.sort, indexes)
+		require.NoError(t, err)
+
+		// Expected order is the same as of in-memory filtering.
+		var inMemory []string
+		for key, status := range data {
+			if q.Match([]byte(fmt.Sprintf(`{"status":%q}`, status))) {
+				inMemory = append(inMemory, key)
+			}
+		}
+		sort.Slice(inMemory, func(i, j int) bool {
+			return q.Compare(
+				inMemory[i], []byte(fmt.Sprintf(`{"status":%q}`, data[inMemory[i]])),
+				inMemory[j], []byte(fmt.Sprintf(`{"status":%q}`, data[inMemory[j]])),
+			) < 0
+		})
+		require.Equal(t, tc.expected, inMemory, tc.filter)
+
+		var keys []string
+		var cursor string
+		for {
+			res, err := broker.ReadFilteredState(ctx, channel, q, centrifuge.MapReadStateOptions{
+				Limit:  2,
+				Cursor: cursor,
+			})
+			require.NoError(t, err)
+			for _, pub := range res.Publications {
+				keys = append(keys, pub.Key)
+			}
+			if res.Cursor == "" {
+				break
+			}
+			cursor = res.Cursor
+		}
+		require.Equal(t, tc.expected, keys, tc.filter)
+	}
+}
+
+// TestPostgresMapBroker_ImportSnapshot tests that imported state keeps stream position.
+func TestPostgresMapBroker_ImportSnapshot(t *testing.T) {
+	node, _ := centrifuge.New(centrifuge.Config{
+		Map: centrifuge.MapConfig{
+			GetMapChannelOptions: func(channel string) centrifuge.MapChannelOptions {
+				return centrifuge.MapChannelOptions{
+					Mode: centrifuge.MapModePersistent,
+				}
+			},
+		},
+	})
+	broker := newTestPostgresMapBroker(t, node)
+
+	ctx := context.Background()
+	source := "test_snapshot_source"
+	target := "test_snapshot_target"
+
+	for i := 0; i < 5; i++ {
+		_, err := broker.Publish(ctx, source, fmt.Sprintf("key%d", i), centrifuge.MapPublishOptions{
+			Data: []byte(fmt.Sprintf("data%d", i)),
+			Tags: map[string]string{"i": strconv.Itoa(i)},
+		})
+		require.NoError(t, err)
+	}
+	exported, err := broker.ReadState(ctx, source, centrifuge.MapReadStateOptions{Limit: -1})
+	require.NoError(t, err)
+	require.Len(t, exported.Publications, 5)
+
+	err = broker.ImportSnapshot(ctx, target, exported.Position, exported.Publications, false)
+	require.NoError(t, err)
+
+	imported, err := broker.ReadState(ctx, target, centrifuge.MapReadStateOptions{Limit: -1})
+	require.NoError(t, err)
+	require.Equal(t, exported.Position, imported.Position)
+	require.Len(t, imported.Publications, 5)
+	for i, pub := range imported.Publications {
+		require.Equal(t, exported.Publications[i].Key, pub.Key)
+		require.Equal(t, exported.Publications[i].Data, pub.Data)
+		require.Equal(t, exported.Publications[i].Offset, pub.Offset)
+		require.Equal(t, exported.Publications[i].Tags, pub.Tags)
+	}
+
+	// Next publication continues the imported stream.
+	res, err := broker.Publish(ctx, target, "key5", centrifuge.MapPublishOptions{Data: []byte("data5")})
+	require.NoError(t, err)
+	require.Equal(t, exported.Position.Epoch, res.Position.Epoch)
+	require.Equal(t, exported.Position.Offset+1, res.Position.Offset)
+
+	err = broker.ImportSnapshot(ctx, target, exported.Position, exported.Publications, false)
+	require.ErrorIs(t, err, mapsnapshot.ErrChannelNotEmpty)
+
+	err = broker.ImportSnapshot(ctx, target, exported.Position, exported.Publications[:2], true)
+	require.NoError(t, err)
+	imported, err = broker.ReadState(ctx, target, centrifuge.MapReadStateOptions{Limit: -1})
+	require.NoError(t, err)
+	require.Equal(t, exported.Position, imported.Position)
+	require.Len(t, imported.Publications, 2)
+}
+
 // TestPostgresMapBroker_StreamRecovery tests stream recovery.
 func TestPostgresMapBroker_StreamRecovery(t *testing.T) {
 	node, _ := centrifuge.New(centrifuge.Config{
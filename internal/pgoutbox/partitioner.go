@@ -37,6 +37,9 @@ func isShutdownErr(err error) bool {
 //     logged via ErrorFn rather than returned, so a failed drop does not
 //     prevent the cleanup pass from attempting the remaining partitions.
 //
+//     When BeforeDrop is set, it is called before each drop and a
+//     partition is kept if it returns an error (retried on next pass).
+//
 //   - Run starts a ticker that calls both methods on each tick.
 //
 // Partition naming convention: {ParentTable}_{YYYY}_{MM}_{DD}. Partitions
@@ -64,6 +67,12 @@ type Partitioner struct {
 	// (now - RetentionDays) are dropped.
 	RetentionDays int
 
+	// BeforeDrop is an optional hook called before DropOldPartitions drops a
+	// partition, with the partition's schema, name and UTC day. If it returns
+	// an error the partition is not dropped, so the hook may e.g. archive
+	// partition rows which must not be lost.
+	BeforeDrop func(ctx context.Context, schema, name string, day time.Time) error
+
 	// ErrorFn is called for non-ctx errors that do not abort the pass
 	// (e.g. per-partition drop failures, listing failures in the
 	// cleanup path). Must be safe for concurrent use.
@@ -156,6 +165,14 @@ func (p *Partitioner) DropOldPartitions(ctx context.Context) {
 		}
 		if partDate.Before(cutoff) {
 			qualified := quoteIdent(pt.schema) + "." + quoteIdent(pt.name)
+			if p.BeforeDrop != nil {
+				if err := p.BeforeDrop(ctx, pt.schema, pt.name, partDate); err != nil {
+					if !isShutdownErr(err) {
+						p.ErrorFn("error before dropping old partition "+qualified+", partition kept", err)
+					}
+					continue
+				}
+			}
 			if _, err := p.Pool.Exec(ctx, "DROP TABLE IF EXISTS "+qualified); err != nil {
 				if !isShutdownErr(err) {
 					p.ErrorFn("error dropping old partition "+qualified, err)
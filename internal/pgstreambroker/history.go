@@ -7,6 +7,8 @@ import (
 	"math"
 	"time"
 
+	"github.com/centrifugal/centrifugo/v6/internal/historyquery"
+
 	"github.com/centrifugal/centrifuge"
 	"github.com/jackc/pgx/v5"
 )
@@ -23,6 +25,19 @@ import (
 // returns an empty result with a zero StreamPosition — matching the map
 // broker's behavior on ErrNoRows.
 func (e *PostgresStreamBroker) History(ch string, opts centrifuge.HistoryOptions) ([]*centrifuge.Publication, centrifuge.StreamPosition, error) {
+	return e.history(ch, opts, historyquery.TimeRange{})
+}
+
+var _ historyquery.RangeReader = (*PostgresStreamBroker)(nil)
+
+// HistoryRange is the same as History but only returns publications created
+// within time range. The range is applied to created_at column, so partitions
+// outside of it are pruned by Postgres.
+func (e *PostgresStreamBroker) HistoryRange(ch string, opts centrifuge.HistoryOptions, tr historyquery.TimeRange) ([]*centrifuge.Publication, centrifuge.StreamPosition, error) {
+	return e.history(ch, opts, tr)
+}
+
+func (e *PostgresStreamBroker) history(ch string, opts centrifuge.HistoryOptions, tr historyquery.TimeRange) ([]*centrifuge.Publication, centrifuge.StreamPosition, error) {
 	ctx := context.Background()
 	pool := e.getReadPool(ch, true) // can use replicas — this is a pure read
 
@@ -65,13 +80,20 @@ func (e *PostgresStreamBroker) History(ch string, opts centrifuge.HistoryOptions
 			windowStart = topOffset - int64(*historySize) + 1
 		}
 	}
-	sinceOffset := int64(0)
+	effectiveStart := windowStart
+	effectiveEnd := topOffset
 	if opts.Filter.Since != nil {
-		sinceOffset = int64(opts.Filter.Since.Offset)
-	}
-	effectiveStart := sinceOffset + 1
-	if windowStart > effectiveStart {
-		effectiveStart = windowStart
+		sinceOffset := int64(opts.Filter.Since.Offset)
+		if opts.Filter.Reverse {
+			// Reverse iteration since position returns publications older than
+			// position – same as Centrifuge memory and Redis brokers do. Zero
+			// offset means iterating from the top of the stream.
+			if sinceOffset > 0 {
+				effectiveEnd = min(effectiveEnd, sinceOffset-1)
+			}
+		} else {
+			effectiveStart = max(effectiveStart, sinceOffset+1)
+		}
 	}
 
 	effectiveLimit := math.MaxInt32
@@ -102,20 +124,33 @@ func (e *PostgresStreamBroker) History(ch string, opts centrifuge.HistoryOptions
 	// publish and the read — the new wider cutoff lets old rows through.
 	// The new (channel, epoch, channel_offset) WHERE kind=0 partial index
 	// resolves the predicate without scanning dead-epoch rows.
+	//
+	// Time range predicates are only added when set: unbounded reads keep
+	// their plan, bounded reads let Postgres prune created_at partitions.
+	args := []any{ch, effectiveStart, effectiveEnd, cutoff, epoch, effectiveLimit}
+	var rangeFilter string
+	if tr.From != 0 {
+		args = append(args, time.UnixMilli(tr.From))
+		rangeFilter += fmt.Sprintf(" AND created_at >= $%d", len(args))
+	}
+	if tr.To != 0 {
+		args = append(args, time.UnixMilli(tr.To))
+		rangeFilter += fmt.Sprintf(" AND created_at < $%d", len(args))
+	}
 	rowsQuery := fmt.Sprintf(`
-		SELECT channel_offset, epoch, data, tags, client_id, user_id, conn_info, chan_info, prev_data
+		SELECT channel_offset, epoch, data, tags, client_id, user_id, conn_info, chan_info, prev_data, created_at
 		  FROM %s
 		 WHERE channel = $1
 		   AND kind = 0
 		   AND channel_offset >= $2
 		   AND channel_offset <= $3
 		   AND created_at > $4
-		   AND epoch = $5
+		   AND epoch = $5%s
 		 ORDER BY channel_offset %s
 		 LIMIT $6
-	`, e.names.stream, order)
+	`, e.names.stream, rangeFilter, order)
 
-	rows, err := pool.Query(ctx, rowsQuery, ch, effectiveStart, topOffset, cutoff, epoch, effectiveLimit)
+	rows, err := pool.Query(ctx, rowsQuery, args...)
 	if err != nil {
 		return nil, centrifuge.StreamPosition{}, fmt.Errorf("postgres stream broker: history query: %w", err)
 	}
@@ -150,6 +185,7 @@ func (e *PostgresStreamBroker) History(ch string, opts centrifuge.HistoryOptions
 			}
 		}
 		_ = raw[8] // prev_data — used by outbox, not by History
+		p.Time = pgRawTimestampMillis(raw[9], fmts[9])
 		pubs = append(pubs, p)
 	}
 	if err := rows.Err(); err != nil {
@@ -0,0 +1,21 @@
+-- Migration to schema version 2: scheduled publications table.
+-- Placeholders: __PREFIX__ → e.g. "cf_stream_" (includes trailing underscore).
+-- Keep in sync with schema.sql.
+CREATE TABLE IF NOT EXISTS __PREFIX__scheduled (
+    id              TEXT PRIMARY KEY,
+    method          TEXT NOT NULL,
+    channels        TEXT[] NOT NULL,
+    payload         BYTEA NOT NULL,
+    publish_at      BIGINT NOT NULL,
+    created_at      BIGINT NOT NULL,
+    claimed_until   BIGINT
+);
+
+CREATE INDEX IF NOT EXISTS __PREFIX__scheduled_publish_at_idx
+    ON __PREFIX__scheduled (publish_at, id) WHERE claimed_until IS NULL;
+
+CREATE INDEX IF NOT EXISTS __PREFIX__scheduled_claimed_until_idx
+    ON __PREFIX__scheduled (claimed_until) WHERE claimed_until IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS __PREFIX__scheduled_channels_idx
+    ON __PREFIX__scheduled USING GIN (channels);
@@ -157,6 +157,35 @@ CREATE TABLE IF NOT EXISTS __PREFIX__shard_lock (
     shard_id SMALLINT PRIMARY KEY
 );
 
+-- ============================================================================
+-- Scheduled Publications Table
+--
+-- Keeps publications sent over server API with publish_at or delay until they
+-- are due. Times are Unix milliseconds. A node firing due publications sets
+-- claimed_until; rows claimed by a node which did not complete them become
+-- claimable again after claimed_until passes. Rows are deleted once fired.
+-- payload is an encoded original API request.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS __PREFIX__scheduled (
+    id              TEXT PRIMARY KEY,
+    method          TEXT NOT NULL,
+    channels        TEXT[] NOT NULL,
+    payload         BYTEA NOT NULL,
+    publish_at      BIGINT NOT NULL,
+    created_at      BIGINT NOT NULL,
+    claimed_until   BIGINT
+);
+
+CREATE INDEX IF NOT EXISTS __PREFIX__scheduled_publish_at_idx
+    ON __PREFIX__scheduled (publish_at, id) WHERE claimed_until IS NULL;
+
+CREATE INDEX IF NOT EXISTS __PREFIX__scheduled_claimed_until_idx
+    ON __PREFIX__scheduled (claimed_until) WHERE claimed_until IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS __PREFIX__scheduled_channels_idx
+    ON __PREFIX__scheduled USING GIN (channels);
+
 -- ============================================================================
 -- Schema Version
 -- ============================================================================
@@ -6,8 +6,12 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/centrifugal/centrifugo/v6/internal/historyarchive"
 	"github.com/centrifugal/centrifugo/v6/internal/metrics"
 	"github.com/centrifugal/centrifugo/v6/internal/pgoutbox"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/jackc/pgx/v5"
 )
 
 // isShutdownErr reports whether err is a normal-shutdown signal — context
@@ -27,10 +31,56 @@ func (e *PostgresStreamBroker) newPartitioner() *pgoutbox.Partitioner {
 		CleanupInterval: e.conf.CleanupInterval,
 		LookaheadDays:   e.conf.PartitionLookaheadDays,
 		RetentionDays:   e.conf.PartitionRetentionDays,
+		BeforeDrop:      e.beforePartitionDrop,
 		ErrorFn:         e.logErrorMsg,
 	}
 }
 
+// beforePartitionDrop archives publications of partition with configured
+// PartitionArchiver, so retention drop does not lose them.
+func (e *PostgresStreamBroker) beforePartitionDrop(ctx context.Context, schema, name string, day time.Time) error {
+	if e.conf.PartitionArchiver == nil {
+		return nil
+	}
+	qualified := pgx.Identifier{schema, name}.Sanitize()
+	return e.conf.PartitionArchiver.ArchivePartition(ctx, name, day, func(write func(historyarchive.Record) error) error {
+		rows, err := e.pool.Query(ctx, fmt.Sprintf(`
+			SELECT channel, channel_offset, epoch, data, tags, client_id, user_id, created_at
+			  FROM %s
+			 WHERE kind = 0
+			 ORDER BY id
+		`, qualified))
+		if err != nil {
+			return fmt.Errorf("postgres stream broker: partition archive query: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var (
+				ch               string
+				offset           int64
+				pub              centrifuge.Publication
+				clientID, userID *string
+				createdAt        time.Time
+			)
+			if err := rows.Scan(&ch, &offset, &pub.Epoch, &pub.Data, &pub.Tags, &clientID, &userID, &createdAt); err != nil {
+				return fmt.Errorf("postgres stream broker: partition archive scan: %w", err)
+			}
+			pub.Offset = uint64(offset)
+			pub.Time = createdAt.UnixMilli()
+			if clientID != nil {
+				pub.Info = &centrifuge.ClientInfo{ClientID: *clientID}
+				if userID != nil {
+					pub.Info.UserID = *userID
+				}
+			}
+			if err := write(historyarchive.NewRecord(ch, &pub)); err != nil {
+				return err
+			}
+		}
+		return rows.Err()
+	})
+}
+
 // runPartitionWorker manages partition lookahead creation and retention drops.
 func (e *PostgresStreamBroker) runPartitionWorker() {
 	p := e.newPartitioner()
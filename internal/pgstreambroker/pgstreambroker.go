@@ -31,6 +31,7 @@ import (
 	"time"
 
 	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/historyarchive"
 	"github.com/centrifugal/centrifugo/v6/internal/pgschema"
 
 	"github.com/centrifugal/centrifuge"
@@ -44,15 +45,22 @@ import (
 var postgresSchemaTemplate string
 
 // schemaVersion is the current schema version. Bump when adding migrations.
-var schemaVersion = 1
+var schemaVersion = 2
+
+//go:embed internal/sql/migration_v2.sql
+var migrationV2 string
 
 // schemaMigrations maps target version to a migration SQL TEMPLATE using the
 // same placeholders as `schema.sql` (`__PREFIX__`, `__DATA_TYPE__`,
 // `__STREAM_TABLE__`). EnsureSchema renders the template once per variant
 // (jsonb + binary) and runs both rendered SQLs in a single transaction with
 // atomic schema_version bumps across both prefix tables — see
-// pgmapbroker.schemaMigrations for the full contract. Empty for v1.
-var schemaMigrations = map[int]string{}
+// pgmapbroker.schemaMigrations for the full contract. Version 1 is the
+// baseline applied via full DDL, so schema.sql must reflect the end state
+// of all migrations.
+var schemaMigrations = map[int]string{
+	2: migrationV2,
+}
 
 func init() {
 	pgschema.ValidateMigrationMap("pgstreambroker", schemaVersion, schemaMigrations)
@@ -129,6 +137,7 @@ func (e *SchemaError) Unwrap() error {
 // prefix (jsonbPrefix when BinaryData is false, binaryPrefix when true).
 type pgNames struct {
 	stream, meta, idempotency, shardLock, schemaVersion string // table names (active variant)
+	scheduled                                           string
 	publish, publishStrict, publishJoin, publishLeave   string // function names (active variant)
 	removeHistory                                       string
 	notifyChannel                                       string
@@ -150,6 +159,7 @@ func newPgNames(userPrefix string, binary bool) pgNames {
 		idempotency:   p + "idempotency",
 		shardLock:     p + "shard_lock",
 		schemaVersion: p + "schema_version",
+		scheduled:     p + "scheduled",
 		publish:       p + "publish",
 		publishStrict: p + "publish_strict",
 		publishJoin:   p + "publish_join",
@@ -300,6 +310,17 @@ type PostgresStreamBrokerConfig struct {
 	// Go-level construction (e.g. tests) must set this explicitly — there
 	// is no implicit default in setDefaults so that 0 (unlimited) survives.
 	PartitionRetentionDays int
+
+	// PartitionArchiver, when set, archives publications of each partition
+	// before the partition retention worker drops it. A partition which
+	// failed to be archived is kept and retried on the next worker tick.
+	PartitionArchiver PartitionArchiver
+}
+
+// PartitionArchiver archives publications of a daily history partition.
+// historyarchive.Archiver implements it.
+type PartitionArchiver interface {
+	ArchivePartition(ctx context.Context, partition string, day time.Time, read func(write func(historyarchive.Record) error) error) error
 }
 
 func (c *PostgresStreamBrokerConfig) setDefaults() {
@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/centrifugal/centrifugo/v6/internal/historyquery"
 	"github.com/centrifugal/centrifugo/v6/internal/metrics"
 
 	"github.com/centrifugal/centrifuge"
@@ -305,6 +306,57 @@ func TestPostgresStreamBroker_HistorySizeClampOnRead(t *testing.T) {
 	require.Equal(t, uint64(total-sizeLimit+1), pubs[sizeLimit-1].Offset)
 }
 
+// TestPostgresStreamBroker_HistoryRange verifies that HistoryRange only returns
+// publications created within time range, sets publication time, and that
+// reverse iteration since position returns older publications.
+func TestPostgresStreamBroker_HistoryRange(t *testing.T) {
+	e, _, _ := newTestPostgresStreamBroker(t)
+	ctx := context.Background()
+
+	channel := "test_history_range"
+	const n = 6
+	for i := 0; i < n; i++ {
+		_, err := e.Publish(channel, []byte(fmt.Sprintf(`{"i":%d}`, i)), centrifuge.PublishOptions{
+			HistoryTTL:  10 * time.Minute,
+			HistorySize: 10,
+		})
+		require.NoError(t, err)
+	}
+	// Spread publications over time: offset N is created N minutes ago.
+	_, err := e.pool.Exec(ctx, fmt.Sprintf(
+		`UPDATE %s SET created_at = NOW() - make_interval(mins => (%d - channel_offset)::int) WHERE channel = $1 AND kind = 0`,
+		e.names.stream, n+1), channel)
+	require.NoError(t, err)
+
+	now := time.Now()
+	tr := historyquery.TimeRange{
+		From: now.Add(-5*time.Minute - 30*time.Second).UnixMilli(),
+		To:   now.Add(-2*time.Minute - 30*time.Second).UnixMilli(),
+	}
+	pubs, sp, err := e.HistoryRange(channel, centrifuge.HistoryOptions{
+		Filter: centrifuge.HistoryFilter{Limit: -1},
+	}, tr)
+	require.NoError(t, err)
+	require.Equal(t, uint64(n), sp.Offset)
+	require.Len(t, pubs, 3)
+	for i, p := range pubs {
+		require.Equal(t, uint64(i+2), p.Offset)
+		require.True(t, tr.Contains(p.Time))
+	}
+
+	pubs, _, err = e.HistoryRange(channel, centrifuge.HistoryOptions{
+		Filter: centrifuge.HistoryFilter{
+			Since:   &centrifuge.StreamPosition{Offset: 4, Epoch: sp.Epoch},
+			Limit:   -1,
+			Reverse: true,
+		},
+	}, tr)
+	require.NoError(t, err)
+	require.Len(t, pubs, 2)
+	require.Equal(t, uint64(3), pubs[0].Offset)
+	require.Equal(t, uint64(2), pubs[1].Offset)
+}
+
 // TestPostgresStreamBroker_RemoveHistory verifies that RemoveHistory wipes
 // publications and a subsequent History() returns empty rows but a non-zero
 // position (because the meta is preserved).
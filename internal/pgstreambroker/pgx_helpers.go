@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"strconv"
+	"time"
 
 	"github.com/centrifugal/centrifugo/v6/internal/convert"
 )
@@ -94,6 +95,28 @@ func pgRawInt16(b []byte, format int16) int16 {
 	return int16(binary.BigEndian.Uint16(b))
 }
 
+// pgEpochDeltaUsec is the difference in microseconds between the Unix epoch
+// (1970-01-01) and the PostgreSQL epoch (2000-01-01).
+const pgEpochDeltaUsec = 946684800_000_000
+
+// pgRawTimestampMillis parses a pgx TIMESTAMPTZ value as Unix milliseconds.
+// Binary wire format: 8-byte big-endian int64 = microseconds since PG epoch (2000-01-01 UTC).
+func pgRawTimestampMillis(b []byte, format int16) int64 {
+	if b == nil {
+		return 0
+	}
+	if format == pgTextFormat {
+		// PostgreSQL text format for timestamptz, e.g. "2025-06-15 12:34:56.123456+00"
+		t, err := time.Parse("2006-01-02 15:04:05.999999Z07:00:00", convert.BytesToString(b))
+		if err != nil {
+			return 0
+		}
+		return t.UnixMilli()
+	}
+	usec := int64(binary.BigEndian.Uint64(b))
+	return (usec + pgEpochDeltaUsec) / 1000
+}
+
 // pgRawString copies a pgx text value into the arena and returns the resulting
 // string. TEXT/VARCHAR have identical representation in both binary and text wire
 // formats, so no format parameter is needed. Returns "" for nil input.
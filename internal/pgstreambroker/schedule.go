@@ -0,0 +1,125 @@
+package pgstreambroker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/schedule"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ScheduleStore is a scheduled publications store over tables of PostgresStreamBroker,
+// so that scheduled publications do not require a separate Redis setup.
+type ScheduleStore struct {
+	pool  *pgxpool.Pool
+	names pgNames
+}
+
+var _ schedule.Store = (*ScheduleStore)(nil)
+
+// ScheduleStore returns scheduled publications store sharing connection pool and
+// schema with broker.
+func (e *PostgresStreamBroker) ScheduleStore() *ScheduleStore {
+	return &ScheduleStore{pool: e.pool, names: e.names}
+}
+
+// Add ...
+func (s *ScheduleStore) Add(ctx context.Context, item schedule.Item) error {
+	_, err := s.pool.Exec(ctx, fmt.Sprintf(`
+		INSERT INTO %s (id, method, channels, payload, publish_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, s.names.scheduled), item.ID, item.Method, item.Channels, item.Payload, item.PublishAt, item.CreatedAt)
+	return err
+}
+
+// Get ...
+func (s *ScheduleStore) Get(ctx context.Context, id string) (schedule.Item, bool, error) {
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(
+		`SELECT id, method, channels, payload, publish_at, created_at FROM %s WHERE id = $1`,
+		s.names.scheduled), id)
+	if err != nil {
+		return schedule.Item{}, false, err
+	}
+	item, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByPos[schedule.Item])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return schedule.Item{}, false, nil
+	}
+	if err != nil {
+		return schedule.Item{}, false, err
+	}
+	return item, true, nil
+}
+
+// List ...
+func (s *ScheduleStore) List(ctx context.Context, opts schedule.ListOptions) ([]schedule.Item, string, error) {
+	publishAt, id, err := schedule.ParseCursor(opts.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	// Request one more item to know whether there are more items to list.
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`
+		SELECT id, method, channels, payload, publish_at, created_at FROM %s
+		WHERE claimed_until IS NULL AND (publish_at, id) > ($1, $2) AND ($3 = '' OR channels @> ARRAY[$3])
+		ORDER BY publish_at, id
+		LIMIT $4
+	`, s.names.scheduled), publishAt, id, opts.Channel, opts.Limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByPos[schedule.Item])
+	if err != nil {
+		return nil, "", err
+	}
+	if len(items) <= opts.Limit {
+		return items, "", nil
+	}
+	items = items[:opts.Limit]
+	return items, schedule.FormatCursor(items[len(items)-1]), nil
+}
+
+// Cancel ...
+func (s *ScheduleStore) Cancel(ctx context.Context, id string) (bool, error) {
+	tag, err := s.pool.Exec(ctx, fmt.Sprintf(
+		`DELETE FROM %s WHERE id = $1 AND claimed_until IS NULL`,
+		s.names.scheduled), id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Claim ...
+func (s *ScheduleStore) Claim(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]schedule.Item, error) {
+	// SKIP LOCKED lets nodes claim different rows concurrently instead of
+	// waiting for each other.
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`
+		UPDATE %[1]s SET claimed_until = $2
+		WHERE id IN (
+			SELECT id FROM %[1]s
+			WHERE (claimed_until IS NULL AND publish_at <= $1) OR claimed_until <= $1
+			ORDER BY publish_at, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, method, channels, payload, publish_at, created_at
+	`, s.names.scheduled), now.UnixMilli(), now.Add(lease).UnixMilli(), limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[schedule.Item])
+}
+
+// Complete ...
+func (s *ScheduleStore) Complete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := s.pool.Exec(ctx, fmt.Sprintf(
+		`DELETE FROM %s WHERE id = ANY($1::text[])`,
+		s.names.scheduled), ids)
+	return err
+}
@@ -0,0 +1,14 @@
+package proxy
+
+import (
+	"context"
+
+	"github.com/centrifugal/centrifugo/v6/internal/proxyproto"
+)
+
+// CacheEmptyProxy allows to send NotifyCacheEmpty requests.
+type CacheEmptyProxy interface {
+	NotifyCacheEmpty(context.Context, *proxyproto.NotifyCacheEmptyRequest) (*proxyproto.NotifyCacheEmptyResponse, error)
+	// Protocol for metrics and logging.
+	Protocol() string
+}
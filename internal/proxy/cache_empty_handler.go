@@ -0,0 +1,119 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/config"
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+	"github.com/centrifugal/centrifugo/v6/internal/proxyproto"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/singleflight"
+)
+
+// CacheEmptyHandlerConfig ...
+type CacheEmptyHandlerConfig struct {
+	Proxy CacheEmptyProxy
+	Name  string
+	// Timeout bounds the backend request. Zero means DefaultCacheEmptyTimeout.
+	Timeout time.Duration
+}
+
+// DefaultCacheEmptyTimeout is used when CacheEmptyHandlerConfig.Timeout is not set.
+const DefaultCacheEmptyTimeout = time.Second
+
+// CacheEmptyHandler ...
+type CacheEmptyHandler struct {
+	proxy     CacheEmptyProxy
+	timeout   time.Duration
+	summary   prometheus.Observer
+	histogram prometheus.Observer
+	errors    prometheus.Counter
+	group     singleflight.Group
+}
+
+// NewCacheEmptyHandler ...
+func NewCacheEmptyHandler(c CacheEmptyHandlerConfig) *CacheEmptyHandler {
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = DefaultCacheEmptyTimeout
+	}
+	return &CacheEmptyHandler{
+		proxy:     c.Proxy,
+		timeout:   timeout,
+		summary:   metrics.ProxyCallDurationSummary.WithLabelValues(c.Proxy.Protocol(), "cache_empty", c.Name),
+		histogram: metrics.ProxyCallDurationHistogram.WithLabelValues(c.Proxy.Protocol(), "cache_empty", c.Name),
+		errors:    metrics.ProxyCallErrorCount.WithLabelValues(c.Proxy.Protocol(), "cache_empty", c.Name),
+	}
+}
+
+// CacheEmptyHandlerFunc ...
+type CacheEmptyHandlerFunc func(centrifuge.CacheEmptyEvent, configtypes.ChannelOptions) (centrifuge.CacheEmptyReply, error)
+
+// Handle CacheEmpty. Concurrent calls for the same channel (many subscribers
+// coming to a cold channel at once) share a single backend request.
+func (h *CacheEmptyHandler) Handle(node *centrifuge.Node) CacheEmptyHandlerFunc {
+	return func(e centrifuge.CacheEmptyEvent, chOpts configtypes.ChannelOptions) (centrifuge.CacheEmptyReply, error) {
+		v, err, _ := h.group.Do(e.Channel, func() (any, error) {
+			return h.loadCache(node, e.Channel, chOpts)
+		})
+		if err != nil {
+			return centrifuge.CacheEmptyReply{}, err
+		}
+		return v.(centrifuge.CacheEmptyReply), nil
+	}
+}
+
+func (h *CacheEmptyHandler) loadCache(node *centrifuge.Node, channel string, chOpts configtypes.ChannelOptions) (centrifuge.CacheEmptyReply, error) {
+	// Request is shared by all subscribers waiting for the channel, so it's not
+	// bound to a context of any of them.
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+	started := time.Now()
+	resp, err := h.proxy.NotifyCacheEmpty(ctx, &proxyproto.NotifyCacheEmptyRequest{
+		Channel: channel,
+	})
+	duration := time.Since(started).Seconds()
+	h.summary.Observe(duration)
+	h.histogram.Observe(duration)
+	if err != nil {
+		h.errors.Inc()
+		log.Error().Err(err).Str("channel", channel).Msg("error proxying cache empty")
+		return centrifuge.CacheEmptyReply{}, err
+	}
+	if resp.Error != nil {
+		h.errors.Inc()
+		return centrifuge.CacheEmptyReply{}, errors.New(resp.Error.Message)
+	}
+	if resp.Result == nil {
+		return centrifuge.CacheEmptyReply{}, nil
+	}
+	if pub := resp.Result.Publication; pub != nil {
+		if err := config.ValidatePublicationData(pub.Data, chOpts.PublicationDataFormat); err != nil {
+			h.errors.Inc()
+			log.Error().Err(err).Str("channel", channel).Msg("invalid publication data from cache empty proxy")
+			return centrifuge.CacheEmptyReply{}, centrifuge.ErrorInternal
+		}
+		if err := config.ValidatePublicationDataSchema(pub.Data, chOpts); err != nil {
+			h.errors.Inc()
+			log.Error().Err(err).Str("channel", channel).Msg("publication data from cache empty proxy violates schema")
+			return centrifuge.CacheEmptyReply{}, centrifuge.ErrorInternal
+		}
+		_, err := node.Publish(
+			channel, pub.Data,
+			centrifuge.WithHistory(chOpts.HistorySize, chOpts.HistoryTTL.ToDuration(), chOpts.HistoryMetaTTL.ToDuration()),
+			centrifuge.WithTags(pub.Tags),
+		)
+		if err != nil {
+			log.Error().Err(err).Str("channel", channel).Msg("error publishing publication from cache empty proxy")
+			return centrifuge.CacheEmptyReply{}, err
+		}
+		return centrifuge.CacheEmptyReply{Populated: true}, nil
+	}
+	return centrifuge.CacheEmptyReply{Populated: resp.Result.Populated}, nil
+}
@@ -0,0 +1,190 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/proxyproto"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/stretchr/testify/require"
+)
+
+type testCacheEmptyProxy struct {
+	calls   atomic.Int32
+	release chan struct{}
+	resp    *proxyproto.NotifyCacheEmptyResponse
+	err     error
+}
+
+func (p *testCacheEmptyProxy) NotifyCacheEmpty(ctx context.Context, _ *proxyproto.NotifyCacheEmptyRequest) (*proxyproto.NotifyCacheEmptyResponse, error) {
+	p.calls.Add(1)
+	if p.release != nil {
+		select {
+		case <-p.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return p.resp, p.err
+}
+
+func (p *testCacheEmptyProxy) Protocol() string {
+	return "test"
+}
+
+func newTestCacheEmptyNode(t *testing.T) *centrifuge.Node {
+	t.Helper()
+	node, err := centrifuge.New(centrifuge.Config{})
+	require.NoError(t, err)
+	require.NoError(t, node.Run())
+	t.Cleanup(func() { _ = node.Shutdown(context.Background()) })
+	return node
+}
+
+func testCacheEmptyChannelOptions() configtypes.ChannelOptions {
+	return configtypes.ChannelOptions{
+		HistorySize:    10,
+		HistoryTTL:     configtypes.Duration(time.Minute),
+		HistoryMetaTTL: configtypes.Duration(time.Hour),
+	}
+}
+
+func TestCacheEmptyHandler_PublicationPopulatesHistory(t *testing.T) {
+	node := newTestCacheEmptyNode(t)
+	p := &testCacheEmptyProxy{
+		resp: &proxyproto.NotifyCacheEmptyResponse{
+			Result: &proxyproto.NotifyCacheEmptyResult{
+				Publication: &proxyproto.Publication{
+					Data: []byte(`{"price":42}`),
+					Tags: map[string]string{"source": "backend"},
+				},
+			},
+		},
+	}
+	h := NewCacheEmptyHandler(CacheEmptyHandlerConfig{Proxy: p, Name: "default"})
+
+	reply, err := h.Handle(node)(centrifuge.CacheEmptyEvent{Channel: "tickers:btc"}, testCacheEmptyChannelOptions())
+	require.NoError(t, err)
+	require.True(t, reply.Populated)
+
+	history, err := node.History("tickers:btc", centrifuge.WithLimit(centrifuge.NoLimit))
+	require.NoError(t, err)
+	require.Len(t, history.Publications, 1)
+	require.JSONEq(t, `{"price":42}`, string(history.Publications[0].Data))
+	require.Equal(t, "backend", history.Publications[0].Tags["source"])
+}
+
+func TestCacheEmptyHandler_Populated(t *testing.T) {
+	node := newTestCacheEmptyNode(t)
+	p := &testCacheEmptyProxy{
+		resp: &proxyproto.NotifyCacheEmptyResponse{
+			Result: &proxyproto.NotifyCacheEmptyResult{Populated: true},
+		},
+	}
+	h := NewCacheEmptyHandler(CacheEmptyHandlerConfig{Proxy: p, Name: "default"})
+
+	reply, err := h.Handle(node)(centrifuge.CacheEmptyEvent{Channel: "tickers:btc"}, testCacheEmptyChannelOptions())
+	require.NoError(t, err)
+	require.True(t, reply.Populated)
+
+	history, err := node.History("tickers:btc", centrifuge.WithLimit(centrifuge.NoLimit))
+	require.NoError(t, err)
+	require.Len(t, history.Publications, 0)
+}
+
+func TestCacheEmptyHandler_Error(t *testing.T) {
+	node := newTestCacheEmptyNode(t)
+
+	t.Run("transport", func(t *testing.T) {
+		p := &testCacheEmptyProxy{err: errors.New("boom")}
+		h := NewCacheEmptyHandler(CacheEmptyHandlerConfig{Proxy: p, Name: "default"})
+		_, err := h.Handle(node)(centrifuge.CacheEmptyEvent{Channel: "tickers:btc"}, testCacheEmptyChannelOptions())
+		require.Error(t, err)
+	})
+
+	t.Run("response", func(t *testing.T) {
+		p := &testCacheEmptyProxy{
+			resp: &proxyproto.NotifyCacheEmptyResponse{
+				Error: &proxyproto.Error{Code: 1000, Message: "unavailable"},
+			},
+		}
+		h := NewCacheEmptyHandler(CacheEmptyHandlerConfig{Proxy: p, Name: "default"})
+		_, err := h.Handle(node)(centrifuge.CacheEmptyEvent{Channel: "tickers:btc"}, testCacheEmptyChannelOptions())
+		require.EqualError(t, err, "unavailable")
+	})
+}
+
+func TestCacheEmptyHandler_Deduplication(t *testing.T) {
+	node := newTestCacheEmptyNode(t)
+	p := &testCacheEmptyProxy{
+		release: make(chan struct{}),
+		resp: &proxyproto.NotifyCacheEmptyResponse{
+			Result: &proxyproto.NotifyCacheEmptyResult{
+				Publication: &proxyproto.Publication{Data: []byte(`{}`)},
+			},
+		},
+	}
+	h := NewCacheEmptyHandler(CacheEmptyHandlerConfig{Proxy: p, Name: "default"})
+	fn := h.Handle(node)
+
+	const numSubscribers = 10
+	var started, done sync.WaitGroup
+	started.Add(numSubscribers)
+	done.Add(numSubscribers)
+	for i := 0; i < numSubscribers; i++ {
+		go func() {
+			defer done.Done()
+			started.Done()
+			reply, err := fn(centrifuge.CacheEmptyEvent{Channel: "tickers:btc"}, testCacheEmptyChannelOptions())
+			require.NoError(t, err)
+			require.True(t, reply.Populated)
+		}()
+	}
+	started.Wait()
+	require.Eventually(t, func() bool { return p.calls.Load() == 1 }, time.Second, 5*time.Millisecond)
+	// Give remaining goroutines a chance to join the in-flight call.
+	time.Sleep(50 * time.Millisecond)
+	close(p.release)
+	done.Wait()
+
+	require.Equal(t, int32(1), p.calls.Load())
+	history, err := node.History("tickers:btc", centrifuge.WithLimit(centrifuge.NoLimit))
+	require.NoError(t, err)
+	require.Len(t, history.Publications, 1)
+}
+
+func TestCacheEmptyHandler_InvalidPublicationData(t *testing.T) {
+	node := newTestCacheEmptyNode(t)
+	p := &testCacheEmptyProxy{
+		resp: &proxyproto.NotifyCacheEmptyResponse{
+			Result: &proxyproto.NotifyCacheEmptyResult{
+				Publication: &proxyproto.Publication{Data: []byte(`not json`)},
+			},
+		},
+	}
+	h := NewCacheEmptyHandler(CacheEmptyHandlerConfig{Proxy: p, Name: "default"})
+
+	chOpts := testCacheEmptyChannelOptions()
+	chOpts.PublicationDataFormat = configtypes.PublicationDataFormatJSON
+	_, err := h.Handle(node)(centrifuge.CacheEmptyEvent{Channel: "tickers:btc"}, chOpts)
+	require.ErrorIs(t, err, centrifuge.ErrorInternal)
+
+	history, err := node.History("tickers:btc", centrifuge.WithLimit(centrifuge.NoLimit))
+	require.NoError(t, err)
+	require.Len(t, history.Publications, 0)
+}
+
+func TestCacheEmptyHandler_Timeout(t *testing.T) {
+	node := newTestCacheEmptyNode(t)
+	p := &testCacheEmptyProxy{release: make(chan struct{})}
+	defer close(p.release)
+	h := NewCacheEmptyHandler(CacheEmptyHandlerConfig{Proxy: p, Name: "default", Timeout: 50 * time.Millisecond})
+	_, err := h.Handle(node)(centrifuge.CacheEmptyEvent{Channel: "tickers:btc"}, testCacheEmptyChannelOptions())
+	require.Error(t, err)
+}
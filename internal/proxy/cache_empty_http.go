@@ -0,0 +1,46 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/centrifugal/centrifugo/v6/internal/proxyproto"
+)
+
+// HTTPCacheEmptyProxy ...
+type HTTPCacheEmptyProxy struct {
+	config     Config
+	httpCaller HTTPCaller
+}
+
+var _ CacheEmptyProxy = (*HTTPCacheEmptyProxy)(nil)
+
+// NewHTTPCacheEmptyProxy ...
+func NewHTTPCacheEmptyProxy(p Config) (*HTTPCacheEmptyProxy, error) {
+	httpClient, err := proxyHTTPClient(p, "cache_empty_proxy")
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP client: %w", err)
+	}
+	return &HTTPCacheEmptyProxy{
+		httpCaller: NewHTTPCaller(httpClient),
+		config:     p,
+	}, nil
+}
+
+// NotifyCacheEmpty asks application backend to load missing channel cache.
+func (p *HTTPCacheEmptyProxy) NotifyCacheEmpty(ctx context.Context, req *proxyproto.NotifyCacheEmptyRequest) (*proxyproto.NotifyCacheEmptyResponse, error) {
+	data, err := httpEncoder.EncodeNotifyCacheEmptyRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	respData, err := p.httpCaller.CallHTTP(ctx, p.config.Endpoint, httpRequestHeaders(ctx, p.config), data)
+	if err != nil {
+		return nil, err
+	}
+	return httpDecoder.DecodeNotifyCacheEmptyResponse(respData)
+}
+
+// Protocol ...
+func (p *HTTPCacheEmptyProxy) Protocol() string {
+	return "http"
+}
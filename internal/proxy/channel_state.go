@@ -0,0 +1,14 @@
+package proxy
+
+import (
+	"context"
+
+	"github.com/centrifugal/centrifugo/v6/internal/proxyproto"
+)
+
+// ChannelStateProxy allows to send NotifyChannelState requests.
+type ChannelStateProxy interface {
+	NotifyChannelState(context.Context, *proxyproto.NotifyChannelStateRequest) (*proxyproto.NotifyChannelStateResponse, error)
+	// Protocol for metrics and logging.
+	Protocol() string
+}
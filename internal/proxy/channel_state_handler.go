@@ -0,0 +1,54 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+	"github.com/centrifugal/centrifugo/v6/internal/proxyproto"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ChannelStateHandlerConfig ...
+type ChannelStateHandlerConfig struct {
+	Proxy ChannelStateProxy
+	Name  string
+}
+
+// ChannelStateHandler ...
+type ChannelStateHandler struct {
+	proxy     ChannelStateProxy
+	summary   prometheus.Observer
+	histogram prometheus.Observer
+	errors    prometheus.Counter
+}
+
+// NewChannelStateHandler ...
+func NewChannelStateHandler(c ChannelStateHandlerConfig) *ChannelStateHandler {
+	return &ChannelStateHandler{
+		proxy:     c.Proxy,
+		summary:   metrics.ProxyCallDurationSummary.WithLabelValues(c.Proxy.Protocol(), "channel_state", c.Name),
+		histogram: metrics.ProxyCallDurationHistogram.WithLabelValues(c.Proxy.Protocol(), "channel_state", c.Name),
+		errors:    metrics.ProxyCallErrorCount.WithLabelValues(c.Proxy.Protocol(), "channel_state", c.Name),
+	}
+}
+
+// Notify sends a batch of channel events to the application backend.
+func (h *ChannelStateHandler) Notify(ctx context.Context, events []*proxyproto.ChannelEvent) error {
+	started := time.Now()
+	resp, err := h.proxy.NotifyChannelState(ctx, &proxyproto.NotifyChannelStateRequest{Events: events})
+	duration := time.Since(started).Seconds()
+	h.summary.Observe(duration)
+	h.histogram.Observe(duration)
+	if err != nil {
+		h.errors.Inc()
+		return err
+	}
+	if resp.Error != nil {
+		h.errors.Inc()
+		return errors.New(resp.Error.Message)
+	}
+	return nil
+}
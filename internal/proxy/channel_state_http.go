@@ -0,0 +1,46 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/centrifugal/centrifugo/v6/internal/proxyproto"
+)
+
+// HTTPChannelStateProxy ...
+type HTTPChannelStateProxy struct {
+	config     Config
+	httpCaller HTTPCaller
+}
+
+var _ ChannelStateProxy = (*HTTPChannelStateProxy)(nil)
+
+// NewHTTPChannelStateProxy ...
+func NewHTTPChannelStateProxy(p Config) (*HTTPChannelStateProxy, error) {
+	httpClient, err := proxyHTTPClient(p, "channel_state_proxy")
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP client: %w", err)
+	}
+	return &HTTPChannelStateProxy{
+		httpCaller: NewHTTPCaller(httpClient),
+		config:     p,
+	}, nil
+}
+
+// NotifyChannelState proxies channel state events to application backend.
+func (p *HTTPChannelStateProxy) NotifyChannelState(ctx context.Context, req *proxyproto.NotifyChannelStateRequest) (*proxyproto.NotifyChannelStateResponse, error) {
+	data, err := httpEncoder.EncodeNotifyChannelStateRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	respData, err := p.httpCaller.CallHTTP(ctx, p.config.Endpoint, httpRequestHeaders(ctx, p.config), data)
+	if err != nil {
+		return nil, err
+	}
+	return httpDecoder.DecodeNotifyChannelStateResponse(respData)
+}
+
+// Protocol ...
+func (p *HTTPChannelStateProxy) Protocol() string {
+	return "http"
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/centrifugal/centrifugo/v6/internal/capability"
 	"github.com/centrifugal/centrifugo/v6/internal/clientstorage"
 	"github.com/centrifugal/centrifugo/v6/internal/config"
 	"github.com/centrifugal/centrifugo/v6/internal/metrics"
@@ -238,6 +239,30 @@ func (h *ConnectHandler) Handle() ConnectingHandlerFunc {
 			}
 		}
 
+		if len(result.Caps) > 0 {
+			caps, err := capability.Compile(capsFromProto(result.Caps))
+			if err != nil {
+				log.Error().Err(err).Str("client", e.ClientID).Msg("invalid caps in connect proxy result")
+				return centrifuge.ConnectReply{}, ConnectExtra{}, centrifuge.ErrorInternal
+			}
+			if reply.Storage == nil {
+				reply.Storage = map[string]any{}
+			}
+			reply.Storage[clientstorage.KeyCaps] = caps
+		}
+
 		return reply, ConnectExtra{}, nil
 	}
 }
+
+func capsFromProto(caps []*proxyproto.ChannelsCapability) []capability.ChannelsCapability {
+	result := make([]capability.ChannelsCapability, 0, len(caps))
+	for _, c := range caps {
+		result = append(result, capability.ChannelsCapability{
+			Channels: c.GetChannels(),
+			Allow:    c.GetAllow(),
+			Match:    c.GetMatch(),
+		})
+	}
+	return result
+}
@@ -10,6 +10,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/centrifugal/centrifugo/v6/internal/capability"
+	"github.com/centrifugal/centrifugo/v6/internal/clientstorage"
 	"github.com/centrifugal/centrifugo/v6/internal/config"
 	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
 	"github.com/centrifugal/centrifugo/v6/internal/tools"
@@ -321,6 +323,31 @@ func TestHandleConnectWithSubscription(t *testing.T) {
 
 }
 
+func TestHandleConnectWithCaps(t *testing.T) {
+	opts := proxyGRPCTestServerOptions{
+		User:     "56",
+		Channels: []string{"chat:"},
+	}
+	grpcTestCase := newConnHandleGRPCTestCase(context.Background(), newProxyGRPCTestServer("caps", opts))
+	defer grpcTestCase.Teardown()
+
+	httpTestCase := newConnHandleHTTPTestCase(context.Background(), "/proxy")
+	httpTestCase.Mux.HandleFunc("/proxy", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`{"result": {"user": "56", "caps": [{"channels": ["chat:"], "allow": ["sub", "pub"], "match": "prefix"}]}}`))
+	})
+	defer httpTestCase.Teardown()
+
+	cases := newConnHandleTestCases(httpTestCase, grpcTestCase)
+	for _, c := range cases {
+		reply, err := c.invokeHandle(context.Background())
+		require.NoError(t, err, c.protocol)
+		caps, ok := reply.Storage[clientstorage.KeyCaps].(*capability.Checker)
+		require.True(t, ok, c.protocol)
+		require.True(t, caps.Allowed("chat:1", capability.Publish), c.protocol)
+		require.False(t, caps.Allowed("chat:1", capability.History), c.protocol)
+	}
+}
+
 func TestHandleConnectWithSubscriptionRecover(t *testing.T) {
 	opts := proxyGRPCTestServerOptions{
 		User:     "56",
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/mapfilter"
 	"github.com/centrifugal/centrifugo/v6/internal/metrics"
 	"github.com/centrifugal/centrifugo/v6/internal/proxyproto"
 
@@ -148,6 +149,9 @@ func (h *MapPublishHandler) Handle(node *centrifuge.Node) MapPublishHandlerFunc
 			version = mapPublishRep.Result.Version
 			versionEpoch = mapPublishRep.Result.VersionEpoch
 		}
+		if chOpts.Map.IndexTags && len(data) > 0 {
+			tags = mapfilter.Tags(chOpts.Map.Indexes.FilterIndexes(), data, tags)
+		}
 
 		result, err := node.MapPublish(
 			client.Context(), e.Channel, key,
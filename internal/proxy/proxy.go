@@ -110,6 +110,26 @@ func GetSharedPollRefreshProxy(name string, p Config) (SharedPollRefreshProxy, e
 	return NewGRPCSharedPollRefreshProxy(name, p)
 }
 
+func GetChannelStateProxy(name string, p Config) (ChannelStateProxy, error) {
+	for i, header := range p.HttpHeaders {
+		p.HttpHeaders[i] = strings.ToLower(header)
+	}
+	if isHttpEndpoint(p.Endpoint) {
+		return NewHTTPChannelStateProxy(p)
+	}
+	return NewGRPCChannelStateProxy(name, p)
+}
+
+func GetCacheEmptyProxy(name string, p Config) (CacheEmptyProxy, error) {
+	for i, header := range p.HttpHeaders {
+		p.HttpHeaders[i] = strings.ToLower(header)
+	}
+	if isHttpEndpoint(p.Endpoint) {
+		return NewHTTPCacheEmptyProxy(p)
+	}
+	return NewGRPCCacheEmptyProxy(name, p)
+}
+
 type PerCallData struct {
 	Meta json.RawMessage
 }
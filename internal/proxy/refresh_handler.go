@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/centrifugal/centrifugo/v6/internal/capability"
 	"github.com/centrifugal/centrifugo/v6/internal/metrics"
 	"github.com/centrifugal/centrifugo/v6/internal/proxyproto"
 
@@ -40,6 +41,8 @@ func NewRefreshHandler(c RefreshHandlerConfig) *RefreshHandler {
 
 type RefreshExtra struct {
 	Meta json.RawMessage
+	// Caps replace connection capabilities when set.
+	Caps *capability.Checker
 }
 
 // RefreshHandlerFunc ...
@@ -118,6 +121,14 @@ func (h *RefreshHandler) Handle() RefreshHandlerFunc {
 		if result.Meta != nil {
 			extra.Meta = json.RawMessage(result.Meta)
 		}
+		if len(result.Caps) > 0 {
+			caps, err := capability.Compile(capsFromProto(result.Caps))
+			if err != nil {
+				log.Error().Err(err).Str("client", client.ID()).Msg("invalid caps in refresh proxy result")
+				return centrifuge.RefreshReply{}, RefreshExtra{}, centrifuge.ErrorInternal
+			}
+			extra.Caps = caps
+		}
 
 		return centrifuge.RefreshReply{
 			ExpireAt: result.ExpireAt,
@@ -63,6 +63,15 @@ func (p proxyGRPCTestServer) Connect(_ context.Context, _ *proxyproto.ConnectReq
 				Subs: subs,
 			},
 		}, nil
+	case "caps":
+		return &proxyproto.ConnectResponse{
+			Result: &proxyproto.ConnectResult{
+				User: p.opts.User,
+				Caps: []*proxyproto.ChannelsCapability{
+					{Channels: p.opts.Channels, Allow: []string{"sub", "pub"}, Match: "prefix"},
+				},
+			},
+		}, nil
 	case "custom disconnect":
 		return &proxyproto.ConnectResponse{
 			Disconnect: p.newDisconnect(),
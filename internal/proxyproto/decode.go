@@ -13,6 +13,7 @@ type ResponseDecoder interface {
 	DecodeMapPublishResponse(data []byte) (*MapPublishResponse, error)
 	DecodeMapRemoveResponse(data []byte) (*MapRemoveResponse, error)
 	DecodeSharedPollRefreshResponse(data []byte) (*SharedPollRefreshResponse, error)
+	DecodeNotifyChannelStateResponse(data []byte) (*NotifyChannelStateResponse, error)
 }
 
 var _ ResponseDecoder = (*JSONDecoder)(nil)
@@ -108,3 +109,12 @@ func (e *JSONDecoder) DecodeSharedPollRefreshResponse(data []byte) (*SharedPollR
 	}
 	return &resp, nil
 }
+
+func (e *JSONDecoder) DecodeNotifyChannelStateResponse(data []byte) (*NotifyChannelStateResponse, error) {
+	var resp NotifyChannelStateResponse
+	err := json.Unmarshal(data, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -13,6 +13,7 @@ type RequestEncoder interface {
 	EncodeMapPublishRequest(req *MapPublishRequest) ([]byte, error)
 	EncodeMapRemoveRequest(req *MapRemoveRequest) ([]byte, error)
 	EncodeSharedPollRefreshRequest(req *SharedPollRefreshRequest) ([]byte, error)
+	EncodeNotifyChannelStateRequest(req *NotifyChannelStateRequest) ([]byte, error)
 }
 
 var _ RequestEncoder = (*JSONEncoder)(nil)
@@ -58,3 +59,7 @@ func (e *JSONEncoder) EncodeMapRemoveRequest(req *MapRemoveRequest) ([]byte, err
 func (e *JSONEncoder) EncodeSharedPollRefreshRequest(req *SharedPollRefreshRequest) ([]byte, error) {
 	return json.Marshal(req)
 }
+
+func (e *JSONEncoder) EncodeNotifyChannelStateRequest(req *NotifyChannelStateRequest) ([]byte, error) {
+	return json.Marshal(req)
+}
@@ -63,7 +63,7 @@ type CentrifugoProxyClient interface {
 	// NotifyCacheEmpty is an EXPERIMENTAL method which allows to load documents from the backend.
 	NotifyCacheEmpty(ctx context.Context, in *NotifyCacheEmptyRequest, opts ...grpc.CallOption) (*NotifyCacheEmptyResponse, error)
 	// NotifyChannelState can be used to receive channel events such as channel "occupied" and "vacated".
-	// This is a feature in a preview state and is only available in Centrifugo PRO.
+	// Events are sent in batches for namespaces with channel_state_proxy_enabled.
 	NotifyChannelState(ctx context.Context, in *NotifyChannelStateRequest, opts ...grpc.CallOption) (*NotifyChannelStateResponse, error)
 	// MapPublish to proxy map publish attempts to channels.
 	MapPublish(ctx context.Context, in *MapPublishRequest, opts ...grpc.CallOption) (*MapPublishResponse, error)
@@ -252,7 +252,7 @@ type CentrifugoProxyServer interface {
 	// NotifyCacheEmpty is an EXPERIMENTAL method which allows to load documents from the backend.
 	NotifyCacheEmpty(context.Context, *NotifyCacheEmptyRequest) (*NotifyCacheEmptyResponse, error)
 	// NotifyChannelState can be used to receive channel events such as channel "occupied" and "vacated".
-	// This is a feature in a preview state and is only available in Centrifugo PRO.
+	// Events are sent in batches for namespaces with channel_state_proxy_enabled.
 	NotifyChannelState(context.Context, *NotifyChannelStateRequest) (*NotifyChannelStateResponse, error)
 	// MapPublish to proxy map publish attempts to channels.
 	MapPublish(context.Context, *MapPublishRequest) (*MapPublishResponse, error)
@@ -0,0 +1,277 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/redisshard"
+
+	"github.com/redis/rueidis"
+)
+
+// RedisStore is a Store on top of Redis sorted sets. All keys share one hash tag,
+// so scheduler state is kept on a single shard – the first one if several shards
+// configured.
+type RedisStore struct {
+	shard  *redisshard.RedisShard
+	prefix string
+}
+
+var _ Store = (*RedisStore)(nil)
+
+// NewRedisStore creates RedisStore.
+func NewRedisStore(cfg configtypes.RedisPrefixed) (*RedisStore, error) {
+	shards, err := redisshard.BuildRedisShards(cfg.Redis)
+	if err != nil {
+		return nil, fmt.Errorf("error building Redis shards for scheduled publications: %w", err)
+	}
+	if len(shards) == 0 {
+		return nil, fmt.Errorf("no Redis shards configured for scheduled publications")
+	}
+	for _, shard := range shards[1:] {
+		shard.Close()
+	}
+	return &RedisStore{shard: shards[0], prefix: cfg.Prefix}, nil
+}
+
+var (
+	// KEYS[1] – queue zset, KEYS[2] – items hash, KEYS[3:] – channel index zsets.
+	// ARGV[1] – item ID, ARGV[2] – publish time in milliseconds, ARGV[3] – item.
+	addScript = rueidis.NewLuaScript(`
+redis.call("hset", KEYS[2], ARGV[1], ARGV[3])
+redis.call("zadd", KEYS[1], ARGV[2], ARGV[1])
+for i = 3, #KEYS do
+  redis.call("zadd", KEYS[i], ARGV[2], ARGV[1])
+end
+return 1
+`)
+	// KEYS[1] – queue zset or channel index zset, KEYS[2] – items hash.
+	// ARGV[1] – cursor publish time, ARGV[2] – cursor item ID, ARGV[3] – limit.
+	listScript = rueidis.NewLuaScript(`
+local limit = tonumber(ARGV[3])
+local cursor = tonumber(ARGV[1])
+local result = {}
+local offset = 0
+while #result < limit do
+  local batch = redis.call("zrangebyscore", KEYS[1], ARGV[1], "+inf", "WITHSCORES", "LIMIT", offset, limit)
+  if #batch == 0 then
+    break
+  end
+  for i = 1, #batch, 2 do
+    local id = batch[i]
+    if tonumber(batch[i + 1]) > cursor or id > ARGV[2] then
+      local data = redis.call("hget", KEYS[2], id)
+      if data then
+        table.insert(result, data)
+        if #result == limit then
+          break
+        end
+      end
+    end
+  end
+  offset = offset + limit
+end
+return result
+`)
+	// KEYS[1] – queue zset, KEYS[2] – items hash. ARGV[1] – item ID,
+	// ARGV[2] – channel index key prefix.
+	cancelScript = rueidis.NewLuaScript(`
+if redis.call("zrem", KEYS[1], ARGV[1]) == 0 then
+  return 0
+end
+local data = redis.call("hget", KEYS[2], ARGV[1])
+redis.call("hdel", KEYS[2], ARGV[1])
+if data then
+  local item = cjson.decode(data)
+  if type(item.channels) == "table" then
+    for _, ch in ipairs(item.channels) do
+      redis.call("zrem", ARGV[2] .. ch, ARGV[1])
+    end
+  end
+end
+return 1
+`)
+	// KEYS[1] – queue zset, KEYS[2] – claimed zset, KEYS[3] – items hash.
+	// ARGV[1] – current time in milliseconds, ARGV[2] – limit, ARGV[3] – claim
+	// deadline in milliseconds, ARGV[4] – channel index key prefix.
+	claimScript = rueidis.NewLuaScript(`
+local limit = tonumber(ARGV[2])
+local ids = redis.call("zrangebyscore", KEYS[2], "-inf", ARGV[1], "LIMIT", 0, limit)
+if #ids < limit then
+  local due = redis.call("zrangebyscore", KEYS[1], "-inf", ARGV[1], "LIMIT", 0, limit - #ids)
+  for _, id in ipairs(due) do
+    redis.call("zrem", KEYS[1], id)
+    local data = redis.call("hget", KEYS[3], id)
+    if data then
+      local item = cjson.decode(data)
+      if type(item.channels) == "table" then
+        for _, ch in ipairs(item.channels) do
+          redis.call("zrem", ARGV[4] .. ch, id)
+        end
+      end
+    end
+    table.insert(ids, id)
+  end
+end
+local result = {}
+for _, id in ipairs(ids) do
+  local data = redis.call("hget", KEYS[3], id)
+  if data then
+    redis.call("zadd", KEYS[2], ARGV[3], id)
+    table.insert(result, data)
+  else
+    redis.call("zrem", KEYS[2], id)
+  end
+end
+return result
+`)
+	// KEYS[1] – claimed zset, KEYS[2] – items hash. ARGV – item IDs.
+	completeScript = rueidis.NewLuaScript(`
+for i = 1, #ARGV, 1000 do
+  local ids = {unpack(ARGV, i, math.min(i + 999, #ARGV))}
+  redis.call("zrem", KEYS[1], unpack(ids))
+  redis.call("hdel", KEYS[2], unpack(ids))
+end
+return 1
+`)
+)
+
+func (s *RedisStore) queueKey() string {
+	return s.prefix + ".{scheduled_publications}.queue"
+}
+
+func (s *RedisStore) claimedKey() string {
+	return s.prefix + ".{scheduled_publications}.claimed"
+}
+
+func (s *RedisStore) itemsKey() string {
+	return s.prefix + ".{scheduled_publications}.items"
+}
+
+func (s *RedisStore) channelKeyPrefix() string {
+	return s.prefix + ".{scheduled_publications}.channel."
+}
+
+// Add ...
+func (s *RedisStore) Add(ctx context.Context, item Item) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(item.Channels)+2)
+	keys = append(keys, s.queueKey(), s.itemsKey())
+	for _, ch := range item.Channels {
+		keys = append(keys, s.channelKeyPrefix()+ch)
+	}
+	res := s.shard.RunOp(func(client rueidis.Client) rueidis.RedisResult {
+		return addScript.Exec(ctx, client, keys, []string{item.ID, strconv.FormatInt(item.PublishAt, 10), string(data)})
+	})
+	return res.Error()
+}
+
+// Get ...
+func (s *RedisStore) Get(ctx context.Context, id string) (Item, bool, error) {
+	res := s.shard.RunOp(func(client rueidis.Client) rueidis.RedisResult {
+		return client.Do(ctx, client.B().Hget().Key(s.itemsKey()).Field(id).Build())
+	})
+	data, err := res.ToString()
+	if rueidis.IsRedisNil(err) {
+		return Item{}, false, nil
+	}
+	if err != nil {
+		return Item{}, false, err
+	}
+	var item Item
+	if err := json.Unmarshal([]byte(data), &item); err != nil {
+		return Item{}, false, fmt.Errorf("malformed scheduled publication: %w", err)
+	}
+	return item, true, nil
+}
+
+// List ...
+func (s *RedisStore) List(ctx context.Context, opts ListOptions) ([]Item, string, error) {
+	publishAt, id, err := ParseCursor(opts.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	key := s.queueKey()
+	if opts.Channel != "" {
+		key = s.channelKeyPrefix() + opts.Channel
+	}
+	// Request one more item to know whether there are more items to list.
+	args := []string{strconv.FormatInt(publishAt, 10), id, strconv.Itoa(opts.Limit + 1)}
+	res := s.shard.RunOp(func(client rueidis.Client) rueidis.RedisResult {
+		return listScript.Exec(ctx, client, []string{key, s.itemsKey()}, args)
+	})
+	items, err := decodeItems(res)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(items) <= opts.Limit {
+		return items, "", nil
+	}
+	items = items[:opts.Limit]
+	return items, FormatCursor(items[len(items)-1]), nil
+}
+
+// Cancel ...
+func (s *RedisStore) Cancel(ctx context.Context, id string) (bool, error) {
+	res := s.shard.RunOp(func(client rueidis.Client) rueidis.RedisResult {
+		return cancelScript.Exec(ctx, client, []string{s.queueKey(), s.itemsKey()}, []string{id, s.channelKeyPrefix()})
+	})
+	removed, err := res.AsInt64()
+	if err != nil {
+		return false, err
+	}
+	return removed == 1, nil
+}
+
+// Claim ...
+func (s *RedisStore) Claim(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]Item, error) {
+	args := []string{
+		strconv.FormatInt(now.UnixMilli(), 10),
+		strconv.Itoa(limit),
+		strconv.FormatInt(now.Add(lease).UnixMilli(), 10),
+		s.channelKeyPrefix(),
+	}
+	res := s.shard.RunOp(func(client rueidis.Client) rueidis.RedisResult {
+		return claimScript.Exec(ctx, client, []string{s.queueKey(), s.claimedKey(), s.itemsKey()}, args)
+	})
+	return decodeItems(res)
+}
+
+// Complete ...
+func (s *RedisStore) Complete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	res := s.shard.RunOp(func(client rueidis.Client) rueidis.RedisResult {
+		return completeScript.Exec(ctx, client, []string{s.claimedKey(), s.itemsKey()}, ids)
+	})
+	return res.Error()
+}
+
+// Close releases Redis shard connections.
+func (s *RedisStore) Close() {
+	s.shard.Close()
+}
+
+func decodeItems(res rueidis.RedisResult) ([]Item, error) {
+	values, err := res.AsStrSlice()
+	if err != nil {
+		return nil, err
+	}
+	items := make([]Item, 0, len(values))
+	for _, value := range values {
+		var item Item
+		if err := json.Unmarshal([]byte(value), &item); err != nil {
+			return nil, fmt.Errorf("malformed scheduled publication: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,218 @@
+// Package schedule implements durable scheduler of publications sent over server
+// API with publish_at or delay set.
+//
+// Scheduled publications are kept in a Store shared by all nodes. Every node polls
+// the Store for due publications and claims a batch of them for a claim timeout.
+// Claimed publications are fired by the node and then removed from the Store. If
+// a node fails to complete a claimed publication (for example, crashes) the claim
+// expires and the publication is claimed by another node. Publications are fired
+// with an idempotency key derived from publication ID, so the broker suppresses
+// the repeated publish in this case. Together this gives exactly once publishing
+// across the cluster as long as publication is completed within broker idempotent
+// result TTL.
+package schedule
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// ErrTooFarInFuture returned when publication is scheduled later than allowed
+	// by max_delay option.
+	ErrTooFarInFuture = errors.New("publication scheduled too far in the future")
+	// ErrInvalidCursor returned when list cursor can not be parsed.
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+const (
+	// DefaultListLimit is used when list limit is not set.
+	DefaultListLimit = 100
+	// MaxListLimit caps list limit.
+	MaxListLimit = 1000
+)
+
+// Item is a scheduled publication.
+type Item struct {
+	ID string `json:"id"`
+	// Method is an API method to call, "publish" or "broadcast".
+	Method   string   `json:"method"`
+	Channels []string `json:"channels"`
+	// Payload is an encoded API request.
+	Payload []byte `json:"payload"`
+	// PublishAt is a time to fire publication at, Unix milliseconds.
+	PublishAt int64 `json:"publish_at"`
+	// CreatedAt is a time publication was scheduled at, Unix milliseconds.
+	CreatedAt int64 `json:"created_at"`
+}
+
+// ListOptions to list pending publications.
+type ListOptions struct {
+	// Channel to list publications of, all publications are listed when empty.
+	Channel string
+	Limit   int
+	// Cursor is a position to continue listing from, see FormatCursor.
+	Cursor string
+}
+
+// Store keeps scheduled publications shared between nodes.
+type Store interface {
+	// Add saves new scheduled publication.
+	Add(ctx context.Context, item Item) error
+	// Get returns publication which is not fired yet. Returns false if publication
+	// not found.
+	Get(ctx context.Context, id string) (Item, bool, error)
+	// List returns pending (not claimed) publications ordered by PublishAt and ID,
+	// together with a cursor to continue listing. Empty cursor is returned when
+	// there are no more publications.
+	List(ctx context.Context, opts ListOptions) ([]Item, string, error)
+	// Cancel removes pending publication. Returns false if publication not found
+	// or already claimed for firing.
+	Cancel(ctx context.Context, id string) (bool, error)
+	// Claim returns up to limit publications due at now, including publications
+	// which claim expired. Returned publications are claimed for lease duration.
+	Claim(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]Item, error)
+	// Complete removes fired publications.
+	Complete(ctx context.Context, ids []string) error
+}
+
+// FormatCursor returns cursor pointing right after the item.
+func FormatCursor(item Item) string {
+	return strconv.FormatInt(item.PublishAt, 10) + ":" + item.ID
+}
+
+// ParseCursor parses cursor made by FormatCursor. Empty cursor points to the start.
+func ParseCursor(cursor string) (int64, string, error) {
+	if cursor == "" {
+		return 0, "", nil
+	}
+	publishAt, id, ok := strings.Cut(cursor, ":")
+	if !ok || id == "" {
+		return 0, "", ErrInvalidCursor
+	}
+	ts, err := strconv.ParseInt(publishAt, 10, 64)
+	if err != nil {
+		return 0, "", ErrInvalidCursor
+	}
+	return ts, id, nil
+}
+
+// Handler fires claimed publication. When Handler returns an error publication stays
+// claimed and is fired again after claim expires.
+type Handler func(ctx context.Context, item Item) error
+
+// Scheduler schedules publications and fires them when due.
+type Scheduler struct {
+	store   Store
+	config  configtypes.ScheduledPublications
+	handler Handler
+}
+
+// New creates Scheduler.
+func New(store Store, cfg configtypes.ScheduledPublications, handler Handler) *Scheduler {
+	return &Scheduler{
+		store:   store,
+		config:  cfg,
+		handler: handler,
+	}
+}
+
+// Schedule saves publication to be fired at publishAt. Returns ID of scheduled publication.
+func (s *Scheduler) Schedule(ctx context.Context, method string, channels []string, payload []byte, publishAt time.Time) (string, error) {
+	now := time.Now()
+	if publishAt.Sub(now) > s.config.MaxDelay.ToDuration() {
+		return "", ErrTooFarInFuture
+	}
+	item := Item{
+		ID:        uuid.NewString(),
+		Method:    method,
+		Channels:  channels,
+		Payload:   payload,
+		PublishAt: publishAt.UnixMilli(),
+		CreatedAt: now.UnixMilli(),
+	}
+	if err := s.store.Add(ctx, item); err != nil {
+		return "", err
+	}
+	return item.ID, nil
+}
+
+// List returns pending publications.
+func (s *Scheduler) List(ctx context.Context, opts ListOptions) ([]Item, string, error) {
+	if _, _, err := ParseCursor(opts.Cursor); err != nil {
+		return nil, "", err
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultListLimit
+	} else if opts.Limit > MaxListLimit {
+		opts.Limit = MaxListLimit
+	}
+	return s.store.List(ctx, opts)
+}
+
+// Get returns publication which is not fired yet.
+func (s *Scheduler) Get(ctx context.Context, id string) (Item, bool, error) {
+	return s.store.Get(ctx, id)
+}
+
+// Cancel cancels pending publication. Returns false if publication not found or
+// already being fired.
+func (s *Scheduler) Cancel(ctx context.Context, id string) (bool, error) {
+	return s.store.Cancel(ctx, id)
+}
+
+// Run polls Store for due publications and fires them until ctx is done.
+func (s *Scheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.config.PollInterval.ToDuration())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.fireDue(ctx)
+		}
+	}
+}
+
+// fireDue fires due publications batch by batch until there are no more due.
+func (s *Scheduler) fireDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		items, err := s.store.Claim(ctx, time.Now(), s.config.BatchSize, s.config.ClaimTimeout.ToDuration())
+		if err != nil {
+			log.Error().Err(err).Msg("error claiming scheduled publications")
+			return
+		}
+		if len(items) == 0 {
+			return
+		}
+		completed := make([]string, 0, len(items))
+		for _, item := range items {
+			if err := s.handler(ctx, item); err != nil {
+				metrics.IncScheduledPublicationFired(item.Method, "error")
+				log.Error().Err(err).Str("id", item.ID).Str("method", item.Method).Msg("error firing scheduled publication, will retry after claim timeout")
+				continue
+			}
+			metrics.IncScheduledPublicationFired(item.Method, "ok")
+			completed = append(completed, item.ID)
+		}
+		if len(completed) > 0 {
+			if err := s.store.Complete(ctx, completed); err != nil {
+				log.Error().Err(err).Msg("error completing scheduled publications")
+				return
+			}
+		}
+		if len(items) < s.config.BatchSize || len(completed) < len(items) {
+			return
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	_ = metrics.Init(metrics.Config{
+		Registerer: prometheus.NewRegistry(),
+	})
+	os.Exit(m.Run())
+}
+
+type memoryStore struct {
+	mu      sync.Mutex
+	items   map[string]Item
+	claimed map[string]int64
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		items:   make(map[string]Item),
+		claimed: make(map[string]int64),
+	}
+}
+
+func (s *memoryStore) sorted() []Item {
+	items := make([]Item, 0, len(s.items))
+	for _, item := range s.items {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].PublishAt != items[j].PublishAt {
+			return items[i].PublishAt < items[j].PublishAt
+		}
+		return items[i].ID < items[j].ID
+	})
+	return items
+}
+
+func (s *memoryStore) Add(_ context.Context, item Item) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[item.ID] = item
+	return nil
+}
+
+func (s *memoryStore) Get(_ context.Context, id string) (Item, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item, ok := s.items[id]
+	return item, ok, nil
+}
+
+func (s *memoryStore) List(_ context.Context, opts ListOptions) ([]Item, string, error) {
+	publishAt, id, err := ParseCursor(opts.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var items []Item
+	for _, item := range s.sorted() {
+		if _, ok := s.claimed[item.ID]; ok {
+			continue
+		}
+		if item.PublishAt < publishAt || (item.PublishAt == publishAt && item.ID <= id) {
+			continue
+		}
+		if opts.Channel != "" && !slices.Contains(item.Channels, opts.Channel) {
+			continue
+		}
+		items = append(items, item)
+	}
+	if len(items) <= opts.Limit {
+		return items, "", nil
+	}
+	items = items[:opts.Limit]
+	return items, FormatCursor(items[len(items)-1]), nil
+}
+
+func (s *memoryStore) Cancel(_ context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.items[id]; !ok {
+		return false, nil
+	}
+	if _, ok := s.claimed[id]; ok {
+		return false, nil
+	}
+	delete(s.items, id)
+	return true, nil
+}
+
+func (s *memoryStore) Claim(_ context.Context, now time.Time, limit int, lease time.Duration) ([]Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var items []Item
+	for _, item := range s.sorted() {
+		if len(items) == limit {
+			break
+		}
+		if until, ok := s.claimed[item.ID]; ok && until > now.UnixMilli() {
+			continue
+		}
+		if item.PublishAt > now.UnixMilli() {
+			continue
+		}
+		s.claimed[item.ID] = now.Add(lease).UnixMilli()
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (s *memoryStore) Complete(_ context.Context, ids []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		delete(s.items, id)
+		delete(s.claimed, id)
+	}
+	return nil
+}
+
+func testConfig() configtypes.ScheduledPublications {
+	return configtypes.ScheduledPublications{
+		Enabled:      true,
+		PollInterval: configtypes.Duration(10 * time.Millisecond),
+		BatchSize:    2,
+		ClaimTimeout: configtypes.Duration(time.Minute),
+		MaxDelay:     configtypes.Duration(time.Hour),
+	}
+}
+
+func TestCursor(t *testing.T) {
+	publishAt, id, err := ParseCursor(FormatCursor(Item{ID: "abc", PublishAt: 42}))
+	require.NoError(t, err)
+	require.Equal(t, int64(42), publishAt)
+	require.Equal(t, "abc", id)
+
+	publishAt, id, err = ParseCursor("")
+	require.NoError(t, err)
+	require.Zero(t, publishAt)
+	require.Empty(t, id)
+
+	for _, cursor := range []string{"42", "42:", "x:abc"} {
+		_, _, err = ParseCursor(cursor)
+		require.ErrorIs(t, err, ErrInvalidCursor, cursor)
+	}
+}
+
+func TestSchedulerSchedule(t *testing.T) {
+	store := newMemoryStore()
+	s := New(store, testConfig(), nil)
+
+	publishAt := time.Now().Add(time.Minute)
+	id, err := s.Schedule(context.Background(), "publish", []string{"test"}, []byte("payload"), publishAt)
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+	require.Equal(t, publishAt.UnixMilli(), store.items[id].PublishAt)
+	require.Equal(t, []string{"test"}, store.items[id].Channels)
+
+	_, err = s.Schedule(context.Background(), "publish", []string{"test"}, nil, time.Now().Add(2*time.Hour))
+	require.ErrorIs(t, err, ErrTooFarInFuture)
+}
+
+func TestSchedulerListAndCancel(t *testing.T) {
+	store := newMemoryStore()
+	s := New(store, testConfig(), nil)
+	ctx := context.Background()
+
+	now := time.Now()
+	var ids []string
+	for i := 0; i < 3; i++ {
+		id, err := s.Schedule(ctx, "broadcast", []string{"a", "b"}, nil, now.Add(time.Duration(i+1)*time.Minute))
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	_, err := s.Schedule(ctx, "publish", []string{"c"}, nil, now.Add(time.Minute))
+	require.NoError(t, err)
+
+	items, cursor, err := s.List(ctx, ListOptions{Channel: "a", Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.Equal(t, ids[:2], []string{items[0].ID, items[1].ID})
+	require.NotEmpty(t, cursor)
+
+	items, cursor, err = s.List(ctx, ListOptions{Channel: "a", Limit: 2, Cursor: cursor})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, ids[2], items[0].ID)
+	require.Empty(t, cursor)
+
+	items, _, err = s.List(ctx, ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, items, 4)
+
+	_, _, err = s.List(ctx, ListOptions{Cursor: "invalid"})
+	require.ErrorIs(t, err, ErrInvalidCursor)
+
+	ok, err := s.Cancel(ctx, ids[0])
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = s.Cancel(ctx, ids[0])
+	require.NoError(t, err)
+	require.False(t, ok)
+	items, _, err = s.List(ctx, ListOptions{Channel: "b"})
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+}
+
+func TestSchedulerFireDue(t *testing.T) {
+	store := newMemoryStore()
+	var mu sync.Mutex
+	var fired []string
+	failID := ""
+	s := New(store, testConfig(), func(_ context.Context, item Item) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if item.ID == failID {
+			return errors.New("boom")
+		}
+		fired = append(fired, item.ID)
+		return nil
+	})
+	ctx := context.Background()
+
+	now := time.Now()
+	var due []string
+	for i := 0; i < 5; i++ {
+		id, err := s.Schedule(ctx, "publish", []string{"test"}, nil, now.Add(-time.Duration(5-i)*time.Second))
+		require.NoError(t, err)
+		due = append(due, id)
+	}
+	notDue, err := s.Schedule(ctx, "publish", []string{"test"}, nil, now.Add(time.Minute))
+	require.NoError(t, err)
+	failID = due[4]
+
+	s.fireDue(ctx)
+	require.Equal(t, due[:4], fired)
+	// Failed publication stays claimed and can't be cancelled.
+	require.Contains(t, store.items, failID)
+	require.Contains(t, store.claimed, failID)
+	ok, err := s.Cancel(ctx, failID)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Contains(t, store.items, notDue)
+	require.NotContains(t, store.claimed, notDue)
+}
+
+func TestSchedulerRun(t *testing.T) {
+	store := newMemoryStore()
+	firedCh := make(chan string, 1)
+	s := New(store, testConfig(), func(_ context.Context, item Item) error {
+		firedCh <- item.ID
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+
+	id, err := s.Schedule(ctx, "publish", []string{"test"}, nil, time.Now().Add(50*time.Millisecond))
+	require.NoError(t, err)
+	select {
+	case fired := <-firedCh:
+		require.Equal(t, id, fired)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timeout waiting for scheduled publication")
+	}
+	require.Eventually(t, func() bool {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		return len(store.items) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,193 @@
+package sharedpoll
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/redisshard"
+
+	"github.com/redis/rueidis"
+)
+
+// RedisStore is a Store on top of Redis. Channels are distributed over shards
+// by channel name hash.
+type RedisStore struct {
+	shards []*redisshard.RedisShard
+	prefix string
+}
+
+var _ Store = (*RedisStore)(nil)
+
+// NewRedisStore creates RedisStore.
+func NewRedisStore(cfg configtypes.RedisPrefixed) (*RedisStore, error) {
+	shards, err := redisshard.BuildRedisShards(cfg.Redis)
+	if err != nil {
+		return nil, fmt.Errorf("error building Redis shards for shared poll coordination: %w", err)
+	}
+	if len(shards) == 0 {
+		return nil, fmt.Errorf("no Redis shards configured for shared poll coordination")
+	}
+	return &RedisStore{shards: shards, prefix: cfg.Prefix}, nil
+}
+
+var (
+	// KEYS[1] – lease key. ARGV[1] – node ID, ARGV[2] – TTL in milliseconds.
+	acquireLeaseScript = rueidis.NewLuaScript(`
+local holder = redis.call("get", KEYS[1])
+if holder == false or holder == ARGV[1] then
+  redis.call("set", KEYS[1], ARGV[1], "px", ARGV[2])
+  return 1
+end
+return 0
+`)
+	// KEYS[1] – keys zset, KEYS[2] – items hash. ARGV[1] – expiration time in
+	// milliseconds, ARGV[2] – TTL in milliseconds, ARGV[3:] – tracked keys.
+	trackKeysScript = rueidis.NewLuaScript(`
+for i = 3, #ARGV do
+  redis.call("zadd", KEYS[1], ARGV[1], ARGV[i])
+end
+redis.call("pexpire", KEYS[1], ARGV[2])
+redis.call("pexpire", KEYS[2], ARGV[2])
+return 1
+`)
+	// KEYS[1] – keys zset, KEYS[2] – items hash. ARGV[1] – current time in milliseconds.
+	trackedKeysScript = rueidis.NewLuaScript(`
+local expired = redis.call("zrangebyscore", KEYS[1], "-inf", ARGV[1])
+if #expired > 0 then
+  redis.call("zremrangebyscore", KEYS[1], "-inf", ARGV[1])
+  for i = 1, #expired, 1000 do
+    redis.call("hdel", KEYS[2], unpack(expired, i, math.min(i + 999, #expired)))
+  end
+end
+return redis.call("zrange", KEYS[1], 0, -1)
+`)
+	// KEYS[1] – items hash. ARGV[1] – TTL in milliseconds, ARGV[2:] – key and item pairs.
+	saveItemsScript = rueidis.NewLuaScript(`
+for i = 2, #ARGV, 2 do
+  redis.call("hset", KEYS[1], ARGV[i], ARGV[i + 1])
+end
+redis.call("pexpire", KEYS[1], ARGV[1])
+return 1
+`)
+)
+
+func (s *RedisStore) shard(channel string) *redisshard.RedisShard {
+	if len(s.shards) == 1 {
+		return s.shards[0]
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(channel))
+	return s.shards[int(h.Sum32()%uint32(len(s.shards)))]
+}
+
+// Keys of the same channel share hash tag to be allocated in one Redis Cluster slot.
+func (s *RedisStore) leaseKey(channel string) string {
+	return s.prefix + ".shared_poll.{" + channel + "}.lease"
+}
+
+func (s *RedisStore) keysKey(channel string) string {
+	return s.prefix + ".shared_poll.{" + channel + "}.keys"
+}
+
+func (s *RedisStore) itemsKey(channel string) string {
+	return s.prefix + ".shared_poll.{" + channel + "}.items"
+}
+
+// AcquireLease ...
+func (s *RedisStore) AcquireLease(ctx context.Context, channel string, nodeID string, ttl time.Duration) (bool, error) {
+	res := s.shard(channel).RunOp(func(client rueidis.Client) rueidis.RedisResult {
+		return acquireLeaseScript.Exec(ctx, client, []string{s.leaseKey(channel)}, []string{nodeID, strconv.FormatInt(ttl.Milliseconds(), 10)})
+	})
+	acquired, err := res.AsInt64()
+	if err != nil {
+		return false, err
+	}
+	return acquired == 1, nil
+}
+
+// TrackKeys ...
+func (s *RedisStore) TrackKeys(ctx context.Context, channel string, keys []string, ttl time.Duration) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	args := make([]string, 0, len(keys)+2)
+	args = append(args,
+		strconv.FormatInt(time.Now().Add(ttl).UnixMilli(), 10),
+		strconv.FormatInt(ttl.Milliseconds(), 10),
+	)
+	args = append(args, keys...)
+	res := s.shard(channel).RunOp(func(client rueidis.Client) rueidis.RedisResult {
+		return trackKeysScript.Exec(ctx, client, []string{s.keysKey(channel), s.itemsKey(channel)}, args)
+	})
+	return res.Error()
+}
+
+// TrackedKeys ...
+func (s *RedisStore) TrackedKeys(ctx context.Context, channel string) ([]string, error) {
+	res := s.shard(channel).RunOp(func(client rueidis.Client) rueidis.RedisResult {
+		return trackedKeysScript.Exec(ctx, client, []string{s.keysKey(channel), s.itemsKey(channel)}, []string{strconv.FormatInt(time.Now().UnixMilli(), 10)})
+	})
+	return res.AsStrSlice()
+}
+
+// SaveItems ...
+func (s *RedisStore) SaveItems(ctx context.Context, channel string, items []Item, ttl time.Duration) error {
+	if len(items) == 0 {
+		return nil
+	}
+	args := make([]string, 0, 2*len(items)+1)
+	args = append(args, strconv.FormatInt(ttl.Milliseconds(), 10))
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		args = append(args, item.Key, string(data))
+	}
+	res := s.shard(channel).RunOp(func(client rueidis.Client) rueidis.RedisResult {
+		return saveItemsScript.Exec(ctx, client, []string{s.itemsKey(channel)}, args)
+	})
+	return res.Error()
+}
+
+// LoadItems ...
+func (s *RedisStore) LoadItems(ctx context.Context, channel string, keys []string) (map[string]Item, error) {
+	items := make(map[string]Item, len(keys))
+	if len(keys) == 0 {
+		return items, nil
+	}
+	res := s.shard(channel).RunOp(func(client rueidis.Client) rueidis.RedisResult {
+		return client.Do(ctx, client.B().Hmget().Key(s.itemsKey(channel)).Field(keys...).Build())
+	})
+	values, err := res.ToArray()
+	if err != nil {
+		return nil, err
+	}
+	for i, value := range values {
+		if value.IsNil() {
+			continue
+		}
+		data, err := value.ToString()
+		if err != nil {
+			return nil, err
+		}
+		var item Item
+		if err := json.Unmarshal([]byte(data), &item); err != nil {
+			return nil, fmt.Errorf("malformed shared poll item %q: %w", keys[i], err)
+		}
+		items[item.Key] = item
+	}
+	return items, nil
+}
+
+// Close releases Redis shard connections.
+func (s *RedisStore) Close() {
+	for _, shard := range s.shards {
+		shard.Close()
+	}
+}
@@ -0,0 +1,335 @@
+// Package sharedpoll implements coordinated mode of shared poll channels.
+//
+// Without coordination every node which has subscribers to a shared poll channel
+// polls the backend for the keys tracked locally. In coordinated mode nodes share
+// a Store: each node registers keys it tracks, and a single node per channel (the
+// holder of a channel lease) polls the backend for all keys tracked in the cluster.
+// The leader saves poll results in the Store and fans changed items out to other
+// nodes over the broker. Followers answer their refresh requests from the Store and
+// only poll the backend for keys the leader has not polled yet. Backend poll load
+// then scales with the number of channels, not with channels times nodes.
+//
+// Coordinated mode relies on explicit item versions, so it requires versioned mode.
+package sharedpoll
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/rs/zerolog/log"
+)
+
+// Item is a last known state of a shared poll key.
+type Item struct {
+	Key     string `json:"k"`
+	Version uint64 `json:"v,omitempty"`
+	Epoch   string `json:"e,omitempty"`
+	Data    []byte `json:"d,omitempty"`
+	Removed bool   `json:"r,omitempty"`
+}
+
+// Store keeps state shared between nodes for coordinated shared poll channels.
+type Store interface {
+	// AcquireLease acquires or prolongs the channel poll lease for the node. Returns
+	// true if the node holds the lease after the call.
+	AcquireLease(ctx context.Context, channel string, nodeID string, ttl time.Duration) (bool, error)
+	// TrackKeys registers keys tracked by the node. Keys not registered again within
+	// ttl are forgotten together with their saved items.
+	TrackKeys(ctx context.Context, channel string, keys []string, ttl time.Duration) error
+	// TrackedKeys returns keys tracked in the channel by all nodes.
+	TrackedKeys(ctx context.Context, channel string) ([]string, error)
+	// SaveItems saves items polled from the backend.
+	SaveItems(ctx context.Context, channel string, items []Item, ttl time.Duration) error
+	// LoadItems returns saved items for the keys. Keys without saved items are
+	// not present in the result.
+	LoadItems(ctx context.Context, channel string, keys []string) (map[string]Item, error)
+}
+
+// ChannelOptions of coordinated shared poll channel.
+type ChannelOptions struct {
+	RefreshInterval  time.Duration
+	RefreshBatchSize int
+}
+
+const (
+	// minLeaseTTL is a lower bound of lease and key tracking TTL.
+	minLeaseTTL = 5 * time.Second
+	// channelIdleTimeout is how long node keeps local state of a channel which
+	// is not refreshed anymore.
+	channelIdleTimeout = time.Minute
+)
+
+// publishFunc delivers an item to channel subscribers on all nodes.
+type publishFunc func(ctx context.Context, channel string, key string, version uint64, epoch string, data []byte) error
+
+// Coordinator elects one poller per shared poll channel across the cluster.
+type Coordinator struct {
+	store   Store
+	nodeID  string
+	publish publishFunc
+
+	mu        sync.Mutex
+	channels  map[string]*channelState
+	lastPrune time.Time
+}
+
+// channelState is a node local state of a coordinated channel.
+type channelState struct {
+	// local keys with time they were last refreshed on this node.
+	local          map[string]time.Time
+	lastSeen       time.Time
+	lastRemotePoll time.Time
+}
+
+// NewCoordinator creates Coordinator. Node must have shared poll publishing enabled
+// for coordinated channels, it's used to fan out results polled by the leader.
+func NewCoordinator(node *centrifuge.Node, store Store) *Coordinator {
+	return newCoordinator(store, node.ID(), node.SharedPollPublish)
+}
+
+func newCoordinator(store Store, nodeID string, publish publishFunc) *Coordinator {
+	return &Coordinator{
+		store:    store,
+		nodeID:   nodeID,
+		publish:  publish,
+		channels: make(map[string]*channelState),
+	}
+}
+
+// Handle processes shared poll refresh event of coordinated channel. The poll
+// handler is used to call the backend. Handle falls back to polling the backend
+// directly when the Store is not available.
+func (c *Coordinator) Handle(ctx context.Context, event centrifuge.SharedPollEvent, opts ChannelOptions, poll centrifuge.SharedPollHandler) (centrifuge.SharedPollResult, error) {
+	ttl := leaseTTL(opts.RefreshInterval)
+	keys := make([]string, 0, len(event.Items))
+	for _, item := range event.Items {
+		keys = append(keys, item.Key)
+	}
+	c.markLocal(event.Channel, keys)
+
+	if err := c.store.TrackKeys(ctx, event.Channel, keys, ttl); err != nil {
+		return c.fallback(ctx, event, poll, err)
+	}
+	leader, err := c.store.AcquireLease(ctx, event.Channel, c.nodeID, ttl)
+	if err != nil {
+		return c.fallback(ctx, event, poll, err)
+	}
+	if leader {
+		metrics.IncSharedPollCoordinatedPoll("leader")
+		return c.lead(ctx, event, opts, poll, ttl)
+	}
+	metrics.IncSharedPollCoordinatedPoll("follower")
+	return c.follow(ctx, event, poll, ttl)
+}
+
+func (c *Coordinator) fallback(ctx context.Context, event centrifuge.SharedPollEvent, poll centrifuge.SharedPollHandler, err error) (centrifuge.SharedPollResult, error) {
+	log.Warn().Err(err).Str("channel", event.Channel).Msg("shared poll coordination store error, polling backend directly")
+	metrics.IncSharedPollCoordinatedPoll("fallback")
+	return poll(ctx, event)
+}
+
+// lead polls the backend for local keys and, once per refresh interval, for keys
+// tracked only by other nodes.
+func (c *Coordinator) lead(ctx context.Context, event centrifuge.SharedPollEvent, opts ChannelOptions, poll centrifuge.SharedPollHandler, ttl time.Duration) (centrifuge.SharedPollResult, error) {
+	result, err := poll(ctx, event)
+	if err != nil {
+		return result, err
+	}
+	c.share(ctx, event.Channel, result, ttl)
+	if c.remotePollDue(event.Channel, opts.RefreshInterval) {
+		if err := c.pollRemote(ctx, event.Channel, opts, poll, ttl); err != nil {
+			log.Warn().Err(err).Str("channel", event.Channel).Msg("error polling shared poll keys tracked by other nodes")
+		}
+	}
+	return result, nil
+}
+
+// follow answers refresh from items saved by the leader. Keys without saved items
+// are polled from the backend directly.
+func (c *Coordinator) follow(ctx context.Context, event centrifuge.SharedPollEvent, poll centrifuge.SharedPollHandler, ttl time.Duration) (centrifuge.SharedPollResult, error) {
+	keys := make([]string, 0, len(event.Items))
+	for _, item := range event.Items {
+		keys = append(keys, item.Key)
+	}
+	saved, err := c.store.LoadItems(ctx, event.Channel, keys)
+	if err != nil {
+		return c.fallback(ctx, event, poll, err)
+	}
+
+	var result centrifuge.SharedPollResult
+	missing := event.Items[:0:0]
+	for _, item := range event.Items {
+		s, ok := saved[item.Key]
+		if !ok {
+			missing = append(missing, item)
+			continue
+		}
+		if s.Epoch != "" {
+			result.Epoch = s.Epoch
+		}
+		if s.Removed {
+			result.Items = append(result.Items, centrifuge.SharedPollRefreshItem{Key: s.Key, Removed: true})
+			continue
+		}
+		if s.Version > item.Version {
+			result.Items = append(result.Items, centrifuge.SharedPollRefreshItem{Key: s.Key, Data: s.Data, Version: s.Version})
+		}
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	polled, err := poll(ctx, centrifuge.SharedPollEvent{Channel: event.Channel, Items: missing})
+	if err != nil {
+		if len(result.Items) == 0 {
+			return centrifuge.SharedPollResult{}, err
+		}
+		return result, nil
+	}
+	if err := c.store.SaveItems(ctx, event.Channel, toItems(polled), ttl); err != nil {
+		log.Warn().Err(err).Str("channel", event.Channel).Msg("error saving shared poll items")
+	}
+	result.Items = append(result.Items, polled.Items...)
+	if polled.Epoch != "" {
+		result.Epoch = polled.Epoch
+	}
+	return result, nil
+}
+
+// pollRemote polls the backend for keys tracked in the cluster but not on this node.
+func (c *Coordinator) pollRemote(ctx context.Context, channel string, opts ChannelOptions, poll centrifuge.SharedPollHandler, ttl time.Duration) error {
+	tracked, err := c.store.TrackedKeys(ctx, channel)
+	if err != nil {
+		return err
+	}
+	remote := c.remoteKeys(channel, tracked, opts.RefreshInterval)
+	if len(remote) == 0 {
+		return nil
+	}
+	saved, err := c.store.LoadItems(ctx, channel, remote)
+	if err != nil {
+		return err
+	}
+	batchSize := opts.RefreshBatchSize
+	if batchSize <= 0 {
+		batchSize = len(remote)
+	}
+	var errs []error
+	for start := 0; start < len(remote); start += batchSize {
+		end := min(start+batchSize, len(remote))
+		items := make([]centrifuge.SharedPollItem, 0, end-start)
+		for _, key := range remote[start:end] {
+			items = append(items, centrifuge.SharedPollItem{Key: key, Version: saved[key].Version})
+		}
+		result, err := poll(ctx, centrifuge.SharedPollEvent{Channel: channel, Items: items})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		c.share(ctx, channel, result, ttl)
+	}
+	return errors.Join(errs...)
+}
+
+// share saves poll result in the Store and fans out changed items to all nodes.
+func (c *Coordinator) share(ctx context.Context, channel string, result centrifuge.SharedPollResult, ttl time.Duration) {
+	if len(result.Items) == 0 {
+		return
+	}
+	if err := c.store.SaveItems(ctx, channel, toItems(result), ttl); err != nil {
+		log.Warn().Err(err).Str("channel", channel).Msg("error saving shared poll items")
+	}
+	for _, item := range result.Items {
+		if item.Removed {
+			// Removals are not published, followers pick them from the Store.
+			continue
+		}
+		if err := c.publish(ctx, channel, item.Key, item.Version, result.Epoch, item.Data); err != nil {
+			log.Warn().Err(err).Str("channel", channel).Str("key", item.Key).Msg("error publishing shared poll item")
+		}
+	}
+}
+
+func (c *Coordinator) markLocal(channel string, keys []string) {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	state, ok := c.channels[channel]
+	if !ok {
+		state = &channelState{local: make(map[string]time.Time)}
+		c.channels[channel] = state
+	}
+	state.lastSeen = now
+	for _, key := range keys {
+		state.local[key] = now
+	}
+	if now.Sub(c.lastPrune) > channelIdleTimeout {
+		c.lastPrune = now
+		for ch, s := range c.channels {
+			if now.Sub(s.lastSeen) > channelIdleTimeout {
+				delete(c.channels, ch)
+			}
+		}
+	}
+}
+
+func (c *Coordinator) remotePollDue(channel string, interval time.Duration) bool {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	state, ok := c.channels[channel]
+	if !ok || now.Sub(state.lastRemotePoll) < interval {
+		return false
+	}
+	state.lastRemotePoll = now
+	return true
+}
+
+// remoteKeys filters out keys refreshed locally within the last two refresh intervals.
+// Local keys which were not refreshed for longer are forgotten.
+func (c *Coordinator) remoteKeys(channel string, tracked []string, interval time.Duration) []string {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	state, ok := c.channels[channel]
+	if !ok {
+		return tracked
+	}
+	for key, seen := range state.local {
+		if now.Sub(seen) > 2*interval {
+			delete(state.local, key)
+		}
+	}
+	remote := make([]string, 0, len(tracked))
+	for _, key := range tracked {
+		if _, ok := state.local[key]; !ok {
+			remote = append(remote, key)
+		}
+	}
+	return remote
+}
+
+func toItems(result centrifuge.SharedPollResult) []Item {
+	items := make([]Item, 0, len(result.Items))
+	for _, item := range result.Items {
+		items = append(items, Item{
+			Key:     item.Key,
+			Version: item.Version,
+			Epoch:   result.Epoch,
+			Data:    item.Data,
+			Removed: item.Removed,
+		})
+	}
+	return items
+}
+
+// leaseTTL returns TTL of channel lease and tracked keys. Lease outlives several
+// refresh intervals so that a short delay on the leader does not cause re-election.
+func leaseTTL(refreshInterval time.Duration) time.Duration {
+	return max(3*refreshInterval, minLeaseTTL)
+}
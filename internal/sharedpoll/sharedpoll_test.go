@@ -0,0 +1,259 @@
+package sharedpoll
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	_ = metrics.Init(metrics.Config{
+		Registerer: prometheus.NewRegistry(),
+	})
+	os.Exit(m.Run())
+}
+
+type memoryStore struct {
+	mu     sync.Mutex
+	leases map[string]string
+	keys   map[string]map[string]struct{}
+	items  map[string]map[string]Item
+	err    error
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		leases: make(map[string]string),
+		keys:   make(map[string]map[string]struct{}),
+		items:  make(map[string]map[string]Item),
+	}
+}
+
+func (s *memoryStore) AcquireLease(_ context.Context, channel string, nodeID string, _ time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return false, s.err
+	}
+	holder, ok := s.leases[channel]
+	if !ok {
+		s.leases[channel] = nodeID
+		return true, nil
+	}
+	return holder == nodeID, nil
+}
+
+func (s *memoryStore) TrackKeys(_ context.Context, channel string, keys []string, _ time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	if _, ok := s.keys[channel]; !ok {
+		s.keys[channel] = make(map[string]struct{})
+	}
+	for _, key := range keys {
+		s.keys[channel][key] = struct{}{}
+	}
+	return nil
+}
+
+func (s *memoryStore) TrackedKeys(_ context.Context, channel string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.keys[channel]))
+	for key := range s.keys[channel] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *memoryStore) SaveItems(_ context.Context, channel string, items []Item, _ time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.items[channel]; !ok {
+		s.items[channel] = make(map[string]Item)
+	}
+	for _, item := range items {
+		s.items[channel][item.Key] = item
+	}
+	return nil
+}
+
+func (s *memoryStore) LoadItems(_ context.Context, channel string, keys []string) (map[string]Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	items := make(map[string]Item)
+	for _, key := range keys {
+		if item, ok := s.items[channel][key]; ok {
+			items[key] = item
+		}
+	}
+	return items, nil
+}
+
+type published struct {
+	key     string
+	version uint64
+	data    string
+}
+
+type testBackend struct {
+	mu       sync.Mutex
+	versions map[string]uint64
+	polls    [][]string
+}
+
+func (b *testBackend) poll(_ context.Context, event centrifuge.SharedPollEvent) (centrifuge.SharedPollResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	keys := make([]string, 0, len(event.Items))
+	var items []centrifuge.SharedPollRefreshItem
+	for _, item := range event.Items {
+		keys = append(keys, item.Key)
+		if v := b.versions[item.Key]; v > item.Version {
+			items = append(items, centrifuge.SharedPollRefreshItem{Key: item.Key, Version: v, Data: []byte(`"` + item.Key + `"`)})
+		}
+	}
+	b.polls = append(b.polls, keys)
+	return centrifuge.SharedPollResult{Items: items, Epoch: "e"}, nil
+}
+
+func newTestCoordinator(store Store, nodeID string) (*Coordinator, *[]published) {
+	var mu sync.Mutex
+	var pubs []published
+	c := newCoordinator(store, nodeID, func(_ context.Context, _ string, key string, version uint64, _ string, data []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		pubs = append(pubs, published{key: key, version: version, data: string(data)})
+		return nil
+	})
+	return c, &pubs
+}
+
+var testOpts = ChannelOptions{RefreshInterval: time.Second, RefreshBatchSize: 2}
+
+func TestCoordinator_LeaderPollsAndShares(t *testing.T) {
+	store := newMemoryStore()
+	backend := &testBackend{versions: map[string]uint64{"a": 1}}
+	leader, pubs := newTestCoordinator(store, "n1")
+
+	result, err := leader.Handle(context.Background(), centrifuge.SharedPollEvent{
+		Channel: "ch",
+		Items:   []centrifuge.SharedPollItem{{Key: "a"}},
+	}, testOpts, backend.poll)
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	require.Equal(t, uint64(1), result.Items[0].Version)
+	require.Equal(t, []published{{key: "a", version: 1, data: `"a"`}}, *pubs)
+	require.Equal(t, uint64(1), store.items["ch"]["a"].Version)
+}
+
+func TestCoordinator_FollowerUsesStore(t *testing.T) {
+	store := newMemoryStore()
+	backend := &testBackend{versions: map[string]uint64{"a": 2, "b": 1}}
+	leader, _ := newTestCoordinator(store, "n1")
+	follower, followerPubs := newTestCoordinator(store, "n2")
+
+	_, err := leader.Handle(context.Background(), centrifuge.SharedPollEvent{
+		Channel: "ch",
+		Items:   []centrifuge.SharedPollItem{{Key: "a"}},
+	}, testOpts, backend.poll)
+	require.NoError(t, err)
+	require.Len(t, backend.polls, 1)
+
+	result, err := follower.Handle(context.Background(), centrifuge.SharedPollEvent{
+		Channel: "ch",
+		Items:   []centrifuge.SharedPollItem{{Key: "a", Version: 1}, {Key: "b"}},
+	}, testOpts, backend.poll)
+	require.NoError(t, err)
+	require.Len(t, result.Items, 2)
+	require.Equal(t, "e", result.Epoch)
+	// Key a is answered from the store, only key b not polled by leader yet goes to backend.
+	require.Equal(t, []string{"b"}, backend.polls[1])
+	require.Empty(t, *followerPubs)
+
+	// Up to date follower gets nothing and does not poll backend.
+	result, err = follower.Handle(context.Background(), centrifuge.SharedPollEvent{
+		Channel: "ch",
+		Items:   []centrifuge.SharedPollItem{{Key: "a", Version: 2}, {Key: "b", Version: 1}},
+	}, testOpts, backend.poll)
+	require.NoError(t, err)
+	require.Empty(t, result.Items)
+	require.Len(t, backend.polls, 2)
+}
+
+func TestCoordinator_FollowerGetsRemoved(t *testing.T) {
+	store := newMemoryStore()
+	require.NoError(t, store.SaveItems(context.Background(), "ch", []Item{{Key: "a", Removed: true}}, time.Second))
+	store.leases["ch"] = "n1"
+	follower, _ := newTestCoordinator(store, "n2")
+
+	result, err := follower.Handle(context.Background(), centrifuge.SharedPollEvent{
+		Channel: "ch",
+		Items:   []centrifuge.SharedPollItem{{Key: "a", Version: 3}},
+	}, testOpts, func(context.Context, centrifuge.SharedPollEvent) (centrifuge.SharedPollResult, error) {
+		return centrifuge.SharedPollResult{}, errors.New("unexpected poll")
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	require.True(t, result.Items[0].Removed)
+}
+
+func TestCoordinator_LeaderPollsRemoteKeys(t *testing.T) {
+	store := newMemoryStore()
+	backend := &testBackend{versions: map[string]uint64{"a": 1, "b": 1, "c": 1, "d": 1}}
+	leader, pubs := newTestCoordinator(store, "n1")
+	require.NoError(t, store.TrackKeys(context.Background(), "ch", []string{"b", "c", "d"}, time.Second))
+
+	_, err := leader.Handle(context.Background(), centrifuge.SharedPollEvent{
+		Channel: "ch",
+		Items:   []centrifuge.SharedPollItem{{Key: "a"}},
+	}, testOpts, backend.poll)
+	require.NoError(t, err)
+	// Local key, then remote keys in batches of RefreshBatchSize.
+	require.Equal(t, [][]string{{"a"}, {"b", "c"}, {"d"}}, backend.polls)
+	require.Len(t, *pubs, 4)
+
+	// Remote keys are polled once per refresh interval.
+	_, err = leader.Handle(context.Background(), centrifuge.SharedPollEvent{
+		Channel: "ch",
+		Items:   []centrifuge.SharedPollItem{{Key: "a", Version: 1}},
+	}, testOpts, backend.poll)
+	require.NoError(t, err)
+	require.Len(t, backend.polls, 4)
+}
+
+func TestCoordinator_StoreErrorFallback(t *testing.T) {
+	store := newMemoryStore()
+	store.err = errors.New("boom")
+	backend := &testBackend{versions: map[string]uint64{"a": 1}}
+	c, pubs := newTestCoordinator(store, "n1")
+
+	result, err := c.Handle(context.Background(), centrifuge.SharedPollEvent{
+		Channel: "ch",
+		Items:   []centrifuge.SharedPollItem{{Key: "a"}},
+	}, testOpts, backend.poll)
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	require.Empty(t, *pubs)
+}
+
+func TestLeaseTTL(t *testing.T) {
+	require.Equal(t, minLeaseTTL, leaseTTL(time.Second))
+	require.Equal(t, 30*time.Second, leaseTTL(10*time.Second))
+}
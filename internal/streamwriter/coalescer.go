@@ -0,0 +1,145 @@
+package streamwriter
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultWriteTimeout is used for writes of coalesced data to connection.
+const DefaultWriteTimeout = time.Second
+
+// Middleware coalesces writes of streaming handlers which can't use Writer
+// directly, such as bidirectional SSE and HTTP-stream handlers of centrifuge
+// library. Handler flushes are delayed for coalescing window, data written in
+// between is buffered and goes to connection in one write. With zero window
+// handler is returned as is.
+func Middleware(c Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if c.Window <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cw := newCoalescingResponseWriter(w, DefaultWriteTimeout, c)
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// coalescingResponseWriter is http.ResponseWriter implementing the same batching
+// as Writer for handlers which call Flush after each message. Unlike Writer it's
+// safe for concurrent use since delayed flushes happen in timer goroutine.
+type coalescingResponseWriter struct {
+	http.ResponseWriter
+	rc           *http.ResponseController
+	writeTimeout time.Duration
+	window       time.Duration
+	maxBytes     int
+
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	timer     *time.Timer
+	scheduled bool
+	closed    bool
+	err       error
+}
+
+func newCoalescingResponseWriter(w http.ResponseWriter, writeTimeout time.Duration, c Config) *coalescingResponseWriter {
+	maxBytes := c.MaxBatchBytes
+	if maxBytes == 0 {
+		maxBytes = DefaultMaxBatchBytes
+	}
+	return &coalescingResponseWriter{
+		ResponseWriter: w,
+		rc:             http.NewResponseController(w),
+		writeTimeout:   writeTimeout,
+		window:         c.Window.ToDuration(),
+		maxBytes:       maxBytes,
+	}
+}
+
+// Write appends data to the pending batch. Error of previous delayed flush is
+// returned so handler notices broken connection.
+func (w *coalescingResponseWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return 0, w.err
+	}
+	return w.buf.Write(p)
+}
+
+// Flush ...
+func (w *coalescingResponseWriter) Flush() {
+	_ = w.FlushError()
+}
+
+// FlushError writes pending data if batch is full, otherwise schedules flush at
+// the end of coalescing window. Used by http.ResponseController.
+func (w *coalescingResponseWriter) FlushError() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+	if w.buf.Len() >= w.maxBytes {
+		w.err = w.flushLocked()
+		return w.err
+	}
+	if !w.scheduled {
+		w.scheduled = true
+		if w.timer == nil {
+			w.timer = time.AfterFunc(w.window, w.onTimer)
+		} else {
+			w.timer.Reset(w.window)
+		}
+	}
+	return nil
+}
+
+// Unwrap allows http.ResponseController to reach underlying writer, e.g. to set
+// deadlines.
+func (w *coalescingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *coalescingResponseWriter) onTimer() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed || !w.scheduled || w.err != nil {
+		return
+	}
+	w.err = w.flushLocked()
+}
+
+func (w *coalescingResponseWriter) flushLocked() error {
+	if w.scheduled {
+		w.scheduled = false
+		w.timer.Stop()
+	}
+	_ = w.rc.SetWriteDeadline(time.Now().Add(w.writeTimeout))
+	defer func() { _ = w.rc.SetWriteDeadline(time.Time{}) }()
+	if w.buf.Len() > 0 {
+		defer w.buf.Reset()
+		if _, err := w.ResponseWriter.Write(w.buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return w.rc.Flush()
+}
+
+// close delivers pending data (e.g. disconnect sent right before handler
+// returned) and stops timer. Must be called before handler returns to server.
+func (w *coalescingResponseWriter) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	if w.err == nil && (w.scheduled || w.buf.Len() > 0) {
+		w.err = w.flushLocked()
+	}
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+}
@@ -0,0 +1,94 @@
+package streamwriter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+
+	"github.com/stretchr/testify/require"
+)
+
+// syncRecorder is httptest.ResponseRecorder safe to inspect while delayed
+// flush may write to it.
+type syncRecorder struct {
+	mu  sync.Mutex
+	rec *httptest.ResponseRecorder
+}
+
+func (r *syncRecorder) Header() http.Header { return r.rec.Header() }
+
+func (r *syncRecorder) WriteHeader(code int) { r.rec.WriteHeader(code) }
+
+func (r *syncRecorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rec.Write(p)
+}
+
+func (r *syncRecorder) Flush() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rec.Flush()
+}
+
+func (r *syncRecorder) body() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rec.Body.String()
+}
+
+func TestMiddleware_NoCoalescing(t *testing.T) {
+	rec := &syncRecorder{rec: httptest.NewRecorder()}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok := w.(*coalescingResponseWriter)
+		require.False(t, ok)
+		_, _ = w.Write([]byte("a"))
+		require.Equal(t, "a", rec.body())
+	})
+	Middleware(Config{})(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestMiddleware_Window(t *testing.T) {
+	rec := &syncRecorder{rec: httptest.NewRecorder()}
+	flushed := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rc := http.NewResponseController(w)
+		_, _ = w.Write([]byte("a"))
+		require.NoError(t, rc.Flush())
+		_, _ = w.Write([]byte("b"))
+		require.NoError(t, rc.Flush())
+		require.Equal(t, "", rec.body())
+		require.Eventually(t, func() bool { return rec.body() == "ab" }, time.Second, time.Millisecond)
+		close(flushed)
+	})
+	Middleware(Config{Window: configtypes.Duration(10 * time.Millisecond)})(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	<-flushed
+	require.True(t, rec.rec.Flushed)
+}
+
+func TestMiddleware_MaxBatchBytes(t *testing.T) {
+	rec := &syncRecorder{rec: httptest.NewRecorder()}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("abc"))
+		w.(http.Flusher).Flush()
+		require.Equal(t, "", rec.body())
+		_, _ = w.Write([]byte("d"))
+		w.(http.Flusher).Flush()
+		require.Equal(t, "abcd", rec.body())
+	})
+	Middleware(Config{Window: configtypes.Duration(time.Hour), MaxBatchBytes: 4})(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestMiddleware_FlushOnReturn(t *testing.T) {
+	rec := &syncRecorder{rec: httptest.NewRecorder()}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("disconnect"))
+		w.(http.Flusher).Flush()
+	})
+	Middleware(Config{Window: configtypes.Duration(time.Hour)})(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, "disconnect", rec.body())
+}
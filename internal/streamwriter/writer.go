@@ -0,0 +1,113 @@
+// Package streamwriter coalesces writes of streaming HTTP transports so that
+// several messages sent to a connection within a short window go to network
+// in one write and flush.
+package streamwriter
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/timers"
+)
+
+// DefaultMaxBatchBytes is used when coalescing is enabled but max batch size
+// is not set. It's close to the maximum TLS record payload size.
+const DefaultMaxBatchBytes = 16384
+
+type Config = configtypes.WriteCoalescing
+
+// Writer buffers data written to HTTP response and flushes it when coalescing
+// window elapses or buffered data reaches max batch size. Writer is not safe for
+// concurrent use – it's supposed to be used from a connection handler goroutine.
+type Writer struct {
+	w            http.ResponseWriter
+	rc           *http.ResponseController
+	writeTimeout time.Duration
+	window       time.Duration
+	maxBytes     int
+	buf          bytes.Buffer
+	timer        *time.Timer
+}
+
+// New creates Writer. With zero coalescing window every Flush call writes data
+// to connection immediately.
+func New(w http.ResponseWriter, writeTimeout time.Duration, c Config) *Writer {
+	maxBytes := c.MaxBatchBytes
+	if maxBytes == 0 {
+		maxBytes = DefaultMaxBatchBytes
+	}
+	return &Writer{
+		w:            w,
+		rc:           http.NewResponseController(w),
+		writeTimeout: writeTimeout,
+		window:       c.Window.ToDuration(),
+		maxBytes:     maxBytes,
+	}
+}
+
+// Write appends data to the pending batch.
+func (w *Writer) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+// WriteString appends string to the pending batch.
+func (w *Writer) WriteString(s string) (int, error) {
+	return w.buf.WriteString(s)
+}
+
+// MaybeFlush should be called after a portion of messages was written. It
+// flushes pending data if coalescing is disabled or batch is full, otherwise
+// starts coalescing timer if it's not running yet.
+func (w *Writer) MaybeFlush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	if w.window <= 0 || w.buf.Len() >= w.maxBytes {
+		return w.Flush()
+	}
+	if w.timer == nil {
+		w.timer = timers.AcquireTimer(w.window)
+	}
+	return nil
+}
+
+// C returns a channel which receives a value when pending data must be
+// flushed. Returns nil channel if there is nothing pending, so it's safe to
+// use in select statement.
+func (w *Writer) C() <-chan time.Time {
+	if w.timer == nil {
+		return nil
+	}
+	return w.timer.C
+}
+
+// Flush writes pending data to connection and flushes it.
+func (w *Writer) Flush() error {
+	w.stopTimer()
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	defer w.buf.Reset()
+	_ = w.rc.SetWriteDeadline(time.Now().Add(w.writeTimeout))
+	if _, err := w.w.Write(w.buf.Bytes()); err != nil {
+		return err
+	}
+	err := w.rc.Flush()
+	_ = w.rc.SetWriteDeadline(time.Time{})
+	return err
+}
+
+// Close releases Writer resources. Pending data is discarded.
+func (w *Writer) Close() {
+	w.stopTimer()
+	w.buf.Reset()
+}
+
+func (w *Writer) stopTimer() {
+	if w.timer != nil {
+		timers.ReleaseTimer(w.timer)
+		w.timer = nil
+	}
+}
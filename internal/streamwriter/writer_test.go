@@ -0,0 +1,61 @@
+package streamwriter
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter_NoCoalescing(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := New(rec, time.Second, Config{})
+	defer w.Close()
+
+	_, _ = w.WriteString("hello")
+	require.Equal(t, 0, rec.Body.Len())
+	require.NoError(t, w.MaybeFlush())
+	require.Equal(t, "hello", rec.Body.String())
+	require.True(t, rec.Flushed)
+	require.Nil(t, w.C())
+}
+
+func TestWriter_Window(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := New(rec, time.Second, Config{Window: configtypes.Duration(10 * time.Millisecond)})
+	defer w.Close()
+
+	_, _ = w.WriteString("a")
+	require.NoError(t, w.MaybeFlush())
+	_, _ = w.WriteString("b")
+	require.NoError(t, w.MaybeFlush())
+	require.Equal(t, 0, rec.Body.Len())
+
+	select {
+	case <-w.C():
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for coalescing window")
+	}
+	require.NoError(t, w.Flush())
+	require.Equal(t, "ab", rec.Body.String())
+	require.Nil(t, w.C())
+}
+
+func TestWriter_MaxBatchBytes(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := New(rec, time.Second, Config{Window: configtypes.Duration(time.Hour), MaxBatchBytes: 4})
+	defer w.Close()
+
+	_, _ = w.WriteString("abc")
+	require.NoError(t, w.MaybeFlush())
+	require.Equal(t, 0, rec.Body.Len())
+	require.NotNil(t, w.C())
+
+	_, _ = w.WriteString("d")
+	require.NoError(t, w.MaybeFlush())
+	require.Equal(t, "abcd", rec.Body.String())
+	require.Nil(t, w.C())
+}
@@ -2,6 +2,7 @@ package survey
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/centrifugal/centrifugo/v6/internal/apiproto"
@@ -17,6 +18,7 @@ type Handler func(node *centrifuge.Node, data []byte) centrifuge.SurveyReply
 type Caller struct {
 	node     *centrifuge.Node
 	handlers map[string]Handler
+	blocking map[string]bool
 }
 
 func NewCaller(node *centrifuge.Node) *Caller {
@@ -25,6 +27,7 @@ func NewCaller(node *centrifuge.Node) *Caller {
 		handlers: map[string]Handler{
 			"channels": respondChannelsSurvey,
 		},
+		blocking: map[string]bool{},
 	}
 	c.node.OnSurvey(func(event centrifuge.SurveyEvent, cb centrifuge.SurveyCallback) {
 		h, ok := c.handlers[event.Op]
@@ -32,11 +35,46 @@ func NewCaller(node *centrifuge.Node) *Caller {
 			cb(centrifuge.SurveyReply{Code: MethodNotFound})
 			return
 		}
-		cb(h(c.node, event.Data))
+		if !c.blocking[event.Op] {
+			cb(h(c.node, event.Data))
+			return
+		}
+		go func() {
+			cb(h(c.node, event.Data))
+		}()
 	})
 	return c
 }
 
+// RegisterBlockingHandler registers handler for survey operation which may wait
+// for events for a long time (for example, long poll handler waits for client
+// messages). Such handlers are called in a separate goroutine to not block node.
+// Must be called before node is run.
+func (c *Caller) RegisterBlockingHandler(op string, h Handler) {
+	c.handlers[op] = h
+	c.blocking[op] = true
+}
+
+// ErrNodeNotFound returned when surveyed node did not answer, i.e. it is not
+// in the cluster anymore.
+var ErrNodeNotFound = errors.New("node not found")
+
+// NodeSurvey sends survey to a single node and returns its reply data.
+func (c *Caller) NodeSurvey(ctx context.Context, op string, data []byte, nodeID string) ([]byte, error) {
+	results, err := c.node.Survey(ctx, op, data, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	result, ok := results[nodeID]
+	if !ok {
+		return nil, ErrNodeNotFound
+	}
+	if result.Code > 0 {
+		return nil, fmt.Errorf("non-zero code from node %s: %d", nodeID, result.Code)
+	}
+	return result.Data, nil
+}
+
 const (
 	InternalError  uint32 = 1
 	InvalidRequest uint32 = 2
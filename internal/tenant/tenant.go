@@ -0,0 +1,149 @@
+// Package tenant keeps token verifiers of tenants and enforces per-tenant limits
+// on a node.
+package tenant
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/centrifugal/centrifugo/v6/internal/config"
+	"github.com/centrifugal/centrifugo/v6/internal/confighelpers"
+	"github.com/centrifugal/centrifugo/v6/internal/jwtverify"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+
+	"golang.org/x/time/rate"
+)
+
+// Registry of tenant token verifiers, connection counters and publish rate limiters.
+// Limits are node-local.
+type Registry struct {
+	cfgContainer *config.Container
+
+	mu          sync.RWMutex
+	verifiers   map[string]*jwtverify.VerifierJWT
+	limiters    map[string]*rate.Limiter
+	connections map[string]int
+}
+
+// New creates Registry for tenants in current configuration.
+func New(cfgContainer *config.Container) (*Registry, error) {
+	r := &Registry{
+		cfgContainer: cfgContainer,
+		verifiers:    make(map[string]*jwtverify.VerifierJWT),
+		limiters:     make(map[string]*rate.Limiter),
+		connections:  make(map[string]int),
+	}
+	if err := r.Reload(cfgContainer.Config()); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload updates token verifiers and publish rate limiters of tenants according
+// to cfg. Connection counters of existing tenants are kept.
+func (r *Registry) Reload(cfg config.Config) error {
+	verifiers := make(map[string]*jwtverify.VerifierJWT)
+	limiters := make(map[string]*rate.Limiter)
+	if cfg.Tenancy.Enabled {
+		for _, t := range cfg.Tenants {
+			verifierConfig, err := confighelpers.MakeVerifierConfig(t.Token)
+			if err != nil {
+				return fmt.Errorf("tenant %s: error creating JWT verifier config: %w", t.Name, err)
+			}
+			r.mu.RLock()
+			verifier, verifierFound := r.verifiers[t.Name]
+			limiter, limiterFound := r.limiters[t.Name]
+			r.mu.RUnlock()
+			if verifierFound {
+				err = verifier.Reload(verifierConfig)
+			} else {
+				verifier, err = jwtverify.NewTokenVerifierJWT(verifierConfig, r.cfgContainer)
+			}
+			if err != nil {
+				return fmt.Errorf("tenant %s: %w", t.Name, err)
+			}
+			verifiers[t.Name] = verifier
+			if t.PublishRateLimit > 0 {
+				if !limiterFound || limiter.Burst() != t.PublishRateLimit {
+					limiter = rate.NewLimiter(rate.Limit(t.PublishRateLimit), t.PublishRateLimit)
+				}
+				limiters[t.Name] = limiter
+			}
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.verifiers = verifiers
+	r.limiters = limiters
+	return nil
+}
+
+// Verifier returns token verifier of tenant.
+func (r *Registry) Verifier(tenant string) (*jwtverify.VerifierJWT, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	v, ok := r.verifiers[tenant]
+	return v, ok
+}
+
+// ConnectionAllowed checks whether one more connection of tenant fits into tenant
+// connection limit. Connections checked concurrently may slightly exceed limit.
+func (r *Registry) ConnectionAllowed(tenant string) bool {
+	t, ok := r.cfgContainer.Tenant(tenant)
+	if !ok || t.ConnectionLimit == 0 {
+		return true
+	}
+	r.mu.RLock()
+	numConnections := r.connections[tenant]
+	r.mu.RUnlock()
+	if numConnections >= t.ConnectionLimit {
+		metrics.IncTenantLimitRejected(tenant, "connection")
+		return false
+	}
+	return true
+}
+
+// AddConnection counts established connection of tenant.
+func (r *Registry) AddConnection(tenant string) {
+	r.mu.Lock()
+	r.connections[tenant]++
+	numConnections := r.connections[tenant]
+	r.mu.Unlock()
+	metrics.SetTenantConnections(tenant, numConnections)
+}
+
+// RemoveConnection stops counting closed connection of tenant.
+func (r *Registry) RemoveConnection(tenant string) {
+	r.mu.Lock()
+	r.connections[tenant]--
+	numConnections := r.connections[tenant]
+	if numConnections <= 0 {
+		delete(r.connections, tenant)
+		numConnections = 0
+	}
+	r.mu.Unlock()
+	metrics.SetTenantConnections(tenant, numConnections)
+}
+
+// ChannelAllowed checks whether tenant connection subscribed to numChannels channels
+// can subscribe to one more channel.
+func (r *Registry) ChannelAllowed(tenant string, numChannels int) bool {
+	t, ok := r.cfgContainer.Tenant(tenant)
+	if !ok || t.ChannelLimit == 0 || numChannels < t.ChannelLimit {
+		return true
+	}
+	metrics.IncTenantLimitRejected(tenant, "channel")
+	return false
+}
+
+// PublishAllowed checks tenant publish rate limit.
+func (r *Registry) PublishAllowed(tenant string) bool {
+	r.mu.RLock()
+	limiter, ok := r.limiters[tenant]
+	r.mu.RUnlock()
+	if !ok || limiter.Allow() {
+		return true
+	}
+	metrics.IncTenantLimitRejected(tenant, "publish_rate")
+	return false
+}
@@ -0,0 +1,109 @@
+package tenant
+
+import (
+	"os"
+	"testing"
+
+	"github.com/centrifugal/centrifugo/v6/internal/config"
+	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	_ = metrics.Init(metrics.Config{
+		Registerer: prometheus.NewRegistry(),
+	})
+	os.Exit(m.Run())
+}
+
+func testConfig() config.Config {
+	cfg := config.DefaultConfig()
+	cfg.Tenancy.Enabled = true
+	cfg.Tenants = []configtypes.Tenant{
+		{
+			Name:             "acme",
+			Hosts:            []string{"acme.example.com"},
+			Token:            configtypes.Token{HMACSecretKey: "acme-secret"},
+			ConnectionLimit:  2,
+			ChannelLimit:     1,
+			PublishRateLimit: 1,
+		},
+		{
+			Name:  "globex",
+			Hosts: []string{"globex.example.com"},
+			Token: configtypes.Token{HMACSecretKey: "globex-secret"},
+		},
+	}
+	return cfg
+}
+
+func newTestRegistry(t *testing.T) (*Registry, *config.Container) {
+	cfgContainer, err := config.NewContainer(testConfig())
+	require.NoError(t, err)
+	r, err := New(cfgContainer)
+	require.NoError(t, err)
+	return r, cfgContainer
+}
+
+func TestRegistryVerifier(t *testing.T) {
+	r, _ := newTestRegistry(t)
+	acmeVerifier, ok := r.Verifier("acme")
+	require.True(t, ok)
+	globexVerifier, ok := r.Verifier("globex")
+	require.True(t, ok)
+	require.NotSame(t, acmeVerifier, globexVerifier)
+	_, ok = r.Verifier("unknown")
+	require.False(t, ok)
+}
+
+func TestRegistryConnectionLimit(t *testing.T) {
+	r, _ := newTestRegistry(t)
+	require.True(t, r.ConnectionAllowed("acme"))
+	r.AddConnection("acme")
+	r.AddConnection("acme")
+	require.False(t, r.ConnectionAllowed("acme"))
+	require.True(t, r.ConnectionAllowed("globex"))
+	r.RemoveConnection("acme")
+	require.True(t, r.ConnectionAllowed("acme"))
+}
+
+func TestRegistryChannelLimit(t *testing.T) {
+	r, _ := newTestRegistry(t)
+	require.True(t, r.ChannelAllowed("acme", 0))
+	require.False(t, r.ChannelAllowed("acme", 1))
+	require.True(t, r.ChannelAllowed("globex", 100))
+}
+
+func TestRegistryPublishRateLimit(t *testing.T) {
+	r, _ := newTestRegistry(t)
+	require.True(t, r.PublishAllowed("acme"))
+	require.False(t, r.PublishAllowed("acme"))
+	require.True(t, r.PublishAllowed("globex"))
+	require.True(t, r.PublishAllowed("globex"))
+}
+
+func TestRegistryReload(t *testing.T) {
+	r, cfgContainer := newTestRegistry(t)
+	acmeVerifier, _ := r.Verifier("acme")
+	r.AddConnection("acme")
+
+	cfg := testConfig()
+	cfg.Tenants = cfg.Tenants[:1]
+	cfg.Tenants[0].PublishRateLimit = 0
+	require.NoError(t, r.Reload(cfg))
+	require.NoError(t, cfgContainer.Reload(cfg))
+
+	verifier, ok := r.Verifier("acme")
+	require.True(t, ok)
+	require.Same(t, acmeVerifier, verifier)
+	_, ok = r.Verifier("globex")
+	require.False(t, ok)
+	for i := 0; i < 10; i++ {
+		require.True(t, r.PublishAllowed("acme"))
+	}
+	r.AddConnection("acme")
+	require.False(t, r.ConnectionAllowed("acme"))
+}
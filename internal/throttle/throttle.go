@@ -0,0 +1,114 @@
+// Package throttle implements publication coalescing used for server-side
+// delivery throttling of high-frequency channels.
+package throttle
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Throttler coalesces functions submitted under the same key within a time window.
+// The first submission in a window is run by the caller immediately. Functions submitted
+// later within the same window replace each other, and only the latest one runs when the
+// window ends – this opens a new window. A key is forgotten once a window ends without
+// pending work. Throttler keeps state in memory, so coalescing happens per node.
+type Throttler struct {
+	mu      sync.Mutex
+	windows map[string]*window
+	closed  bool
+}
+
+type window struct {
+	timer   *time.Timer
+	pending func()
+}
+
+// New creates Throttler.
+func New() *Throttler {
+	return &Throttler{
+		windows: make(map[string]*window),
+	}
+}
+
+// Key builds a coalescing key for a publication in channel. If tagKey is not empty,
+// then publications are coalesced per channel and value of the tag.
+func Key(channel string, tagKey string, tags map[string]string) string {
+	if tagKey == "" {
+		return channel
+	}
+	var sb strings.Builder
+	value := tags[tagKey]
+	sb.Grow(len(channel) + len(value) + 1)
+	sb.WriteString(channel)
+	sb.WriteByte(0)
+	sb.WriteString(value)
+	return sb.String()
+}
+
+// Submit returns true if there is no active window for key – in this case a new window
+// starts and the caller is expected to run its action right away, fn is not stored.
+// Otherwise fn is stored to be run when the current window ends, replacing previously
+// stored function (if any), and Submit returns false. The replaced flag tells whether
+// a previously pending function was dropped as a result of this call.
+func (t *Throttler) Submit(key string, interval time.Duration, fn func()) (runNow bool, replaced bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return true, false
+	}
+	w, ok := t.windows[key]
+	if ok {
+		replaced = w.pending != nil
+		w.pending = fn
+		return false, replaced
+	}
+	w = &window{}
+	w.timer = time.AfterFunc(interval, func() {
+		t.flush(key, w, interval)
+	})
+	t.windows[key] = w
+	return true, false
+}
+
+func (t *Throttler) flush(key string, w *window, interval time.Duration) {
+	t.mu.Lock()
+	if t.windows[key] != w {
+		t.mu.Unlock()
+		return
+	}
+	fn := w.pending
+	if fn == nil {
+		delete(t.windows, key)
+		t.mu.Unlock()
+		return
+	}
+	w.pending = nil
+	t.mu.Unlock()
+	fn()
+	// Start the next window only after fn returned to keep delivery order for key.
+	t.mu.Lock()
+	if t.windows[key] == w {
+		w.timer.Reset(interval)
+	}
+	t.mu.Unlock()
+}
+
+// Close stops all windows and runs pending functions so that latest coalesced
+// state is not lost. After Close Submit always returns true.
+func (t *Throttler) Close() {
+	t.mu.Lock()
+	t.closed = true
+	var pending []func()
+	for key, w := range t.windows {
+		w.timer.Stop()
+		if w.pending != nil {
+			pending = append(pending, w.pending)
+		}
+		delete(t.windows, key)
+	}
+	t.mu.Unlock()
+	for _, fn := range pending {
+		fn()
+	}
+}
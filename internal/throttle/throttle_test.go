@@ -0,0 +1,87 @@
+package throttle
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey(t *testing.T) {
+	require.Equal(t, "ch", Key("ch", "", map[string]string{"symbol": "BTC"}))
+	require.Equal(t, "ch\x00BTC", Key("ch", "symbol", map[string]string{"symbol": "BTC"}))
+	require.Equal(t, "ch\x00", Key("ch", "symbol", nil))
+	require.NotEqual(t, Key("ch", "symbol", map[string]string{"symbol": "BTC"}), Key("ch", "symbol", map[string]string{"symbol": "ETH"}))
+}
+
+func TestThrottlerCoalesces(t *testing.T) {
+	th := New()
+	defer th.Close()
+
+	var mu sync.Mutex
+	var delivered []int
+	deliver := func(i int) func() {
+		return func() {
+			mu.Lock()
+			delivered = append(delivered, i)
+			mu.Unlock()
+		}
+	}
+
+	runNow, replaced := th.Submit("ch", 50*time.Millisecond, deliver(1))
+	require.True(t, runNow)
+	require.False(t, replaced)
+
+	runNow, replaced = th.Submit("ch", 50*time.Millisecond, deliver(2))
+	require.False(t, runNow)
+	require.False(t, replaced)
+
+	runNow, replaced = th.Submit("ch", 50*time.Millisecond, deliver(3))
+	require.False(t, runNow)
+	require.True(t, replaced)
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(delivered) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	mu.Lock()
+	require.Equal(t, []int{3}, delivered)
+	mu.Unlock()
+
+	// Window is forgotten after it ends without pending work.
+	require.Eventually(t, func() bool {
+		th.mu.Lock()
+		defer th.mu.Unlock()
+		return len(th.windows) == 0
+	}, time.Second, 5*time.Millisecond)
+
+	runNow, _ = th.Submit("ch", 50*time.Millisecond, deliver(4))
+	require.True(t, runNow)
+}
+
+func TestThrottlerKeysIndependent(t *testing.T) {
+	th := New()
+	defer th.Close()
+	runNow, _ := th.Submit("a", time.Minute, func() {})
+	require.True(t, runNow)
+	runNow, _ = th.Submit("b", time.Minute, func() {})
+	require.True(t, runNow)
+	runNow, _ = th.Submit("a", time.Minute, func() {})
+	require.False(t, runNow)
+}
+
+func TestThrottlerCloseFlushesPending(t *testing.T) {
+	th := New()
+	runNow, _ := th.Submit("ch", time.Minute, func() {})
+	require.True(t, runNow)
+	flushed := false
+	runNow, _ = th.Submit("ch", time.Minute, func() { flushed = true })
+	require.False(t, runNow)
+	th.Close()
+	require.True(t, flushed)
+	runNow, _ = th.Submit("ch", time.Minute, func() {})
+	require.True(t, runNow)
+}
@@ -214,6 +214,7 @@ type TestClientMock struct {
 	IDFunc           func() string
 	UserIDFunc       func() string
 	IsSubscribedFunc func(string) bool
+	ChannelsFunc     func() []string
 	ContextFunc      func() context.Context
 	TransportFunc    func() centrifuge.TransportInfo
 	storageMu        sync.Mutex
@@ -256,6 +257,13 @@ func (m *TestClientMock) IsSubscribed(s string) bool {
 	panic("not implemented")
 }
 
+func (m *TestClientMock) Channels() []string {
+	if m.ChannelsFunc != nil {
+		return m.ChannelsFunc()
+	}
+	panic("not implemented")
+}
+
 func (m *TestClientMock) Context() context.Context {
 	if m.ContextFunc != nil {
 		return m.ContextFunc()
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/centrifugal/centrifugo/v6/internal/logging"
+	"github.com/centrifugal/centrifugo/v6/internal/streamwriter"
 	"github.com/centrifugal/centrifugo/v6/internal/tools"
 
 	"github.com/centrifugal/centrifuge"
@@ -114,8 +115,6 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Expire", "0")
 	w.WriteHeader(http.StatusOK)
 
-	rc := http.NewResponseController(w)
-
 	sendAck := func() {
 		select {
 		case ack <- struct{}{}:
@@ -123,33 +122,35 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	sw := streamwriter.New(w, streamWriteTimeout, h.config.WriteCoalescing)
+	defer sw.Close()
+
 	for {
 		select {
 		case <-r.Context().Done():
 			return
 		case <-transport.disconnectCh:
+			// Deliver messages sent right before disconnect.
+			_ = sw.Flush()
 			return
+		case <-sw.C():
+			if err := sw.Flush(); err != nil {
+				return
+			}
 		case messages, messagesOK := <-transport.messages:
 			if !messagesOK {
 				sendAck()
 				return
 			}
-			_ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
 			for _, msg := range messages {
-				_, err = w.Write(msg)
-				if err != nil {
-					sendAck()
-					return
-				}
-				_, err = w.Write([]byte("\n"))
-				if err != nil {
-					sendAck()
-					return
-				}
+				_, _ = sw.Write(msg)
+				_, _ = sw.WriteString("\n")
 			}
-			_ = rc.Flush()
-			_ = rc.SetWriteDeadline(time.Time{})
+			err = sw.MaybeFlush()
 			sendAck()
+			if err != nil {
+				return
+			}
 		}
 	}
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/centrifugal/centrifugo/v6/internal/convert"
 	"github.com/centrifugal/centrifugo/v6/internal/logging"
+	"github.com/centrifugal/centrifugo/v6/internal/streamwriter"
 	"github.com/centrifugal/centrifugo/v6/internal/tools"
 
 	"github.com/centrifugal/centrifuge"
@@ -142,28 +143,36 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	sw := streamwriter.New(w, streamWriteTimeout, h.config.WriteCoalescing)
+	defer sw.Close()
+
 	for {
 		select {
 		case <-r.Context().Done():
 			return
 		case <-transport.disconnectCh:
+			// Deliver messages sent right before disconnect.
+			_ = sw.Flush()
 			return
+		case <-sw.C():
+			if err := sw.Flush(); err != nil {
+				return
+			}
 		case messages, messagesOK := <-transport.messages:
 			if !messagesOK {
 				sendAck()
 				return
 			}
-			_ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
 			for _, msg := range messages {
-				_, err = w.Write(convert.StringToBytes("data: " + convert.BytesToString(msg) + "\n\n"))
-				if err != nil {
-					sendAck()
-					return
-				}
+				_, _ = sw.WriteString("data: ")
+				_, _ = sw.Write(msg)
+				_, _ = sw.WriteString("\n\n")
 			}
-			_ = rc.Flush()
-			_ = rc.SetWriteDeadline(time.Time{})
+			err = sw.MaybeFlush()
 			sendAck()
+			if err != nil {
+				return
+			}
 		}
 	}
 }
@@ -187,3 +187,48 @@ func TestUnidirectionalSSE(t *testing.T) {
 		}
 	})
 }
+
+func TestUnidirectionalSSE_WriteCoalescing(t *testing.T) {
+	t.Parallel()
+	node, err := centrifuge.New(centrifuge.Config{})
+	require.NoError(t, err)
+	node.OnConnecting(func(ctx context.Context, event centrifuge.ConnectEvent) (centrifuge.ConnectReply, error) {
+		return centrifuge.ConnectReply{
+			Credentials: &centrifuge.Credentials{UserID: "42"},
+			Subscriptions: map[string]centrifuge.SubscribeOptions{
+				"test": {},
+			},
+		}, nil
+	})
+	require.NoError(t, node.Run())
+	t.Cleanup(func() { _ = node.Shutdown(context.Background()) })
+
+	config := configtypes.UniSSE{
+		MaxRequestBodySize: 65536,
+		WriteCoalescing: configtypes.WriteCoalescing{
+			Window: configtypes.Duration(50 * time.Millisecond),
+		},
+	}
+	server := httptest.NewServer(NewHandler(node, config, centrifuge.PingPongConfig{}))
+	t.Cleanup(func() { server.Close() })
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	reader := bufio.NewReader(resp.Body)
+	message, err := readSSEMessage(reader)
+	require.NoError(t, err)
+	ensureSSEMessageHasClient(t, message)
+
+	for i := 0; i < 3; i++ {
+		_, err = node.Publish("test", []byte(`{"n":1}`))
+		require.NoError(t, err)
+	}
+	for i := 0; i < 3; i++ {
+		message, err := readSSEMessage(reader)
+		require.NoError(t, err)
+		require.Contains(t, message, `"channel":"test"`)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/centrifugal/centrifugo/v6/internal/convert"
 	"github.com/centrifugal/centrifugo/v6/internal/logging"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
 	"github.com/centrifugal/centrifugo/v6/internal/websocket"
 
 	"github.com/centrifugal/centrifuge"
@@ -30,6 +31,9 @@ type Handler struct {
 // The value should be a properly encoded JSON object representing protocol.ConnectRequest.
 const connectUrlParam = "cf_connect"
 
+// Clients may select a preset compression dictionary by namespace name.
+const dictionaryUrlParam = "cf_ws_dictionary"
+
 var writeBufferPool = &sync.Pool{}
 
 // NewHandler creates new Handler.
@@ -39,6 +43,16 @@ func NewHandler(
 	upgrade := &websocket.Upgrader{
 		ReadBufferSize:    c.ReadBufferSize,
 		EnableCompression: c.Compression,
+		CompressionObserver: func(uncompressed int, compressed int) {
+			metrics.ObserveWebSocketCompression(transportName, uncompressed, compressed)
+		},
+	}
+	if c.CompressionContext.Enabled {
+		upgrade.CompressionContextTakeover = true
+		upgrade.CompressionWindowBits = c.CompressionContext.WindowBits
+		if c.CompressionContext.MemoryLimit > 0 {
+			upgrade.CompressionWindowBudget = websocket.NewWindowBudget(int64(c.CompressionContext.MemoryLimit))
+		}
 	}
 	if c.UseWriteBufferPool {
 		upgrade.WriteBufferPool = writeBufferPool
@@ -58,6 +72,18 @@ func NewHandler(
 	}
 }
 
+// SetCompressionDictionary sets a function to look up preset compression
+// dictionary by namespace name passed by client in URL.
+func (s *Handler) SetCompressionDictionary(dictionary func(namespace string) []byte) {
+	s.upgrade.CompressionDictionary = func(r *http.Request) []byte {
+		namespace := r.URL.Query().Get(dictionaryUrlParam)
+		if namespace == "" {
+			return nil
+		}
+		return dictionary(namespace)
+	}
+}
+
 func (s *Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 	compression := s.config.Compression
 	compressionLevel := s.config.CompressionLevel
@@ -1,25 +1,37 @@
 package uniws
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/centrifugal/centrifugo/v6/internal/configtypes"
+	"github.com/centrifugal/centrifugo/v6/internal/metrics"
 	"github.com/centrifugal/centrifugo/v6/internal/middleware"
 	"github.com/centrifugal/centrifugo/v6/internal/websocket"
 
 	"github.com/centrifugal/centrifuge"
 	"github.com/centrifugal/protocol"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 )
 
+func TestMain(m *testing.M) {
+	_ = metrics.Init(metrics.Config{
+		Registerer: prometheus.NewRegistry(),
+	})
+	os.Exit(m.Run())
+}
+
 func ensureMessageHasClient(t *testing.T, data []byte) {
 	t.Log(string(data))
 	var reply protocol.Reply
@@ -321,3 +333,45 @@ func TestUnidirectionalWebSocket_CloseFrameSent(t *testing.T) {
 	require.Equal(t, centrifuge.DisconnectConnectionLimit.Code, uint32(closeErr.Code))
 	require.Equal(t, centrifuge.DisconnectConnectionLimit.Reason, closeErr.Text)
 }
+
+func TestUnidirectionalWebSocket_CompressionContextTakeover(t *testing.T) {
+	t.Parallel()
+	node, err := centrifuge.New(centrifuge.Config{})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = node.Shutdown(context.Background()) })
+
+	config := configtypes.UniWebSocket{
+		Compression: true,
+		CompressionContext: configtypes.WebSocketCompressionContext{
+			Enabled:    true,
+			WindowBits: 12,
+		},
+	}
+	handler := NewHandler(node, config, func(r *http.Request) bool { return true }, centrifuge.PingPongConfig{})
+	var requestedDictionary string
+	handler.SetCompressionDictionary(func(namespace string) []byte {
+		requestedDictionary = namespace
+		return []byte("dictionary")
+	})
+	server := httptest.NewServer(handler)
+	t.Cleanup(func() { server.Close() })
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"?cf_ws_dictionary=chat", nil)
+	require.NoError(t, err)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Sec-WebSocket-Extensions", "permessage-deflate; client_max_window_bits")
+	require.NoError(t, req.Write(conn))
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	require.Equal(t, "permessage-deflate; client_max_window_bits=12", resp.Header.Get("Sec-WebSocket-Extensions"))
+	require.Equal(t, "chat", requestedDictionary)
+}
@@ -82,9 +82,11 @@ type Features struct {
 	SSE           bool
 	UniWebsocket  bool
 	UniGRPC       bool
+	BidiGRPC      bool
 	UniSSE        bool
 	UniHTTPStream bool
 	WebTransport  bool
+	LongPoll      bool
 
 	// Proxies.
 	ConnectProxy         bool
@@ -369,9 +371,15 @@ func (s *Sender) prepareMetrics() ([]*metric, error) {
 	if s.features.UniGRPC {
 		metrics = append(metrics, createPoint("transports_enabled.uni_grpc"))
 	}
+	if s.features.BidiGRPC {
+		metrics = append(metrics, createPoint("transports_enabled.bidi_grpc"))
+	}
 	if s.features.WebTransport {
 		metrics = append(metrics, createPoint("transports_enabled.webtransport"))
 	}
+	if s.features.LongPoll {
+		metrics = append(metrics, createPoint("transports_enabled.long_poll"))
+	}
 	if s.features.ConnectProxy {
 		metrics = append(metrics, createPoint("proxies_enabled.connect"))
 	}
@@ -18,6 +18,15 @@ const (
 	defaultCompressionLevel = 1
 )
 
+// flateMessageTail is appended to a compressed message before inflating it.
+const flateMessageTail =
+// Add four bytes as specified in RFC
+"\x00\x00\xff\xff" +
+	// Add final block to squelch unexpected EOF error from flate reader.
+	"\x01\x00\x00\xff\xff"
+
+var errUnexpectedFlateTail = errors.New("websocket: internal error, unexpected bytes at end of flate stream")
+
 var (
 	flateWriterPools [maxCompressionLevel - minCompressionLevel + 1]sync.Pool
 	flateReaderPool  = sync.Pool{New: func() interface{} {
@@ -26,14 +35,8 @@ var (
 )
 
 func decompressNoContextTakeover(r io.Reader) io.ReadCloser {
-	const tail =
-	// Add four bytes as specified in RFC
-	"\x00\x00\xff\xff" +
-		// Add final block to squelch unexpected EOF error from flate reader.
-		"\x01\x00\x00\xff\xff"
-
 	fr, _ := flateReaderPool.Get().(io.ReadCloser)
-	_ = fr.(flate.Resetter).Reset(io.MultiReader(r, strings.NewReader(tail)), nil)
+	_ = fr.(flate.Resetter).Reset(io.MultiReader(r, strings.NewReader(flateMessageTail)), nil)
 	return &flateReadWrapper{fr}
 }
 
@@ -110,7 +113,7 @@ func (w *flateWriteWrapper) Close() error {
 	w.p.Put(w.fw)
 	w.fw = nil
 	if w.tw.p != [4]byte{0, 0, 0xff, 0xff} {
-		return errors.New("websocket: internal error, unexpected bytes at end of flate stream")
+		return errUnexpectedFlateTail
 	}
 	err2 := w.tw.w.Close()
 	if err1 != nil {
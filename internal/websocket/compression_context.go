@@ -0,0 +1,393 @@
+package websocket
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	kflate "github.com/klauspost/compress/flate"
+)
+
+const (
+	// minCompressionWindowBits is the smallest LZ77 window we agree to. RFC 7692
+	// allows 8, but zlib silently upgrades 8 to 9 when compressing, so a peer
+	// offering 8 may still reference 512 bytes back.
+	minCompressionWindowBits = 9
+	maxCompressionWindowBits = 15
+)
+
+// WindowBudget limits the total memory reserved for context takeover sliding
+// windows across connections. It's safe for concurrent use.
+type WindowBudget struct {
+	limit int64
+	used  atomic.Int64
+}
+
+// NewWindowBudget creates WindowBudget which allows reserving up to limit bytes.
+// Zero or negative limit means no limit.
+func NewWindowBudget(limit int64) *WindowBudget {
+	return &WindowBudget{limit: limit}
+}
+
+// Used returns the number of bytes currently reserved.
+func (b *WindowBudget) Used() int64 {
+	return b.used.Load()
+}
+
+func (b *WindowBudget) reserve(n int64) bool {
+	if b.used.Add(n) > b.limit && b.limit > 0 {
+		b.used.Add(-n)
+		return false
+	}
+	return true
+}
+
+func (b *WindowBudget) release(n int64) {
+	b.used.Add(-n)
+}
+
+// compressionNegotiation describes permessage-deflate parameters accepted by
+// server for a connection.
+type compressionNegotiation struct {
+	serverContextTakeover bool
+	clientContextTakeover bool
+	// clientMaxWindowBits is included into response when positive. Only sent
+	// if client offered client_max_window_bits.
+	clientMaxWindowBits int
+	// reserved is the amount of memory reserved in budget for windows.
+	reserved int64
+	budget   *WindowBudget
+}
+
+// release returns memory reserved for windows back to budget.
+func (n compressionNegotiation) release() {
+	if n.budget != nil && n.reserved > 0 {
+		n.budget.release(n.reserved)
+	}
+}
+
+func (n compressionNegotiation) extensionHeader() string {
+	var b strings.Builder
+	b.WriteString("permessage-deflate")
+	if !n.serverContextTakeover {
+		b.WriteString("; server_no_context_takeover")
+	}
+	if !n.clientContextTakeover {
+		b.WriteString("; client_no_context_takeover")
+	}
+	if n.clientMaxWindowBits > 0 {
+		b.WriteString("; client_max_window_bits=")
+		b.WriteString(strconv.Itoa(n.clientMaxWindowBits))
+	}
+	return b.String()
+}
+
+// serverWindowSize is the size of sliding window we keep for outgoing messages.
+func (n compressionNegotiation) serverWindowSize(bits int) int {
+	if !n.serverContextTakeover {
+		return 0
+	}
+	return 1 << bits
+}
+
+// clientWindowSize is the size of sliding window we must keep to decompress
+// incoming messages: the client's window is 32KB unless we restricted it.
+func (n compressionNegotiation) clientWindowSize() int {
+	if !n.clientContextTakeover {
+		return 0
+	}
+	if n.clientMaxWindowBits > 0 {
+		return 1 << n.clientMaxWindowBits
+	}
+	return 1 << maxCompressionWindowBits
+}
+
+func (u *Upgrader) compressionWindowBits() int {
+	if u.CompressionWindowBits == 0 {
+		return maxCompressionWindowBits
+	}
+	return u.CompressionWindowBits
+}
+
+// CompressionContextTakeoverOffered reports whether request offers permessage-deflate
+// with context takeover in at least one direction which Upgrader may accept.
+func (u *Upgrader) CompressionContextTakeoverOffered(r *http.Request) bool {
+	if !u.EnableCompression || !u.CompressionContextTakeover {
+		return false
+	}
+	for _, ext := range parseExtensions(r.Header) {
+		if ext[""] != "permessage-deflate" {
+			continue
+		}
+		_, snct := ext["server_no_context_takeover"]
+		_, cnct := ext["client_no_context_takeover"]
+		return !snct || !cnct
+	}
+	return false
+}
+
+// negotiateCompression processes a permessage-deflate offer from client.
+func (u *Upgrader) negotiateCompression(ext map[string]string) compressionNegotiation {
+	var n compressionNegotiation
+	if !u.CompressionContextTakeover {
+		return n
+	}
+	bits := u.compressionWindowBits()
+
+	if _, ok := ext["server_no_context_takeover"]; !ok {
+		// We don't restrict LZ77 window of the compressor, so only use context
+		// takeover if client accepts the full window.
+		if v, ok := ext["server_max_window_bits"]; !ok || v == strconv.Itoa(maxCompressionWindowBits) {
+			n.serverContextTakeover = true
+		}
+	}
+
+	if _, ok := ext["client_no_context_takeover"]; !ok {
+		if v, ok := ext["client_max_window_bits"]; ok {
+			clientBits := bits
+			if v != "" {
+				offered, err := strconv.Atoi(v)
+				if err != nil || offered < 8 || offered > maxCompressionWindowBits {
+					return compressionNegotiation{}
+				}
+				clientBits = min(clientBits, max(offered, minCompressionWindowBits))
+			}
+			n.clientContextTakeover = true
+			n.clientMaxWindowBits = clientBits
+		} else if bits == maxCompressionWindowBits {
+			// Client can't be asked to reduce its window, so we only keep
+			// client context when configured window allows 32KB.
+			n.clientContextTakeover = true
+		}
+	}
+
+	if n.serverContextTakeover || n.clientContextTakeover {
+		if u.CompressionWindowBudget != nil {
+			size := int64(n.serverWindowSize(bits) + n.clientWindowSize())
+			if !u.CompressionWindowBudget.reserve(size) {
+				return compressionNegotiation{}
+			}
+			n.reserved = size
+			n.budget = u.CompressionWindowBudget
+		}
+	}
+	return n
+}
+
+// setupCompression configures compression for a server connection according
+// to negotiated parameters.
+func (u *Upgrader) setupCompression(c *Conn, r *http.Request, n compressionNegotiation) {
+	c.newCompressionWriter = compressNoContextTakeover
+	c.newDecompressionReader = decompressNoContextTakeover
+
+	if n.serverContextTakeover || n.clientContextTakeover {
+		bits := u.compressionWindowBits()
+		var dict []byte
+		if u.CompressionDictionary != nil {
+			dict = u.CompressionDictionary(r)
+		}
+		if size := n.serverWindowSize(bits); size > 0 {
+			comp := &contextTakeoverCompressor{window: slidingWindow{size: size}}
+			comp.window.write(dict)
+			c.newCompressionWriter = comp.newWriter
+			c.compressionContextTakeover = true
+		}
+		if size := n.clientWindowSize(); size > 0 {
+			decomp := &contextTakeoverDecompressor{window: slidingWindow{size: size}}
+			decomp.window.write(dict)
+			c.newDecompressionReader = decomp.newReader
+		}
+		if n.reserved > 0 {
+			var once sync.Once
+			c.releaseCompression = func() {
+				once.Do(n.release)
+			}
+		}
+	}
+
+	if observer := u.CompressionObserver; observer != nil {
+		newWriter := c.newCompressionWriter
+		c.newCompressionWriter = func(w io.WriteCloser, level int) io.WriteCloser {
+			cw := &countingWriteCloser{w: w}
+			return &observedWriteCloser{w: newWriter(cw, level), cw: cw, observer: observer}
+		}
+	}
+}
+
+// slidingWindow keeps the tail of the uncompressed stream. It's used as a
+// dictionary for the next message when context takeover is negotiated, so
+// memory per connection and direction is bounded by size.
+type slidingWindow struct {
+	buf  []byte
+	size int
+}
+
+func (w *slidingWindow) write(p []byte) {
+	if len(p) == 0 {
+		return
+	}
+	if w.buf == nil {
+		w.buf = make([]byte, 0, w.size)
+	}
+	if len(p) >= w.size {
+		w.buf = append(w.buf[:0], p[len(p)-w.size:]...)
+		return
+	}
+	if n := len(w.buf) + len(p) - w.size; n > 0 {
+		w.buf = w.buf[:copy(w.buf, w.buf[n:])]
+	}
+	w.buf = append(w.buf, p...)
+}
+
+var (
+	contextFlateWriterPools [maxCompressionLevel - minCompressionLevel + 1]sync.Pool
+	contextFlateReaderPool  = sync.Pool{New: func() any {
+		return kflate.NewReader(nil)
+	}}
+)
+
+type contextTakeoverCompressor struct {
+	window slidingWindow
+}
+
+// minContextTakeoverCompressionLevel is the lowest level used with context
+// takeover. Faster levels don't search for matches in flushes shorter than 128
+// bytes, so small messages would not benefit from history at all.
+const minContextTakeoverCompressionLevel = 7
+
+func (c *contextTakeoverCompressor) newWriter(w io.WriteCloser, level int) io.WriteCloser {
+	if level > 0 && level < minContextTakeoverCompressionLevel {
+		level = minContextTakeoverCompressionLevel
+	}
+	p := &contextFlateWriterPools[level-minCompressionLevel]
+	tw := &truncWriter{w: w}
+	fw, _ := p.Get().(*kflate.Writer)
+	if fw == nil {
+		fw, _ = kflate.NewWriter(tw, level)
+	}
+	// Window contents are copied into compressor state here, so it's safe
+	// to append to window while writing.
+	fw.ResetDict(tw, c.window.buf)
+	return &contextFlateWriteWrapper{fw: fw, tw: tw, p: p, c: c}
+}
+
+type contextFlateWriteWrapper struct {
+	fw *kflate.Writer
+	tw *truncWriter
+	p  *sync.Pool
+	c  *contextTakeoverCompressor
+}
+
+func (w *contextFlateWriteWrapper) Write(p []byte) (int, error) {
+	if w.fw == nil {
+		return 0, errWriteClosed
+	}
+	n, err := w.fw.Write(p)
+	w.c.window.write(p[:n])
+	return n, err
+}
+
+func (w *contextFlateWriteWrapper) Close() error {
+	if w.fw == nil {
+		return errWriteClosed
+	}
+	err1 := w.fw.Flush()
+	w.p.Put(w.fw)
+	w.fw = nil
+	if w.tw.p != [4]byte{0, 0, 0xff, 0xff} {
+		return errUnexpectedFlateTail
+	}
+	err2 := w.tw.w.Close()
+	if err1 != nil {
+		return err1
+	}
+	return err2
+}
+
+type contextTakeoverDecompressor struct {
+	window slidingWindow
+}
+
+func (d *contextTakeoverDecompressor) newReader(r io.Reader) io.ReadCloser {
+	fr, _ := contextFlateReaderPool.Get().(io.ReadCloser)
+	_ = fr.(kflate.Resetter).Reset(io.MultiReader(r, strings.NewReader(flateMessageTail)), d.window.buf)
+	return &contextFlateReadWrapper{fr: fr, d: d}
+}
+
+type contextFlateReadWrapper struct {
+	fr io.ReadCloser
+	d  *contextTakeoverDecompressor
+}
+
+func (r *contextFlateReadWrapper) Read(p []byte) (int, error) {
+	if r.fr == nil {
+		return 0, io.ErrClosedPipe
+	}
+	n, err := r.fr.Read(p)
+	r.d.window.write(p[:n])
+	if err == io.EOF {
+		r.release()
+	}
+	return n, err
+}
+
+// Close consumes the rest of the message: the peer compressed the next message
+// using the whole current one as a history, so the window must include it.
+func (r *contextFlateReadWrapper) Close() error {
+	if r.fr == nil {
+		return io.ErrClosedPipe
+	}
+	_, err := io.Copy(io.Discard, r)
+	if r.fr != nil {
+		r.release()
+	}
+	return err
+}
+
+func (r *contextFlateReadWrapper) release() {
+	_ = r.fr.Close()
+	contextFlateReaderPool.Put(r.fr)
+	r.fr = nil
+}
+
+// countingWriteCloser counts bytes passed to the underlying writer.
+type countingWriteCloser struct {
+	w io.WriteCloser
+	n int
+}
+
+func (w *countingWriteCloser) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += n
+	return n, err
+}
+
+func (w *countingWriteCloser) Close() error {
+	return w.w.Close()
+}
+
+// observedWriteCloser reports sizes of a message before and after compression
+// when the message is closed.
+type observedWriteCloser struct {
+	w        io.WriteCloser
+	cw       *countingWriteCloser
+	n        int
+	observer func(uncompressed int, compressed int)
+}
+
+func (w *observedWriteCloser) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += n
+	return n, err
+}
+
+func (w *observedWriteCloser) Close() error {
+	err := w.w.Close()
+	if err == nil {
+		w.observer(w.n, w.cw.n)
+	}
+	return err
+}
@@ -0,0 +1,327 @@
+package websocket
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// contextTakeoverPair returns a client connection writing compressed messages
+// with context takeover into a buffer and a server connection reading them.
+func contextTakeoverPair(windowSize int, dict []byte) (wc *Conn, rc *Conn, buf *bytes.Buffer) {
+	buf = &bytes.Buffer{}
+	wc = newTestConn(nil, buf, false)
+	comp := &contextTakeoverCompressor{window: slidingWindow{size: windowSize}}
+	comp.window.write(dict)
+	wc.newCompressionWriter = comp.newWriter
+	wc.compressionContextTakeover = true
+
+	rc = newTestConn(buf, &bytes.Buffer{}, true)
+	decomp := &contextTakeoverDecompressor{window: slidingWindow{size: windowSize}}
+	decomp.window.write(dict)
+	rc.newDecompressionReader = decomp.newReader
+	return wc, rc, buf
+}
+
+func TestCompressionContextTakeoverRoundTrip(t *testing.T) {
+	messages := textMessages(100)
+	wc, rc, buf := contextTakeoverPair(1<<15, nil)
+
+	for _, msg := range messages {
+		if err := wc.WriteMessage(TextMessage, msg); err != nil {
+			t.Fatalf("WriteMessage: %v", err)
+		}
+	}
+	takeoverSize := buf.Len()
+
+	for i, msg := range messages {
+		_, data, err := rc.ReadMessage()
+		if err != nil {
+			t.Fatalf("ReadMessage %d: %v", i, err)
+		}
+		if !bytes.Equal(data, msg) {
+			t.Fatalf("message %d = %q, want %q", i, data, msg)
+		}
+	}
+
+	var plain bytes.Buffer
+	pc := newTestConn(nil, &plain, false)
+	pc.newCompressionWriter = compressNoContextTakeover
+	for _, msg := range messages {
+		if err := pc.WriteMessage(TextMessage, msg); err != nil {
+			t.Fatalf("WriteMessage: %v", err)
+		}
+	}
+	if takeoverSize >= plain.Len() {
+		t.Fatalf("context takeover produced %d bytes, no context takeover %d bytes", takeoverSize, plain.Len())
+	}
+}
+
+func TestCompressionContextTakeoverSmallWindow(t *testing.T) {
+	messages := textMessages(50)
+	wc, rc, _ := contextTakeoverPair(1<<minCompressionWindowBits, nil)
+
+	for _, msg := range messages {
+		if err := wc.WriteMessage(TextMessage, bytes.Repeat(msg, 20)); err != nil {
+			t.Fatalf("WriteMessage: %v", err)
+		}
+	}
+	for i, msg := range messages {
+		_, data, err := rc.ReadMessage()
+		if err != nil {
+			t.Fatalf("ReadMessage %d: %v", i, err)
+		}
+		if !bytes.Equal(data, bytes.Repeat(msg, 20)) {
+			t.Fatalf("unexpected message %d", i)
+		}
+	}
+}
+
+func TestCompressionContextTakeoverDictionary(t *testing.T) {
+	dict := []byte(`{"push":{"channel":"news","pub":{"data":{"title":"","body":""},"offset":0}}}`)
+	msg := []byte(`{"push":{"channel":"news","pub":{"data":{"title":"hello","body":"world"},"offset":1}}}`)
+
+	wc, rc, buf := contextTakeoverPair(1<<15, dict)
+	if err := wc.WriteMessage(TextMessage, msg); err != nil {
+		t.Fatalf("WriteMessage: %v", err)
+	}
+	withDict := buf.Len()
+	_, data, err := rc.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	if !bytes.Equal(data, msg) {
+		t.Fatalf("message = %q, want %q", data, msg)
+	}
+
+	wc, _, buf = contextTakeoverPair(1<<15, nil)
+	if err := wc.WriteMessage(TextMessage, msg); err != nil {
+		t.Fatalf("WriteMessage: %v", err)
+	}
+	if withDict >= buf.Len() {
+		t.Fatalf("dictionary did not improve compression: %d >= %d", withDict, buf.Len())
+	}
+}
+
+func TestCompressionContextTakeoverPartialRead(t *testing.T) {
+	messages := textMessages(3)
+	wc, rc, _ := contextTakeoverPair(1<<15, nil)
+	for _, msg := range messages {
+		if err := wc.WriteMessage(TextMessage, msg); err != nil {
+			t.Fatalf("WriteMessage: %v", err)
+		}
+	}
+
+	_, r, err := rc.NextReader()
+	if err != nil {
+		t.Fatalf("NextReader: %v", err)
+	}
+	// Read only a part of the first message.
+	if _, err := io.ReadFull(r, make([]byte, 5)); err != nil {
+		t.Fatalf("ReadFull: %v", err)
+	}
+	for i := 1; i < len(messages); i++ {
+		_, data, err := rc.ReadMessage()
+		if err != nil {
+			t.Fatalf("ReadMessage %d: %v", i, err)
+		}
+		if !bytes.Equal(data, messages[i]) {
+			t.Fatalf("message %d = %q, want %q", i, data, messages[i])
+		}
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	w := slidingWindow{size: 8}
+	w.write([]byte("abc"))
+	w.write([]byte("defgh"))
+	if string(w.buf) != "abcdefgh" {
+		t.Fatalf("window = %q", w.buf)
+	}
+	w.write([]byte("ij"))
+	if string(w.buf) != "cdefghij" {
+		t.Fatalf("window = %q", w.buf)
+	}
+	w.write([]byte("0123456789"))
+	if string(w.buf) != "23456789" {
+		t.Fatalf("window = %q", w.buf)
+	}
+	if cap(w.buf) != 8 {
+		t.Fatalf("window capacity = %d", cap(w.buf))
+	}
+}
+
+func TestNegotiateCompression(t *testing.T) {
+	tests := []struct {
+		name     string
+		upgrader Upgrader
+		offer    string
+		want     string
+	}{
+		{
+			name:     "context takeover disabled",
+			upgrader: Upgrader{},
+			offer:    "permessage-deflate; client_max_window_bits",
+			want:     "permessage-deflate; server_no_context_takeover; client_no_context_takeover",
+		},
+		{
+			name:     "browser offer",
+			upgrader: Upgrader{CompressionContextTakeover: true},
+			offer:    "permessage-deflate; client_max_window_bits",
+			want:     "permessage-deflate; client_max_window_bits=15",
+		},
+		{
+			name:     "no client_max_window_bits",
+			upgrader: Upgrader{CompressionContextTakeover: true},
+			offer:    "permessage-deflate",
+			want:     "permessage-deflate",
+		},
+		{
+			name:     "bounded window",
+			upgrader: Upgrader{CompressionContextTakeover: true, CompressionWindowBits: 12},
+			offer:    "permessage-deflate; client_max_window_bits",
+			want:     "permessage-deflate; client_max_window_bits=12",
+		},
+		{
+			name:     "bounded window without client_max_window_bits",
+			upgrader: Upgrader{CompressionContextTakeover: true, CompressionWindowBits: 12},
+			offer:    "permessage-deflate",
+			want:     "permessage-deflate; client_no_context_takeover",
+		},
+		{
+			name:     "client offers smaller window",
+			upgrader: Upgrader{CompressionContextTakeover: true},
+			offer:    "permessage-deflate; client_max_window_bits=10",
+			want:     "permessage-deflate; client_max_window_bits=10",
+		},
+		{
+			name:     "client window 8 upgraded to 9",
+			upgrader: Upgrader{CompressionContextTakeover: true},
+			offer:    "permessage-deflate; client_max_window_bits=8",
+			want:     "permessage-deflate; client_max_window_bits=9",
+		},
+		{
+			name:     "client opts out",
+			upgrader: Upgrader{CompressionContextTakeover: true},
+			offer:    "permessage-deflate; server_no_context_takeover; client_no_context_takeover",
+			want:     "permessage-deflate; server_no_context_takeover; client_no_context_takeover",
+		},
+		{
+			name:     "server window restricted",
+			upgrader: Upgrader{CompressionContextTakeover: true},
+			offer:    "permessage-deflate; server_max_window_bits=10; client_max_window_bits",
+			want:     "permessage-deflate; server_no_context_takeover; client_max_window_bits=15",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exts := parseExtensions(map[string][]string{"Sec-Websocket-Extensions": {tt.offer}})
+			if len(exts) != 1 {
+				t.Fatalf("unexpected extensions: %v", exts)
+			}
+			n := tt.upgrader.negotiateCompression(exts[0])
+			if got := n.extensionHeader(); got != tt.want {
+				t.Fatalf("extension header = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindowBudget(t *testing.T) {
+	budget := NewWindowBudget(3 << 15)
+	u := Upgrader{CompressionContextTakeover: true, CompressionWindowBudget: budget}
+	ext := map[string]string{"": "permessage-deflate"}
+
+	n1 := u.negotiateCompression(ext)
+	if !n1.serverContextTakeover || !n1.clientContextTakeover {
+		t.Fatal("expected context takeover")
+	}
+	if budget.Used() != 2<<15 {
+		t.Fatalf("used = %d", budget.Used())
+	}
+	n2 := u.negotiateCompression(ext)
+	if n2.serverContextTakeover || n2.clientContextTakeover {
+		t.Fatal("expected fallback to no context takeover over budget")
+	}
+	if budget.Used() != 2<<15 {
+		t.Fatalf("used = %d", budget.Used())
+	}
+
+	c := newTestConn(nil, nil, true)
+	u.setupCompression(c, nil, n1)
+	_ = c.Close()
+	_ = c.Close()
+	if budget.Used() != 0 {
+		t.Fatalf("used after close = %d", budget.Used())
+	}
+}
+
+func TestCompressionContextTakeoverOffered(t *testing.T) {
+	u := Upgrader{EnableCompression: true, CompressionContextTakeover: true}
+	for _, tc := range []struct {
+		header   string
+		expected bool
+	}{
+		{"", false},
+		{"permessage-deflate", true},
+		{"permessage-deflate; client_no_context_takeover", true},
+		{"permessage-deflate; server_no_context_takeover; client_no_context_takeover", false},
+		{"x-webkit-deflate-frame", false},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tc.header != "" {
+			r.Header.Set("Sec-Websocket-Extensions", tc.header)
+		}
+		if got := u.CompressionContextTakeoverOffered(r); got != tc.expected {
+			t.Fatalf("%q: got %v, want %v", tc.header, got, tc.expected)
+		}
+	}
+	u.CompressionContextTakeover = false
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Sec-Websocket-Extensions", "permessage-deflate")
+	if u.CompressionContextTakeoverOffered(r) {
+		t.Fatal("context takeover is disabled")
+	}
+}
+
+func TestWindowBudget_ReleasedOnUpgradeError(t *testing.T) {
+	budget := NewWindowBudget(2 << 15)
+	u := Upgrader{EnableCompression: true, CompressionContextTakeover: true, CompressionWindowBudget: budget}
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Connection", "upgrade")
+	r.Header.Set("Upgrade", "websocket")
+	r.Header.Set("Sec-Websocket-Version", "13")
+	r.Header.Set("Sec-Websocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	r.Header.Set("Sec-Websocket-Extensions", "permessage-deflate")
+
+	// Recorder does not implement http.Hijacker so upgrade fails after negotiation.
+	if _, _, err := u.Upgrade(httptest.NewRecorder(), r, nil); err == nil {
+		t.Fatal("expected upgrade error")
+	}
+	if budget.Used() != 0 {
+		t.Fatalf("used after upgrade error = %d", budget.Used())
+	}
+}
+
+func TestCompressionObserver(t *testing.T) {
+	var uncompressed, compressed int
+	u := Upgrader{CompressionObserver: func(u int, c int) {
+		uncompressed += u
+		compressed += c
+	}}
+	var buf bytes.Buffer
+	c := newTestConn(nil, &buf, true)
+	u.setupCompression(c, nil, compressionNegotiation{})
+	msg := bytes.Repeat([]byte("centrifugo"), 100)
+	if err := c.WriteMessage(TextMessage, msg); err != nil {
+		t.Fatalf("WriteMessage: %v", err)
+	}
+	if uncompressed != len(msg) {
+		t.Fatalf("uncompressed = %d, want %d", uncompressed, len(msg))
+	}
+	if compressed == 0 || compressed >= uncompressed {
+		t.Fatalf("unexpected compressed size %d", compressed)
+	}
+}
@@ -278,6 +278,13 @@ type Conn struct {
 	readFinal              bool // true the current message has more frames.
 	enableWriteCompression bool
 	isServer               bool
+
+	// compressionContextTakeover is set when outgoing messages are compressed
+	// with context takeover, so compressed frames can't be shared between
+	// connections.
+	compressionContextTakeover bool
+	// releaseCompression releases memory reserved for compression windows.
+	releaseCompression func()
 }
 
 func newConn(conn net.Conn, isServer bool, readBufferSize, writeBufferSize int, writeBufferPool BufferPool, br *bufio.Reader, writeBuf []byte) *Conn {
@@ -330,6 +337,9 @@ func (c *Conn) setReadRemaining(n int64) error {
 // Close closes the underlying network connection without sending or waiting
 // for a close message.
 func (c *Conn) Close() error {
+	if c.releaseCompression != nil {
+		c.releaseCompression()
+	}
 	return c.conn.Close()
 }
 
@@ -724,6 +734,10 @@ func (w *messageWriter) Close() error {
 
 // WritePreparedMessage writes prepared message into connection.
 func (c *Conn) WritePreparedMessage(pm *PreparedMessage) error {
+	if c.compressionContextTakeover && c.enableWriteCompression && isData(pm.messageType) {
+		// Compressed representation depends on connection history.
+		return c.WriteMessage(pm.messageType, pm.data)
+	}
 	frameType, frameData, err := pm.frame(prepareKey{
 		isServer:         c.isServer,
 		compress:         c.newCompressionWriter != nil && c.enableWriteCompression && isData(pm.messageType),
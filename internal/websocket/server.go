@@ -71,10 +71,40 @@ type Upgrader struct {
 
 	// EnableCompression specify if the server should attempt to negotiate per
 	// message compression (RFC 7692). Setting this value to true does not
-	// guarantee that compression will be supported. Currently only "no context
-	// takeover" modes are supported.
+	// guarantee that compression will be supported. Only "no context takeover"
+	// modes are used unless CompressionContextTakeover is set.
 	EnableCompression bool
 
+	// CompressionContextTakeover allows negotiating context takeover for
+	// permessage-deflate, so messages are compressed using previous messages
+	// as a history. This improves compression ratio for small similar messages
+	// at the cost of keeping a sliding window per connection and direction.
+	// Context takeover is only used in directions the client did not opt out.
+	// Compression levels from 1 to 6 are raised to 7 for context takeover since
+	// faster levels don't look for matches in small messages.
+	CompressionContextTakeover bool
+
+	// CompressionWindowBits bounds the sliding window kept per connection and
+	// direction for context takeover to 1<<CompressionWindowBits bytes. Values
+	// from 9 to 15 are allowed, zero means 15 (32KB). When less than 15, client
+	// context takeover is only used if the client supports client_max_window_bits.
+	CompressionWindowBits int
+
+	// CompressionWindowBudget optionally limits total memory reserved for
+	// context takeover windows. Connections which don't fit into the budget
+	// fall back to no context takeover.
+	CompressionWindowBudget *WindowBudget
+
+	// CompressionDictionary optionally returns a preset dictionary for the
+	// connection. The dictionary primes sliding windows of both directions when
+	// context takeover is negotiated, so the peer must be primed with the same
+	// dictionary. Return nil to not use a dictionary.
+	CompressionDictionary func(r *http.Request) []byte
+
+	// CompressionObserver is called after each compressed message is written
+	// with message sizes before and after compression.
+	CompressionObserver func(uncompressed int, compressed int)
+
 	// DisableHTTP1Upgrade disables support for HTTP/1.1 Upgrade WebSocket handshakes.
 	// When true, server only accepts WebSocket connections over HTTP/2 Extended Connect
 	// (for now requires GODEBUG=http2xconnect=1).
@@ -222,13 +252,14 @@ func (u *Upgrader) Upgrade(w http.ResponseWriter, r *http.Request, responseHeade
 	subprotocol := u.selectSubprotocol(r, responseHeader)
 
 	// Negotiate PMCE.
-	var compress bool
+	var compress *compressionNegotiation
 	if u.EnableCompression {
 		for _, ext := range parseExtensions(r.Header) {
 			if ext[""] != "permessage-deflate" {
 				continue
 			}
-			compress = true
+			n := u.negotiateCompression(ext)
+			compress = &n
 			break
 		}
 	}
@@ -242,7 +273,17 @@ func (u *Upgrader) Upgrade(w http.ResponseWriter, r *http.Request, responseHeade
 }
 
 // upgradeH1 handles the HTTP/1.1 Upgrade handshake.
-func (u *Upgrader) upgradeH1(w http.ResponseWriter, r *http.Request, responseHeader http.Header, challengeKey, subprotocol string, compress bool) (*Conn, string, error) {
+func (u *Upgrader) upgradeH1(w http.ResponseWriter, r *http.Request, responseHeader http.Header, challengeKey, subprotocol string, compress *compressionNegotiation) (*Conn, string, error) {
+	// Memory reserved for compression windows is owned by connection once
+	// compression is set up (released on Close), before that return it to budget
+	// on error.
+	compressionOwned := false
+	defer func() {
+		if compress != nil && !compressionOwned {
+			compress.release()
+		}
+	}()
+
 	h, ok := w.(http.Hijacker)
 	if !ok {
 		return u.returnError(w, r, http.StatusInternalServerError, "websocket: response does not implement http.Hijacker")
@@ -274,9 +315,9 @@ func (u *Upgrader) upgradeH1(w http.ResponseWriter, r *http.Request, responseHea
 
 	c := newConn(netConn, true, u.ReadBufferSize, u.WriteBufferSize, u.WriteBufferPool, br, writeBuf)
 
-	if compress {
-		c.newCompressionWriter = compressNoContextTakeover
-		c.newDecompressionReader = decompressNoContextTakeover
+	if compress != nil {
+		u.setupCompression(c, r, *compress)
+		compressionOwned = true
 	}
 
 	// Use larger of hijacked buffer and connection write buffer for header.
@@ -296,8 +337,10 @@ func (u *Upgrader) upgradeH1(w http.ResponseWriter, r *http.Request, responseHea
 		p = append(p, subprotocol...)
 		p = append(p, "\r\n"...)
 	}
-	if compress {
-		p = append(p, "Sec-WebSocket-Extensions: permessage-deflate; server_no_context_takeover; client_no_context_takeover\r\n"...)
+	if compress != nil {
+		p = append(p, "Sec-WebSocket-Extensions: "...)
+		p = append(p, compress.extensionHeader()...)
+		p = append(p, "\r\n"...)
 	}
 	for k, vs := range responseHeader {
 		if k == "Sec-Websocket-Protocol" {
@@ -326,7 +369,7 @@ func (u *Upgrader) upgradeH1(w http.ResponseWriter, r *http.Request, responseHea
 		_ = netConn.SetWriteDeadline(time.Now().Add(u.HandshakeTimeout))
 	}
 	if _, err = netConn.Write(p); err != nil {
-		_ = netConn.Close()
+		_ = c.Close()
 		return nil, "", err
 	}
 	if u.HandshakeTimeout > 0 {
@@ -337,7 +380,7 @@ func (u *Upgrader) upgradeH1(w http.ResponseWriter, r *http.Request, responseHea
 }
 
 // upgradeH2 handles the HTTP/2 extended CONNECT handshake.
-func (u *Upgrader) upgradeH2(w http.ResponseWriter, r *http.Request, responseHeader http.Header, subprotocol string, compress bool) (*Conn, string, error) {
+func (u *Upgrader) upgradeH2(w http.ResponseWriter, r *http.Request, responseHeader http.Header, subprotocol string, compress *compressionNegotiation) (*Conn, string, error) {
 	// https://www.rfc-editor.org/rfc/rfc8441.html:
 	// Implementations using this extended CONNECT to bootstrap WebSockets do not do the processing of
 	// the Sec-WebSocket-Key and Sec-WebSocket-Accept header fields of [RFC6455] as that functionality
@@ -347,8 +390,8 @@ func (u *Upgrader) upgradeH2(w http.ResponseWriter, r *http.Request, responseHea
 		w.Header().Set("Sec-WebSocket-Protocol", subprotocol)
 	}
 
-	if compress {
-		w.Header().Set("Sec-WebSocket-Extensions", "permessage-deflate; server_no_context_takeover; client_no_context_takeover")
+	if compress != nil {
+		w.Header().Set("Sec-WebSocket-Extensions", compress.extensionHeader())
 	}
 
 	// Copy additional response headers.
@@ -368,10 +411,16 @@ func (u *Upgrader) upgradeH2(w http.ResponseWriter, r *http.Request, responseHea
 	// handshake before we start streaming on the tunnel.
 	rc := http.NewResponseController(w)
 	if err := rc.Flush(); err != nil {
+		if compress != nil {
+			compress.release()
+		}
 		return nil, "", err
 	}
 	err := rc.SetReadDeadline(time.Time{})
 	if err != nil {
+		if compress != nil {
+			compress.release()
+		}
 		return nil, "", err
 	}
 
@@ -390,9 +439,8 @@ func (u *Upgrader) upgradeH2(w http.ResponseWriter, r *http.Request, responseHea
 	// by setting Upgrader.WriteBufferPool.
 	br := bufio.NewReaderSize(stream, 16)
 	c := newConn(stream, true, u.ReadBufferSize, u.WriteBufferSize, u.WriteBufferPool, br, nil)
-	if compress {
-		c.newCompressionWriter = compressNoContextTakeover
-		c.newDecompressionReader = decompressNoContextTakeover
+	if compress != nil {
+		u.setupCompression(c, r, *compress)
 	}
 
 	return c, subprotocol, nil
@@ -26,7 +26,8 @@ func main() {
 	root.AddCommand(
 		cli.Version(), cli.CheckConfig(), cli.GenConfig(), cli.GenToken(),
 		cli.GenSubToken(), cli.CheckToken(), cli.CheckSubToken(), cli.DefaultConfig(),
-		cli.DefaultEnv(), cli.ConfigDoc(), cli.Serve(),
+		cli.DefaultEnv(), cli.ConfigDoc(), cli.Serve(), cli.MapSnapshot(), cli.MigrateConfig(),
+		cli.HistoryArchive(),
 	)
 	_ = root.Execute()
 }